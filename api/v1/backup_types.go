@@ -73,6 +73,14 @@ const (
 	// BackupMethodBarmanObjectStore means using barman to backup the
 	// PostgreSQL cluster
 	BackupMethodBarmanObjectStore BackupMethod = "barmanObjectStore"
+
+	// BackupMethodPlugin means using the backup plugin configured
+	// in the PostgreSQL cluster
+	BackupMethodPlugin BackupMethod = "plugin"
+
+	// BackupMethodPVC means using pg_basebackup to store the backup in
+	// the PersistentVolumeClaim configured in the PostgreSQL cluster
+	BackupMethodPVC BackupMethod = "pvc"
 )
 
 // BackupSpec defines the desired state of Backup
@@ -90,10 +98,10 @@ type BackupSpec struct {
 	// +kubebuilder:validation:Enum=primary;prefer-standby
 	Target BackupTarget `json:"target,omitempty"`
 
-	// The backup method to be used, possible options are `barmanObjectStore`
-	// and `volumeSnapshot`. Defaults to: `barmanObjectStore`.
+	// The backup method to be used, possible options are `barmanObjectStore`,
+	// `volumeSnapshot`, `plugin` and `pvc`. Defaults to: `barmanObjectStore`.
 	// +optional
-	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin;pvc
 	// +kubebuilder:default:=barmanObjectStore
 	Method BackupMethod `json:"method,omitempty"`
 
@@ -107,6 +115,14 @@ type BackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// Whether the backup with volume snapshots is crash-consistent: the
+	// snapshots are taken on the primary, without putting PostgreSQL in
+	// backup mode nor fencing the instance, and are restored like after a
+	// crash. No WAL archive is required, but PGDATA must be on a single
+	// volume. It can't be used together with `online` and `onlineConfiguration`
+	// +optional
+	CrashConsistent bool `json:"crashConsistent,omitempty"`
 }
 
 // BackupSnapshotStatus the fields exclusive to the volumeSnapshot method backup
@@ -156,6 +172,11 @@ type BackupStatus struct {
 	// +optional
 	ServerName string `json:"serverName,omitempty"`
 
+	// The name of the PersistentVolumeClaim storing the backup, when
+	// the backup method is `pvc`
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
 	// Encryption method required to S3 API
 	// +optional
 	Encryption string `json:"encryption,omitempty"`
@@ -230,6 +251,22 @@ type BackupStatus struct {
 
 	// Whether the backup was online/hot (`true`) or offline/cold (`false`)
 	Online *bool `json:"online,omitempty"`
+
+	// The size of the backup in bytes, as reported by the backup method.
+	// For volume snapshots, this is the sum of the restore sizes of the
+	// snapshots
+	// +optional
+	Size *int64 `json:"size,omitempty"`
+
+	// The size in bytes of the data actually stored for this backup,
+	// available only when the backup method supports deduplicated or
+	// incremental backups
+	// +optional
+	DeduplicatedSize *int64 `json:"deduplicatedSize,omitempty"`
+
+	// The time taken by the backup, from its start to its end
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
@@ -303,6 +340,20 @@ func (backupStatus *BackupStatus) SetAsCompleted() {
 	backupStatus.Phase = BackupPhaseCompleted
 	backupStatus.Error = ""
 	backupStatus.StoppedAt = ptr.To(metav1.Now())
+	backupStatus.UpdateDuration()
+}
+
+// UpdateDuration sets the duration of the backup from its start and
+// stop times, when both are known
+func (backupStatus *BackupStatus) UpdateDuration() {
+	if backupStatus.StartedAt == nil || backupStatus.StoppedAt == nil {
+		backupStatus.Duration = nil
+		return
+	}
+
+	backupStatus.Duration = &metav1.Duration{
+		Duration: backupStatus.StoppedAt.Sub(backupStatus.StartedAt.Time),
+	}
 }
 
 // SetAsStarted marks a certain backup as started
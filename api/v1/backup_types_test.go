@@ -81,6 +81,22 @@ var _ = Describe("BackupStatus structure", func() {
 			BackupSnapshotElementStatus{Name: "cluster-example-snapshot-2", Type: string(utils.PVCRolePgWal)}))
 	})
 
+	It("records the duration when set as completed", func() {
+		status := BackupStatus{
+			StartedAt: ptr.To(metav1.NewTime(time.Now().Add(-10 * time.Minute))),
+		}
+		status.SetAsCompleted()
+		Expect(status.Phase).To(BeEquivalentTo(BackupPhaseCompleted))
+		Expect(status.Duration).ToNot(BeNil())
+		Expect(status.Duration.Duration).To(BeNumerically("~", 10*time.Minute, time.Minute))
+	})
+
+	It("has no duration when the start time is unknown", func() {
+		status := BackupStatus{}
+		status.SetAsCompleted()
+		Expect(status.Duration).To(BeNil())
+	})
+
 	Context("backup phases", func() {
 		When("the backup phase is `running`", func() {
 			It("can tell if a backup is in progress or done", func() {
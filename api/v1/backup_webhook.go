@@ -108,5 +108,21 @@ func (r *Backup) validate() field.ErrorList {
 		))
 	}
 
+	if r.Spec.CrashConsistent && r.Spec.Method != BackupMethodVolumeSnapshot {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "crashConsistent"),
+			r.Spec.CrashConsistent,
+			"CrashConsistent parameter can be specified only if the backup method is volumeSnapshot",
+		))
+	}
+
+	if r.Spec.CrashConsistent && (r.Spec.Online != nil || r.Spec.OnlineConfiguration != nil) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "crashConsistent"),
+			r.Spec.CrashConsistent,
+			"CrashConsistent parameter can't be used together with online and onlineConfiguration",
+		))
+	}
+
 	return result
 }
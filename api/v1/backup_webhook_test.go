@@ -72,4 +72,30 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("complains if crashConsistent is set on a barman backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:          BackupMethodBarmanObjectStore,
+				CrashConsistent: true,
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.crashConsistent"))
+	})
+
+	It("complains if crashConsistent is set together with online", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:          BackupMethodVolumeSnapshot,
+				Online:          ptr.To(true),
+				CrashConsistent: true,
+			},
+		}
+		utils.SetVolumeSnapshot(true)
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.crashConsistent"))
+	})
 })
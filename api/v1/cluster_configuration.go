@@ -17,14 +17,27 @@ limitations under the License.
 package v1
 
 import (
+	"golang.org/x/exp/slices"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // GetSyncReplicasData computes the actual number of required synchronous replicas and the names of
 // the electable sync replicas given the requested min, max, the number of ready replicas in the cluster and the sync
-// replicas constraints (if any)
+// replicas constraints (if any). When the synchronous replication is configured through the `synchronous`
+// stanza, the requested number of synchronous replicas is returned instead, together with the standby names
 func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncReplicas []string) {
+	if synchronous := cluster.Spec.PostgresConfiguration.Synchronous; synchronous != nil {
+		// The synchronous replication is enabled once the first primary
+		// has been elected, not to block the bootstrap of the cluster
+		if cluster.Status.CurrentPrimary == "" {
+			return 0, nil
+		}
+
+		return synchronous.Number, cluster.getSynchronousStandbyNames()
+	}
+
 	// We start with the number of healthy replicas (healthy pods minus one)
 	// and verify it is greater than 0 and between minSyncReplicas and maxSyncReplicas.
 	// Formula: 1 <= minSyncReplicas <= SyncReplicas <= maxSyncReplicas < readyReplicas
@@ -63,13 +76,53 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
+// getSynchronousStandbyNames computes the standby names configured through
+// the `synchronous` stanza: the instances of the cluster, except the primary,
+// are sorted to avoid spurious configuration changes and listed between the
+// user-defined names
+func (cluster *Cluster) getSynchronousStandbyNames() []string {
+	synchronous := cluster.Spec.PostgresConfiguration.Synchronous
+
+	instances := make([]string, 0, len(cluster.Status.InstanceNames))
+	for _, instance := range cluster.Status.InstanceNames {
+		if instance != cluster.Status.CurrentPrimary {
+			instances = append(instances, instance)
+		}
+	}
+	slices.Sort(instances)
+
+	if maxNames := synchronous.MaxStandbyNamesFromCluster; maxNames != nil && len(instances) > *maxNames {
+		instances = instances[:*maxNames]
+	}
+
+	names := make([]string, 0, len(synchronous.StandbyNamesPre)+len(instances)+len(synchronous.StandbyNamesPost))
+	names = append(names, synchronous.StandbyNamesPre...)
+	names = append(names, instances...)
+	return append(names, synchronous.StandbyNamesPost...)
+}
+
 // getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
 func (cluster *Cluster) getElectableSyncReplicas() []string {
+	excludeLaggingReplicas := cluster.Spec.ReplicationLagSLO != nil &&
+		cluster.Spec.ReplicationLagSLO.ExcludeFromSyncQuorum
+
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
-			nonPrimaryInstances = append(nonPrimaryInstances, instance)
+		if cluster.Status.CurrentPrimary == instance {
+			continue
 		}
+
+		// Replicas breaching the replication lag thresholds are kept out
+		// of the quorum until they catch up, if requested by the user.
+		// This runs at every reconciliation, and the ReplicationHealthy
+		// condition already reports the lagging replicas
+		if excludeLaggingReplicas && slices.Contains(cluster.Status.LaggingReplicas, instance) {
+			log.Debug("excluding lagging replica from the electable sync replicas",
+				"instanceName", instance)
+			continue
+		}
+
+		nonPrimaryInstances = append(nonPrimaryInstances, instance)
 	}
 
 	topology := cluster.Status.Topology
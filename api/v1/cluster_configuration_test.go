@@ -79,4 +79,76 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(BeEmpty())
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
+
+	It("should exclude the lagging replicas when requested", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.ReplicationLagSLO = &ReplicationLagSLO{
+			ExcludeFromSyncQuorum: true,
+		}
+		cluster.Status.LaggingReplicas = []string{"example-2"}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-3"}))
+	})
+
+	It("should keep the lagging replicas when exclusion is not requested", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.ReplicationLagSLO = &ReplicationLagSLO{}
+		cluster.Status.LaggingReplicas = []string{"example-2"}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+	})
+})
+
+var _ = Describe("synchronous replica data from the synchronous stanza", func() {
+	newCluster := func() *Cluster {
+		cluster := createFakeCluster("example")
+		cluster.Spec.MinSyncReplicas = 0
+		cluster.Spec.MaxSyncReplicas = 0
+		cluster.Spec.PostgresConfiguration.Synchronous = &SynchronousReplicaConfiguration{
+			Method: SynchronousReplicaConfigurationMethodFirst,
+			Number: 2,
+		}
+		cluster.Status.InstanceNames = []string{"example-3", "example-1", "example-2"}
+		return cluster
+	}
+
+	It("should list every standby of the cluster, sorted", func() {
+		cluster := newCluster()
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+	})
+
+	It("should never lower the number of synchronous replicas", func() {
+		cluster := newCluster()
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"example-1"},
+			utils.PodFailed:  {"example-2", "example-3"},
+		}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+	})
+
+	It("should list the user-defined standby names around the instances", func() {
+		cluster := newCluster()
+		maxNames := 1
+		cluster.Spec.PostgresConfiguration.Synchronous.MaxStandbyNamesFromCluster = &maxNames
+		cluster.Spec.PostgresConfiguration.Synchronous.StandbyNamesPre = []string{"pre"}
+		cluster.Spec.PostgresConfiguration.Synchronous.StandbyNamesPost = []string{"post-b", "post-a"}
+		_, names := cluster.GetSyncReplicasData()
+		Expect(names).To(Equal([]string{"pre", "example-2", "post-b", "post-a"}))
+	})
+
+	It("should not enable the synchronous replication before the primary is elected", func() {
+		cluster := newCluster()
+		cluster.Status.CurrentPrimary = ""
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(BeZero())
+		Expect(names).To(BeEmpty())
+	})
 })
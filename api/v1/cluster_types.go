@@ -24,6 +24,7 @@ import (
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -83,6 +84,15 @@ const (
 	// data
 	ServiceReadWriteSuffix = "-rw"
 
+	// ServiceDesignatedPrimarySuffix is the suffix appended to the cluster
+	// name to get the service name of the designated primary of a permanent
+	// replica cluster, which replaces the read-write service
+	ServiceDesignatedPrimarySuffix = "-designated"
+
+	// PrimaryLeaseSuffix is the suffix appended to the cluster name to
+	// get the name of the Lease holding the identity of the primary
+	PrimaryLeaseSuffix = "-primary"
+
 	// ClusterSecretSuffix is the suffix appended to the cluster name to
 	// get the name of the pull secret
 	ClusterSecretSuffix = "-pull-secret"
@@ -248,6 +258,16 @@ type ClusterSpec struct {
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
+	// The configuration of the init container installing the instance
+	// manager in the Pods of the cluster
+	// +optional
+	BootstrapController *BootstrapControllerConfiguration `json:"bootstrapController,omitempty"`
+
+	// Additional init containers, executed after the one installing the
+	// instance manager in the Pods and in the jobs of the cluster
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
 	// If specified, the pod will be dispatched by specified Kubernetes
 	// scheduler. If not specified, the pod will be dispatched by the default
 	// scheduler. More info:
@@ -286,6 +306,13 @@ type ClusterSpec struct {
 	// +optional
 	MaxSyncReplicas int `json:"maxSyncReplicas,omitempty"`
 
+	// The replication lag thresholds the standby instances are expected
+	// to respect. The replicas breaching them are reported in the
+	// `ReplicationHealthy` condition and, optionally, excluded from the
+	// synchronous replication quorum
+	// +optional
+	ReplicationLagSLO *ReplicationLagSLO `json:"replicationLagSLO,omitempty"`
+
 	// Configuration of the PostgreSQL server
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
@@ -295,6 +322,11 @@ type ClusterSpec struct {
 	// +optional
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Tuning of the connections used by the replicas to stream from the
+	// primary, and by the join job to clone it
+	// +optional
+	ReplicationConnection *ReplicationConnectionConfiguration `json:"replicationConnection,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -359,6 +391,28 @@ type ClusterSpec struct {
 	// +optional
 	SmartShutdownTimeout int32 `json:"smartShutdownTimeout,omitempty"`
 
+	// The time in seconds the kubelet waits for an instance Pod to terminate
+	// before killing it. It defaults to the value of `stopDelay`.
+	// Make sure it leaves enough time for PostgreSQL to complete its
+	// shutdown, including the final checkpoint
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// The action taken by the instance manager in the preStop hook, before
+	// the instance Pod is sent the termination signal: no action (`none` -
+	// default), a checkpoint (`checkpoint`), or a checkpoint followed by a
+	// fast shutdown of PostgreSQL, skipping the smart one (`fastShutdown`)
+	// +kubebuilder:default:=none
+	// +kubebuilder:validation:Enum:=none;checkpoint;fastShutdown
+	// +optional
+	PreStopStrategy PreStopStrategy `json:"preStopStrategy,omitempty"`
+
+	// The hooks invoked by the instance manager at key moments of the
+	// lifecycle of the instances, such as promotion and shutdown
+	// +optional
+	LifecycleHooks *LifecycleHooksConfiguration `json:"lifecycleHooks,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 3600 seconds (1 hour).
@@ -373,6 +427,34 @@ type ClusterSpec struct {
 	// +optional
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// When enabled, the liveness probe of the primary instance fails when
+	// the primary can reach neither the Kubernetes API server nor any of
+	// its standbys, so that the kubelet restarts it to prevent a
+	// split-brain. Disabled by default, as an outage of the API server while
+	// no standby is connected restarts the primary
+	// +optional
+	StopIsolatedPrimary bool `json:"stopIsolatedPrimary,omitempty"`
+
+	// The maximum amount of WAL, as an amount of bytes, that can be lost
+	// when promoting a replica during a failover. It is computed as the
+	// difference between the last LSN reported by the primary and the LSN
+	// replayed by the replica chosen for promotion, on the same timeline.
+	// When exceeded, or when it cannot be computed, the failover is blocked
+	// until the `cnpg.io/allowDataLossOnFailover` annotation is set to
+	// `enabled` on the cluster.
+	// No limit is enforced by default
+	// +optional
+	MaxDataLossOnFailover *resource.Quantity `json:"maxDataLossOnFailover,omitempty"`
+
+	// How a replica found on an older timeline than the primary when starting
+	// up rejoins the cluster: by waiting for a manual intervention (`manual` -
+	// default), by running `pg_rewind` (`rewind`), or by running `pg_rewind`
+	// and cloning the primary again if it fails (`reclone`)
+	// +kubebuilder:default:=manual
+	// +kubebuilder:validation:Enum:=rewind;reclone;manual
+	// +optional
+	StaleTimelinePolicy StaleTimelinePolicy `json:"staleTimelinePolicy,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -383,6 +465,31 @@ type ClusterSpec struct {
 	// +optional
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// The DNS policy of every generated Pod. Defaults to `ClusterFirst`.
+	// Please refer to
+	// https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-s-dns-policy
+	// for more information
+	// +kubebuilder:validation:Enum:=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// The DNS parameters of every generated Pod, such as the name servers,
+	// the search domains and the `ndots` option, merged with the ones
+	// generated from the DNS policy. Please refer to
+	// https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-dns-config
+	// for more information
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// When set to `true`, the instance Pods use the network namespace of
+	// the node they are scheduled on, and PostgreSQL listens directly on
+	// the node IP addresses. The PostgreSQL, metrics and status ports are
+	// reserved on the node as host ports, so it requires the pod
+	// anti-affinity to be `required` with the `kubernetes.io/hostname`
+	// topology key. Defaults to `false`
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
 	// Resources requirements of every generated Pod. Please refer to
 	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// for more information.
@@ -416,6 +523,58 @@ type ClusterSpec struct {
 	// +optional
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Policy to follow when the storage class of the storage, of the WAL
+	// storage or of a tablespace is changed: the existing PVCs can be kept,
+	// with the new storage class only used for new PVCs (`ignore` - default),
+	// or migrated (`migrate`), recreating the instances one at a time on the
+	// new storage class, starting from the replicas and switching over before
+	// recreating the primary. The migration requires at least two instances
+	// +kubebuilder:validation:Enum:=ignore;migrate
+	// +optional
+	StorageClassUpdatePolicy StorageClassUpdatePolicy `json:"storageClassUpdatePolicy,omitempty"`
+
+	// Policy to follow for the PVCs of the instances when the cluster is
+	// deleted: the PVCs can be deleted together with the cluster (`delete` -
+	// default) or retained (`retain`), removing their ownership so that a
+	// new cluster with the same name adopts them
+	// +kubebuilder:validation:Enum:=delete;retain
+	// +optional
+	PersistentVolumeClaimRetentionPolicy PVCRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// Policy to follow when the Secrets projected into the instances (the
+	// superuser and application secrets) change: the changes can be
+	// applied by reloading the configuration (`reload` - default) or with
+	// a rolling update of the instances (`restart`). In both cases, the
+	// pods are annotated with a checksum of the versions of those resources
+	// +kubebuilder:default:=reload
+	// +kubebuilder:validation:Enum:=reload;restart
+	// +optional
+	ResourcesUpdatePolicy ResourcesUpdatePolicy `json:"resourcesUpdatePolicy,omitempty"`
+
+	// The time windows in which the operator is allowed to restart the
+	// instances or to perform a switchover as part of a rolling update.
+	// Failovers and restarts requested by the user are not affected.
+	// When empty, rolling updates can happen at any time
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// The safety checks the operator runs before restarting the first
+	// instance on a new PostgreSQL image. When empty, no check is done
+	// +optional
+	ImageUpgradeSafety *ImageUpgradeSafetyConfiguration `json:"imageUpgradeSafety,omitempty"`
+
+	// The handling of the collations whose version changed after an
+	// update of the image, for example because of a new version of the
+	// C library or of ICU
+	// +optional
+	Collation *CollationConfiguration `json:"collation,omitempty"`
+
+	// The detection of the transaction ID wraparound risk, based on the
+	// age of the oldest unfrozen transaction ID of each database, which
+	// is reported in the `TransactionIDWraparoundSafe` condition
+	// +optional
+	WraparoundProtection *WraparoundProtectionConfiguration `json:"wraparoundProtection,omitempty"`
+
 	// The configuration to be used for backups
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
@@ -459,6 +618,11 @@ type ClusterSpec struct {
 	// +optional
 	Managed *ManagedConfiguration `json:"managed,omitempty"`
 
+	// The configuration of the Lease published by the operator with the
+	// identity of the current primary, to be consumed by external tools
+	// +optional
+	PrimaryLease *PrimaryLeaseConfiguration `json:"primaryLease,omitempty"`
+
 	// The SeccompProfile applied to every Pod and Container.
 	// Defaults to: `RuntimeDefault`
 	// +optional
@@ -488,6 +652,34 @@ const (
 	// PhaseWaitingForUser set the status to wait for an action from the user
 	PhaseWaitingForUser = "Waiting for user action"
 
+	// PhaseStorageClassMigration for a cluster recreating its instances
+	// on a different storage class
+	PhaseStorageClassMigration = "Migrating the storage class"
+
+	// PhaseFailoverBlocked set the status to wait for the user to allow a failover
+	// that may lose more data than allowed by the cluster configuration
+	PhaseFailoverBlocked = "Failover blocked: potential data loss exceeds the configured threshold or is unknown"
+
+	// PhaseWaitingForRolloutSlot set the status to wait for the operator to
+	// allow the cluster to be rolled out
+	PhaseWaitingForRolloutSlot = "Waiting for a rollout slot"
+
+	// PhaseWaitingForMaintenanceWindow set the status to wait for the next
+	// maintenance window of the cluster before proceeding with a rolling update
+	PhaseWaitingForMaintenanceWindow = "Waiting for the maintenance window"
+
+	// PhaseWaitingForImageUpgradeSafety set the status to wait for the safety
+	// checks to pass before rolling out a new PostgreSQL image
+	PhaseWaitingForImageUpgradeSafety = "Waiting for the image upgrade safety checks"
+
+	// PhaseMajorUpgrade for a cluster upgrading the data directory of the
+	// primary to a new major version of PostgreSQL
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseMajorUpgradeFailed for a cluster whose major version upgrade
+	// failed, needing the intervention of the user
+	PhaseMajorUpgradeFailed = "Postgres major version upgrade failed"
+
 	// PhaseInplacePrimaryRestart for a cluster restarting the primary instance in-place
 	PhaseInplacePrimaryRestart = "Primary instance is being restarted in-place"
 
@@ -657,6 +849,24 @@ type TablespaceState struct {
 	Error string `json:"error,omitempty"`
 }
 
+// ParameterDrift represents a parameter whose live value in an instance
+// has been changed with `ALTER SYSTEM`
+type ParameterDrift struct {
+	// The name of the parameter
+	Name string `json:"name"`
+
+	// The name of the instance where the parameter has been changed
+	Instance string `json:"instance"`
+
+	// The live value of the parameter in the instance
+	Value string `json:"value"`
+
+	// The value of the parameter in the configuration of the cluster,
+	// empty when the parameter is not set there
+	// +optional
+	DesiredValue string `json:"desiredValue,omitempty"`
+}
+
 // TablespaceStatus represents the status of a tablespace in the cluster
 type TablespaceStatus string
 
@@ -694,10 +904,34 @@ type ClusterStatus struct {
 	// +optional
 	TablespacesStatus []TablespaceState `json:"tablespacesStatus,omitempty"`
 
+	// The parameters whose live value, in at least one instance, has been
+	// changed with `ALTER SYSTEM`
+	// +optional
+	ConfigurationDrift []ParameterDrift `json:"configurationDrift,omitempty"`
+
 	// The timeline of the Postgres cluster
 	// +optional
 	TimelineID int `json:"timelineID,omitempty"`
 
+	// The digest of the PostgreSQL image run by the most recently created
+	// instance, as pulled by Kubernetes: the image registry is not queried.
+	// It is only tracked when the image pull policy is `Always`, and is
+	// used to roll out the instances still running an older image behind
+	// the same tag
+	// +optional
+	LatestImageDigest string `json:"latestImageDigest,omitempty"`
+
+	// The PostgreSQL image that was last used by the primary to run its
+	// data directory, and its major version. When the requested image has
+	// a newer major version, the data directory is upgraded with pg_upgrade
+	// +optional
+	PGDataImageInfo *ImageInfo `json:"pgDataImageInfo,omitempty"`
+
+	// The list of the replicas whose replication lag is exceeding the
+	// thresholds defined in `.spec.replicationLagSLO`
+	// +optional
+	LaggingReplicas []string `json:"laggingReplicas,omitempty"`
+
 	// Instances topology.
 	// +optional
 	Topology Topology `json:"topology,omitempty"`
@@ -728,10 +962,16 @@ type ClusterStatus struct {
 	// +optional
 	DanglingPVC []string `json:"danglingPVC,omitempty"`
 
-	// List of all the PVCs that have ResizingPVC condition.
+	// List of all the PVCs that are being resized
 	// +optional
 	ResizingPVC []string `json:"resizingPVC,omitempty"`
 
+	// List of all the PVCs whose file system has not been expanded while
+	// in use, and which require a restart of the Pod using them to
+	// complete the resize
+	// +optional
+	FileSystemResizePendingPVC []string `json:"fileSystemResizePendingPVC,omitempty"`
+
 	// List of all the PVCs that are being initialized by this cluster
 	// +optional
 	InitializingPVC []string `json:"initializingPVC,omitempty"`
@@ -752,6 +992,12 @@ type ClusterStatus struct {
 	// +optional
 	ReadService string `json:"readService,omitempty"`
 
+	// The stable DNS names of the instances, resolved through the
+	// headless "-any" service even before the instances are ready.
+	// Only available when the operator creates the "-any" service
+	// +optional
+	InstancesFQDN map[PodName]string `json:"instancesFQDN,omitempty"`
+
 	// Current phase of the cluster
 	// +optional
 	Phase string `json:"phase,omitempty"`
@@ -800,6 +1046,12 @@ type ClusterStatus struct {
 	// +optional
 	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
 
+	// The named restore points created on the primary, usable as
+	// `targetName` in the recovery target. Only the most recent ones
+	// are kept, the last one being the most recent
+	// +optional
+	RestorePoints []RestorePoint `json:"restorePoints,omitempty"`
+
 	// The commit hash number of which this operator running
 	// +optional
 	CommitHash string `json:"cloudNativePGCommitHash,omitempty"`
@@ -817,6 +1069,17 @@ type ClusterStatus struct {
 	// +optional
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
 
+	// The planned switchover that has been announced, and that is waiting
+	// for the applications to be drained before changing the target primary
+	// +optional
+	PlannedSwitchover *PlannedSwitchoverStatus `json:"plannedSwitchover,omitempty"`
+
+	// The name of the Lease publishing the identity of the primary, set
+	// while the operator manages it, so that it can be deleted once the
+	// Lease is disabled
+	// +optional
+	PrimaryLease string `json:"primaryLease,omitempty"`
+
 	// The integration needed by poolers referencing the cluster
 	// +optional
 	PoolerIntegrations *PoolerIntegrations `json:"poolerIntegrations,omitempty"`
@@ -849,6 +1112,9 @@ type InstanceReportedState struct {
 	// indicates on which TimelineId the instance is
 	// +optional
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// indicates the digest of the PostgreSQL image the instance is running
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -863,6 +1129,38 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionReplicationHealthy represents whether the replicas are
+	// respecting the replication lag thresholds
+	ConditionReplicationHealthy ClusterConditionType = "ReplicationHealthy"
+	// ConditionStorageHealthy represents whether the storage of the
+	// instances is working
+	ConditionStorageHealthy ClusterConditionType = "StorageHealthy"
+	// ConditionTimelinesAligned represents whether every instance is on the
+	// same timeline of the primary
+	ConditionTimelinesAligned ClusterConditionType = "TimelinesAligned"
+	// ConditionUpdateAvailable represents whether a newer minor version of
+	// PostgreSQL is available for the image of the cluster
+	ConditionUpdateAvailable ClusterConditionType = "UpdateAvailable"
+	// ConditionPodsSchedulable represents whether the scheduler can place
+	// the Pods of the instances and of the jobs of the cluster
+	ConditionPodsSchedulable ClusterConditionType = "PodsSchedulable"
+	// ConditionCollationVersionsAligned represents whether the collations
+	// used by the databases match the version of the libraries in the image
+	ConditionCollationVersionsAligned ClusterConditionType = "CollationVersionsAligned"
+	// ConditionTransactionIDWraparoundSafe represents whether the age of the
+	// oldest unfrozen transaction ID of every database is below the warning
+	// threshold
+	ConditionTransactionIDWraparoundSafe ClusterConditionType = "TransactionIDWraparoundSafe"
+	// ConditionHealthy summarizes the health checks of the cluster,
+	// covering the primary, the WAL archiving, the replicas, the
+	// certificates and the backups
+	ConditionHealthy ClusterConditionType = "Healthy"
+	// ConditionFailoverAllowed represents whether the last failover was
+	// allowed by the maximum data loss on failover check
+	ConditionFailoverAllowed ClusterConditionType = "FailoverAllowed"
+	// ConditionVolumesExpandable represents whether the storage classes of
+	// the PVCs allow them to be expanded to the requested size
+	ConditionVolumesExpandable ClusterConditionType = "VolumesExpandable"
 )
 
 // A Condition that can be used to communicate the Backup progress
@@ -943,6 +1241,95 @@ const (
 
 	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
 	DetachedVolume ConditionReason = "DetachedVolume"
+
+	// ReplicationLagWithinSLO means that every replica is respecting the
+	// replication lag thresholds
+	ReplicationLagWithinSLO ConditionReason = "ReplicationLagWithinSLO"
+
+	// ReplicationLagSLOBreached means that at least one replica is exceeding
+	// the replication lag thresholds
+	ReplicationLagSLOBreached ConditionReason = "ReplicationLagSLOBreached"
+
+	// StorageFailureDetected means that at least one instance is reporting
+	// a read-only or failing storage
+	StorageFailureDetected ConditionReason = "StorageFailureDetected"
+
+	// StorageFailureResolved means that no instance is reporting a storage
+	// failure anymore
+	StorageFailureResolved ConditionReason = "StorageFailureResolved"
+
+	// TimelinesDiverging means that at least one instance is on a different
+	// timeline than the primary
+	TimelinesDiverging ConditionReason = "TimelinesDiverging"
+
+	// TimelinesConverged means that every instance is on the same timeline
+	// of the primary again
+	TimelinesConverged ConditionReason = "TimelinesConverged"
+
+	// MinorUpdateAvailable means that an image running a newer minor
+	// version of PostgreSQL is known to the operator
+	MinorUpdateAvailable ConditionReason = "MinorUpdateAvailable"
+
+	// ImageUpToDate means that the image of the cluster is running the
+	// latest minor version of PostgreSQL known to the operator
+	ImageUpToDate ConditionReason = "ImageUpToDate"
+
+	// PodsUnschedulable means that the scheduler cannot place at least one
+	// of the Pods of the cluster
+	PodsUnschedulable ConditionReason = "PodsUnschedulable"
+
+	// PodsScheduled means that no Pod of the cluster is waiting to be
+	// scheduled anymore
+	PodsScheduled ConditionReason = "PodsScheduled"
+
+	// CollationVersionMismatch means that at least one database uses a
+	// collation whose version changed, and needs its indexes to be rebuilt
+	CollationVersionMismatch ConditionReason = "CollationVersionMismatch"
+
+	// CollationVersionsMatch means that every collation used by the
+	// databases matches the version of the libraries in the image
+	CollationVersionsMatch ConditionReason = "CollationVersionsMatch"
+
+	// TransactionIDAgeAboveThreshold means that the oldest unfrozen
+	// transaction ID of at least one database is older than the
+	// warning threshold
+	TransactionIDAgeAboveThreshold ConditionReason = "TransactionIDAgeAboveThreshold"
+
+	// TransactionIDAgeBelowThreshold means that the oldest unfrozen
+	// transaction ID of every database is younger than the warning threshold
+	TransactionIDAgeBelowThreshold ConditionReason = "TransactionIDAgeBelowThreshold"
+
+	// HealthChecksPassing means that every health check of the cluster
+	// is passing
+	HealthChecksPassing ConditionReason = "HealthChecksPassing"
+
+	// HealthChecksFailing means that at least one of the health checks of
+	// the cluster is failing
+	HealthChecksFailing ConditionReason = "HealthChecksFailing"
+
+	// FailoverDataLossWithinThreshold means that the data loss of the
+	// failover was estimated below the maximum allowed one
+	FailoverDataLossWithinThreshold ConditionReason = "FailoverDataLossWithinThreshold"
+
+	// FailoverDataLossAllowedByUser means that the failover was executed
+	// despite its data loss, as allowed by the user via annotation
+	FailoverDataLossAllowedByUser ConditionReason = "FailoverDataLossAllowedByUser"
+
+	// FailoverDataLossExceeded means that the failover is blocked, as its
+	// estimated data loss exceeds the maximum allowed one
+	FailoverDataLossExceeded ConditionReason = "FailoverDataLossExceeded"
+
+	// FailoverDataLossUnknown means that the failover is blocked, as its
+	// data loss cannot be estimated
+	FailoverDataLossUnknown ConditionReason = "FailoverDataLossUnknown"
+
+	// VolumeExpansionNotAllowed means that at least one PVC cannot be
+	// expanded, as its storage class doesn't allow volume expansion
+	VolumeExpansionNotAllowed ConditionReason = "VolumeExpansionNotAllowed"
+
+	// VolumeExpansionAllowed means that no PVC is waiting for an expansion
+	// refused by its storage class anymore
+	VolumeExpansionAllowed ConditionReason = "VolumeExpansionAllowed"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -978,6 +1365,12 @@ type ReplicaClusterConfiguration struct {
 	// object store or via streaming through pg_basebackup.
 	// Refer to the Replica clusters page of the documentation for more information.
 	Enabled bool `json:"enabled"`
+
+	// When enabled, the cluster is a permanent standby of the source,
+	// e.g. for reporting: no write service is reported in the status, and
+	// the replica mode can't be disabled until this flag is turned off
+	// +optional
+	Permanent bool `json:"permanent,omitempty"`
 }
 
 // DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
@@ -1072,6 +1465,83 @@ func (r *ReplicationSlotsHAConfiguration) GetEnabled() bool {
 	return true
 }
 
+// ReplicationSslMode is the SSL mode used by the replicas to connect
+// to the primary
+type ReplicationSslMode string
+
+const (
+	// ReplicationSslModeVerifyCA verifies that the certificate of the
+	// primary is signed by the server CA
+	ReplicationSslModeVerifyCA = ReplicationSslMode("verify-ca")
+
+	// ReplicationSslModeVerifyFull also verifies that the certificate of
+	// the primary matches the name of the read-write service
+	ReplicationSslModeVerifyFull = ReplicationSslMode("verify-full")
+)
+
+// ReplicationConnectionConfiguration encapsulates the libpq connection
+// parameters used in `primary_conninfo` and when cloning the primary.
+// Parameters that are not specified are not passed to libpq,
+// which uses its own defaults.
+type ReplicationConnectionConfiguration struct {
+	// Maximum time to wait while connecting to the primary, in seconds.
+	// When not specified, the replicas wait indefinitely while the
+	// join job and the instance manager wait 5 seconds
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConnectTimeout int32 `json:"connectTimeout,omitempty"`
+
+	// Number of seconds of inactivity after which a TCP keepalive
+	// message is sent to the primary
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesIdle int32 `json:"keepalivesIdle,omitempty"`
+
+	// Number of seconds after which a TCP keepalive message that is
+	// not acknowledged by the primary is retransmitted
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesInterval int32 `json:"keepalivesInterval,omitempty"`
+
+	// Number of TCP keepalive messages that can be lost before the
+	// connection to the primary is considered dead
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesCount int32 `json:"keepalivesCount,omitempty"`
+
+	// Number of milliseconds that transmitted data may remain
+	// unacknowledged before the connection to the primary is
+	// forcibly closed
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TCPUserTimeout int32 `json:"tcpUserTimeout,omitempty"`
+
+	// The SSL mode used to connect to the primary, either `verify-ca`
+	// (default) or `verify-full`
+	// +kubebuilder:validation:Enum=verify-ca;verify-full
+	// +kubebuilder:default:=verify-ca
+	// +optional
+	SslMode ReplicationSslMode `json:"sslMode,omitempty"`
+}
+
+// GetConnectTimeout returns the configured connection timeout, or zero
+// if the libpq default should be used
+func (r *ReplicationConnectionConfiguration) GetConnectTimeout() int32 {
+	if r == nil {
+		return 0
+	}
+	return r.ConnectTimeout
+}
+
+// GetSslMode returns the SSL mode used to connect to the primary,
+// defaulting to `verify-ca`
+func (r *ReplicationConnectionConfiguration) GetSslMode() ReplicationSslMode {
+	if r == nil || r.SslMode == "" {
+		return ReplicationSslModeVerifyCA
+	}
+	return r.SslMode
+}
+
 // KubernetesUpgradeStrategy tells the operator if the user want to
 // allocate more space while upgrading a k8s node which is hosting
 // the PostgreSQL Pods or just wait for the node to come up
@@ -1107,6 +1577,419 @@ type NodeMaintenanceWindow struct {
 	InProgress bool `json:"inProgress,omitempty"`
 }
 
+// ReplicationLagSLO contains the replication lag thresholds a standby
+// instance is expected to respect
+type ReplicationLagSLO struct {
+	// The maximum amount of WAL, in bytes, that a replica may have still
+	// to replay compared to the current position of the primary
+	// +optional
+	MaxLagSize *resource.Quantity `json:"maxLagSize,omitempty"`
+
+	// The maximum replay lag of a replica, as reported by the primary
+	// in `pg_stat_replication`
+	// +optional
+	MaxLagTime *metav1.Duration `json:"maxLagTime,omitempty"`
+
+	// When enabled, the replicas breaching the thresholds are not
+	// considered for the synchronous replication quorum until they
+	// catch up (default false)
+	// +kubebuilder:default:=false
+	// +optional
+	ExcludeFromSyncQuorum bool `json:"excludeFromSyncQuorum,omitempty"`
+}
+
+// IsBreachedBy checks whether a replica having the given replication
+// lag, in bytes and in time, is exceeding the thresholds
+func (slo *ReplicationLagSLO) IsBreachedBy(lagSize int64, lagTime time.Duration) bool {
+	if slo == nil {
+		return false
+	}
+
+	if slo.MaxLagSize != nil && lagSize > slo.MaxLagSize.Value() {
+		return true
+	}
+
+	if slo.MaxLagTime != nil && lagTime > slo.MaxLagTime.Duration {
+		return true
+	}
+
+	return false
+}
+
+// MaintenanceWindow is a recurring time window in which the operator
+// is allowed to perform rolling updates
+type MaintenanceWindow struct {
+	// The schedule of the start of the window, in UTC. It follows the same
+	// format used by ScheduledBackup, including the seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	Schedule string `json:"schedule"`
+
+	// The duration of the window
+	Duration metav1.Duration `json:"duration"`
+}
+
+// ImageUpgradeSafetyFailurePolicy is the action taken by the operator when
+// the safety checks of an image upgrade are not satisfied
+type ImageUpgradeSafetyFailurePolicy string
+
+const (
+	// ImageUpgradeSafetyFailurePolicyBlock means that the rollout of the new
+	// image waits until the checks are satisfied (`block`, default)
+	ImageUpgradeSafetyFailurePolicyBlock ImageUpgradeSafetyFailurePolicy = "block"
+
+	// ImageUpgradeSafetyFailurePolicyWarn means that the rollout of the new
+	// image proceeds, raising a warning event (`warn`)
+	ImageUpgradeSafetyFailurePolicyWarn ImageUpgradeSafetyFailurePolicy = "warn"
+)
+
+// ImageUpgradeSafetyConfiguration contains the checks the operator runs
+// before restarting the first instance on a new PostgreSQL image
+type ImageUpgradeSafetyConfiguration struct {
+	// When enabled, the operator creates a named restore point on the
+	// primary before rolling out the new image. The restore point is
+	// not created in replica clusters
+	// +optional
+	CreateRestorePoint bool `json:"createRestorePoint,omitempty"`
+
+	// The maximum age of the last successful backup of the cluster.
+	// When set, the new image is only rolled out if a backup completed
+	// more recently than that
+	// +optional
+	MaxBackupAge *metav1.Duration `json:"maxBackupAge,omitempty"`
+
+	// The action to take when the checks are not satisfied: wait for
+	// them to be satisfied (`block` - default), or proceed with the
+	// rollout raising a warning event (`warn`)
+	// +kubebuilder:default:=block
+	// +kubebuilder:validation:Enum:=block;warn
+	// +optional
+	FailurePolicy ImageUpgradeSafetyFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// CollationConfiguration contains the handling of the collations whose
+// version differs from the one provided by the libraries of the image
+type CollationConfiguration struct {
+	// When enabled, the primary rebuilds the indexes of the databases
+	// using a collation whose version changed, and records the new
+	// version, inside the maintenance windows of the cluster.
+	// Otherwise, the mismatches are only reported in the
+	// `CollationVersionsAligned` condition
+	// +optional
+	AutoReindex bool `json:"autoReindex,omitempty"`
+}
+
+// DefaultWraparoundWarningAge is the default age of the oldest unfrozen
+// transaction ID of a database above which the wraparound risk is reported
+const DefaultWraparoundWarningAge = 1000000000
+
+// WraparoundProtectionConfiguration contains the thresholds used to detect
+// and mitigate the transaction ID wraparound risk
+type WraparoundProtectionConfiguration struct {
+	// The age of the oldest unfrozen transaction ID of a database above
+	// which the `TransactionIDWraparoundSafe` condition of the cluster
+	// is set to false. Default: 1000000000
+	// +kubebuilder:validation:Minimum=10000000
+	// +kubebuilder:validation:Maximum=2000000000
+	// +optional
+	WarningAge *int32 `json:"warningAge,omitempty"`
+
+	// When set, the primary runs `VACUUM (FREEZE)` on the databases whose
+	// oldest unfrozen transaction ID is older than this age, regardless
+	// of the maintenance windows of the cluster. Otherwise, no vacuum
+	// is triggered by the operator
+	// +kubebuilder:validation:Minimum=10000000
+	// +kubebuilder:validation:Maximum=2000000000
+	// +optional
+	EmergencyVacuumAge *int32 `json:"emergencyVacuumAge,omitempty"`
+}
+
+// IsOpen checks whether the maintenance window is open at the given time.
+// An invalid schedule never opens the window
+func (window MaintenanceWindow) IsOpen(now time.Time) bool {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return false
+	}
+
+	// The window is open if it started less than its duration ago
+	now = now.UTC()
+	return !schedule.Next(now.Add(-window.Duration.Duration)).After(now)
+}
+
+// GetEnd gets the end time of the occurrence of the maintenance window
+// open at the given time, or the zero time if the window is not open
+func (window MaintenanceWindow) GetEnd(now time.Time) time.Time {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+
+	now = now.UTC()
+	start := schedule.Next(now.Add(-window.Duration.Duration))
+	if start.After(now) {
+		return time.Time{}
+	}
+
+	return start.Add(window.Duration.Duration)
+}
+
+// GetNextStart gets the start time of the first occurrence of the maintenance
+// window following the given time, or the zero time if the schedule is invalid
+func (window MaintenanceWindow) GetNextStart(now time.Time) time.Time {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return schedule.Next(now.UTC())
+}
+
+// PreStopStrategy contains the action taken by the instance manager
+// before an instance Pod is sent the termination signal
+type PreStopStrategy string
+
+const (
+	// PreStopStrategyNone means that no preStop hook is configured (`none`, default)
+	PreStopStrategyNone PreStopStrategy = "none"
+
+	// PreStopStrategyCheckpoint means that the instance manager issues a
+	// checkpoint before PostgreSQL is shut down (`checkpoint`)
+	PreStopStrategyCheckpoint PreStopStrategy = "checkpoint"
+
+	// PreStopStrategyFastShutdown means that the instance manager issues a
+	// checkpoint, and then shuts down PostgreSQL with the fast mode, skipping
+	// the smart one (`fastShutdown`)
+	PreStopStrategyFastShutdown PreStopStrategy = "fastShutdown"
+)
+
+// LifecycleHooksConfiguration contains the hooks invoked by the instance
+// manager at key moments of the lifecycle of the instances
+type LifecycleHooksConfiguration struct {
+	// The hooks invoked before promoting an instance to primary
+	// +optional
+	PrePromote []LifecycleHook `json:"prePromote,omitempty"`
+
+	// The hooks invoked after an instance has been promoted to primary
+	// +optional
+	PostPromote []LifecycleHook `json:"postPromote,omitempty"`
+
+	// The hooks invoked before shutting down PostgreSQL
+	// +optional
+	PreShutdown []LifecycleHook `json:"preShutdown,omitempty"`
+
+	// The hooks invoked after the bootstrap of the cluster
+	// +optional
+	PostBootstrap []LifecycleHook `json:"postBootstrap,omitempty"`
+
+	// The hooks invoked before taking a backup with the
+	// `barmanObjectStore` or `plugin` methods
+	// +optional
+	PreBackup []LifecycleHook `json:"preBackup,omitempty"`
+
+	// The hooks invoked by the operator before a planned switchover,
+	// once the switchover has been announced and the poolers paused,
+	// to let the applications drain their connections. A failing hook
+	// having the `Fail` failure policy aborts the switchover. Only
+	// `http` hooks are supported, as they are invoked by the operator
+	// +optional
+	PreSwitchover []LifecycleHook `json:"preSwitchover,omitempty"`
+}
+
+// ImageInfo contains the information about a PostgreSQL image
+type ImageInfo struct {
+	// The name of the image
+	Image string `json:"image"`
+
+	// The major version of PostgreSQL in the image
+	MajorVersion int `json:"majorVersion"`
+}
+
+// RestorePoint is a named restore point created on the primary
+type RestorePoint struct {
+	// The name of the restore point
+	Name string `json:"name"`
+
+	// The LSN of the restore point. It is empty while the restore point
+	// is being created, or until the instance manager creates it again
+	// when it could not record the LSN
+	// +optional
+	LSN string `json:"lsn,omitempty"`
+
+	// When the restore point has been created
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// PlannedSwitchoverStatus is a planned switchover that has been announced
+type PlannedSwitchoverStatus struct {
+	// The instance that will be promoted
+	TargetPrimary string `json:"targetPrimary"`
+
+	// Why the switchover has been requested
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// When the switchover has been announced
+	AnnouncedAt metav1.Time `json:"announcedAt"`
+
+	// When the preSwitchover hooks have completed. The hooks run in the
+	// background and, until then, the switchover waits for them
+	// +optional
+	HooksCompletedAt *metav1.Time `json:"hooksCompletedAt,omitempty"`
+
+	// When the switchover has been aborted, leaving the current
+	// primary in place
+	// +optional
+	AbortedAt *metav1.Time `json:"abortedAt,omitempty"`
+
+	// Why the switchover has been aborted
+	// +optional
+	AbortReason string `json:"abortReason,omitempty"`
+}
+
+// LifecycleHookFailurePolicy is the action taken when a lifecycle hook fails
+type LifecycleHookFailurePolicy string
+
+const (
+	// LifecycleHookFailurePolicyFail means that the failure of the hook
+	// aborts the operation, when it still can be aborted (`Fail`)
+	LifecycleHookFailurePolicyFail LifecycleHookFailurePolicy = "Fail"
+
+	// LifecycleHookFailurePolicyIgnore means that the failure of the hook
+	// is only logged (`Ignore`, default)
+	LifecycleHookFailurePolicyIgnore LifecycleHookFailurePolicy = "Ignore"
+)
+
+// DefaultLifecycleHookTimeoutSeconds is the default time in seconds
+// a lifecycle hook is allowed to run
+const DefaultLifecycleHookTimeoutSeconds = 10
+
+// LifecycleHook is an HTTP request or a command invoked by the
+// instance manager at a lifecycle event. Exactly one between `http`
+// and `exec` must be specified
+type LifecycleHook struct {
+	// The name of the hook, used in the logs
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The HTTP endpoint receiving a POST request with the details
+	// of the event
+	// +optional
+	HTTP *HTTPLifecycleHook `json:"http,omitempty"`
+
+	// The command executed in the PostgreSQL container
+	// +optional
+	Exec *ExecLifecycleHook `json:"exec,omitempty"`
+
+	// The time in seconds the hook is allowed to run. Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=10
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// What to do when the hook fails: `Fail` aborts the operation when
+	// it can still be aborted, while `Ignore` (default) only logs the error.
+	// The promotion, the bootstrap and the backup can be aborted, while
+	// the failure of the `postPromote` and `preShutdown` hooks is always
+	// ignored
+	// +kubebuilder:validation:Enum:=Fail;Ignore
+	// +kubebuilder:default:=Ignore
+	// +optional
+	FailurePolicy LifecycleHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// HTTPLifecycleHook is a lifecycle hook invoking an HTTP endpoint
+type HTTPLifecycleHook struct {
+	// The URL of the endpoint, using the `http` or `https` scheme
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// The headers added to the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ExecLifecycleHook is a lifecycle hook executing a command
+type ExecLifecycleHook struct {
+	// The command to execute, with its arguments. The command is not run
+	// in a shell, and receives the details of the event in the
+	// `CNPG_HOOK_EVENT`, `CNPG_CLUSTER_NAME`, `CNPG_NAMESPACE` and
+	// `CNPG_POD_NAME` environment variables
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+}
+
+// GetTimeout returns the time the lifecycle hook is allowed to run
+func (hook LifecycleHook) GetTimeout() time.Duration {
+	if hook.TimeoutSeconds > 0 {
+		return time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	return DefaultLifecycleHookTimeoutSeconds * time.Second
+}
+
+// GetFailurePolicy returns the action to take when the lifecycle hook fails
+func (hook LifecycleHook) GetFailurePolicy() LifecycleHookFailurePolicy {
+	if hook.FailurePolicy == "" {
+		return LifecycleHookFailurePolicyIgnore
+	}
+
+	return hook.FailurePolicy
+}
+
+// ConfigurationDriftPolicy contains the policy to follow when the live
+// value of a parameter has been changed with `ALTER SYSTEM`
+type ConfigurationDriftPolicy string
+
+const (
+	// ConfigurationDriftPolicyAlert means that the drift is only reported
+	// in the status of the cluster (`alert`, default)
+	ConfigurationDriftPolicyAlert ConfigurationDriftPolicy = "alert"
+
+	// ConfigurationDriftPolicyRevert means that the instance manager resets
+	// the parameters changed with `ALTER SYSTEM` (`revert`)
+	ConfigurationDriftPolicyRevert ConfigurationDriftPolicy = "revert"
+)
+
+// WALTuningProfile is a preset of the checkpoint and WAL sizing
+// parameters, matching the throughput of the storage
+type WALTuningProfile string
+
+const (
+	// WALTuningProfileNone means that the PostgreSQL defaults are
+	// used (`none`, default)
+	WALTuningProfileNone WALTuningProfile = "none"
+
+	// WALTuningProfileLow matches storage sustaining up to
+	// about 150MB/s of writes (`low`)
+	WALTuningProfileLow WALTuningProfile = "low"
+
+	// WALTuningProfileMedium matches storage sustaining between about
+	// 150MB/s and 500MB/s of writes (`medium`)
+	WALTuningProfileMedium WALTuningProfile = "medium"
+
+	// WALTuningProfileHigh matches storage sustaining more than
+	// about 500MB/s of writes, like local NVMe disks (`high`)
+	WALTuningProfileHigh WALTuningProfile = "high"
+)
+
+// StaleTimelinePolicy contains the policy to follow when a replica is
+// found on an older timeline than the primary
+type StaleTimelinePolicy string
+
+const (
+	// StaleTimelinePolicyRewind means that the instance manager runs
+	// `pg_rewind` to align the replica with the primary (`rewind`)
+	StaleTimelinePolicyRewind StaleTimelinePolicy = "rewind"
+
+	// StaleTimelinePolicyReclone means that the instance manager runs
+	// `pg_rewind` and, if it fails, clones the primary again (`reclone`)
+	StaleTimelinePolicyReclone StaleTimelinePolicy = "reclone"
+
+	// StaleTimelinePolicyManual means that the instance manager doesn't
+	// take any action, waiting for a manual intervention (`manual`, default)
+	StaleTimelinePolicyManual StaleTimelinePolicy = "manual"
+)
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -1115,6 +1998,34 @@ type PrimaryUpdateStrategy string
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateMethod string
 
+// StorageClassUpdatePolicy contains the policy to follow when the
+// storage class of the PVCs of the cluster is changed
+type StorageClassUpdatePolicy string
+
+const (
+	// StorageClassUpdatePolicyIgnore means that the existing PVCs are kept,
+	// and only the new PVCs use the new storage class (`ignore`, default)
+	StorageClassUpdatePolicyIgnore StorageClassUpdatePolicy = "ignore"
+
+	// StorageClassUpdatePolicyMigrate means that the operator recreates the
+	// instances using a different storage class, one at a time (`migrate`)
+	StorageClassUpdatePolicyMigrate StorageClassUpdatePolicy = "migrate"
+)
+
+// PVCRetentionPolicy contains the policy to follow for the PVCs of
+// the instances when the cluster is deleted
+type PVCRetentionPolicy string
+
+const (
+	// PVCRetentionPolicyDelete means that the PVCs are deleted together
+	// with the cluster (`delete`, default)
+	PVCRetentionPolicyDelete PVCRetentionPolicy = "delete"
+
+	// PVCRetentionPolicyRetain means that the PVCs are kept after the
+	// deletion of the cluster, without an owner (`retain`)
+	PVCRetentionPolicyRetain PVCRetentionPolicy = "retain"
+)
+
 const (
 	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
 	// user to manually issue a switchover request before updating the primary
@@ -1170,6 +2081,13 @@ type PostgresConfiguration struct {
 	// +optional
 	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
 
+	// Configuration of the synchronous replication, as an alternative to
+	// `minSyncReplicas` and `maxSyncReplicas`. The requested number of
+	// synchronous standbys is always enforced, and the transactions wait
+	// for them even when not enough standbys are available
+	// +optional
+	Synchronous *SynchronousReplicaConfiguration `json:"synchronous,omitempty"`
+
 	// Lists of shared preload libraries to add to the default ones
 	// +optional
 	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
@@ -1190,6 +2108,92 @@ type PostgresConfiguration struct {
 	// Defaults to false.
 	// +optional
 	EnableAlterSystem bool `json:"enableAlterSystem,omitempty"`
+
+	// Policy to follow when the live value of a parameter has been changed
+	// with `ALTER SYSTEM`, drifting from the configuration of the cluster:
+	// the drift can be reported in the status of the cluster (`alert` -
+	// default), or reverted by resetting the changed parameters (`revert`)
+	// +kubebuilder:validation:Enum:=alert;revert
+	// +optional
+	ConfigurationDriftPolicy ConfigurationDriftPolicy `json:"configurationDriftPolicy,omitempty"`
+
+	// Options to detect and mitigate connection storms, that is
+	// a number of client connections exceeding a threshold for a
+	// sustained amount of time
+	// +optional
+	ConnectionStormProtection *ConnectionStormProtectionConfiguration `json:"connectionStormProtection,omitempty"`
+
+	// The preset of the checkpoint and WAL sizing parameters (`max_wal_size`,
+	// `min_wal_size`, `checkpoint_timeout` and `checkpoint_completion_target`)
+	// matching the write throughput of the storage, as measured for example
+	// with `kubectl cnpg fio`: `none` (default), `low`, `medium` or `high`.
+	// The operator doesn't measure the throughput, the profile is chosen
+	// by the user.
+	// `max_wal_size` and `min_wal_size` are capped to a fraction of the
+	// volume containing the WAL files, and the values set in `parameters`
+	// always take precedence
+	// +kubebuilder:validation:Enum:=none;low;medium;high
+	// +optional
+	WALTuningProfile WALTuningProfile `json:"walTuningProfile,omitempty"`
+}
+
+// ConnectionStormMitigation is the action taken by the instance
+// manager when it detects a connection storm
+type ConnectionStormMitigation string
+
+const (
+	// ConnectionStormMitigationAlert means that the storm is only
+	// reported through the logs and the metrics of the instance
+	ConnectionStormMitigationAlert ConnectionStormMitigation = "alert"
+
+	// ConnectionStormMitigationReject means that the instance rejects
+	// the new connections of any user but the superuser until the
+	// storm passes
+	ConnectionStormMitigationReject ConnectionStormMitigation = "reject"
+)
+
+// ConnectionStormProtectionConfiguration contains the parameters used
+// by the instance manager to detect and mitigate connection storms
+type ConnectionStormProtectionConfiguration struct {
+	// The number of client connections, excluding the ones of the
+	// superuser, above which the instance is considered under a storm
+	// +kubebuilder:validation:Minimum=1
+	Threshold int `json:"threshold"`
+
+	// The time the number of client connections needs to stay above
+	// the threshold before the storm is detected (default 30s)
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The action to take while a connection storm is in progress:
+	// `alert` (default) only reports it through the logs and the
+	// `cnpg_collector_connection_storm` metric, while `reject` also
+	// rejects the new connections of any user but the superuser
+	// via `pg_hba.conf`. The original rules are restored as soon as
+	// the number of connections goes back below the threshold
+	// +kubebuilder:validation:Enum:=alert;reject
+	// +kubebuilder:default:=alert
+	// +optional
+	Mitigation ConnectionStormMitigation `json:"mitigation,omitempty"`
+}
+
+// GetDuration gets the time the number of connections needs to stay
+// above the threshold before a storm is detected
+func (config *ConnectionStormProtectionConfiguration) GetDuration() time.Duration {
+	if config == nil || config.Duration == nil {
+		return 30 * time.Second
+	}
+
+	return config.Duration.Duration
+}
+
+// GetMitigation gets the action to take while a connection storm is in progress
+func (config *ConnectionStormProtectionConfiguration) GetMitigation() ConnectionStormMitigation {
+	if config == nil || config.Mitigation == "" {
+		return ConnectionStormMitigationAlert
+	}
+
+	return config.Mitigation
 }
 
 // BootstrapConfiguration contains information about how to create the PostgreSQL
@@ -1323,6 +2327,51 @@ type CertificatesConfiguration struct {
 	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
 	// +optional
 	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// The cert-manager issuer signing the server TLS certificate. When set,
+	// the operator creates a cert-manager `Certificate` storing the server
+	// certificate in the ServerTLSSecret, whose `ca.crt` is used as the
+	// server CA unless ServerCASecret is specified
+	// +optional
+	ServerIssuerRef *CertManagerIssuerReference `json:"serverIssuerRef,omitempty"`
+
+	// The cert-manager issuer signing the client certificate of the
+	// `streaming_replica` user. When set, the operator creates a cert-manager
+	// `Certificate` storing the client certificate in the ReplicationTLSSecret,
+	// whose `ca.crt` is used as the client CA unless ClientCASecret is specified
+	// +optional
+	ReplicationIssuerRef *CertManagerIssuerReference `json:"replicationIssuerRef,omitempty"`
+}
+
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// The name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The kind of the issuer, such as `Issuer` (the default) or `ClusterIssuer`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// The API group of the issuer, defaults to `cert-manager.io`
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// GetKind returns the kind of the issuer, defaulting to `Issuer`
+func (ref *CertManagerIssuerReference) GetKind() string {
+	if ref.Kind == "" {
+		return "Issuer"
+	}
+	return ref.Kind
+}
+
+// GetGroup returns the API group of the issuer, defaulting to `cert-manager.io`
+func (ref *CertManagerIssuerReference) GetGroup() string {
+	if ref.Group == "" {
+		return "cert-manager.io"
+	}
+	return ref.Group
 }
 
 // CertificatesStatus contains configuration certificates and related expiration dates.
@@ -1378,6 +2427,29 @@ type BootstrapInitDB struct {
 	// +optional
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb:
+	// `libc`, `icu` (PostgreSQL 15+) or `builtin` (PostgreSQL 17+).
+	// When empty, the PostgreSQL default is used (`libc`)
+	// +kubebuilder:validation:Enum:=libc;icu;builtin
+	// +optional
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb.
+	// Requires the `icu` locale provider
+	// +optional
+	IcuLocale string `json:"icuLocale,omitempty"`
+
+	// The value to be passed as option `--icu-rules` for initdb, the
+	// additional collation rules to customize the ICU locale
+	// (PostgreSQL 16+). Requires the `icu` locale provider
+	// +optional
+	IcuRules string `json:"icuRules,omitempty"`
+
+	// The value to be passed as option `--builtin-locale` for initdb,
+	// like `C` or `C.UTF-8`. Requires the `builtin` locale provider
+	// +optional
+	BuiltinLocale string `json:"builtinLocale,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -1408,6 +2480,12 @@ type BootstrapInitDB struct {
 	// +optional
 	Import *Import `json:"import,omitempty"`
 
+	// Bootstraps the new cluster by restoring a logical dump, taken with
+	// `pg_dump`, into the application database.
+	// Mutually exclusive with `import`.
+	// +optional
+	ImportFromDump *ImportFromDump `json:"importFromDump,omitempty"`
+
 	// PostInitApplicationSQLRefs points references to ConfigMaps or Secrets which
 	// contain SQL files, the general implementation order to these references is
 	// from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
@@ -1462,6 +2540,88 @@ type ImportSource struct {
 	ExternalCluster string `json:"externalCluster"`
 }
 
+// DumpFormat is the format of a logical dump taken with `pg_dump`
+type DumpFormat string
+
+const (
+	// DumpFormatCustom is the custom archive format of `pg_dump` (`-Fc`)
+	DumpFormatCustom DumpFormat = "custom"
+
+	// DumpFormatDirectory is the directory format of `pg_dump` (`-Fd`)
+	DumpFormatDirectory DumpFormat = "directory"
+
+	// DumpFormatTar is the tar archive format of `pg_dump` (`-Ft`)
+	DumpFormatTar DumpFormat = "tar"
+
+	// DumpFormatPlain is the plain SQL script format of `pg_dump` (`-Fp`)
+	DumpFormatPlain DumpFormat = "plain"
+)
+
+// ImportFromDump contains the configuration to init the application
+// database from a logical dump taken with `pg_dump`
+type ImportFromDump struct {
+	// The location of the dump
+	Location DumpLocation `json:"location"`
+
+	// The format of the dump: `custom`, `directory`, `tar` or `plain`.
+	// The dumps in the `plain` format are restored with `psql`, the other
+	// ones with `pg_restore`. Default: `custom`.
+	// +kubebuilder:validation:Enum=custom;directory;tar;plain
+	// +kubebuilder:default:=custom
+	// +optional
+	Format DumpFormat `json:"format,omitempty"`
+
+	// When set to true, the ownership and the privileges recorded in the
+	// dump are restored, requiring the involved roles to exist (i.e.
+	// creating them with `postInitSQL`). By default, every imported
+	// object is owned by the owner of the application database.
+	// Default: `false`.
+	// +optional
+	PreserveOwnership bool `json:"preserveOwnership,omitempty"`
+
+	// The roles owning objects in the dump, mapped to the roles of the
+	// cluster which will own them once the dump is restored. The source
+	// roles missing in the cluster are created for the time of the import.
+	// Requires `preserveOwnership` to be true
+	// +optional
+	OwnerMapping map[string]string `json:"ownerMapping,omitempty"`
+}
+
+// DumpLocation is where the logical dump to be imported is
+// stored. Only one of the locations can be set
+type DumpLocation struct {
+	// The PersistentVolumeClaim containing the dump, which is mounted
+	// read-only in the import job
+	// +optional
+	PersistentVolumeClaim *DumpVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// The secret key containing the HTTP or HTTPS URL where the dump is
+	// downloaded from, like a pre-signed URL of an object store.
+	// Not available with the `directory` format
+	// +optional
+	URLSecret *SecretKeySelector `json:"urlSecret,omitempty"`
+}
+
+// DumpVolumeSource is the PersistentVolumeClaim containing a logical dump
+type DumpVolumeSource struct {
+	// The name of the PersistentVolumeClaim, in the namespace of the cluster
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// The path of the dump, relative to the root of the volume
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
+// GetFormat gets the format of the dump, defaulting to `custom`
+func (importFromDump *ImportFromDump) GetFormat() DumpFormat {
+	if importFromDump.Format == "" {
+		return DumpFormatCustom
+	}
+
+	return importFromDump.Format
+}
+
 // PostInitApplicationSQLRefs points references to ConfigMaps or Secrets which
 // contain SQL files, the general implementation order to these references is
 // from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
@@ -1519,6 +2679,12 @@ type BootstrapRecovery struct {
 	// +optional
 	RecoveryTarget *RecoveryTarget `json:"recoveryTarget,omitempty"`
 
+	// The PostgreSQL settings used only by the first instance while it
+	// replays the WAL files during the recovery. They don't apply to the
+	// replicas joining the cluster
+	// +optional
+	Tuning *RecoveryTuning `json:"tuning,omitempty"`
+
 	// Name of the database used by the application. Default: `app`.
 	// +optional
 	Database string `json:"database,omitempty"`
@@ -1584,6 +2750,27 @@ type BootstrapPgBaseBackup struct {
 	Secret *LocalObjectReference `json:"secret,omitempty"`
 }
 
+// RecoveryTuning contains the PostgreSQL settings applied only while the
+// first instance replays the WAL files during the recovery. Settings not
+// supported by the PostgreSQL major version are ignored. The bandwidth used
+// to copy the base backup is not limited by the operator, so it can't be tuned
+type RecoveryTuning struct {
+	// Whether to prefetch the blocks referenced in the WAL files that
+	// are not yet in the buffer pool (`recovery_prefetch`).
+	// Available from PostgreSQL 15
+	// +kubebuilder:validation:Enum=try;on;off
+	// +optional
+	Prefetch string `json:"prefetch,omitempty"`
+
+	// The number of concurrent I/O requests issued while prefetching
+	// the blocks referenced in the WAL files (`maintenance_io_concurrency`).
+	// Available from PostgreSQL 13
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaintenanceIOConcurrency *int `json:"maintenanceIOConcurrency,omitempty"`
+}
+
 // RecoveryTarget allows to configure the moment where the recovery process
 // will stop. All the target options except TargetTLI are mutually exclusive.
 type RecoveryTarget struct {
@@ -1710,10 +2897,58 @@ type DatabaseRoleRef struct {
 type SyncReplicaElectionConstraints struct {
 	// A list of node labels values to extract and compare to evaluate if the pods reside in the same topology or not
 	// +optional
-	NodeLabelsAntiAffinity []string `json:"nodeLabelsAntiAffinity,omitempty"`
+	NodeLabelsAntiAffinity []string `json:"nodeLabelsAntiAffinity,omitempty"`
+
+	// This flag enables the constraints for sync replicas
+	Enabled bool `json:"enabled"`
+}
+
+// SynchronousReplicaConfigurationMethod is the method used to select the
+// synchronous standbys among the listed ones
+type SynchronousReplicaConfigurationMethod string
+
+const (
+	// SynchronousReplicaConfigurationMethodAny means that the transactions
+	// wait for any of the listed standbys (quorum-based replication)
+	SynchronousReplicaConfigurationMethodAny = SynchronousReplicaConfigurationMethod("any")
+
+	// SynchronousReplicaConfigurationMethodFirst means that the transactions
+	// wait for the first listed standbys (priority-based replication)
+	SynchronousReplicaConfigurationMethodFirst = SynchronousReplicaConfigurationMethod("first")
+)
+
+// SynchronousReplicaConfiguration contains the configuration of the
+// PostgreSQL synchronous replication, used to build the
+// `synchronous_standby_names` option
+type SynchronousReplicaConfiguration struct {
+	// Method to select the synchronous standbys among the listed ones,
+	// `any` for quorum-based synchronous replication and `first` for
+	// priority-based synchronous replication
+	// +kubebuilder:validation:Enum=any;first
+	Method SynchronousReplicaConfigurationMethod `json:"method"`
+
+	// The number of synchronous standbys the transactions must wait
+	// for before completing
+	// +kubebuilder:validation:Minimum=1
+	Number int `json:"number"`
+
+	// The maximum number of instances of the cluster listed in
+	// `synchronous_standby_names`. All the standbys of the cluster
+	// are listed by default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxStandbyNamesFromCluster *int `json:"maxStandbyNamesFromCluster,omitempty"`
 
-	// This flag enables the constraints for sync replicas
-	Enabled bool `json:"enabled"`
+	// The application names listed before the instances of the cluster,
+	// such as the standbys of another cluster. The order matters only
+	// with the `first` method
+	// +optional
+	StandbyNamesPre []string `json:"standbyNamesPre,omitempty"`
+
+	// The application names listed after the instances of the cluster.
+	// The order matters only with the `first` method
+	// +optional
+	StandbyNamesPost []string `json:"standbyNamesPost,omitempty"`
 }
 
 // AffinityConfiguration contains the info we need to create the
@@ -1765,6 +3000,18 @@ type AffinityConfiguration struct {
 	AdditionalPodAffinity *corev1.PodAffinity `json:"additionalPodAffinity,omitempty"`
 }
 
+// BootstrapControllerConfiguration defines how the instance manager
+// is installed in the Pods of the cluster
+type BootstrapControllerConfiguration struct {
+	// The absolute path of a compatible instance manager executable
+	// shipped by the PostgreSQL image. When set, the instance manager is
+	// installed from the PostgreSQL image, which avoids pulling the
+	// operator image in the Pods of the cluster
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	InstanceManagerPath string `json:"instanceManagerPath,omitempty"`
+}
+
 // RollingUpdateStatus contains the information about an instance which is
 // being updated
 type RollingUpdateStatus struct {
@@ -1895,7 +3142,8 @@ type BarmanObjectStoreConfiguration struct {
 }
 
 // BackupConfiguration defines how the backup of the cluster are taken.
-// The supported backup methods are BarmanObjectStore and VolumeSnapshot.
+// The supported backup methods are BarmanObjectStore, VolumeSnapshot,
+// Plugin and PVC.
 // For details and examples refer to the Backup and Recovery section of the
 // documentation
 type BackupConfiguration struct {
@@ -1907,11 +3155,28 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The backup plugin used to archive the WAL files and to take
+	// the backups using the `plugin` method
+	// +optional
+	Plugin *BackupPluginConfiguration `json:"plugin,omitempty"`
+
+	// The commands used to archive and restore the WAL files, for
+	// archival systems supported neither by barman-cloud nor by a
+	// backup plugin
+	// +optional
+	WalCommands *WalCommandsConfiguration `json:"walCommands,omitempty"`
+
+	// The PersistentVolumeClaim storing the WAL archive and the backups
+	// taken using the `pvc` method, for sites with no object storage
+	// +optional
+	PVC *PVCBackupConfiguration `json:"pvc,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
 	// days, weeks, months.
-	// It's currently only applicable when using the BarmanObjectStore method.
+	// It's currently only applicable when using the BarmanObjectStore
+	// and the PVC methods.
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
@@ -1926,6 +3191,67 @@ type BackupConfiguration struct {
 	Target BackupTarget `json:"target,omitempty"`
 }
 
+// BarmanCloudPluginName is the name of the built-in backup plugin based
+// on the barman-cloud tool suite
+const BarmanCloudPluginName = "barman-cloud"
+
+// BackupPluginConfiguration defines the plugin taking care of the
+// backups and of the WAL archive of the cluster. The plugin runs in a
+// sidecar container of every instance, listening for the requests of
+// the instance manager on the `/plugins/<name>.sock` Unix domain socket
+type BackupPluginConfiguration struct {
+	// The name of the plugin. `barman-cloud` is the built-in plugin using
+	// the `barmanObjectStore` configuration
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The image of the sidecar container running the plugin. It is
+	// required unless the built-in `barman-cloud` plugin is used,
+	// which defaults to the image of the cluster
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The parameters passed to the plugin with every request
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// WalCommandsConfiguration defines the shell commands used by the
+// instance manager to archive and restore the WAL files. In both
+// commands, `%p` is replaced by the path of the file, `%f` by its
+// name and `%%` by a single `%` character, like in the
+// `archive_command` and `restore_command` PostgreSQL settings
+type WalCommandsConfiguration struct {
+	// The command archiving the WAL file, which must reference it with
+	// `%p`. It is executed with `/bin/sh -c` in the data directory, and
+	// the archival is considered successful when it exits with status zero
+	// +kubebuilder:validation:MinLength=1
+	ArchiveCommand string `json:"archiveCommand"`
+
+	// The command copying the WAL file `%f` from the archive to the path
+	// `%p`, used by the replicas to fetch the WAL files they can't stream
+	// from the primary. It is executed with `/bin/sh -c`, and a non-zero
+	// exit status means that the file is not available
+	// +optional
+	RestoreCommand string `json:"restoreCommand,omitempty"`
+}
+
+// PVCBackupConfiguration defines the PersistentVolumeClaim where the WAL
+// files are archived and the base backups are stored. The claim is
+// created by the user and mounted by every instance, so it must support
+// the ReadWriteMany access mode. The files of each server are stored in
+// a directory named after it, allowing many clusters to share a claim
+type PVCBackupConfiguration struct {
+	// The name of the PersistentVolumeClaim, in the namespace of the cluster
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// The name of the directory where the files of the server are stored,
+	// defaulting to the name of the cluster
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
@@ -1943,6 +3269,12 @@ type WalBackupConfiguration struct {
 	// +optional
 	Encryption EncryptionType `json:"encryption,omitempty"`
 
+	// The ID of the AWS KMS key used to encrypt the WAL files when the
+	// encryption is `aws:kms`. When not specified, the AWS managed key of
+	// the bucket is used. Requires Barman 3.4 or later in the operand image
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
 	// Number of WAL files to be either archived in parallel (when the
 	// PostgreSQL instance is archiving to a backup object store) or
 	// restored in parallel (when a PostgreSQL standby is fetching WAL
@@ -1952,6 +3284,15 @@ type WalBackupConfiguration struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// The maximum number of ready WAL files to be archived by a single
+	// invocation of the archive command, uploading up to `maxParallel`
+	// of them at the same time. Larger batches reduce the archiving lag
+	// on write-heavy clusters. It defaults to `maxParallel`, and values
+	// lower than `maxParallel` are ignored.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ArchiveBatchSize int `json:"archiveBatchSize,omitempty"`
 }
 
 // DataBackupConfiguration is the configuration of the backup of
@@ -1972,6 +3313,12 @@ type DataBackupConfiguration struct {
 	// +optional
 	Encryption EncryptionType `json:"encryption,omitempty"`
 
+	// The ID of the AWS KMS key used to encrypt the backup files when the
+	// encryption is `aws:kms`. When not specified, the AWS managed key of
+	// the bucket is used. Requires Barman 3.4 or later in the operand image
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
 	// The number of parallel jobs to be used to upload the backup, defaults
 	// to 2
 	// +kubebuilder:validation:Minimum=1
@@ -1985,6 +3332,19 @@ type DataBackupConfiguration struct {
 	// possible. `false` by default.
 	// +optional
 	ImmediateCheckpoint bool `json:"immediateCheckpoint,omitempty"`
+
+	// The maximum amount of data to be uploaded per second to the object
+	// store while taking a backup, e.g. `100Mi`. No limit by default.
+	// Requires Barman >= 3.4
+	// +optional
+	BandwidthLimit *resource.Quantity `json:"bandwidthLimit,omitempty"`
+
+	// Whether to run the backup process with the lowest CPU priority and
+	// with the idle I/O scheduling class, to reduce its impact on the
+	// PostgreSQL workload. The I/O priority is only honored by the I/O
+	// schedulers supporting it, such as BFQ. `false` by default.
+	// +optional
+	LowPriority bool `json:"lowPriority,omitempty"`
 }
 
 // S3Credentials is the type for the credentials to be used to upload
@@ -2136,14 +3496,22 @@ type ExternalCluster struct {
 	// The configuration for the barman-cloud tool suite
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The PersistentVolumeClaim storing the backups and the WAL archive
+	// of the external cluster, taken with the `pvc` method
+	// +optional
+	PVC *PVCBackupConfiguration `json:"pvc,omitempty"`
 }
 
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
-// in the BarmanObjectStore
+// in the BarmanObjectStore or in the PVC configuration
 func (in ExternalCluster) GetServerName() string {
 	if in.BarmanObjectStore != nil && in.BarmanObjectStore.ServerName != "" {
 		return in.BarmanObjectStore.ServerName
 	}
+	if in.PVC != nil && in.PVC.ServerName != "" {
+		return in.PVC.ServerName
+	}
 	return in.Name
 }
 
@@ -2157,12 +3525,91 @@ const (
 	EnsureAbsent  EnsureOption = "absent"
 )
 
+// PrimaryLeaseConfiguration contains the configuration of the Lease holding
+// the identity of the current primary of the cluster
+type PrimaryLeaseConfiguration struct {
+	// Enabled is true when the operator must publish the Lease
+	Enabled bool `json:"enabled"`
+
+	// The duration of the Lease, in seconds (default 30). The operator
+	// renews the Lease at a third of its duration while the primary is
+	// healthy, and stops renewing it during a failover or a switchover
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=3
+	// +optional
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+}
+
 // ManagedConfiguration represents the portions of PostgreSQL that are managed
 // by the instance manager
 type ManagedConfiguration struct {
 	// Database roles managed by the `Cluster`
 	// +optional
 	Roles []RoleConfiguration `json:"roles,omitempty"`
+
+	// Services managed by the `Cluster`, in addition to the default ones
+	// +optional
+	Services *ManagedServices `json:"services,omitempty"`
+}
+
+// ManagedServices represents the services managed by the operator in
+// addition to the default ones
+type ManagedServices struct {
+	// InstanceServices, when enabled, makes the operator create a Service
+	// for each instance, named after the instance itself. These services
+	// select the instance regardless of its role, so they are stable across
+	// failovers and switchovers, and can be used to reach a specific
+	// instance from outside the Kubernetes cluster
+	// +optional
+	InstanceServices *InstanceServicesConfiguration `json:"instanceServices,omitempty"`
+
+	// ReadWriteFencingGap is the number of seconds the read-write Service
+	// doesn't select any instance after the promotion of a new primary.
+	// When set, the read-write Service is detached as soon as a failover
+	// or a switchover starts, and is attached to the new primary only
+	// once the gap has elapsed, so that no write can reach the former
+	// primary. Disabled by default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReadWriteFencingGap int32 `json:"readWriteFencingGap,omitempty"`
+}
+
+// InstanceServicesConfiguration contains the configuration of the Services
+// created for each instance of the cluster
+type InstanceServicesConfiguration struct {
+	// Enabled is true when a Service must be created for each instance
+	Enabled bool `json:"enabled"`
+
+	// ExternalDomain is the DNS domain used to build a stable external
+	// hostname for each instance, in the form `<instance-name>.<externalDomain>`.
+	// When set, the hostname is published via the
+	// `external-dns.alpha.kubernetes.io/hostname` annotation on the Service
+	// of each instance, so that it can be registered by external-dns
+	// +optional
+	ExternalDomain string `json:"externalDomain,omitempty"`
+
+	// Type is the type of the Service created for each instance
+	// +kubebuilder:validation:Enum:=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default:=ClusterIP
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Metadata are the additional labels and annotations to be set on
+	// the Service of each instance, i.e. to configure an internal load
+	// balancer. An explicit `external-dns.alpha.kubernetes.io/hostname`
+	// annotation takes precedence over the one generated from
+	// `externalDomain`
+	// +optional
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+// GetType returns the type of the Service created for each instance
+func (configuration *InstanceServicesConfiguration) GetType() corev1.ServiceType {
+	if configuration == nil || configuration.Type == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+
+	return configuration.Type
 }
 
 // RoleConfiguration is the representation, in Kubernetes, of a PostgreSQL role
@@ -2255,6 +3702,12 @@ type RoleConfiguration struct {
 	// Default is `false`.
 	// +optional
 	BypassRLS bool `json:"bypassrls,omitempty"` // Row-Level Security
+
+	// The default timeouts of the sessions of this role. When this section
+	// is present, the timeouts which are not specified are reset to the
+	// values inherited from the PostgreSQL configuration
+	// +optional
+	Timeouts *SessionTimeouts `json:"timeouts,omitempty"`
 }
 
 // GetRoleSecretsName gets the name of the secret which is used to store the role's password
@@ -2408,6 +3861,27 @@ func (cluster *Cluster) GetImageName() string {
 	return configuration.Current.PostgresImageName
 }
 
+// GetBootstrapControllerImageName gets the name of the image used to install
+// the instance manager in the Pods of the cluster
+func (cluster *Cluster) GetBootstrapControllerImageName() string {
+	if cluster.GetInstanceManagerPath() != "" {
+		return cluster.GetImageName()
+	}
+
+	return configuration.Current.OperatorImageName
+}
+
+// GetInstanceManagerPath gets the path of the instance manager executable
+// shipped by the PostgreSQL image, or an empty string if the instance
+// manager is installed from the operator image
+func (cluster *Cluster) GetInstanceManagerPath() string {
+	if cluster.Spec.BootstrapController == nil {
+		return ""
+	}
+
+	return cluster.Spec.BootstrapController.InstanceManagerPath
+}
+
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
 // image name.
 // Example:
@@ -2421,6 +3895,12 @@ func (cluster *Cluster) GetPostgresqlVersion() (int, error) {
 	return postgres.GetPostgresVersionFromTag(tag)
 }
 
+// GetPostgresqlMajorVersion gets the major version of PostgreSQL
+// in the image of the cluster, e.g. 16
+func (cluster *Cluster) GetPostgresqlMajorVersion() (int, error) {
+	return postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(cluster.GetImageName()))
+}
+
 // GetImagePullSecret get the name of the pull secret to use
 // to download the PostgreSQL image
 func (cluster *Cluster) GetImagePullSecret() string {
@@ -2499,6 +3979,41 @@ func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	return false
 }
 
+// AreInstanceServicesEnabled returns true if a Service must be created for
+// each instance of the cluster
+func (cluster *Cluster) AreInstanceServicesEnabled() bool {
+	return cluster.Spec.Managed != nil &&
+		cluster.Spec.Managed.Services != nil &&
+		cluster.Spec.Managed.Services.InstanceServices != nil &&
+		cluster.Spec.Managed.Services.InstanceServices.Enabled
+}
+
+// GetReadWriteFencingGap returns for how long the read-write Service
+// must not select any instance after the promotion of a new primary
+func (cluster *Cluster) GetReadWriteFencingGap() time.Duration {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return 0
+	}
+
+	return time.Duration(cluster.Spec.Managed.Services.ReadWriteFencingGap) * time.Second
+}
+
+// GetInstanceExternalHostname returns the stable external hostname of the
+// passed instance, or an empty string if no external domain has been
+// configured for the instance Services
+func (cluster *Cluster) GetInstanceExternalHostname(instanceName string) string {
+	if !cluster.AreInstanceServicesEnabled() {
+		return ""
+	}
+
+	externalDomain := cluster.Spec.Managed.Services.InstanceServices.ExternalDomain
+	if externalDomain == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s.%s", instanceName, externalDomain)
+}
+
 // GetApplicationSecretName get the name of the application secret for any bootstrap type
 func (cluster *Cluster) GetApplicationSecretName() string {
 	bootstrap := cluster.Spec.Bootstrap
@@ -2573,6 +4088,10 @@ func (cluster *Cluster) GetServerCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
 		return cluster.Spec.Certificates.ServerCASecret
 	}
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerIssuerRef != nil {
+		// cert-manager stores the CA in the secret of the certificate
+		return cluster.GetServerTLSSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
 }
 
@@ -2591,6 +4110,10 @@ func (cluster *Cluster) GetClientCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ClientCASecret != "" {
 		return cluster.Spec.Certificates.ClientCASecret
 	}
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationIssuerRef != nil {
+		// cert-manager stores the CA in the secret of the certificate
+		return cluster.GetReplicationSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, ClientCaSecretSuffix)
 }
 
@@ -2626,6 +4149,12 @@ func (cluster *Cluster) GetServiceAnyName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
 }
 
+// GetInstanceFQDN returns the stable DNS name of an instance, resolved
+// through the headless "-any" service even when the instance is not ready
+func (cluster *Cluster) GetInstanceFQDN(instanceName string) string {
+	return fmt.Sprintf("%v.%v.%v.svc", instanceName, cluster.GetServiceAnyName(), cluster.Namespace)
+}
+
 // GetServiceReadName return the name of the service that is used for
 // read transactions (including the primary)
 func (cluster *Cluster) GetServiceReadName() string {
@@ -2644,6 +4173,45 @@ func (cluster *Cluster) GetServiceReadWriteName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteSuffix)
 }
 
+// GetServiceDesignatedPrimaryName return the name of the service used by
+// the instances of a permanent replica cluster to stream from the
+// designated primary
+func (cluster *Cluster) GetServiceDesignatedPrimaryName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceDesignatedPrimarySuffix)
+}
+
+// GetServicePrimaryName return the name of the service selecting the
+// primary, which the other instances stream from: the read-write service
+// or, in a permanent replica cluster, the designated primary service
+func (cluster *Cluster) GetServicePrimaryName() string {
+	if cluster.IsPermanentReplica() {
+		return cluster.GetServiceDesignatedPrimaryName()
+	}
+	return cluster.GetServiceReadWriteName()
+}
+
+// GetPrimaryLeaseName return the name of the Lease holding the identity
+// of the current primary
+func (cluster *Cluster) GetPrimaryLeaseName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, PrimaryLeaseSuffix)
+}
+
+// IsPrimaryLeaseEnabled returns true if the operator must publish the
+// Lease holding the identity of the current primary
+func (cluster *Cluster) IsPrimaryLeaseEnabled() bool {
+	return cluster.Spec.PrimaryLease != nil && cluster.Spec.PrimaryLease.Enabled
+}
+
+// GetPrimaryLeaseDuration get the duration of the Lease holding the
+// identity of the current primary, defaulting to 30 seconds
+func (cluster *Cluster) GetPrimaryLeaseDuration() time.Duration {
+	if cluster.Spec.PrimaryLease == nil || cluster.Spec.PrimaryLease.LeaseDurationSeconds == 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(cluster.Spec.PrimaryLease.LeaseDurationSeconds) * time.Second
+}
+
 // GetMaxStartDelay get the amount of time of startDelay config option
 func (cluster *Cluster) GetMaxStartDelay() int32 {
 	if cluster.Spec.MaxStartDelay > 0 {
@@ -2660,6 +4228,26 @@ func (cluster *Cluster) GetMaxStopDelay() int32 {
 	return 1800
 }
 
+// GetTerminationGracePeriodSeconds get the amount of time the kubelet waits
+// for an instance Pod to terminate, defaulting to the stop delay
+func (cluster *Cluster) GetTerminationGracePeriodSeconds() int64 {
+	if cluster.Spec.TerminationGracePeriodSeconds != nil {
+		return *cluster.Spec.TerminationGracePeriodSeconds
+	}
+	return int64(cluster.GetMaxStopDelay())
+}
+
+// GetPreStopStrategy get the action taken in the preStop hook of
+// the instance Pods, defaulting to none
+func (cluster *Cluster) GetPreStopStrategy() PreStopStrategy {
+	strategy := cluster.Spec.PreStopStrategy
+	if strategy == "" {
+		return PreStopStrategyNone
+	}
+
+	return strategy
+}
+
 // GetSmartShutdownTimeout is used to ensure that smart shutdown timeout is a positive integer
 func (cluster *Cluster) GetSmartShutdownTimeout() int32 {
 	if cluster.Spec.SmartShutdownTimeout > 0 {
@@ -2668,6 +4256,31 @@ func (cluster *Cluster) GetSmartShutdownTimeout() int32 {
 	return 180
 }
 
+// GetRestorePoint returns the named restore point recorded in the
+// status, if any
+func (cluster *Cluster) GetRestorePoint(name string) *RestorePoint {
+	for idx := range cluster.Status.RestorePoints {
+		if cluster.Status.RestorePoints[idx].Name == name {
+			return &cluster.Status.RestorePoints[idx]
+		}
+	}
+
+	return nil
+}
+
+// IsSwitchoverOrchestrated returns true when the planned switchovers
+// need to be announced, and the applications drained, before changing
+// the target primary
+func (cluster *Cluster) IsSwitchoverOrchestrated() bool {
+	return cluster.Spec.LifecycleHooks != nil && len(cluster.Spec.LifecycleHooks.PreSwitchover) > 0
+}
+
+// IsPending returns true when the planned switchover has been announced
+// and has not been aborted yet
+func (planned *PlannedSwitchoverStatus) IsPending() bool {
+	return planned != nil && planned.AbortedAt == nil
+}
+
 // GetMaxSwitchoverDelay get the amount of time PostgreSQL has to stop before switchover
 func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	if cluster.Spec.MaxSwitchoverDelay > 0 {
@@ -2698,6 +4311,217 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// GetStorageClassUpdatePolicy get the policy to follow when the storage
+// class of the PVCs is changed, defaulting to ignore
+func (cluster *Cluster) GetStorageClassUpdatePolicy() StorageClassUpdatePolicy {
+	if cluster.Spec.StorageClassUpdatePolicy == "" {
+		return StorageClassUpdatePolicyIgnore
+	}
+
+	return cluster.Spec.StorageClassUpdatePolicy
+}
+
+// GetPVCRetentionPolicy get the policy to follow for the PVCs when the
+// cluster is deleted, defaulting to delete
+func (cluster *Cluster) GetPVCRetentionPolicy() PVCRetentionPolicy {
+	if cluster.Spec.PersistentVolumeClaimRetentionPolicy == "" {
+		return PVCRetentionPolicyDelete
+	}
+
+	return cluster.Spec.PersistentVolumeClaimRetentionPolicy
+}
+
+// GetConfigurationDriftPolicy get the policy to follow when the live value
+// of a parameter has been changed with `ALTER SYSTEM`, defaulting to alert
+func (cluster *Cluster) GetConfigurationDriftPolicy() ConfigurationDriftPolicy {
+	if cluster.Spec.PostgresConfiguration.ConfigurationDriftPolicy == "" {
+		return ConfigurationDriftPolicyAlert
+	}
+
+	return cluster.Spec.PostgresConfiguration.ConfigurationDriftPolicy
+}
+
+// walTuningPreset contains the values of the parameters
+// set by a WAL tuning profile
+type walTuningPreset struct {
+	maxWALSizeMB               int64
+	minWALSizeMB               int64
+	checkpointTimeout          string
+	checkpointCompletionTarget string
+}
+
+var walTuningPresets = map[WALTuningProfile]walTuningPreset{
+	WALTuningProfileLow: {
+		maxWALSizeMB:               2048,
+		minWALSizeMB:               512,
+		checkpointTimeout:          "15min",
+		checkpointCompletionTarget: "0.9",
+	},
+	WALTuningProfileMedium: {
+		maxWALSizeMB:               8192,
+		minWALSizeMB:               1024,
+		checkpointTimeout:          "15min",
+		checkpointCompletionTarget: "0.9",
+	},
+	WALTuningProfileHigh: {
+		maxWALSizeMB:               32768,
+		minWALSizeMB:               4096,
+		checkpointTimeout:          "30min",
+		checkpointCompletionTarget: "0.9",
+	},
+}
+
+// GetWALTuningSettings gets the PostgreSQL parameters set by the WAL tuning
+// profile of the cluster, if any. `max_wal_size` is capped to half of the
+// volume containing the WAL files, and `min_wal_size` to a quarter of
+// `max_wal_size`, so that checkpoints are triggered before the volume is full
+func (cluster *Cluster) GetWALTuningSettings() map[string]string {
+	preset, ok := walTuningPresets[cluster.Spec.PostgresConfiguration.WALTuningProfile]
+	if !ok {
+		return nil
+	}
+
+	volumeSize := cluster.Spec.WalStorage.GetSizeOrNil()
+	if volumeSize == nil {
+		volumeSize = cluster.Spec.StorageConfiguration.GetSizeOrNil()
+	}
+
+	// PostgreSQL requires min_wal_size to be at least two WAL segments
+	const minimumWALSizeMB = 32
+
+	maxWALSizeMB := preset.maxWALSizeMB
+	if volumeSize != nil {
+		maxWALSizeMB = max(min(maxWALSizeMB, volumeSize.Value()/(1024*1024)/2), 2*minimumWALSizeMB)
+	}
+	minWALSizeMB := max(min(preset.minWALSizeMB, maxWALSizeMB/4), minimumWALSizeMB)
+
+	return map[string]string{
+		"max_wal_size":                 fmt.Sprintf("%dMB", maxWALSizeMB),
+		"min_wal_size":                 fmt.Sprintf("%dMB", minWALSizeMB),
+		"checkpoint_timeout":           preset.checkpointTimeout,
+		"checkpoint_completion_target": preset.checkpointCompletionTarget,
+	}
+}
+
+// GetStaleTimelinePolicy get the policy to follow when a replica is found
+// on an older timeline than the primary, defaulting to manual
+func (cluster *Cluster) GetStaleTimelinePolicy() StaleTimelinePolicy {
+	policy := cluster.Spec.StaleTimelinePolicy
+	if policy == "" {
+		return StaleTimelinePolicyManual
+	}
+
+	return policy
+}
+
+// GetResourcesUpdatePolicy get the policy to apply the changes of the
+// Secrets projected into the instances, defaulting to reload
+func (cluster *Cluster) GetResourcesUpdatePolicy() ResourcesUpdatePolicy {
+	if cluster.Spec.ResourcesUpdatePolicy == "" {
+		return ResourcesUpdatePolicyReload
+	}
+
+	return cluster.Spec.ResourcesUpdatePolicy
+}
+
+// GetImageUpgradeSafetyFailurePolicy get the action to take when the
+// safety checks of an image upgrade are not satisfied
+func (cluster *Cluster) GetImageUpgradeSafetyFailurePolicy() ImageUpgradeSafetyFailurePolicy {
+	if cluster.Spec.ImageUpgradeSafety == nil || cluster.Spec.ImageUpgradeSafety.FailurePolicy == "" {
+		return ImageUpgradeSafetyFailurePolicyBlock
+	}
+
+	return cluster.Spec.ImageUpgradeSafety.FailurePolicy
+}
+
+// IsCollationAutoReindexEnabled checks whether the indexes depending on
+// a collation whose version changed are to be rebuilt automatically
+func (cluster *Cluster) IsCollationAutoReindexEnabled() bool {
+	return cluster.Spec.Collation != nil && cluster.Spec.Collation.AutoReindex
+}
+
+// GetWraparoundWarningAge gets the age of the oldest unfrozen transaction
+// ID of a database above which the wraparound risk is reported
+func (cluster *Cluster) GetWraparoundWarningAge() int32 {
+	if cluster.Spec.WraparoundProtection == nil || cluster.Spec.WraparoundProtection.WarningAge == nil {
+		return DefaultWraparoundWarningAge
+	}
+
+	return *cluster.Spec.WraparoundProtection.WarningAge
+}
+
+// GetWraparoundEmergencyVacuumAge gets the age of the oldest unfrozen
+// transaction ID of a database above which an aggressive vacuum is run,
+// and whether the emergency vacuum is enabled at all
+func (cluster *Cluster) GetWraparoundEmergencyVacuumAge() (int32, bool) {
+	if cluster.Spec.WraparoundProtection == nil || cluster.Spec.WraparoundProtection.EmergencyVacuumAge == nil {
+		return 0, false
+	}
+
+	return *cluster.Spec.WraparoundProtection.EmergencyVacuumAge, true
+}
+
+// GetLastSuccessfulBackupTime get the completion time of the most recent
+// successful backup of the cluster, among all the backup methods
+func (cluster *Cluster) GetLastSuccessfulBackupTime() (metav1.Time, bool) {
+	var lastBackup metav1.Time
+	found := false
+	for _, backupTime := range cluster.Status.LastSuccessfulBackupByMethod {
+		if !found || backupTime.After(lastBackup.Time) {
+			lastBackup = backupTime
+			found = true
+		}
+	}
+
+	return lastBackup, found
+}
+
+// IsInMaintenanceWindow checks whether the operator is allowed to perform
+// a rolling update of the cluster at the given time
+func (cluster *Cluster) IsInMaintenanceWindow(now time.Time) bool {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return true
+	}
+
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		if window.IsOpen(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMaintenanceWindowEnd gets the time the maintenance windows open at the
+// given time close, or the zero time if no window is open
+func (cluster *Cluster) GetMaintenanceWindowEnd(now time.Time) time.Time {
+	var end time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		if windowEnd := window.GetEnd(now); windowEnd.After(end) {
+			end = windowEnd
+		}
+	}
+
+	return end
+}
+
+// GetNextMaintenanceWindowStart gets the start time of the first maintenance
+// window following the given time, or the zero time if there is none
+func (cluster *Cluster) GetNextMaintenanceWindowStart(now time.Time) time.Time {
+	var nextStart time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		start := window.GetNextStart(now)
+		if start.IsZero() {
+			continue
+		}
+		if nextStart.IsZero() || start.Before(nextStart) {
+			nextStart = start
+		}
+	}
+
+	return nextStart
+}
+
 // IsNodeMaintenanceWindowInProgress check if the upgrade mode is active or not
 func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
@@ -2909,6 +4733,12 @@ func (cluster Cluster) IsReplica() bool {
 	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
 }
 
+// IsPermanentReplica checks if this is a replica cluster that is not
+// expected to be promoted
+func (cluster Cluster) IsPermanentReplica() bool {
+	return cluster.IsReplica() && cluster.Spec.ReplicaCluster.Permanent
+}
+
 var slotNameNegativeRegex = regexp.MustCompile("[^a-z0-9_]+")
 
 // GetSlotNameFromInstanceName returns the slot name, given the instance name.
@@ -2948,6 +4778,9 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 		cluster.GetServiceReadOnlyName(),
 		fmt.Sprintf("%v.%v", cluster.GetServiceReadOnlyName(), cluster.Namespace),
 		fmt.Sprintf("%v.%v.svc", cluster.GetServiceReadOnlyName(), cluster.Namespace),
+		cluster.GetServiceDesignatedPrimaryName(),
+		fmt.Sprintf("%v.%v", cluster.GetServiceDesignatedPrimaryName(), cluster.Namespace),
+		fmt.Sprintf("%v.%v.svc", cluster.GetServiceDesignatedPrimaryName(), cluster.Namespace),
 	}
 
 	if cluster.Spec.Certificates == nil {
@@ -3104,6 +4937,7 @@ func (cluster *Cluster) SetInheritedData(obj *metav1.ObjectMeta) {
 	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current)
 	utils.InheritLabels(obj, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.LabelClusterName(obj, cluster.GetName())
+	utils.LabelManagedBy(obj)
 	utils.SetOperatorVersion(obj, versions.Version)
 }
 
@@ -3159,6 +4993,69 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// GetBackupPlugin returns the configuration of the backup plugin
+// of the cluster, or nil if no backup plugin is defined
+func (cluster *Cluster) GetBackupPlugin() *BackupPluginConfiguration {
+	if cluster.Spec.Backup == nil {
+		return nil
+	}
+
+	return cluster.Spec.Backup.Plugin
+}
+
+// GetWalCommands gets the commands archiving and restoring the WAL files,
+// if they are configured
+func (cluster *Cluster) GetWalCommands() *WalCommandsConfiguration {
+	if cluster.Spec.Backup == nil {
+		return nil
+	}
+
+	return cluster.Spec.Backup.WalCommands
+}
+
+// GetBackupPVC gets the configuration of the PersistentVolumeClaim
+// storing the backups and the WAL archive, if it is configured
+func (cluster *Cluster) GetBackupPVC() *PVCBackupConfiguration {
+	if cluster.Spec.Backup == nil {
+		return nil
+	}
+
+	return cluster.Spec.Backup.PVC
+}
+
+// GetRetentionCutoff gets the time before which the backups stored in
+// the PersistentVolumeClaim are expired, or nil when there's no
+// retention policy
+func (backupConfiguration *BackupConfiguration) GetRetentionCutoff(now time.Time) (*time.Time, error) {
+	return getRetentionCutoff(backupConfiguration.RetentionPolicy, now)
+}
+
+// GetBackupPVCServerName gets the name of the directory where the
+// backups and the WAL archive of this cluster are stored
+func (cluster *Cluster) GetBackupPVCServerName() string {
+	if configuration := cluster.GetBackupPVC(); configuration != nil && configuration.ServerName != "" {
+		return configuration.ServerName
+	}
+
+	return cluster.Name
+}
+
+// GetRecoveryPVC gets the external cluster used as the source of the
+// recovery bootstrap, if its backups are stored in a PersistentVolumeClaim
+func (cluster *Cluster) GetRecoveryPVC() (*ExternalCluster, bool) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil ||
+		cluster.Spec.Bootstrap.Recovery.Source == "" {
+		return nil, false
+	}
+
+	externalCluster, found := cluster.ExternalCluster(cluster.Spec.Bootstrap.Recovery.Source)
+	if !found || externalCluster.PVC == nil {
+		return nil, false
+	}
+
+	return &externalCluster, true
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -3228,6 +5125,31 @@ func (cluster *Cluster) UpdateBackupTimes(
 		})
 }
 
+// BuildPostgresOptions create the list of options that
+// should be added to the PostgreSQL configuration to
+// tune the recovery, skipping the ones not supported
+// by the given major version of PostgreSQL
+func (tuning *RecoveryTuning) BuildPostgresOptions(majorVersion int) string {
+	result := ""
+
+	if tuning == nil {
+		return result
+	}
+
+	if tuning.Prefetch != "" && majorVersion >= 15 {
+		result += fmt.Sprintf(
+			"recovery_prefetch = '%v'\n",
+			tuning.Prefetch)
+	}
+	if tuning.MaintenanceIOConcurrency != nil && majorVersion >= 13 {
+		result += fmt.Sprintf(
+			"maintenance_io_concurrency = '%v'\n",
+			*tuning.MaintenanceIOConcurrency)
+	}
+
+	return result
+}
+
 // BuildPostgresOptions create the list of options that
 // should be added to the PostgreSQL configuration to
 // recover given a certain target
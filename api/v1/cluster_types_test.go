@@ -17,11 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -102,6 +105,86 @@ var _ = Describe("Primary update strategy", func() {
 	})
 })
 
+var _ = Describe("Maintenance windows", func() {
+	// Saturdays from 02:00 to 06:00, and Wednesdays from 22:00 to 23:00
+	cluster := Cluster{
+		Spec: ClusterSpec{
+			MaintenanceWindows: []MaintenanceWindow{
+				{Schedule: "0 0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				{Schedule: "0 0 22 * * 3", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+		},
+	}
+	// 2024-01-06 is a Saturday
+	saturday := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	It("allows rollouts at any time when no windows are defined", func() {
+		Expect((&Cluster{}).IsInMaintenanceWindow(saturday)).To(BeTrue())
+		Expect((&Cluster{}).GetNextMaintenanceWindowStart(saturday)).To(BeZero())
+	})
+
+	It("detects when the time is inside a window", func() {
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(2 * time.Hour))).To(BeTrue())
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(5 * time.Hour))).To(BeTrue())
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(4*24*time.Hour + 22*time.Hour + 30*time.Minute))).To(BeTrue())
+	})
+
+	It("detects when the time is outside every window", func() {
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(time.Hour))).To(BeFalse())
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(6 * time.Hour))).To(BeFalse())
+		Expect(cluster.IsInMaintenanceWindow(saturday.Add(24 * time.Hour))).To(BeFalse())
+	})
+
+	It("evaluates the windows in UTC", func() {
+		location := time.FixedZone("UTC+2", 2*60*60)
+		Expect(cluster.IsInMaintenanceWindow(time.Date(2024, 1, 6, 4, 0, 0, 0, location))).To(BeTrue())
+		Expect(cluster.IsInMaintenanceWindow(time.Date(2024, 1, 6, 3, 0, 0, 0, location))).To(BeFalse())
+	})
+
+	It("gets the start of the next window", func() {
+		Expect(cluster.GetNextMaintenanceWindowStart(saturday)).
+			To(Equal(saturday.Add(2 * time.Hour)))
+		Expect(cluster.GetNextMaintenanceWindowStart(saturday.Add(3 * time.Hour))).
+			To(Equal(saturday.Add(4*24*time.Hour + 22*time.Hour)))
+	})
+
+	It("never opens a window with an invalid schedule", func() {
+		window := MaintenanceWindow{Schedule: "invalid", Duration: metav1.Duration{Duration: time.Hour}}
+		Expect(window.IsOpen(saturday)).To(BeFalse())
+		Expect(window.GetNextStart(saturday)).To(BeZero())
+		Expect(window.GetEnd(saturday)).To(BeZero())
+	})
+
+	It("gets the end of the open window", func() {
+		Expect(cluster.GetMaintenanceWindowEnd(saturday.Add(3 * time.Hour))).
+			To(Equal(saturday.Add(6 * time.Hour)))
+		Expect(cluster.GetMaintenanceWindowEnd(saturday.Add(4*24*time.Hour + 22*time.Hour))).
+			To(Equal(saturday.Add(4*24*time.Hour + 23*time.Hour)))
+		Expect(cluster.GetMaintenanceWindowEnd(saturday.Add(time.Hour))).To(BeZero())
+		Expect((&Cluster{}).GetMaintenanceWindowEnd(saturday)).To(BeZero())
+	})
+})
+
+var _ = Describe("Replication lag SLO", func() {
+	It("is never breached when not defined", func() {
+		var slo *ReplicationLagSLO
+		Expect(slo.IsBreachedBy(1<<40, 24*time.Hour)).To(BeFalse())
+		Expect((&ReplicationLagSLO{}).IsBreachedBy(1<<40, 24*time.Hour)).To(BeFalse())
+	})
+
+	It("detects a breach of the size threshold", func() {
+		slo := &ReplicationLagSLO{MaxLagSize: ptr.To(resource.MustParse("16Mi"))}
+		Expect(slo.IsBreachedBy(16*1024*1024, 24*time.Hour)).To(BeFalse())
+		Expect(slo.IsBreachedBy(16*1024*1024+1, 0)).To(BeTrue())
+	})
+
+	It("detects a breach of the time threshold", func() {
+		slo := &ReplicationLagSLO{MaxLagTime: &metav1.Duration{Duration: 30 * time.Second}}
+		Expect(slo.IsBreachedBy(1<<40, 30*time.Second)).To(BeFalse())
+		Expect(slo.IsBreachedBy(0, 31*time.Second)).To(BeTrue())
+	})
+})
+
 var _ = Describe("Node maintenance window", func() {
 	It("default maintenance not in progress", func() {
 		cluster := Cluster{}
@@ -458,6 +541,33 @@ var _ = Describe("look up for secrets", func() {
 	It("retrieves server CA secret name", func() {
 		Expect(cluster.GetServerCASecretName()).To(Equal("clustername-ca"))
 	})
+	It("uses the secrets of the cert-manager certificates as CA secrets", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "clustername"},
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerTLSSecret:      "server-tls",
+					ServerIssuerRef:      &CertManagerIssuerReference{Name: "issuer"},
+					ReplicationTLSSecret: "replication-tls",
+					ReplicationIssuerRef: &CertManagerIssuerReference{Name: "issuer"},
+				},
+			},
+		}
+		Expect(cluster.GetServerCASecretName()).To(Equal("server-tls"))
+		Expect(cluster.GetClientCASecretName()).To(Equal("replication-tls"))
+
+		cluster.Spec.Certificates.ServerCASecret = "server-ca"
+		Expect(cluster.GetServerCASecretName()).To(Equal("server-ca"))
+	})
+	It("defaults the kind and the group of the cert-manager issuers", func() {
+		ref := CertManagerIssuerReference{Name: "issuer"}
+		Expect(ref.GetKind()).To(Equal("Issuer"))
+		Expect(ref.GetGroup()).To(Equal("cert-manager.io"))
+
+		ref = CertManagerIssuerReference{Name: "issuer", Kind: "ClusterIssuer", Group: "example.com"}
+		Expect(ref.GetKind()).To(Equal("ClusterIssuer"))
+		Expect(ref.GetGroup()).To(Equal("example.com"))
+	})
 	It("retrieves replication secret name", func() {
 		Expect(cluster.GetReplicationSecretName()).To(Equal("clustername-replication"))
 	})
@@ -465,7 +575,7 @@ var _ = Describe("look up for secrets", func() {
 		Expect(cluster.GetReplicationSecretName()).To(Equal("clustername-replication"))
 	})
 	It("retrieves all names needed to build a server CA certificate are 9", func() {
-		Expect(cluster.GetClusterAltDNSNames()).To(HaveLen(9))
+		Expect(cluster.GetClusterAltDNSNames()).To(HaveLen(12))
 	})
 })
 
@@ -939,6 +1049,15 @@ var _ = Describe("Managed Roles", func() {
 		Expect(cluster.Spec.Managed.Roles[0].GetRoleSecretsName()).To(Equal("test_user_secrets"))
 	})
 
+	It("Verifies the explicitly set values", func() {
+		role := RoleConfiguration{
+			Name:    "test_user",
+			Inherit: ptr.To(false),
+		}
+		Expect(role.GetRoleInherit()).To(BeFalse())
+		Expect(role.GetRoleSecretsName()).To(BeEmpty())
+	})
+
 	It("Verifies default values when there are no managed roles", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
@@ -948,6 +1067,63 @@ var _ = Describe("Managed Roles", func() {
 	})
 })
 
+var _ = Describe("Instance services", func() {
+	It("are disabled by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.AreInstanceServicesEnabled()).To(BeFalse())
+		Expect(cluster.GetInstanceExternalHostname("cluster-example-1")).To(BeEmpty())
+	})
+
+	It("have no read-write fencing gap by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetReadWriteFencingGap()).To(BeZero())
+
+		cluster.Spec.Managed = &ManagedConfiguration{
+			Services: &ManagedServices{ReadWriteFencingGap: 5},
+		}
+		Expect(cluster.GetReadWriteFencingGap()).To(Equal(5 * time.Second))
+	})
+
+	It("have no external hostname without an external domain", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						InstanceServices: &InstanceServicesConfiguration{Enabled: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.AreInstanceServicesEnabled()).To(BeTrue())
+		Expect(cluster.GetInstanceExternalHostname("cluster-example-1")).To(BeEmpty())
+	})
+
+	It("build the external hostname from the external domain", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						InstanceServices: &InstanceServicesConfiguration{
+							Enabled:        true,
+							ExternalDomain: "db.example.com",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetInstanceExternalHostname("cluster-example-1")).
+			To(Equal("cluster-example-1.db.example.com"))
+	})
+
+	It("are of type ClusterIP by default", func() {
+		var configuration *InstanceServicesConfiguration
+		Expect(configuration.GetType()).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect((&InstanceServicesConfiguration{}).GetType()).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect((&InstanceServicesConfiguration{Type: corev1.ServiceTypeLoadBalancer}).GetType()).
+			To(Equal(corev1.ServiceTypeLoadBalancer))
+	})
+})
+
 var _ = Describe("SeccompProfile usages", func() {
 	It("return a RuntimeDefault profile by default", func() {
 		cluster := Cluster{}
@@ -1081,3 +1257,304 @@ var _ = Describe("Tablespaces", func() {
 		})
 	})
 })
+
+var _ = Describe("Recovery target PostgreSQL options", func() {
+	It("is inclusive by default", func() {
+		var target *RecoveryTarget
+		Expect(target.BuildPostgresOptions()).To(BeEmpty())
+		Expect((&RecoveryTarget{}).BuildPostgresOptions()).To(Equal("recovery_target_inclusive = true\n"))
+	})
+
+	It("translates every target", func() {
+		target := &RecoveryTarget{
+			TargetTLI:  "latest",
+			TargetXID:  "1234",
+			TargetName: "before-upgrade",
+			TargetLSN:  "0/3000000",
+			TargetTime: "2024-03-10 10:00:00+00",
+			Exclusive:  ptr.To(true),
+		}
+		Expect(target.BuildPostgresOptions()).To(Equal(
+			"recovery_target_timeline = 'latest'\n" +
+				"recovery_target_xid = '1234'\n" +
+				"recovery_target_name = 'before-upgrade'\n" +
+				"recovery_target_lsn = '0/3000000'\n" +
+				"recovery_target_time = '2024-03-10 10:00:00+00'\n" +
+				"recovery_target_inclusive = false\n"))
+	})
+
+	It("supports the immediate target", func() {
+		target := &RecoveryTarget{TargetImmediate: ptr.To(true)}
+		Expect(target.BuildPostgresOptions()).To(Equal(
+			"recovery_target = immediate\nrecovery_target_inclusive = true\n"))
+	})
+})
+
+var _ = Describe("Recovery tuning PostgreSQL options", func() {
+	tuning := &RecoveryTuning{
+		Prefetch:                 "on",
+		MaintenanceIOConcurrency: ptr.To(256),
+	}
+
+	It("is empty by default", func() {
+		var empty *RecoveryTuning
+		Expect(empty.BuildPostgresOptions(16)).To(BeEmpty())
+		Expect((&RecoveryTuning{}).BuildPostgresOptions(16)).To(BeEmpty())
+	})
+
+	It("translates every setting", func() {
+		Expect(tuning.BuildPostgresOptions(16)).To(Equal(
+			"recovery_prefetch = 'on'\n" +
+				"maintenance_io_concurrency = '256'\n"))
+	})
+
+	It("skips the settings not supported by the PostgreSQL version", func() {
+		Expect(tuning.BuildPostgresOptions(14)).To(Equal("maintenance_io_concurrency = '256'\n"))
+		Expect(tuning.BuildPostgresOptions(12)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Bootstrap controller image", func() {
+	It("uses the operator image by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetInstanceManagerPath()).To(BeEmpty())
+		Expect(cluster.GetBootstrapControllerImageName()).To(Equal(configuration.Current.OperatorImageName))
+	})
+
+	It("uses the PostgreSQL image when it ships the instance manager", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16-custom",
+				BootstrapController: &BootstrapControllerConfiguration{
+					InstanceManagerPath: "/usr/local/bin/manager",
+				},
+			},
+		}
+		Expect(cluster.GetInstanceManagerPath()).To(Equal("/usr/local/bin/manager"))
+		Expect(cluster.GetBootstrapControllerImageName()).To(Equal("postgres:16-custom"))
+	})
+})
+
+var _ = Describe("Cluster IsReplica", func() {
+	It("is false when the replica stanza is missing", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsReplica()).To(BeFalse())
+	})
+
+	It("is false when the replica mode is disabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Source:  "origin",
+					Enabled: false,
+				},
+			},
+		}
+		Expect(cluster.IsReplica()).To(BeFalse())
+	})
+
+	It("is true when the replica mode is enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Source:  "origin",
+					Enabled: true,
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "origin"},
+				},
+			},
+		}
+		Expect(cluster.IsReplica()).To(BeTrue())
+
+		source, found := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+		Expect(found).To(BeTrue())
+		Expect(source.Name).To(Equal("origin"))
+	})
+
+	It("is permanent only when the replica mode is enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Source:    "origin",
+					Enabled:   true,
+					Permanent: true,
+				},
+			},
+		}
+		Expect(cluster.IsPermanentReplica()).To(BeTrue())
+
+		cluster.Spec.ReplicaCluster.Enabled = false
+		Expect(cluster.IsPermanentReplica()).To(BeFalse())
+	})
+
+	It("streams from the designated primary service in a permanent replica cluster", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{Enabled: true, Permanent: true},
+			},
+		}
+		Expect(cluster.GetServicePrimaryName()).To(Equal("cluster-example-designated"))
+		Expect(cluster.GetClusterAltDNSNames()).To(ContainElement("cluster-example-designated"))
+
+		cluster.Spec.ReplicaCluster.Permanent = false
+		Expect(cluster.GetServicePrimaryName()).To(Equal("cluster-example-rw"))
+	})
+})
+
+var _ = Describe("Cluster GetImageUpgradeSafetyFailurePolicy", func() {
+	It("blocks the rollout by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetImageUpgradeSafetyFailurePolicy()).To(Equal(ImageUpgradeSafetyFailurePolicyBlock))
+
+		cluster.Spec.ImageUpgradeSafety = &ImageUpgradeSafetyConfiguration{}
+		Expect(cluster.GetImageUpgradeSafetyFailurePolicy()).To(Equal(ImageUpgradeSafetyFailurePolicyBlock))
+	})
+
+	It("uses the configured policy", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageUpgradeSafety: &ImageUpgradeSafetyConfiguration{
+					FailurePolicy: ImageUpgradeSafetyFailurePolicyWarn,
+				},
+			},
+		}
+		Expect(cluster.GetImageUpgradeSafetyFailurePolicy()).To(Equal(ImageUpgradeSafetyFailurePolicyWarn))
+	})
+})
+
+var _ = Describe("Cluster GetLastSuccessfulBackupTime", func() {
+	It("returns false when there are no successful backups", func() {
+		cluster := Cluster{}
+		_, found := cluster.GetLastSuccessfulBackupTime()
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns the most recent backup among all the methods", func() {
+		now := metav1.Now()
+		cluster := Cluster{
+			Status: ClusterStatus{
+				LastSuccessfulBackupByMethod: map[BackupMethod]metav1.Time{
+					BackupMethodBarmanObjectStore: metav1.NewTime(now.Add(-time.Hour)),
+					BackupMethodVolumeSnapshot:    now,
+				},
+			},
+		}
+		lastBackup, found := cluster.GetLastSuccessfulBackupTime()
+		Expect(found).To(BeTrue())
+		Expect(lastBackup.Equal(&now)).To(BeTrue())
+	})
+})
+
+var _ = Describe("PVC backup configuration", func() {
+	It("uses the name of the cluster as the default server name", func() {
+		cluster := Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.GetBackupPVCServerName()).To(Equal("cluster-example"))
+
+		cluster.Spec.Backup = &BackupConfiguration{
+			PVC: &PVCBackupConfiguration{ClaimName: "backups", ServerName: "renamed"},
+		}
+		Expect(cluster.GetBackupPVCServerName()).To(Equal("renamed"))
+	})
+
+	It("detects a recovery from an external cluster stored in a PVC", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{Source: "origin"},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "origin", BarmanObjectStore: &BarmanObjectStoreConfiguration{}},
+				},
+			},
+		}
+		_, found := cluster.GetRecoveryPVC()
+		Expect(found).To(BeFalse())
+
+		cluster.Spec.ExternalClusters[0] = ExternalCluster{
+			Name: "origin",
+			PVC:  &PVCBackupConfiguration{ClaimName: "backups"},
+		}
+		externalCluster, found := cluster.GetRecoveryPVC()
+		Expect(found).To(BeTrue())
+		Expect(externalCluster.PVC.ClaimName).To(Equal("backups"))
+		Expect(externalCluster.GetServerName()).To(Equal("origin"))
+	})
+})
+
+var _ = Describe("Cluster GetWALTuningSettings", func() {
+	newCluster := func(profile WALTuningProfile, storageSize string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{WALTuningProfile: profile},
+				StorageConfiguration:  StorageConfiguration{Size: storageSize},
+			},
+		}
+	}
+
+	It("doesn't set anything without a profile", func() {
+		Expect(newCluster("", "100Gi").GetWALTuningSettings()).To(BeNil())
+		Expect(newCluster(WALTuningProfileNone, "100Gi").GetWALTuningSettings()).To(BeNil())
+	})
+
+	It("uses the preset values when the volume is big enough", func() {
+		Expect(newCluster(WALTuningProfileMedium, "100Gi").GetWALTuningSettings()).To(Equal(map[string]string{
+			"max_wal_size":                 "8192MB",
+			"min_wal_size":                 "1024MB",
+			"checkpoint_timeout":           "15min",
+			"checkpoint_completion_target": "0.9",
+		}))
+	})
+
+	It("caps the WAL size to the volume containing the WAL files", func() {
+		cluster := newCluster(WALTuningProfileHigh, "100Gi")
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "4Gi"}
+		settings := cluster.GetWALTuningSettings()
+		Expect(settings).To(HaveKeyWithValue("max_wal_size", "2048MB"))
+		Expect(settings).To(HaveKeyWithValue("min_wal_size", "512MB"))
+		Expect(settings).To(HaveKeyWithValue("checkpoint_timeout", "30min"))
+	})
+
+	It("never goes below the minimum WAL size accepted by PostgreSQL", func() {
+		settings := newCluster(WALTuningProfileLow, "64Mi").GetWALTuningSettings()
+		Expect(settings).To(HaveKeyWithValue("max_wal_size", "64MB"))
+		Expect(settings).To(HaveKeyWithValue("min_wal_size", "32MB"))
+	})
+})
+
+var _ = Describe("Cluster wraparound protection", func() {
+	It("uses the default warning age and no emergency vacuum when not configured", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetWraparoundWarningAge()).To(BeEquivalentTo(DefaultWraparoundWarningAge))
+		_, enabled := cluster.GetWraparoundEmergencyVacuumAge()
+		Expect(enabled).To(BeFalse())
+	})
+
+	It("uses the configured thresholds", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WraparoundProtection: &WraparoundProtectionConfiguration{
+					WarningAge:         ptr.To(int32(800000000)),
+					EmergencyVacuumAge: ptr.To(int32(1200000000)),
+				},
+			},
+		}
+		Expect(cluster.GetWraparoundWarningAge()).To(BeEquivalentTo(800000000))
+		age, enabled := cluster.GetWraparoundEmergencyVacuumAge()
+		Expect(enabled).To(BeTrue())
+		Expect(age).To(BeEquivalentTo(1200000000))
+	})
+})
+
+var _ = Describe("Cluster GetStaleTimelinePolicy", func() {
+	It("waits for a manual intervention by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetStaleTimelinePolicy()).To(Equal(StaleTimelinePolicyManual))
+	})
+
+	It("uses the configured policy", func() {
+		cluster := &Cluster{Spec: ClusterSpec{StaleTimelinePolicy: StaleTimelinePolicyRewind}}
+		Expect(cluster.GetStaleTimelinePolicy()).To(Equal(StaleTimelinePolicyRewind))
+	})
+})
@@ -17,12 +17,16 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,9 +38,11 @@ import (
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/admissionhook"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -68,6 +74,7 @@ var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&clusterCustomValidator{}).
 		Complete()
 }
 
@@ -275,14 +282,62 @@ func (r *Cluster) defaultPgBaseBackup() {
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vcluster.cnpg.io,sideEffects=None
 
-var _ webhook.Validator = &Cluster{}
+// clusterCustomValidator validates the clusters, passing the context of
+// the admission request down to the admission hook
+type clusterCustomValidator struct{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
+var _ webhook.CustomValidator = &clusterCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *clusterCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", obj))
+	}
+
+	return cluster.validateCreate(ctx)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *clusterCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	cluster, ok := newObj.(*Cluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", newObj))
+	}
+	oldCluster, ok := oldObj.(*Cluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", oldObj))
+	}
+
+	return cluster.validateUpdate(ctx, oldCluster)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *clusterCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", obj))
+	}
+
+	clusterLog.Info("validate delete", "name", cluster.Name)
+
+	// TODO(user): fill in your validation logic upon object deletion.
+	return nil, nil
+}
+
+// validateCreate validates a new cluster
+func (r *Cluster) validateCreate(ctx context.Context) (admission.Warnings, error) {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	allErrs := r.Validate()
+	allErrs := append(
+		r.Validate(),
+		r.validateMemoryLimit()...,
+	)
 	if len(allErrs) == 0 {
-		return nil, nil
+		warnings, err := r.validateWithAdmissionHook(ctx, nil)
+		return append(r.getMemoryUsageWarnings(), warnings...), err
 	}
 
 	return nil, apierrors.NewInvalid(
@@ -290,6 +345,22 @@ func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 		r.Name, allErrs)
 }
 
+// validateWithAdmissionHook sends the cluster to the admission hook
+// defined in the operator configuration, if any
+func (r *Cluster) validateWithAdmissionHook(ctx context.Context, old *Cluster) (admission.Warnings, error) {
+	var oldObject client.Object
+	if old != nil {
+		oldObject = old
+	}
+
+	request, err := admissionhook.NewRequest(GroupVersion.WithKind(ClusterKind), "clusters", r, oldObject)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	return admissionhook.Validate(ctx, request)
+}
+
 // Validate groups the validation logic for clusters returning a list of all encountered errors
 func (r *Cluster) Validate() (allErrs field.ErrorList) {
 	type validationFunc func() field.ErrorList
@@ -298,6 +369,7 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateRecoveryApplicationDatabase,
 		r.validatePgBaseBackupApplicationDatabase,
 		r.validateImport,
+		r.validateImportFromDump,
 		r.validateSuperuserSecret,
 		r.validateCerts,
 		r.validateBootstrapMethod,
@@ -306,6 +378,7 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
 		r.validateMinSyncReplicas,
+		r.validateSynchronousReplicaConfiguration,
 		r.validateMaxSyncReplicas,
 		r.validateStorageSize,
 		r.validateWalStorageSize,
@@ -321,13 +394,29 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
+		r.validateBackupPlugin,
+		r.validateWalCommands,
+		r.validateBackupPVC,
 		r.validateConfiguration,
+		r.validateConfigurationDriftPolicy,
 		r.validateLDAP,
 		r.validateReplicationSlots,
 		r.validateEnv,
 		r.validateManagedRoles,
+		r.validateManagedServices,
+		r.validateMaintenanceWindows,
+		r.validateImageUpgradeSafety,
+		r.validateReplicationLagSLO,
+		r.validateMaxDataLossOnFailover,
+		r.validateConnectionStormProtection,
 		r.validateManagedExtensions,
 		r.validateResources,
+		r.validateLifecycleHooks,
+		r.validateDNS,
+		r.validateHostNetwork,
+		r.validateStorageClassUpdatePolicy,
+		r.validateInitContainers,
+		r.validateRestorePointAnnotation,
 	}
 
 	for _, validate := range validations {
@@ -337,10 +426,9 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 	return allErrs
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+// validateUpdate validates the changes of a cluster
+func (r *Cluster) validateUpdate(ctx context.Context, oldCluster *Cluster) (admission.Warnings, error) {
 	clusterLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
-	oldCluster := old.(*Cluster)
 
 	// applying defaults before validating updates to set any new default
 	oldCluster.SetDefaults()
@@ -351,7 +439,8 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 	)
 
 	if len(allErrs) == 0 {
-		return nil, nil
+		warnings, err := r.validateWithAdmissionHook(ctx, oldCluster)
+		return append(r.getMemoryUsageWarnings(), warnings...), err
 	}
 
 	return nil, apierrors.NewInvalid(
@@ -377,6 +466,7 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 		r.validateReplicaModeChange,
 		r.validateUnixPermissionIdentifierChange,
 		r.validateReplicationSlotsChange,
+		r.validateMemoryLimitChange,
 	}
 	for _, validate := range validations {
 		allErrs = append(allErrs, validate(old)...)
@@ -385,14 +475,6 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 	return allErrs
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *Cluster) ValidateDelete() (admission.Warnings, error) {
-	clusterLog.Info("validate delete", "name", r.Name)
-
-	// TODO(user): fill in your validation logic upon object deletion.
-	return nil, nil
-}
-
 // validateLDAP validates the ldap postgres configuration
 func (r *Cluster) validateLDAP() field.ErrorList {
 	// No validating if not specified
@@ -489,6 +571,8 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
+	result = append(result, r.validateInitDBLocale()...)
+
 	if initDBOptions.PostInitApplicationSQLRefs != nil {
 		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
 			if item.Name == "" || item.Key == "" {
@@ -516,6 +600,83 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 	return result
 }
 
+// validateInitDBLocale validates the locale provider options of initdb,
+// which depend on the provider and on the major version of PostgreSQL
+func (r *Cluster) validateInitDBLocale() field.ErrorList {
+	var result field.ErrorList
+
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	initDBPath := field.NewPath("spec", "bootstrap", "initdb")
+
+	providerOptions := []struct {
+		name     string
+		value    string
+		provider string
+	}{
+		{name: "icuLocale", value: initDBOptions.IcuLocale, provider: "icu"},
+		{name: "icuRules", value: initDBOptions.IcuRules, provider: "icu"},
+		{name: "builtinLocale", value: initDBOptions.BuiltinLocale, provider: "builtin"},
+	}
+	for _, option := range providerOptions {
+		if option.value != "" && initDBOptions.LocaleProvider != option.provider {
+			result = append(
+				result,
+				field.Invalid(
+					initDBPath.Child(option.name),
+					option.value,
+					fmt.Sprintf("%s requires the %s locale provider", option.name, option.provider)))
+		}
+	}
+
+	if initDBOptions.LocaleProvider == "icu" && initDBOptions.IcuLocale == "" {
+		result = append(
+			result,
+			field.Required(
+				initDBPath.Child("icuLocale"),
+				"the icu locale provider requires an ICU locale"))
+	}
+
+	if initDBOptions.LocaleProvider == "builtin" && initDBOptions.BuiltinLocale == "" {
+		result = append(
+			result,
+			field.Required(
+				initDBPath.Child("builtinLocale"),
+				"the builtin locale provider requires a builtin locale"))
+	}
+
+	pgVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	minProviderVersion := 150000
+	if initDBOptions.LocaleProvider == "builtin" {
+		minProviderVersion = 170000
+	}
+	if initDBOptions.LocaleProvider != "" && pgVersion < minProviderVersion {
+		result = append(
+			result,
+			field.Invalid(
+				initDBPath.Child("localeProvider"),
+				initDBOptions.LocaleProvider,
+				fmt.Sprintf("the %s locale provider requires PostgreSQL %d or later",
+					initDBOptions.LocaleProvider, minProviderVersion/10000)))
+	}
+
+	if initDBOptions.IcuRules != "" && pgVersion < 160000 {
+		result = append(
+			result,
+			field.Invalid(
+				initDBPath.Child("icuRules"),
+				initDBOptions.IcuRules,
+				"ICU rules require PostgreSQL 16 or later"))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateImport() field.ErrorList {
 	// If it's not configured, everything is ok
 	if r.Spec.Bootstrap == nil {
@@ -628,6 +789,70 @@ func (s Import) validateMonolith() field.ErrorList {
 	return result
 }
 
+// validateImportFromDump validates the configuration of the
+// logical dump to be restored into the application database
+func (r *Cluster) validateImportFromDump() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil ||
+		r.Spec.Bootstrap.InitDB.ImportFromDump == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	importFromDump := r.Spec.Bootstrap.InitDB.ImportFromDump
+	path := field.NewPath("spec", "bootstrap", "initdb", "importFromDump")
+
+	if r.Spec.Bootstrap.InitDB.Import != nil {
+		result = append(result, field.Invalid(
+			path,
+			"",
+			"importFromDump and import are mutually exclusive"))
+	}
+
+	location := importFromDump.Location
+	locationPath := path.Child("location")
+	switch {
+	case location.PersistentVolumeClaim == nil && location.URLSecret == nil:
+		result = append(result, field.Required(
+			locationPath,
+			"one of persistentVolumeClaim and urlSecret is required"))
+	case location.PersistentVolumeClaim != nil && location.URLSecret != nil:
+		result = append(result, field.Invalid(
+			locationPath.Child("urlSecret"),
+			location.URLSecret.Name,
+			"persistentVolumeClaim and urlSecret are mutually exclusive"))
+	case location.PersistentVolumeClaim != nil:
+		if !filepath.IsLocal(location.PersistentVolumeClaim.Path) {
+			result = append(result, field.Invalid(
+				locationPath.Child("persistentVolumeClaim", "path"),
+				location.PersistentVolumeClaim.Path,
+				"the path must be relative to the root of the volume"))
+		}
+	case importFromDump.GetFormat() == DumpFormatDirectory:
+		result = append(result, field.Invalid(
+			path.Child("format"),
+			importFromDump.Format,
+			"the directory format can't be downloaded from a URL"))
+	}
+
+	if len(importFromDump.OwnerMapping) > 0 && !importFromDump.PreserveOwnership {
+		result = append(result, field.Invalid(
+			path.Child("ownerMapping"),
+			importFromDump.OwnerMapping,
+			"ownerMapping requires preserveOwnership to be true"))
+	}
+	for source, target := range importFromDump.OwnerMapping {
+		if source == "" || target == "" {
+			result = append(result, field.Invalid(
+				path.Child("ownerMapping"),
+				importFromDump.OwnerMapping,
+				"the roles of the mapping can't be empty"))
+			break
+		}
+	}
+
+	return result
+}
+
 // validateRecovery validate the bootstrapping options when Recovery
 // method is used
 func (r *Cluster) validateRecoveryApplicationDatabase() field.ErrorList {
@@ -700,7 +925,27 @@ func (r *Cluster) validateCerts() field.ErrorList {
 		return result
 	}
 
-	if certificates.ServerTLSSecret != "" {
+	if certificates.ServerIssuerRef != nil && certificates.ServerTLSSecret == "" {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "certificates", "serverTLSSecret"),
+				"",
+				"Server TLS secret can't be empty when the server issuer is provided"))
+	}
+
+	if certificates.ReplicationIssuerRef != nil && certificates.ReplicationTLSSecret == "" {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "certificates", "replicationTLSSecret"),
+				"",
+				"Client replication secret can't be empty when the replication issuer is provided"))
+	}
+
+	// When an issuer is provided the operator creates the certificate, and
+	// cert-manager stores the CA in the same secret
+	if certificates.ServerTLSSecret != "" && certificates.ServerIssuerRef == nil {
 		// Currently names are not validated, maybe add this check in future
 		if len(certificates.ServerAltDNSNames) != 0 {
 			result = append(
@@ -723,7 +968,8 @@ func (r *Cluster) validateCerts() field.ErrorList {
 	}
 
 	// If you provide the ReplicationTLSSecret we must provide the ClientCaSecret
-	if certificates.ReplicationTLSSecret != "" && certificates.ClientCASecret == "" {
+	if certificates.ReplicationTLSSecret != "" && certificates.ReplicationIssuerRef == nil &&
+		certificates.ClientCASecret == "" {
 		result = append(
 			result,
 			field.Invalid(
@@ -954,6 +1200,109 @@ func (r *Cluster) validateImageName() field.ErrorList {
 	return result
 }
 
+// getSharedMemoryEstimate returns the memory limit of the cluster and the
+// estimated shared memory of PostgreSQL, or false when the cluster has no
+// memory limit or the estimate can't be computed
+func (r *Cluster) getSharedMemoryEstimate() (*resource.Quantity, int64, bool) {
+	memoryLimit := r.Spec.Resources.Limits.Memory()
+	if memoryLimit.IsZero() {
+		return nil, 0, false
+	}
+
+	// An unknown major version is reported elsewhere
+	majorVersion, _ := r.GetPostgresqlMajorVersion()
+	estimate, err := postgres.EstimateMemoryUsage(r.Spec.PostgresConfiguration.Parameters, majorVersion)
+	if err != nil {
+		// The parameters are validated elsewhere
+		return nil, 0, false
+	}
+
+	return memoryLimit, estimate.SharedMemory, true
+}
+
+// newSharedMemoryError builds the error reporting that the memory limit is
+// lower than the shared memory of PostgreSQL
+func newSharedMemoryError(memoryLimit *resource.Quantity, sharedMemory int64) *field.Error {
+	return field.Invalid(
+		field.NewPath("spec", "resources", "limits", "memory"),
+		memoryLimit.String(),
+		fmt.Sprintf("Memory limit is lower than the shared memory of PostgreSQL (%s), "+
+			"made of `shared_buffers` and `wal_buffers`",
+			resource.NewQuantity(sharedMemory, resource.BinarySI).String()),
+	)
+}
+
+// validateMemoryLimit rejects the configurations whose shared memory doesn't
+// fit in the memory limit, as the instance is guaranteed to be OOM killed
+// once the shared buffers are filled
+func (r *Cluster) validateMemoryLimit() field.ErrorList {
+	memoryLimit, sharedMemory, ok := r.getSharedMemoryEstimate()
+	if !ok || sharedMemory < memoryLimit.Value() {
+		return nil
+	}
+
+	return field.ErrorList{newSharedMemoryError(memoryLimit, sharedMemory)}
+}
+
+// validateMemoryLimitChange rejects the updates whose shared memory doesn't
+// fit in the memory limit only when they increase the shared memory or
+// decrease the memory limit, so that a cluster which was already exceeding
+// it, for example because it was created with a previous version of the
+// operator, can still be updated. Such a cluster gets a warning instead
+func (r *Cluster) validateMemoryLimitChange(old *Cluster) field.ErrorList {
+	memoryLimit, sharedMemory, ok := r.getSharedMemoryEstimate()
+	if !ok || sharedMemory < memoryLimit.Value() {
+		return nil
+	}
+
+	oldMemoryLimit, oldSharedMemory, oldOk := old.getSharedMemoryEstimate()
+	if oldOk && oldSharedMemory >= oldMemoryLimit.Value() &&
+		sharedMemory <= oldSharedMemory && memoryLimit.Cmp(*oldMemoryLimit) >= 0 {
+		return nil
+	}
+
+	return field.ErrorList{newSharedMemoryError(memoryLimit, sharedMemory)}
+}
+
+// getMemoryUsageWarnings warns about the configurations whose estimated
+// memory usage exceeds the memory limit, as they are likely to be OOM
+// killed under load
+func (r *Cluster) getMemoryUsageWarnings() admission.Warnings {
+	memoryLimit := r.Spec.Resources.Limits.Memory()
+	if memoryLimit.IsZero() {
+		return nil
+	}
+
+	// An unknown major version is reported elsewhere
+	majorVersion, _ := r.GetPostgresqlMajorVersion()
+	estimate, err := postgres.EstimateMemoryUsage(r.Spec.PostgresConfiguration.Parameters, majorVersion)
+	if err != nil {
+		return nil
+	}
+
+	// The updates of the clusters whose shared memory was already
+	// exceeding the memory limit are allowed, and only warned about
+	if estimate.SharedMemory >= memoryLimit.Value() {
+		return admission.Warnings{
+			fmt.Sprintf("The shared memory of PostgreSQL (%s), made of `shared_buffers` and `wal_buffers`, "+
+				"exceeds the memory limit (%s): consider reducing `shared_buffers`",
+				resource.NewQuantity(estimate.SharedMemory, resource.BinarySI).String(),
+				memoryLimit.String()),
+		}
+	}
+
+	if estimate.Total() <= memoryLimit.Value() {
+		return nil
+	}
+
+	return admission.Warnings{
+		fmt.Sprintf("The estimated memory usage of PostgreSQL (%s) exceeds the memory limit (%s): "+
+			"consider reducing `max_connections`, `work_mem` or `shared_buffers`",
+			resource.NewQuantity(estimate.Total(), resource.BinarySI).String(),
+			memoryLimit.String()),
+	}
+}
+
 // validateImagePullPolicy validates the image pull policy,
 // ensuring it is one of "Always", "Never" or "IfNotPresent" when defined
 func (r *Cluster) validateImagePullPolicy() field.ErrorList {
@@ -1031,6 +1380,184 @@ func (r *Cluster) validateResources() field.ErrorList {
 	return result
 }
 
+// validateDNS validates the DNS policy and configuration of the Pods
+func (r *Cluster) validateDNS() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.DNSPolicy == v1.DNSNone &&
+		(r.Spec.DNSConfig == nil || len(r.Spec.DNSConfig.Nameservers) == 0) {
+		result = append(result, field.Required(
+			field.NewPath("spec", "dnsConfig", "nameservers"),
+			"at least one name server is required when the DNS policy is None"))
+	}
+
+	return result
+}
+
+// validateHostNetwork validates the configuration of the instances using
+// the host network. Every instance reserves the same ports on its node,
+// so two instances of the cluster must never be scheduled together
+func (r *Cluster) validateHostNetwork() field.ErrorList {
+	if !r.Spec.HostNetwork {
+		return nil
+	}
+
+	var result field.ErrorList
+	affinity := r.Spec.Affinity
+
+	if affinity.EnablePodAntiAffinity != nil && !*affinity.EnablePodAntiAffinity {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "affinity", "enablePodAntiAffinity"),
+			*affinity.EnablePodAntiAffinity,
+			"pod anti-affinity is required when using the host network"))
+	}
+
+	if affinity.PodAntiAffinityType != PodAntiAffinityTypeRequired {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "affinity", "podAntiAffinityType"),
+			affinity.PodAntiAffinityType,
+			fmt.Sprintf("pod anti-affinity type must be '%s' when using the host network",
+				PodAntiAffinityTypeRequired)))
+	}
+
+	if affinity.TopologyKey != "" && affinity.TopologyKey != "kubernetes.io/hostname" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "affinity", "topologyKey"),
+			affinity.TopologyKey,
+			"topology key must be 'kubernetes.io/hostname' when using the host network"))
+	}
+
+	if r.Spec.DNSPolicy == v1.DNSClusterFirst {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "dnsPolicy"),
+			r.Spec.DNSPolicy,
+			fmt.Sprintf("DNS policy must be '%s' to resolve the cluster services when using the host network",
+				v1.DNSClusterFirstWithHostNet)))
+	}
+
+	return result
+}
+
+// validateStorageClassUpdatePolicy checks that the storage class can be
+// migrated without stopping the cluster, switching over to a replica
+func (r *Cluster) validateStorageClassUpdatePolicy() field.ErrorList {
+	if r.Spec.StorageClassUpdatePolicy != StorageClassUpdatePolicyMigrate || r.Spec.Instances > 1 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "storageClassUpdatePolicy"),
+			r.Spec.StorageClassUpdatePolicy,
+			"the storage class can only be migrated in clusters having at least two instances"),
+	}
+}
+
+// validateRestorePointAnnotation ensures that the name of the requested
+// restore point can be used by PostgreSQL
+func (r *Cluster) validateRestorePointAnnotation() field.ErrorList {
+	// PostgreSQL refuses the names of restore points longer than
+	// MAXFNAMELEN - 1 characters
+	const maxRestorePointNameLength = 63
+
+	name, ok := r.Annotations[utils.RestorePointAnnotationName]
+	if !ok || (len(name) > 0 && len(name) <= maxRestorePointNameLength) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("metadata", "annotations", utils.RestorePointAnnotationName),
+			name,
+			fmt.Sprintf("the name of the restore point must be between 1 and %d characters long",
+				maxRestorePointNameLength)),
+	}
+}
+
+// validateInitContainers ensures that the additional init containers have
+// unique names, not clashing with the one installing the instance manager
+func (r *Cluster) validateInitContainers() field.ErrorList {
+	// This is the name of the init container installing the instance manager
+	const bootstrapControllerContainerName = "bootstrap-controller"
+
+	var result field.ErrorList
+	names := stringset.From([]string{bootstrapControllerContainerName})
+	for idx, container := range r.Spec.InitContainers {
+		if names.Has(container.Name) {
+			result = append(result, field.Duplicate(
+				field.NewPath("spec", "initContainers").Index(idx).Child("name"),
+				container.Name))
+		}
+		names.Put(container.Name)
+	}
+
+	return result
+}
+
+// validateLifecycleHooks validates the hooks invoked at the lifecycle events
+func (r *Cluster) validateLifecycleHooks() field.ErrorList {
+	hooks := r.Spec.LifecycleHooks
+	if hooks == nil {
+		return nil
+	}
+
+	basePath := field.NewPath("spec", "lifecycleHooks")
+
+	var result field.ErrorList
+	result = append(result, validateLifecycleHookList(basePath.Child("prePromote"), hooks.PrePromote)...)
+	result = append(result, validateLifecycleHookList(basePath.Child("postPromote"), hooks.PostPromote)...)
+	result = append(result, validateLifecycleHookList(basePath.Child("preShutdown"), hooks.PreShutdown)...)
+	result = append(result, validateLifecycleHookList(basePath.Child("postBootstrap"), hooks.PostBootstrap)...)
+	result = append(result, validateLifecycleHookList(basePath.Child("preBackup"), hooks.PreBackup)...)
+	result = append(result, validateLifecycleHookList(basePath.Child("preSwitchover"), hooks.PreSwitchover)...)
+
+	// The preSwitchover hooks are invoked by the operator,
+	// which can't execute commands in the PostgreSQL container
+	for idx, hook := range hooks.PreSwitchover {
+		if hook.Exec != nil {
+			result = append(result, field.Forbidden(
+				basePath.Child("preSwitchover").Index(idx).Child("exec"),
+				"only http hooks are supported before a switchover"))
+		}
+	}
+	return result
+}
+
+func validateLifecycleHookList(path *field.Path, hooks []LifecycleHook) field.ErrorList {
+	var result field.ErrorList
+
+	names := stringset.New()
+	for idx, hook := range hooks {
+		hookPath := path.Index(idx)
+
+		if names.Has(hook.Name) {
+			result = append(result, field.Duplicate(hookPath.Child("name"), hook.Name))
+		}
+		names.Put(hook.Name)
+
+		switch {
+		case hook.HTTP == nil && hook.Exec == nil:
+			result = append(result, field.Required(
+				hookPath,
+				"one between http and exec is required"))
+		case hook.HTTP != nil && hook.Exec != nil:
+			result = append(result, field.Forbidden(
+				hookPath,
+				"only one between http and exec can be specified"))
+		case hook.HTTP != nil:
+			hookURL, err := url.Parse(hook.HTTP.URL)
+			if err != nil || hookURL.Host == "" || (hookURL.Scheme != "http" && hookURL.Scheme != "https") {
+				result = append(result, field.Invalid(
+					hookPath.Child("http", "url"),
+					hook.HTTP.URL,
+					"must be an absolute http or https URL"))
+			}
+		}
+	}
+
+	return result
+}
+
 // validateConfiguration determines whether a PostgreSQL configuration is valid
 func (r *Cluster) validateConfiguration() field.ErrorList {
 	var result field.ErrorList
@@ -1278,7 +1805,7 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 				field.NewPath("spec", "imageName"),
 				r.Spec.ImageName,
 				fmt.Sprintf("wrong version: %v", err.Error())))
-	} else if !status {
+	} else if !status && !r.canChangeMajorVersion(oldVersion, newVersion) {
 		result = append(
 			result,
 			field.Invalid(
@@ -1291,6 +1818,29 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 	return result
 }
 
+// canChangeMajorVersion checks if the data directory can be upgraded from
+// the major version of PostgreSQL of the old image to the one of the new
+// image with pg_upgrade. Replica clusters follow the major version of their
+// source, and major version downgrades are possible only to go back to the
+// major version of the data directory before the upgrade is completed
+func (r *Cluster) canChangeMajorVersion(oldImage, newImage string) bool {
+	oldMajorVersion, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(oldImage))
+	if err != nil {
+		return false
+	}
+
+	newMajorVersion, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(newImage))
+	if err != nil {
+		return false
+	}
+
+	if r.Status.PGDataImageInfo != nil && newMajorVersion == r.Status.PGDataImageInfo.MajorVersion {
+		return true
+	}
+
+	return !r.IsReplica() && newMajorVersion > oldMajorVersion
+}
+
 // Validate the recovery target to ensure that the mutual exclusivity
 // of options is respected and plus validating the format of targetTime
 // if specified
@@ -1460,6 +2010,40 @@ func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
 	return result
 }
 
+// Validate the synchronous replication configuration
+func (r *Cluster) validateSynchronousReplicaConfiguration() field.ErrorList {
+	synchronous := r.Spec.PostgresConfiguration.Synchronous
+	if synchronous == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "synchronous")
+
+	if r.Spec.MinSyncReplicas > 0 || r.Spec.MaxSyncReplicas > 0 {
+		result = append(result, field.Invalid(
+			path,
+			synchronous,
+			"synchronous cannot be used together with minSyncReplicas and maxSyncReplicas"))
+	}
+
+	standbyNamesFromCluster := r.Spec.Instances - 1
+	if maxNames := synchronous.MaxStandbyNamesFromCluster; maxNames != nil && *maxNames < standbyNamesFromCluster {
+		standbyNamesFromCluster = *maxNames
+	}
+
+	standbyNames := len(synchronous.StandbyNamesPre) + standbyNamesFromCluster + len(synchronous.StandbyNamesPost)
+	if synchronous.Number > standbyNames {
+		result = append(result, field.Invalid(
+			path.Child("number"),
+			synchronous.Number,
+			fmt.Sprintf("the number of synchronous standbys cannot be greater than "+
+				"the number of standby names (%d)", standbyNames)))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateStorageSize() field.ErrorList {
 	return validateStorageConfigurationSize(*field.NewPath("spec", "storage"), r.Spec.StorageConfiguration)
 }
@@ -1719,12 +2303,25 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
 	var result field.ErrorList
 
-	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
+	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil &&
+		externalCluster.PVC == nil {
 		result = append(result,
 			field.Invalid(
 				path,
 				externalCluster,
-				"one of connectionParameters and barmanObjectStore is required"))
+				"one of connectionParameters, barmanObjectStore and pvc is required"))
+	}
+
+	if externalCluster.BarmanObjectStore != nil && externalCluster.PVC != nil {
+		result = append(result,
+			field.Invalid(
+				path.Child("pvc"),
+				externalCluster.PVC,
+				"barmanObjectStore and pvc can't be used together"))
+	}
+
+	if externalCluster.PVC != nil {
+		result = append(result, validatePVCBackupConfiguration(externalCluster.PVC, path.Child("pvc"))...)
 	}
 
 	return result
@@ -1733,6 +2330,14 @@ func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path
 // Check replica mode is enabled only at cluster creation time
 func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
+	// a permanent replica cluster needs to be turned into a regular one before being promoted
+	if old.IsPermanentReplica() && !r.IsReplica() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "replica", "enabled"),
+			r.Spec.ReplicaCluster,
+			"Can not promote a permanent replica cluster, set permanent to false first"))
+	}
+
 	// if we are not specifying any replica cluster configuration or disabling it, nothing to do
 	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
 		return result
@@ -1799,6 +2404,15 @@ func (r *Cluster) validateReplicaMode() field.ErrorList {
 				fmt.Sprintf("External cluster %v not found", r.Spec.ReplicaCluster.Source)))
 	}
 
+	if r.Spec.ReplicaCluster.Permanent && !r.Spec.ReplicaCluster.Enabled {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "replica", "permanent"),
+				r.Spec.ReplicaCluster.Permanent,
+				"a permanent replica cluster requires the replica mode to be enabled"))
+	}
+
 	return result
 }
 
@@ -1906,6 +2520,147 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 	return allErrors
 }
 
+// validateBackupPlugin validates the configuration of the backup plugin
+func (r *Cluster) validateBackupPlugin() field.ErrorList {
+	plugin := r.GetBackupPlugin()
+	if plugin == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	pluginPath := field.NewPath("spec", "backup", "plugin")
+
+	if errs := validationutil.IsDNS1123Label(plugin.Name); len(errs) > 0 {
+		result = append(result, field.Invalid(
+			pluginPath.Child("name"),
+			plugin.Name,
+			strings.Join(errs, ", ")))
+	}
+
+	if plugin.Name == BarmanCloudPluginName {
+		if r.Spec.Backup.BarmanObjectStore == nil {
+			result = append(result, field.Required(
+				field.NewPath("spec", "backup", "barmanObjectStore"),
+				"the barman-cloud backup plugin requires the barmanObjectStore configuration"))
+		}
+	} else {
+		if plugin.Image == "" {
+			result = append(result, field.Required(
+				pluginPath.Child("image"),
+				"an image is required for backup plugins other than barman-cloud"))
+		}
+
+		// The plugin would take over the WAL archiving and the backups,
+		// silently ignoring the Barman object store
+		if r.Spec.Backup.BarmanObjectStore != nil {
+			result = append(result, field.Invalid(
+				pluginPath,
+				plugin.Name,
+				"backup plugins other than barman-cloud can't be used together with barmanObjectStore"))
+		}
+	}
+
+	return result
+}
+
+// validateWalCommands validates the commands archiving and restoring
+// the WAL files
+func (r *Cluster) validateWalCommands() field.ErrorList {
+	walCommands := r.GetWalCommands()
+	if walCommands == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	walCommandsPath := field.NewPath("spec", "backup", "walCommands")
+
+	if r.Spec.Backup.BarmanObjectStore != nil || r.Spec.Backup.Plugin != nil {
+		result = append(result, field.Invalid(
+			walCommandsPath,
+			walCommands,
+			"the WAL commands can't be used together with barmanObjectStore or with a backup plugin"))
+	}
+
+	if !strings.Contains(walCommands.ArchiveCommand, "%p") {
+		result = append(result, field.Invalid(
+			walCommandsPath.Child("archiveCommand"),
+			walCommands.ArchiveCommand,
+			"the archive command must reference the path of the WAL file with %p"))
+	}
+
+	if walCommands.RestoreCommand != "" &&
+		(!strings.Contains(walCommands.RestoreCommand, "%f") || !strings.Contains(walCommands.RestoreCommand, "%p")) {
+		result = append(result, field.Invalid(
+			walCommandsPath.Child("restoreCommand"),
+			walCommands.RestoreCommand,
+			"the restore command must reference the name of the WAL file with %f and its destination with %p"))
+	}
+
+	return result
+}
+
+// validateBackupPVC validates the PersistentVolumeClaim storing the
+// backups and the WAL archive
+func (r *Cluster) validateBackupPVC() field.ErrorList {
+	configuration := r.GetBackupPVC()
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	pvcPath := field.NewPath("spec", "backup", "pvc")
+
+	if r.Spec.Backup.BarmanObjectStore != nil || r.Spec.Backup.Plugin != nil || r.Spec.Backup.WalCommands != nil {
+		result = append(result, field.Invalid(
+			pvcPath,
+			configuration,
+			"the pvc backup target can't be used together with barmanObjectStore, a backup plugin or WAL commands"))
+	}
+
+	return append(result, validatePVCBackupConfiguration(configuration, pvcPath)...)
+}
+
+// validatePVCBackupConfiguration validates the names used to locate the
+// files of a server in a PersistentVolumeClaim
+func validatePVCBackupConfiguration(configuration *PVCBackupConfiguration, path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if errs := validationutil.IsDNS1123Subdomain(configuration.ClaimName); len(errs) > 0 {
+		result = append(result, field.Invalid(
+			path.Child("claimName"),
+			configuration.ClaimName,
+			strings.Join(errs, ", ")))
+	}
+
+	// The server name is used as the name of a directory
+	if configuration.ServerName != "" {
+		if errs := validationutil.IsDNS1123Subdomain(configuration.ServerName); len(errs) > 0 {
+			result = append(result, field.Invalid(
+				path.Child("serverName"),
+				configuration.ServerName,
+				strings.Join(errs, ", ")))
+		}
+	}
+
+	return result
+}
+
+// validateConfigurationDriftPolicy checks that the parameters changed with
+// ALTER SYSTEM are reverted only when ALTER SYSTEM is disabled
+func (r *Cluster) validateConfigurationDriftPolicy() field.ErrorList {
+	if r.GetConfigurationDriftPolicy() != ConfigurationDriftPolicyRevert ||
+		!r.Spec.PostgresConfiguration.EnableAlterSystem {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "configurationDriftPolicy"),
+			r.Spec.PostgresConfiguration.ConfigurationDriftPolicy,
+			"the parameters changed with ALTER SYSTEM can't be reverted when enableAlterSystem is true"),
+	}
+}
+
 // validateBackupConfiguration validates the backup configuration
 func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -1958,9 +2713,65 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		}
 	}
 
+	if data := r.Spec.Backup.BarmanObjectStore.Data; data != nil &&
+		data.BandwidthLimit != nil && data.BandwidthLimit.Sign() <= 0 {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "bandwidthLimit"),
+			data.BandwidthLimit.String(),
+			"the bandwidth limit must be positive",
+		))
+	}
+
+	if wal := r.Spec.Backup.BarmanObjectStore.Wal; wal != nil {
+		allErrors = append(allErrors, validateCompression(
+			field.NewPath("spec", "backup", "barmanObjectStore", "wal", "compression"),
+			wal.Compression)...)
+	}
+
+	if data := r.Spec.Backup.BarmanObjectStore.Data; data != nil {
+		allErrors = append(allErrors, validateCompression(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "compression"),
+			data.Compression)...)
+	}
+
+	if wal := r.Spec.Backup.BarmanObjectStore.Wal; wal != nil &&
+		wal.KMSKeyID != "" && wal.Encryption != EncryptionTypeNoneAWSKMS {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "wal", "kmsKeyID"),
+			wal.KMSKeyID,
+			"a KMS key ID requires the aws:kms encryption",
+		))
+	}
+
+	if data := r.Spec.Backup.BarmanObjectStore.Data; data != nil &&
+		data.KMSKeyID != "" && data.Encryption != EncryptionTypeNoneAWSKMS {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "kmsKeyID"),
+			data.KMSKeyID,
+			"a KMS key ID requires the aws:kms encryption",
+		))
+	}
+
 	return allErrors
 }
 
+// validateCompression checks that the compression algorithm is one of
+// the ones supported by barman-cloud
+func validateCompression(path *field.Path, compression CompressionType) field.ErrorList {
+	switch compression {
+	case CompressionTypeNone, CompressionTypeGzip, CompressionTypeBzip2, CompressionTypeSnappy:
+		return nil
+	default:
+		return field.ErrorList{
+			field.NotSupported(path, compression, []string{
+				string(CompressionTypeGzip),
+				string(CompressionTypeBzip2),
+				string(CompressionTypeSnappy),
+			}),
+		}
+	}
+}
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	if r.Spec.ReplicationSlots == nil {
 		r.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
@@ -2189,6 +3000,170 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 	return result
 }
 
+// validateManagedServices validate the managed services configuration set by the user
+func (r *Cluster) validateManagedServices() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil ||
+		r.Spec.Managed.Services == nil ||
+		r.Spec.Managed.Services.InstanceServices == nil {
+		return nil
+	}
+
+	instanceServices := r.Spec.Managed.Services.InstanceServices
+	if instanceServices.ExternalDomain == "" {
+		return nil
+	}
+
+	for _, msg := range validationutil.IsDNS1123Subdomain(instanceServices.ExternalDomain) {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "managed", "services", "instanceServices", "externalDomain"),
+				instanceServices.ExternalDomain,
+				msg))
+	}
+
+	return result
+}
+
+// validateMaintenanceWindows validates the maintenance windows of the cluster
+func (r *Cluster) validateMaintenanceWindows() field.ErrorList {
+	var result field.ErrorList
+
+	for idx, window := range r.Spec.MaintenanceWindows {
+		windowPath := field.NewPath("spec", "maintenanceWindows").Index(idx)
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			result = append(
+				result,
+				field.Invalid(
+					windowPath.Child("schedule"),
+					window.Schedule,
+					err.Error()))
+		}
+
+		if window.Duration.Duration <= 0 {
+			result = append(
+				result,
+				field.Invalid(
+					windowPath.Child("duration"),
+					window.Duration.String(),
+					"the duration of a maintenance window must be positive"))
+		}
+	}
+
+	return result
+}
+
+// validateImageUpgradeSafety validates the safety checks of the image upgrades
+func (r *Cluster) validateImageUpgradeSafety() field.ErrorList {
+	var result field.ErrorList
+
+	safety := r.Spec.ImageUpgradeSafety
+	if safety == nil || safety.MaxBackupAge == nil {
+		return result
+	}
+
+	maxBackupAgePath := field.NewPath("spec", "imageUpgradeSafety", "maxBackupAge")
+	if safety.MaxBackupAge.Duration <= 0 {
+		result = append(
+			result,
+			field.Invalid(
+				maxBackupAgePath,
+				safety.MaxBackupAge.String(),
+				"the maximum age of the last backup must be positive"))
+	}
+
+	if r.Spec.Backup == nil {
+		result = append(
+			result,
+			field.Invalid(
+				maxBackupAgePath,
+				safety.MaxBackupAge.String(),
+				"the maximum age of the last backup requires the backup to be configured"))
+	}
+
+	return result
+}
+
+// validateReplicationLagSLO validates the replication lag thresholds of the cluster
+func (r *Cluster) validateReplicationLagSLO() field.ErrorList {
+	var result field.ErrorList
+
+	slo := r.Spec.ReplicationLagSLO
+	if slo == nil {
+		return result
+	}
+
+	sloPath := field.NewPath("spec", "replicationLagSLO")
+	if slo.MaxLagSize != nil && slo.MaxLagSize.Sign() <= 0 {
+		result = append(
+			result,
+			field.Invalid(
+				sloPath.Child("maxLagSize"),
+				slo.MaxLagSize.String(),
+				"the maximum replication lag size must be positive"))
+	}
+
+	if slo.MaxLagTime != nil && slo.MaxLagTime.Duration <= 0 {
+		result = append(
+			result,
+			field.Invalid(
+				sloPath.Child("maxLagTime"),
+				slo.MaxLagTime.String(),
+				"the maximum replication lag time must be positive"))
+	}
+
+	return result
+}
+
+// validateMaxDataLossOnFailover validates the maximum data loss allowed on failover
+func (r *Cluster) validateMaxDataLossOnFailover() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.MaxDataLossOnFailover != nil && r.Spec.MaxDataLossOnFailover.Sign() < 0 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "maxDataLossOnFailover"),
+				r.Spec.MaxDataLossOnFailover.String(),
+				"the maximum data loss on failover cannot be negative"))
+	}
+
+	return result
+}
+
+// validateConnectionStormProtection validates the connection storm protection configuration
+func (r *Cluster) validateConnectionStormProtection() field.ErrorList {
+	var result field.ErrorList
+
+	config := r.Spec.PostgresConfiguration.ConnectionStormProtection
+	if config == nil {
+		return result
+	}
+
+	configPath := field.NewPath("spec", "postgresql", "connectionStormProtection")
+	if config.Threshold <= 0 {
+		result = append(
+			result,
+			field.Invalid(
+				configPath.Child("threshold"),
+				config.Threshold,
+				"the connection threshold must be positive"))
+	}
+
+	if config.Duration != nil && config.Duration.Duration < 0 {
+		result = append(
+			result,
+			field.Invalid(
+				configPath.Child("duration"),
+				config.Duration.String(),
+				"the duration of a connection storm cannot be negative"))
+	}
+
+	return result
+}
+
 // validateManagedExtensions validate the managed extensions parameters set by the user
 func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	allErrors := field.ErrorList{}
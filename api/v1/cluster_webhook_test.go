@@ -17,16 +17,20 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -186,6 +190,43 @@ var _ = Describe("azure credentials", func() {
 	})
 })
 
+var _ = Describe("Google credentials", func() {
+	path := field.NewPath("spec", "backupConfiguration", "googleCredentials")
+	applicationCredentials := &SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{
+			Name: "gcs-config",
+		},
+		Key: "gcsCredentials",
+	}
+
+	It("is correct when the application credentials are given", func() {
+		googleCredentials := GoogleCredentials{
+			ApplicationCredentials: applicationCredentials,
+		}
+		Expect(googleCredentials.validateGCSCredentials(path)).To(BeEmpty())
+	})
+
+	It("is correct when relying on the GKE workload identity", func() {
+		googleCredentials := GoogleCredentials{
+			GKEEnvironment: true,
+		}
+		Expect(googleCredentials.validateGCSCredentials(path)).To(BeEmpty())
+	})
+
+	It("requires the application credentials outside GKE", func() {
+		googleCredentials := GoogleCredentials{}
+		Expect(googleCredentials.validateGCSCredentials(path)).To(HaveLen(1))
+	})
+
+	It("doesn't allow the application credentials with the GKE workload identity", func() {
+		googleCredentials := GoogleCredentials{
+			GKEEnvironment:         true,
+			ApplicationCredentials: applicationCredentials,
+		}
+		Expect(googleCredentials.validateGCSCredentials(path)).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("certificates options validation", func() {
 	It("doesn't complain if there isn't a configuration", func() {
 		emptyCluster := &Cluster{}
@@ -228,6 +269,33 @@ var _ = Describe("certificates options validation", func() {
 		result := cluster.validateCerts()
 		Expect(result).To(HaveLen(1))
 	})
+	It("doesn't require the CA secrets when the certificates come from cert-manager issuers", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerTLSSecret:      "test-server-tls",
+					ServerAltDNSNames:    []string{"dns-name"},
+					ServerIssuerRef:      &CertManagerIssuerReference{Name: "issuer"},
+					ReplicationTLSSecret: "test-replication-tls",
+					ReplicationIssuerRef: &CertManagerIssuerReference{Name: "issuer"},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(BeEmpty())
+	})
+	It("complains if you specify the cert-manager issuers without the TLS secrets", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerIssuerRef:      &CertManagerIssuerReference{Name: "issuer"},
+					ReplicationIssuerRef: &CertManagerIssuerReference{Name: "issuer"},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(HaveLen(2))
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -435,6 +503,66 @@ var _ = Describe("initdb options validation", func() {
 		result := cluster.validateSuperuserSecret()
 		Expect(result).To(HaveLen(1))
 	})
+
+	It("doesn't complain about a valid ICU locale configuration", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16.1",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+	})
+
+	It("complains about the locale options not matching the locale provider", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:17.0",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "builtin",
+						IcuLocale:      "en-US",
+					},
+				},
+			},
+		}
+
+		// icuLocale without the icu provider, and a missing builtinLocale
+		Expect(cluster.validateInitDB()).To(HaveLen(2))
+	})
+
+	It("complains about the locale providers not supported by the PostgreSQL version", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.5",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+
+		cluster.Spec.ImageName = "postgres:14.10"
+		Expect(cluster.validateInitDB()).To(HaveLen(2))
+
+		cluster.Spec.ImageName = "postgres:16.1"
+		cluster.Spec.Bootstrap.InitDB = &BootstrapInitDB{
+			LocaleProvider: "builtin",
+			BuiltinLocale:  "C.UTF-8",
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("cluster configuration", func() {
@@ -1107,6 +1235,58 @@ var _ = Describe("validate image name change", func() {
 		Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
 	})
 
+	It("doesn't complain when upgrading to a newer major version", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.5",
+			},
+		}
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16.1",
+			},
+		}
+		Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+	})
+
+	It("complains when upgrading the major version of a replica cluster", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.5",
+			},
+		}
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16.1",
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "origin",
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+	})
+
+	It("doesn't complain when going back to the major version of the data directory", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16.1",
+			},
+		}
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.5",
+			},
+			Status: ClusterStatus{
+				PGDataImageInfo: &ImageInfo{
+					Image:        "postgres:15.5",
+					MajorVersion: 15,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+	})
+
 	It("doesn't complain if image change it's valid", func() {
 		clusterOld := Cluster{
 			Spec: ClusterSpec{
@@ -1499,6 +1679,63 @@ var _ = Describe("Number of synchronous replicas", func() {
 	})
 })
 
+var _ = Describe("synchronous replication configuration", func() {
+	It("is valid when not set", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
+	It("accepts a number of synchronous standbys covered by the standby names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method:          SynchronousReplicaConfigurationMethodAny,
+						Number:          3,
+						StandbyNamesPre: []string{"other-cluster"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
+	It("complains when there are not enough standby names", func() {
+		maxNames := 1
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method:                     SynchronousReplicaConfigurationMethodFirst,
+						Number:                     2,
+						MaxStandbyNamesFromCluster: &maxNames,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(HaveLen(1))
+	})
+
+	It("complains when used together with minSyncReplicas and maxSyncReplicas", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 2,
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method: SynchronousReplicaConfigurationMethodAny,
+						Number: 1,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("storage configuration validation", func() {
 	It("complains if the size is being reduced", func() {
 		clusterOld := Cluster{
@@ -1695,6 +1932,27 @@ var _ = Describe("validation of an external cluster", func() {
 		cluster.Spec.ExternalClusters[0].ConnectionParameters = nil
 		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
 		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		cluster.Spec.ExternalClusters[0].PVC = &PVCBackupConfiguration{ClaimName: "backups"}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains if barmanObjectStore and pvc are both set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:              "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+						PVC:               &PVCBackupConfiguration{ClaimName: "backups"},
+					},
+				},
+			},
+		}
+		result := cluster.validateExternalClusters()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.externalClusters[0].pvc"))
 	})
 })
 
@@ -2245,6 +2503,58 @@ var _ = Describe("replica mode validation", func() {
 		Expect(cluster.validateReplicaMode()).To(BeEmpty())
 		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
 	})
+
+	It("complains when a permanent replica cluster has the replica mode disabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled:   false,
+					Permanent: true,
+					Source:    "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(HaveLen(1))
+	})
+
+	It("complains when a permanent replica cluster is promoted", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled:   true,
+					Permanent: true,
+					Source:    "test",
+				},
+			},
+		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.ReplicaCluster.Enabled = false
+		cluster.Spec.ReplicaCluster.Permanent = false
+		Expect(cluster.validateReplicaModeChange(oldCluster)).To(HaveLen(1))
+
+		cluster.Spec.ReplicaCluster = nil
+		Expect(cluster.validateReplicaModeChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("allows the promotion of a replica cluster that is no more permanent", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+			},
+		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.ReplicaCluster.Enabled = false
+		Expect(cluster.validateReplicaModeChange(oldCluster)).To(BeEmpty())
+	})
 })
 
 var _ = Describe("Validation changes", func() {
@@ -2302,6 +2612,101 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(err).To(HaveLen(2))
 	})
+
+	It("complain if the bandwidth limit is not positive", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Data: &DataBackupConfiguration{
+							BandwidthLimit: ptr.To(resource.MustParse("0")),
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(2))
+	})
+
+	It("complain if the compression algorithm is not supported", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Wal: &WalBackupConfiguration{
+							Compression: "zstd",
+						},
+						Data: &DataBackupConfiguration{
+							Compression: "lz4",
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(3))
+	})
+
+	It("accepts the supported compression algorithms", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Wal: &WalBackupConfiguration{
+							Compression: CompressionTypeSnappy,
+						},
+						Data: &DataBackupConfiguration{
+							Compression: CompressionTypeGzip,
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+	})
+
+	It("complain if a KMS key ID is used without the aws:kms encryption", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Wal: &WalBackupConfiguration{
+							Encryption: EncryptionTypeAES256,
+							KMSKeyID:   "my-key",
+						},
+						Data: &DataBackupConfiguration{
+							KMSKeyID: "my-key",
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(3))
+	})
+
+	It("accepts a KMS key ID with the aws:kms encryption", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Wal: &WalBackupConfiguration{
+							Encryption: EncryptionTypeNoneAWSKMS,
+							KMSKeyID:   "my-key",
+						},
+						Data: &DataBackupConfiguration{
+							Encryption: EncryptionTypeNoneAWSKMS,
+							KMSKeyID:   "my-key",
+						},
+					},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("Default monitoring queries", func() {
@@ -2604,14 +3009,138 @@ var _ = Describe("validation of imports", func() {
 	})
 })
 
-var _ = Describe("validation of replication slots configuration", func() {
-	It("prevents using replication slots on PostgreSQL 10 and older", func() {
-		cluster := &Cluster{
+var _ = Describe("validation of imports from a dump", func() {
+	newCluster := func(importFromDump *ImportFromDump) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: ptr.To(true),
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:       "app",
+						Owner:          "app",
+						ImportFromDump: importFromDump,
+					},
+				},
+			},
+		}
+	}
+
+	It("accepts a dump stored in a PVC", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app/app.dump"},
+			},
+		})
+		Expect(cluster.validateImportFromDump()).To(BeEmpty())
+	})
+
+	It("accepts a dump downloaded from a URL", func() {
+		cluster := newCluster(&ImportFromDump{
+			Format: DumpFormatPlain,
+			Location: DumpLocation{
+				URLSecret: &SecretKeySelector{LocalObjectReference: LocalObjectReference{Name: "dump"}, Key: "url"},
+			},
+		})
+		Expect(cluster.validateImportFromDump()).To(BeEmpty())
+	})
+
+	It("complains if no location is set", func() {
+		result := newCluster(&ImportFromDump{}).validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.location"))
+	})
+
+	It("complains if both the locations are set", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+				URLSecret:             &SecretKeySelector{LocalObjectReference: LocalObjectReference{Name: "dump"}},
+			},
+		})
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.location.urlSecret"))
+	})
+
+	It("complains if the path escapes the volume", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "../app.dump"},
+			},
+		})
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.location.persistentVolumeClaim.path"))
+	})
+
+	It("complains if a dump in the directory format is downloaded from a URL", func() {
+		cluster := newCluster(&ImportFromDump{
+			Format: DumpFormatDirectory,
+			Location: DumpLocation{
+				URLSecret: &SecretKeySelector{LocalObjectReference: LocalObjectReference{Name: "dump"}},
+			},
+		})
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.format"))
+	})
+
+	It("accepts an owner mapping when the ownership is preserved", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+			},
+			PreserveOwnership: true,
+			OwnerMapping:      map[string]string{"legacy": "app"},
+		})
+		Expect(cluster.validateImportFromDump()).To(BeEmpty())
+	})
+
+	It("complains if an owner mapping is set without preserving the ownership", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+			},
+			OwnerMapping: map[string]string{"legacy": "app"},
+		})
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.ownerMapping"))
+	})
+
+	It("complains if a role of the owner mapping is empty", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+			},
+			PreserveOwnership: true,
+			OwnerMapping:      map[string]string{"legacy": ""},
+		})
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump.ownerMapping"))
+	})
+
+	It("complains if it's used together with import", func() {
+		cluster := newCluster(&ImportFromDump{
+			Location: DumpLocation{
+				PersistentVolumeClaim: &DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+			},
+		})
+		cluster.Spec.Bootstrap.InitDB.Import = &Import{Type: MicroserviceSnapshotType}
+		result := cluster.validateImportFromDump()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.importFromDump"))
+	})
+})
+
+var _ = Describe("validation of replication slots configuration", func() {
+	It("prevents using replication slots on PostgreSQL 10 and older", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(true),
 					},
 					UpdateInterval: 0,
 				},
@@ -2956,6 +3485,551 @@ var _ = Describe("Role management validation", func() {
 	})
 })
 
+var _ = Describe("Managed services validation", func() {
+	It("should succeed if there is no services stanza", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("should succeed with a valid external domain", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						InstanceServices: &InstanceServicesConfiguration{
+							Enabled:        true,
+							ExternalDomain: "eu-west.db.example.com",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).To(BeEmpty())
+	})
+
+	It("should complain about an invalid external domain", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Services: &ManagedServices{
+						InstanceServices: &InstanceServicesConfiguration{
+							Enabled:        true,
+							ExternalDomain: "-Invalid_Domain",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedServices()).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Maintenance windows validation", func() {
+	It("should succeed if there are no maintenance windows", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateMaintenanceWindows()).To(BeEmpty())
+	})
+
+	It("should succeed with a valid maintenance window", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows: []MaintenanceWindow{
+					{Schedule: "0 0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindows()).To(BeEmpty())
+	})
+
+	It("should complain about an invalid schedule and a missing duration", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindows: []MaintenanceWindow{
+					{Schedule: "every saturday"},
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindows()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Image upgrade safety validation", func() {
+	It("should succeed if there are no image upgrade safety checks", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateImageUpgradeSafety()).To(BeEmpty())
+	})
+
+	It("should succeed with a valid maximum backup age", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+				ImageUpgradeSafety: &ImageUpgradeSafetyConfiguration{
+					CreateRestorePoint: true,
+					MaxBackupAge:       &metav1.Duration{Duration: 24 * time.Hour},
+				},
+			},
+		}
+		Expect(cluster.validateImageUpgradeSafety()).To(BeEmpty())
+	})
+
+	It("should complain about a non positive maximum backup age without backups", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageUpgradeSafety: &ImageUpgradeSafetyConfiguration{
+					MaxBackupAge: &metav1.Duration{},
+				},
+			},
+		}
+		Expect(cluster.validateImageUpgradeSafety()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Replication lag SLO validation", func() {
+	It("should succeed if there is no replication lag SLO", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateReplicationLagSLO()).To(BeEmpty())
+	})
+
+	It("should succeed with valid thresholds", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicationLagSLO: &ReplicationLagSLO{
+					MaxLagSize: ptr.To(resource.MustParse("1Gi")),
+					MaxLagTime: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		}
+		Expect(cluster.validateReplicationLagSLO()).To(BeEmpty())
+	})
+
+	It("should complain about non positive thresholds", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicationLagSLO: &ReplicationLagSLO{
+					MaxLagSize: ptr.To(resource.MustParse("0")),
+					MaxLagTime: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+		}
+		Expect(cluster.validateReplicationLagSLO()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Maximum data loss on failover validation", func() {
+	It("should succeed if there is no limit", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateMaxDataLossOnFailover()).To(BeEmpty())
+	})
+
+	It("should succeed with a zero or positive limit", func() {
+		for _, limit := range []string{"0", "16Mi"} {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					MaxDataLossOnFailover: ptr.To(resource.MustParse(limit)),
+				},
+			}
+			Expect(cluster.validateMaxDataLossOnFailover()).To(BeEmpty())
+		}
+	})
+
+	It("should complain about a negative limit", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaxDataLossOnFailover: ptr.To(resource.MustParse("-1Mi")),
+			},
+		}
+		Expect(cluster.validateMaxDataLossOnFailover()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("WAL commands validation", func() {
+	It("should succeed if no WAL command is configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateWalCommands()).To(BeEmpty())
+	})
+
+	It("should succeed with valid archive and restore commands", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					WalCommands: &WalCommandsConfiguration{
+						ArchiveCommand: "tape-archive put %p",
+						RestoreCommand: "tape-archive get %f %p",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateWalCommands()).To(BeEmpty())
+	})
+
+	It("complains if the commands don't reference the WAL file", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					WalCommands: &WalCommandsConfiguration{
+						ArchiveCommand: "tape-archive put",
+						RestoreCommand: "tape-archive get %f",
+					},
+				},
+			},
+		}
+		result := cluster.validateWalCommands()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.backup.walCommands.archiveCommand"))
+		Expect(result[1].Field).To(Equal("spec.backup.walCommands.restoreCommand"))
+	})
+
+	It("complains if used together with barmanObjectStore", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					WalCommands: &WalCommandsConfiguration{
+						ArchiveCommand: "tape-archive put %p",
+					},
+				},
+			},
+		}
+		result := cluster.validateWalCommands()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.walCommands"))
+	})
+})
+
+var _ = Describe("Backup PVC validation", func() {
+	It("should succeed if no PVC is configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateBackupPVC()).To(BeEmpty())
+	})
+
+	It("should accept a valid claim and server name", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					PVC: &PVCBackupConfiguration{ClaimName: "backups", ServerName: "cluster-example"},
+				},
+			},
+		}
+		Expect(cluster.validateBackupPVC()).To(BeEmpty())
+	})
+
+	It("complains about a server name that isn't a valid directory name", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					PVC: &PVCBackupConfiguration{ClaimName: "backups", ServerName: "../other"},
+				},
+			},
+		}
+		result := cluster.validateBackupPVC()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.pvc.serverName"))
+	})
+
+	It("complains if used together with another WAL archive", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					PVC:               &PVCBackupConfiguration{ClaimName: "backups"},
+				},
+			},
+		}
+		result := cluster.validateBackupPVC()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.pvc"))
+	})
+})
+
+var _ = Describe("Backup plugin validation", func() {
+	It("should succeed if no plugin is configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateBackupPlugin()).To(BeEmpty())
+	})
+
+	It("should accept the barman-cloud plugin with an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					Plugin:            &BackupPluginConfiguration{Name: BarmanCloudPluginName},
+				},
+			},
+		}
+		Expect(cluster.validateBackupPlugin()).To(BeEmpty())
+	})
+
+	It("should complain about the barman-cloud plugin without an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					Plugin: &BackupPluginConfiguration{Name: BarmanCloudPluginName},
+				},
+			},
+		}
+		Expect(cluster.validateBackupPlugin()).To(HaveLen(1))
+	})
+
+	It("should require an image for third-party plugins", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					Plugin: &BackupPluginConfiguration{Name: "vendor-backup"},
+				},
+			},
+		}
+		Expect(cluster.validateBackupPlugin()).To(HaveLen(1))
+
+		cluster.Spec.Backup.Plugin.Image = "registry.example.com/vendor-backup:1.0"
+		Expect(cluster.validateBackupPlugin()).To(BeEmpty())
+	})
+
+	It("should complain about third-party plugins used together with an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+					Plugin: &BackupPluginConfiguration{
+						Name:  "vendor-backup",
+						Image: "registry.example.com/vendor-backup:1.0",
+					},
+				},
+			},
+		}
+		result := cluster.validateBackupPlugin()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.plugin"))
+	})
+
+	It("should complain about a plugin name that is not a DNS label", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					Plugin: &BackupPluginConfiguration{
+						Name:  "Vendor/Backup",
+						Image: "registry.example.com/vendor-backup:1.0",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupPlugin()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("DNS validation", func() {
+	It("should succeed with the default DNS policy", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateDNS()).To(BeEmpty())
+	})
+
+	It("should require a name server with the None DNS policy", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				DNSPolicy: corev1.DNSNone,
+				DNSConfig: &corev1.PodDNSConfig{
+					Searches: []string{"example.com"},
+				},
+			},
+		}
+		Expect(cluster.validateDNS()).To(HaveLen(1))
+
+		cluster.Spec.DNSConfig.Nameservers = []string{"10.0.0.10"}
+		Expect(cluster.validateDNS()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Host network validation", func() {
+	It("should succeed when the host network is not used", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateHostNetwork()).To(BeEmpty())
+	})
+
+	It("should require the required pod anti-affinity on the node", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				HostNetwork: true,
+			},
+		}
+		Expect(cluster.validateHostNetwork()).To(HaveLen(1))
+
+		cluster.Spec.Affinity.PodAntiAffinityType = PodAntiAffinityTypeRequired
+		Expect(cluster.validateHostNetwork()).To(BeEmpty())
+
+		cluster.Spec.Affinity.TopologyKey = "topology.kubernetes.io/zone"
+		Expect(cluster.validateHostNetwork()).To(HaveLen(1))
+
+		cluster.Spec.Affinity.TopologyKey = "kubernetes.io/hostname"
+		cluster.Spec.Affinity.EnablePodAntiAffinity = ptr.To(false)
+		Expect(cluster.validateHostNetwork()).To(HaveLen(1))
+	})
+
+	It("should reject the ClusterFirst DNS policy", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				HostNetwork: true,
+				DNSPolicy:   corev1.DNSClusterFirst,
+				Affinity: AffinityConfiguration{
+					PodAntiAffinityType: PodAntiAffinityTypeRequired,
+				},
+			},
+		}
+		Expect(cluster.validateHostNetwork()).To(HaveLen(1))
+
+		cluster.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		Expect(cluster.validateHostNetwork()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Storage class update policy validation", func() {
+	It("should accept the default policy with a single instance", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1}}
+		Expect(cluster.validateStorageClassUpdatePolicy()).To(BeEmpty())
+	})
+
+	It("should require at least two instances to migrate the storage class", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:                1,
+				StorageClassUpdatePolicy: StorageClassUpdatePolicyMigrate,
+			},
+		}
+		Expect(cluster.validateStorageClassUpdatePolicy()).To(HaveLen(1))
+
+		cluster.Spec.Instances = 2
+		Expect(cluster.validateStorageClassUpdatePolicy()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Lifecycle hooks validation", func() {
+	It("should succeed if no hook is configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateLifecycleHooks()).To(BeEmpty())
+	})
+
+	It("should accept HTTP and exec hooks", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PrePromote: []LifecycleHook{
+						{Name: "dns", HTTP: &HTTPLifecycleHook{URL: "https://dns.example.com/promote"}},
+					},
+					PreShutdown: []LifecycleHook{
+						{Name: "drain", Exec: &ExecLifecycleHook{Command: []string{"/bin/drain"}}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLifecycleHooks()).To(BeEmpty())
+	})
+
+	It("should require exactly one between http and exec", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PostPromote: []LifecycleHook{
+						{Name: "none"},
+						{
+							Name: "both",
+							HTTP: &HTTPLifecycleHook{URL: "https://example.com"},
+							Exec: &ExecLifecycleHook{Command: []string{"/bin/true"}},
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateLifecycleHooks()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.lifecycleHooks.postPromote[0]"))
+		Expect(result[1].Field).To(Equal("spec.lifecycleHooks.postPromote[1]"))
+	})
+
+	It("should complain about invalid URLs", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PreBackup: []LifecycleHook{
+						{Name: "relative", HTTP: &HTTPLifecycleHook{URL: "/hook"}},
+						{Name: "ftp", HTTP: &HTTPLifecycleHook{URL: "ftp://example.com/hook"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateLifecycleHooks()).To(HaveLen(2))
+	})
+
+	It("should only accept HTTP hooks before a switchover", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PreSwitchover: []LifecycleHook{
+						{Name: "drain", HTTP: &HTTPLifecycleHook{URL: "https://app.example.com/drain"}},
+						{Name: "script", Exec: &ExecLifecycleHook{Command: []string{"/bin/drain"}}},
+					},
+				},
+			},
+		}
+		result := cluster.validateLifecycleHooks()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.lifecycleHooks.preSwitchover[1].exec"))
+		Expect(cluster.IsSwitchoverOrchestrated()).To(BeTrue())
+	})
+
+	It("should complain about duplicate names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				LifecycleHooks: &LifecycleHooksConfiguration{
+					PostBootstrap: []LifecycleHook{
+						{Name: "notify", Exec: &ExecLifecycleHook{Command: []string{"/bin/true"}}},
+						{Name: "notify", Exec: &ExecLifecycleHook{Command: []string{"/bin/false"}}},
+					},
+				},
+			},
+		}
+		result := cluster.validateLifecycleHooks()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.lifecycleHooks.postBootstrap[1].name"))
+	})
+})
+
+var _ = Describe("Connection storm protection validation", func() {
+	It("should succeed if the protection is not configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateConnectionStormProtection()).To(BeEmpty())
+	})
+
+	It("should succeed with a valid configuration", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConnectionStormProtection: &ConnectionStormProtectionConfiguration{
+						Threshold:  200,
+						Duration:   &metav1.Duration{Duration: time.Minute},
+						Mitigation: ConnectionStormMitigationReject,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConnectionStormProtection()).To(BeEmpty())
+	})
+
+	It("should complain about an invalid threshold and duration", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConnectionStormProtection: &ConnectionStormProtectionConfiguration{
+						Duration: &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConnectionStormProtection()).To(HaveLen(2))
+	})
+})
+
 var _ = Describe("Managed Extensions validation", func() {
 	It("should succeed if no extension is enabled", func() {
 		cluster := Cluster{
@@ -3688,3 +4762,208 @@ var _ = Describe("Tablespaces validation", func() {
 		Expect(cluster.validateTablespaceBackupSnapshot()).To(HaveLen(1))
 	})
 })
+
+var _ = Describe("Init containers validation", func() {
+	It("accepts init containers with unique names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InitContainers: []corev1.Container{
+					{Name: "warm-up"},
+					{Name: "tune-filesystem"},
+				},
+			},
+		}
+		Expect(cluster.validateInitContainers()).To(BeEmpty())
+	})
+
+	It("complains about duplicated names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InitContainers: []corev1.Container{
+					{Name: "warm-up"},
+					{Name: "warm-up"},
+				},
+			},
+		}
+		Expect(cluster.validateInitContainers()).To(HaveLen(1))
+	})
+
+	It("complains about the name of the init container installing the instance manager", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InitContainers: []corev1.Container{
+					{Name: "bootstrap-controller"},
+				},
+			},
+		}
+		Expect(cluster.validateInitContainers()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validate the restore point annotation", func() {
+	It("accepts a cluster without the annotation", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateRestorePointAnnotation()).To(BeEmpty())
+	})
+
+	It("accepts a valid restore point name", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.RestorePointAnnotationName: "pre-deploy-v1.2.3"},
+			},
+		}
+		Expect(cluster.validateRestorePointAnnotation()).To(BeEmpty())
+	})
+
+	It("complains about empty or too long restore point names", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.RestorePointAnnotationName: ""},
+			},
+		}
+		Expect(cluster.validateRestorePointAnnotation()).To(HaveLen(1))
+
+		cluster.Annotations[utils.RestorePointAnnotationName] = strings.Repeat("a", 64)
+		Expect(cluster.validateRestorePointAnnotation()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("configuration drift policy validation", func() {
+	It("accepts reverting the parameters changed with ALTER SYSTEM", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConfigurationDriftPolicy: ConfigurationDriftPolicyRevert,
+				},
+			},
+		}
+		Expect(cluster.validateConfigurationDriftPolicy()).To(BeEmpty())
+	})
+
+	It("accepts reporting the drift when ALTER SYSTEM is enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					EnableAlterSystem: true,
+				},
+			},
+		}
+		Expect(cluster.validateConfigurationDriftPolicy()).To(BeEmpty())
+	})
+
+	It("complains when reverting the drift while ALTER SYSTEM is enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					EnableAlterSystem:        true,
+					ConfigurationDriftPolicy: ConfigurationDriftPolicyRevert,
+				},
+			},
+		}
+		result := cluster.validateConfigurationDriftPolicy()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.configurationDriftPolicy"))
+	})
+})
+
+var _ = Describe("memory usage validation", func() {
+	newCluster := func(memoryLimit string, parameters map[string]string) Cluster {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: parameters,
+				},
+			},
+		}
+		if memoryLimit != "" {
+			cluster.Spec.Resources.Limits = corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(memoryLimit),
+			}
+		}
+		return cluster
+	}
+
+	It("doesn't check the clusters without a memory limit", func() {
+		cluster := newCluster("", map[string]string{"shared_buffers": "64GB"})
+		Expect(cluster.validateMemoryLimit()).To(BeEmpty())
+		Expect(cluster.getMemoryUsageWarnings()).To(BeEmpty())
+	})
+
+	It("accepts the configurations fitting in the memory limit", func() {
+		cluster := newCluster("2Gi", map[string]string{"shared_buffers": "512MB"})
+		Expect(cluster.validateMemoryLimit()).To(BeEmpty())
+		Expect(cluster.getMemoryUsageWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the estimated memory usage exceeds the memory limit", func() {
+		cluster := newCluster("2Gi", map[string]string{
+			"shared_buffers":  "512MB",
+			"max_connections": "500",
+			"work_mem":        "16MB",
+		})
+		Expect(cluster.validateMemoryLimit()).To(BeEmpty())
+		Expect(cluster.getMemoryUsageWarnings()).To(HaveLen(1))
+	})
+
+	It("rejects the configurations whose shared memory exceeds the memory limit", func() {
+		cluster := newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		result := cluster.validateMemoryLimit()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.resources.limits.memory"))
+	})
+
+	It("only warns when updating a cluster whose shared memory was already exceeding the memory limit", func() {
+		oldCluster := newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		cluster := newCluster("1Gi", map[string]string{"shared_buffers": "1GB", "work_mem": "8MB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(BeEmpty())
+		Expect(cluster.getMemoryUsageWarnings()).To(ConsistOf(ContainSubstring("shared memory")))
+
+		cluster = newCluster("1Gi", map[string]string{"shared_buffers": "900MB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(BeEmpty())
+	})
+
+	It("rejects the updates increasing the shared memory beyond the memory limit", func() {
+		oldCluster := newCluster("1Gi", map[string]string{"shared_buffers": "512MB"})
+		cluster := newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(HaveLen(1))
+
+		oldCluster = newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		cluster = newCluster("1Gi", map[string]string{"shared_buffers": "2GB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(HaveLen(1))
+	})
+
+	It("rejects the updates decreasing the memory limit below the shared memory", func() {
+		oldCluster := newCluster("2Gi", map[string]string{"shared_buffers": "1GB"})
+		cluster := newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(HaveLen(1))
+
+		oldCluster = newCluster("1Gi", map[string]string{"shared_buffers": "1GB"})
+		cluster = newCluster("512Mi", map[string]string{"shared_buffers": "1GB"})
+		Expect(cluster.validateMemoryLimitChange(&oldCluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("cluster custom validator", func() {
+	validator := &clusterCustomValidator{}
+
+	It("refuses objects that are not clusters", func() {
+		_, err := validator.ValidateCreate(context.Background(), &Backup{})
+		Expect(apierrors.IsBadRequest(err)).To(BeTrue())
+
+		_, err = validator.ValidateUpdate(context.Background(), &Cluster{}, &Backup{})
+		Expect(apierrors.IsBadRequest(err)).To(BeTrue())
+
+		_, err = validator.ValidateUpdate(context.Background(), &Backup{}, &Cluster{})
+		Expect(apierrors.IsBadRequest(err)).To(BeTrue())
+	})
+
+	It("refuses invalid clusters", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:invalid tag",
+			},
+		}
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+})
@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1
 
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
 // VolumeSnapshotKind this is a strongly typed reference to the kind used by the volumesnapshot package
 const VolumeSnapshotKind = "VolumeSnapshot"
 
@@ -38,3 +42,62 @@ type Metadata struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
+
+// ResourcesUpdatePolicy defines how the changes to the Secrets and ConfigMaps
+// used by a workload are applied to its pods
+type ResourcesUpdatePolicy string
+
+const (
+	// ResourcesUpdatePolicyReload means that the changes are applied by
+	// reloading the configuration, without restarting the pods (`reload`)
+	ResourcesUpdatePolicyReload ResourcesUpdatePolicy = "reload"
+
+	// ResourcesUpdatePolicyRestart means that the pods are restarted with
+	// a rolling update to apply the changes (`restart`)
+	ResourcesUpdatePolicyRestart ResourcesUpdatePolicy = "restart"
+)
+
+// SessionTimeouts are the default timeouts applied to the sessions, using
+// the PostgreSQL syntax for time values, such as `30s` or `5min`.
+// A value without unit is expressed in milliseconds, and `0` disables
+// the timeout
+type SessionTimeouts struct {
+	// The default `idle_in_transaction_session_timeout`, terminating the
+	// sessions which are idle in an open transaction for longer than that
+	// +kubebuilder:validation:Pattern=`^[0-9]+(us|ms|s|min|h|d)?$`
+	// +optional
+	IdleInTransactionSessionTimeout string `json:"idleInTransactionSessionTimeout,omitempty"`
+
+	// The default `statement_timeout`, aborting the statements running
+	// for longer than that
+	// +kubebuilder:validation:Pattern=`^[0-9]+(us|ms|s|min|h|d)?$`
+	// +optional
+	StatementTimeout string `json:"statementTimeout,omitempty"`
+
+	// The default `lock_timeout`, aborting the statements waiting for
+	// a lock for longer than that
+	// +kubebuilder:validation:Pattern=`^[0-9]+(us|ms|s|min|h|d)?$`
+	// +optional
+	LockTimeout string `json:"lockTimeout,omitempty"`
+}
+
+// ToParameters returns the timeouts which are set, indexed by the name
+// of the corresponding PostgreSQL parameter
+func (timeouts *SessionTimeouts) ToParameters() map[string]string {
+	if timeouts == nil {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for name, value := range map[string]string{
+		postgres.IdleInTransactionSessionTimeoutParameter: timeouts.IdleInTransactionSessionTimeout,
+		postgres.StatementTimeoutParameter:                timeouts.StatementTimeout,
+		postgres.LockTimeoutParameter:                     timeouts.LockTimeout,
+	} {
+		if value != "" {
+			result[name] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("session timeouts", func() {
+	It("returns no parameters when the timeouts are not defined", func() {
+		var timeouts *SessionTimeouts
+		Expect(timeouts.ToParameters()).To(BeNil())
+	})
+
+	It("returns only the timeouts which are set", func() {
+		timeouts := &SessionTimeouts{
+			IdleInTransactionSessionTimeout: "10min",
+			LockTimeout:                     "0",
+		}
+		Expect(timeouts.ToParameters()).To(Equal(map[string]string{
+			"idle_in_transaction_session_timeout": "10min",
+			"lock_timeout":                        "0",
+		}))
+	})
+})
@@ -0,0 +1,209 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseReclaimPolicy describes a policy for end-of-life maintenance of databases.
+// +enum
+type DatabaseReclaimPolicy string
+
+const (
+	// DatabaseReclaimDelete means the database will be deleted from its PostgreSQL cluster on release
+	// from its claim.
+	DatabaseReclaimDelete DatabaseReclaimPolicy = "delete"
+
+	// DatabaseReclaimRetain means the database will be left in its current phase for manual
+	// reclamation by the administrator. The default policy is Retain.
+	DatabaseReclaimRetain DatabaseReclaimPolicy = "retain"
+)
+
+// DatabaseSpec is the specification of a Postgresql Database
+type DatabaseSpec struct {
+	// The corresponding cluster
+	ClusterRef LocalObjectReference `json:"cluster"`
+
+	// Ensure the PostgreSQL database is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The name of the database inside PostgreSQL
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'postgres'",message="the name postgres is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template0'",message="the name template0 is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template1'",message="the name template1 is reserved"
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The owner of the database
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// The name of the template from which to create the new database
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="template is immutable"
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// The encoding of the database, such as `UTF8`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="encoding is immutable"
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The locale of the database, used both as `LC_COLLATE` and `LC_CTYPE`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="locale is immutable"
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// True when the database is a template
+	// +optional
+	IsTemplate *bool `json:"isTemplate,omitempty"`
+
+	// True when connections to this database are allowed
+	// +optional
+	AllowConnections *bool `json:"allowConnections,omitempty"`
+
+	// How many concurrent connections can be made to this database.
+	// `-1` (the default) means no limit.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int `json:"connectionLimit,omitempty"`
+
+	// The policy for end-of-life maintenance of this database:
+	// `delete` drops the database from PostgreSQL when this object
+	// is deleted, `retain` (the default) leaves it in place
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy DatabaseReclaimPolicy `json:"databaseReclaimPolicy,omitempty"`
+
+	// The list of extensions to be managed in the database
+	// +optional
+	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+
+	// The default timeouts of the sessions connected to this database.
+	// When this section is present, the timeouts which are not specified
+	// are reset to the values inherited from the PostgreSQL configuration
+	// +optional
+	Timeouts *SessionTimeouts `json:"timeouts,omitempty"`
+}
+
+// ExtensionSpec configures an extension in a database
+type ExtensionSpec struct {
+	// The name of the extension
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Ensure the extension is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The version of the extension to install or to update to.
+	// When empty, the default version is installed and never updated.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema in which to install the extension objects.
+	// When empty, the current schema of the extension is kept.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
+// DatabaseStatus defines the observed state of Database
+type DatabaseStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Ready is true if the database was reconciled correctly
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Error is the reconciliation error message
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error",description="Latest error message"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Database.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec DatabaseSpec `json:"spec"`
+	// Most recently observed status of the Database. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+// GetReclaimPolicy returns the reclaim policy of the database,
+// defaulting to `retain`
+func (db *Database) GetReclaimPolicy() DatabaseReclaimPolicy {
+	if db.Spec.ReclaimPolicy == "" {
+		return DatabaseReclaimRetain
+	}
+	return db.Spec.ReclaimPolicy
+}
+
+// GetEnsure returns whether the database should be present or absent,
+// defaulting to `present`
+func (db *Database) GetEnsure() EnsureOption {
+	if db.Spec.Ensure == "" {
+		return EnsurePresent
+	}
+	return db.Spec.Ensure
+}
+
+// GetEnsure returns whether the extension should be present or absent,
+// defaulting to `present`
+func (ext ExtensionSpec) GetEnsure() EnsureOption {
+	if ext.Ensure == "" {
+		return EnsurePresent
+	}
+	return ext.Ensure
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
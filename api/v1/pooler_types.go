@@ -17,10 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
 // PoolerType is the type of the connection pool, meaning the service
@@ -55,7 +59,14 @@ const (
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
 	// Pooler name should never match with any cluster name within the same namespace.
-	Cluster LocalObjectReference `json:"cluster"`
+	// Either this or `externalCluster` must be specified
+	// +optional
+	Cluster LocalObjectReference `json:"cluster,omitempty"`
+
+	// The PostgreSQL server, not managed by CloudNativePG, on which the
+	// Pooler will work. Either this or `cluster` must be specified
+	// +optional
+	ExternalCluster *PoolerExternalCluster `json:"externalCluster,omitempty"`
 
 	// Type of service to forward traffic to. Default: `rw`.
 	// +kubebuilder:default:=rw
@@ -78,11 +89,72 @@ type PoolerSpec struct {
 	// +optional
 	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
 
+	// Policy to follow when the Secrets used by PgBouncer change: the
+	// changes can be applied by reloading PgBouncer (`reload` - default)
+	// or with a rolling update of the Deployment (`restart`)
+	// +kubebuilder:default:=reload
+	// +kubebuilder:validation:Enum:=reload;restart
+	// +optional
+	ResourcesUpdatePolicy ResourcesUpdatePolicy `json:"resourcesUpdatePolicy,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this pooler.
 	// +optional
 	Monitoring *PoolerMonitoringConfiguration `json:"monitoring,omitempty"`
 }
 
+// PoolerExternalCluster contains the coordinates of a PostgreSQL server
+// that is not managed by CloudNativePG
+type PoolerExternalCluster struct {
+	// The host name or the IP address of the PostgreSQL server
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// The port of the PostgreSQL server. Default: `5432`.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=5432
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// The SSL mode used by PgBouncer to connect to the PostgreSQL
+	// server. Default: `require`.
+	// +kubebuilder:validation:Enum:=disable;allow;prefer;require;verify-ca;verify-full
+	// +kubebuilder:default:=require
+	// +optional
+	SSLMode string `json:"sslmode,omitempty"`
+
+	// The secret containing the CA certificate, in the `ca.crt` key, used
+	// to verify the certificate of the PostgreSQL server. Required by the
+	// `verify-ca` and `verify-full` SSL modes
+	// +optional
+	ServerCASecret *LocalObjectReference `json:"serverCASecret,omitempty"`
+}
+
+// GetPort returns the port of the PostgreSQL server
+func (in *PoolerExternalCluster) GetPort() int32 {
+	if in.Port == 0 {
+		return 5432
+	}
+	return in.Port
+}
+
+// GetSSLMode returns the SSL mode used to connect to the PostgreSQL server
+func (in *PoolerExternalCluster) GetSSLMode() string {
+	if in.SSLMode == "" {
+		return "require"
+	}
+	return in.SSLMode
+}
+
+// GetServerCASecretName returns the name of the secret containing the CA
+// certificate of the PostgreSQL server, or an empty string if not set
+func (in *PoolerExternalCluster) GetServerCASecretName() string {
+	if in.ServerCASecret == nil {
+		return ""
+	}
+	return in.ServerCASecret.Name
+}
+
 // PoolerMonitoringConfiguration is the type containing all the monitoring
 // configuration for a certain Pooler.
 //
@@ -164,6 +236,120 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// The databases exposed by PgBouncer, each one with its own pool
+	// settings. When not specified, every database of the cluster is
+	// exposed through a wildcard entry using the global pool settings
+	// +optional
+	Databases []PgBouncerDatabase `json:"databases,omitempty"`
+
+	// The pool settings overriding the global ones for specific users
+	// +optional
+	Users []PgBouncerUser `json:"users,omitempty"`
+
+	// Allows the operator to pause PgBouncer while the primary of the
+	// cluster changes during a switchover or a failover, resuming it once
+	// the new primary is serving. Only supported by `rw` poolers
+	// +optional
+	PauseOnSwitchover *PgBouncerPauseOnSwitchoverConfiguration `json:"pauseOnSwitchover,omitempty"`
+}
+
+// PgBouncerPauseOnSwitchoverConfiguration contains the configuration
+// of the pause of PgBouncer while the primary of the cluster changes
+type PgBouncerPauseOnSwitchoverConfiguration struct {
+	// When set to `true`, the operator invokes PgBouncer's `PAUSE` command
+	// when the primary of the cluster starts changing, and the `RESUME`
+	// command as soon as the `rw` service points to the new primary.
+	// Default: `false`.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum time in seconds PgBouncer is kept paused, after which it
+	// is resumed even if the new primary is not serving yet, bounding the
+	// time the clients have to wait. Default: `30`.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=30
+	// +optional
+	MaxPauseSeconds int32 `json:"maxPauseSeconds,omitempty"`
+}
+
+// GetMaxPause returns the maximum time PgBouncer is kept paused
+// while the primary of the cluster changes
+func (in *PgBouncerPauseOnSwitchoverConfiguration) GetMaxPause() time.Duration {
+	if in.MaxPauseSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(in.MaxPauseSeconds) * time.Second
+}
+
+// PgBouncerDatabase is an entry of the `databases` section of the
+// PgBouncer configuration, pointing to a database of the cluster
+type PgBouncerDatabase struct {
+	// The name of the database as seen by the clients connecting
+	// to PgBouncer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The name of the database in the cluster. Defaults to the name
+	// of the entry
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The pool mode for this database, overriding the global one
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections for each user
+	// of this database, overriding `default_pool_size`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The minimum number of server connections kept in the pool,
+	// overriding `min_pool_size`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinPoolSize *int32 `json:"minPoolSize,omitempty"`
+
+	// The number of additional connections allowed to the pool,
+	// overriding `reserve_pool_size`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePoolSize *int32 `json:"reservePoolSize,omitempty"`
+
+	// The maximum number of server connections to this database,
+	// overriding `max_db_connections`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// GetDBName returns the name of the database in the cluster
+func (in PgBouncerDatabase) GetDBName() string {
+	if in.DBName != "" {
+		return in.DBName
+	}
+	return in.Name
+}
+
+// PgBouncerUser is an entry of the `users` section of the PgBouncer
+// configuration
+type PgBouncerUser struct {
+	// The name of the user
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The pool mode for this user, overriding the database and the
+	// global ones
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections for this user,
+	// overriding `max_user_connections`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections *int32 `json:"maxUserConnections,omitempty"`
 }
 
 // IsPaused returns whether all database should be paused or not
@@ -179,8 +365,43 @@ type PoolerStatus struct {
 	// The number of pods trying to be scheduled
 	// +optional
 	Instances int32 `json:"instances,omitempty"`
+
+	// The time when the operator paused PgBouncer because the primary
+	// of the cluster is changing. Unset when PgBouncer is not paused
+	// for this reason
+	// +optional
+	SwitchoverPausedSince *metav1.Time `json:"switchoverPausedSince,omitempty"`
+
+	// The hash of the secrets versions reloaded by each PgBouncer
+	// instance, indexed by the name of its pod
+	// +optional
+	ReloadedSecrets map[string]string `json:"reloadedSecrets,omitempty"`
+
+	// Conditions for the pooler object
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionPoolerSecretsReloaded is the condition type telling
+	// whether every PgBouncer instance reloaded the current version of
+	// the certificates and secrets
+	ConditionPoolerSecretsReloaded = "SecretsReloaded"
+
+	// ReasonPoolerSecretsReloaded is the reason of the SecretsReloaded
+	// condition when every instance reloaded the current secrets
+	ReasonPoolerSecretsReloaded = "Reloaded"
+
+	// ReasonPoolerSecretsReloadPending is the reason of the SecretsReloaded
+	// condition when some instances didn't reload the current secrets yet
+	ReasonPoolerSecretsReloadPending = "ReloadPending"
+
+	// ReasonPoolerSecretsReloadTimedOut is the reason of the SecretsReloaded
+	// condition when some instances didn't reload the current secrets
+	// within the expected time
+	ReasonPoolerSecretsReloadTimedOut = "ReloadTimedOut"
+)
+
 // PoolerSecrets contains the versions of all the secrets used
 type PoolerSecrets struct {
 	// The server TLS secret version
@@ -265,6 +486,32 @@ func (in *Pooler) GetAuthQuerySecretName() string {
 	return in.Spec.Cluster.Name + DefaultPgBouncerPoolerSecretSuffix
 }
 
+// GetSecretsHash returns the hash of the versions of the secrets
+// the PgBouncer instances should use
+func (in *Pooler) GetSecretsHash() (string, error) {
+	return hash.ComputeHash(in.Status.Secrets)
+}
+
+// IsExternal returns true if the Pooler works on a PostgreSQL server
+// not managed by CloudNativePG
+func (in *Pooler) IsExternal() bool {
+	return in.Spec.ExternalCluster != nil
+}
+
+// IsPauseOnSwitchoverEnabled returns true if the operator should pause
+// PgBouncer while the primary of the cluster changes
+func (in *Pooler) IsPauseOnSwitchoverEnabled() bool {
+	return in.Spec.PgBouncer != nil &&
+		in.Spec.PgBouncer.PauseOnSwitchover != nil &&
+		in.Spec.PgBouncer.PauseOnSwitchover.Enabled
+}
+
+// IsPausedForSwitchover returns true if the operator paused PgBouncer
+// because the primary of the cluster is changing
+func (in *Pooler) IsPausedForSwitchover() bool {
+	return in.Status.SwitchoverPausedSince != nil
+}
+
 // GetAuthQuery returns the specified AuthQuery name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuery() string {
@@ -274,3 +521,13 @@ func (in *Pooler) GetAuthQuery() string {
 
 	return DefaultPgBouncerPoolerAuthQuery
 }
+
+// GetResourcesUpdatePolicy returns the policy to apply the changes of the
+// Secrets used by PgBouncer, defaulting to reload
+func (in *Pooler) GetResourcesUpdatePolicy() ResourcesUpdatePolicy {
+	if in.Spec.ResourcesUpdatePolicy == "" {
+		return ResourcesUpdatePolicyReload
+	}
+
+	return in.Spec.ResourcesUpdatePolicy
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -34,4 +36,21 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	It("pgbouncer pools are not paused during switchovers by default", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{},
+			},
+		}
+		Expect(pooler.IsPauseOnSwitchoverEnabled()).To(BeFalse())
+		Expect(pooler.IsPausedForSwitchover()).To(BeFalse())
+
+		pooler.Spec.PgBouncer.PauseOnSwitchover = &PgBouncerPauseOnSwitchoverConfiguration{Enabled: true}
+		Expect(pooler.IsPauseOnSwitchoverEnabled()).To(BeTrue())
+		Expect(pooler.Spec.PgBouncer.PauseOnSwitchover.GetMaxPause()).To(Equal(30 * time.Second))
+
+		pooler.Spec.PgBouncer.PauseOnSwitchover.MaxPauseSeconds = 5
+		Expect(pooler.Spec.PgBouncer.PauseOnSwitchover.GetMaxPause()).To(Equal(5 * time.Second))
+	})
 })
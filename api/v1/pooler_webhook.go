@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"regexp"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -29,7 +32,21 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
+const (
+	// pgbouncerAdminDatabase is the name of the virtual database
+	// used to access the PgBouncer admin console
+	pgbouncerAdminDatabase = "pgbouncer"
+)
+
 var (
+	// pgbouncerIdentifierRegex matches the database and user names that can
+	// be written in the PgBouncer configuration without quoting
+	pgbouncerIdentifierRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_$.-]*$`)
+
+	// pgbouncerHostRegex matches the host names and the IP addresses
+	// of the external clusters
+	pgbouncerHostRegex = regexp.MustCompile(`^[a-zA-Z0-9.:_-]+$`)
+
 	// poolerLog is for logging in this package.
 	poolerLog = log.WithName("pooler-resource").WithValues("version", "v1")
 
@@ -147,12 +164,19 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 	}
 
 	result = append(result, r.validatePgbouncerGenericParameters()...)
+	result = append(result, r.validatePgbouncerDatabases()...)
+	result = append(result, r.validatePgbouncerUsers()...)
+	result = append(result, r.validatePauseOnSwitchover()...)
 
 	return result
 }
 
 func (r *Pooler) validateCluster() field.ErrorList {
 	var result field.ErrorList
+	if r.IsExternal() {
+		return r.validateExternalCluster()
+	}
+
 	if r.Spec.Cluster.Name == "" {
 		result = append(result,
 			field.Invalid(
@@ -168,6 +192,45 @@ func (r *Pooler) validateCluster() field.ErrorList {
 	return result
 }
 
+// validateExternalCluster validates a Pooler working on a PostgreSQL
+// server not managed by CloudNativePG
+func (r *Pooler) validateExternalCluster() field.ErrorList {
+	var result field.ErrorList
+	externalCluster := r.Spec.ExternalCluster
+
+	if r.Spec.Cluster.Name != "" {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "cluster", "name"),
+				r.Spec.Cluster.Name, "cannot specify both a cluster and an external cluster"))
+	}
+
+	if r.Spec.PgBouncer != nil &&
+		(r.Spec.PgBouncer.AuthQuerySecret == nil || r.Spec.PgBouncer.AuthQuerySecret.Name == "") {
+		result = append(result,
+			field.Required(
+				field.NewPath("spec", "pgbouncer", "authQuerySecret"),
+				"must specify an auth query secret when using an external cluster"))
+	}
+
+	if !pgbouncerHostRegex.MatchString(externalCluster.Host) {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "externalCluster", "host"),
+				externalCluster.Host, "invalid host name or IP address"))
+	}
+
+	sslMode := externalCluster.GetSSLMode()
+	if (sslMode == "verify-ca" || sslMode == "verify-full") && externalCluster.GetServerCASecretName() == "" {
+		result = append(result,
+			field.Required(
+				field.NewPath("spec", "externalCluster", "serverCASecret"),
+				fmt.Sprintf("must specify a server CA secret when using the %s SSL mode", sslMode)))
+	}
+
+	return result
+}
+
 // Validate validates the configuration of a Pooler, returning
 // a list of errors
 func (r *Pooler) Validate() (allErrs field.ErrorList) {
@@ -190,3 +253,75 @@ func (r *Pooler) validatePgbouncerGenericParameters() field.ErrorList {
 	}
 	return result
 }
+
+// validatePgbouncerDatabases validates the databases exposed by PgBouncer
+func (r *Pooler) validatePgbouncerDatabases() field.ErrorList {
+	var result field.ErrorList
+
+	names := stringset.New()
+	for idx, database := range r.Spec.PgBouncer.Databases {
+		path := field.NewPath("spec", "pgbouncer", "databases").Index(idx)
+
+		switch {
+		case !pgbouncerIdentifierRegex.MatchString(database.Name):
+			result = append(result, field.Invalid(
+				path.Child("name"), database.Name, "invalid database name"))
+		case database.Name == pgbouncerAdminDatabase:
+			result = append(result, field.Invalid(
+				path.Child("name"), database.Name, "the name is reserved for the PgBouncer admin console"))
+		case names.Has(database.Name):
+			result = append(result, field.Duplicate(path.Child("name"), database.Name))
+		}
+		names.Put(database.Name)
+
+		if database.DBName != "" && !pgbouncerIdentifierRegex.MatchString(database.DBName) {
+			result = append(result, field.Invalid(
+				path.Child("dbname"), database.DBName, "invalid database name"))
+		}
+	}
+
+	return result
+}
+
+// validatePgbouncerUsers validates the user specific PgBouncer settings
+func (r *Pooler) validatePgbouncerUsers() field.ErrorList {
+	var result field.ErrorList
+
+	names := stringset.New()
+	for idx, user := range r.Spec.PgBouncer.Users {
+		path := field.NewPath("spec", "pgbouncer", "users").Index(idx)
+
+		switch {
+		case !pgbouncerIdentifierRegex.MatchString(user.Name):
+			result = append(result, field.Invalid(
+				path.Child("name"), user.Name, "invalid user name"))
+		case names.Has(user.Name):
+			result = append(result, field.Duplicate(path.Child("name"), user.Name))
+		}
+		names.Put(user.Name)
+	}
+
+	return result
+}
+
+// validatePauseOnSwitchover validates the pause of PgBouncer while the
+// primary of the cluster changes
+func (r *Pooler) validatePauseOnSwitchover() field.ErrorList {
+	var result field.ErrorList
+
+	if !r.IsPauseOnSwitchoverEnabled() {
+		return result
+	}
+
+	path := field.NewPath("spec", "pgbouncer", "pauseOnSwitchover", "enabled")
+	switch {
+	case r.IsExternal():
+		result = append(result, field.Invalid(
+			path, true, "not supported when using an external cluster"))
+	case r.Spec.Type == PoolerTypeRO:
+		result = append(result, field.Invalid(
+			path, true, "only supported by rw poolers"))
+	}
+
+	return result
+}
@@ -128,4 +128,123 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("allows specifying multiple databases and users", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabase{
+						{Name: "app"},
+						{Name: "reporting", DBName: "app"},
+					},
+					Users: []PgBouncerUser{
+						{Name: "app"},
+					},
+				},
+			},
+		}
+
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+	})
+
+	It("doesn't allow duplicate or reserved database names", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabase{
+						{Name: "app"},
+						{Name: "app"},
+						{Name: "pgbouncer"},
+					},
+				},
+			},
+		}
+
+		Expect(pooler.validatePgBouncer()).To(HaveLen(2))
+	})
+
+	It("doesn't allow database and user names that need quoting", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabase{
+						{Name: "app", DBName: "my app"},
+						{Name: "*"},
+					},
+					Users: []PgBouncerUser{
+						{Name: "app=1"},
+						{Name: "app"},
+						{Name: "app"},
+					},
+				},
+			},
+		}
+
+		Expect(pooler.validatePgBouncer()).To(HaveLen(4))
+	})
+
+	Context("with an external cluster", func() {
+		var pooler Pooler
+
+		BeforeEach(func() {
+			pooler = Pooler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pooler",
+				},
+				Spec: PoolerSpec{
+					ExternalCluster: &PoolerExternalCluster{
+						Host: "postgres.example.com",
+					},
+					PgBouncer: &PgBouncerSpec{
+						AuthQuery: "SELECT usename, passwd FROM pg_shadow WHERE usename=$1",
+						AuthQuerySecret: &LocalObjectReference{
+							Name: "auth",
+						},
+					},
+				},
+			}
+		})
+
+		It("doesn't require a cluster name", func() {
+			Expect(pooler.Validate()).To(BeEmpty())
+		})
+
+		It("doesn't allow specifying a cluster too", func() {
+			pooler.Spec.Cluster.Name = "cluster-example"
+			Expect(pooler.Validate()).To(HaveLen(1))
+		})
+
+		It("requires an auth query secret", func() {
+			pooler.Spec.PgBouncer = &PgBouncerSpec{}
+			Expect(pooler.Validate()).To(HaveLen(1))
+		})
+
+		It("requires a CA when verifying the server certificate", func() {
+			pooler.Spec.ExternalCluster.SSLMode = "verify-full"
+			Expect(pooler.Validate()).To(HaveLen(1))
+
+			pooler.Spec.ExternalCluster.ServerCASecret = &LocalObjectReference{Name: "ca"}
+			Expect(pooler.Validate()).To(BeEmpty())
+		})
+
+		It("doesn't allow invalid host names", func() {
+			pooler.Spec.ExternalCluster.Host = "postgres.example.com port=1234"
+			Expect(pooler.Validate()).To(HaveLen(1))
+		})
+	})
+
+	It("allows pausing rw poolers during switchovers only", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Type: PoolerTypeRW,
+				PgBouncer: &PgBouncerSpec{
+					PauseOnSwitchover: &PgBouncerPauseOnSwitchoverConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+
+		pooler.Spec.Type = PoolerTypeRO
+		Expect(pooler.validatePgBouncer()).To(HaveLen(1))
+	})
 })
@@ -17,6 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -60,10 +65,10 @@ type ScheduledBackupSpec struct {
 	// +optional
 	Target BackupTarget `json:"target,omitempty"`
 
-	// The backup method to be used, possible options are `barmanObjectStore`
-	// and `volumeSnapshot`. Defaults to: `barmanObjectStore`.
+	// The backup method to be used, possible options are `barmanObjectStore`,
+	// `volumeSnapshot`, `plugin` and `pvc`. Defaults to: `barmanObjectStore`.
 	// +optional
-	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot
+	// +kubebuilder:validation:Enum=barmanObjectStore;volumeSnapshot;plugin;pvc
 	// +kubebuilder:default:=barmanObjectStore
 	Method BackupMethod `json:"method,omitempty"`
 
@@ -77,6 +82,24 @@ type ScheduledBackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// Whether the backup with volume snapshots is crash-consistent: the
+	// snapshots are taken on the primary, without putting PostgreSQL in
+	// backup mode nor fencing the instance, and are restored like after a
+	// crash. No WAL archive is required, but PGDATA must be on a single
+	// volume. It can't be used together with `online` and `onlineConfiguration`
+	// +optional
+	CrashConsistent bool `json:"crashConsistent,omitempty"`
+	// RetentionPolicy is the retention policy of the backups created by
+	// this schedule (i.e. '7d'), independent from the one of the cluster.
+	// The retention policy is expressed in the form of `XXu` where `XX` is
+	// a positive integer and `u` is in `[dwm]` - days, weeks, months.
+	// The older backups are deleted together with their volume snapshots,
+	// always keeping the latest completed one.
+	// It's currently only applicable when using the volumeSnapshot method.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	RetentionPolicy string `json:"retentionPolicy,omitempty"`
 }
 
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
@@ -167,6 +190,45 @@ func (scheduledBackup *ScheduledBackup) GetStatus() *ScheduledBackupStatus {
 	return &scheduledBackup.Status
 }
 
+// retentionPolicyRegexp matches the retention policy of a scheduled backup
+var retentionPolicyRegexp = regexp.MustCompile(`^([1-9][0-9]*)([dwm])$`)
+
+// GetRetentionCutoff gets the time before which the backups created by this
+// scheduled backup are expired, or nil when there's no retention policy
+func (scheduledBackup *ScheduledBackup) GetRetentionCutoff(now time.Time) (*time.Time, error) {
+	return getRetentionCutoff(scheduledBackup.Spec.RetentionPolicy, now)
+}
+
+// getRetentionCutoff gets the time before which the backups are expired
+// according to the passed retention policy, or nil when it is empty
+func getRetentionCutoff(retentionPolicy string, now time.Time) (*time.Time, error) {
+	if retentionPolicy == "" {
+		return nil, nil
+	}
+
+	matches := retentionPolicyRegexp.FindStringSubmatch(retentionPolicy)
+	if matches == nil {
+		return nil, fmt.Errorf("not a valid retention policy: %s", retentionPolicy)
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var cutoff time.Time
+	switch matches[2] {
+	case "d":
+		cutoff = now.AddDate(0, 0, -value)
+	case "w":
+		cutoff = now.AddDate(0, 0, -7*value)
+	default:
+		cutoff = now.AddDate(0, -value, 0)
+	}
+
+	return &cutoff, nil
+}
+
 // CreateBackup creates a backup from this scheduled backup
 func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 	backup := Backup{
@@ -180,9 +242,11 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Method:              scheduledBackup.Spec.Method,
 			Online:              scheduledBackup.Spec.Online,
 			OnlineConfiguration: scheduledBackup.Spec.OnlineConfiguration,
+			CrashConsistent:     scheduledBackup.Spec.CrashConsistent,
 		},
 	}
 	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current)
+	utils.InheritLabels(&backup.ObjectMeta, scheduledBackup.Labels, nil, configuration.Current)
 	return &backup
 }
 
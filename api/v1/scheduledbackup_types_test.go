@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -49,6 +51,23 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(backup.Spec.Target).To(BeEmpty())
 	})
 
+	It("properly creates a backup with the inherited labels", func() {
+		scheduledBackup := &ScheduledBackup{}
+		scheduledBackup.Labels = map[string]string{
+			"team":        "dba",
+			"environment": "production",
+		}
+		configuration.Current.InheritedLabels = []string{"team"}
+		defer func() {
+			configuration.Current.InheritedLabels = nil
+		}()
+
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup).ToNot(BeNil())
+		Expect(backup.Labels).To(HaveKeyWithValue("team", "dba"))
+		Expect(backup.Labels).ToNot(HaveKey("environment"))
+	})
+
 	It("properly creates a backup with standby target", func() {
 		scheduledBackup.Spec.Target = BackupTargetStandby
 		backup := scheduledBackup.CreateBackup("test")
@@ -90,4 +109,38 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("properly creates a crash-consistent backup", func() {
+		scheduledBackup.Spec.CrashConsistent = true
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup.Spec.CrashConsistent).To(BeTrue())
+	})
+
+	It("computes the retention cutoff", func() {
+		now := time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)
+		scheduledBackup := &ScheduledBackup{}
+
+		cutoff, err := scheduledBackup.GetRetentionCutoff(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cutoff).To(BeNil())
+
+		scheduledBackup.Spec.RetentionPolicy = "3d"
+		cutoff, err = scheduledBackup.GetRetentionCutoff(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*cutoff).To(Equal(time.Date(2024, time.March, 28, 12, 0, 0, 0, time.UTC)))
+
+		scheduledBackup.Spec.RetentionPolicy = "2w"
+		cutoff, err = scheduledBackup.GetRetentionCutoff(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*cutoff).To(Equal(time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)))
+
+		scheduledBackup.Spec.RetentionPolicy = "1m"
+		cutoff, err = scheduledBackup.GetRetentionCutoff(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*cutoff).To(Equal(now.AddDate(0, -1, 0)))
+
+		scheduledBackup.Spec.RetentionPolicy = "1y"
+		_, err = scheduledBackup.GetRetentionCutoff(now)
+		Expect(err).To(HaveOccurred())
+	})
 })
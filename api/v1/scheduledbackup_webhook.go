@@ -118,5 +118,29 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 		))
 	}
 
+	if r.Spec.CrashConsistent && r.Spec.Method != BackupMethodVolumeSnapshot {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "crashConsistent"),
+			r.Spec.CrashConsistent,
+			"CrashConsistent parameter can be specified only if the method is volumeSnapshot",
+		))
+	}
+
+	if r.Spec.CrashConsistent && (r.Spec.Online != nil || r.Spec.OnlineConfiguration != nil) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "crashConsistent"),
+			r.Spec.CrashConsistent,
+			"CrashConsistent parameter can't be used together with online and onlineConfiguration",
+		))
+	}
+
+	if r.Spec.RetentionPolicy != "" && r.Spec.Method != BackupMethodVolumeSnapshot {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "retentionPolicy"),
+			r.Spec.RetentionPolicy,
+			"RetentionPolicy parameter can be specified only if the method is volumeSnapshot",
+		))
+	}
+
 	return result
 }
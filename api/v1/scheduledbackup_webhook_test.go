@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"k8s.io/utils/ptr"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -70,4 +72,71 @@ var _ = Describe("Validate schedule", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.method"))
 	})
+
+	It("doesn't complain about a crash-consistent snapshot schedule with a retention policy", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:        "0 0 * * * *",
+				Method:          BackupMethodVolumeSnapshot,
+				CrashConsistent: true,
+				RetentionPolicy: "2d",
+			},
+		}
+		utils.SetVolumeSnapshot(true)
+		Expect(schedule.validate()).To(BeEmpty())
+	})
+
+	It("complains if crashConsistent is set together with onlineConfiguration", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:            "0 0 * * * *",
+				Method:              BackupMethodVolumeSnapshot,
+				CrashConsistent:     true,
+				OnlineConfiguration: &OnlineConfiguration{},
+			},
+		}
+		utils.SetVolumeSnapshot(true)
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.crashConsistent"))
+	})
+
+	It("complains if online is set on a barman backup", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule: "0 0 0 * * *",
+				Method:   BackupMethodBarmanObjectStore,
+				Online:   ptr.To(true),
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.online"))
+	})
+
+	It("complains if onlineConfiguration is set on a barman backup", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:            "0 0 0 * * *",
+				Method:              BackupMethodBarmanObjectStore,
+				OnlineConfiguration: &OnlineConfiguration{},
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
+	})
+
+	It("complains if retentionPolicy is set on a barman backup", func() {
+		schedule := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule:        "0 0 0 * * *",
+				Method:          BackupMethodBarmanObjectStore,
+				RetentionPolicy: "30d",
+			},
+		}
+		result := schedule.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.retentionPolicy"))
+	})
 })
@@ -153,6 +153,21 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(BackupPluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WalCommands != nil {
+		in, out := &in.WalCommands, &out.WalCommands
+		*out = new(WalCommandsConfiguration)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCBackupConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -197,6 +212,28 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPluginConfiguration) DeepCopyInto(out *BackupPluginConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPluginConfiguration.
+func (in *BackupPluginConfiguration) DeepCopy() *BackupPluginConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupPluginConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSnapshotElementStatus) DeepCopyInto(out *BackupSnapshotElementStatus) {
 	*out = *in
@@ -317,6 +354,21 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DeduplicatedSize != nil {
+		in, out := &in.DeduplicatedSize, &out.DeduplicatedSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -434,6 +486,21 @@ func (in *BootstrapConfiguration) DeepCopy() *BootstrapConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapControllerConfiguration) DeepCopyInto(out *BootstrapControllerConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapControllerConfiguration.
+func (in *BootstrapControllerConfiguration) DeepCopy() *BootstrapControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 	*out = *in
@@ -472,6 +539,11 @@ func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 		*out = new(Import)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImportFromDump != nil {
+		in, out := &in.ImportFromDump, &out.ImportFromDump
+		*out = new(ImportFromDump)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PostInitApplicationSQLRefs != nil {
 		in, out := &in.PostInitApplicationSQLRefs, &out.PostInitApplicationSQLRefs
 		*out = new(PostInitApplicationSQLRefs)
@@ -527,6 +599,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(RecoveryTarget)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(RecoveryTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
 		*out = new(LocalObjectReference)
@@ -544,6 +621,21 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -552,6 +644,16 @@ func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServerIssuerRef != nil {
+		in, out := &in.ServerIssuerRef, &out.ServerIssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+	if in.ReplicationIssuerRef != nil {
+		in, out := &in.ReplicationIssuerRef, &out.ReplicationIssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
@@ -654,12 +756,34 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BootstrapController != nil {
+		in, out := &in.BootstrapController, &out.BootstrapController
+		*out = new(BootstrapControllerConfiguration)
+		**out = **in
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReplicationLagSLO != nil {
+		in, out := &in.ReplicationLagSLO, &out.ReplicationLagSLO
+		*out = new(ReplicationLagSLO)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicationConnection != nil {
+		in, out := &in.ReplicationConnection, &out.ReplicationConnection
+		*out = new(ReplicationConnectionConfiguration)
+		**out = **in
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -701,6 +825,21 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = new(LifecycleHooksConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxDataLossOnFailover != nil {
+		in, out := &in.MaxDataLossOnFailover, &out.MaxDataLossOnFailover
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
@@ -709,12 +848,37 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.EphemeralVolumesSizeLimit != nil {
 		in, out := &in.EphemeralVolumesSizeLimit, &out.EphemeralVolumesSizeLimit
 		*out = new(EphemeralVolumesSizeLimitConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageUpgradeSafety != nil {
+		in, out := &in.ImageUpgradeSafety, &out.ImageUpgradeSafety
+		*out = new(ImageUpgradeSafetyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Collation != nil {
+		in, out := &in.Collation, &out.Collation
+		*out = new(CollationConfiguration)
+		**out = **in
+	}
+	if in.WraparoundProtection != nil {
+		in, out := &in.WraparoundProtection, &out.WraparoundProtection
+		*out = new(WraparoundProtectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -761,6 +925,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ManagedConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PrimaryLease != nil {
+		in, out := &in.PrimaryLease, &out.PrimaryLease
+		*out = new(PrimaryLeaseConfiguration)
+		**out = **in
+	}
 	if in.SeccompProfile != nil {
 		in, out := &in.SeccompProfile, &out.SeccompProfile
 		*out = new(corev1.SeccompProfile)
@@ -817,6 +986,21 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]TablespaceState, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfigurationDrift != nil {
+		in, out := &in.ConfigurationDrift, &out.ConfigurationDrift
+		*out = make([]ParameterDrift, len(*in))
+		copy(*out, *in)
+	}
+	if in.PGDataImageInfo != nil {
+		in, out := &in.PGDataImageInfo, &out.PGDataImageInfo
+		*out = new(ImageInfo)
+		**out = **in
+	}
+	if in.LaggingReplicas != nil {
+		in, out := &in.LaggingReplicas, &out.LaggingReplicas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -828,6 +1012,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.FileSystemResizePendingPVC != nil {
+		in, out := &in.FileSystemResizePendingPVC, &out.FileSystemResizePendingPVC
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.InitializingPVC != nil {
 		in, out := &in.InitializingPVC, &out.InitializingPVC
 		*out = make([]string, len(*in))
@@ -843,6 +1032,13 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstancesFQDN != nil {
+		in, out := &in.InstancesFQDN, &out.InstancesFQDN
+		*out = make(map[PodName]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
@@ -860,6 +1056,18 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.RestorePoints != nil {
+		in, out := &in.RestorePoints, &out.RestorePoints
+		*out = make([]RestorePoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlannedSwitchover != nil {
+		in, out := &in.PlannedSwitchover, &out.PlannedSwitchover
+		*out = new(PlannedSwitchoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -889,6 +1097,21 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollationConfiguration) DeepCopyInto(out *CollationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollationConfiguration.
+func (in *CollationConfiguration) DeepCopy() *CollationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CollationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
 	*out = *in
@@ -927,6 +1150,26 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionStormProtectionConfiguration) DeepCopyInto(out *ConnectionStormProtectionConfiguration) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionStormProtectionConfiguration.
+func (in *ConnectionStormProtectionConfiguration) DeepCopy() *ConnectionStormProtectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionStormProtectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
@@ -935,6 +1178,11 @@ func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.BandwidthLimit != nil {
+		in, out := &in.BandwidthLimit, &out.BandwidthLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataBackupConfiguration.
@@ -975,6 +1223,65 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Database) DeepCopyInto(out *Database) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
+func (in *Database) DeepCopy() *Database {
+	if in == nil {
+		return nil
+	}
+	out := new(Database)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Database) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Database, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseList.
+func (in *DatabaseList) DeepCopy() *DatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseRoleRef) DeepCopyInto(out *DatabaseRoleRef) {
 	*out = *in
@@ -990,6 +1297,102 @@ func (in *DatabaseRoleRef) DeepCopy() *DatabaseRoleRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.IsTemplate != nil {
+		in, out := &in.IsTemplate, &out.IsTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowConnections != nil {
+		in, out := &in.AllowConnections, &out.AllowConnections
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(SessionTimeouts)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
+func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DumpLocation) DeepCopyInto(out *DumpLocation) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(DumpVolumeSource)
+		**out = **in
+	}
+	if in.URLSecret != nil {
+		in, out := &in.URLSecret, &out.URLSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DumpLocation.
+func (in *DumpLocation) DeepCopy() *DumpLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(DumpLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DumpVolumeSource) DeepCopyInto(out *DumpVolumeSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DumpVolumeSource.
+func (in *DumpVolumeSource) DeepCopy() *DumpVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DumpVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1044,6 +1447,41 @@ func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopy() *EphemeralVolumesSi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecLifecycleHook) DeepCopyInto(out *ExecLifecycleHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecLifecycleHook.
+func (in *ExecLifecycleHook) DeepCopy() *ExecLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionSpec) DeepCopyInto(out *ExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionSpec.
+func (in *ExtensionSpec) DeepCopy() *ExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -1079,6 +1517,11 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCBackupConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
@@ -1111,6 +1554,63 @@ func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLifecycleHook) DeepCopyInto(out *HTTPLifecycleHook) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLifecycleHook.
+func (in *HTTPLifecycleHook) DeepCopy() *HTTPLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageInfo) DeepCopyInto(out *ImageInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageInfo.
+func (in *ImageInfo) DeepCopy() *ImageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageUpgradeSafetyConfiguration) DeepCopyInto(out *ImageUpgradeSafetyConfiguration) {
+	*out = *in
+	if in.MaxBackupAge != nil {
+		in, out := &in.MaxBackupAge, &out.MaxBackupAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageUpgradeSafetyConfiguration.
+func (in *ImageUpgradeSafetyConfiguration) DeepCopy() *ImageUpgradeSafetyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageUpgradeSafetyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
@@ -1142,6 +1642,29 @@ func (in *Import) DeepCopy() *Import {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportFromDump) DeepCopyInto(out *ImportFromDump) {
+	*out = *in
+	in.Location.DeepCopyInto(&out.Location)
+	if in.OwnerMapping != nil {
+		in, out := &in.OwnerMapping, &out.OwnerMapping
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportFromDump.
+func (in *ImportFromDump) DeepCopy() *ImportFromDump {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportFromDump)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImportSource) DeepCopyInto(out *ImportSource) {
 	*out = *in
@@ -1187,6 +1710,26 @@ func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceServicesConfiguration) DeepCopyInto(out *InstanceServicesConfiguration) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceServicesConfiguration.
+func (in *InstanceServicesConfiguration) DeepCopy() *InstanceServicesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceServicesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
 	*out = *in
@@ -1247,6 +1790,88 @@ func (in *LDAPConfig) DeepCopy() *LDAPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHook) DeepCopyInto(out *LifecycleHook) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPLifecycleHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecLifecycleHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHook.
+func (in *LifecycleHook) DeepCopy() *LifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHooksConfiguration) DeepCopyInto(out *LifecycleHooksConfiguration) {
+	*out = *in
+	if in.PrePromote != nil {
+		in, out := &in.PrePromote, &out.PrePromote
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostPromote != nil {
+		in, out := &in.PostPromote, &out.PostPromote
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreShutdown != nil {
+		in, out := &in.PreShutdown, &out.PreShutdown
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostBootstrap != nil {
+		in, out := &in.PostBootstrap, &out.PostBootstrap
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreBackup != nil {
+		in, out := &in.PreBackup, &out.PreBackup
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreSwitchover != nil {
+		in, out := &in.PreSwitchover, &out.PreSwitchover
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHooksConfiguration.
+func (in *LifecycleHooksConfiguration) DeepCopy() *LifecycleHooksConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHooksConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
@@ -1262,6 +1887,22 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -1272,6 +1913,11 @@ func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
@@ -1328,12 +1974,32 @@ func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
-func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
+func (in *ManagedRoles) DeepCopy() *ManagedRoles {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRoles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.InstanceServices != nil {
+		in, out := &in.InstanceServices, &out.InstanceServices
+		*out = new(InstanceServicesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
+func (in *ManagedServices) DeepCopy() *ManagedServices {
 	if in == nil {
 		return nil
 	}
-	out := new(ManagedRoles)
+	out := new(ManagedServices)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1464,6 +2130,36 @@ func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCBackupConfiguration) DeepCopyInto(out *PVCBackupConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCBackupConfiguration.
+func (in *PVCBackupConfiguration) DeepCopy() *PVCBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterDrift) DeepCopyInto(out *ParameterDrift) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterDrift.
+func (in *ParameterDrift) DeepCopy() *ParameterDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordState) DeepCopyInto(out *PasswordState) {
 	*out = *in
@@ -1479,6 +2175,41 @@ func (in *PasswordState) DeepCopy() *PasswordState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDatabase) DeepCopyInto(out *PgBouncerDatabase) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinPoolSize != nil {
+		in, out := &in.MinPoolSize, &out.MinPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservePoolSize != nil {
+		in, out := &in.ReservePoolSize, &out.ReservePoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabase.
+func (in *PgBouncerDatabase) DeepCopy() *PgBouncerDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1499,6 +2230,21 @@ func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerPauseOnSwitchoverConfiguration) DeepCopyInto(out *PgBouncerPauseOnSwitchoverConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerPauseOnSwitchoverConfiguration.
+func (in *PgBouncerPauseOnSwitchoverConfiguration) DeepCopy() *PgBouncerPauseOnSwitchoverConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerPauseOnSwitchoverConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
 	*out = *in
@@ -1540,6 +2286,25 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgBouncerUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PauseOnSwitchover != nil {
+		in, out := &in.PauseOnSwitchover, &out.PauseOnSwitchover
+		*out = new(PgBouncerPauseOnSwitchoverConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -1552,6 +2317,50 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUser) DeepCopyInto(out *PgBouncerUser) {
+	*out = *in
+	if in.MaxUserConnections != nil {
+		in, out := &in.MaxUserConnections, &out.MaxUserConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUser.
+func (in *PgBouncerUser) DeepCopy() *PgBouncerUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedSwitchoverStatus) DeepCopyInto(out *PlannedSwitchoverStatus) {
+	*out = *in
+	in.AnnouncedAt.DeepCopyInto(&out.AnnouncedAt)
+	if in.HooksCompletedAt != nil {
+		in, out := &in.HooksCompletedAt, &out.HooksCompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.AbortedAt != nil {
+		in, out := &in.AbortedAt, &out.AbortedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedSwitchoverStatus.
+func (in *PlannedSwitchoverStatus) DeepCopy() *PlannedSwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedSwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
 	*out = *in
@@ -1617,6 +2426,26 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerExternalCluster) DeepCopyInto(out *PoolerExternalCluster) {
+	*out = *in
+	if in.ServerCASecret != nil {
+		in, out := &in.ServerCASecret, &out.ServerCASecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerExternalCluster.
+func (in *PoolerExternalCluster) DeepCopy() *PoolerExternalCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerExternalCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
 	*out = *in
@@ -1729,6 +2558,11 @@ func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
 func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 	*out = *in
 	out.Cluster = in.Cluster
+	if in.ExternalCluster != nil {
+		in, out := &in.ExternalCluster, &out.ExternalCluster
+		*out = new(PoolerExternalCluster)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Instances != nil {
 		in, out := &in.Instances, &out.Instances
 		*out = new(int32)
@@ -1774,6 +2608,24 @@ func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
 		*out = new(PoolerSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SwitchoverPausedSince != nil {
+		in, out := &in.SwitchoverPausedSince, &out.SwitchoverPausedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.ReloadedSecrets != nil {
+		in, out := &in.ReloadedSecrets, &out.ReloadedSecrets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
@@ -1832,6 +2684,11 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		copy(*out, *in)
 	}
 	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(SynchronousReplicaConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalLibraries != nil {
 		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
 		*out = make([]string, len(*in))
@@ -1842,6 +2699,11 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnectionStormProtection != nil {
+		in, out := &in.ConnectionStormProtection, &out.ConnectionStormProtection
+		*out = new(ConnectionStormProtectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -1854,6 +2716,21 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryLeaseConfiguration) DeepCopyInto(out *PrimaryLeaseConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryLeaseConfiguration.
+func (in *PrimaryLeaseConfiguration) DeepCopy() *PrimaryLeaseConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryLeaseConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -1879,6 +2756,26 @@ func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryTuning) DeepCopyInto(out *RecoveryTuning) {
+	*out = *in
+	if in.MaintenanceIOConcurrency != nil {
+		in, out := &in.MaintenanceIOConcurrency, &out.MaintenanceIOConcurrency
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryTuning.
+func (in *RecoveryTuning) DeepCopy() *RecoveryTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
 	*out = *in
@@ -1894,6 +2791,46 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConnectionConfiguration) DeepCopyInto(out *ReplicationConnectionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConnectionConfiguration.
+func (in *ReplicationConnectionConfiguration) DeepCopy() *ReplicationConnectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConnectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationLagSLO) DeepCopyInto(out *ReplicationLagSLO) {
+	*out = *in
+	if in.MaxLagSize != nil {
+		in, out := &in.MaxLagSize, &out.MaxLagSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxLagTime != nil {
+		in, out := &in.MaxLagTime, &out.MaxLagTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationLagSLO.
+func (in *ReplicationLagSLO) DeepCopy() *ReplicationLagSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationLagSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -1934,6 +2871,22 @@ func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestorePoint) DeepCopyInto(out *RestorePoint) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestorePoint.
+func (in *RestorePoint) DeepCopy() *RestorePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(RestorePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 	*out = *in
@@ -1956,6 +2909,11 @@ func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(SessionTimeouts)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleConfiguration.
@@ -2224,6 +3182,21 @@ func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionTimeouts) DeepCopyInto(out *SessionTimeouts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionTimeouts.
+func (in *SessionTimeouts) DeepCopy() *SessionTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -2274,6 +3247,36 @@ func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstra
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynchronousReplicaConfiguration) DeepCopyInto(out *SynchronousReplicaConfiguration) {
+	*out = *in
+	if in.MaxStandbyNamesFromCluster != nil {
+		in, out := &in.MaxStandbyNamesFromCluster, &out.MaxStandbyNamesFromCluster
+		*out = new(int)
+		**out = **in
+	}
+	if in.StandbyNamesPre != nil {
+		in, out := &in.StandbyNamesPre, &out.StandbyNamesPre
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StandbyNamesPost != nil {
+		in, out := &in.StandbyNamesPost, &out.StandbyNamesPost
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousReplicaConfiguration.
+func (in *SynchronousReplicaConfiguration) DeepCopy() *SynchronousReplicaConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SynchronousReplicaConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
@@ -2395,3 +3398,43 @@ func (in *WalBackupConfiguration) DeepCopy() *WalBackupConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalCommandsConfiguration) DeepCopyInto(out *WalCommandsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalCommandsConfiguration.
+func (in *WalCommandsConfiguration) DeepCopy() *WalCommandsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalCommandsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WraparoundProtectionConfiguration) DeepCopyInto(out *WraparoundProtectionConfiguration) {
+	*out = *in
+	if in.WarningAge != nil {
+		in, out := &in.WarningAge, &out.WarningAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EmergencyVacuumAge != nil {
+		in, out := &in.EmergencyVacuumAge, &out.EmergencyVacuumAge
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WraparoundProtectionConfiguration.
+func (in *WraparoundProtectionConfiguration) DeepCopy() *WraparoundProtectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WraparoundProtectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
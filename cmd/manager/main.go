@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/backup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/backupplugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/bootstrap"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance"
@@ -52,6 +53,7 @@ func main() {
 	logFlags.AddFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(backup.NewCmd())
+	cmd.AddCommand(backupplugin.NewCmd())
 	cmd.AddCommand(bootstrap.NewCmd())
 	cmd.AddCommand(controller.NewCmd())
 	cmd.AddCommand(instance.NewCmd())
@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
@@ -153,7 +154,8 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
-	if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore {
+	if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore || backup.Spec.Method == apiv1.BackupMethodPlugin ||
+		backup.Spec.Method == apiv1.BackupMethodPVC {
 		if isRunning {
 			return ctrl.Result{}, nil
 		}
@@ -164,7 +166,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	origBackup := backup.DeepCopy()
 	switch backup.Spec.Method {
-	case apiv1.BackupMethodBarmanObjectStore:
+	case apiv1.BackupMethodBarmanObjectStore, apiv1.BackupMethodPlugin, apiv1.BackupMethodPVC:
 		// If no good running backups are found we elect a pod for the backup
 		pod, err := r.getBackupTargetPod(ctx, &cluster, &backup)
 		if apierrs.IsNotFound(err) {
@@ -195,11 +197,21 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			"cluster", cluster.Name,
 			"pod", pod.Name)
 
-		if cluster.Spec.Backup.BarmanObjectStore == nil {
+		if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore && cluster.Spec.Backup.BarmanObjectStore == nil {
 			tryFlagBackupAsFailed(ctx, r.Client, &backup,
 				errors.New("no barmanObjectStore section defined on the target cluster"))
 			return ctrl.Result{}, nil
 		}
+		if backup.Spec.Method == apiv1.BackupMethodPlugin && cluster.Spec.Backup.Plugin == nil {
+			tryFlagBackupAsFailed(ctx, r.Client, &backup,
+				errors.New("no plugin section defined on the target cluster"))
+			return ctrl.Result{}, nil
+		}
+		if backup.Spec.Method == apiv1.BackupMethodPVC && cluster.Spec.Backup.PVC == nil {
+			tryFlagBackupAsFailed(ctx, r.Client, &backup,
+				errors.New("no pvc section defined on the target cluster"))
+			return ctrl.Result{}, nil
+		}
 		// This backup has been started
 		if err := startBarmanBackup(ctx, r.Client, &backup, pod, &cluster); err != nil {
 			r.Recorder.Eventf(&backup, "Warning", "Error", "Backup exit with error %v", err)
@@ -505,6 +517,11 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 	if backup.Spec.Target != "" {
 		backupTarget = backup.Spec.Target
 	}
+
+	// The PGDATA of a standby isn't restorable as it is after a crash
+	if backup.Spec.CrashConsistent {
+		backupTarget = apiv1.BackupTargetPrimary
+	}
 	postgresqlStatusList := r.instanceStatusClient.GetStatusFromInstances(ctx, pods)
 	for _, item := range postgresqlStatusList.Items {
 		if !item.IsPodReady {
@@ -550,7 +567,7 @@ func startBarmanBackup(
 ) error {
 	// This backup has been started
 	status := backup.GetStatus()
-	status.SetAsStarted(pod, apiv1.BackupMethodBarmanObjectStore)
+	status.SetAsStarted(pod, backup.Spec.Method)
 
 	if err := postgres.PatchBackupStatusAndRetry(ctx, client, backup); err != nil {
 		return err
@@ -611,6 +628,10 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 		return err
 	}
 
+	if err := metrics.Registry.Register(&backupMetricsCollector{cli: mgr.GetClient()}); err != nil {
+		return err
+	}
+
 	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Backup{}).
 		Watches(&apiv1.Cluster{},
@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// backupMetricsTimeout is the maximum time allowed to list the backups
+// when the metrics are scraped
+const backupMetricsTimeout = 10 * time.Second
+
+var backupMetricsLabels = []string{"namespace", "cluster", "method"}
+
+var (
+	backupCompletedDesc = prometheus.NewDesc(
+		"cnpg_backups_completed",
+		"Number of completed backups of the cluster",
+		backupMetricsLabels, nil,
+	)
+	backupSizeDesc = prometheus.NewDesc(
+		"cnpg_backups_size_bytes",
+		"Total size in bytes of the completed backups of the cluster",
+		backupMetricsLabels, nil,
+	)
+	backupDeduplicatedSizeDesc = prometheus.NewDesc(
+		"cnpg_backups_deduplicated_size_bytes",
+		"Total size in bytes of the data actually stored by the completed backups of the cluster, "+
+			"only for the backup methods supporting deduplicated or incremental backups",
+		backupMetricsLabels, nil,
+	)
+	backupLastDurationDesc = prometheus.NewDesc(
+		"cnpg_backups_last_duration_seconds",
+		"Duration in seconds of the last completed backup of the cluster",
+		backupMetricsLabels, nil,
+	)
+)
+
+// backupMetricsKey identifies the set of backups metrics are aggregated on
+type backupMetricsKey struct {
+	namespace string
+	cluster   string
+	method    apiv1.BackupMethod
+}
+
+// backupMetricsValue contains the aggregated metrics of a set of backups
+type backupMetricsValue struct {
+	completed        int
+	size             int64
+	deduplicatedSize int64
+	lastStoppedAt    time.Time
+	lastDuration     time.Duration
+}
+
+// backupMetricsCollector exports the per-cluster totals of the completed
+// backups, to help the capacity planning of the backup storage
+type backupMetricsCollector struct {
+	cli client.Reader
+}
+
+// Describe implements prometheus.Collector
+func (c *backupMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- backupCompletedDesc
+	ch <- backupSizeDesc
+	ch <- backupDeduplicatedSizeDesc
+	ch <- backupLastDurationDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *backupMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), backupMetricsTimeout)
+	defer cancel()
+
+	var backupList apiv1.BackupList
+	if err := c.cli.List(ctx, &backupList); err != nil {
+		log.Error(err, "while listing backups to collect metrics")
+		return
+	}
+
+	for key, value := range aggregateBackupMetrics(backupList.Items) {
+		labels := []string{key.namespace, key.cluster, string(key.method)}
+		ch <- prometheus.MustNewConstMetric(
+			backupCompletedDesc, prometheus.GaugeValue, float64(value.completed), labels...)
+		ch <- prometheus.MustNewConstMetric(
+			backupSizeDesc, prometheus.GaugeValue, float64(value.size), labels...)
+		ch <- prometheus.MustNewConstMetric(
+			backupDeduplicatedSizeDesc, prometheus.GaugeValue, float64(value.deduplicatedSize), labels...)
+		ch <- prometheus.MustNewConstMetric(
+			backupLastDurationDesc, prometheus.GaugeValue, value.lastDuration.Seconds(), labels...)
+	}
+}
+
+// aggregateBackupMetrics computes the metrics of the completed backups,
+// grouped by cluster and backup method
+func aggregateBackupMetrics(backups []apiv1.Backup) map[backupMetricsKey]*backupMetricsValue {
+	result := make(map[backupMetricsKey]*backupMetricsValue)
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+			continue
+		}
+
+		key := backupMetricsKey{
+			namespace: backup.Namespace,
+			cluster:   backup.Spec.Cluster.Name,
+			method:    backup.Status.Method,
+		}
+		value, ok := result[key]
+		if !ok {
+			value = &backupMetricsValue{}
+			result[key] = value
+		}
+
+		value.completed++
+		if backup.Status.Size != nil {
+			value.size += *backup.Status.Size
+		}
+		if backup.Status.DeduplicatedSize != nil {
+			value.deduplicatedSize += *backup.Status.DeduplicatedSize
+		}
+		if backup.Status.StoppedAt != nil && backup.Status.StoppedAt.After(value.lastStoppedAt) {
+			value.lastStoppedAt = backup.Status.StoppedAt.Time
+			value.lastDuration = 0
+			if backup.Status.Duration != nil {
+				value.lastDuration = backup.Status.Duration.Duration
+			}
+		}
+	}
+
+	return result
+}
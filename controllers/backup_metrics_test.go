@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("aggregateBackupMetrics", func() {
+	now := time.Now()
+
+	newBackup := func(
+		cluster string,
+		phase apiv1.BackupPhase,
+		size int64,
+		stoppedAt time.Time,
+		duration time.Duration,
+	) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster},
+			},
+			Status: apiv1.BackupStatus{
+				Phase:            phase,
+				Method:           apiv1.BackupMethodBarmanObjectStore,
+				Size:             ptr.To(size),
+				DeduplicatedSize: ptr.To(size / 2),
+				StoppedAt:        ptr.To(metav1.NewTime(stoppedAt)),
+				Duration:         &metav1.Duration{Duration: duration},
+			},
+		}
+	}
+
+	It("aggregates the completed backups by cluster", func() {
+		result := aggregateBackupMetrics([]apiv1.Backup{
+			newBackup("cluster-example", apiv1.BackupPhaseCompleted, 100, now.Add(-2*time.Hour), time.Minute),
+			newBackup("cluster-example", apiv1.BackupPhaseCompleted, 200, now.Add(-time.Hour), 2*time.Minute),
+			newBackup("cluster-example", apiv1.BackupPhaseFailed, 400, now, 3*time.Minute),
+			newBackup("cluster-other", apiv1.BackupPhaseCompleted, 50, now, 4*time.Minute),
+		})
+		Expect(result).To(HaveLen(2))
+
+		value := result[backupMetricsKey{
+			namespace: "default",
+			cluster:   "cluster-example",
+			method:    apiv1.BackupMethodBarmanObjectStore,
+		}]
+		Expect(value).ToNot(BeNil())
+		Expect(value.completed).To(Equal(2))
+		Expect(value.size).To(BeEquivalentTo(300))
+		Expect(value.deduplicatedSize).To(BeEquivalentTo(150))
+		Expect(value.lastDuration).To(Equal(2 * time.Minute))
+	})
+
+	It("doesn't export metrics for clusters without completed backups", func() {
+		result := aggregateBackupMetrics([]apiv1.Backup{
+			newBackup("cluster-example", apiv1.BackupPhaseRunning, 100, now, time.Minute),
+		})
+		Expect(result).To(BeEmpty())
+	})
+})
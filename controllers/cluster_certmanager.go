@@ -0,0 +1,196 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// certManagerCertificateNameAnnotation is the annotation set by cert-manager
+// on the secrets it issues, containing the name of their Certificate
+const certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+// reconcileCertManagerCertificates ensures that the cert-manager certificates
+// are defined for every cluster certificate issued through a cert-manager
+// issuer, deleting the ones whose issuer has been removed
+func (r *ClusterReconciler) reconcileCertManagerCertificates(ctx context.Context, cluster *apiv1.Cluster) error {
+	certificates := []struct {
+		secretName  string
+		certificate *unstructured.Unstructured
+	}{
+		{
+			secretName:  cluster.GetServerTLSSecretName(),
+			certificate: specs.BuildServerCertManagerCertificate(cluster),
+		},
+		{
+			secretName:  cluster.GetReplicationSecretName(),
+			certificate: specs.BuildReplicationCertManagerCertificate(cluster),
+		},
+	}
+
+	for _, item := range certificates {
+		if item.certificate == nil {
+			if err := r.deleteUnusedCertManagerCertificate(ctx, cluster, item.secretName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.createOrPatchCertManagerCertificate(ctx, cluster, item.certificate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createOrPatchCertManagerCertificate creates or patches a cert-manager
+// certificate, checking that cert-manager already issued its secret.
+// When the secret exists, failing to reconcile the certificate doesn't
+// prevent the cluster from working, and is only logged
+func (r *ClusterReconciler) createOrPatchCertManagerCertificate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	certificate *unstructured.Unstructured,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	// The secret is issued asynchronously by cert-manager: until it exists
+	// we can't proceed with the rest of the PKI
+	secretName := certificate.GetName()
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	secretExists := err == nil
+
+	oldCertificate := &unstructured.Unstructured{}
+	oldCertificate.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+	err = r.Get(ctx, client.ObjectKeyFromObject(certificate), oldCertificate)
+	switch {
+	case meta.IsNoMatchError(err) && secretExists:
+		contextLogger.Warning("cert-manager is not installed, keeping the existing secret",
+			"certificate", certificate.GetName(), "err", err)
+		return nil
+
+	case meta.IsNoMatchError(err):
+		return fmt.Errorf("cert-manager is not installed, cannot create Certificate %s: %w",
+			certificate.GetName(), err)
+
+	case apierrs.IsNotFound(err):
+		r.Recorder.Event(cluster, "Normal", "CreatingCertificate",
+			fmt.Sprintf("Creating cert-manager Certificate %s", certificate.GetName()))
+		if err := r.Create(ctx, certificate); err != nil {
+			return fmt.Errorf("while creating cert-manager Certificate: %w", err)
+		}
+
+	case err != nil && secretExists:
+		contextLogger.Warning("cannot get the cert-manager Certificate, keeping the existing secret",
+			"certificate", certificate.GetName(), "err", err)
+		return nil
+
+	case err != nil:
+		return fmt.Errorf("while getting cert-manager Certificate: %w", err)
+
+	default:
+		if owner, owned := IsOwnedByCluster(oldCertificate); !owned || owner != cluster.Name {
+			return fmt.Errorf("cert-manager Certificate %s already exists and is not owned by the cluster",
+				certificate.GetName())
+		}
+
+		patchedCertificate := oldCertificate.DeepCopy()
+		for key, value := range certificate.Object["spec"].(map[string]interface{}) {
+			if err := unstructured.SetNestedField(patchedCertificate.Object, value, "spec", key); err != nil {
+				return err
+			}
+		}
+
+		if !reflect.DeepEqual(patchedCertificate.Object["spec"], oldCertificate.Object["spec"]) {
+			r.Recorder.Event(cluster, "Normal", "UpdatingCertificate",
+				fmt.Sprintf("Updating cert-manager Certificate %s", certificate.GetName()))
+			if err := r.Patch(ctx, patchedCertificate, client.MergeFrom(oldCertificate)); err != nil {
+				return fmt.Errorf("while patching cert-manager Certificate: %w", err)
+			}
+		}
+	}
+
+	if !secretExists {
+		contextLogger.Info("Waiting for cert-manager to issue the secret", "secret", secretName)
+		return fmt.Errorf("waiting for cert-manager to issue the %s secret: %w", secretName, ErrNextLoop)
+	}
+
+	return nil
+}
+
+// deleteUnusedCertManagerCertificate deletes the cert-manager Certificate
+// which issued the passed secret, when it is owned by the cluster, as the
+// cluster doesn't refer to an issuer for that secret anymore.
+// The secret is kept, and is then handled as any other secret
+func (r *ClusterReconciler) deleteUnusedCertManagerCertificate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secretName string,
+) error {
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	certificateName, issuedByCertManager := secret.Annotations[certManagerCertificateNameAnnotation]
+	if !issuedByCertManager {
+		return nil
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: certificateName}, certificate)
+	if meta.IsNoMatchError(err) || apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting cert-manager Certificate: %w", err)
+	}
+
+	if owner, owned := IsOwnedByCluster(certificate); !owned || owner != cluster.Name {
+		return nil
+	}
+
+	r.Recorder.Event(cluster, "Normal", "DeletingCertificate",
+		fmt.Sprintf("Deleting cert-manager Certificate %s, as its issuer has been removed", certificateName))
+	if err := r.Delete(ctx, certificate); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting cert-manager Certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager certificates", func() {
+	const secretName = "cluster-example-server-tls"
+
+	var (
+		ctx     context.Context
+		cluster *apiv1.Cluster
+	)
+
+	newReconciler := func(funcs *interceptor.Funcs, objects ...k8client.Object) *ClusterReconciler {
+		builder := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(objects...)
+		if funcs != nil {
+			builder = builder.WithInterceptorFuncs(*funcs)
+		}
+		return &ClusterReconciler{
+			Client:   builder.Build(),
+			Recorder: record.NewFakeRecorder(100),
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	}
+
+	newSecret := func(annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secretName,
+				Namespace:   cluster.Namespace,
+				Annotations: annotations,
+			},
+		}
+	}
+
+	getCertificate := func(r *ClusterReconciler) (*unstructured.Unstructured, error) {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(specs.CertManagerCertificateGVK)
+		err := r.Get(ctx, k8client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, certificate)
+		return certificate, err
+	}
+
+	issuerName := func(certificate *unstructured.Unstructured) string {
+		name, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+		return name
+	}
+
+	// noMatch makes cert-manager look not installed
+	noMatch := &interceptor.Funcs{
+		Get: func(
+			ctx context.Context,
+			client k8client.WithWatch,
+			key k8client.ObjectKey,
+			obj k8client.Object,
+			opts ...k8client.GetOption,
+		) error {
+			if obj.GetObjectKind().GroupVersionKind() == specs.CertManagerCertificateGVK {
+				return &meta.NoKindMatchError{
+					GroupKind: specs.CertManagerCertificateGVK.GroupKind(),
+				}
+			}
+			return client.Get(ctx, key, obj, opts...)
+		},
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					ServerTLSSecret: secretName,
+					ServerCASecret:  secretName,
+					ServerIssuerRef: &apiv1.CertManagerIssuerReference{Name: "ca-issuer"},
+				},
+			},
+		}
+	})
+
+	It("creates the certificate and waits for its secret", func() {
+		r := newReconciler(nil)
+		err := r.createOrPatchCertManagerCertificate(ctx, cluster, specs.BuildServerCertManagerCertificate(cluster))
+		Expect(err).To(MatchError(ErrNextLoop))
+
+		certificate, err := getCertificate(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issuerName(certificate)).To(Equal("ca-issuer"))
+	})
+
+	It("patches the certificate when the issuer changes", func() {
+		existing := specs.BuildServerCertManagerCertificate(cluster)
+		r := newReconciler(nil, existing, newSecret(nil))
+
+		cluster.Spec.Certificates.ServerIssuerRef.Name = "other-issuer"
+		Expect(r.createOrPatchCertManagerCertificate(ctx, cluster, specs.BuildServerCertManagerCertificate(cluster))).
+			To(Succeed())
+
+		certificate, err := getCertificate(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issuerName(certificate)).To(Equal("other-issuer"))
+	})
+
+	It("refuses to overwrite a certificate not owned by the cluster", func() {
+		existing := specs.BuildServerCertManagerCertificate(cluster)
+		existing.SetOwnerReferences(nil)
+		r := newReconciler(nil, existing, newSecret(nil))
+
+		cluster.Spec.Certificates.ServerIssuerRef.Name = "other-issuer"
+		err := r.createOrPatchCertManagerCertificate(ctx, cluster, specs.BuildServerCertManagerCertificate(cluster))
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ErrNextLoop))
+
+		certificate, err := getCertificate(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issuerName(certificate)).To(Equal("ca-issuer"))
+	})
+
+	It("keeps working with the existing secret when cert-manager is not available", func() {
+		r := newReconciler(noMatch, newSecret(nil))
+		Expect(r.createOrPatchCertManagerCertificate(ctx, cluster, specs.BuildServerCertManagerCertificate(cluster))).
+			To(Succeed())
+	})
+
+	It("fails when cert-manager is not available and the secret is missing", func() {
+		r := newReconciler(noMatch)
+		err := r.createOrPatchCertManagerCertificate(ctx, cluster, specs.BuildServerCertManagerCertificate(cluster))
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(ErrNextLoop))
+	})
+
+	It("deletes the certificate owned by the cluster when the issuer is removed", func() {
+		existing := specs.BuildServerCertManagerCertificate(cluster)
+		r := newReconciler(nil, existing,
+			newSecret(map[string]string{certManagerCertificateNameAnnotation: secretName}))
+
+		cluster.Spec.Certificates.ServerIssuerRef = nil
+		Expect(r.reconcileCertManagerCertificates(ctx, cluster)).To(Succeed())
+
+		_, err := getCertificate(r)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("keeps the certificate not owned by the cluster when the issuer is removed", func() {
+		existing := specs.BuildServerCertManagerCertificate(cluster)
+		existing.SetOwnerReferences(nil)
+		r := newReconciler(nil, existing,
+			newSecret(map[string]string{certManagerCertificateNameAnnotation: secretName}))
+
+		cluster.Spec.Certificates.ServerIssuerRef = nil
+		Expect(r.reconcileCertManagerCertificates(ctx, cluster)).To(Succeed())
+
+		_, err := getCertificate(r)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	goruntime "runtime"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -39,15 +40,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backgroundtask"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	instanceReconciler "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/lsntracker"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/rolloutqueue"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
@@ -73,6 +79,10 @@ type ClusterReconciler struct {
 	Recorder        record.EventRecorder
 
 	*instance.StatusClient
+
+	rolloutManager  *rolloutqueue.Manager
+	primaryLSNs     *lsntracker.Tracker
+	switchoverHooks *backgroundtask.Runner
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -83,6 +93,9 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
 		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg"),
+		rolloutManager:  rolloutqueue.NewManager(configuration.Current.MaxConcurrentRollouts),
+		primaryLSNs:     lsntracker.NewTracker(),
+		switchoverHooks: backgroundtask.NewRunner(),
 	}
 }
 
@@ -94,12 +107,15 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
-// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;patch;delete
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
@@ -115,6 +131,7 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile is the operator reconcile loop
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -132,6 +149,9 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		r.rolloutManager.Release(req.NamespacedName)
+		r.primaryLSNs.Forget(req.NamespacedName)
+		r.switchoverHooks.Forget(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -140,14 +160,38 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				"namespace", req.Namespace,
 			)
 		}
+		if err := r.deleteDatabaseFinalizers(ctx, req.NamespacedName); err != nil {
+			contextLogger.Error(
+				err,
+				"error while deleting finalizers of databases",
+				"clusterName", req.Name,
+				"namespace", req.Namespace,
+			)
+		}
 		return ctrl.Result{}, err
 	}
 
+	ctx, span := tracing.StartClusterSpan(ctx, "Cluster.Reconcile", cluster)
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
+
+	// Attach the read-write service to the new primary as soon
+	// as the fencing gap has elapsed
+	if _, remaining := getReadWriteServiceFencing(cluster, time.Now()); remaining > 0 &&
+		!result.Requeue && (result.RequeueAfter == 0 || result.RequeueAfter > remaining) {
+		result.RequeueAfter = remaining
+	}
+	// Renew the primary Lease before it expires
+	if renewInterval := cluster.GetPrimaryLeaseDuration() / 3; cluster.IsPrimaryLeaseEnabled() &&
+		!result.Requeue && (result.RequeueAfter == 0 || result.RequeueAfter > renewInterval) {
+		result.RequeueAfter = renewInterval
+	}
 	if errors.Is(err, ErrNextLoop) {
+		tracing.EndSpan(span, nil)
 		return result, nil
 	}
+	tracing.EndSpan(span, err)
 	return result, err
 }
 
@@ -156,6 +200,12 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluster) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// The PVCs of a deleted cluster are released even when the reconciliation
+	// is disabled, as the finalizer would otherwise block the deletion
+	if deleted, err := r.reconcilePVCRetention(ctx, cluster); err != nil || deleted {
+		return ctrl.Result{}, err
+	}
+
 	if utils.IsReconciliationDisabled(&cluster.ObjectMeta) {
 		contextLogger.Warning("Disable reconciliation loop annotation set, skipping the reconciliation.")
 		return ctrl.Result{}, nil
@@ -188,6 +238,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Ensure we have the required global objects
 	if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
+		if errors.Is(err, ErrNextLoop) {
+			// Some of the objects, like the secrets issued by
+			// cert-manager, are not ready yet
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, err
+		}
 		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
 	}
 
@@ -210,6 +265,21 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
 	}
 
+	// Move the role labels as soon as the current primary changes, without
+	// waiting for the status of the instances, so that the services are
+	// routed to the new primary with the smallest possible delay
+	if err := instanceReconciler.ReconcileRoleLabels(ctx, r.Client, cluster, resources.instances); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Upgrade the data directory when a new major version of PostgreSQL is requested
+	if result, err := r.reconcileMajorUpgrade(ctx, cluster, resources); result != nil || err != nil {
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return *result, nil
+	}
+
 	if cluster.Status.CurrentPrimary != "" &&
 		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
 		contextLogger.Info("There is a switchover or a failover "+
@@ -221,7 +291,10 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 	}
 
 	// Get the replication status
-	instancesStatus := r.StatusClient.GetStatusFromInstances(ctx, resources.instances)
+	statusCtx, statusSpan := tracing.StartClusterSpan(ctx, "GetStatusFromInstances", cluster)
+	instancesStatus := r.StatusClient.GetStatusFromInstances(statusCtx, resources.instances)
+	tracing.EndSpan(statusSpan, instancesStatus.Errors())
+	r.trackPrimaryLSN(cluster, instancesStatus)
 
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
@@ -237,6 +310,14 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileGeneratedObjectsMetadata(ctx, cluster, resources); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePrimaryLease(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the primary Lease: %w", err)
+	}
+
 	if instancesStatus.AllReadyInstancesStatusUnreachable() {
 		contextLogger.Warning(
 			"Failed to extract instance status from ready instances. Attempting to requeue...",
@@ -381,6 +462,10 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrFailoverDataLossExceeded) {
+			contextLogger.Info("Failover blocked, waiting for the user to allow the data loss")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"error", err)
@@ -393,6 +478,11 @@ func (r *ClusterReconciler) handleSwitchover(
 		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// Drive the planned switchover that has been announced, if any
+	if result, err := r.reconcilePlannedSwitchover(ctx, cluster, instancesStatus); result != nil || err != nil {
+		return result, err
+	}
+
 	// Primary is healthy, No switchover in progress.
 	// If we have a currentPrimaryFailingSince timestamp, let's unset it.
 	if cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" {
@@ -500,12 +590,18 @@ func (r *ClusterReconciler) reconcileResources(
 			// to an unready instance.
 			contextLogger.Debug("An instance is not ready. Pausing reconciliation...")
 
-			// Register a phase indicating some instances aren't active yet
+			// Register a phase indicating some instances aren't active yet,
+			// reporting why the scheduler cannot place them if that's the case
+			reason := "Some instances are not yet active. Please wait."
+			if unschedulable := resources.getUnschedulablePods(); len(unschedulable) > 0 {
+				reason = fmt.Sprintf("Some instances are not yet active, pods that cannot be scheduled: %s",
+					strings.Join(unschedulable, ", "))
+			}
 			if err := r.RegisterPhase(
 				ctx,
 				cluster,
 				apiv1.PhaseWaitingForInstancesToBeActive,
-				"Some instances are not yet active. Please wait.",
+				reason,
 			); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -518,6 +614,7 @@ func (r *ClusterReconciler) reconcileResources(
 	if res, err := persistentvolumeclaim.Reconcile(
 		ctx,
 		r.Client,
+		r.Recorder,
 		cluster,
 		resources.instances.Items,
 		resources.pvcs.Items,
@@ -685,7 +782,10 @@ func (r *ClusterReconciler) reconcilePods(ctx context.Context, cluster *apiv1.Cl
 	// 3 - We have already some Pods, all they all ready ==> we can create the other
 	// pods joining the node that we already have.
 	if cluster.Status.Instances == 0 {
-		return r.createPrimaryInstance(ctx, cluster)
+		jobCtx, jobSpan := tracing.StartClusterSpan(ctx, "CreatePrimaryInstance", cluster)
+		result, err := r.createPrimaryInstance(jobCtx, cluster)
+		tracing.EndSpan(jobSpan, err)
+		return result, err
 	}
 
 	// Stop acting here if there are non-ready Pods unless in maintenance reusing PVCs.
@@ -703,7 +803,10 @@ func (r *ClusterReconciler) reconcilePods(ctx context.Context, cluster *apiv1.Cl
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
 		}
-		return r.joinReplicaInstance(ctx, newNodeSerial, cluster)
+		jobCtx, jobSpan := tracing.StartClusterSpan(ctx, "JoinReplicaInstance", cluster)
+		result, err := r.joinReplicaInstance(jobCtx, newNodeSerial, cluster)
+		tracing.EndSpan(jobSpan, err)
+		return result, err
 	}
 
 	// Are there nodes to be removed? Remove one of them
@@ -724,7 +827,7 @@ func (r *ClusterReconciler) reconcilePods(ctx context.Context, cluster *apiv1.Cl
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
-	return r.handleRollingUpdate(ctx, cluster, instancesStatus)
+	return r.handleRollingUpdate(ctx, cluster, resources, instancesStatus)
 }
 
 func (r *ClusterReconciler) ensureHealthyPVCsAnnotation(
@@ -762,6 +865,7 @@ func (r *ClusterReconciler) ensureHealthyPVCsAnnotation(
 func (r *ClusterReconciler) handleRollingUpdate(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
+	resources *managedResources,
 	instancesStatus postgres.PostgresqlStatusList,
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
@@ -772,8 +876,24 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		return ctrl.Result{}, err
 	}
 	if done {
-		// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
-		return ctrl.Result{}, ErrNextLoop
+		return getRolloutInProgressResult(cluster)
+	}
+
+	// If we need to recreate any instance on a new storage class, it is
+	// done after the rollout of the instances
+	done, err = r.migrateStorageClass(ctx, cluster, resources, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if done {
+		return getRolloutInProgressResult(cluster)
+	}
+
+	// No instance requires a rollout, the slot can be used by other clusters
+	r.rolloutManager.Release(client.ObjectKeyFromObject(cluster))
+
+	if err := r.updateResourcesChecksumAnnotation(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
@@ -800,9 +920,33 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
+	// Keep track of the PVCs being resized, as the ones whose file system
+	// is not expanded in time require their instance to be restarted
+	if len(cluster.Status.ResizingPVC) > 0 {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// getRolloutInProgressResult gets the result of the reconciliation loop
+// while the instances are being rolled out
+func getRolloutInProgressResult(cluster *apiv1.Cluster) (ctrl.Result, error) {
+	switch cluster.Status.Phase {
+	case apiv1.PhaseWaitingForRolloutSlot:
+		// The cluster is waiting for a free rollout slot, let's check again later
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, ErrNextLoop
+	case apiv1.PhaseWaitingForMaintenanceWindow:
+		// Let's check again when the next maintenance window starts
+		now := time.Now()
+		requeueAfter := max(cluster.GetNextMaintenanceWindowStart(now).Sub(now), time.Second)
+		return ctrl.Result{RequeueAfter: requeueAfter}, ErrNextLoop
+	}
+
+	// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
+	return ctrl.Result{}, ErrNextLoop
+}
+
 // SetupWithManager creates a ClusterReconciler
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	err := r.createFieldIndexes(ctx, mgr)
@@ -810,6 +954,10 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return err
 	}
 
+	if err := metrics.Registry.Register(&clusterHealthMetricsCollector{cli: mgr.GetClient()}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
 		Owns(&corev1.Pod{}).
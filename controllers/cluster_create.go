@@ -81,7 +81,7 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
-	err = r.createRoleBinding(ctx, cluster)
+	err = r.createOrPatchRoleBinding(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -174,15 +174,7 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 		if err != nil {
 			return err
 		}
-		postgresSecret := specs.CreateSecret(
-			cluster.GetSuperuserSecretName(),
-			cluster.Namespace,
-			cluster.GetServiceReadWriteName(),
-			"*",
-			"postgres",
-			postgresPassword)
-		cluster.SetInheritedDataAndOwnership(&postgresSecret.ObjectMeta)
-
+		postgresSecret := specs.CreateSuperuserSecret(cluster, postgresPassword)
 		return createOrPatchClusterCredentialSecret(ctx, r.Client, postgresSecret)
 	}
 
@@ -214,15 +206,7 @@ func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster
 		if err != nil {
 			return err
 		}
-		appSecret := specs.CreateSecret(
-			cluster.GetApplicationSecretName(),
-			cluster.Namespace,
-			cluster.GetServiceReadWriteName(),
-			cluster.GetApplicationDatabaseName(),
-			cluster.GetApplicationDatabaseOwner(),
-			appPassword)
-
-		cluster.SetInheritedDataAndOwnership(&appSecret.ObjectMeta)
+		appSecret := specs.CreateApplicationSecret(cluster, appPassword)
 		return createOrPatchClusterCredentialSecret(ctx, r.Client, appSecret)
 	}
 	return nil
@@ -298,36 +282,223 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) reconcilePostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
-	if configuration.Current.CreateAnyService {
-		anyService := specs.CreateClusterAnyService(*cluster)
-		cluster.SetInheritedDataAndOwnership(&anyService.ObjectMeta)
+	var instanceNames []string
+	if cluster.AreInstanceServicesEnabled() {
+		instanceNames = cluster.Status.InstanceNames
+	}
+	fenced, _ := getReadWriteServiceFencing(cluster, time.Now())
 
-		if err := r.serviceReconciler(ctx, anyService); err != nil {
-			return err
+	for _, service := range specs.CreateClusterServices(
+		cluster, configuration.Current.CreateAnyService, fenced, instanceNames) {
+		switch service.Name {
+		case cluster.GetServiceReadWriteName():
+			if err := r.reconcileReadWriteService(ctx, cluster, service); err != nil {
+				return err
+			}
+
+		case cluster.GetServiceReadName(), cluster.GetServiceReadOnlyName(), cluster.GetServiceDesignatedPrimaryName():
+			if err := r.serviceReconciler(ctx, service); err != nil {
+				return err
+			}
+
+		default:
+			// The type of the -any and of the instance services can't be changed in place
+			if err := r.deleteServiceWithOutdatedType(ctx, service); err != nil {
+				return err
+			}
+
+			if err := r.serviceReconciler(ctx, service); err != nil {
+				return err
+			}
 		}
 	}
 
-	readService := specs.CreateClusterReadService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readService.ObjectMeta)
+	if err := r.deleteUnneededPrimaryService(ctx, cluster); err != nil {
+		return err
+	}
 
-	if err := r.serviceReconciler(ctx, readService); err != nil {
+	return r.deleteUnneededInstanceServices(ctx, cluster, instanceNames)
+}
+
+// deleteUnneededPrimaryService deletes the -rw service of a permanent
+// replica cluster, which is replaced by the -designated one, or the
+// -designated service once the cluster is not a permanent replica anymore
+func (r *ClusterReconciler) deleteUnneededPrimaryService(ctx context.Context, cluster *apiv1.Cluster) error {
+	unneededService := cluster.GetServiceDesignatedPrimaryName()
+	if cluster.IsPermanentReplica() {
+		unneededService = cluster.GetServiceReadWriteName()
+	}
+
+	var service corev1.Service
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: unneededService}, &service)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readOnlyService.ObjectMeta)
+	if _, owned := IsOwnedByCluster(&service); !owned {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting unneeded primary service", "service", service.Name)
+	if err := r.Delete(ctx, &service); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting service %s: %w", service.Name, err)
+	}
+
+	return nil
+}
+
+// reconcileReadWriteService ensures that the read-write Service selects the
+// primary, or no instance at all while the read-write fencing gap requires it,
+// raising an event when this changes
+func (r *ClusterReconciler) reconcileReadWriteService(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	readWriteService *corev1.Service,
+) error {
+	var livingService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(readWriteService), &livingService)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	wasDetached := err == nil && specs.IsDetachedReadWriteService(&livingService)
+
+	if err := r.serviceReconciler(ctx, readWriteService); err != nil {
+		return err
+	}
+
+	isDetached := specs.IsDetachedReadWriteService(readWriteService)
+	switch {
+	case isDetached && !wasDetached:
+		log.FromContext(ctx).Info("Detaching the read-write service from the primary",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary)
+		r.Recorder.Eventf(cluster, "Normal", "ReadWriteServiceDetached",
+			"Detached the read-write service from %s at %s",
+			cluster.Status.CurrentPrimary, utils.GetCurrentTimestamp())
+	case !isDetached && wasDetached:
+		log.FromContext(ctx).Info("Attaching the read-write service to the primary",
+			"currentPrimary", cluster.Status.CurrentPrimary)
+		r.Recorder.Eventf(cluster, "Normal", "ReadWriteServiceAttached",
+			"Attached the read-write service to %s at %s",
+			cluster.Status.CurrentPrimary, utils.GetCurrentTimestamp())
+	}
+
+	return nil
+}
+
+// getReadWriteServiceFencing checks if the read-write Service must not select
+// any instance, returning how long it still needs to be detached once the new
+// primary has been promoted
+func getReadWriteServiceFencing(cluster *apiv1.Cluster, now time.Time) (bool, time.Duration) {
+	gap := cluster.GetReadWriteFencingGap()
+	if gap == 0 {
+		return false, 0
+	}
+
+	// A failover or a switchover is in progress
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		return true, 0
+	}
+
+	promotedAt, err := time.Parse(metav1.RFC3339Micro, cluster.Status.CurrentPrimaryTimestamp)
+	if err != nil {
+		return false, 0
+	}
+
+	remaining := promotedAt.Add(gap).Sub(now)
+	if remaining <= 0 {
+		return false, 0
+	}
+
+	return true, remaining
+}
+
+// deleteUnneededInstanceServices removes the instance Services
+// which are not needed anymore, keeping the ones of the passed instances
+func (r *ClusterReconciler) deleteUnneededInstanceServices(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instanceNames []string,
+) error {
+	var serviceList corev1.ServiceList
+	if err := r.List(
+		ctx,
+		&serviceList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.InstanceNameLabelName},
+	); err != nil {
+		return fmt.Errorf("while listing instance services: %w", err)
+	}
+
+	for idx := range serviceList.Items {
+		service := &serviceList.Items[idx]
+		if slices.Contains(instanceNames, service.Name) {
+			continue
+		}
+
+		if _, owned := IsOwnedByCluster(service); !owned {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Deleting unneeded instance service", "service", service.Name)
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting instance service %s: %w", service.Name, err)
+		}
+	}
 
-	if err := r.serviceReconciler(ctx, readOnlyService); err != nil {
+	return nil
+}
+
+// deleteServiceWithOutdatedType deletes the living Service if its type
+// doesn't match the proposed one, so that it can be recreated.
+// Switching between service types in place would require the operator to
+// take care of the allocated node ports and load balancer fields, while
+// the cluster IP of a Service cannot be changed to make it headless
+func (r *ClusterReconciler) deleteServiceWithOutdatedType(
+	ctx context.Context,
+	proposed *corev1.Service,
+) error {
+	var livingService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(proposed), &livingService)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readWriteService.ObjectMeta)
+	if livingService.Spec.Type == proposed.Spec.Type && isHeadlessService(&livingService) == isHeadlessService(proposed) {
+		return nil
+	}
+
+	if _, owned := IsOwnedByCluster(&livingService); !owned {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Recreating service to change its type",
+		"service", livingService.Name,
+		"currentType", livingService.Spec.Type,
+		"type", proposed.Spec.Type,
+		"headless", isHeadlessService(proposed))
+	if err := r.Delete(ctx, &livingService); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting service %s: %w", livingService.Name, err)
+	}
 
-	return r.serviceReconciler(ctx, readWriteService)
+	return nil
+}
+
+// isHeadlessService checks if the Service has no cluster IP
+func isHeadlessService(service *corev1.Service) bool {
+	return service.Spec.ClusterIP == corev1.ClusterIPNone
 }
 
 func (r *ClusterReconciler) serviceReconciler(ctx context.Context, proposed *corev1.Service) error {
+	utils.SetSpecHash(&proposed.ObjectMeta, proposed.Spec)
+
 	var livingService corev1.Service
 	err := r.Client.Get(ctx, types.NamespacedName{Name: proposed.Name, Namespace: proposed.Namespace}, &livingService)
 	if apierrs.IsNotFound(err) {
@@ -506,20 +677,11 @@ func (r *ClusterReconciler) createServiceAccount(ctx context.Context, cluster *a
 		return fmt.Errorf("while generating pull secret names: %w", err)
 	}
 
-	serviceAccount := &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: cluster.Namespace,
-			Name:      cluster.Name,
-		},
-	}
-	err = specs.UpdateServiceAccount(generatedPullSecretNames, serviceAccount)
+	serviceAccount, err := specs.CreateServiceAccount(cluster, generatedPullSecretNames)
 	if err != nil {
 		return fmt.Errorf("while creating new ServiceAccount: %w", err)
 	}
 
-	cluster.SetInheritedDataAndOwnership(&serviceAccount.ObjectMeta)
-	cluster.Spec.ServiceAccountTemplate.MergeMetadata(serviceAccount)
-
 	err = r.Create(ctx, serviceAccount)
 	if err != nil && !apierrs.IsAlreadyExists(err) {
 		return err
@@ -615,17 +777,21 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 	}
 
 	generatedRole := specs.CreateRole(*cluster, originBackup)
-	if reflect.DeepEqual(generatedRole.Rules, role.Rules) {
-		// Everything fine, the two config maps are exactly the same
+	cluster.SetInheritedData(&generatedRole.ObjectMeta)
+	if reflect.DeepEqual(generatedRole.Rules, role.Rules) &&
+		utils.IsMapSubset(role.Labels, generatedRole.Labels) &&
+		utils.IsMapSubset(role.Annotations, generatedRole.Annotations) {
+		// Everything fine, the two roles are exactly the same
 		return nil
 	}
 
 	r.Recorder.Event(cluster, "Normal", "UpdatingRole", "Updating Cluster Role")
 
 	// The configuration changed, and we need the patch the
-	// configMap we have
-	patchedRole := role
+	// role we have
+	patchedRole := *role.DeepCopy()
 	patchedRole.Rules = generatedRole.Rules
+	utils.MergeObjectsMetadata(&patchedRole, &generatedRole)
 	if err := r.Patch(ctx, &patchedRole, client.MergeFrom(&role)); err != nil {
 		return fmt.Errorf("while patching role: %w", err)
 	}
@@ -917,15 +1083,39 @@ func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Clust
 	return nil
 }
 
-// createRoleBinding creates the role binding
-func (r *ClusterReconciler) createRoleBinding(ctx context.Context, cluster *apiv1.Cluster) error {
-	roleBinding := specs.CreateRoleBinding(cluster.ObjectMeta)
-	cluster.SetInheritedDataAndOwnership(&roleBinding.ObjectMeta)
+// createOrPatchRoleBinding ensures that the role binding exists and
+// is aligned with the one generated by the operator
+func (r *ClusterReconciler) createOrPatchRoleBinding(ctx context.Context, cluster *apiv1.Cluster) error {
+	generatedRoleBinding := specs.CreateRoleBinding(cluster.ObjectMeta)
+	cluster.SetInheritedDataAndOwnership(&generatedRoleBinding.ObjectMeta)
 
-	err := r.Create(ctx, &roleBinding)
-	if err != nil && !apierrs.IsAlreadyExists(err) {
-		log.FromContext(ctx).Error(err, "Unable to create the ServiceAccount", "object", roleBinding)
-		return err
+	var roleBinding rbacv1.RoleBinding
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&generatedRoleBinding), &roleBinding); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting role binding: %w", err)
+		}
+
+		err = r.Create(ctx, &generatedRoleBinding)
+		if err != nil && !apierrs.IsAlreadyExists(err) {
+			log.FromContext(ctx).Error(err, "Unable to create the RoleBinding", "object", generatedRoleBinding)
+			return err
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(generatedRoleBinding.Subjects, roleBinding.Subjects) &&
+		utils.IsMapSubset(roleBinding.Labels, generatedRoleBinding.Labels) &&
+		utils.IsMapSubset(roleBinding.Annotations, generatedRoleBinding.Annotations) {
+		return nil
+	}
+
+	// The role reference is immutable, and it never changes
+	// for a certain cluster
+	patchedRoleBinding := roleBinding.DeepCopy()
+	patchedRoleBinding.Subjects = generatedRoleBinding.Subjects
+	utils.MergeObjectsMetadata(patchedRoleBinding, &generatedRoleBinding)
+	if err := r.Patch(ctx, patchedRoleBinding, client.MergeFrom(&roleBinding)); err != nil {
+		return fmt.Errorf("while patching role binding: %w", err)
 	}
 
 	return nil
@@ -1049,18 +1239,22 @@ func (r *ClusterReconciler) createPrimaryInstance(
 				return ctrl.Result{}, err
 			}
 			r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from volumeSnapshots)")
-			job = specs.CreatePrimaryJobViaRestoreSnapshot(*cluster, nodeSerial, snapshot, backup)
+			job, err = specs.CreatePrimaryJobViaRestoreSnapshot(*cluster, nodeSerial, snapshot, backup)
 			break
 		}
 
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from backup)")
-		job = specs.CreatePrimaryJobViaRecovery(*cluster, nodeSerial, backup)
+		job, err = specs.CreatePrimaryJobViaRecovery(*cluster, nodeSerial, backup)
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil:
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from physical backup)")
-		job = specs.CreatePrimaryJobViaPgBaseBackup(*cluster, nodeSerial)
+		job, err = specs.CreatePrimaryJobViaPgBaseBackup(*cluster, nodeSerial)
 	default:
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (initdb)")
-		job = specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial)
+		job, err = specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial)
+	}
+	if err != nil {
+		contextLogger.Error(err, "Unable to create the job for the primary instance")
+		return ctrl.Result{}, err
 	}
 
 	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
@@ -1093,6 +1287,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.SetSpecHash(&job.ObjectMeta, job.Spec)
 
 	if err = r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1152,12 +1347,18 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		return ctrl.Result{}, err
 	}
 
-	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
-
 	// If we can bootstrap this replica from a pre-existing source, we do it
+	var job *batchv1.Job
+	var err error
 	storageSource := persistentvolumeclaim.GetCandidateStorageSourceForReplica(ctx, cluster, backupList)
 	if storageSource != nil {
-		job = specs.RestoreReplicaInstance(*cluster, nodeSerial)
+		job, err = specs.RestoreReplicaInstance(*cluster, nodeSerial)
+	} else {
+		job, err = specs.JoinReplicaInstance(*cluster, nodeSerial)
+	}
+	if err != nil {
+		contextLogger.Error(err, "Unable to create the job for the replica instance")
+		return ctrl.Result{}, err
 	}
 
 	contextLogger.Info("Creating new Job",
@@ -1190,6 +1391,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.SetSpecHash(&job.ObjectMeta, job.Spec)
 
 	if err := r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -18,10 +18,12 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,6 +40,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -161,6 +165,32 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("should make sure that reconcilePostgresServices recreates the any service to make it headless", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		configuration.Current.CreateAnyService = true
+
+		By("creating an any service with a cluster IP", func() {
+			svc := specs.CreateClusterAnyService(*cluster)
+			svc.Spec.ClusterIP = ""
+			cluster.SetInheritedDataAndOwnership(&svc.ObjectMeta)
+			Expect(k8sClient.Create(ctx, svc)).To(Succeed())
+		})
+
+		By("executing reconcilePostgresServices", func() {
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the any service is headless", func() {
+			var anyService corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.GetServiceAnyName(), namespace, &anyService)
+			Expect(anyService.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(anyService.Spec.PublishNotReadyAddresses).To(BeTrue())
+		})
+	})
+
 	It("should make sure that reconcilePostgresServices can update the selectors on existing services", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -187,6 +217,8 @@ var _ = Describe("cluster_create unit tests", func() {
 			Expect(afterChangesService.Spec.Selector).ToNot(Equal(before.Spec.Selector))
 			Expect(afterChangesService.Spec.Selector).To(Equal(expectedLabels))
 			Expect(afterChangesService.Labels).To(Equal(before.Labels))
+			Expect(afterChangesService.Annotations).To(HaveKey(utils.CNPGHashAnnotationName))
+			delete(afterChangesService.Annotations, utils.CNPGHashAnnotationName)
 			Expect(afterChangesService.Annotations).To(Equal(before.Annotations))
 		}
 
@@ -250,6 +282,155 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("should make sure that reconcilePostgresServices manages the instance services", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Status.InstanceNames = []string{cluster.Name + "-1", cluster.Name + "-2"}
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				InstanceServices: &apiv1.InstanceServicesConfiguration{
+					Enabled:        true,
+					ExternalDomain: "db.example.com",
+				},
+			},
+		}
+
+		By("executing reconcilePostgresServices with instance services enabled", func() {
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that a service has been created for each instance", func() {
+			for _, instanceName := range cluster.Status.InstanceNames {
+				var service corev1.Service
+				expectResourceExistsWithDefaultClient(instanceName, namespace, &service)
+				Expect(service.Spec.Selector[utils.InstanceNameLabelName]).To(Equal(instanceName))
+				Expect(service.Annotations[utils.ExternalDNSHostnameAnnotationName]).
+					To(Equal(instanceName + ".db.example.com"))
+			}
+		})
+
+		By("executing reconcilePostgresServices after an instance has been removed", func() {
+			cluster.Status.InstanceNames = []string{cluster.Name + "-1"}
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the service of the removed instance has been deleted", func() {
+			expectResourceExistsWithDefaultClient(cluster.Name+"-1", namespace, &corev1.Service{})
+			expectResourceDoesntExistWithDefaultClient(cluster.Name+"-2", namespace, &corev1.Service{})
+		})
+
+		By("executing reconcilePostgresServices after changing the services type", func() {
+			cluster.Spec.Managed.Services.InstanceServices.Type = corev1.ServiceTypeNodePort
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the instance service has been recreated with the new type", func() {
+			var service corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.Name+"-1", namespace, &service)
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		})
+
+		By("executing reconcilePostgresServices with instance services disabled", func() {
+			cluster.Spec.Managed.Services.InstanceServices.Enabled = false
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that every instance service has been deleted", func() {
+			expectResourceDoesntExistWithDefaultClient(cluster.Name+"-1", namespace, &corev1.Service{})
+		})
+	})
+
+	It("should make sure that reconcilePostgresServices detaches the read-write service", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{ReadWriteFencingGap: 30},
+		}
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		cluster.Status.TargetPrimary = cluster.Name + "-2"
+
+		By("executing reconcilePostgresServices while the primary is changing", func() {
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the read-write service doesn't select the primary", func() {
+			var service corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadWriteName(), namespace, &service)
+			Expect(specs.IsDetachedReadWriteService(&service)).To(BeTrue())
+		})
+
+		By("executing reconcilePostgresServices once the fencing gap has elapsed", func() {
+			cluster.Status.CurrentPrimary = cluster.Name + "-2"
+			cluster.Status.CurrentPrimaryTimestamp = time.Now().Add(-time.Minute).Format(metav1.RFC3339Micro)
+			err := clusterReconciler.reconcilePostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the read-write service selects the primary", func() {
+			var service corev1.Service
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadWriteName(), namespace, &service)
+			Expect(specs.IsDetachedReadWriteService(&service)).To(BeFalse())
+			Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelPrimary))
+		})
+	})
+
+	It("should make sure that reconcilePostgresServices replaces the -rw service of a permanent replica", func() {
+		ctx := context.Background()
+		cluster := &apiv1.Cluster{
+			TypeMeta:   metav1.TypeMeta{Kind: apiv1.ClusterKind, APIVersion: apiv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled:   true,
+					Permanent: true,
+					Source:    "cluster-origin",
+				},
+			},
+		}
+		readWriteService := specs.CreateClusterReadWriteService(*cluster)
+		cluster.SetInheritedDataAndOwnership(&readWriteService.ObjectMeta)
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(readWriteService).
+			Build()
+		r := &ClusterReconciler{Client: fakeClient, Recorder: record.NewFakeRecorder(10)}
+
+		By("executing reconcilePostgresServices on a permanent replica cluster", func() {
+			Expect(r.reconcilePostgresServices(ctx, cluster)).To(Succeed())
+		})
+
+		By("making sure that the -rw service has been replaced by the -designated one", func() {
+			var service corev1.Service
+			Expect(fakeClient.Get(ctx, k8client.ObjectKey{
+				Namespace: cluster.Namespace, Name: cluster.GetServiceDesignatedPrimaryName(),
+			}, &service)).To(Succeed())
+			Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelPrimary))
+
+			err := fakeClient.Get(ctx, k8client.ObjectKeyFromObject(readWriteService), &corev1.Service{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+
+		By("executing reconcilePostgresServices once the cluster is not permanent anymore", func() {
+			cluster.Spec.ReplicaCluster.Permanent = false
+			Expect(r.reconcilePostgresServices(ctx, cluster)).To(Succeed())
+		})
+
+		By("making sure that the -rw service is back", func() {
+			Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(readWriteService), &corev1.Service{})).
+				To(Succeed())
+			err := fakeClient.Get(ctx, k8client.ObjectKey{
+				Namespace: cluster.Namespace, Name: cluster.GetServiceDesignatedPrimaryName(),
+			}, &corev1.Service{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
 	It("should make sure that createOrPatchServiceAccount works correctly", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -790,3 +971,102 @@ var _ = Describe("createOrPatchOwnedPodDisruptionBudget", func() {
 		})
 	})
 })
+
+var _ = Describe("createOrPatchRoleBinding", func() {
+	var (
+		ctx        context.Context
+		fakeClient k8client.Client
+		reconciler *ClusterReconciler
+		cluster    *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build()
+		reconciler = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(10000),
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+	})
+
+	It("should create the role binding when it doesn't exist", func() {
+		Expect(reconciler.createOrPatchRoleBinding(ctx, cluster)).To(Succeed())
+
+		var roleBinding rbacv1.RoleBinding
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cluster), &roleBinding)).To(Succeed())
+		Expect(roleBinding.Annotations).To(HaveKey(utils.CNPGHashAnnotationName))
+		Expect(roleBinding.Annotations).To(HaveKeyWithValue(utils.OperatorVersionAnnotationName, versions.Version))
+	})
+
+	It("should patch the metadata of a role binding created by a previous operator", func() {
+		roleBinding := specs.CreateRoleBinding(cluster.ObjectMeta)
+		roleBinding.Annotations = map[string]string{
+			utils.OperatorVersionAnnotationName: "1.0.0",
+			"external":                          "value",
+		}
+		Expect(fakeClient.Create(ctx, &roleBinding)).To(Succeed())
+
+		Expect(reconciler.createOrPatchRoleBinding(ctx, cluster)).To(Succeed())
+
+		var patchedRoleBinding rbacv1.RoleBinding
+		Expect(fakeClient.Get(ctx, k8client.ObjectKeyFromObject(cluster), &patchedRoleBinding)).To(Succeed())
+		Expect(patchedRoleBinding.Annotations).To(HaveKey(utils.CNPGHashAnnotationName))
+		Expect(patchedRoleBinding.Annotations).To(HaveKeyWithValue(
+			utils.OperatorVersionAnnotationName, versions.Version))
+		Expect(patchedRoleBinding.Annotations).To(HaveKeyWithValue("external", "value"))
+		Expect(patchedRoleBinding.Subjects).To(Equal(roleBinding.Subjects))
+	})
+})
+
+var _ = Describe("read-write service fencing", func() {
+	newCluster := func(gap int32) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{ReadWriteFencingGap: gap},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:          "cluster-example-1",
+				TargetPrimary:           "cluster-example-1",
+				CurrentPrimaryTimestamp: utils.GetCurrentTimestamp(),
+			},
+		}
+	}
+
+	It("never fences the read-write service without a gap", func() {
+		cluster := newCluster(0)
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		fenced, remaining := getReadWriteServiceFencing(cluster, time.Now())
+		Expect(fenced).To(BeFalse())
+		Expect(remaining).To(BeZero())
+	})
+
+	It("fences the read-write service while the primary is changing", func() {
+		cluster := newCluster(10)
+		cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+		fenced, remaining := getReadWriteServiceFencing(cluster, time.Now())
+		Expect(fenced).To(BeTrue())
+		Expect(remaining).To(BeZero())
+	})
+
+	It("fences the read-write service until the gap has elapsed", func() {
+		cluster := newCluster(10)
+		fenced, remaining := getReadWriteServiceFencing(cluster, time.Now())
+		Expect(fenced).To(BeTrue())
+		Expect(remaining).To(BeNumerically("~", 10*time.Second, time.Second))
+
+		fenced, remaining = getReadWriteServiceFencing(cluster, time.Now().Add(10*time.Second))
+		Expect(fenced).To(BeFalse())
+		Expect(remaining).To(BeZero())
+	})
+})
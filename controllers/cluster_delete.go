@@ -22,10 +22,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // deleteDanglingMonitoringQueries deletes the default monitoring configMap and/or secret if no cluster in the namespace
@@ -87,3 +91,105 @@ func (r *ClusterReconciler) deleteDanglingMonitoringQueries(ctx context.Context,
 
 	return nil
 }
+
+// deleteDatabaseFinalizers removes the finalizers from the databases of a
+// deleted cluster, as there is no longer an instance manager taking care of them
+func (r *ClusterReconciler) deleteDatabaseFinalizers(ctx context.Context, namespacedName types.NamespacedName) error {
+	var databases apiv1.DatabaseList
+	if err := r.List(ctx, &databases, client.InNamespace(namespacedName.Namespace)); err != nil {
+		return err
+	}
+
+	for idx := range databases.Items {
+		database := &databases.Items[idx]
+		if database.Spec.ClusterRef.Name != namespacedName.Name {
+			continue
+		}
+
+		origDatabase := database.DeepCopy()
+		if !controllerutil.RemoveFinalizer(database, utils.DatabaseFinalizerName) {
+			continue
+		}
+
+		if err := r.Patch(ctx, database, client.MergeFrom(origDatabase)); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcilePVCRetention adds or removes the finalizer retaining the PVCs
+// according to the retention policy of the cluster and, when the cluster
+// is being deleted, releases its PVCs before removing the finalizer.
+// It returns true when the cluster is being deleted
+func (r *ClusterReconciler) reconcilePVCRetention(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if !cluster.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(cluster, utils.RetainPVCsFinalizerName) {
+			return false, nil
+		}
+
+		if err := r.releasePVCs(ctx, cluster); err != nil {
+			return true, err
+		}
+
+		origCluster := cluster.DeepCopy()
+		controllerutil.RemoveFinalizer(cluster, utils.RetainPVCsFinalizerName)
+		if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil && !apierrs.IsNotFound(err) {
+			return true, err
+		}
+		return true, nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	var changed bool
+	if cluster.GetPVCRetentionPolicy() == apiv1.PVCRetentionPolicyRetain {
+		changed = controllerutil.AddFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	} else {
+		changed = controllerutil.RemoveFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	}
+	if !changed {
+		return false, nil
+	}
+
+	return false, r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// releasePVCs removes the ownership of the cluster from its PVCs, so that
+// they are not garbage collected and can be adopted by a new cluster with
+// the same name, through reconcileRestoredCluster
+func (r *ClusterReconciler) releasePVCs(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(
+		ctx,
+		&pvcs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for idx := range pvcs.Items {
+		pvc := &pvcs.Items[idx]
+		ownerReferences := make([]metav1.OwnerReference, 0, len(pvc.OwnerReferences))
+		for _, ownerReference := range pvc.OwnerReferences {
+			if ownerReference.UID != cluster.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		if len(ownerReferences) == len(pvc.OwnerReferences) {
+			continue
+		}
+
+		origPVC := pvc.DeepCopy()
+		pvc.OwnerReferences = ownerReferences
+		if err := r.Patch(ctx, pvc, client.MergeFrom(origPVC)); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		contextLogger.Info("Retained the PVC of the deleted cluster", "pvcName", pvc.Name)
+	}
+
+	return nil
+}
@@ -18,14 +18,21 @@ package controllers
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -139,3 +146,177 @@ var _ = Describe("ensures that deleteDanglingMonitoringQueries works correctly",
 		})
 	})
 })
+
+var _ = Describe("deleteDatabaseFinalizers", func() {
+	newDatabase := func(name, clusterName string) *apiv1.Database {
+		return &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "default",
+				Finalizers: []string{utils.DatabaseFinalizerName},
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: apiv1.LocalObjectReference{Name: clusterName},
+				Name:       name,
+				Owner:      "app",
+			},
+		}
+	}
+
+	It("removes the finalizers only from the databases of the deleted cluster", func(ctx context.Context) {
+		databaseOne := newDatabase("one", "cluster-example")
+		databaseTwo := newDatabase("two", "another-cluster")
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(databaseOne, databaseTwo).
+			Build()
+		crReconciler := &ClusterReconciler{Client: fakeClient}
+
+		err := crReconciler.deleteDatabaseFinalizers(ctx, types.NamespacedName{
+			Namespace: "default",
+			Name:      "cluster-example",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var database apiv1.Database
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(databaseOne), &database)).To(Succeed())
+		Expect(database.Finalizers).To(BeEmpty())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(databaseTwo), &database)).To(Succeed())
+		Expect(database.Finalizers).To(ConsistOf(utils.DatabaseFinalizerName))
+	})
+})
+
+var _ = Describe("reconcilePVCRetention", func() {
+	newPVC := func(name, clusterName string, owners ...metav1.OwnerReference) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Labels:          map[string]string{utils.ClusterLabelName: clusterName},
+				OwnerReferences: owners,
+			},
+		}
+	}
+
+	newCluster := func(policy apiv1.PVCRetentionPolicy, finalizers ...string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "cluster-example",
+				Namespace:  "default",
+				UID:        "cluster-uid",
+				Finalizers: finalizers,
+			},
+			Spec: apiv1.ClusterSpec{
+				PersistentVolumeClaimRetentionPolicy: policy,
+			},
+		}
+	}
+
+	clusterOwner := metav1.OwnerReference{
+		APIVersion: apiv1.GroupVersion.String(),
+		Kind:       apiv1.ClusterKind,
+		Name:       "cluster-example",
+		UID:        "cluster-uid",
+	}
+
+	It("adds the finalizer when the PVCs are retained", func(ctx context.Context) {
+		cluster := newCluster(apiv1.PVCRetentionPolicyRetain)
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		crReconciler := &ClusterReconciler{Client: fakeClient}
+
+		deleted, err := crReconciler.reconcilePVCRetention(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+
+		var updatedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Finalizers).To(ConsistOf(utils.RetainPVCsFinalizerName))
+	})
+
+	It("removes the finalizer when the PVCs are deleted with the cluster", func(ctx context.Context) {
+		cluster := newCluster("", utils.RetainPVCsFinalizerName)
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		crReconciler := &ClusterReconciler{Client: fakeClient}
+
+		deleted, err := crReconciler.reconcilePVCRetention(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+
+		var updatedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Finalizers).To(BeEmpty())
+	})
+
+	It("releases the PVCs of a deleted cluster", func(ctx context.Context) {
+		cluster := newCluster(apiv1.PVCRetentionPolicyRetain, utils.RetainPVCsFinalizerName)
+		ownedPVC := newPVC("cluster-example-1", "cluster-example", clusterOwner)
+		otherPVC := newPVC("another-cluster-1", "another-cluster", metav1.OwnerReference{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+			Name:       "another-cluster",
+			UID:        "another-uid",
+		})
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, ownedPVC, otherPVC).
+			Build()
+		crReconciler := &ClusterReconciler{Client: fakeClient}
+
+		Expect(fakeClient.Delete(ctx, cluster)).To(Succeed())
+		var deletingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &deletingCluster)).To(Succeed())
+
+		deleted, err := crReconciler.reconcilePVCRetention(ctx, &deletingCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+
+		var pvc corev1.PersistentVolumeClaim
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ownedPVC), &pvc)).To(Succeed())
+		Expect(pvc.OwnerReferences).To(BeEmpty())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(otherPVC), &pvc)).To(Succeed())
+		Expect(pvc.OwnerReferences).To(HaveLen(1))
+
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &deletingCluster)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("lets a new cluster with the same name adopt the retained PVCs", func(ctx context.Context) {
+		cluster := newCluster(apiv1.PVCRetentionPolicyRetain, utils.RetainPVCsFinalizerName)
+		var pvcs []client.Object
+		for serial, role := range map[int]string{1: specs.ClusterRoleLabelReplica, 2: specs.ClusterRoleLabelPrimary} {
+			pvc := newPVC(specs.GetInstanceName(cluster.Name, serial), cluster.Name, clusterOwner)
+			pvc.Labels[utils.ClusterInstanceRoleLabelName] = role
+			pvc.Annotations = map[string]string{utils.ClusterSerialAnnotationName: strconv.Itoa(serial)}
+			pvcs = append(pvcs, pvc)
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(append(pvcs, cluster)...).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		crReconciler := &ClusterReconciler{Client: fakeClient}
+
+		Expect(fakeClient.Delete(ctx, cluster)).To(Succeed())
+		var deletingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &deletingCluster)).To(Succeed())
+		_, err := crReconciler.reconcilePVCRetention(ctx, &deletingCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		recreatedCluster := newCluster(apiv1.PVCRetentionPolicyRetain)
+		recreatedCluster.UID = "new-cluster-uid"
+		Expect(fakeClient.Create(ctx, recreatedCluster)).To(Succeed())
+		Expect(crReconciler.reconcileRestoredCluster(ctx, recreatedCluster)).To(Succeed())
+
+		var adoptedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(recreatedCluster), &adoptedCluster)).To(Succeed())
+		Expect(adoptedCluster.Status.LatestGeneratedNode).To(Equal(2))
+		Expect(adoptedCluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+
+		for _, pvc := range pvcs {
+			var adoptedPVC corev1.PersistentVolumeClaim
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(pvc), &adoptedPVC)).To(Succeed())
+			Expect(adoptedPVC.OwnerReferences).To(ConsistOf(HaveField("UID", recreatedCluster.UID)))
+		}
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileGeneratedObjectsMetadata brings the objects generated by previous
+// versions of the operator in line with the current ones: the Jobs of the
+// cluster that were created without an owner reference are adopted, and
+// every Pod, PVC and Job owned by the cluster is labeled as managed by the
+// operator. Each updated object is recorded with an event on the cluster
+func (r *ClusterReconciler) reconcileGeneratedObjectsMetadata(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	orphanJobs, err := r.getOrphanJobs(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	var objects []client.Object
+	for idx := range resources.instances.Items {
+		objects = append(objects, &resources.instances.Items[idx])
+	}
+	for idx := range resources.pvcs.Items {
+		objects = append(objects, &resources.pvcs.Items[idx])
+	}
+	for idx := range resources.jobs.Items {
+		objects = append(objects, &resources.jobs.Items[idx])
+	}
+	for idx := range orphanJobs {
+		objects = append(objects, &orphanJobs[idx])
+	}
+
+	for _, object := range objects {
+		if err := r.updateGeneratedObjectMetadata(ctx, cluster, object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getOrphanJobs returns the Jobs created for the cluster that are not
+// controlled by any object
+func (r *ClusterReconciler) getOrphanJobs(ctx context.Context, cluster *apiv1.Cluster) ([]batchv1.Job, error) {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.JobRoleLabelName},
+	); err != nil {
+		return nil, err
+	}
+
+	var result []batchv1.Job
+	for _, job := range jobs.Items {
+		if metav1.GetControllerOf(&job) == nil {
+			result = append(result, job)
+		}
+	}
+
+	return result, nil
+}
+
+// updateGeneratedObjectMetadata patches the object when its metadata is
+// missing the owner reference or the managed-by label
+func (r *ClusterReconciler) updateGeneratedObjectMetadata(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	object client.Object,
+) error {
+	origObject, ok := object.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", object)
+	}
+
+	changes := setGeneratedObjectMetadata(object, cluster)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(object, r.Client.Scheme())
+	if err != nil {
+		return err
+	}
+
+	kind := gvk.Kind
+	log.FromContext(ctx).Info("Updating the metadata of an object generated for the cluster",
+		"kind", kind,
+		"name", object.GetName(),
+		"changes", changes)
+	if err := r.Patch(ctx, object, client.MergeFrom(origObject)); err != nil {
+		return fmt.Errorf("while updating the metadata of %s %s: %w", kind, object.GetName(), err)
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "UpdatedObjectMetadata",
+		"Updated the metadata of %s %s: %v", kind, object.GetName(), changes)
+	return nil
+}
+
+// setGeneratedObjectMetadata adds the controller owner reference, when no
+// object controls the passed one, and the managed-by label, returning the
+// list of the applied changes
+func setGeneratedObjectMetadata(object client.Object, cluster *apiv1.Cluster) []string {
+	var changes []string
+
+	if metav1.GetControllerOf(object) == nil {
+		object.SetOwnerReferences(append(object.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+			Controller: ptr.To(true),
+		}))
+		changes = append(changes, "added the owner reference")
+	}
+
+	if object.GetLabels()[utils.KubernetesAppManagedByLabelName] != utils.ManagerName {
+		labels := object.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[utils.KubernetesAppManagedByLabelName] = utils.ManagerName
+		object.SetLabels(labels)
+		changes = append(changes, fmt.Sprintf("added the %s label", utils.KubernetesAppManagedByLabelName))
+	}
+
+	return changes
+}
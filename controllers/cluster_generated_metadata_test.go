@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generated objects metadata", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+			UID:       "cluster-uid",
+		},
+	}
+
+	It("adopts the jobs of the cluster without an owner and labels them", func(ctx SpecContext) {
+		orphanJob := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1-initdb",
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+					utils.JobRoleLabelName: "initdb",
+				},
+			},
+		}
+		unrelatedJob := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unrelated",
+				Namespace: "default",
+				Labels:    map[string]string{utils.ClusterLabelName: cluster.Name},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(orphanJob, unrelatedJob).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &ClusterReconciler{Client: fakeClient, Recorder: recorder}
+
+		Expect(reconciler.reconcileGeneratedObjectsMetadata(ctx, cluster, &managedResources{})).To(Succeed())
+
+		var job batchv1.Job
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(orphanJob), &job)).To(Succeed())
+		Expect(metav1.IsControlledBy(&job, cluster)).To(BeTrue())
+		Expect(job.Labels).To(HaveKeyWithValue(utils.KubernetesAppManagedByLabelName, utils.ManagerName))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Job cluster-example-1-initdb")))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(unrelatedJob), &job)).To(Succeed())
+		Expect(job.OwnerReferences).To(BeEmpty())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("labels the owned objects without touching their owner", func(ctx SpecContext) {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+			},
+		}
+		cluster.SetInheritedDataAndOwnership(&pvc.ObjectMeta)
+		delete(pvc.Labels, utils.KubernetesAppManagedByLabelName)
+
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(pvc.DeepCopy()).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &ClusterReconciler{Client: fakeClient, Recorder: recorder}
+
+		resources := &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{pvc}},
+		}
+		Expect(reconciler.reconcileGeneratedObjectsMetadata(ctx, cluster, resources)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("PersistentVolumeClaim cluster-example-1")))
+
+		var result corev1.PersistentVolumeClaim
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(&pvc), &result)).To(Succeed())
+		Expect(result.OwnerReferences).To(Equal(pvc.OwnerReferences))
+		Expect(result.Labels).To(HaveKeyWithValue(utils.KubernetesAppManagedByLabelName, utils.ManagerName))
+
+		// Nothing is recorded once the metadata is up to date
+		resources.pvcs.Items = []corev1.PersistentVolumeClaim{result}
+		Expect(reconciler.reconcileGeneratedObjectsMetadata(ctx, cluster, resources)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+})
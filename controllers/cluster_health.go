@@ -0,0 +1,251 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// certificateExpirationLayout is the format used to store the expiration
+// dates of the certificates in the status of the cluster
+const certificateExpirationLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// clusterHealthMetricsTimeout is the maximum time allowed to list the
+// clusters when the metrics are scraped
+const clusterHealthMetricsTimeout = 10 * time.Second
+
+// clusterHealthCheck is one of the checks composing the health of a cluster
+type clusterHealthCheck string
+
+const (
+	// healthCheckPrimary verifies that the cluster has a running primary
+	// and that no failover or switchover is in progress
+	healthCheckPrimary clusterHealthCheck = "primary"
+
+	// healthCheckArchiving verifies that the WAL archiving is not failing
+	healthCheckArchiving clusterHealthCheck = "archiving"
+
+	// healthCheckReplication verifies that every instance is ready, on the
+	// timeline of the primary and within the replication lag thresholds
+	healthCheckReplication clusterHealthCheck = "replication"
+
+	// healthCheckCertificates verifies that no certificate is expired
+	healthCheckCertificates clusterHealthCheck = "certificates"
+
+	// healthCheckBackups verifies that the last backup didn't fail and
+	// that the scheduled backups of the cluster are not behind schedule
+	healthCheckBackups clusterHealthCheck = "backups"
+)
+
+// clusterHealthChecks is the ordered list of the health checks
+var clusterHealthChecks = []clusterHealthCheck{
+	healthCheckPrimary,
+	healthCheckArchiving,
+	healthCheckReplication,
+	healthCheckCertificates,
+	healthCheckBackups,
+}
+
+var (
+	clusterHealthCheckDesc = prometheus.NewDesc(
+		"cnpg_cluster_health_check",
+		"1 if the health check of the cluster is passing, 0 otherwise",
+		[]string{"namespace", "cluster", "check"}, nil,
+	)
+	clusterHealthScoreDesc = prometheus.NewDesc(
+		"cnpg_cluster_health_score",
+		"Fraction of the health checks of the cluster that are passing, from 0 to 1",
+		[]string{"namespace", "cluster"}, nil,
+	)
+)
+
+// evaluateClusterHealth runs the health checks on the status of the cluster,
+// returning whether each of them is passing
+func evaluateClusterHealth(
+	cluster *apiv1.Cluster,
+	scheduledBackups []apiv1.ScheduledBackup,
+	now time.Time,
+) map[clusterHealthCheck]bool {
+	conditions := cluster.Status.Conditions
+	primaryState, primaryReported := cluster.Status.InstancesReportedState[apiv1.PodName(cluster.Status.CurrentPrimary)]
+
+	return map[clusterHealthCheck]bool{
+		healthCheckPrimary: cluster.Status.CurrentPrimary != "" &&
+			cluster.Status.CurrentPrimary == cluster.Status.TargetPrimary &&
+			primaryReported && primaryState.IsPrimary,
+		healthCheckArchiving: !meta.IsStatusConditionFalse(conditions, string(apiv1.ConditionContinuousArchiving)),
+		healthCheckReplication: cluster.Status.ReadyInstances == cluster.Spec.Instances &&
+			!meta.IsStatusConditionFalse(conditions, string(apiv1.ConditionReplicationHealthy)) &&
+			!meta.IsStatusConditionFalse(conditions, string(apiv1.ConditionTimelinesAligned)),
+		healthCheckCertificates: areCertificatesValid(cluster, now),
+		healthCheckBackups: !meta.IsStatusConditionFalse(conditions, string(apiv1.ConditionBackup)) &&
+			isLastBackupRecent(cluster, scheduledBackups, now),
+	}
+}
+
+// isLastBackupRecent checks that no scheduled backup of the cluster missed
+// two runs since the last successful backup. One missed run is tolerated,
+// as the backup it started may still be in progress
+func isLastBackupRecent(cluster *apiv1.Cluster, scheduledBackups []apiv1.ScheduledBackup, now time.Time) bool {
+	var lastSuccessfulBackup time.Time
+	if cluster.Status.LastSuccessfulBackup != "" {
+		if parsedTime, err := time.Parse(time.RFC3339, cluster.Status.LastSuccessfulBackup); err == nil {
+			lastSuccessfulBackup = parsedTime
+		}
+	}
+
+	for idx := range scheduledBackups {
+		scheduledBackup := &scheduledBackups[idx]
+		if scheduledBackup.Namespace != cluster.Namespace ||
+			scheduledBackup.Spec.Cluster.Name != cluster.Name ||
+			scheduledBackup.IsSuspended() {
+			continue
+		}
+
+		// An invalid schedule is reported by the scheduled backup controller
+		schedule, err := cron.Parse(scheduledBackup.GetSchedule())
+		if err != nil {
+			continue
+		}
+
+		// A scheduled backup created after the last successful backup
+		// had no chance to run yet
+		since := lastSuccessfulBackup
+		if since.Before(scheduledBackup.CreationTimestamp.Time) {
+			since = scheduledBackup.CreationTimestamp.Time
+		}
+
+		if schedule.Next(schedule.Next(since)).Before(now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// areCertificatesValid checks that none of the certificates used by the
+// cluster is expired
+func areCertificatesValid(cluster *apiv1.Cluster, now time.Time) bool {
+	for _, expiration := range cluster.Status.Certificates.Expirations {
+		expirationTime, err := time.Parse(certificateExpirationLayout, expiration)
+		if err != nil || !now.Before(expirationTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// updateClusterHealth sets the Healthy condition, summarizing the health
+// checks of the cluster
+func updateClusterHealth(cluster *apiv1.Cluster, scheduledBackups []apiv1.ScheduledBackup) {
+	results := evaluateClusterHealth(cluster, scheduledBackups, time.Now())
+
+	var failing []string
+	for _, check := range clusterHealthChecks {
+		if !results[check] {
+			failing = append(failing, string(check))
+		}
+	}
+
+	if len(failing) > 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionHealthy),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.HealthChecksFailing),
+			Message: fmt.Sprintf("Failing health checks: %s", strings.Join(failing, ", ")),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionHealthy),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.HealthChecksPassing),
+		Message: "Every health check is passing",
+	})
+}
+
+// clusterHealthMetricsCollector exports the health checks, the memory
+// headroom and the update availability of every cluster, so that a fleet
+// of clusters can be monitored at a glance
+type clusterHealthMetricsCollector struct {
+	cli client.Reader
+}
+
+// Describe implements prometheus.Collector
+func (c *clusterHealthMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterHealthCheckDesc
+	ch <- clusterHealthScoreDesc
+	ch <- clusterMemoryEstimateDesc
+	ch <- clusterMemoryHeadroomDesc
+	ch <- clusterUpdateAvailableDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *clusterHealthMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterHealthMetricsTimeout)
+	defer cancel()
+
+	var clusterList apiv1.ClusterList
+	if err := c.cli.List(ctx, &clusterList); err != nil {
+		log.Error(err, "while listing clusters to collect metrics")
+		return
+	}
+
+	var scheduledBackupList apiv1.ScheduledBackupList
+	if err := c.cli.List(ctx, &scheduledBackupList); err != nil {
+		log.Error(err, "while listing scheduled backups to collect metrics")
+		return
+	}
+
+	now := time.Now()
+	for idx := range clusterList.Items {
+		cluster := &clusterList.Items[idx]
+		results := evaluateClusterHealth(cluster, scheduledBackupList.Items, now)
+
+		passing := 0
+		for _, check := range clusterHealthChecks {
+			value := 0.0
+			if results[check] {
+				value = 1
+				passing++
+			}
+			ch <- prometheus.MustNewConstMetric(
+				clusterHealthCheckDesc, prometheus.GaugeValue, value,
+				cluster.Namespace, cluster.Name, string(check))
+		}
+		ch <- prometheus.MustNewConstMetric(
+			clusterHealthScoreDesc, prometheus.GaugeValue,
+			float64(passing)/float64(len(clusterHealthChecks)),
+			cluster.Namespace, cluster.Name)
+		collectMemoryMetrics(ch, cluster)
+		collectUpdateAvailabilityMetrics(ch, cluster)
+	}
+}
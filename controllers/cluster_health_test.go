@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster health", func() {
+	now := time.Now()
+
+	var cluster *apiv1.Cluster
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{Instances: 2},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				ReadyInstances: 2,
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {IsPrimary: true},
+					"cluster-example-2": {IsPrimary: false},
+				},
+				Certificates: apiv1.CertificatesStatus{
+					Expirations: map[string]string{
+						"cluster-example-ca": now.Add(24 * time.Hour).Truncate(time.Second).String(),
+					},
+				},
+			},
+		}
+	})
+
+	It("passes every check on a healthy cluster", func() {
+		results := evaluateClusterHealth(cluster, nil, now)
+		for _, check := range clusterHealthChecks {
+			Expect(results[check]).To(BeTrue(), string(check))
+		}
+
+		updateClusterHealth(cluster, nil)
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionHealthy))).To(BeTrue())
+	})
+
+	It("fails the primary check during a switchover", func() {
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		Expect(evaluateClusterHealth(cluster, nil, now)[healthCheckPrimary]).To(BeFalse())
+	})
+
+	It("fails the replication check when an instance is not ready", func() {
+		cluster.Status.ReadyInstances = 1
+		Expect(evaluateClusterHealth(cluster, nil, now)[healthCheckReplication]).To(BeFalse())
+	})
+
+	It("fails the checks depending on the failing conditions", func() {
+		cluster.Status.Conditions = []metav1.Condition{
+			{Type: string(apiv1.ConditionContinuousArchiving), Status: metav1.ConditionFalse},
+			{Type: string(apiv1.ConditionBackup), Status: metav1.ConditionFalse},
+		}
+		results := evaluateClusterHealth(cluster, nil, now)
+		Expect(results[healthCheckArchiving]).To(BeFalse())
+		Expect(results[healthCheckBackups]).To(BeFalse())
+		Expect(results[healthCheckPrimary]).To(BeTrue())
+
+		updateClusterHealth(cluster, nil)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionHealthy))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(Equal("Failing health checks: archiving, backups"))
+	})
+
+	Context("with a scheduled backup", func() {
+		var scheduledBackups []apiv1.ScheduledBackup
+		BeforeEach(func() {
+			cluster.Name = "cluster-example"
+			cluster.Namespace = "default"
+			scheduledBackups = []apiv1.ScheduledBackup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "daily",
+						Namespace:         "default",
+						CreationTimestamp: metav1.NewTime(now.Add(-7 * 24 * time.Hour)),
+					},
+					Spec: apiv1.ScheduledBackupSpec{
+						Schedule: "0 0 0 * * *",
+						Cluster:  apiv1.LocalObjectReference{Name: "cluster-example"},
+					},
+				},
+			}
+		})
+
+		It("passes the backups check when the last backup is recent", func() {
+			cluster.Status.LastSuccessfulBackup = now.Add(-23 * time.Hour).Format(time.RFC3339)
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeTrue())
+		})
+
+		It("fails the backups check when two runs were missed", func() {
+			cluster.Status.LastSuccessfulBackup = now.Add(-72 * time.Hour).Format(time.RFC3339)
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeFalse())
+
+			cluster.Status.LastSuccessfulBackup = ""
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeFalse())
+		})
+
+		It("doesn't fail the backups check for a new scheduled backup", func() {
+			scheduledBackups[0].CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeTrue())
+		})
+
+		It("ignores the suspended scheduled backups and the ones of other clusters", func() {
+			scheduledBackups[0].Spec.Suspend = ptr.To(true)
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeTrue())
+
+			scheduledBackups[0].Spec.Suspend = nil
+			scheduledBackups[0].Spec.Cluster.Name = "another-cluster"
+			Expect(evaluateClusterHealth(cluster, scheduledBackups, now)[healthCheckBackups]).To(BeTrue())
+		})
+	})
+
+	It("fails the certificates check when a certificate is expired", func() {
+		Expect(evaluateClusterHealth(cluster, nil, now.Add(48*time.Hour))[healthCheckCertificates]).To(BeFalse())
+	})
+})
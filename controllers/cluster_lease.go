@@ -0,0 +1,174 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcilePrimaryLease publishes the identity and the timeline of the
+// current primary in a Lease, renewed while the primary is healthy, or
+// deletes the Lease when it is not enabled anymore. The Leases are read
+// directly from the API server, so the Lease is read only when enabled or
+// still recorded in the status of the cluster
+func (r *ClusterReconciler) reconcilePrimaryLease(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	if !cluster.IsPrimaryLeaseEnabled() {
+		return r.deletePrimaryLease(ctx, cluster)
+	}
+
+	// The Lease expires when the primary is not healthy, signaling
+	// that its identity cannot be relied upon
+	if !isPrimaryHealthy(cluster, instancesStatus) {
+		return nil
+	}
+
+	var lease coordinationv1.Lease
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetPrimaryLeaseName()}, &lease)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	if apierrs.IsNotFound(err) {
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetPrimaryLeaseName(),
+				Namespace: cluster.Namespace,
+			},
+		}
+		cluster.SetInheritedDataAndOwnership(&lease.ObjectMeta)
+		updatePrimaryLease(&lease, cluster, time.Now())
+		if err := r.Create(ctx, &lease); err != nil {
+			return err
+		}
+	} else if updatePrimaryLease(&lease, cluster, time.Now()) {
+		if err := r.Update(ctx, &lease); err != nil {
+			return err
+		}
+	}
+
+	return r.setPrimaryLeaseStatus(ctx, cluster, lease.Name)
+}
+
+// deletePrimaryLease deletes the Lease recorded in the status of the
+// cluster, if it is owned by the cluster, and then forgets it
+func (r *ClusterReconciler) deletePrimaryLease(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Status.PrimaryLease == "" {
+		return nil
+	}
+
+	var lease coordinationv1.Lease
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.PrimaryLease}, &lease)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if _, owned := IsOwnedByCluster(&lease); owned {
+			if err := r.Delete(ctx, &lease); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+
+	return r.setPrimaryLeaseStatus(ctx, cluster, "")
+}
+
+// setPrimaryLeaseStatus records in the status of the cluster the name
+// of the Lease managed by the operator
+func (r *ClusterReconciler) setPrimaryLeaseStatus(ctx context.Context, cluster *apiv1.Cluster, name string) error {
+	if cluster.Status.PrimaryLease == name {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PrimaryLease = name
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isPrimaryHealthy checks if the current primary is reporting its status
+// and is ready to accept connections, and no failover or switchover is
+// changing it
+func isPrimaryHealthy(cluster *apiv1.Cluster, instancesStatus postgres.PostgresqlStatusList) bool {
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return false
+	}
+
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		return item.Error == nil && item.IsPodReady && (item.IsPrimary || cluster.IsReplica())
+	}
+
+	return false
+}
+
+// updatePrimaryLease sets the identity and the timeline of the current
+// primary in the Lease, renewing it when a third of its duration has
+// elapsed. It returns true when the Lease has been changed
+func updatePrimaryLease(lease *coordinationv1.Lease, cluster *apiv1.Cluster, now time.Time) bool {
+	duration := cluster.GetPrimaryLeaseDuration()
+	holder := cluster.Status.CurrentPrimary
+	timeline := strconv.Itoa(cluster.Status.TimelineID)
+
+	changed := false
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		if lease.Spec.HolderIdentity != nil {
+			lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		}
+		lease.Spec.HolderIdentity = ptr.To(holder)
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		changed = true
+	}
+
+	if lease.Annotations[utils.TimelineAnnotationName] != timeline {
+		if lease.Annotations == nil {
+			lease.Annotations = make(map[string]string)
+		}
+		lease.Annotations[utils.TimelineAnnotationName] = timeline
+		changed = true
+	}
+
+	durationSeconds := int32(duration / time.Second)
+	if ptr.Deref(lease.Spec.LeaseDurationSeconds, 0) != durationSeconds {
+		lease.Spec.LeaseDurationSeconds = ptr.To(durationSeconds)
+		changed = true
+	}
+
+	if changed || lease.Spec.RenewTime == nil || now.Sub(lease.Spec.RenewTime.Time) >= duration/3 {
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,219 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Primary Lease", func() {
+	newCluster := func(enabled bool) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				PrimaryLease: &apiv1.PrimaryLeaseConfiguration{Enabled: enabled},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				TimelineID:     2,
+			},
+		}
+	}
+
+	healthyPrimary := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{{
+		Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+		IsPrimary:  true,
+		IsPodReady: true,
+	}}}
+
+	leaseKey := client.ObjectKey{Namespace: "default", Name: "cluster-example-primary"}
+
+	Context("updatePrimaryLease", func() {
+		now := time.Now()
+
+		It("sets the identity and the timeline of the primary", func() {
+			var lease coordinationv1.Lease
+			Expect(updatePrimaryLease(&lease, newCluster(true), now)).To(BeTrue())
+			Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-1"))
+			Expect(*lease.Spec.LeaseDurationSeconds).To(BeEquivalentTo(30))
+			Expect(lease.Spec.RenewTime.Time).To(Equal(now))
+			Expect(lease.Spec.AcquireTime.Time).To(Equal(now))
+			Expect(lease.Spec.LeaseTransitions).To(BeNil())
+			Expect(lease.Annotations).To(HaveKeyWithValue(utils.TimelineAnnotationName, "2"))
+		})
+
+		It("renews the lease only after a third of its duration", func() {
+			var lease coordinationv1.Lease
+			cluster := newCluster(true)
+			updatePrimaryLease(&lease, cluster, now)
+
+			Expect(updatePrimaryLease(&lease, cluster, now.Add(5*time.Second))).To(BeFalse())
+			Expect(updatePrimaryLease(&lease, cluster, now.Add(10*time.Second))).To(BeTrue())
+			Expect(lease.Spec.RenewTime.Time).To(Equal(now.Add(10 * time.Second)))
+			Expect(lease.Spec.AcquireTime.Time).To(Equal(now))
+		})
+
+		It("counts the transitions of the primary", func() {
+			var lease coordinationv1.Lease
+			cluster := newCluster(true)
+			updatePrimaryLease(&lease, cluster, now)
+
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			cluster.Status.TimelineID = 3
+			Expect(updatePrimaryLease(&lease, cluster, now.Add(time.Second))).To(BeTrue())
+			Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-2"))
+			Expect(*lease.Spec.LeaseTransitions).To(BeEquivalentTo(1))
+			Expect(lease.Spec.AcquireTime.Time).To(Equal(now.Add(time.Second)))
+			Expect(lease.Annotations).To(HaveKeyWithValue(utils.TimelineAnnotationName, "3"))
+		})
+	})
+
+	Context("isPrimaryHealthy", func() {
+		It("is true when the current primary is ready", func() {
+			Expect(isPrimaryHealthy(newCluster(true), healthyPrimary)).To(BeTrue())
+		})
+
+		It("is false when the current primary is not reporting", func() {
+			cluster := newCluster(true)
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			Expect(isPrimaryHealthy(cluster, healthyPrimary)).To(BeFalse())
+		})
+
+		It("is false while the primary is changing", func() {
+			cluster := newCluster(true)
+			cluster.Status.TargetPrimary = "cluster-example-2"
+			Expect(isPrimaryHealthy(cluster, healthyPrimary)).To(BeFalse())
+
+			cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+			Expect(isPrimaryHealthy(cluster, healthyPrimary)).To(BeFalse())
+		})
+
+		It("is false when the current primary is not ready", func() {
+			status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{{
+				Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary: true,
+			}}}
+			Expect(isPrimaryHealthy(newCluster(true), status)).To(BeFalse())
+		})
+	})
+
+	Context("reconcilePrimaryLease", func() {
+		It("creates the Lease of a healthy primary", func(ctx context.Context) {
+			cluster := newCluster(true)
+			fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				WithStatusSubresource(cluster).
+				Build()
+			r := &ClusterReconciler{Client: fakeClient}
+
+			Expect(r.reconcilePrimaryLease(ctx, cluster, healthyPrimary)).To(Succeed())
+
+			var lease coordinationv1.Lease
+			Expect(fakeClient.Get(ctx, leaseKey, &lease)).To(Succeed())
+			Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-1"))
+			Expect(lease.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+
+			var storedCluster apiv1.Cluster
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+			Expect(storedCluster.Status.PrimaryLease).To(Equal(leaseKey.Name))
+		})
+
+		It("doesn't read the Lease of a cluster that never enabled it", func(ctx context.Context) {
+			fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+						return errors.New("unexpected read of the Lease")
+					},
+				}).
+				Build()
+			r := &ClusterReconciler{Client: fakeClient}
+
+			Expect(r.reconcilePrimaryLease(ctx, newCluster(false), healthyPrimary)).To(Succeed())
+		})
+
+		It("doesn't renew the Lease when the primary is not healthy", func(ctx context.Context) {
+			renewTime := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: leaseKey.Name, Namespace: leaseKey.Namespace},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity: ptr.To("cluster-example-1"),
+					RenewTime:      &renewTime,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(lease).
+				Build()
+			r := &ClusterReconciler{Client: fakeClient}
+
+			Expect(r.reconcilePrimaryLease(ctx, newCluster(true), postgres.PostgresqlStatusList{})).To(Succeed())
+
+			var updatedLease coordinationv1.Lease
+			Expect(fakeClient.Get(ctx, leaseKey, &updatedLease)).To(Succeed())
+			Expect(updatedLease.Spec.RenewTime.Unix()).To(Equal(renewTime.Unix()))
+		})
+
+		It("deletes the Lease when it is disabled", func(ctx context.Context) {
+			cluster := newCluster(false)
+			cluster.Status.PrimaryLease = leaseKey.Name
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: leaseKey.Name, Namespace: leaseKey.Namespace},
+			}
+			cluster.SetInheritedDataAndOwnership(&lease.ObjectMeta)
+			fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster, lease).
+				WithStatusSubresource(cluster).
+				Build()
+			r := &ClusterReconciler{Client: fakeClient}
+
+			Expect(r.reconcilePrimaryLease(ctx, cluster, healthyPrimary)).To(Succeed())
+
+			err := fakeClient.Get(ctx, leaseKey, &coordinationv1.Lease{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+			var storedCluster apiv1.Cluster
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+			Expect(storedCluster.Status.PrimaryLease).To(BeEmpty())
+		})
+	})
+})
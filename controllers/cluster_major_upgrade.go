@@ -0,0 +1,312 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileMajorUpgrade upgrades the data directory of the primary instance
+// with pg_upgrade when the image of the cluster contains a newer major
+// version of PostgreSQL than the one the data directory has been created with.
+// Every instance is shut down during the upgrade, and the replicas are
+// recreated from the upgraded primary once the upgrade has completed. If the
+// upgrade fails, the replicas are kept to restart the cluster with the old
+// major version
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	requestedMajorVersion, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		// The major version can't be detected from the image tag,
+		// and major upgrades are not available
+		contextLogger.Debug("Cannot detect the major version of the cluster image", "err", err)
+		return nil, nil
+	}
+
+	pgDataImageInfo := getPGDataImageInfo(cluster, resources.instances.Items)
+	if pgDataImageInfo == nil {
+		return nil, nil
+	}
+
+	if requestedMajorVersion <= pgDataImageInfo.MajorVersion {
+		// A failed upgrade job would block the reconciliation
+		// when the old image is restored
+		if err := r.deleteMajorUpgradeJob(ctx, cluster, resources); err != nil {
+			return nil, err
+		}
+
+		// Any image with the same major version can be used to
+		// run the old binaries in a future major upgrade
+		pgDataImageInfo.Image = cluster.GetImageName()
+		return nil, r.setPGDataImageInfo(ctx, cluster, pgDataImageInfo)
+	}
+
+	if err := r.setPGDataImageInfo(ctx, cluster, pgDataImageInfo); err != nil {
+		return nil, err
+	}
+
+	if cluster.IsReplica() || cluster.Status.CurrentPrimary == "" ||
+		cluster.Annotations[utils.HibernationAnnotationName] == hibernation.HibernationOn {
+		return nil, nil
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseMajorUpgrade && cluster.Status.Phase != apiv1.PhaseMajorUpgradeFailed {
+		// The safety checks run while the primary is still up, before
+		// any instance is shut down
+		if ready, err := r.enforceImageUpgradeSafety(ctx, cluster); !ready || err != nil {
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+
+		contextLogger.Info("Upgrading the major version of PostgreSQL",
+			"fromImage", pgDataImageInfo.Image,
+			"fromMajorVersion", pgDataImageInfo.MajorVersion,
+			"toImage", cluster.GetImageName(),
+			"toMajorVersion", requestedMajorVersion)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+			fmt.Sprintf("Upgrading from PostgreSQL %v to %v",
+				pgDataImageInfo.MajorVersion, requestedMajorVersion)); err != nil {
+			return nil, err
+		}
+	}
+
+	// The data directory can be upgraded only when PostgreSQL is not running
+	if len(resources.instances.Items) > 0 {
+		for idx := range resources.instances.Items {
+			pod := &resources.instances.Items[idx]
+			if !pod.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			contextLogger.Info("Deleting instance before the major upgrade", "pod", pod.Name)
+			if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	job := findJobByName(resources.jobs.Items, specs.GetMajorUpgradeJobName(cluster.Status.CurrentPrimary))
+	switch {
+	case job == nil:
+		primarySerial, err := getPrimarySerial(cluster, resources.pvcs.Items)
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster.Status.Phase == apiv1.PhaseMajorUpgradeFailed {
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+				"Retrying the major upgrade"); err != nil {
+				return nil, err
+			}
+		}
+		return r.createMajorUpgradeJob(ctx, cluster, primarySerial, pgDataImageInfo.Image)
+
+	case job.Status.Failed > 0:
+		if cluster.Status.Phase != apiv1.PhaseMajorUpgradeFailed {
+			return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+				fmt.Sprintf("The major upgrade job %s failed, check its logs", job.Name))
+		}
+		return &ctrl.Result{}, nil
+
+	case !utils.JobHasOneCompletion(*job):
+		contextLogger.Debug("Waiting for the major upgrade job to complete", "job", job.Name)
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	// The replicas can't follow the upgraded primary, and will be
+	// cloned again from it
+	for _, pvc := range resources.pvcs.Items {
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err != nil {
+			return nil, err
+		}
+
+		instanceName := specs.GetInstanceName(cluster.Name, serial)
+		if instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		contextLogger.Info("Deleting replica after the major upgrade", "instance", instanceName)
+		if err := r.ensureInstanceIsDeleted(ctx, cluster, instanceName); err != nil {
+			return nil, err
+		}
+	}
+
+	contextLogger.Info("Major upgrade completed", "job", job.Name)
+	if err := r.setPGDataImageInfo(ctx, cluster, &apiv1.ImageInfo{
+		Image:        cluster.GetImageName(),
+		MajorVersion: requestedMajorVersion,
+	}); err != nil {
+		return nil, err
+	}
+
+	// The completed job is removed, and the primary instance
+	// recreated, by the next reconciliation loops
+	return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// getPGDataImageInfo gets the image the data directory of the cluster has
+// been created with. When missing from the status, it is detected from the
+// image of the current primary, or of the cluster if no instance is running.
+// It returns nil when the major version of that image can't be detected
+func getPGDataImageInfo(cluster *apiv1.Cluster, instances []corev1.Pod) *apiv1.ImageInfo {
+	if cluster.Status.PGDataImageInfo != nil {
+		return cluster.Status.PGDataImageInfo.DeepCopy()
+	}
+
+	image := cluster.GetImageName()
+	for idx := range instances {
+		if instances[idx].Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+		if primaryImage, err := specs.GetPostgresImageName(instances[idx]); err == nil {
+			image = primaryImage
+		}
+	}
+
+	majorVersion, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(image))
+	if err != nil {
+		return nil
+	}
+
+	return &apiv1.ImageInfo{
+		Image:        image,
+		MajorVersion: majorVersion,
+	}
+}
+
+// getPrimarySerial gets the serial of the current primary instance
+// from its PVCs
+func getPrimarySerial(cluster *apiv1.Cluster, pvcs []corev1.PersistentVolumeClaim) (int, error) {
+	for _, pvc := range pvcs {
+		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
+		if err != nil {
+			return 0, err
+		}
+
+		if specs.GetInstanceName(cluster.Name, serial) == cluster.Status.CurrentPrimary {
+			return serial, nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot find the storage of the primary instance %s", cluster.Status.CurrentPrimary)
+}
+
+// setPGDataImageInfo stores the image the data directory of
+// the cluster has been created with in its status
+func (r *ClusterReconciler) setPGDataImageInfo(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	info *apiv1.ImageInfo,
+) error {
+	if cluster.Status.PGDataImageInfo != nil && *cluster.Status.PGDataImageInfo == *info {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = info
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// createMajorUpgradeJob creates the job upgrading the
+// data directory of the instance with the given serial
+func (r *ClusterReconciler) createMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+	oldImage string,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	job, err := specs.CreateMajorUpgradeJob(*cluster, nodeSerial, oldImage)
+	if err != nil {
+		contextLogger.Error(err, "Unable to create the major upgrade job")
+		return nil, err
+	}
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		contextLogger.Error(err, "Unable to set the owner reference for the major upgrade job")
+		return nil, err
+	}
+	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.SetSpecHash(&job.ObjectMeta, job.Spec)
+
+	contextLogger.Info("Creating the major upgrade job", "name", job.Name, "oldImage", oldImage)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		contextLogger.Error(err, "Unable to create job", "job", job)
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// deleteMajorUpgradeJob deletes the major upgrade job of the
+// current primary instance, if any
+func (r *ClusterReconciler) deleteMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	job := findJobByName(resources.jobs.Items, specs.GetMajorUpgradeJobName(cluster.Status.CurrentPrimary))
+	if job == nil || !job.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting the major upgrade job", "job", job.Name)
+	foreground := metav1.DeletePropagationForeground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &foreground}); err != nil &&
+		!apierrs.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// findJobByName finds a job in a list given its name
+func findJobByName(jobs []batchv1.Job, name string) *batchv1.Job {
+	for idx := range jobs {
+		if jobs[idx].Name == name {
+			return &jobs[idx]
+		}
+	}
+
+	return nil
+}
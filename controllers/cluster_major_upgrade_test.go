@@ -0,0 +1,98 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Major upgrade", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:16.1",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "test-1",
+			},
+		}
+	})
+
+	It("keeps the image of the data directory recorded in the status", func() {
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{Image: "postgres:15.5", MajorVersion: 15}
+		Expect(getPGDataImageInfo(cluster, nil)).To(Equal(cluster.Status.PGDataImageInfo))
+	})
+
+	It("detects the image of the data directory from the current primary", func() {
+		primary := specs.PodWithExistingStorage(*cluster, 1)
+		primary.Spec.Containers[0].Image = "postgres:15.5"
+		replica := specs.PodWithExistingStorage(*cluster, 2)
+
+		Expect(getPGDataImageInfo(cluster, []corev1.Pod{*replica, *primary})).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:15.5",
+			MajorVersion: 15,
+		}))
+	})
+
+	It("uses the image of the cluster when no instance is running", func() {
+		Expect(getPGDataImageInfo(cluster, nil)).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:16.1",
+			MajorVersion: 16,
+		}))
+	})
+
+	It("ignores the images without a major version", func() {
+		cluster.Spec.ImageName = "postgres:latest"
+		Expect(getPGDataImageInfo(cluster, nil)).To(BeNil())
+	})
+
+	It("finds the jobs by name", func() {
+		jobs := []batchv1.Job{
+			{ObjectMeta: metav1.ObjectMeta{Name: "test-1-initdb"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "test-1-major-upgrade"}},
+		}
+		Expect(findJobByName(jobs, "test-1-major-upgrade")).To(Equal(&jobs[1]))
+		Expect(findJobByName(jobs, "test-2-major-upgrade")).To(BeNil())
+	})
+
+	It("finds the serial of the primary from its PVCs", func() {
+		pvc := func(serial string) corev1.PersistentVolumeClaim {
+			return corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{utils.ClusterSerialAnnotationName: serial},
+				},
+			}
+		}
+
+		Expect(getPrimarySerial(cluster, []corev1.PersistentVolumeClaim{pvc("2"), pvc("1")})).To(Equal(1))
+		Expect(getPrimarySerial(cluster, []corev1.PersistentVolumeClaim{pvc("2")})).Error().To(HaveOccurred())
+	})
+})
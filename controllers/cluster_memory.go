@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+var (
+	clusterMemoryEstimateDesc = prometheus.NewDesc(
+		"cnpg_cluster_memory_estimated_bytes",
+		"Estimated memory usage of each instance of the cluster in the worst case",
+		[]string{"namespace", "cluster"}, nil,
+	)
+	clusterMemoryHeadroomDesc = prometheus.NewDesc(
+		"cnpg_cluster_memory_headroom_bytes",
+		"Difference between the memory limit and the estimated memory usage of each instance, "+
+			"negative when the instances are likely to be OOM killed",
+		[]string{"namespace", "cluster"}, nil,
+	)
+)
+
+// collectMemoryMetrics exports the estimated memory usage of the instances
+// of a cluster and, when a memory limit is set, the headroom left by it
+func collectMemoryMetrics(ch chan<- prometheus.Metric, cluster *apiv1.Cluster) {
+	majorVersion, _ := cluster.GetPostgresqlMajorVersion()
+	estimate, err := postgres.EstimateMemoryUsage(cluster.Spec.PostgresConfiguration.Parameters, majorVersion)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		clusterMemoryEstimateDesc, prometheus.GaugeValue, float64(estimate.Total()),
+		cluster.Namespace, cluster.Name)
+
+	memoryLimit := cluster.Spec.Resources.Limits.Memory()
+	if memoryLimit.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		clusterMemoryHeadroomDesc, prometheus.GaugeValue, float64(memoryLimit.Value()-estimate.Total()),
+		cluster.Namespace, cluster.Name)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster memory metrics", func() {
+	collect := func(cluster *apiv1.Cluster) []*prometheus.Desc {
+		ch := make(chan prometheus.Metric, 10)
+		collectMemoryMetrics(ch, cluster)
+		close(ch)
+
+		var result []*prometheus.Desc
+		for metric := range ch {
+			result = append(result, metric.Desc())
+		}
+		return result
+	}
+
+	var cluster *apiv1.Cluster
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+	})
+
+	It("exports the estimated memory usage of the instances", func() {
+		Expect(collect(cluster)).To(Equal([]*prometheus.Desc{clusterMemoryEstimateDesc}))
+	})
+
+	It("exports the memory headroom when a memory limit is set", func() {
+		cluster.Spec.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}
+		Expect(collect(cluster)).To(Equal([]*prometheus.Desc{
+			clusterMemoryEstimateDesc,
+			clusterMemoryHeadroomDesc,
+		}))
+	})
+
+	It("doesn't export anything when the parameters can't be parsed", func() {
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{"work_mem": "a lot"}
+		Expect(collect(cluster)).To(BeEmpty())
+	})
+})
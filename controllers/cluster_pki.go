@@ -28,12 +28,16 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // setupPostgresPKI create all the PKI infrastructure that PostgreSQL need to work
 // if using ssl=on
 func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	// These are the certificates issued through cert-manager
+	if err := r.reconcileCertManagerCertificates(ctx, cluster); err != nil {
+		return err
+	}
+
 	// This is the CA of cluster
 	serverCaSecret, err := r.ensureServerCASecret(ctx, cluster)
 	if err != nil {
@@ -98,7 +102,8 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 
 // ensureClientCASecret ensure that the cluster CA really exist and is valid
 func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *apiv1.Cluster) (*v1.Secret, error) {
-	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ClientCASecret == "" {
+	certificates := cluster.Spec.Certificates
+	if certificates == nil || (certificates.ClientCASecret == "" && certificates.ReplicationIssuerRef == nil) {
 		return r.ensureCASecret(ctx, cluster, cluster.GetClientCASecretName())
 	}
 
@@ -135,7 +140,7 @@ func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *a
 func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *apiv1.Cluster) (*v1.Secret, error) {
 	// If not specified, use default amd renew/generate
 	certificates := cluster.Spec.Certificates
-	if certificates == nil || certificates.ServerCASecret == "" {
+	if certificates == nil || (certificates.ServerCASecret == "" && certificates.ServerIssuerRef == nil) {
 		return r.ensureCASecret(ctx, cluster, cluster.GetServerCASecretName())
 	}
 
@@ -214,7 +219,7 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	}
 
 	derivedCaSecret := caPair.GenerateCASecret(cluster.Namespace, secretName)
-	utils.SetAsOwnedBy(&derivedCaSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	cluster.SetInheritedDataAndOwnership(&derivedCaSecret.ObjectMeta)
 	err = r.Create(ctx, derivedCaSecret)
 
 	return derivedCaSecret, err
@@ -338,11 +343,8 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 		return err
 	}
 
-	utils.SetAsOwnedBy(&serverSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	cluster.SetInheritedDataAndOwnership(&serverSecret.ObjectMeta)
 	for k, v := range additionalLabels {
-		if serverSecret.Labels == nil {
-			serverSecret.Labels = make(map[string]string)
-		}
 		serverSecret.Labels[k] = v
 	}
 	return r.Create(ctx, serverSecret)
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,12 +33,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
@@ -49,6 +53,7 @@ type managedResources struct {
 	instances corev1.PodList
 	pvcs      corev1.PersistentVolumeClaimList
 	jobs      batchv1.JobList
+	jobPods   corev1.PodList
 }
 
 // Count the number of jobs that are still running
@@ -78,6 +83,23 @@ func (resources *managedResources) noInstanceIsAlive() bool {
 	return true
 }
 
+// getUnschedulablePods gets the instances and job Pods that the scheduler
+// cannot place, together with the reason it is reporting, sorted by name
+func (resources *managedResources) getUnschedulablePods() []string {
+	var result []string
+	for _, list := range []corev1.PodList{resources.instances, resources.jobPods} {
+		for idx := range list.Items {
+			pod := &list.Items[idx]
+			if message := utils.GetPodUnscheduledMessage(pod); message != "" {
+				result = append(result, fmt.Sprintf("%s (%s)", pod.Name, message))
+			}
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
 // Retrieve a PVC by name
 func (resources *managedResources) getPVC(name string) *corev1.PersistentVolumeClaim {
 	for _, pvc := range resources.pvcs.Items {
@@ -110,6 +132,11 @@ func (r *ClusterReconciler) getManagedResources(
 		return nil, err
 	}
 
+	jobPods, err := r.getManagedJobPods(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	nodes, err := r.getNodes(ctx)
 	if err != nil {
 		return nil, err
@@ -119,6 +146,7 @@ func (r *ClusterReconciler) getManagedResources(
 		instances: instances,
 		pvcs:      childPVCs,
 		jobs:      childJobs,
+		jobPods:   jobPods,
 		nodes:     nodes,
 	}, nil
 }
@@ -203,6 +231,24 @@ func (r *ClusterReconciler) getManagedJobs(
 	return childJobs, nil
 }
 
+// getManagedJobPods extract the list of Pods created by the jobs of
+// this cluster
+func (r *ClusterReconciler) getManagedJobPods(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (corev1.PodList, error) {
+	var jobPods corev1.PodList
+	if err := r.List(ctx, &jobPods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.JobRoleLabelName},
+	); err != nil {
+		return corev1.PodList{}, err
+	}
+
+	return jobPods, nil
+}
+
 // Set the PvcStatusAnnotation to Ready for a PVC
 func (r *ClusterReconciler) setPVCStatusReady(
 	ctx context.Context,
@@ -233,7 +279,8 @@ func (r *ClusterReconciler) updateResourceStatus(
 ) error {
 	// Retrieve the cluster key
 
-	existingClusterStatus := cluster.Status
+	// the conditions are updated in place, so we need a deep copy to detect their changes
+	existingClusterStatus := *cluster.Status.DeepCopy()
 
 	persistentvolumeclaim.EnrichStatus(
 		ctx,
@@ -259,9 +306,19 @@ func (r *ClusterReconciler) updateResourceStatus(
 		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint,
 	)
 
-	// Services
-	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
+	// Services. A permanent replica cluster has no read-write service
+	cluster.Status.WriteService = ""
+	if !cluster.IsPermanentReplica() {
+		cluster.Status.WriteService = cluster.GetServiceReadWriteName()
+	}
 	cluster.Status.ReadService = cluster.GetServiceReadName()
+	cluster.Status.InstancesFQDN = getInstancesFQDN(cluster)
+
+	if unschedulable := updatePodsSchedulability(cluster, resources); len(unschedulable) > 0 &&
+		!meta.IsStatusConditionFalse(existingClusterStatus.Conditions, string(apiv1.ConditionPodsSchedulable)) {
+		r.Recorder.Eventf(cluster, "Warning", "PodsUnschedulable",
+			"Pods that cannot be scheduled: %s", strings.Join(unschedulable, ", "))
+	}
 
 	// If we are switching, check if the target primary is still active
 	// Ignore this check if current primary is empty (it happens during the bootstrap)
@@ -751,16 +808,37 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster *apiv1.Cluster,
 	statuses postgres.PostgresqlStatusList,
 ) error {
-	existingClusterStatus := cluster.Status
+	// the conditions are updated in place, so we need a deep copy to detect their changes
+	existingClusterStatus := *cluster.Status.DeepCopy()
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
 	for _, item := range statuses.Items {
 		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
+			IsPrimary:   item.IsPrimary,
+			TimeLineID:  item.TimeLineID,
+			ImageDigest: specs.GetPostgresImageDigest(*item.Pod),
 		}
 	}
+	cluster.Status.LatestImageDigest = getLatestImageDigest(cluster, statuses)
+	updateReplicationHealth(cluster, statuses)
+	updateStorageHealth(cluster, statuses)
+	updateMinorUpdateAvailability(cluster)
+	if diverging := updateTimelinesAlignment(cluster, statuses); len(diverging) > 0 &&
+		!meta.IsStatusConditionFalse(existingClusterStatus.Conditions, string(apiv1.ConditionTimelinesAligned)) {
+		r.Recorder.Eventf(cluster, "Warning", "TimelinesDiverging",
+			"Instances on a different timeline than the primary: %s", strings.Join(diverging, ", "))
+	}
+	if drift := updateConfigurationDrift(cluster, statuses); len(drift) > 0 &&
+		!reflect.DeepEqual(drift, existingClusterStatus.ConfigurationDrift) {
+		r.Recorder.Eventf(cluster, "Warning", "ConfigurationDrift",
+			"Parameters changed with ALTER SYSTEM: %s", strings.Join(getDriftedParameterNames(drift), ", "))
+	}
+	var scheduledBackupList apiv1.ScheduledBackupList
+	if err := r.List(ctx, &scheduledBackupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return fmt.Errorf("while listing scheduled backups: %w", err)
+	}
+	updateClusterHealth(cluster, scheduledBackupList.Items)
 
 	// we update any relevant cluster status that depends on the primary instance
 	for _, item := range statuses.Items {
@@ -777,6 +855,289 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	return nil
 }
 
+// getLatestImageDigest returns the digest of the PostgreSQL image run by the
+// most recently created instance using the image of the cluster. Digests are
+// only tracked when the image pull policy is `Always`, as it's the only one
+// ensuring that a new Pod resolves the tag again. The registry is never
+// queried, so a new image behind the tag is only seen when a Pod is created
+func getLatestImageDigest(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) string {
+	if cluster.Spec.ImagePullPolicy != corev1.PullAlways {
+		return ""
+	}
+
+	var latestPod *corev1.Pod
+	var latestDigest string
+	for _, item := range statuses.Items {
+		imageName, err := specs.GetPostgresImageName(*item.Pod)
+		if err != nil || imageName != cluster.GetImageName() {
+			continue
+		}
+
+		digest := specs.GetPostgresImageDigest(*item.Pod)
+		if digest == "" {
+			continue
+		}
+
+		if latestPod == nil || latestPod.CreationTimestamp.Before(&item.Pod.CreationTimestamp) {
+			latestPod = item.Pod
+			latestDigest = digest
+		}
+	}
+
+	return latestDigest
+}
+
+// updateReplicationHealth refreshes the list of the replicas breaching the
+// replication lag thresholds, as seen by the primary, and the corresponding
+// condition. The last known state is kept when the primary can't be reached
+func updateReplicationHealth(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	slo := cluster.Spec.ReplicationLagSLO
+	if slo == nil {
+		cluster.Status.LaggingReplicas = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionReplicationHealthy))
+		return
+	}
+
+	var primary *postgres.PostgresqlStatus
+	for idx := range statuses.Items {
+		if statuses.Items[idx].IsPrimary && statuses.Items[idx].Error == nil {
+			primary = &statuses.Items[idx]
+			break
+		}
+	}
+	if primary == nil {
+		return
+	}
+
+	currentLsn, err := primary.CurrentLsn.Parse()
+	if err != nil {
+		return
+	}
+
+	var laggingReplicas []string
+	for _, replication := range primary.ReplicationInfo {
+		replayLsn, err := replication.ReplayLsn.Parse()
+		if err != nil {
+			continue
+		}
+
+		lagTime := time.Duration(replication.ReplayLagSeconds * float64(time.Second))
+		if slo.IsBreachedBy(currentLsn-replayLsn, lagTime) {
+			laggingReplicas = append(laggingReplicas, replication.ApplicationName)
+		}
+	}
+	sort.Strings(laggingReplicas)
+	cluster.Status.LaggingReplicas = laggingReplicas
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionReplicationHealthy),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ReplicationLagWithinSLO),
+		Message: "The replication lag of every replica is within the thresholds",
+	}
+	if len(laggingReplicas) > 0 {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReplicationHealthy),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ReplicationLagSLOBreached),
+			Message: fmt.Sprintf("The replication lag of the following replicas exceeds the thresholds: %s",
+				strings.Join(laggingReplicas, ", ")),
+		}
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// updateStorageHealth sets the condition reporting the instances whose
+// storage is failing. The condition is only added once a failure is detected
+func updateStorageHealth(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var failures []string
+	for _, item := range statuses.Items {
+		if item.HasStorageFailure() {
+			failures = append(failures, fmt.Sprintf("%s (%s)", item.Pod.Name, item.StorageFailure))
+		}
+	}
+	sort.Strings(failures)
+
+	if len(failures) > 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionStorageHealthy),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.StorageFailureDetected),
+			Message: fmt.Sprintf("Storage failure detected on: %s", strings.Join(failures, ", ")),
+		})
+		return
+	}
+
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionStorageHealthy)) != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionStorageHealthy),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.StorageFailureResolved),
+			Message: "No instance is reporting a storage failure",
+		})
+	}
+}
+
+// updateMinorUpdateAvailability sets the UpdateAvailable condition when the
+// operator knows about an image running a newer minor version of PostgreSQL.
+// The cluster is never updated automatically. The condition is removed when
+// the feature is disabled or the version of the image can't be detected
+func updateMinorUpdateAvailability(cluster *apiv1.Cluster) {
+	if !configuration.Current.EnableUpdateRecommendations {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionUpdateAvailable))
+		return
+	}
+
+	image := cluster.GetImageName()
+	latestImage, err := postgres.GetLatestMinorImage(image, configuration.Current.GetPostgresImageCatalog())
+	if err != nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionUpdateAvailable))
+		return
+	}
+
+	if latestImage != "" {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionUpdateAvailable),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.MinorUpdateAvailable),
+			Message: fmt.Sprintf("A newer minor version of PostgreSQL is available with the image %s", latestImage),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionUpdateAvailable),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ImageUpToDate),
+		Message: fmt.Sprintf("The image %s is running the latest known minor version of PostgreSQL", image),
+	})
+}
+
+// updatePodsSchedulability sets the PodsSchedulable condition depending on
+// the Pods that the scheduler cannot place, which are returned together with
+// the reason reported by the scheduler. The condition is added only after an
+// unschedulable Pod has been detected
+func updatePodsSchedulability(cluster *apiv1.Cluster, resources *managedResources) []string {
+	unschedulable := resources.getUnschedulablePods()
+	if len(unschedulable) > 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionPodsSchedulable),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.PodsUnschedulable),
+			Message: fmt.Sprintf("Pods that cannot be scheduled: %s", strings.Join(unschedulable, ", ")),
+		})
+		return unschedulable
+	}
+
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPodsSchedulable)) != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionPodsSchedulable),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.PodsScheduled),
+			Message: "Every Pod has been scheduled",
+		})
+	}
+
+	return nil
+}
+
+// updateTimelinesAlignment sets the TimelinesAligned condition depending on
+// the instances running on a different timeline than the primary, which are
+// returned. The condition is added only after a divergence has been detected.
+// The last known state is kept when the primary is not reporting its timeline
+func updateTimelinesAlignment(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) []string {
+	primaryTimeline := 0
+	for _, item := range statuses.Items {
+		if item.IsPrimary && item.Pod.Name == cluster.Status.CurrentPrimary {
+			primaryTimeline = item.TimeLineID
+			break
+		}
+	}
+	if primaryTimeline == 0 {
+		return nil
+	}
+
+	var diverging []string
+	for _, item := range statuses.Items {
+		if item.TimeLineID != 0 && item.TimeLineID != primaryTimeline {
+			diverging = append(diverging, fmt.Sprintf("%s (timeline %d)", item.Pod.Name, item.TimeLineID))
+		}
+	}
+	sort.Strings(diverging)
+
+	if len(diverging) > 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   string(apiv1.ConditionTimelinesAligned),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.TimelinesDiverging),
+			Message: fmt.Sprintf("The primary is on timeline %d, diverging instances: %s",
+				primaryTimeline, strings.Join(diverging, ", ")),
+		})
+		return diverging
+	}
+
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionTimelinesAligned)) != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionTimelinesAligned),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.TimelinesConverged),
+			Message: fmt.Sprintf("Every instance is on timeline %d", primaryTimeline),
+		})
+	}
+
+	return nil
+}
+
+// updateConfigurationDrift refreshes the list of the parameters whose live
+// value has been changed with ALTER SYSTEM, as reported by each instance.
+// The last known state of the instances that can't be reached is kept
+func updateConfigurationDrift(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) []apiv1.ParameterDrift {
+	reachable := make(map[string]bool, len(statuses.Items))
+	var drift []apiv1.ParameterDrift
+	for _, item := range statuses.Items {
+		if item.Error != nil {
+			continue
+		}
+		reachable[item.Pod.Name] = true
+
+		for name, value := range item.AlterSystemParameters {
+			drift = append(drift, apiv1.ParameterDrift{
+				Name:         name,
+				Instance:     item.Pod.Name,
+				Value:        value,
+				DesiredValue: cluster.Spec.PostgresConfiguration.Parameters[name],
+			})
+		}
+	}
+
+	for _, previous := range cluster.Status.ConfigurationDrift {
+		if !reachable[previous.Instance] && slices.Contains(cluster.Status.InstanceNames, previous.Instance) {
+			drift = append(drift, previous)
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Name != drift[j].Name {
+			return drift[i].Name < drift[j].Name
+		}
+		return drift[i].Instance < drift[j].Instance
+	})
+
+	cluster.Status.ConfigurationDrift = drift
+	return drift
+}
+
+// getDriftedParameterNames gets the sorted names of the drifted parameters
+func getDriftedParameterNames(drift []apiv1.ParameterDrift) []string {
+	var names []string
+	for _, item := range drift {
+		if !slices.Contains(names, item.Name) {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
@@ -809,3 +1170,18 @@ func getPodsTopology(
 
 	return apiv1.Topology{SuccessfullyExtracted: true, Instances: data, NodesUsed: int32(len(nodesMap))}
 }
+
+// getInstancesFQDN gets the stable DNS names of the instances of the
+// cluster, which are only available when the "-any" service is created
+func getInstancesFQDN(cluster *apiv1.Cluster) map[apiv1.PodName]string {
+	if !configuration.Current.CreateAnyService || len(cluster.Status.InstanceNames) == 0 {
+		return nil
+	}
+
+	result := make(map[apiv1.PodName]string, len(cluster.Status.InstanceNames))
+	for _, instanceName := range cluster.Status.InstanceNames {
+		result[apiv1.PodName(instanceName)] = cluster.GetInstanceFQDN(instanceName)
+	}
+
+	return result
+}
@@ -18,15 +18,24 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -165,4 +174,332 @@ var _ = Describe("cluster_status unit tests", func() {
 			}))
 		})
 	})
+
+	It("makes sure that getLatestImageDigest returns the digest of the newest instance", func() {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				ImageName:       "postgres:16",
+				ImagePullPolicy: corev1.PullAlways,
+			},
+		}
+		newInstance := func(name string, created time.Time, imageName, imageID string) postgres.PostgresqlStatus {
+			return postgres.PostgresqlStatus{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              name,
+						CreationTimestamp: metav1.NewTime(created),
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: specs.PostgresContainerName, Image: imageName},
+						},
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{Name: specs.PostgresContainerName, ImageID: imageID},
+						},
+					},
+				},
+			}
+		}
+		now := time.Now()
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstance("test-1", now.Add(-2*time.Hour), "postgres:16", "postgres@sha256:first"),
+				newInstance("test-2", now.Add(-time.Hour), "postgres:16", "postgres@sha256:second"),
+				newInstance("test-3", now, "postgres:15", "postgres@sha256:other"),
+			},
+		}
+
+		By("ignoring the instances running a different image", func() {
+			Expect(getLatestImageDigest(cluster, statuses)).To(Equal("sha256:second"))
+		})
+
+		By("not tracking the digest when the image pull policy is not Always", func() {
+			cluster.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+			Expect(getLatestImageDigest(cluster, statuses)).To(BeEmpty())
+		})
+	})
+
+	It("makes sure that updateReplicationHealth detects the lagging replicas", func() {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				ReplicationLagSLO: &v1.ReplicationLagSLO{
+					MaxLagSize: ptr.To(resource.MustParse("16Mi")),
+					MaxLagTime: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-1"}},
+					IsPrimary:  true,
+					CurrentLsn: "0/A000000",
+					ReplicationInfo: postgres.PgStatReplicationList{
+						{ApplicationName: "test-2", ReplayLsn: "0/9000000", ReplayLagSeconds: 1},
+						{ApplicationName: "test-3", ReplayLsn: "0/1000000", ReplayLagSeconds: 1},
+						{ApplicationName: "test-4", ReplayLsn: "0/A000000", ReplayLagSeconds: 120},
+					},
+				},
+			},
+		}
+
+		By("reporting the replicas breaching the thresholds", func() {
+			updateReplicationHealth(cluster, statuses)
+			Expect(cluster.Status.LaggingReplicas).To(Equal([]string{"test-3", "test-4"}))
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicationHealthy))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.ReplicationLagSLOBreached)))
+		})
+
+		By("keeping the last known state when the primary is not available", func() {
+			updateReplicationHealth(cluster, postgres.PostgresqlStatusList{})
+			Expect(cluster.Status.LaggingReplicas).To(Equal([]string{"test-3", "test-4"}))
+		})
+
+		By("marking the replication as healthy when every replica catches up", func() {
+			statuses.Items[0].ReplicationInfo = postgres.PgStatReplicationList{
+				{ApplicationName: "test-2", ReplayLsn: "0/A000000"},
+			}
+			updateReplicationHealth(cluster, statuses)
+			Expect(cluster.Status.LaggingReplicas).To(BeEmpty())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicationHealthy))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		By("removing the condition when no thresholds are defined", func() {
+			cluster.Spec.ReplicationLagSLO = nil
+			updateReplicationHealth(cluster, statuses)
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicationHealthy))).To(BeNil())
+		})
+	})
+
+	It("makes sure that updateStorageHealth reports the instances with a failing storage", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-1"}}},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-2"}}},
+			},
+		}
+
+		By("not adding the condition while the storage is working", func() {
+			updateStorageHealth(cluster, statuses)
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+
+		By("reporting the instances with a failing storage", func() {
+			statuses.Items[1].StorageFailure = "storage failure: read-only file system"
+			updateStorageHealth(cluster, statuses)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionStorageHealthy))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.StorageFailureDetected)))
+			Expect(condition.Message).To(ContainSubstring("test-2 (storage failure: read-only file system)"))
+		})
+
+		By("marking the failure as resolved", func() {
+			statuses.Items[1].StorageFailure = ""
+			updateStorageHealth(cluster, statuses)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionStorageHealthy))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(v1.StorageFailureResolved)))
+		})
+	})
+
+	It("makes sure that updateMinorUpdateAvailability reports the newer minor versions", func() {
+		cluster := &v1.Cluster{Spec: v1.ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:16.1"}}
+		DeferCleanup(func(config configuration.Data) {
+			configuration.Current.EnableUpdateRecommendations = config.EnableUpdateRecommendations
+			configuration.Current.PostgresImageCatalog = config.PostgresImageCatalog
+		}, *configuration.Current)
+
+		By("not adding the condition when the feature is disabled", func() {
+			configuration.Current.EnableUpdateRecommendations = false
+			updateMinorUpdateAvailability(cluster)
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+
+		By("reporting the image running the latest minor version", func() {
+			configuration.Current.EnableUpdateRecommendations = true
+			configuration.Current.PostgresImageCatalog = []string{"ghcr.io/cloudnative-pg/postgresql:16.3"}
+			updateMinorUpdateAvailability(cluster)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionUpdateAvailable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(v1.MinorUpdateAvailable)))
+			Expect(condition.Message).To(ContainSubstring("ghcr.io/cloudnative-pg/postgresql:16.3"))
+			Expect(cluster.Spec.ImageName).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.1"))
+		})
+
+		By("marking the image as up to date", func() {
+			cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.3"
+			updateMinorUpdateAvailability(cluster)
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionUpdateAvailable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.ImageUpToDate)))
+		})
+
+		By("removing the condition when the version can't be detected", func() {
+			cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:latest"
+			updateMinorUpdateAvailability(cluster)
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	It("makes sure that updatePodsSchedulability reports the pods that cannot be scheduled", func() {
+		cluster := &v1.Cluster{}
+		unschedulable := corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient cpu.",
+				},
+			},
+		}
+		resources := &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			}},
+			jobPods: corev1.PodList{Items: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "test-2-join-abcde"}, Status: unschedulable},
+			}},
+		}
+
+		By("not adding the condition while every pod is scheduled", func() {
+			Expect(updatePodsSchedulability(cluster, &managedResources{instances: resources.instances})).To(BeEmpty())
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+
+		By("reporting the reason of the scheduler", func() {
+			Expect(updatePodsSchedulability(cluster, resources)).To(Equal([]string{
+				"test-2-join-abcde (0/3 nodes are available: 3 Insufficient cpu.)",
+			}))
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionPodsSchedulable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.PodsUnschedulable)))
+			Expect(condition.Message).To(ContainSubstring("3 Insufficient cpu"))
+		})
+
+		By("marking the pods as scheduled", func() {
+			resources.jobPods.Items[0].Status = corev1.PodStatus{Phase: corev1.PodRunning}
+			Expect(updatePodsSchedulability(cluster, resources)).To(BeEmpty())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionPodsSchedulable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(v1.PodsScheduled)))
+		})
+	})
+
+	It("makes sure that updateTimelinesAlignment reports the instances on a diverging timeline", func() {
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{CurrentPrimary: "test-1"}}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-1"}}, IsPrimary: true, TimeLineID: 2},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-2"}}, TimeLineID: 2},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-3"}}},
+			},
+		}
+
+		By("not adding the condition while the timelines are aligned", func() {
+			Expect(updateTimelinesAlignment(cluster, statuses)).To(BeEmpty())
+			Expect(cluster.Status.Conditions).To(BeEmpty())
+		})
+
+		By("reporting the instances on a diverging timeline", func() {
+			statuses.Items[1].TimeLineID = 3
+			Expect(updateTimelinesAlignment(cluster, statuses)).To(Equal([]string{"test-2 (timeline 3)"}))
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionTimelinesAligned))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(v1.TimelinesDiverging)))
+		})
+
+		By("keeping the last state when the primary is not reporting its timeline", func() {
+			withoutPrimary := postgres.PostgresqlStatusList{Items: statuses.Items[1:]}
+			Expect(updateTimelinesAlignment(cluster, withoutPrimary)).To(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+				string(v1.ConditionTimelinesAligned))).To(BeTrue())
+		})
+
+		By("marking the timelines as converged", func() {
+			statuses.Items[1].TimeLineID = 2
+			Expect(updateTimelinesAlignment(cluster, statuses)).To(BeEmpty())
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionTimelinesAligned))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(v1.TimelinesConverged)))
+		})
+	})
+
+	It("makes sure that updateConfigurationDrift reports the parameters changed with ALTER SYSTEM", func() {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PostgresConfiguration: v1.PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "4MB"},
+				},
+			},
+			Status: v1.ClusterStatus{InstanceNames: []string{"test-1", "test-2"}},
+		}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-1"}},
+					AlterSystemParameters: map[string]string{"work_mem": "64MB", "log_statement": "all"},
+				},
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-2"}}},
+			},
+		}
+
+		By("reporting the drift of each instance", func() {
+			Expect(updateConfigurationDrift(cluster, statuses)).To(Equal([]v1.ParameterDrift{
+				{Name: "log_statement", Instance: "test-1", Value: "all"},
+				{Name: "work_mem", Instance: "test-1", Value: "64MB", DesiredValue: "4MB"},
+			}))
+			Expect(getDriftedParameterNames(cluster.Status.ConfigurationDrift)).To(Equal(
+				[]string{"log_statement", "work_mem"}))
+		})
+
+		By("keeping the last state of the instances that can't be reached", func() {
+			statuses.Items[0].Error = errors.New("unreachable")
+			Expect(updateConfigurationDrift(cluster, statuses)).To(HaveLen(2))
+		})
+
+		By("clearing the drift once reverted", func() {
+			statuses.Items[0].Error = nil
+			statuses.Items[0].AlterSystemParameters = nil
+			Expect(updateConfigurationDrift(cluster, statuses)).To(BeEmpty())
+			Expect(cluster.Status.ConfigurationDrift).To(BeEmpty())
+		})
+	})
+
+	It("makes sure that getInstancesFQDN returns the stable names only with the any service", func() {
+		cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: v1.ClusterStatus{
+				InstanceNames: []string{"cluster-example-1", "cluster-example-2"},
+			},
+		}
+
+		configuration.Current.CreateAnyService = false
+		Expect(getInstancesFQDN(cluster)).To(BeNil())
+
+		configuration.Current.CreateAnyService = true
+		DeferCleanup(func() {
+			configuration.Current.CreateAnyService = false
+		})
+		Expect(getInstancesFQDN(cluster)).To(Equal(map[v1.PodName]string{
+			"cluster-example-1": "cluster-example-1.cluster-example-any.default.svc",
+			"cluster-example-2": "cluster-example-2.cluster-example-any.default.svc",
+		}))
+	})
 })
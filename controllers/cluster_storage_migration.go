@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+)
+
+// migrateStorageClass recreates, one at a time, the instances having PVCs
+// using a storage class different from the requested one, when the cluster
+// is configured to migrate them. The replicas are recreated first, as the
+// missing instances are joined again using the new storage class, while the
+// primary is recreated after a switchover. It returns true when the migration
+// is in progress
+func (r *ClusterReconciler) migrateStorageClass(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) (bool, error) {
+	if cluster.GetStorageClassUpdatePolicy() != apiv1.StorageClassUpdatePolicyMigrate {
+		return false, nil
+	}
+
+	outdatedInstances := persistentvolumeclaim.GetInstancesWithOutdatedStorageClass(cluster, resources.pvcs.Items)
+	if len(outdatedInstances) == 0 {
+		return false, nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("outdatedInstances", outdatedInstances)
+	if len(instancesStatus.Items) < 2 {
+		contextLogger.Info("Cannot migrate the storage class of a cluster with a single instance")
+		return false, nil
+	}
+
+	if allowed, err := r.checkMaintenanceWindow(ctx, cluster); !allowed || err != nil {
+		return true, err
+	}
+
+	if acquired, err := r.acquireRolloutSlot(ctx, cluster); !acquired || err != nil {
+		return true, err
+	}
+
+	for _, instanceName := range outdatedInstances {
+		if instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		message := fmt.Sprintf("Recreating instance %s to migrate its storage class", instanceName)
+		contextLogger.Info(message)
+		r.Recorder.Event(cluster, "Normal", "StorageClassMigration", message)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseStorageClassMigration, message); err != nil {
+			return false, err
+		}
+
+		return true, r.ensureInstanceIsDeleted(ctx, cluster, instanceName)
+	}
+
+	// Only the primary is left, every replica is already using the
+	// new storage class
+	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
+		contextLogger.Info("Waiting for the user to request a switchover to migrate the storage class of the primary")
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+			"User must issue a supervised switchover"); err != nil {
+			return false, err
+		}
+
+		// The switchover is up to the user, let other clusters be rolled out
+		// in the meantime
+		r.rolloutManager.Release(client.ObjectKeyFromObject(cluster))
+
+		return true, nil
+	}
+
+	// The pod list is sorted in the same order we use for switchover / failover
+	var targetPrimary string
+	for _, item := range instancesStatus.Items {
+		if item.Pod.Name != cluster.Status.CurrentPrimary {
+			targetPrimary = item.Pod.Name
+			break
+		}
+	}
+
+	if !canAnnounceSwitchover(cluster) {
+		contextLogger.Info("The primary needs to be recreated to migrate its storage class, "+
+			"waiting for the planned switchover",
+			"plannedSwitchover", cluster.Status.PlannedSwitchover)
+		return true, nil
+	}
+
+	contextLogger.Info("The primary needs to be recreated to migrate its storage class, "+
+		"we'll trigger a switchover to do that",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", targetPrimary)
+	r.Recorder.Eventf(cluster, "Normal", "Switchover",
+		"Initiating switchover to %s to migrate the storage class of %s", targetPrimary, cluster.Status.CurrentPrimary)
+	return true, r.requestSwitchover(ctx, cluster, targetPrimary,
+		"the primary instance needs to be recreated to migrate its storage class")
+}
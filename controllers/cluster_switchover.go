@@ -0,0 +1,348 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// plannedSwitchoverCheckInterval is the time between two checks of an
+	// announced switchover waiting for the poolers to be paused
+	plannedSwitchoverCheckInterval = 1 * time.Second
+
+	// poolersPauseTimeout is the maximum time an announced switchover waits
+	// for the poolers to be paused. The switchover is aborted when the
+	// poolers are not paused in time
+	poolersPauseTimeout = 30 * time.Second
+
+	// preSwitchoverHooksTimeout is the maximum time allowed to the
+	// preSwitchover hooks, all together, to complete. The hooks run in
+	// the background, so this doesn't hold the reconciliation loop
+	preSwitchoverHooksTimeout = 2 * time.Minute
+
+	// switchoverCheckpointTimeout is the maximum time allowed to the
+	// checkpoint issued on the primary before changing the target primary
+	switchoverCheckpointTimeout = 30 * time.Second
+
+	// plannedSwitchoverRetryDelay is the time to wait before announcing
+	// again a switchover that has been aborted
+	plannedSwitchoverRetryDelay = 1 * time.Minute
+)
+
+// canAnnounceSwitchover checks if a new planned switchover can be requested,
+// that is if no switchover has already been announced and no switchover
+// has been recently aborted
+func canAnnounceSwitchover(cluster *apiv1.Cluster) bool {
+	if !cluster.IsSwitchoverOrchestrated() {
+		return true
+	}
+
+	planned := cluster.Status.PlannedSwitchover
+	if planned == nil {
+		return true
+	}
+
+	return planned.AbortedAt != nil && time.Since(planned.AbortedAt.Time) >= plannedSwitchoverRetryDelay
+}
+
+// requestSwitchover asks for a switchover to the passed instance. When the
+// switchover is orchestrated it is only announced, and the target primary
+// will be changed by reconcilePlannedSwitchover once the applications
+// have been drained
+func (r *ClusterReconciler) requestSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	targetPrimary string,
+	reason string,
+) error {
+	if !cluster.IsSwitchoverOrchestrated() {
+		return r.setPrimaryInstance(ctx, cluster, targetPrimary)
+	}
+
+	if !canAnnounceSwitchover(cluster) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Announcing a planned switchover",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", targetPrimary,
+		"reason", reason)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchoverAnnounced",
+		"Announcing a switchover from %s to %s: %s", cluster.Status.CurrentPrimary, targetPrimary, reason)
+
+	cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+		TargetPrimary: targetPrimary,
+		Reason:        reason,
+		AnnouncedAt:   metav1.Now(),
+	}
+	return r.Status().Update(ctx, cluster)
+}
+
+// reconcilePlannedSwitchover drives an announced switchover: it waits for
+// the poolers to be paused, invokes the preSwitchover hooks, verifies the
+// candidate, issues a checkpoint on the primary to shorten its shutdown
+// and finally changes the target primary. The switchover is aborted,
+// leaving the current primary in place, when any of these steps fails or
+// doesn't complete in time
+func (r *ClusterReconciler) reconcilePlannedSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	planned := cluster.Status.PlannedSwitchover
+	if !planned.IsPending() {
+		return nil, nil
+	}
+
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+			r.abortPlannedSwitchover(ctx, cluster, "the primary instance is already changing")
+	}
+
+	if planned.TargetPrimary == cluster.Status.CurrentPrimary {
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+			r.abortPlannedSwitchover(ctx, cluster, "the target instance is already the primary")
+	}
+
+	poolers, err := r.getPoolersWaitingForPause(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(poolers) > 0 {
+		if time.Since(planned.AnnouncedAt.Time) >= poolersPauseTimeout {
+			return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+				r.abortPlannedSwitchover(ctx, cluster,
+					fmt.Sprintf("the poolers %v have not been paused in %v", poolers, poolersPauseTimeout))
+		}
+
+		contextLogger.Info("Waiting for the poolers to be paused before switching over",
+			"poolers", poolers)
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}, nil
+	}
+
+	if planned.HooksCompletedAt == nil {
+		return r.reconcilePreSwitchoverHooks(ctx, cluster)
+	}
+
+	if err := verifySwitchoverCandidate(cluster, instancesStatus, planned.TargetPrimary); err != nil {
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+			r.abortPlannedSwitchover(ctx, cluster, err.Error())
+	}
+
+	if err := r.checkpointPrimary(ctx, cluster, instancesStatus); err != nil {
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+			r.abortPlannedSwitchover(ctx, cluster, err.Error())
+	}
+
+	contextLogger.Info("Applications drained, switching over",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", planned.TargetPrimary)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
+		"Switching over from %s to %s: %s", cluster.Status.CurrentPrimary, planned.TargetPrimary, planned.Reason)
+	origStatus := cluster.Status.DeepCopy()
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v: %v", planned.TargetPrimary, planned.Reason)); err != nil {
+		return nil, err
+	}
+
+	cluster.Status.PlannedSwitchover = nil
+	if err := r.setPrimaryInstance(ctx, cluster, planned.TargetPrimary); err != nil {
+		// The target primary has not been changed, so we restore the
+		// previous phase and let the poolers resume
+		cluster.Status = *origStatus
+		reason := fmt.Sprintf("cannot change the target primary: %v", err)
+		if abortErr := r.abortPlannedSwitchover(ctx, cluster, reason); abortErr != nil {
+			return nil, fmt.Errorf("while aborting the switchover after %s: %w", reason, abortErr)
+		}
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}, nil
+	}
+
+	return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}, nil
+}
+
+// reconcilePreSwitchoverHooks runs the preSwitchover hooks of the announced
+// switchover in the background, recording in the status when they complete
+// or aborting the switchover when they fail. Until then, the reconciliation
+// loop is requeued, so that a failover of the cluster is not delayed by
+// the hooks
+func (r *ClusterReconciler) reconcilePreSwitchoverHooks(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*ctrl.Result, error) {
+	planned := cluster.Status.PlannedSwitchover
+
+	// The hooks are bound to this announcement: they outlive the
+	// reconciliation loop, and are run again by a new announcement
+	hooksCluster := cluster.DeepCopy()
+	targetPrimary := planned.TargetPrimary
+	contextLogger := log.FromContext(ctx)
+	attempt := fmt.Sprintf("%s/%s", targetPrimary, planned.AnnouncedAt.UTC().Format(time.RFC3339))
+	done, err := r.switchoverHooks.Poll(client.ObjectKeyFromObject(cluster), attempt, func() error {
+		hooksCtx, cancel := context.WithTimeout(
+			log.IntoContext(context.Background(), contextLogger), preSwitchoverHooksTimeout)
+		defer cancel()
+		return lifecyclehooks.RunPreSwitchover(hooksCtx, hooksCluster, targetPrimary)
+	})
+	if !done {
+		contextLogger.Info("Waiting for the preSwitchover hooks to complete",
+			"targetPrimary", targetPrimary)
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}, nil
+	}
+
+	if err != nil {
+		return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval},
+			r.abortPlannedSwitchover(ctx, cluster, err.Error())
+	}
+
+	completedAt := metav1.Now()
+	planned.HooksCompletedAt = &completedAt
+	return &ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}, r.Status().Update(ctx, cluster)
+}
+
+// checkpointPrimary issues a checkpoint on the current primary, so that
+// the shutdown checkpoint following the switchover has less work to do
+func (r *ClusterReconciler) checkpointPrimary(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	primary := findInstancePod(instancesStatus, cluster.Status.CurrentPrimary)
+	if primary == nil {
+		return fmt.Errorf("the status of the primary instance %s is not available", cluster.Status.CurrentPrimary)
+	}
+
+	checkpointCtx, cancel := context.WithTimeout(ctx, switchoverCheckpointTimeout)
+	defer cancel()
+	if err := r.StatusClient.RequestCheckpoint(checkpointCtx, primary); err != nil {
+		return fmt.Errorf("cannot issue a checkpoint on the primary instance %s: %w",
+			cluster.Status.CurrentPrimary, err)
+	}
+
+	return nil
+}
+
+// findInstancePod returns the Pod of the passed instance, if it
+// reported its status
+func findInstancePod(instancesStatus postgres.PostgresqlStatusList, podName string) *corev1.Pod {
+	for _, item := range instancesStatus.Items {
+		if item.Pod != nil && item.Pod.Name == podName && item.HasHTTPStatus() {
+			return item.Pod
+		}
+	}
+
+	return nil
+}
+
+// abortPlannedSwitchover marks the announced switchover as aborted,
+// letting the poolers resume
+func (r *ClusterReconciler) abortPlannedSwitchover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason string,
+) error {
+	planned := cluster.Status.PlannedSwitchover
+
+	log.FromContext(ctx).Warning("Aborting the planned switchover",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", planned.TargetPrimary,
+		"reason", reason)
+	r.Recorder.Eventf(cluster, "Warning", "SwitchoverAborted",
+		"Aborting the switchover to %s: %s", planned.TargetPrimary, reason)
+
+	abortedAt := metav1.Now()
+	planned.AbortedAt = &abortedAt
+	planned.AbortReason = reason
+	return r.Status().Update(ctx, cluster)
+}
+
+// getPoolersWaitingForPause returns the names of the poolers of the
+// cluster that are expected to pause PgBouncer before a switchover
+// and have not done it yet
+func (r *ClusterReconciler) getPoolersWaitingForPause(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]string, error) {
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{poolerClusterKey: cluster.Name}); err != nil {
+		return nil, fmt.Errorf("while getting poolers for cluster %s: %w", cluster.Name, err)
+	}
+
+	return filterPoolersWaitingForPause(poolers), nil
+}
+
+// filterPoolersWaitingForPause returns the names of the poolers having
+// the pause on switchover enabled and not paused yet
+func filterPoolersWaitingForPause(poolers apiv1.PoolerList) []string {
+	var result []string
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		if pooler.IsPauseOnSwitchoverEnabled() && !pooler.IsPausedForSwitchover() {
+			result = append(result, pooler.Name)
+		}
+	}
+
+	return result
+}
+
+// verifySwitchoverCandidate checks if the instance chosen by a planned
+// switchover is still ready to be promoted, after the applications
+// have been drained
+func verifySwitchoverCandidate(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	targetPrimary string,
+) error {
+	if cluster.IsInstanceFenced(targetPrimary) {
+		return fmt.Errorf("the instance %s is fenced", targetPrimary)
+	}
+
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.Name != targetPrimary {
+			continue
+		}
+
+		switch {
+		case !item.HasHTTPStatus():
+			return fmt.Errorf("the status of the instance %s is not available", targetPrimary)
+		case !item.IsPodReady:
+			return fmt.Errorf("the instance %s is not ready", targetPrimary)
+		case !cluster.IsReplica() && !item.IsWalReceiverActive:
+			return fmt.Errorf("the instance %s is not streaming from the primary", targetPrimary)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("the instance %s does not exist", targetPrimary)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/backgroundtask"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("planned switchover", func() {
+	orchestratedCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				LifecycleHooks: &apiv1.LifecycleHooksConfiguration{
+					PreSwitchover: []apiv1.LifecycleHook{
+						{
+							Name: "drain",
+							HTTP: &apiv1.HTTPLifecycleHook{URL: "http://app.default.svc/drain"},
+						},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+	}
+
+	instance := func(name string, ready, streaming bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPodReady:          ready,
+			IsWalReceiverActive: streaming,
+		}
+	}
+
+	It("can always announce a switchover when it is not orchestrated", func() {
+		cluster := orchestratedCluster()
+		cluster.Spec.LifecycleHooks = nil
+		cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{TargetPrimary: "cluster-example-2"}
+		Expect(canAnnounceSwitchover(cluster)).To(BeTrue())
+	})
+
+	It("doesn't announce a switchover when another one is pending", func() {
+		cluster := orchestratedCluster()
+		Expect(canAnnounceSwitchover(cluster)).To(BeTrue())
+
+		cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+			TargetPrimary: "cluster-example-2",
+			AnnouncedAt:   metav1.Now(),
+		}
+		Expect(canAnnounceSwitchover(cluster)).To(BeFalse())
+	})
+
+	It("waits before announcing again an aborted switchover", func() {
+		cluster := orchestratedCluster()
+		abortedAt := metav1.Now()
+		cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+			TargetPrimary: "cluster-example-2",
+			AbortedAt:     &abortedAt,
+		}
+		Expect(canAnnounceSwitchover(cluster)).To(BeFalse())
+
+		abortedAt = metav1.NewTime(time.Now().Add(-plannedSwitchoverRetryDelay))
+		Expect(canAnnounceSwitchover(cluster)).To(BeTrue())
+	})
+
+	It("finds the poolers that still need to be paused", func() {
+		pausedSince := metav1.Now()
+		pauseEnabled := &apiv1.PgBouncerSpec{
+			PauseOnSwitchover: &apiv1.PgBouncerPauseOnSwitchoverConfiguration{Enabled: true},
+		}
+		poolers := apiv1.PoolerList{
+			Items: []apiv1.Pooler{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pooler-disabled"},
+					Spec:       apiv1.PoolerSpec{PgBouncer: &apiv1.PgBouncerSpec{}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pooler-paused"},
+					Spec:       apiv1.PoolerSpec{PgBouncer: pauseEnabled},
+					Status:     apiv1.PoolerStatus{SwitchoverPausedSince: &pausedSince},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pooler-waiting"},
+					Spec:       apiv1.PoolerSpec{PgBouncer: pauseEnabled},
+				},
+			},
+		}
+		Expect(filterPoolersWaitingForPause(poolers)).To(ConsistOf("pooler-waiting"))
+	})
+
+	It("accepts a ready and streaming candidate", func() {
+		cluster := orchestratedCluster()
+		instances := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instance("cluster-example-1", true, false),
+				instance("cluster-example-2", true, true),
+			},
+		}
+		Expect(verifySwitchoverCandidate(cluster, instances, "cluster-example-2")).To(Succeed())
+	})
+
+	It("refuses a candidate that cannot be promoted", func() {
+		cluster := orchestratedCluster()
+		instances := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instance("cluster-example-1", true, false),
+				instance("cluster-example-2", false, true),
+				instance("cluster-example-3", true, false),
+			},
+		}
+		Expect(verifySwitchoverCandidate(cluster, instances, "cluster-example-2")).ToNot(Succeed())
+		Expect(verifySwitchoverCandidate(cluster, instances, "cluster-example-3")).ToNot(Succeed())
+		Expect(verifySwitchoverCandidate(cluster, instances, "cluster-example-4")).ToNot(Succeed())
+	})
+
+	It("refuses a fenced candidate", func() {
+		cluster := orchestratedCluster()
+		cluster.Annotations = map[string]string{
+			utils.FencedInstanceAnnotation: `["cluster-example-2"]`,
+		}
+		instances := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instance("cluster-example-2", true, true),
+			},
+		}
+		Expect(verifySwitchoverCandidate(cluster, instances, "cluster-example-2")).ToNot(Succeed())
+	})
+
+	It("finds the pod of an instance reporting its status", func() {
+		failing := instance("cluster-example-2", true, true)
+		failing.Error = fmt.Errorf("connection refused")
+		instances := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				instance("cluster-example-1", true, false),
+				failing,
+			},
+		}
+		Expect(findInstancePod(instances, "cluster-example-1").Name).To(Equal("cluster-example-1"))
+		Expect(findInstancePod(instances, "cluster-example-2")).To(BeNil())
+		Expect(findInstancePod(instances, "cluster-example-3")).To(BeNil())
+	})
+
+	Context("preSwitchover hooks", func() {
+		var (
+			release    chan struct{}
+			statusCode int
+			cluster    *apiv1.Cluster
+			r          *ClusterReconciler
+		)
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			statusCode = http.StatusOK
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				<-release
+				w.WriteHeader(statusCode)
+			}))
+			DeferCleanup(server.Close)
+
+			cluster = orchestratedCluster()
+			cluster.Namespace = "default"
+			cluster.Spec.LifecycleHooks.PreSwitchover[0].HTTP.URL = server.URL
+			cluster.Spec.LifecycleHooks.PreSwitchover[0].FailurePolicy = apiv1.LifecycleHookFailurePolicyFail
+			cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+				TargetPrimary: "cluster-example-2",
+				AnnouncedAt:   metav1.Now(),
+			}
+			r = &ClusterReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+					WithObjects(cluster).
+					WithStatusSubresource(cluster).
+					Build(),
+				Recorder:        record.NewFakeRecorder(100),
+				switchoverHooks: backgroundtask.NewRunner(),
+			}
+		})
+
+		It("waits for the hooks without holding the reconciliation loop", func(ctx SpecContext) {
+			result, err := r.reconcilePreSwitchoverHooks(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(&ctrl.Result{RequeueAfter: plannedSwitchoverCheckInterval}))
+			Expect(cluster.Status.PlannedSwitchover.HooksCompletedAt).To(BeNil())
+
+			close(release)
+			Eventually(func(g Gomega) {
+				_, err := r.reconcilePreSwitchoverHooks(ctx, cluster)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cluster.Status.PlannedSwitchover.HooksCompletedAt).ToNot(BeNil())
+			}).Should(Succeed())
+
+			var stored apiv1.Cluster
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+			Expect(stored.Status.PlannedSwitchover.HooksCompletedAt).ToNot(BeNil())
+			Expect(stored.Status.PlannedSwitchover.IsPending()).To(BeTrue())
+		})
+
+		It("aborts the switchover when a hook fails", func(ctx SpecContext) {
+			statusCode = http.StatusServiceUnavailable
+			close(release)
+
+			Eventually(func(g Gomega) {
+				_, err := r.reconcilePreSwitchoverHooks(ctx, cluster)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cluster.Status.PlannedSwitchover.IsPending()).To(BeFalse())
+			}).Should(Succeed())
+			Expect(cluster.Status.PlannedSwitchover.HooksCompletedAt).To(BeNil())
+			Expect(cluster.Status.PlannedSwitchover.AbortReason).To(ContainSubstring("drain"))
+		})
+	})
+})
@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+var clusterUpdateAvailableDesc = prometheus.NewDesc(
+	"cnpg_cluster_update_available",
+	"1 if a newer minor version of PostgreSQL is available in the image catalog of the operator, "+
+		"0 if the cluster is running the latest known one",
+	[]string{"namespace", "cluster"}, nil,
+)
+
+// getUpdateAvailability returns the value of the update availability
+// metric of a cluster, as reported by its UpdateAvailable condition, and
+// false when the condition is not set
+func getUpdateAvailability(cluster *apiv1.Cluster) (float64, bool) {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionUpdateAvailable))
+	if condition == nil {
+		return 0, false
+	}
+
+	if condition.Status == metav1.ConditionTrue {
+		return 1, true
+	}
+
+	return 0, true
+}
+
+// collectUpdateAvailabilityMetrics exports whether a newer minor version of
+// PostgreSQL is available for a cluster. Nothing is exported when the update
+// recommendations are disabled or the version of the image is unknown
+func collectUpdateAvailabilityMetrics(ch chan<- prometheus.Metric, cluster *apiv1.Cluster) {
+	value, ok := getUpdateAvailability(cluster)
+	if !ok {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		clusterUpdateAvailableDesc, prometheus.GaugeValue, value,
+		cluster.Namespace, cluster.Name)
+}
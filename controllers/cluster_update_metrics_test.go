@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster update availability metrics", func() {
+	collect := func(cluster *apiv1.Cluster) []*prometheus.Desc {
+		ch := make(chan prometheus.Metric, 10)
+		collectUpdateAvailabilityMetrics(ch, cluster)
+		close(ch)
+
+		var result []*prometheus.Desc
+		for metric := range ch {
+			result = append(result, metric.Desc())
+		}
+		return result
+	}
+
+	newCluster := func(status metav1.ConditionStatus) *apiv1.Cluster {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		if status != "" {
+			cluster.Status.Conditions = []metav1.Condition{
+				{Type: string(apiv1.ConditionUpdateAvailable), Status: status},
+			}
+		}
+		return cluster
+	}
+
+	It("reports 1 when a newer minor version is available", func() {
+		cluster := newCluster(metav1.ConditionTrue)
+		value, ok := getUpdateAvailability(cluster)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeEquivalentTo(1))
+		Expect(collect(cluster)).To(Equal([]*prometheus.Desc{clusterUpdateAvailableDesc}))
+	})
+
+	It("reports 0 when the cluster is running the latest known minor version", func() {
+		cluster := newCluster(metav1.ConditionFalse)
+		value, ok := getUpdateAvailability(cluster)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeEquivalentTo(0))
+		Expect(collect(cluster)).To(Equal([]*prometheus.Desc{clusterUpdateAvailableDesc}))
+	})
+
+	It("doesn't export anything when the condition is not set", func() {
+		cluster := newCluster("")
+		_, ok := getUpdateAvailability(cluster)
+		Expect(ok).To(BeFalse())
+		Expect(collect(cluster)).To(BeEmpty())
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	neturl "net/url"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -71,6 +72,20 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 			continue
 		}
 
+		if !podRollout.requestedByUser {
+			if allowed, err := r.checkMaintenanceWindow(ctx, cluster); !allowed || err != nil {
+				return true, err
+			}
+		}
+
+		if ready, err := r.checkImageUpgradeSafety(ctx, cluster, podList); !ready || err != nil {
+			return true, err
+		}
+
+		if acquired, err := r.acquireRolloutSlot(ctx, cluster); !acquired || err != nil {
+			return true, err
+		}
+
 		restartMessage := fmt.Sprintf("Restarting instance %s, because: %s",
 			postgresqlStatus.Pod.Name, podRollout.reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade, restartMessage); err != nil {
@@ -104,10 +119,53 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		return false, nil
 	}
 
+	if !podRollout.requestedByUser {
+		if allowed, err := r.checkMaintenanceWindow(ctx, cluster); !allowed || err != nil {
+			return true, err
+		}
+	}
+
+	if ready, err := r.checkImageUpgradeSafety(ctx, cluster, podList); !ready || err != nil {
+		return true, err
+	}
+
 	return r.updatePrimaryPod(ctx, cluster, podList, *primaryPostgresqlStatus.Pod,
 		podRollout.canBeInPlace, podRollout.primaryForceRecreate, podRollout.reason)
 }
 
+// checkMaintenanceWindow checks whether the operator is allowed to roll out
+// the cluster now, given its maintenance windows. If not, the cluster is
+// marked as waiting for the next maintenance window
+func (r *ClusterReconciler) checkMaintenanceWindow(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	now := time.Now()
+	if cluster.IsInMaintenanceWindow(now) {
+		return true, nil
+	}
+
+	// Other clusters can be rolled out in the meantime
+	r.rolloutManager.Release(client.ObjectKeyFromObject(cluster))
+
+	nextStart := cluster.GetNextMaintenanceWindowStart(now)
+	log.FromContext(ctx).Info("Waiting for the maintenance window to roll out the cluster",
+		"nextMaintenanceWindowStart", nextStart)
+	return false, r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForMaintenanceWindow,
+		fmt.Sprintf("Next maintenance window starts at %s", nextStart.Format(time.RFC3339)))
+}
+
+// acquireRolloutSlot checks whether the cluster is allowed to be rolled out,
+// given the maximum number of concurrent rollouts configured in the operator.
+// If not, the cluster is marked as waiting for a rollout slot
+func (r *ClusterReconciler) acquireRolloutSlot(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	acquired, position := r.rolloutManager.Acquire(client.ObjectKeyFromObject(cluster))
+	if acquired {
+		return true, nil
+	}
+
+	log.FromContext(ctx).Info("Waiting for a rollout slot", "queuePosition", position)
+	return false, r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForRolloutSlot,
+		fmt.Sprintf("Position %d in the rollout queue", position))
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -121,7 +179,8 @@ func (r *ClusterReconciler) updatePrimaryPod(
 
 	// we need to check whether a manual switchover is required
 	contextLogger = contextLogger.WithValues("primaryPod", primaryPod.Name)
-	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
+	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised &&
+		!utils.IsPrimaryUpdateApproved(&cluster.ObjectMeta) {
 		contextLogger.Info("Waiting for the user to request a switchover to complete the rolling update",
 			"reason", reason)
 		err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser, "User must issue a supervised switchover")
@@ -129,9 +188,21 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			return false, err
 		}
 
+		// The switchover is up to the user, let other clusters be rolled out
+		// in the meantime
+		r.rolloutManager.Release(client.ObjectKeyFromObject(cluster))
+
 		return true, nil
 	}
 
+	if acquired, err := r.acquireRolloutSlot(ctx, cluster); !acquired || err != nil {
+		return true, err
+	}
+
+	if err := r.consumePrimaryUpdateApproval(ctx, cluster); err != nil {
+		return false, err
+	}
+
 	if cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodRestart || forceRecreate {
 		if inPlacePossible {
 			// In-place restart is possible
@@ -169,6 +240,13 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			targetPrimary = podList.Items[0].Pod.Name
 		}
 
+		if !canAnnounceSwitchover(cluster) {
+			contextLogger.Info("The primary needs to be restarted, waiting for the planned switchover",
+				"reason", reason,
+				"plannedSwitchover", cluster.Status.PlannedSwitchover)
+			return true, nil
+		}
+
 		contextLogger.Info("The primary needs to be restarted, we'll trigger a switchover to do that",
 			"reason", reason,
 			"currentPrimary", primaryPod.Name,
@@ -176,7 +254,8 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			"podList", podList)
 		r.Recorder.Eventf(cluster, "Normal", "Switchover",
 			"Initiating switchover to %s to upgrade %s", targetPrimary, primaryPod.Name)
-		return true, r.setPrimaryInstance(ctx, cluster, targetPrimary)
+		return true, r.requestSwitchover(ctx, cluster, targetPrimary,
+			fmt.Sprintf("the primary instance needs to be restarted: %s", reason))
 	}
 
 	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
@@ -190,6 +269,27 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod, reason)
 }
 
+// consumePrimaryUpdateApproval removes the annotation approving a supervised
+// rolling update of the primary, so that the following rolling updates
+// need a new approval
+func (r *ClusterReconciler) consumePrimaryUpdateApproval(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.GetPrimaryUpdateStrategy() != apiv1.PrimaryUpdateStrategySupervised ||
+		!utils.IsPrimaryUpdateApproved(&cluster.ObjectMeta) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Completing the supervised rolling update approved by the user")
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.ApprovePrimaryUpdateAnnotationName)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "PrimaryUpdateApproved",
+		"Completing the supervised rolling update of the primary approved by the user")
+	return nil
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -211,13 +311,55 @@ func (r *ClusterReconciler) updateRestartAnnotation(
 	return nil
 }
 
+// updateResourcesChecksumAnnotation sets the checksum of the secrets and
+// config maps on the instance pods not having it yet and, when the changes
+// are applied by reloading the configuration, on the ones having an outdated one
+func (r *ClusterReconciler) updateResourcesChecksumAnnotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+	checksum := specs.GetResourcesChecksum(*cluster)
+	updatePolicy := cluster.GetResourcesUpdatePolicy()
+
+	for idx := range instancesStatus.Items {
+		pod := instancesStatus.Items[idx].Pod
+		if pod == nil {
+			continue
+		}
+
+		podChecksum, ok := pod.Annotations[utils.ResourcesChecksumAnnotationName]
+		if podChecksum == checksum ||
+			(ok && updatePolicy != apiv1.ResourcesUpdatePolicyReload) {
+			continue
+		}
+
+		contextLogger.Debug("Updating the resources checksum annotation",
+			"pod", pod.Name, "checksum", checksum)
+		original := pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[utils.ResourcesChecksumAnnotationName] = checksum
+		if err := r.Client.Patch(ctx, pod, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // rollout describes whether a rollout should happen, and if so whether it can
 // be done in-place, and what the reason for the rollout is
 type rollout struct {
 	required             bool
 	canBeInPlace         bool
 	primaryForceRecreate bool
-	reason               string
+	// requestedByUser is true when the rollout has been explicitly asked
+	// by the user, and is not subject to the maintenance windows
+	requestedByUser bool
+	reason          string
 }
 
 type rolloutChecker func(
@@ -268,10 +410,13 @@ func isPodNeedingRollout(
 		"instance is missing executable hash":  checkHasExecutableHash,
 		"pod has missing PVCs":                 checkHasMissingPVCs,
 		"pod has PVC requiring resizing":       checkHasResizingPVC,
+		"pod has PVC requiring FS resizing":    checkHasFileSystemResizePendingPVC,
 		"pod projected volume is outdated":     checkProjectedVolumeIsOutdated,
 		"pod image is outdated":                checkPodImageIsOutdated,
+		"pod image digest is outdated":         checkPodImageDigestIsOutdated,
 		"postgres restart required":            checkPostgresPendingRestart,
 		"cluster has newer restart annotation": checkClusterHasNewerRestartAnnotation,
+		"projected secrets changed":            checkResourcesChecksumIsOutdated,
 	}
 
 	podRollout := applyCheckers(checkers)
@@ -346,6 +491,21 @@ func checkHasResizingPVC(
 	return rollout{}, nil
 }
 
+func checkHasFileSystemResizePendingPVC(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	for _, pvcName := range cluster.Status.FileSystemResizePendingPVC {
+		if persistentvolumeclaim.BelongToInstance(cluster, status.Pod.Name, pvcName) {
+			return rollout{
+				required: true,
+				reason:   fmt.Sprintf("rebooting pod to complete the file system resize of %s", pvcName),
+			}, nil
+		}
+	}
+	return rollout{}, nil
+}
+
 func checkPodNeedsUpdatedTopology(
 	status postgres.PostgresqlStatus,
 	cluster *apiv1.Cluster,
@@ -460,9 +620,42 @@ func checkPodImageIsOutdated(
 	}, nil
 }
 
+// checkPodImageDigestIsOutdated detects the instances running the image of
+// the cluster with a digest that is older than the one resolved by the most
+// recently created instance, i.e. when the image behind a mutable tag changed
+func checkPodImageDigestIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	if cluster.Spec.ImagePullPolicy != corev1.PullAlways || cluster.Status.LatestImageDigest == "" {
+		return rollout{}, nil
+	}
+
+	pgCurrentImageName, err := specs.GetPostgresImageName(*status.Pod)
+	if err != nil {
+		return rollout{}, err
+	}
+
+	// A different image name is handled by checkPodImageIsOutdated
+	if pgCurrentImageName != cluster.GetImageName() {
+		return rollout{}, nil
+	}
+
+	currentDigest := specs.GetPostgresImageDigest(*status.Pod)
+	if currentDigest == "" || currentDigest == cluster.Status.LatestImageDigest {
+		return rollout{}, nil
+	}
+
+	return rollout{
+		required: true,
+		reason: fmt.Sprintf("the instance is using an old image digest: %s -> %s",
+			currentDigest, cluster.Status.LatestImageDigest),
+	}, nil
+}
+
 func checkPodInitContainerIsOutdated(
 	status postgres.PostgresqlStatus,
-	_ *apiv1.Cluster,
+	cluster *apiv1.Cluster,
 ) (rollout, error) {
 	if configuration.Current.EnableInstanceManagerInplaceUpdates {
 		return rollout{}, nil
@@ -473,7 +666,8 @@ func checkPodInitContainerIsOutdated(
 		return rollout{}, err
 	}
 
-	if opCurrentImageName == configuration.Current.OperatorImageName {
+	opTargetImageName := cluster.GetBootstrapControllerImageName()
+	if opCurrentImageName == opTargetImageName {
 		return rollout{}, nil
 	}
 
@@ -481,7 +675,7 @@ func checkPodInitContainerIsOutdated(
 	return rollout{
 		required: true,
 		reason: fmt.Sprintf("the instance is using an old init container image: %s -> %s",
-			opCurrentImageName, configuration.Current.OperatorImageName),
+			opCurrentImageName, opTargetImageName),
 	}, nil
 }
 
@@ -508,9 +702,10 @@ func checkClusterHasNewerRestartAnnotation(
 		podRestart := status.Pod.Annotations[utils.ClusterRestartAnnotationName]
 		if clusterRestart != podRestart {
 			return rollout{
-				required:     true,
-				reason:       "cluster has been explicitly restarted via annotation",
-				canBeInPlace: true,
+				required:        true,
+				reason:          "cluster has been explicitly restarted via annotation",
+				canBeInPlace:    true,
+				requestedByUser: true,
 			}, nil
 		}
 	}
@@ -518,6 +713,31 @@ func checkClusterHasNewerRestartAnnotation(
 	return rollout{}, nil
 }
 
+func checkResourcesChecksumIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	if cluster.GetResourcesUpdatePolicy() != apiv1.ResourcesUpdatePolicyRestart {
+		return rollout{}, nil
+	}
+
+	// Pods created by an older version of the operator don't have the
+	// checksum: they will get it in place without being restarted
+	podChecksum, ok := status.Pod.Annotations[utils.ResourcesChecksumAnnotationName]
+	if !ok {
+		return rollout{}, nil
+	}
+
+	if podChecksum != specs.GetResourcesChecksum(*cluster) {
+		return rollout{
+			required: true,
+			reason:   "the secrets projected into the instance changed",
+		}, nil
+	}
+
+	return rollout{}, nil
+}
+
 func checkPostgresPendingRestart(
 	status postgres.PostgresqlStatus,
 	_ *apiv1.Cluster,
@@ -596,7 +816,7 @@ func checkPodSpecIsOutdated(
 		return rollout{}, fmt.Errorf("while unmarshaling the pod resources annotation: %w", err)
 	}
 	envConfig := specs.CreatePodEnvConfig(*cluster, status.Pod.Name)
-	gracePeriod := int64(cluster.GetMaxStopDelay())
+	gracePeriod := cluster.GetTerminationGracePeriodSeconds()
 	targetPodSpec := specs.CreateClusterPodSpec(status.Pod.Name, *cluster, envConfig, gracePeriod)
 
 	// the bootstrap init-container could change image after an operator upgrade.
@@ -605,18 +825,20 @@ func checkPodSpecIsOutdated(
 	if err != nil {
 		return rollout{}, err
 	}
-	if opCurrentImageName != configuration.Current.OperatorImageName &&
+	opTargetImageName := cluster.GetBootstrapControllerImageName()
+	if opCurrentImageName != opTargetImageName &&
 		!configuration.Current.EnableInstanceManagerInplaceUpdates {
 		return rollout{
 			required: true,
 			reason: fmt.Sprintf("the instance is using an old init container image: %s -> %s",
-				opCurrentImageName, configuration.Current.OperatorImageName),
+				opCurrentImageName, opTargetImageName),
 		}, nil
 	}
 
-	// from here we don't care about drift in the init containers: avoid checking them
-	storedPodSpec.InitContainers = nil
-	targetPodSpec.InitContainers = nil
+	// from here we don't care about drift in the bootstrap init container: avoid
+	// checking it, while still checking the additional init containers
+	storedPodSpec.InitContainers = withoutBootstrapContainer(storedPodSpec.InitContainers)
+	targetPodSpec.InitContainers = withoutBootstrapContainer(targetPodSpec.InitContainers)
 
 	match, diff := specs.ComparePodSpecs(storedPodSpec, targetPodSpec)
 	if !match {
@@ -629,6 +851,19 @@ func checkPodSpecIsOutdated(
 	return rollout{}, nil
 }
 
+// withoutBootstrapContainer returns the passed init containers, excluding the
+// one bootstrapping the instance manager
+func withoutBootstrapContainer(containers []corev1.Container) []corev1.Container {
+	var result []corev1.Container
+	for _, container := range containers {
+		if container.Name != specs.BootstrapControllerContainerName {
+			result = append(result, container)
+		}
+	}
+
+	return result
+}
+
 // upgradePod deletes a Pod to let the operator recreate it using an
 // updated definition
 func (r *ClusterReconciler) upgradePod(
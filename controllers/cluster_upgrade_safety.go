@@ -0,0 +1,189 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// imageUpgradeRestorePointPrefix is the prefix of the name of the restore
+// points created before rolling out a new PostgreSQL image
+const imageUpgradeRestorePointPrefix = "pre-upgrade-"
+
+// imageUpgradeRestorePointTimeLayout is the layout of the time the upgrade
+// started, in the name of the restore points created before an upgrade
+const imageUpgradeRestorePointTimeLayout = "20060102150405"
+
+// checkImageUpgradeSafety runs the safety checks configured for the rollout
+// of a new PostgreSQL image, before the first instance is restarted.
+// It returns true when the rollout can proceed
+func (r *ClusterReconciler) checkImageUpgradeSafety(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+) (bool, error) {
+	if !isImageUpgradeStarting(cluster, podList) {
+		return true, nil
+	}
+
+	return r.enforceImageUpgradeSafety(ctx, cluster)
+}
+
+// enforceImageUpgradeSafety runs the configured image upgrade safety
+// checks, applying the failure policy when they are not satisfied.
+// It returns true when the upgrade can proceed
+func (r *ClusterReconciler) enforceImageUpgradeSafety(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if cluster.Spec.ImageUpgradeSafety == nil {
+		return true, nil
+	}
+
+	now := time.Now()
+	failures := getBackupAgeFailures(cluster, now)
+	if cluster.Spec.ImageUpgradeSafety.CreateRestorePoint && !cluster.IsReplica() {
+		name := getImageUpgradeRestorePointName(cluster, now)
+		created, err := r.ensureRestorePoint(ctx, cluster, name)
+		if err != nil {
+			return false, err
+		}
+		if !created {
+			failures = append(failures, fmt.Sprintf("the restore point %s has not been created yet", name))
+		}
+	}
+
+	if len(failures) == 0 {
+		return true, nil
+	}
+
+	message := strings.Join(failures, ", ")
+	if cluster.GetImageUpgradeSafetyFailurePolicy() == apiv1.ImageUpgradeSafetyFailurePolicyWarn {
+		r.Recorder.Eventf(cluster, "Warning", "ImageUpgradeSafetyNotSatisfied",
+			"Rolling out the new image anyway: %s", message)
+		return true, nil
+	}
+
+	// Other clusters can be rolled out in the meantime
+	r.rolloutManager.Release(client.ObjectKeyFromObject(cluster))
+
+	log.FromContext(ctx).Info("Waiting for the image upgrade safety checks", "reason", message)
+	return false, r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForImageUpgradeSafety, message)
+}
+
+// isImageUpgradeStarting checks whether the image upgrade safety checks
+// are configured and no instance is running the new image yet, i.e. the
+// next restart will be the first one on the new image
+func isImageUpgradeStarting(cluster *apiv1.Cluster, podList *postgres.PostgresqlStatusList) bool {
+	if cluster.Spec.ImageUpgradeSafety == nil {
+		return false
+	}
+
+	targetImageName := cluster.GetImageName()
+	outdated := false
+	for _, status := range podList.Items {
+		imageName, err := specs.GetPostgresImageName(*status.Pod)
+		if err != nil {
+			continue
+		}
+		if imageName == targetImageName {
+			return false
+		}
+		outdated = true
+	}
+
+	return outdated
+}
+
+// getBackupAgeFailures checks that the last successful backup of the
+// cluster is recent enough, returning the reason why it is not
+func getBackupAgeFailures(cluster *apiv1.Cluster, now time.Time) []string {
+	maxBackupAge := cluster.Spec.ImageUpgradeSafety.MaxBackupAge
+	if maxBackupAge == nil {
+		return nil
+	}
+
+	lastBackup, found := cluster.GetLastSuccessfulBackupTime()
+	if !found {
+		return []string{"no successful backup has been found"}
+	}
+
+	if age := now.Sub(lastBackup.Time); age > maxBackupAge.Duration {
+		return []string{fmt.Sprintf("the last successful backup is older than %s (%s)",
+			maxBackupAge.Duration, lastBackup.Format(time.RFC3339))}
+	}
+
+	return nil
+}
+
+// getImageUpgradeRestorePointName gets the name of the restore point
+// created before rolling out the image of the cluster, made of a hash of the
+// image and of the time the upgrade started. The name of the restore point
+// already requested for the same image is kept, so that it is stable until
+// the restore point has been created, while upgrading to the same image
+// again later creates a new restore point
+func getImageUpgradeRestorePointName(cluster *apiv1.Cluster, now time.Time) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(cluster.GetImageName())))
+	prefix := imageUpgradeRestorePointPrefix + hash[:12] + "-"
+	if requested := cluster.Annotations[utils.RestorePointAnnotationName]; strings.HasPrefix(requested, prefix) {
+		return requested
+	}
+
+	return prefix + now.UTC().Format(imageUpgradeRestorePointTimeLayout)
+}
+
+// ensureRestorePoint requests the creation of the passed restore point
+// through the restore point annotation, returning true once the primary
+// instance has recorded it, with its LSN, in the status of the cluster.
+// The instance manager completes a recorded restore point lacking its LSN
+// as long as its name is in the annotation, which is set again if it has
+// been replaced in the meantime
+func (r *ClusterReconciler) ensureRestorePoint(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	name string,
+) (bool, error) {
+	if restorePoint := cluster.GetRestorePoint(name); restorePoint != nil && restorePoint.LSN != "" {
+		return true, nil
+	}
+
+	if cluster.Annotations[utils.RestorePointAnnotationName] == name {
+		return false, nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.RestorePointAnnotationName] = name
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return false, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "RestorePointRequested",
+		"Requested the restore point %s before upgrading to the image %s", name, cluster.GetImageName())
+	return false, nil
+}
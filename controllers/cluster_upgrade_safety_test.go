@@ -0,0 +1,194 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Image upgrade safety", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName:          "postgres:16.1",
+				ImageUpgradeSafety: &apiv1.ImageUpgradeSafetyConfiguration{},
+			},
+		}
+	})
+
+	podListWithImages := func(images ...string) *postgres.PostgresqlStatusList {
+		podList := &postgres.PostgresqlStatusList{}
+		for idx, image := range images {
+			pod := specs.PodWithExistingStorage(*cluster, idx+1)
+			pod.Spec.Containers[0].Image = image
+			podList.Items = append(podList.Items, postgres.PostgresqlStatus{Pod: pod})
+		}
+		return podList
+	}
+
+	It("detects the first restart on a new image", func() {
+		Expect(isImageUpgradeStarting(cluster, podListWithImages("postgres:16.0", "postgres:16.0"))).To(BeTrue())
+	})
+
+	It("ignores the rollouts already running the new image", func() {
+		Expect(isImageUpgradeStarting(cluster, podListWithImages("postgres:16.0", "postgres:16.1"))).To(BeFalse())
+		Expect(isImageUpgradeStarting(cluster, podListWithImages("postgres:16.1"))).To(BeFalse())
+	})
+
+	It("ignores the clusters without image upgrade safety checks", func() {
+		cluster.Spec.ImageUpgradeSafety = nil
+		Expect(isImageUpgradeStarting(cluster, podListWithImages("postgres:16.0"))).To(BeFalse())
+	})
+
+	It("accepts any backup when the maximum backup age is not set", func() {
+		Expect(getBackupAgeFailures(cluster, time.Now())).To(BeEmpty())
+	})
+
+	It("requires a recent successful backup", func() {
+		now := time.Now()
+		cluster.Spec.ImageUpgradeSafety.MaxBackupAge = &metav1.Duration{Duration: time.Hour}
+		Expect(getBackupAgeFailures(cluster, now)).To(HaveLen(1))
+
+		cluster.Status.LastSuccessfulBackupByMethod = map[apiv1.BackupMethod]metav1.Time{
+			apiv1.BackupMethodBarmanObjectStore: metav1.NewTime(now.Add(-2 * time.Hour)),
+		}
+		Expect(getBackupAgeFailures(cluster, now)).To(HaveLen(1))
+
+		cluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodVolumeSnapshot] = metav1.NewTime(
+			now.Add(-time.Minute))
+		Expect(getBackupAgeFailures(cluster, now)).To(BeEmpty())
+	})
+
+	It("names the restore point after the new image and the start of the upgrade", func() {
+		now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+		cluster.Spec.ImageName = "postgres:16.1"
+		name := getImageUpgradeRestorePointName(cluster, now)
+		Expect(name).To(HavePrefix(imageUpgradeRestorePointPrefix))
+		Expect(name).To(HaveSuffix("-20240301103000"))
+		Expect(len(name)).To(BeNumerically("<", 64))
+		Expect(getImageUpgradeRestorePointName(cluster, now.Add(time.Hour))).ToNot(Equal(name))
+
+		cluster.Spec.ImageName = "postgres:16.2"
+		Expect(getImageUpgradeRestorePointName(cluster, now)).ToNot(Equal(name))
+	})
+
+	It("keeps the name of the restore point already requested for the image", func() {
+		now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+		cluster.Spec.ImageName = "postgres:16.1"
+		name := getImageUpgradeRestorePointName(cluster, now)
+
+		cluster.Annotations = map[string]string{utils.RestorePointAnnotationName: name}
+		Expect(getImageUpgradeRestorePointName(cluster, now.Add(time.Hour))).To(Equal(name))
+
+		cluster.Annotations[utils.RestorePointAnnotationName] = "before-deploy"
+		Expect(getImageUpgradeRestorePointName(cluster, now.Add(time.Hour))).ToNot(Equal(name))
+	})
+})
+
+var _ = Describe("Image upgrade restore point", func() {
+	const name = "pre-upgrade-3f2a9c1b7d4e-20240301103000"
+
+	var (
+		ctx     context.Context
+		cluster *apiv1.Cluster
+		r       *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		r = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(100),
+		}
+	})
+
+	getRequestedRestorePoint := func() string {
+		var stored apiv1.Cluster
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		return stored.Annotations[utils.RestorePointAnnotationName]
+	}
+
+	It("requests the restore point and waits for it", func() {
+		done, err := r.ensureRestorePoint(ctx, cluster, name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeFalse())
+		Expect(getRequestedRestorePoint()).To(Equal(name))
+	})
+
+	It("is done once the restore point has been recorded with its LSN", func() {
+		cluster.Status.RestorePoints = []apiv1.RestorePoint{{Name: name, LSN: "0/3000090"}}
+
+		done, err := r.ensureRestorePoint(ctx, cluster, name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+	})
+
+	When("the restore point has been recorded without its LSN", func() {
+		BeforeEach(func() {
+			cluster.Status.RestorePoints = []apiv1.RestorePoint{{Name: name}}
+		})
+
+		It("waits for the instance manager to complete it", func() {
+			cluster.Annotations = map[string]string{utils.RestorePointAnnotationName: name}
+
+			done, err := r.ensureRestorePoint(ctx, cluster, name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+		})
+
+		It("requests it again when another restore point has been requested", func() {
+			cluster.Annotations = map[string]string{utils.RestorePointAnnotationName: "before-deploy"}
+
+			done, err := r.ensureRestorePoint(ctx, cluster, name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(getRequestedRestorePoint()).To(Equal(name))
+		})
+	})
+})
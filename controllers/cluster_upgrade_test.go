@@ -142,6 +142,73 @@ var _ = Describe("Pod upgrade", Ordered, func() {
 		Expect(rollout.reason).To(Equal("Postgres needs a restart to apply some configuration changes"))
 	})
 
+	It("requires rollout when the image digest behind the tag changed", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:    specs.PostgresContainerName,
+				ImageID: "ghcr.io/cloudnative-pg/postgresql@sha256:old",
+			},
+		}
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Status.LatestImageDigest = "sha256:new"
+
+		By("not requiring a rollout when the image pull policy is not Always", func() {
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeFalse())
+		})
+
+		By("requiring a rollout when the image pull policy is Always", func() {
+			updatedCluster.Spec.ImagePullPolicy = corev1.PullAlways
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeTrue())
+			Expect(rollout.reason).To(Equal("the instance is using an old image digest: sha256:old -> sha256:new"))
+			Expect(rollout.canBeInPlace).To(BeFalse())
+		})
+
+		By("not requiring a rollout when the instance runs the latest digest", func() {
+			updatedCluster.Status.LatestImageDigest = "sha256:old"
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeFalse())
+		})
+	})
+
+	It("requires rollout when the secrets change with the restart update policy", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Status.SecretsResourceVersion.SuperuserSecretVersion = "2"
+
+		By("not requiring a rollout with the default reload policy", func() {
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeFalse())
+		})
+
+		By("requiring a rollout with the restart policy", func() {
+			updatedCluster.Spec.ResourcesUpdatePolicy = apiv1.ResourcesUpdatePolicyRestart
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeTrue())
+			Expect(rollout.reason).To(Equal("the secrets projected into the instance changed"))
+		})
+
+		By("not requiring a rollout for pods without the checksum", func() {
+			delete(pod.Annotations, utils.ResourcesChecksumAnnotationName)
+			rollout := isPodNeedingRollout(ctx, status, updatedCluster)
+			Expect(rollout.required).To(BeFalse())
+		})
+	})
+
 	It("requires pod rollout if executable does not have a hash", func(ctx SpecContext) {
 		pod := specs.PodWithExistingStorage(cluster, 1)
 		status := postgres.PostgresqlStatus{
@@ -155,6 +222,25 @@ var _ = Describe("Pod upgrade", Ordered, func() {
 		Expect(rollout.canBeInPlace).To(BeFalse())
 	})
 
+	It("requires pod rollout when the file system of a PVC needs a restart to be resized", func(ctx SpecContext) {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+
+		cluster.Status.FileSystemResizePendingPVC = []string{"test-2"}
+		rollout := isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeFalse())
+
+		cluster.Status.FileSystemResizePendingPVC = []string{"test-1"}
+		rollout = isPodNeedingRollout(ctx, status, &cluster)
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.reason).To(Equal("rebooting pod to complete the file system resize of test-1"))
+		Expect(rollout.canBeInPlace).To(BeFalse())
+	})
+
 	It("checkPodSpecIsOutdated should not return any error", func(ctx SpecContext) {
 		pod := specs.PodWithExistingStorage(cluster, 1)
 		status := postgres.PostgresqlStatus{
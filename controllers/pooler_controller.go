@@ -23,6 +23,7 @@ import (
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -95,11 +96,18 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, fmt.Errorf("while getting managed resources: %w", err)
 	}
 
-	if resources.Cluster == nil {
+	if !pooler.IsExternal() && resources.Cluster == nil {
 		contextLogger.Info("Cluster not found, will retry in 30 seconds", "cluster", pooler.Spec.Cluster.Name)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	if pooler.IsExternal() && pooler.Spec.ExternalCluster.GetServerCASecretName() != "" &&
+		resources.ExternalClusterCASecret == nil {
+		contextLogger.Info("External cluster CA secret not found, waiting 30 seconds",
+			"secret", pooler.Spec.ExternalCluster.GetServerCASecretName())
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	if resources.AuthUserSecret == nil {
 		contextLogger.Info("AuthUserSecret not found, waiting 30 seconds", "secret", pooler.GetAuthQuerySecretName())
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
@@ -116,8 +124,35 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	// Pause PgBouncer while the primary of the cluster is changing
+	requeueAfter, err := r.reconcileSwitchoverPause(ctx, &pooler, resources)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while reconciling the switchover pause", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("while reconciling the switchover pause: %w", err)
+	}
+
 	// Take the required actions to align the spec with the collected status
-	return ctrl.Result{}, r.updateOwnedObjects(ctx, &pooler, resources)
+	if err := r.updateOwnedObjects(ctx, &pooler, resources); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report whether every PgBouncer instance reloaded the current secrets
+	reloadRequeueAfter, err := r.reconcileSecretsReloadedCondition(ctx, &pooler)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while reconciling the secrets reloaded condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("while reconciling the secrets reloaded condition: %w", err)
+	}
+	if requeueAfter == 0 || (reloadRequeueAfter != 0 && reloadRequeueAfter < requeueAfter) {
+		requeueAfter = reloadRequeueAfter
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SetupWithManager setup this controller inside the controller manager
@@ -127,6 +162,7 @@ func (r *PoolerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&v1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ServiceAccount{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Watches(
@@ -134,6 +170,11 @@ func (r *PoolerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler()),
 			builder.WithPredicates(secretsPoolerPredicate),
 		).
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToPooler()),
+			builder.WithPredicates(clustersPoolerPredicate),
+		).
 		Complete(r)
 }
 
@@ -187,6 +228,39 @@ func (r *PoolerReconciler) mapSecretToPooler() handler.MapFunc {
 	}
 }
 
+// mapClusterToPooler returns a function mapping cluster events to the poolers using them
+func (r *PoolerReconciler) mapClusterToPooler() handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) (result []reconcile.Request) {
+		cluster, ok := obj.(*apiv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		var poolers apiv1.PoolerList
+		if err := r.List(ctx, &poolers,
+			client.InNamespace(cluster.Namespace),
+		); err != nil {
+			log.FromContext(ctx).Error(err, "while getting pooler list for cluster",
+				"namespace", cluster.Namespace, "cluster", cluster.Name)
+			return nil
+		}
+
+		for _, pooler := range poolers.Items {
+			if pooler.Spec.Cluster.Name != cluster.Name {
+				continue
+			}
+			result = append(result, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			})
+		}
+
+		return result
+	}
+}
+
 // getPoolersUsingSecret get a list of poolers which are using the passed secret
 func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (requests []types.NamespacedName) {
 	for _, pooler := range poolers.Items {
@@ -208,6 +282,16 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 			)
 			continue
 		}
+
+		if pooler.IsExternal() && pooler.Spec.ExternalCluster.GetServerCASecretName() == secret.Name {
+			requests = append(requests,
+				types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			)
+			continue
+		}
 	}
 	return requests
 }
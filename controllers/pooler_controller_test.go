@@ -185,6 +185,26 @@ var _ = Describe("pooler_controller unit tests", func() {
 		})
 	})
 
+	It("should make sure that mapClusterToPooler produces the correct requests", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		otherCluster := newFakeCNPGCluster(namespace)
+
+		pooler1 := newFakePooler(cluster)
+		pooler2 := newFakePooler(cluster)
+		otherPooler := newFakePooler(otherCluster)
+
+		reqs := poolerReconciler.mapClusterToPooler()(ctx, cluster)
+		Expect(reqs).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: pooler1.Name, Namespace: namespace}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: pooler2.Name, Namespace: namespace}},
+		))
+		Expect(reqs).ToNot(ContainElement(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: otherPooler.Name, Namespace: namespace}},
+		))
+	})
+
 	It("should make sure that isOwnedByPooler works correctly", func() {
 		namespace := newFakeNamespace()
 		cluster := newFakeCNPGCluster(namespace)
@@ -21,6 +21,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // secretsPoolerPredicate contains the set of predicate functions of the pooler secrets
@@ -41,6 +43,61 @@ var (
 	}
 )
 
+// clustersPoolerPredicate filters the cluster events that are relevant for
+// the poolers, i.e. the ones changing the certificates PgBouncer uses
+// or the primary instance
+var clustersPoolerPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return isPoolerCertificateUpdate(e.ObjectOld, e.ObjectNew) ||
+			isPrimaryUpdate(e.ObjectOld, e.ObjectNew)
+	},
+}
+
+// isPoolerCertificateUpdate checks if the versions of the secrets
+// containing the certificates used by the poolers of a cluster changed
+func isPoolerCertificateUpdate(oldObject, newObject client.Object) bool {
+	oldCluster, ok := oldObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+	newCluster, ok := newObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+
+	oldVersions := oldCluster.Status.SecretsResourceVersion
+	newVersions := newCluster.Status.SecretsResourceVersion
+	return oldVersions.ServerSecretVersion != newVersions.ServerSecretVersion ||
+		oldVersions.ServerCASecretVersion != newVersions.ServerCASecretVersion ||
+		oldVersions.ClientCASecretVersion != newVersions.ClientCASecretVersion
+}
+
+// isPrimaryUpdate checks if the current or the target primary
+// of a cluster changed
+func isPrimaryUpdate(oldObject, newObject client.Object) bool {
+	oldCluster, ok := oldObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+	newCluster, ok := newObject.(*apiv1.Cluster)
+	if !ok {
+		return false
+	}
+
+	return oldCluster.Status.CurrentPrimary != newCluster.Status.CurrentPrimary ||
+		oldCluster.Status.TargetPrimary != newCluster.Status.TargetPrimary ||
+		oldCluster.Status.PlannedSwitchover.IsPending() != newCluster.Status.PlannedSwitchover.IsPending()
+}
+
 func isOwnedByPoolerOrSatisfiesPredicate(
 	object client.Object,
 	predicate func(client.Object) bool,
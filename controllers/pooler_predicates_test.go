@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -73,4 +74,75 @@ var _ = Describe("pooler_predicates unit tests", func() {
 			return false
 		})
 	})
+	It("makes sure isPoolerCertificateUpdate works correctly", func() {
+		oldCluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				SecretsResourceVersion: apiv1.SecretsResourceVersion{
+					ServerSecretVersion:   "1",
+					ServerCASecretVersion: "1",
+					ClientCASecretVersion: "1",
+				},
+			},
+		}
+
+		By("making sure it returns false when the certificates didn't change", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.Phase = apiv1.PhaseHealthy
+			Expect(isPoolerCertificateUpdate(oldCluster, newCluster)).To(BeFalse())
+		})
+
+		By("making sure it returns true when the server CA changed", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.SecretsResourceVersion.ServerCASecretVersion = "2"
+			Expect(isPoolerCertificateUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("making sure it returns true when the server certificate changed", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.SecretsResourceVersion.ServerSecretVersion = "2"
+			Expect(isPoolerCertificateUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("making sure it returns true when the client CA changed", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.SecretsResourceVersion.ClientCASecretVersion = "2"
+			Expect(isPoolerCertificateUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+	})
+
+	It("makes sure isPrimaryUpdate works correctly", func() {
+		oldCluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+
+		By("making sure it returns false when the primary didn't change", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.Phase = apiv1.PhaseHealthy
+			Expect(isPrimaryUpdate(oldCluster, newCluster)).To(BeFalse())
+		})
+
+		By("making sure it returns true when the target primary changed", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.TargetPrimary = "cluster-example-2"
+			Expect(isPrimaryUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("making sure it returns true when the current primary changed", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.CurrentPrimary = "cluster-example-2"
+			Expect(isPrimaryUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+
+		By("making sure it returns true when a planned switchover has been announced", func() {
+			newCluster := oldCluster.DeepCopy()
+			newCluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+				TargetPrimary: "cluster-example-2",
+				AnnouncedAt:   metav1.Now(),
+			}
+			Expect(isPrimaryUpdate(oldCluster, newCluster)).To(BeTrue())
+		})
+	})
 })
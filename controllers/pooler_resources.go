@@ -21,6 +21,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	v1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,9 +42,15 @@ type poolerManagedResources struct {
 	// This is the service where pgbouncer is accessible
 	Service *corev1.Service
 
+	// This is the pod disruption budget of the pgbouncer pods, if any
+	PodDisruptionBudget *policyv1.PodDisruptionBudget
+
 	// The referenced Cluster
 	Cluster *apiv1.Cluster
 
+	// The secret containing the CA of the external cluster, if any
+	ExternalClusterCASecret *corev1.Secret
+
 	// The RBAC resources needed for the pooler instance manager
 	// to watch over the relative Pooler resource
 	ServiceAccount *corev1.ServiceAccount
@@ -79,13 +86,31 @@ func (r *PoolerReconciler) getManagedResources(ctx context.Context,
 		return nil, err
 	}
 
-	// Get the referenced cluster
-	result.Cluster, err = getClusterOrNil(
-		ctx, r.Client, client.ObjectKey{Name: pooler.Spec.Cluster.Name, Namespace: pooler.Namespace})
+	// Get the pod disruption budget
+	result.PodDisruptionBudget, err = getPodDisruptionBudgetOrNil(
+		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
 	if err != nil {
 		return nil, err
 	}
 
+	if pooler.IsExternal() {
+		// Get the CA of the external cluster, if any
+		if caSecretName := pooler.Spec.ExternalCluster.GetServerCASecretName(); caSecretName != "" {
+			result.ExternalClusterCASecret, err = getSecretOrNil(
+				ctx, r.Client, client.ObjectKey{Name: caSecretName, Namespace: pooler.Namespace})
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// Get the referenced cluster
+		result.Cluster, err = getClusterOrNil(
+			ctx, r.Client, client.ObjectKey{Name: pooler.Spec.Cluster.Name, Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	result.ServiceAccount, err = getServiceAccountOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
 	if err != nil {
@@ -139,6 +164,26 @@ func getServiceOrNil(ctx context.Context, r client.Client, objectKey client.Obje
 	return &service, nil
 }
 
+// getPodDisruptionBudgetOrNil gets a pod disruption budget with a certain name,
+// returning nil when it doesn't exist
+func getPodDisruptionBudgetOrNil(
+	ctx context.Context,
+	r client.Client,
+	objectKey client.ObjectKey,
+) (*policyv1.PodDisruptionBudget, error) {
+	var pdb policyv1.PodDisruptionBudget
+	err := r.Get(ctx, objectKey, &pdb)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &pdb, nil
+}
+
 // getServiceAccountOrNil gets a service account with a certain name, returning nil when it doesn't exist
 func getServiceAccountOrNil(
 	ctx context.Context,
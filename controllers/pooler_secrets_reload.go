@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// secretsReloadCheckInterval is the time between two checks of the
+	// secrets reloaded by the PgBouncer instances
+	secretsReloadCheckInterval = 10 * time.Second
+
+	// secretsReloadTimeout is the time the PgBouncer instances have to
+	// reload a new version of the secrets
+	secretsReloadTimeout = 5 * time.Minute
+)
+
+// reconcileSecretsReloadedCondition sets the SecretsReloaded condition,
+// telling whether every running PgBouncer instance reloaded the current
+// version of the certificates and secrets. It returns the time after
+// which the pooler should be reconciled again, or zero if not needed
+func (r *PoolerReconciler) reconcileSecretsReloadedCondition(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+) (time.Duration, error) {
+	secretsHash, err := pooler.GetSecretsHash()
+	if err != nil {
+		return 0, err
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(pooler.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name},
+	); err != nil {
+		return 0, fmt.Errorf("while listing the pooler pods: %w", err)
+	}
+
+	reloadedSecrets, pending := getPendingSecretsReload(pooler, pods.Items, secretsHash)
+	currentCondition := meta.FindStatusCondition(pooler.Status.Conditions, apiv1.ConditionPoolerSecretsReloaded)
+	condition := buildSecretsReloadedCondition(currentCondition, pending, time.Now())
+
+	if condition.Reason == apiv1.ReasonPoolerSecretsReloadTimedOut &&
+		currentCondition.Reason != apiv1.ReasonPoolerSecretsReloadTimedOut {
+		r.Recorder.Event(pooler, "Warning", "SecretsReloadTimedOut", condition.Message)
+	}
+
+	updatedPooler := pooler.DeepCopy()
+	updatedPooler.Status.ReloadedSecrets = reloadedSecrets
+	meta.SetStatusCondition(&updatedPooler.Status.Conditions, condition)
+	if !reflect.DeepEqual(pooler.Status, updatedPooler.Status) {
+		if err := r.Status().Patch(ctx, updatedPooler, client.MergeFrom(pooler)); err != nil {
+			return 0, err
+		}
+		pooler.Status = updatedPooler.Status
+	}
+
+	if len(pending) > 0 {
+		return secretsReloadCheckInterval, nil
+	}
+	return 0, nil
+}
+
+// getPendingSecretsReload returns the reloaded secrets of the existing
+// pods, and the name of the running pods which didn't reload the current
+// version of the secrets yet
+func getPendingSecretsReload(
+	pooler *apiv1.Pooler,
+	pods []corev1.Pod,
+	secretsHash string,
+) (map[string]string, []string) {
+	var reloadedSecrets map[string]string
+	var pending []string
+	for _, pod := range pods {
+		if reloaded, ok := pooler.Status.ReloadedSecrets[pod.Name]; ok {
+			if reloadedSecrets == nil {
+				reloadedSecrets = make(map[string]string)
+			}
+			reloadedSecrets[pod.Name] = reloaded
+		}
+
+		// Pods that are not running will load the current secrets
+		// when started
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if reloadedSecrets[pod.Name] != secretsHash {
+			pending = append(pending, pod.Name)
+		}
+	}
+	sort.Strings(pending)
+
+	return reloadedSecrets, pending
+}
+
+// buildSecretsReloadedCondition builds the SecretsReloaded condition given
+// the pods which didn't reload the current secrets yet
+func buildSecretsReloadedCondition(
+	currentCondition *metav1.Condition,
+	pending []string,
+	now time.Time,
+) metav1.Condition {
+	if len(pending) == 0 {
+		return metav1.Condition{
+			Type:    apiv1.ConditionPoolerSecretsReloaded,
+			Status:  metav1.ConditionTrue,
+			Reason:  apiv1.ReasonPoolerSecretsReloaded,
+			Message: "Every PgBouncer instance reloaded the current secrets",
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   apiv1.ConditionPoolerSecretsReloaded,
+		Status: metav1.ConditionFalse,
+		Reason: apiv1.ReasonPoolerSecretsReloadPending,
+		Message: fmt.Sprintf("Waiting for PgBouncer instances to reload the current secrets: %s",
+			strings.Join(pending, ", ")),
+	}
+
+	if currentCondition != nil && currentCondition.Status == metav1.ConditionFalse &&
+		now.Sub(currentCondition.LastTransitionTime.Time) >= secretsReloadTimeout {
+		condition.Reason = apiv1.ReasonPoolerSecretsReloadTimedOut
+		condition.Message = fmt.Sprintf("PgBouncer instances didn't reload the current secrets within %v: %s",
+			secretsReloadTimeout, strings.Join(pending, ", "))
+	}
+
+	return condition
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pooler_secrets_reload unit tests", func() {
+	newPod := func(name string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	It("detects the running pods which didn't reload the secrets", func() {
+		pooler := &apiv1.Pooler{
+			Status: apiv1.PoolerStatus{
+				ReloadedSecrets: map[string]string{
+					"pooler-1":    "new",
+					"pooler-2":    "old",
+					"pooler-gone": "old",
+				},
+			},
+		}
+		terminating := newPod("pooler-4", corev1.PodRunning)
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		pods := []corev1.Pod{
+			newPod("pooler-1", corev1.PodRunning),
+			newPod("pooler-2", corev1.PodRunning),
+			newPod("pooler-3", corev1.PodPending),
+			terminating,
+			newPod("pooler-5", corev1.PodRunning),
+		}
+
+		reloaded, pending := getPendingSecretsReload(pooler, pods, "new")
+		Expect(reloaded).To(Equal(map[string]string{
+			"pooler-1": "new",
+			"pooler-2": "old",
+		}))
+		Expect(pending).To(Equal([]string{"pooler-2", "pooler-5"}))
+	})
+
+	It("reports the secrets as reloaded when nothing is pending", func() {
+		condition := buildSecretsReloadedCondition(nil, nil, time.Now())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(apiv1.ReasonPoolerSecretsReloaded))
+	})
+
+	It("reports the pending pods until the timeout expires", func() {
+		now := time.Now()
+		condition := buildSecretsReloadedCondition(nil, []string{"pooler-1"}, now)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(apiv1.ReasonPoolerSecretsReloadPending))
+		Expect(condition.Message).To(ContainSubstring("pooler-1"))
+
+		current := &metav1.Condition{
+			Type:               apiv1.ConditionPoolerSecretsReloaded,
+			Status:             metav1.ConditionFalse,
+			Reason:             apiv1.ReasonPoolerSecretsReloadPending,
+			LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+		}
+		condition = buildSecretsReloadedCondition(current, []string{"pooler-1"}, now)
+		Expect(condition.Reason).To(Equal(apiv1.ReasonPoolerSecretsReloadPending))
+
+		current.LastTransitionTime = metav1.NewTime(now.Add(-secretsReloadTimeout))
+		condition = buildSecretsReloadedCondition(current, []string{"pooler-1"}, now)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(apiv1.ReasonPoolerSecretsReloadTimedOut))
+	})
+})
@@ -46,7 +46,19 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		}
 	}
 
-	if cluster := resources.Cluster; cluster != nil {
+	if pooler.IsExternal() {
+		// There's no certificate to be used for client connections, and
+		// the CA of the server is optional
+		updatedStatus.Secrets.ServerTLS = apiv1.SecretVersion{}
+		updatedStatus.Secrets.ClientCA = apiv1.SecretVersion{}
+		updatedStatus.Secrets.ServerCA = apiv1.SecretVersion{}
+		if caSecret := resources.ExternalClusterCASecret; caSecret != nil {
+			updatedStatus.Secrets.ServerCA = apiv1.SecretVersion{
+				Name:    caSecret.Name,
+				Version: caSecret.ResourceVersion,
+			}
+		}
+	} else if cluster := resources.Cluster; cluster != nil {
 		updatedStatus.Secrets.ServerTLS = apiv1.SecretVersion{
 			Name:    cluster.GetServerTLSSecretName(),
 			Version: cluster.Status.SecretsResourceVersion.ServerSecretVersion,
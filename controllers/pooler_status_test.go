@@ -72,6 +72,32 @@ var _ = Describe("pooler_status unit tests", func() {
 		assertAuthUserStatus(pooler, authUserSecret)
 	})
 
+	It("should take the server CA from the external cluster", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pooler := newFakePooler(cluster)
+		pooler.Spec.ExternalCluster = &v1.PoolerExternalCluster{
+			Host:           "postgres.example.com",
+			ServerCASecret: &v1.LocalObjectReference{Name: "external-ca"},
+		}
+		caSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "external-ca",
+				Namespace:       pooler.Namespace,
+				ResourceVersion: "1",
+			},
+		}
+		res := &poolerManagedResources{ExternalClusterCASecret: caSecret}
+
+		err := poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.Status.Secrets.ServerCA.Name).To(Equal(caSecret.Name))
+		Expect(pooler.Status.Secrets.ServerCA.Version).To(Equal(caSecret.ResourceVersion))
+		Expect(pooler.Status.Secrets.ServerTLS.Name).To(BeEmpty())
+		Expect(pooler.Status.Secrets.ClientCA.Name).To(BeEmpty())
+	})
+
 	It("should correctly set the deployment status", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// switchoverPauseCheckInterval is the time between two checks of the
+// primary of the cluster while PgBouncer is paused
+const switchoverPauseCheckInterval = time.Second
+
+// reconcileSwitchoverPause pauses PgBouncer when the primary of the cluster
+// starts changing, and resumes it as soon as the new primary is serving or
+// the maximum pause time elapsed. The pause is requested to the PgBouncer
+// instances via the status of the Pooler. It returns the time after which
+// the pooler should be reconciled again, or zero if not needed
+func (r *PoolerReconciler) reconcileSwitchoverPause(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+	cluster := resources.Cluster
+
+	if !pooler.IsPauseOnSwitchoverEnabled() || pooler.IsExternal() || cluster == nil {
+		if pooler.IsPausedForSwitchover() {
+			return 0, r.setSwitchoverPausedSince(ctx, pooler, nil)
+		}
+		return 0, nil
+	}
+
+	if !pooler.IsPausedForSwitchover() {
+		if !isPrimaryChanging(cluster) {
+			return 0, nil
+		}
+
+		contextLogger.Info("Pausing PgBouncer while the primary is changing",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary)
+		r.Recorder.Event(pooler, "Normal", "SwitchoverPause",
+			"Pausing PgBouncer while the primary is changing")
+		now := metav1.Now()
+		return switchoverPauseCheckInterval, r.setSwitchoverPausedSince(ctx, pooler, &now)
+	}
+
+	maxPause := pooler.Spec.PgBouncer.PauseOnSwitchover.GetMaxPause()
+	if time.Since(pooler.Status.SwitchoverPausedSince.Time) >= maxPause {
+		contextLogger.Info("Resuming PgBouncer, the new primary is not serving yet",
+			"maxPause", maxPause)
+		r.Recorder.Eventf(pooler, "Warning", "SwitchoverResume",
+			"Resuming PgBouncer after %v, the new primary is not serving yet", maxPause)
+		return 0, r.setSwitchoverPausedSince(ctx, pooler, nil)
+	}
+
+	serving, err := r.isPrimaryServing(ctx, cluster)
+	if err != nil {
+		return 0, err
+	}
+	if !serving {
+		return switchoverPauseCheckInterval, nil
+	}
+
+	contextLogger.Info("Resuming PgBouncer, the new primary is serving",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"pauseDuration", time.Since(pooler.Status.SwitchoverPausedSince.Time))
+	r.Recorder.Event(pooler, "Normal", "SwitchoverResume",
+		"Resuming PgBouncer, the new primary is serving")
+	return 0, r.setSwitchoverPausedSince(ctx, pooler, nil)
+}
+
+// isPrimaryChanging checks if the cluster is running a switchover
+// or a failover, or has announced a planned switchover
+func isPrimaryChanging(cluster *apiv1.Cluster) bool {
+	if cluster.Status.PlannedSwitchover.IsPending() {
+		return true
+	}
+
+	return cluster.Status.TargetPrimary != "" &&
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary
+}
+
+// isPrimaryServing checks if the primary of the cluster is the only
+// ready Pod selected by the `rw` service, and that the service selects it,
+// which is not the case while the read-write fencing gap detaches it
+func (r *PoolerReconciler) isPrimaryServing(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if isPrimaryChanging(cluster) {
+		return false, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName:     cluster.Name,
+			utils.ClusterRoleLabelName: specs.ClusterRoleLabelPrimary,
+		},
+	); err != nil {
+		return false, err
+	}
+
+	if len(pods.Items) != 1 ||
+		pods.Items[0].Name != cluster.Status.CurrentPrimary ||
+		!utils.IsPodReady(pods.Items[0]) {
+		return false, nil
+	}
+
+	var readWriteService corev1.Service
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServiceReadWriteName()},
+		&readWriteService)
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return isServiceSelectingPod(&readWriteService, &pods.Items[0]), nil
+}
+
+// isServiceSelectingPod checks if the passed service has the passed pod
+// among its endpoints
+func isServiceSelectingPod(service *corev1.Service, pod *corev1.Pod) bool {
+	if len(service.Spec.Selector) == 0 {
+		return false
+	}
+
+	return labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(pod.Labels))
+}
+
+// setSwitchoverPausedSince patches the status of the pooler with the time
+// PgBouncer was paused, or removes it when resuming
+func (r *PoolerReconciler) setSwitchoverPausedSince(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	pausedSince *metav1.Time,
+) error {
+	origPooler := pooler.DeepCopy()
+	pooler.Status.SwitchoverPausedSince = pausedSince
+	return r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pooler_switchover unit tests", func() {
+	It("detects when the primary of the cluster is changing", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		Expect(isPrimaryChanging(cluster)).To(BeFalse())
+
+		cluster.Status.TargetPrimary = "cluster-example-2"
+		Expect(isPrimaryChanging(cluster)).To(BeTrue())
+
+		cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+		Expect(isPrimaryChanging(cluster)).To(BeTrue())
+
+		cluster.Status.TargetPrimary = ""
+		Expect(isPrimaryChanging(cluster)).To(BeFalse())
+	})
+
+	It("detects when a planned switchover has been announced", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				PlannedSwitchover: &apiv1.PlannedSwitchoverStatus{
+					TargetPrimary: "cluster-example-2",
+					AnnouncedAt:   metav1.Now(),
+				},
+			},
+		}
+		Expect(isPrimaryChanging(cluster)).To(BeTrue())
+
+		abortedAt := metav1.Now()
+		cluster.Status.PlannedSwitchover.AbortedAt = &abortedAt
+		Expect(isPrimaryChanging(cluster)).To(BeFalse())
+	})
+
+	Context("when checking if the primary is serving", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+			},
+		}
+		primary := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-2",
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ClusterLabelName:     cluster.Name,
+					utils.ClusterRoleLabelName: specs.ClusterRoleLabelPrimary,
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+			},
+		}
+
+		newReconciler := func(objects ...client.Object) *PoolerReconciler {
+			return &PoolerReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+					WithObjects(objects...).
+					Build(),
+				Recorder: record.NewFakeRecorder(100),
+			}
+		}
+
+		It("is serving when the rw service selects the ready primary", func(ctx SpecContext) {
+			r := newReconciler(primary.DeepCopy(), specs.CreateClusterReadWriteService(*cluster))
+			Expect(r.isPrimaryServing(ctx, cluster)).To(BeTrue())
+		})
+
+		It("is not serving while the rw service is detached by the fencing gap", func(ctx SpecContext) {
+			r := newReconciler(primary.DeepCopy(), specs.CreateClusterDetachedReadWriteService(*cluster))
+			Expect(r.isPrimaryServing(ctx, cluster)).To(BeFalse())
+		})
+
+		It("is not serving when the rw service doesn't exist", func(ctx SpecContext) {
+			r := newReconciler(primary.DeepCopy())
+			Expect(r.isPrimaryServing(ctx, cluster)).To(BeFalse())
+		})
+
+		It("is not serving when the primary is not ready", func(ctx SpecContext) {
+			notReady := primary.DeepCopy()
+			notReady.Status.Conditions = nil
+			r := newReconciler(notReady, specs.CreateClusterReadWriteService(*cluster))
+			Expect(r.isPrimaryServing(ctx, cluster)).To(BeFalse())
+		})
+	})
+})
@@ -56,6 +56,10 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
+	if err := r.reconcilePodDisruptionBudget(ctx, pooler, resources); err != nil {
+		return err
+	}
+
 	return createOrPatchPodMonitor(ctx, r.Client, r.DiscoveryClient, pgbouncer.NewPoolerPodMonitorManager(pooler))
 }
 
@@ -92,9 +96,11 @@ func (r *PoolerReconciler) updateDeployment(
 	case resources.Deployment != nil:
 		currentVersion := resources.Deployment.Annotations[utils.PoolerSpecHashAnnotationName]
 		updatedVersion := generatedDeployment.Annotations[utils.PoolerSpecHashAnnotationName]
-		if currentVersion == updatedVersion {
+		currentChecksum := resources.Deployment.Annotations[utils.ResourcesChecksumAnnotationName]
+		updatedChecksum := generatedDeployment.Annotations[utils.ResourcesChecksumAnnotationName]
+		if currentVersion == updatedVersion && currentChecksum == updatedChecksum {
 			// Everything fine, the two deployments are using the
-			// same specifications
+			// same specifications and secrets
 			return nil
 		}
 
@@ -151,6 +157,57 @@ func (r *PoolerReconciler) reconcileService(
 	return r.Patch(ctx, patchedService, client.MergeFrom(resources.Service))
 }
 
+// reconcilePodDisruptionBudget update, create or delete the pgbouncer
+// pod disruption budget as needed
+func (r *PoolerReconciler) reconcilePodDisruptionBudget(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) error {
+	contextLog := log.FromContext(ctx)
+	expectedPdb := pgbouncer.PodDisruptionBudget(pooler, resources.Cluster)
+
+	if expectedPdb == nil {
+		if resources.PodDisruptionBudget == nil {
+			return nil
+		}
+
+		contextLog.Info("Deleting the pod disruption budget")
+		if err := r.Delete(ctx, resources.PodDisruptionBudget); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		resources.PodDisruptionBudget = nil
+		return nil
+	}
+
+	if err := ctrl.SetControllerReference(pooler, expectedPdb, r.Scheme); err != nil {
+		return err
+	}
+
+	if resources.PodDisruptionBudget == nil {
+		contextLog.Info("Creating the pod disruption budget")
+		err := r.Create(ctx, expectedPdb)
+		if err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		resources.PodDisruptionBudget = expectedPdb
+		return nil
+	}
+
+	patchedPdb := resources.PodDisruptionBudget.DeepCopy()
+	patchedPdb.Spec = expectedPdb.Spec
+	utils.MergeObjectsMetadata(patchedPdb, expectedPdb)
+
+	if reflect.DeepEqual(patchedPdb.ObjectMeta, resources.PodDisruptionBudget.ObjectMeta) &&
+		reflect.DeepEqual(patchedPdb.Spec, resources.PodDisruptionBudget.Spec) {
+		return nil
+	}
+
+	contextLog.Info("Updating the pod disruption budget")
+
+	return r.Patch(ctx, patchedPdb, client.MergeFrom(resources.PodDisruptionBudget))
+}
+
 // updateRBAC update or create the pgbouncer RBAC
 func (r *PoolerReconciler) updateRBAC(
 	ctx context.Context,
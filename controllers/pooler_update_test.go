@@ -22,6 +22,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -282,6 +283,58 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 			Expect(expectedSVC.Labels[utils.ClusterLabelName]).To(Equal(cluster.Name))
 		})
 	})
+
+	It("should reconcilePodDisruptionBudget works correctly", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pooler := newFakePooler(cluster)
+		res := &poolerManagedResources{Cluster: cluster}
+		pdbKey := types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace}
+
+		By("making sure it doesn't create the pdb for a single instance", func() {
+			err := poolerReconciler.reconcilePodDisruptionBudget(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.PodDisruptionBudget).To(BeNil())
+
+			err = k8sClient.Get(ctx, pdbKey, &policyv1.PodDisruptionBudget{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		By("making sure it creates the pdb when the pooler is scaled up", func() {
+			pooler.Spec.Instances = ptr.To(int32(3))
+			err := poolerReconciler.reconcilePodDisruptionBudget(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			err = k8sClient.Get(ctx, pdbKey, pdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pdb.Spec.Selector.MatchLabels[utils.PgbouncerNameLabel]).To(Equal(pooler.Name))
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+			res.PodDisruptionBudget = pdb
+		})
+
+		By("making sure the pdb doesn't get updated if there are not changes", func() {
+			previousResourceVersion := res.PodDisruptionBudget.ResourceVersion
+			err := poolerReconciler.reconcilePodDisruptionBudget(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			err = k8sClient.Get(ctx, pdbKey, pdb)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(previousResourceVersion).To(Equal(pdb.ResourceVersion))
+		})
+
+		By("making sure it deletes the pdb when the pooler is scaled down", func() {
+			pooler.Spec.Instances = ptr.To(int32(1))
+			err := poolerReconciler.reconcilePodDisruptionBudget(ctx, pooler, res)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.PodDisruptionBudget).To(BeNil())
+
+			err = k8sClient.Get(ctx, pdbKey, &policyv1.PodDisruptionBudget{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
 })
 
 var _ = Describe("ensureServiceAccountPullSecret", func() {
@@ -18,16 +18,21 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/lsntracker"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -40,6 +45,11 @@ var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 // elapsed yet
 var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the delay before triggering a failover") //nolint: lll
 
+// ErrFailoverDataLossExceeded is raised when the primary server can't be elected because the
+// promotion would lose more data than allowed by .spec.maxDataLossOnFailover, or
+// the data loss cannot be estimated
+var ErrFailoverDataLossExceeded = fmt.Errorf("the failover would exceed the maximum allowed data loss")
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -60,7 +70,8 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 	// and issue a switchover if that's the case
 	if primary := status.Items[0]; (primary.IsPrimary || (cluster.IsReplica() && primary.IsPodReady)) &&
 		primary.Pod.Name == cluster.Status.CurrentPrimary &&
-		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary &&
+		canAnnounceSwitchover(cluster) {
 		isPrimaryOnUnschedulableNode, err := r.isNodeUnschedulable(ctx, primary.Node)
 		if err != nil {
 			contextLogger.Error(err, "while checking if current primary is on an unschedulable node")
@@ -110,8 +121,20 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", fmt.Errorf("unable to evaluate failover logic, unable to fetch the instances status")
 	}
 
-	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
-		return "", err
+	// A fenced instance has PostgreSQL shut down and cannot be promoted:
+	// we wait for the fence to be lifted
+	if cluster.IsInstanceFenced(mostAdvancedInstance.Pod.Name) {
+		contextLogger.Info("The most advanced instance is fenced, skipping the failover",
+			"instance", mostAdvancedInstance.Pod.Name)
+		return "", nil
+	}
+
+	// A primary reporting a storage failure is not going to recover
+	// by itself: there's no point in waiting for the failover delay
+	if !status.IsReportingStorageFailure(cluster.Status.CurrentPrimary) {
+		if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
+			return "", err
+		}
 	}
 
 	// The current primary is not correctly working, and we need to elect a new one
@@ -120,6 +143,10 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// (if is still alive) to shut down by setting the apiv1.PendingFailoverMarker as
 	// target primary.
 	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if err := r.enforceMaxDataLossOnFailover(ctx, cluster, mostAdvancedInstance); err != nil {
+			return "", err
+		}
+
 		contextLogger.Info("Current primary isn't healthy, initiating a failover")
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
@@ -242,6 +269,12 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 			"currentPrimary", primaryPod.Pod.Name, "currentPrimaryNode", primaryPod.Node,
 			"targetPrimary", candidate.Pod.Name, "targetPrimaryNode", candidate.Node)
 		status.LogStatus(ctx)
+		if cluster.IsSwitchoverOrchestrated() {
+			// The switchover is only announced, and the target primary
+			// will be changed once the applications have been drained
+			return "", r.requestSwitchover(ctx, cluster, candidate.Pod.Name,
+				fmt.Sprintf("primary instance is running on unschedulable node %v", primaryPod.Node))
+		}
 		r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
 			"Current primary is running on unschedulable node %v, switching over from %v to %v",
 			primaryPod.Node, cluster.Status.TargetPrimary, candidate.Pod.Name)
@@ -285,6 +318,14 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		}
 	}
 
+	// A fenced instance has PostgreSQL shut down and cannot be promoted:
+	// we wait for the fence to be lifted
+	if cluster.IsInstanceFenced(status.Items[0].Pod.Name) {
+		contextLogger.Info("The most advanced instance is fenced, skipping the failover",
+			"instance", status.Items[0].Pod.Name)
+		return "", nil
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -417,3 +458,130 @@ func findDeletableInstance(cluster *apiv1.Cluster, instances []corev1.Pod) strin
 
 	return instances[resultIdx].Name
 }
+
+// trackPrimaryLSN records the last LSN and timeline reported by the current primary,
+// to be able to estimate the data loss of a failover when the primary is unreachable
+func (r *ClusterReconciler) trackPrimaryLSN(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) {
+	if len(status.Items) == 0 || cluster.IsReplica() {
+		return
+	}
+
+	primary := status.Items[0]
+	if !primary.IsPrimary || primary.Pod == nil || primary.Pod.Name != cluster.Status.CurrentPrimary {
+		return
+	}
+
+	r.primaryLSNs.Update(client.ObjectKeyFromObject(cluster), primary.Pod.Name, lsntracker.Position{
+		LSN:        primary.CurrentLsn,
+		TimelineID: primary.TimeLineID,
+	})
+}
+
+// enforceMaxDataLossOnFailover blocks the failover when promoting the passed
+// candidate would lose more data than allowed by .spec.maxDataLossOnFailover,
+// or when the data loss cannot be estimated, unless the user explicitly
+// allowed it via annotation
+func (r *ClusterReconciler) enforceMaxDataLossOnFailover(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	candidate postgres.PostgresqlStatus,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.MaxDataLossOnFailover == nil {
+		return nil
+	}
+
+	lastKnownPosition, known := r.primaryLSNs.Get(client.ObjectKeyFromObject(cluster), cluster.Status.CurrentPrimary)
+	dataLoss, err := getFailoverDataLoss(lastKnownPosition, known, candidate)
+	if err == nil && dataLoss <= cluster.Spec.MaxDataLossOnFailover.Value() {
+		return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+			Type:   string(apiv1.ConditionFailoverAllowed),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.FailoverDataLossWithinThreshold),
+			Message: fmt.Sprintf("Failing over to %v with a potential data loss of %d bytes",
+				candidate.Pod.Name, dataLoss),
+		})
+	}
+
+	var reason apiv1.ConditionReason
+	var message string
+	if err != nil {
+		reason = apiv1.FailoverDataLossUnknown
+		message = fmt.Sprintf("The data loss of promoting %v cannot be estimated: %v", candidate.Pod.Name, err)
+	} else {
+		reason = apiv1.FailoverDataLossExceeded
+		message = fmt.Sprintf("Promoting %v may lose %d bytes of WAL, exceeding the configured threshold of %v",
+			candidate.Pod.Name, dataLoss, cluster.Spec.MaxDataLossOnFailover.String())
+	}
+
+	if utils.IsDataLossOnFailoverAllowed(&cluster.ObjectMeta) {
+		contextLogger.Warning("Failing over despite the potential data loss, as allowed by the user",
+			"candidate", candidate.Pod.Name,
+			"lastKnownPrimaryLSN", lastKnownPosition.LSN,
+			"reason", message)
+		r.Recorder.Eventf(cluster, "Warning", "FailoverDataLossAllowed",
+			"%s. Failing over anyway, as allowed by the user", message)
+		return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionFailoverAllowed),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.FailoverDataLossAllowedByUser),
+			Message: message,
+		})
+	}
+
+	contextLogger.Info("Failover blocked, the potential data loss exceeds the configured threshold or is unknown",
+		"candidate", candidate.Pod.Name,
+		"lastKnownPrimaryLSN", lastKnownPosition.LSN,
+		"reason", message,
+		"maxDataLossOnFailover", cluster.Spec.MaxDataLossOnFailover.String())
+	r.Recorder.Eventf(cluster, "Warning", "FailoverBlocked",
+		"Failover blocked. %s. Set the %v annotation to \"enabled\" to proceed",
+		message, utils.AllowDataLossOnFailoverAnnotationName)
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionFailoverAllowed),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(reason),
+		Message: message,
+	})
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailoverBlocked, message); err != nil {
+		return err
+	}
+
+	return ErrFailoverDataLossExceeded
+}
+
+// getFailoverDataLoss returns the amount of WAL, in bytes, which was written by
+// the primary up to the passed position and that the candidate hasn't replayed yet.
+// An error is returned when the data loss cannot be computed, because the last
+// position of the primary is unknown, or the candidate isn't on the same timeline
+func getFailoverDataLoss(
+	lastKnownPrimaryPosition lsntracker.Position,
+	known bool,
+	candidate postgres.PostgresqlStatus,
+) (int64, error) {
+	if !known {
+		return 0, errors.New("the last LSN of the primary is unknown")
+	}
+
+	if candidate.TimeLineID != lastKnownPrimaryPosition.TimelineID {
+		return 0, fmt.Errorf("the candidate is on timeline %d, while the primary was on timeline %d",
+			candidate.TimeLineID, lastKnownPrimaryPosition.TimelineID)
+	}
+
+	primaryPosition, err := lastKnownPrimaryPosition.LSN.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN of the primary: %w", err)
+	}
+
+	candidatePosition, err := candidate.ReplayLsn.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("invalid replay LSN of the candidate: %w", err)
+	}
+
+	if candidatePosition >= primaryPosition {
+		return 0, nil
+	}
+
+	return primaryPosition - candidatePosition, nil
+}
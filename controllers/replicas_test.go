@@ -17,11 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/lsntracker"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -151,3 +154,101 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Failover data loss", func() {
+	primaryPosition := lsntracker.Position{LSN: "0/4000000", TimelineID: 2}
+
+	It("computes the WAL the candidate hasn't replayed", func() {
+		dataLoss, err := getFailoverDataLoss(primaryPosition, true, postgres.PostgresqlStatus{
+			TimeLineID:  2,
+			ReceivedLsn: "0/4000000",
+			ReplayLsn:   "0/3000000",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataLoss).To(BeEquivalentTo(0x1000000))
+	})
+
+	It("reports no data loss when the candidate is up to date", func() {
+		dataLoss, err := getFailoverDataLoss(primaryPosition, true, postgres.PostgresqlStatus{
+			TimeLineID: 2,
+			ReplayLsn:  "0/4000060",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dataLoss).To(BeZero())
+	})
+
+	It("cannot compute the data loss when the position of the primary is unknown", func() {
+		_, err := getFailoverDataLoss(lsntracker.Position{}, false, postgres.PostgresqlStatus{
+			TimeLineID: 2,
+			ReplayLsn:  "0/4000060",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("cannot compute the data loss when the candidate is on another timeline", func() {
+		_, err := getFailoverDataLoss(primaryPosition, true, postgres.PostgresqlStatus{
+			TimeLineID: 1,
+			ReplayLsn:  "0/4000060",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("cannot compute the data loss without valid LSNs", func() {
+		_, err := getFailoverDataLoss(primaryPosition, true, postgres.PostgresqlStatus{TimeLineID: 2})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Failover with fenced instances", func() {
+	newStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:   isPrimary,
+			ReceivedLsn: "0/4000000",
+		}
+	}
+
+	newCluster := func(fencedInstances string, replica bool) *apiv1.Cluster {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-example",
+				Namespace:   "default",
+				Annotations: map[string]string{utils.FencedInstanceAnnotation: fencedInstances},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		if replica {
+			cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+		}
+		return cluster
+	}
+
+	It("doesn't promote a fenced replica", func(ctx context.Context) {
+		r := &ClusterReconciler{}
+		cluster := newCluster(`["cluster-example-2"]`, false)
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", false),
+		}}
+
+		newPrimary, err := r.updateTargetPrimaryFromPodsPrimaryCluster(ctx, cluster, status, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't promote a fenced instance of a replica cluster", func(ctx context.Context) {
+		r := &ClusterReconciler{}
+		cluster := newCluster(`["*"]`, true)
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", false),
+		}}
+
+		newPrimary, err := r.updateTargetPrimaryFromPodsReplicaCluster(ctx, cluster, status, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+})
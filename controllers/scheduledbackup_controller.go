@@ -58,7 +58,8 @@ type ScheduledBackupReconciler struct {
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledbackups/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;create
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;create;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=list;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is the main reconciler logic
@@ -118,6 +119,11 @@ func (r *ScheduledBackupReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
+	if err := deleteExpiredBackups(ctx, r.Recorder, r.Client, &scheduledBackup, time.Now()); err != nil {
+		contextLogger.Error(err, "Cannot delete the expired backups")
+		return ctrl.Result{}, err
+	}
+
 	return ReconcileScheduledBackup(ctx, r.Recorder, r.Client, &scheduledBackup)
 }
 
@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// deleteExpiredBackups deletes the backups created by a scheduled backup that
+// are older than its retention policy, together with their volume snapshots
+func deleteExpiredBackups(
+	ctx context.Context,
+	event record.EventRecorder,
+	cli client.Client,
+	scheduledBackup *apiv1.ScheduledBackup,
+	now time.Time,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	cutoff, err := scheduledBackup.GetRetentionCutoff(now)
+	if err != nil || cutoff == nil {
+		return err
+	}
+
+	var backups apiv1.BackupList
+	if err := cli.List(ctx, &backups,
+		client.InNamespace(scheduledBackup.Namespace),
+		client.MatchingLabels{utils.ParentScheduledBackupLabelName: scheduledBackup.Name},
+	); err != nil {
+		return err
+	}
+
+	expiredBackups := getExpiredBackups(backups.Items, *cutoff)
+	for idx := range expiredBackups {
+		backup := &expiredBackups[idx]
+		var snapshots storagesnapshotv1.VolumeSnapshotList
+		if err := cli.List(ctx, &snapshots,
+			client.InNamespace(backup.Namespace),
+			client.MatchingLabels{utils.BackupNameLabelName: backup.Name},
+		); err != nil {
+			return err
+		}
+
+		for snapshotIdx := range snapshots.Items {
+			if err := cli.Delete(ctx, &snapshots.Items[snapshotIdx]); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+
+		if err := cli.Delete(ctx, backup); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+
+		contextLogger.Info("Deleted expired backup",
+			"backupName", backup.Name,
+			"retentionPolicy", scheduledBackup.Spec.RetentionPolicy)
+		event.Eventf(scheduledBackup, "Normal", "DeletedExpiredBackup",
+			"Deleted backup %v, expired according to the retention policy", backup.Name)
+	}
+
+	return nil
+}
+
+// getExpiredBackups gets the terminated backups taken before the cutoff time.
+// The latest completed backup is never expired, to always have a backup
+// to restore from
+func getExpiredBackups(backups []apiv1.Backup, cutoff time.Time) []apiv1.Backup {
+	var latestCompleted *apiv1.Backup
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.Status.Phase != apiv1.BackupPhaseCompleted {
+			continue
+		}
+		if latestCompleted == nil || getBackupTime(latestCompleted).Before(getBackupTime(backup)) {
+			latestCompleted = backup
+		}
+	}
+
+	var result []apiv1.Backup
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup == latestCompleted || !backup.Status.IsDone() {
+			continue
+		}
+		if getBackupTime(backup).Before(cutoff) {
+			result = append(result, *backup)
+		}
+	}
+
+	return result
+}
+
+// getBackupTime gets the time a backup was taken, falling back to
+// the creation time for the backups that never stopped
+func getBackupTime(backup *apiv1.Backup) time.Time {
+	if backup.Status.StoppedAt != nil {
+		return backup.Status.StoppedAt.Time
+	}
+
+	return backup.CreationTimestamp.Time
+}
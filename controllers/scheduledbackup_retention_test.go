@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduled backup retention", func() {
+	const namespace = "default"
+	now := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	newBackup := func(name string, phase apiv1.BackupPhase, daysAgo int) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{utils.ParentScheduledBackupLabelName: "snapshots"},
+			},
+			Status: apiv1.BackupStatus{
+				Phase:     phase,
+				StoppedAt: ptr.To(metav1.NewTime(now.AddDate(0, 0, -daysAgo))),
+			},
+		}
+	}
+
+	It("expires the terminated backups older than the cutoff", func() {
+		backups := []apiv1.Backup{
+			newBackup("old-completed", apiv1.BackupPhaseCompleted, 5),
+			newBackup("old-failed", apiv1.BackupPhaseFailed, 4),
+			newBackup("old-running", apiv1.BackupPhaseRunning, 4),
+			newBackup("recent-completed", apiv1.BackupPhaseCompleted, 1),
+		}
+
+		expired := getExpiredBackups(backups, now.AddDate(0, 0, -2))
+		Expect(expired).To(HaveLen(2))
+		Expect(expired[0].Name).To(Equal("old-completed"))
+		Expect(expired[1].Name).To(Equal("old-failed"))
+	})
+
+	It("never expires the latest completed backup", func() {
+		backups := []apiv1.Backup{
+			newBackup("older-completed", apiv1.BackupPhaseCompleted, 6),
+			newBackup("latest-completed", apiv1.BackupPhaseCompleted, 5),
+			newBackup("failed", apiv1.BackupPhaseFailed, 1),
+		}
+
+		expired := getExpiredBackups(backups, now.AddDate(0, 0, -2))
+		Expect(expired).To(HaveLen(1))
+		Expect(expired[0].Name).To(Equal("older-completed"))
+	})
+
+	It("deletes the expired backups together with their volume snapshots", func(ctx SpecContext) {
+		oldBackup := newBackup("old", apiv1.BackupPhaseCompleted, 5)
+		recentBackup := newBackup("recent", apiv1.BackupPhaseCompleted, 1)
+		snapshot := &storagesnapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old",
+				Namespace: namespace,
+				Labels:    map[string]string{utils.BackupNameLabelName: "old"},
+			},
+		}
+		scheduledBackup := &apiv1.ScheduledBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshots", Namespace: namespace},
+			Spec: apiv1.ScheduledBackupSpec{
+				Method:          apiv1.BackupMethodVolumeSnapshot,
+				RetentionPolicy: "2d",
+			},
+		}
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(&oldBackup, &recentBackup, snapshot).
+			Build()
+
+		err := deleteExpiredBackups(ctx, record.NewFakeRecorder(10), cli, scheduledBackup, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		var backups apiv1.BackupList
+		Expect(cli.List(ctx, &backups, client.InNamespace(namespace))).To(Succeed())
+		Expect(backups.Items).To(HaveLen(1))
+		Expect(backups.Items[0].Name).To(Equal("recent"))
+
+		var snapshots storagesnapshotv1.VolumeSnapshotList
+		Expect(cli.List(ctx, &snapshots, client.InNamespace(namespace))).To(Succeed())
+		Expect(snapshots.Items).To(BeEmpty())
+	})
+})
@@ -0,0 +1,282 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// FailurePolicy defines how an error invoking the admission hook is handled
+type FailurePolicy string
+
+const (
+	// FailurePolicyFail means that an error invoking the admission hook
+	// rejects the request
+	FailurePolicyFail FailurePolicy = "Fail"
+
+	// FailurePolicyIgnore means that an error invoking the admission hook
+	// is logged and the request is allowed
+	FailurePolicyIgnore FailurePolicy = "Ignore"
+)
+
+// errDenied is returned when the admission hook refuses a request
+var errDenied = errors.New("denied by the admission hook")
+
+// configuredHook is the admission hook defined in the operator
+// configuration. It is built once by Configure, so that every request
+// shares the same HTTP client and connection pool
+var configuredHook atomic.Pointer[Hook]
+
+// Hook is an external validating webhook, receiving an AdmissionReview
+// for every request and replying with the standard Kubernetes format
+type Hook struct {
+	// URL is the address where the AdmissionReview is sent
+	URL string
+
+	// FailurePolicy defines how errors invoking the hook are handled
+	FailurePolicy FailurePolicy
+
+	// Client is the HTTP client used to invoke the hook
+	Client *http.Client
+}
+
+// FromConfiguration creates the admission hook defined in the operator
+// configuration, returning nil when no admission hook is configured
+func FromConfiguration(config *configuration.Data) (*Hook, error) {
+	if config.AdmissionHookURL == "" {
+		return nil, nil
+	}
+
+	failurePolicy := FailurePolicy(config.AdmissionHookFailurePolicy)
+	switch failurePolicy {
+	case "":
+		failurePolicy = FailurePolicyFail
+	case FailurePolicyFail, FailurePolicyIgnore:
+	default:
+		return nil, fmt.Errorf("invalid admission hook failure policy: %q", config.AdmissionHookFailurePolicy)
+	}
+
+	timeout := config.AdmissionHookTimeout
+	if timeout <= 0 {
+		timeout = configuration.DefaultAdmissionHookTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.AdmissionHookCAFile != "" {
+		caBundle, err := os.ReadFile(config.AdmissionHookCAFile) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("while reading the admission hook CA bundle: %w", err)
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid certificate found in %s", config.AdmissionHookCAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
+		}
+	}
+
+	return &Hook{
+		URL:           config.AdmissionHookURL,
+		FailurePolicy: failurePolicy,
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// NewRequest creates the admission request for the creation (when oldObject
+// is nil) or the update of an object
+func NewRequest(
+	gvk schema.GroupVersionKind,
+	resource string,
+	object client.Object,
+	oldObject client.Object,
+) (*admissionv1.AdmissionRequest, error) {
+	request := &admissionv1.AdmissionRequest{
+		UID: types.UID(uuid.NewString()),
+		Kind: metav1.GroupVersionKind{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind,
+		},
+		Resource: metav1.GroupVersionResource{
+			Group:    gvk.Group,
+			Version:  gvk.Version,
+			Resource: resource,
+		},
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+		Operation: admissionv1.Create,
+	}
+
+	var err error
+	if request.Object, err = encodeObject(gvk, object); err != nil {
+		return nil, err
+	}
+
+	if oldObject != nil {
+		request.Operation = admissionv1.Update
+		if request.OldObject, err = encodeObject(gvk, oldObject); err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
+
+// encodeObject serializes an object making sure its kind is set, as it
+// is not always preserved by the decoder of the webhook server
+func encodeObject(gvk schema.GroupVersionKind, object client.Object) (runtime.RawExtension, error) {
+	objectCopy := object.DeepCopyObject()
+	objectCopy.GetObjectKind().SetGroupVersionKind(gvk)
+
+	raw, err := json.Marshal(objectCopy)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+
+	return runtime.RawExtension{Raw: raw}, nil
+}
+
+// Configure creates the admission hook defined in the operator
+// configuration, which is then used by Validate
+func Configure(config *configuration.Data) error {
+	hook, err := FromConfiguration(config)
+	if err != nil {
+		return err
+	}
+
+	configuredHook.Store(hook)
+	return nil
+}
+
+// Validate sends the request to the admission hook defined in the
+// operator configuration, if any
+func Validate(ctx context.Context, request *admissionv1.AdmissionRequest) (admission.Warnings, error) {
+	return configuredHook.Load().Review(ctx, request)
+}
+
+// Review sends the request to the admission hook, returning the
+// warnings it produced and an API error if the request was refused
+func (h *Hook) Review(ctx context.Context, request *admissionv1.AdmissionRequest) (admission.Warnings, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	groupResource := schema.GroupResource{Group: request.Resource.Group, Resource: request.Resource.Resource}
+	warnings, err := h.review(ctx, request)
+	switch {
+	case err == nil:
+		return warnings, nil
+
+	case errors.Is(err, errDenied):
+		return warnings, apierrors.NewForbidden(groupResource, request.Name, err)
+
+	case h.FailurePolicy == FailurePolicyIgnore:
+		log.FromContext(ctx).Warning("Error invoking the admission hook, ignoring it",
+			"url", h.URL,
+			"name", request.Name,
+			"namespace", request.Namespace,
+			"error", err)
+		return nil, nil
+
+	default:
+		return nil, apierrors.NewInternalError(fmt.Errorf("while invoking the admission hook: %w", err))
+	}
+}
+
+func (h *Hook) review(ctx context.Context, request *admissionv1.AdmissionRequest) (admission.Warnings, error) {
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionv1.SchemeGroupVersion.String(),
+			Kind:       "AdmissionReview",
+		},
+		Request: request,
+	}
+
+	body, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := h.Client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = httpResponse.Body.Close()
+	}()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status code: %d", httpResponse.StatusCode)
+	}
+
+	var result admissionv1.AdmissionReview
+	if err := json.NewDecoder(httpResponse.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("while decoding the AdmissionReview: %w", err)
+	}
+
+	response := result.Response
+	if response == nil {
+		return nil, errors.New("missing response in the AdmissionReview")
+	}
+	if response.UID != request.UID {
+		return nil, fmt.Errorf("mismatching response UID, expected %q, got %q", request.UID, response.UID)
+	}
+
+	if !response.Allowed {
+		if response.Result != nil && response.Result.Message != "" {
+			return response.Warnings, fmt.Errorf("%w: %s", errDenied, response.Result.Message)
+		}
+		return response.Warnings, errDenied
+	}
+
+	return response.Warnings, nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var configMapGVK = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+func newConfigMap(value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Data: map[string]string{"key": value},
+	}
+}
+
+// newHookServer starts an HTTP server replying to every AdmissionReview
+// with the response built by the passed function
+func newHookServer(
+	reply func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse,
+) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review admissionv1.AdmissionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		review.Response = reply(review.Request)
+		review.Request = nil
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+}
+
+var _ = Describe("Admission hook configuration", func() {
+	It("is disabled when no URL is configured", func() {
+		hook, err := FromConfiguration(&configuration.Data{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hook).To(BeNil())
+
+		warnings, err := hook.Review(context.Background(), &admissionv1.AdmissionRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("fails by default", func() {
+		hook, err := FromConfiguration(&configuration.Data{AdmissionHookURL: "https://hook.example.com"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hook.FailurePolicy).To(Equal(FailurePolicyFail))
+	})
+
+	It("rejects unknown failure policies", func() {
+		_, err := FromConfiguration(&configuration.Data{
+			AdmissionHookURL:           "https://hook.example.com",
+			AdmissionHookFailurePolicy: "Maybe",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects a missing CA bundle", func() {
+		_, err := FromConfiguration(&configuration.Data{
+			AdmissionHookURL:    "https://hook.example.com",
+			AdmissionHookCAFile: "/this/file/does/not/exist",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("is built once and reused by every validation", func() {
+		DeferCleanup(func() {
+			configuredHook.Store(nil)
+		})
+
+		Expect(Configure(&configuration.Data{AdmissionHookURL: "https://hook.example.com"})).To(Succeed())
+		hook := configuredHook.Load()
+		Expect(hook).ToNot(BeNil())
+		Expect(hook.Client).ToNot(BeNil())
+		Expect(configuredHook.Load()).To(BeIdenticalTo(hook))
+
+		Expect(Configure(&configuration.Data{})).To(Succeed())
+		Expect(configuredHook.Load()).To(BeNil())
+	})
+
+	It("keeps the previous admission hook when the configuration is invalid", func() {
+		DeferCleanup(func() {
+			configuredHook.Store(nil)
+		})
+
+		Expect(Configure(&configuration.Data{AdmissionHookURL: "https://hook.example.com"})).To(Succeed())
+		hook := configuredHook.Load()
+
+		Expect(Configure(&configuration.Data{
+			AdmissionHookURL:           "https://hook.example.com",
+			AdmissionHookFailurePolicy: "Maybe",
+		})).ToNot(Succeed())
+		Expect(configuredHook.Load()).To(BeIdenticalTo(hook))
+	})
+})
+
+var _ = Describe("Admission requests", func() {
+	It("are creations when there is no old object", func() {
+		request, err := NewRequest(configMapGVK, "configmaps", newConfigMap("new"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.UID).ToNot(BeEmpty())
+		Expect(request.Operation).To(Equal(admissionv1.Create))
+		Expect(request.Name).To(Equal("test"))
+		Expect(request.Namespace).To(Equal("default"))
+		Expect(request.Resource.Resource).To(Equal("configmaps"))
+		Expect(request.OldObject.Raw).To(BeEmpty())
+
+		var object corev1.ConfigMap
+		Expect(json.Unmarshal(request.Object.Raw, &object)).To(Succeed())
+		Expect(object.Kind).To(Equal("ConfigMap"))
+		Expect(object.APIVersion).To(Equal("v1"))
+		Expect(object.Data).To(HaveKeyWithValue("key", "new"))
+	})
+
+	It("are updates when there is an old object", func() {
+		request, err := NewRequest(configMapGVK, "configmaps", newConfigMap("new"), newConfigMap("old"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.Operation).To(Equal(admissionv1.Update))
+
+		var oldObject corev1.ConfigMap
+		Expect(json.Unmarshal(request.OldObject.Raw, &oldObject)).To(Succeed())
+		Expect(oldObject.Data).To(HaveKeyWithValue("key", "old"))
+	})
+})
+
+var _ = Describe("Admission hook review", func() {
+	var request *admissionv1.AdmissionRequest
+
+	BeforeEach(func() {
+		var err error
+		request, err = NewRequest(configMapGVK, "configmaps", newConfigMap("value"), nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	newHook := func(server *httptest.Server, failurePolicy FailurePolicy) *Hook {
+		return &Hook{
+			URL:           server.URL,
+			FailurePolicy: failurePolicy,
+			Client:        server.Client(),
+		}
+	}
+
+	It("allows the request returning the hook warnings", func() {
+		server := newHookServer(func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{
+				UID:      request.UID,
+				Allowed:  true,
+				Warnings: []string{"consider adding a backup"},
+			}
+		})
+		defer server.Close()
+
+		warnings, err := newHook(server, FailurePolicyFail).Review(context.Background(), request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(ConsistOf("consider adding a backup"))
+	})
+
+	It("refuses the request with the message of the hook", func() {
+		server := newHookServer(func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{
+				UID:     request.UID,
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "at least 3 instances are required in production",
+				},
+			}
+		})
+		defer server.Close()
+
+		_, err := newHook(server, FailurePolicyIgnore).Review(context.Background(), request)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("at least 3 instances are required in production"))
+	})
+
+	It("refuses responses not matching the request", func() {
+		server := newHookServer(func(_ *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{
+				UID:     "another-request",
+				Allowed: true,
+			}
+		})
+		defer server.Close()
+
+		_, err := newHook(server, FailurePolicyFail).Review(context.Background(), request)
+		Expect(apierrors.IsInternalError(err)).To(BeTrue())
+	})
+
+	It("stops waiting for the hook when the admission request is canceled", func() {
+		server := newHookServer(func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+			return &admissionv1.AdmissionResponse{UID: request.UID, Allowed: true}
+		})
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := newHook(server, FailurePolicyFail).Review(ctx, request)
+		Expect(apierrors.IsInternalError(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(context.Canceled.Error()))
+	})
+
+	When("the hook is not working", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			DeferCleanup(server.Close)
+		})
+
+		It("refuses the request with the Fail policy", func() {
+			_, err := newHook(server, FailurePolicyFail).Review(context.Background(), request)
+			Expect(apierrors.IsInternalError(err)).To(BeTrue())
+		})
+
+		It("allows the request with the Ignore policy", func() {
+			warnings, err := newHook(server, FailurePolicyIgnore).Review(context.Background(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package barmancloud implements the barman-cloud backup plugin, which
+// runs in a sidecar of the PostgreSQL instance
+package barmancloud
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the "backup-plugin barman-cloud" command
+func NewCmd() *cobra.Command {
+	var socketPath string
+	var podName string
+	var pgData string
+
+	cmd := &cobra.Command{
+		Use:           apiv1.BarmanCloudPluginName,
+		Short:         "serve the barman-cloud backup plugin",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			contextLogger := log.WithName("backup-plugin").WithValues("plugin", apiv1.BarmanCloudPluginName)
+			ctx, stop := signal.NotifyContext(
+				log.IntoContext(cmd.Context(), contextLogger),
+				syscall.SIGINT,
+				syscall.SIGTERM,
+			)
+			defer stop()
+
+			typedClient, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				contextLogger.Error(err, "creating controller-runtime client")
+				return err
+			}
+
+			provider := &Provider{
+				Client:  typedClient,
+				PodName: podName,
+				PGData:  pgData,
+			}
+			if err := backupplugin.Serve(ctx, socketPath, provider); err != nil {
+				contextLogger.Error(err, "while serving the backup plugin")
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&socketPath, "socket", os.Getenv(backupplugin.SocketEnvVar),
+		"The path of the socket where the plugin listens")
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of the "+
+		"current pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA of the instance")
+
+	return cmd
+}
@@ -0,0 +1,191 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barmancloud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// Provider is the barman-cloud implementation of the backup provider
+type Provider struct {
+	proto.UnimplementedBackupProviderServer
+
+	// Client is used to read the secrets containing the
+	// object store credentials
+	Client client.Client
+
+	// PodName is the name of the Pod running the plugin
+	PodName string
+
+	// PGData is the data directory of the PostgreSQL instance
+	PGData string
+
+	// archiveLock serializes the WAL archiving, as PostgreSQL does with
+	// the archive_command, since the archivers share the spool directory
+	archiveLock sync.Mutex
+}
+
+// GetMetadata implements the BackupProviderServer interface
+func (p *Provider) GetMetadata(
+	context.Context,
+	*proto.GetMetadataRequest,
+) (*proto.GetMetadataResponse, error) {
+	return &proto.GetMetadataResponse{
+		Name:    apiv1.BarmanCloudPluginName,
+		Version: versions.Version,
+	}, nil
+}
+
+// ArchiveWAL implements the BackupProviderServer interface
+func (p *Provider) ArchiveWAL(
+	ctx context.Context,
+	request *proto.ArchiveWALRequest,
+) (*proto.ArchiveWALResponse, error) {
+	cluster, err := decodeBarmanCluster(request.ClusterDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	p.archiveLock.Lock()
+	defer p.archiveLock.Unlock()
+
+	contextLogger := log.FromContext(ctx).WithValues("walName", request.SourceFileName)
+	if err := walarchive.ArchiveWithBarman(
+		log.IntoContext(ctx, contextLogger),
+		p.PodName,
+		p.PGData,
+		cluster,
+		request.SourceFileName,
+	); err != nil {
+		return nil, err
+	}
+
+	return &proto.ArchiveWALResponse{}, nil
+}
+
+// Backup implements the BackupProviderServer interface
+func (p *Provider) Backup(
+	ctx context.Context,
+	request *proto.BackupRequest,
+) (*proto.BackupResponse, error) {
+	cluster, err := decodeBarmanCluster(request.ClusterDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := backupplugin.DecodeBackup(request.BackupDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		p.Client,
+		cluster.Namespace,
+		cluster.Spec.Backup.BarmanObjectStore,
+		os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("cannot recover backup credentials: %w", err)
+	}
+
+	backupStatus := backup.GetStatus()
+	if backupStatus.ServerName == "" {
+		backupStatus.ServerName = cluster.Name
+		if serverName := cluster.Spec.Backup.BarmanObjectStore.ServerName; serverName != "" {
+			backupStatus.ServerName = serverName
+		}
+	}
+	if backupStatus.BackupName == "" && capabilities.ShouldExecuteBackupWithName(cluster) {
+		backupStatus.BackupName = fmt.Sprintf("backup-%v", utils.ToCompactISO8601(time.Now()))
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("backupName", backup.Name)
+	backupCommand := &postgres.BackupCommand{
+		Cluster:      cluster,
+		Backup:       backup,
+		Env:          env,
+		Log:          contextLogger,
+		Capabilities: capabilities,
+	}
+	barmanBackup, err := backupCommand.ExecuteBarmanBackup(log.IntoContext(ctx, contextLogger))
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Spec.Backup.RetentionPolicy != "" {
+		contextLogger.Info("Applying backup retention policy",
+			"retentionPolicy", cluster.Spec.Backup.RetentionPolicy)
+		// Failing to apply the retention policy doesn't invalidate the
+		// backup we just took, and the error has already been logged
+		_ = barman.DeleteBackupsByPolicy(ctx, cluster.Spec.Backup, backupStatus.ServerName, env)
+	}
+
+	return newBackupResponse(barmanBackup), nil
+}
+
+// decodeBarmanCluster decodes the cluster definition, ensuring
+// the Barman object store is configured
+func decodeBarmanCluster(clusterDefinition []byte) (*apiv1.Cluster, error) {
+	cluster, err := backupplugin.DecodeCluster(clusterDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return nil, fmt.Errorf("the %s plugin requires the barmanObjectStore section",
+			apiv1.BarmanCloudPluginName)
+	}
+
+	return cluster, nil
+}
+
+func newBackupResponse(barmanBackup *catalog.BarmanBackup) *proto.BackupResponse {
+	return &proto.BackupResponse{
+		BackupId:   barmanBackup.ID,
+		BackupName: barmanBackup.BackupName,
+		StartedAt:  barmanBackup.BeginTime.Unix(),
+		StoppedAt:  barmanBackup.EndTime.Unix(),
+		BeginWal:   barmanBackup.BeginWal,
+		EndWal:     barmanBackup.EndWal,
+		BeginLsn:   barmanBackup.BeginLSN,
+		EndLsn:     barmanBackup.EndLSN,
+	}
+}
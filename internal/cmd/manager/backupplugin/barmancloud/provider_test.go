@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barmancloud
+
+import (
+	"encoding/json"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud provider", func() {
+	It("reports its name", func(ctx SpecContext) {
+		metadata, err := (&Provider{}).GetMetadata(ctx, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.Name).To(Equal(apiv1.BarmanCloudPluginName))
+	})
+
+	It("requires the Barman object store to be configured", func() {
+		definition, err := json.Marshal(&apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					Plugin: &apiv1.BackupPluginConfiguration{Name: apiv1.BarmanCloudPluginName},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = decodeBarmanCluster(definition)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("decodes clusters with the Barman object store", func() {
+		definition, err := json.Marshal(&apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+				},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster, err := decodeBarmanCluster(definition)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Spec.Backup.BarmanObjectStore.DestinationPath).To(Equal("s3://bucket"))
+	})
+
+	It("converts the Barman catalog entry into the plugin response", func() {
+		beginTime := time.Date(2023, 10, 10, 10, 10, 10, 0, time.UTC)
+		response := newBackupResponse(&catalog.BarmanBackup{
+			ID:         "20231010T101010",
+			BackupName: "backup-20231010101010",
+			BeginTime:  beginTime,
+			EndTime:    beginTime.Add(time.Minute),
+			BeginWal:   "000000010000000000000002",
+			EndWal:     "000000010000000000000003",
+		})
+		Expect(response.BackupId).To(Equal("20231010T101010"))
+		Expect(response.BackupName).To(Equal("backup-20231010101010"))
+		Expect(response.StartedAt).To(Equal(beginTime.Unix()))
+		Expect(response.StoppedAt).To(Equal(beginTime.Add(time.Minute).Unix()))
+		Expect(response.EndWal).To(Equal("000000010000000000000003"))
+	})
+})
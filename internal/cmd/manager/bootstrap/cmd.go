@@ -39,7 +39,14 @@ func NewCmd() *cobra.Command {
 				"destination", dest,
 				"version", versions.Version,
 				"build", versions.Info)
-			err := fileutils.CopyFile(cmd.Root().Name(), dest)
+			// The executable may be shipped by the PostgreSQL image
+			// in any location, so we don't rely on the working directory
+			source, err := os.Executable()
+			if err != nil {
+				panic(err)
+			}
+
+			err = fileutils.CopyFile(source, dest)
 			if err != nil {
 				panic(err)
 			}
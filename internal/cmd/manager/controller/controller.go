@@ -25,6 +25,7 @@ import (
 	"net/http/pprof"
 	"time"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,11 +37,13 @@ import (
 	// +kubebuilder:scaffold:imports
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/admissionhook"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/multicache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -108,6 +111,21 @@ func RunController(
 		startPprofDebugServer(ctx)
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx, tracing.OperatorServiceName)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		return err
+	}
+	defer func() {
+		// The manager context is done at this point, the pending
+		// spans are flushed using a new one
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracing.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			setupLog.Error(err, "unable to flush the pending traces")
+		}
+	}()
+
 	managerOptions := ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
@@ -132,6 +150,15 @@ func RunController(
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		LeaderElectionReleaseOnCancel: true,
+		// Leases are read directly from the API server, to avoid caching
+		// the ones of the nodes, which are frequently renewed
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{
+					&coordinationv1.Lease{},
+				},
+			},
+		},
 	}
 
 	if configuration.Current.WatchNamespace != "" {
@@ -183,6 +210,11 @@ func RunController(
 
 	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
 
+	if err := admissionhook.Configure(configuration.Current); err != nil {
+		setupLog.Error(err, "unable to configure the admission hook")
+		return err
+	}
+
 	discoveryClient, err := utils.GetDiscoveryClient()
 	if err != nil {
 		return err
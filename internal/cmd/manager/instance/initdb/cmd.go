@@ -21,32 +21,25 @@ import (
 	"context"
 	"os"
 
-	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
 // NewCmd generates the "init" subcommand
 func NewCmd() *cobra.Command {
-	var appDBName string
-	var appUser string
 	var clusterName string
-	var initDBFlagsString string
 	var namespace string
-	var parentNode string
 	var pgData string
-	var pgWal string
 	var podName string
-	var postInitSQLStr string
-	var postInitApplicationSQLStr string
-	var postInitTemplateSQLStr string
-	var postInitApplicationSQLRefsFolder string
+	var options jobcommand.Options
 
 	cmd := &cobra.Command{
 		Use: "init [options]",
@@ -59,46 +52,27 @@ func NewCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			initDBFlags, err := shellquote.Split(initDBFlagsString)
-			if err != nil {
-				log.Error(err, "Error while parsing initdb flags")
-				return err
-			}
-
-			postInitSQL, err := shellquote.Split(postInitSQLStr)
-			if err != nil {
-				log.Error(err, "Error while parsing post init SQL queries")
-				return err
-			}
-
-			postInitApplicationSQL, err := shellquote.Split(postInitApplicationSQLStr)
-			if err != nil {
-				log.Error(err, "Error while parsing post init template SQL queries")
-				return err
-			}
-
-			postInitTemplateSQL, err := shellquote.Split(postInitTemplateSQLStr)
-			if err != nil {
-				log.Error(err, "Error while parsing post init template SQL queries")
+			if err := options.Validate(jobcommand.CommandInit); err != nil {
+				log.Error(err, "Invalid options")
 				return err
 			}
 
 			info := postgres.InitInfo{
-				ApplicationDatabase:    appDBName,
-				ApplicationUser:        appUser,
+				ApplicationDatabase:    options.AppDBName,
+				ApplicationUser:        options.AppUser,
 				ClusterName:            clusterName,
-				InitDBOptions:          initDBFlags,
+				InitDBOptions:          options.InitDBFlags,
 				Namespace:              namespace,
-				ParentNode:             parentNode,
+				ParentNode:             options.ParentNode,
 				PgData:                 pgData,
-				PgWal:                  pgWal,
+				PgWal:                  options.PgWal,
 				PodName:                podName,
-				PostInitSQL:            postInitSQL,
-				PostInitApplicationSQL: postInitApplicationSQL,
-				PostInitTemplateSQL:    postInitTemplateSQL,
+				PostInitSQL:            options.PostInitSQL,
+				PostInitApplicationSQL: options.PostInitApplicationSQL,
+				PostInitTemplateSQL:    options.PostInitTemplateSQL,
 				// if the value to postInitApplicationSQLRefsFolder is empty,
 				// bootstrap will do nothing for post init application SQL refs.
-				PostInitApplicationSQLRefsFolder: postInitApplicationSQLRefsFolder,
+				PostInitApplicationSQLRefsFolder: options.PostInitApplicationSQLRefsFolder,
 			}
 
 			return initSubCommand(ctx, info)
@@ -112,30 +86,15 @@ func NewCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&appDBName, "app-db-name", "app",
-		"The name of the application containing the database")
-	cmd.Flags().StringVar(&appUser, "app-user", "app",
-		"The name of the application user")
 	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
 		"current cluster in k8s, used to coordinate switchover and failover")
-	cmd.Flags().StringVar(&initDBFlagsString, "initdb-flags", "", "The list of flags to be passed "+
-		"to initdb while creating the initial database")
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
 		"the cluster and the pod in k8s")
-	cmd.Flags().StringVar(&parentNode, "parent-node", "", "The origin node")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
-	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
 	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The pod name to "+
 		"be checked against the cluster state")
-	cmd.Flags().StringVar(&postInitSQLStr, "post-init-sql", "",
-		"The list of SQL queries to be executed to configure the new instance")
-	cmd.Flags().StringVar(&postInitApplicationSQLStr, "post-init-application-sql", "",
-		"The list of SQL queries to be executed inside application database right after the database is created")
-	cmd.Flags().StringVar(&postInitTemplateSQLStr, "post-init-template-sql", "",
-		"The list of SQL queries to be executed inside template1 database to configure the new instance")
-	cmd.Flags().StringVar(&postInitApplicationSQLRefsFolder, "post-init-application-sql-refs-folder",
-		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
-			"against the application database immediately after its creation")
+
+	options.AddFlags(cmd.Flags(), jobcommand.CommandInit)
 
 	return cmd
 }
@@ -152,5 +111,11 @@ func initSubCommand(ctx context.Context, info postgres.InitInfo) error {
 		return err
 	}
 
-	return nil
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	return lifecyclehooks.RunForCluster(
+		ctx, typedClient, info.Namespace, info.ClusterName, info.PodName, lifecyclehooks.EventPostBootstrap)
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
@@ -37,11 +38,10 @@ import (
 // NewCmd creates the new "join" command
 func NewCmd() *cobra.Command {
 	var pgData string
-	var pgWal string
-	var parentNode string
 	var podName string
 	var clusterName string
 	var namespace string
+	var options jobcommand.Options
 
 	cmd := &cobra.Command{
 		Use: "join [options]",
@@ -53,6 +53,11 @@ func NewCmd() *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
+			if err := options.Validate(jobcommand.CommandJoin); err != nil {
+				log.Error(err, "Invalid options")
+				return err
+			}
+
 			instance := postgres.NewInstance()
 
 			// The following are needed to correctly
@@ -64,8 +69,8 @@ func NewCmd() *cobra.Command {
 
 			info := postgres.InitInfo{
 				PgData:     pgData,
-				PgWal:      pgWal,
-				ParentNode: parentNode,
+				PgWal:      options.PgWal,
+				ParentNode: options.ParentNode,
 				PodName:    podName,
 			}
 
@@ -81,8 +86,6 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
-	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
-	cmd.Flags().StringVar(&parentNode, "parent-node", "", "The origin node")
 	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of this pod, to "+
 		"be checked against the cluster state")
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
@@ -90,6 +93,8 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of "+
 		"the current cluster in k8s, used to download TLS certificates")
 
+	options.AddFlags(cmd.Flags(), jobcommand.CommandJoin)
+
 	return cmd
 }
 
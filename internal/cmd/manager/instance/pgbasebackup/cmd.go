@@ -30,6 +30,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
@@ -47,7 +49,7 @@ func NewCmd() *cobra.Command {
 	var clusterName string
 	var namespace string
 	var pgData string
-	var pgWal string
+	var options jobcommand.Options
 
 	cmd := &cobra.Command{
 		Use: "pgbasebackup",
@@ -58,6 +60,11 @@ func NewCmd() *cobra.Command {
 			})
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.Validate(jobcommand.CommandPgBaseBackup); err != nil {
+				log.Error(err, "Invalid options")
+				return err
+			}
+
 			client, err := management.NewControllerRuntimeClient()
 			if err != nil {
 				return err
@@ -68,7 +75,7 @@ func NewCmd() *cobra.Command {
 					ClusterName: clusterName,
 					Namespace:   namespace,
 					PgData:      pgData,
-					PgWal:       pgWal,
+					PgWal:       options.PgWal,
 				},
 				client: client,
 			}
@@ -77,8 +84,11 @@ func NewCmd() *cobra.Command {
 
 			if err = env.bootstrapUsingPgbasebackup(ctx); err != nil {
 				log.Error(err, "Unable to boostrap cluster")
+				return err
 			}
-			return err
+
+			return lifecyclehooks.RunForCluster(
+				ctx, client, namespace, clusterName, os.Getenv("POD_NAME"), lifecyclehooks.EventPostBootstrap)
 		},
 		PostRunE: func(cmd *cobra.Command, args []string) error {
 			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
@@ -94,7 +104,8 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
 		"the cluster and of the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be created")
-	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be created")
+
+	options.AddFlags(cmd.Flags(), jobcommand.CommandPgBaseBackup)
 
 	return cmd
 }
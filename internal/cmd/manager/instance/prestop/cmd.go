@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prestop implements the "instance prestop" subcommand of the operator
+package prestop
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd creates the "instance prestop" subcommand, invoked by the preStop
+// hook of the PostgreSQL container. It forwards the request to the local
+// webserver of the instance manager, which is only reachable from the Pod
+func NewCmd() *cobra.Command {
+	var strategy string
+
+	cmd := &cobra.Command{
+		Use:  "prestop",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return preStopSubCommand(strategy)
+		},
+	}
+
+	cmd.Flags().StringVar(&strategy, "strategy", "", "The preStop strategy of the cluster")
+
+	return cmd
+}
+
+func preStopSubCommand(strategy string) error {
+	preStopURL := url.Local(url.PathPgPreStop, url.LocalPort)
+	resp, err := http.Get(preStopURL + "?strategy=" + strategy) // nolint:gosec
+	if err != nil {
+		log.Error(err, "Error while requesting the preStop actions")
+		return err
+	}
+
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			log.Error(err, "Can't close the connection",
+				"preStopURL", preStopURL,
+				"statusCode", resp.StatusCode,
+			)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error(err, "Error while reading the preStop response body",
+			"preStopURL", preStopURL,
+			"statusCode", resp.StatusCode,
+		)
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Info(
+			"Error while requesting the preStop actions",
+			"preStopURL", preStopURL,
+			"statusCode", resp.StatusCode,
+			"body", string(body),
+		)
+		return fmt.Errorf("invalid status code: %v", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
@@ -36,7 +38,7 @@ func NewCmd() *cobra.Command {
 	var clusterName string
 	var namespace string
 	var pgData string
-	var pgWal string
+	var options jobcommand.Options
 
 	cmd := &cobra.Command{
 		Use:           "restore [flags]",
@@ -49,12 +51,16 @@ func NewCmd() *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			if err := options.Validate(jobcommand.CommandRestore); err != nil {
+				log.Error(err, "Invalid options")
+				return err
+			}
 
 			info := postgres.InitInfo{
 				ClusterName: clusterName,
 				Namespace:   namespace,
 				PgData:      pgData,
-				PgWal:       pgWal,
+				PgWal:       options.PgWal,
 			}
 
 			return restoreSubCommand(ctx, info)
@@ -73,7 +79,8 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
 		"the cluster and the Pod in k8s")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be restored")
-	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be restored")
+
+	options.AddFlags(cmd.Flags(), jobcommand.CommandRestore)
 
 	return cmd
 }
@@ -90,5 +97,11 @@ func restoreSubCommand(ctx context.Context, info postgres.InitInfo) error {
 		return err
 	}
 
-	return nil
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	return lifecyclehooks.RunForCluster(
+		ctx, typedClient, info.Namespace, info.ClusterName, os.Getenv("POD_NAME"), lifecyclehooks.EventPostBootstrap)
 }
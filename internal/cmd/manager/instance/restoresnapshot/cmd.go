@@ -28,6 +28,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
@@ -35,13 +37,10 @@ import (
 // NewCmd creates the "restoresnapshot" subcommand
 func NewCmd() *cobra.Command {
 	var (
-		clusterName   string
-		namespace     string
-		pgData        string
-		pgWal         string
-		backupLabel   string
-		tablespaceMap string
-		immediate     bool
+		clusterName string
+		namespace   string
+		pgData      string
+		options     jobcommand.Options
 	)
 
 	cmd := &cobra.Command{
@@ -55,31 +54,35 @@ func NewCmd() *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			if err := options.Validate(jobcommand.CommandRestoreSnapshot); err != nil {
+				log.Error(err, "Invalid options")
+				return err
+			}
 
 			info := postgres.InitInfo{
 				ClusterName: clusterName,
 				Namespace:   namespace,
 				PgData:      pgData,
-				PgWal:       pgWal,
+				PgWal:       options.PgWal,
 			}
 
-			if backupLabel != "" {
-				res, err := base64.StdEncoding.DecodeString(backupLabel)
+			if options.BackupLabel != "" {
+				res, err := base64.StdEncoding.DecodeString(options.BackupLabel)
 				if err != nil {
 					return err
 				}
 				info.BackupLabelFile = res
 			}
 
-			if tablespaceMap != "" {
-				res, err := base64.StdEncoding.DecodeString(tablespaceMap)
+			if options.TablespaceMap != "" {
+				res, err := base64.StdEncoding.DecodeString(options.TablespaceMap)
 				if err != nil {
 					return err
 				}
 				info.TablespaceMapFile = res
 			}
 
-			err := execute(ctx, info, immediate)
+			err := execute(ctx, info, options.Immediate)
 			if err != nil {
 				log.Error(err, "Error while recovering Volume Snapshot backup")
 			}
@@ -99,10 +102,8 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
 		"the cluster")
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be restored")
-	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be restored")
-	cmd.Flags().StringVar(&backupLabel, "backuplabel", "", "The restore backup_label file content")
-	cmd.Flags().StringVar(&tablespaceMap, "tablespacemap", "", "The restore tablespace_map file content")
-	cmd.Flags().BoolVar(&immediate, "immediate", false, "Do not start PostgreSQL but just recover the snapshot")
+
+	options.AddFlags(cmd.Flags(), jobcommand.CommandRestoreSnapshot)
 
 	return cmd
 }
@@ -113,5 +114,16 @@ func execute(ctx context.Context, info postgres.InitInfo, immediate bool) error
 		return err
 	}
 
-	return info.RestoreSnapshot(ctx, typedClient, immediate)
+	if err := info.RestoreSnapshot(ctx, typedClient, immediate); err != nil {
+		return err
+	}
+
+	// Replicas are restored from volume snapshots too, using the
+	// immediate mode, but they're not bootstrapping the cluster
+	if immediate {
+		return nil
+	}
+
+	return lifecyclehooks.RunForCluster(
+		ctx, typedClient, info.Namespace, info.ClusterName, os.Getenv("POD_NAME"), lifecyclehooks.EventPostBootstrap)
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -32,11 +33,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
+	localcache "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/connectionstorm"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/databases"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/externalservers"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
@@ -50,12 +55,17 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
 var scheme = runtime.NewScheme()
 
+// apiServerOutageTimeout is the time the controllers of the instance manager
+// wait for their caches to be synced, covering the outages of the API server
+const apiServerOutageTimeout = 24 * time.Hour
+
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = apiv1.AddToScheme(scheme)
@@ -71,7 +81,7 @@ func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "run [flags]",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			return management.WaitKubernetesAPIServer(cmd.Context(), client.ObjectKey{
+			return waitKubernetesAPIServer(cmd.Context(), client.ObjectKey{
 				Name:      clusterName,
 				Namespace: namespace,
 			})
@@ -85,6 +95,24 @@ func NewCmd() *cobra.Command {
 			instance.PodName = podName
 			instance.ClusterName = clusterName
 
+			// Tracing is optional: a misconfiguration must not prevent
+			// PostgreSQL from starting
+			shutdownTracing, err := tracing.Setup(
+				ctx,
+				tracing.InstanceManagerServiceName,
+				tracing.PodAttributes(namespace, podName)...)
+			if err != nil {
+				log.Error(err, "unable to set up tracing, spans won't be exported")
+			} else {
+				defer func() {
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), tracing.ShutdownTimeout)
+					defer cancel()
+					if err := shutdownTracing(shutdownCtx); err != nil {
+						log.Error(err, "unable to flush the pending traces")
+					}
+				}()
+			}
+
 			return retry.OnError(retry.DefaultRetry, isRunSubCommandRetryable, func() error {
 				return runSubCommand(ctx, instance)
 			})
@@ -127,6 +155,11 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 						instance.Namespace: {},
 					},
 				},
+				&apiv1.Database{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
@@ -142,6 +175,11 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		Metrics: server.Options{
 			BindAddress: "0", // TODO: merge metrics to the manager one
 		},
+		// The controllers wait for the API server to be reachable
+		// instead of stopping the manager, and PostgreSQL with it
+		Controller: ctrlconfig.Controller{
+			CacheSyncTimeout: apiServerOutageTimeout,
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to set up overall controller manager")
@@ -223,6 +261,12 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	connectionStormGuard := connectionstorm.NewGuard(instance, reconciler)
+	if err = mgr.Add(connectionStormGuard); err != nil {
+		setupLog.Error(err, "unable to create connection storm guard")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -263,6 +307,13 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	setupLog.Info("starting database reconciler")
+	if err := databases.NewDatabaseReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create database reconciler")
+		return err
+	}
+
 	setupLog.Info("starting controller-runtime manager")
 	if err := mgr.Start(onlineUpgradeCtx); err != nil {
 		setupLog.Error(err, "unable to run controller-runtime manager")
@@ -271,3 +322,27 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 
 	return nil
 }
+
+// waitKubernetesAPIServer waits for the API server to be reachable. When it
+// is not, but a previous run of the instance manager persisted the definition
+// of the cluster, the instance manager has been restarted during an outage of
+// the API server, for example by an online upgrade: the cluster definition is
+// loaded into the local cache, and the instance manager starts and keeps the
+// running PostgreSQL instance, waiting for the API server before reconciling
+// it. The role of the instance is never changed using the persisted definition
+func waitKubernetesAPIServer(ctx context.Context, clusterObjectKey client.ObjectKey) error {
+	err := management.WaitKubernetesAPIServer(ctx, clusterObjectKey)
+	if err == nil {
+		return nil
+	}
+
+	cluster, loadErr := localcache.LoadPersistedCluster()
+	if loadErr != nil {
+		return err
+	}
+
+	log.Warning("The API server is not reachable, using the last known cluster definition",
+		"err", err.Error(), "resourceVersion", cluster.ResourceVersion)
+	localcache.StoreCluster(cluster)
+	return nil
+}
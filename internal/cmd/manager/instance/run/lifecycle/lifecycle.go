@@ -24,7 +24,9 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
@@ -111,6 +113,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					return nil
 				}
 				contextLogger.Info("Context has been cancelled, shutting down and exiting")
+				i.runPreShutdownHooks(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error shutting down instance, proceeding")
 				}
@@ -125,6 +128,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					"signal", sig,
 					"smartShutdownTimeout", i.instance.SmartStopDelay,
 				)
+				i.runPreShutdownHooks(ctx)
 				if err := i.instance.TryShuttingDownSmartFast(ctx); err != nil {
 					contextLogger.Error(err, "error while shutting down instance, proceeding")
 				}
@@ -152,3 +156,26 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 		// process
 	}
 }
+
+// runPreShutdownHooks invokes the hooks configured to run before PostgreSQL
+// is shut down. The shutdown can't be aborted, so failures are only logged
+func (i *PostgresLifecycle) runPreShutdownHooks(ctx context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		contextLogger.Warning("Cannot load the cluster, skipping the pre-shutdown hooks", "err", err)
+		return
+	}
+
+	// The context may have already been cancelled, but the hooks
+	// still need to run, within their own timeout
+	if err := lifecyclehooks.Run(
+		context.WithoutCancel(ctx),
+		cluster,
+		i.instance.PodName,
+		lifecyclehooks.EventPreShutdown,
+	); err != nil {
+		contextLogger.Error(err, "while running the pre-shutdown hooks")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package execute implements the "instance upgrade execute" subcommand of the operator
+package execute
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "execute" subcommand
+func NewCmd() *cobra.Command {
+	var pgData string
+	var options jobcommand.Options
+
+	cmd := &cobra.Command{
+		Use:   "execute [options]",
+		Short: "Upgrades the data directory to the major version of PostgreSQL of this image",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if err := options.Validate(jobcommand.CommandUpgradeExecute); err != nil {
+				log.Error(err, "Invalid options")
+				return err
+			}
+
+			oldBinDir, err := fileutils.ReadFile(options.OldBinDirFile)
+			if err != nil {
+				log.Error(err, "Error while reading the location of the old binaries")
+				return err
+			}
+
+			info := postgres.InitInfo{
+				InitDBOptions: options.InitDBFlags,
+				PgData:        pgData,
+				PgWal:         options.PgWal,
+			}
+
+			if err := info.UpgradeMajorVersion(ctx, strings.TrimSpace(string(oldBinDir))); err != nil {
+				log.Error(err, "Error while upgrading the data directory")
+				return err
+			}
+
+			return nil
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+
+	options.AddFlags(cmd.Flags(), jobcommand.CommandUpgradeExecute)
+
+	return cmd
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prepare implements the "instance upgrade prepare" subcommand of the operator
+package prepare
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the "prepare" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prepare [target]",
+		Short: "Copies the PostgreSQL binaries of this image to be used by pg_upgrade",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return prepareSubCommand(cmd.Context(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+// prepareSubCommand copies the executables, the libraries and the shared
+// files of PostgreSQL below the target directory, keeping their absolute
+// paths so that PostgreSQL can still locate them relatively to its
+// executables. The location of the executables is written in the "bindir"
+// file of the target directory
+func prepareSubCommand(ctx context.Context, target string) error {
+	contextLogger := log.FromContext(ctx)
+
+	var binDir string
+	for _, option := range []string{"--bindir", "--pkglibdir", "--sharedir"} {
+		dir, err := getPgConfig(option)
+		if err != nil {
+			return err
+		}
+		if option == "--bindir" {
+			binDir = dir
+		}
+
+		destination := path.Join(target, dir)
+		contextLogger.Info("Copying PostgreSQL files", "source", dir, "destination", destination)
+		if err := copyDirectory(dir, destination); err != nil {
+			return fmt.Errorf("while copying %s: %w", dir, err)
+		}
+	}
+
+	_, err := fileutils.WriteStringToFile(path.Join(target, "bindir"), path.Join(target, binDir))
+	return err
+}
+
+// getPgConfig gets the value of an option of pg_config
+func getPgConfig(option string) (string, error) {
+	out, err := exec.Command("pg_config", option).Output() // #nosec G204
+	if err != nil {
+		return "", fmt.Errorf("while executing pg_config %s: %w", option, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// copyDirectory copies the content of a directory, preserving
+// symbolic links and permissions
+func copyDirectory(source, destination string) error {
+	return filepath.WalkDir(source, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, current)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(current)
+			if err != nil {
+				return err
+			}
+			if err := fileutils.EnsureParentDirectoryExist(target); err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+
+		case entry.IsDir():
+			return fileutils.EnsureDirectoryExists(target)
+
+		default:
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if err := fileutils.CopyFile(current, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+	})
+}
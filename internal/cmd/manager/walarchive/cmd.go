@@ -36,10 +36,13 @@ import (
 	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walcommand"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -134,11 +137,12 @@ func run(
 	cluster *apiv1.Cluster,
 	args []string,
 ) error {
-	startTime := time.Now()
 	contextLog := log.FromContext(ctx)
 	walName := args[0]
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if cluster.Spec.Backup == nil ||
+		(cluster.Spec.Backup.BarmanObjectStore == nil && cluster.Spec.Backup.Plugin == nil &&
+			cluster.Spec.Backup.WalCommands == nil && cluster.Spec.Backup.PVC == nil) {
 		// Backup not configured, skipping WAL
 		contextLog.Info("Backup not configured, skip WAL archiving",
 			"walName", walName,
@@ -161,7 +165,13 @@ func run(
 		}
 	}
 
-	if cluster.Status.CurrentPrimary != podName {
+	// The timeline history file is written by the instance being promoted
+	// before it is recorded as the current primary. We archive it as soon
+	// as possible, as the other instances need it to follow the new timeline
+	archivingHistoryOnTargetPrimary := cluster.Status.CurrentPrimary != podName &&
+		cluster.Status.TargetPrimary == podName &&
+		postgres.IsHistoryFile(walName)
+	if cluster.Status.CurrentPrimary != podName && !archivingHistoryOnTargetPrimary {
 		contextLog.Info("Refusing to archive WAL when there is a switchover in progress",
 			"currentPrimary", cluster.Status.CurrentPrimary,
 			"targetPrimary", cluster.Status.TargetPrimary,
@@ -169,9 +179,108 @@ func run(
 		return errSwitchoverInProgress
 	}
 
+	if cluster.GetBackupPlugin() != nil {
+		return archiveWithPlugin(ctx, cluster, walName)
+	}
+
+	if walCommands := cluster.GetWalCommands(); walCommands != nil {
+		return archiveWithCommand(ctx, pgData, cluster, walCommands.ArchiveCommand, walName)
+	}
+
+	if cluster.GetBackupPVC() != nil {
+		return archiveWithPVC(ctx, pgData, cluster, walName)
+	}
+
+	return ArchiveWithBarman(ctx, podName, pgData, cluster, walName)
+}
+
+// archiveWithCommand archives a WAL file with the archive command
+// configured in the cluster
+func archiveWithCommand(
+	ctx context.Context,
+	pgData string,
+	cluster *apiv1.Cluster,
+	archiveCommand string,
+	walName string,
+) error {
+	startTime := time.Now()
+	if err := walcommand.Run(ctx, archiveCommand, pgData, walName, path.Base(walName)); err != nil {
+		return fmt.Errorf("while running the archive command: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Archived WAL file (command)",
+		"walName", walName,
+		"startTime", startTime,
+		"elapsedWalTime", time.Since(startTime),
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// archiveWithPVC archives a WAL file in the PersistentVolumeClaim
+// configured in the cluster
+func archiveWithPVC(
+	ctx context.Context,
+	pgData string,
+	cluster *apiv1.Cluster,
+	walName string,
+) error {
+	startTime := time.Now()
+	if err := pvcbackup.ArchiveWAL(pvcbackup.ServerDirectory(cluster), pgData, walName); err != nil {
+		return fmt.Errorf("while archiving the WAL file in the PVC: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Archived WAL file (pvc)",
+		"walName", walName,
+		"startTime", startTime,
+		"elapsedWalTime", time.Since(startTime),
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// archiveWithPlugin forwards the archiving of a WAL file to the
+// backup plugin configured in the cluster
+func archiveWithPlugin(ctx context.Context, cluster *apiv1.Cluster, walName string) error {
+	client, err := backupplugin.ConnectToClusterPlugin(cluster)
+	if err != nil {
+		return fmt.Errorf("while connecting to the backup plugin: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err := client.ArchiveWAL(ctx, cluster, walName); err != nil {
+		return fmt.Errorf("while archiving the WAL file with the backup plugin: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Archived WAL file (plugin)",
+		"walName", walName,
+		"plugin", cluster.GetBackupPlugin().Name,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// ArchiveWithBarman archives the passed WAL file, together with the
+// other WAL files ready to be archived, using barman-cloud-wal-archive
+func ArchiveWithBarman(
+	ctx context.Context,
+	podName, pgData string,
+	cluster *apiv1.Cluster,
+	walName string,
+) error {
+	startTime := time.Now()
+	contextLog := log.FromContext(ctx)
+
+	archivingHistoryOnTargetPrimary := cluster.Status.CurrentPrimary != podName &&
+		postgres.IsHistoryFile(walName)
+
 	maxParallel := 1
-	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
-		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
+	batchSize := 1
+	if walConfig := cluster.Spec.Backup.BarmanObjectStore.Wal; walConfig != nil && !archivingHistoryOnTargetPrimary {
+		maxParallel = max(walConfig.MaxParallel, 1)
+		batchSize = max(walConfig.ArchiveBatchSize, maxParallel)
 	}
 
 	// Get environment from cache
@@ -201,7 +310,7 @@ func run(
 	}
 
 	// Step 3: gather the WAL files names to archive
-	walFilesList := gatherWALFilesToArchive(ctx, walName, maxParallel)
+	walFilesList := gatherWALFilesToArchive(ctx, pgData, walName, batchSize)
 
 	// Step 4: Check if the archive location is safe to perform archiving
 	if utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
@@ -217,7 +326,7 @@ func run(
 
 	// Step 5: archive the WAL files in parallel
 	uploadStartTime := time.Now()
-	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
+	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options, maxParallel)
 	if len(walStatus) > 1 {
 		contextLog.Info("Completed archive command (parallel)",
 			"walsCount", len(walStatus),
@@ -235,20 +344,25 @@ func run(
 }
 
 // gatherWALFilesToArchive reads from the archived status the list of WAL files
-// that can be archived in parallel way.
+// that can be archived by a single invocation of the archive command.
 // `requestedWALFile` is the name of the file whose archiving was requested by
 // PostgreSQL, and that file is always the first of the list and is always included.
-// `parallel` is the maximum number of WALs that we can archive in parallel
-func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, parallel int) (walList []string) {
+// `batchSize` is the maximum number of WALs that we can archive in this invocation
+func gatherWALFilesToArchive(
+	ctx context.Context,
+	pgData string,
+	requestedWALFile string,
+	batchSize int,
+) (walList []string) {
 	contextLog := log.FromContext(ctx)
-	pgWalDirectory := path.Join(os.Getenv("PGDATA"), "pg_wal")
+	pgWalDirectory := path.Join(pgData, "pg_wal")
 	archiveStatusPath := path.Join(pgWalDirectory, "archive_status")
 	noMoreWALFilesNeeded := errors.New("no more files needed")
 
-	// allocate parallel + 1 only if it does not overflow. Cap otherwise
+	// allocate batchSize + 1 only if it does not overflow. Cap otherwise
 	var walListLength int
-	if parallel < math.MaxInt-1 {
-		walListLength = parallel + 1
+	if batchSize < math.MaxInt-1 {
+		walListLength = batchSize + 1
 	} else {
 		walListLength = math.MaxInt - 1
 	}
@@ -265,7 +379,7 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 			return filepath.SkipDir
 		}
 
-		if len(walList) >= parallel {
+		if len(walList) >= batchSize {
 			return noMoreWALFilesNeeded
 		}
 
@@ -333,6 +447,15 @@ func barmanCloudWalArchiveOptions(
 				"-e",
 				string(configuration.Wal.Encryption))
 		}
+		if len(configuration.Wal.KMSKeyID) != 0 {
+			if !capabilities.HasKMSKeyID {
+				return nil, fmt.Errorf("custom KMS keys are not supported in Barman %v", capabilities.Version)
+			}
+			options = append(
+				options,
+				"--sse-kms-key-id",
+				configuration.Wal.KMSKeyID)
+		}
 	}
 	if len(configuration.EndpointURL) > 0 {
 		options = append(
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Function gatherWALFilesToArchive", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		archiveStatus := filepath.Join(pgData, "pg_wal", "archive_status")
+		Expect(os.MkdirAll(archiveStatus, 0o700)).To(Succeed())
+		for idx := 1; idx <= 5; idx++ {
+			readyFile := filepath.Join(archiveStatus, fmt.Sprintf("00000001000000000000000%d.ready", idx))
+			Expect(os.WriteFile(readyFile, nil, 0o600)).To(Succeed())
+		}
+		Expect(os.WriteFile(filepath.Join(archiveStatus, "000000010000000000000009.done"), nil, 0o600)).
+			To(Succeed())
+	})
+
+	It("doesn't gather more files than the batch size", func() {
+		walList := gatherWALFilesToArchive(context.Background(), pgData, "pg_wal/000000010000000000000001", 3)
+		Expect(walList).To(Equal([]string{
+			"pg_wal/000000010000000000000001",
+			"pg_wal/000000010000000000000002",
+			"pg_wal/000000010000000000000003",
+		}))
+	})
+
+	It("only archives the requested file when the batch size is one", func() {
+		walList := gatherWALFilesToArchive(context.Background(), pgData, "pg_wal/000000010000000000000003", 1)
+		Expect(walList).To(Equal([]string{"pg_wal/000000010000000000000003"}))
+	})
+
+	It("gathers every ready file, once, when the batch is larger", func() {
+		walList := gatherWALFilesToArchive(context.Background(), pgData, "pg_wal/000000010000000000000003", 10)
+		Expect(walList).To(HaveLen(5))
+		Expect(walList[0]).To(Equal("pg_wal/000000010000000000000003"))
+		Expect(walList[1:]).To(ConsistOf(
+			"pg_wal/000000010000000000000001",
+			"pg_wal/000000010000000000000002",
+			"pg_wal/000000010000000000000004",
+			"pg_wal/000000010000000000000005",
+		))
+	})
+})
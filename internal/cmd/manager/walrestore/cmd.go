@@ -33,6 +33,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walcommand"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -111,6 +113,17 @@ func run(ctx context.Context, podName string, args []string) error {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
+	// The designated primary of a replica cluster restores the WAL files
+	// from the source cluster, not from the archive of this cluster
+	if walCommands := cluster.GetWalCommands(); walCommands != nil && walCommands.RestoreCommand != "" &&
+		!(cluster.IsReplica() && cluster.Status.CurrentPrimary == podName) {
+		return restoreWithCommand(ctx, cluster, walCommands.RestoreCommand, walName, destinationPath)
+	}
+
+	if cluster.GetBackupPVC() != nil && !(cluster.IsReplica() && cluster.Status.CurrentPrimary == podName) {
+		return restoreWithPVC(ctx, cluster, walName, destinationPath)
+	}
+
 	recoverClusterName, recoverEnv, barmanConfiguration, err := GetRecoverConfiguration(cluster, podName)
 	if errors.Is(err, ErrNoBackupConfigured) {
 		// Backup not configured, skipping WAL
@@ -270,6 +283,64 @@ func mergeEnv(env []string, incomingEnv []string) {
 	}
 }
 
+// restoreWithCommand restores a WAL file with the restore command
+// configured in the cluster
+func restoreWithCommand(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	restoreCommand string,
+	walName string,
+	destinationPath string,
+) error {
+	startTime := time.Now()
+
+	// PostgreSQL runs the restore command in the data directory, which
+	// is inherited by the command
+	if err := walcommand.Run(ctx, restoreCommand, "", destinationPath, walName); err != nil {
+		return fmt.Errorf("while running the restore command: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Restored WAL file (command)",
+		"walName", walName,
+		"startTime", startTime,
+		"elapsedWalTime", time.Since(startTime),
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// restoreWithPVC restores a WAL file from the PersistentVolumeClaim
+// configured in the cluster
+func restoreWithPVC(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	walName string,
+	destinationPath string,
+) error {
+	contextLog := log.FromContext(ctx)
+	startTime := time.Now()
+
+	err := pvcbackup.RestoreWAL(pvcbackup.ServerDirectory(cluster), walName, destinationPath)
+	if errors.Is(err, pvcbackup.ErrWALNotFound) {
+		contextLog.Info("WAL file not found in the PVC",
+			"walName", walName,
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary)
+		return restorer.ErrWALNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("while restoring the WAL file from the PVC: %w", err)
+	}
+
+	contextLog.Info("Restored WAL file (pvc)",
+		"walName", walName,
+		"startTime", startTime,
+		"elapsedWalTime", time.Since(startTime),
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
 // GetRecoverConfiguration get the appropriate recover Configuration for a given cluster
 func GetRecoverConfiguration(
 	cluster *apiv1.Cluster,
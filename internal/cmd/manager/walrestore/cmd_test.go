@@ -108,3 +108,27 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function gatherWALFilesToRestore", func() {
+	It("prefetches the following WAL files when restoring in parallel", func() {
+		walList, err := gatherWALFilesToRestore("0000000100000001000000FE", 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walList).To(Equal([]string{
+			"0000000100000001000000FE",
+			"0000000100000001000000FF",
+			"000000010000000200000000",
+		}))
+	})
+
+	It("only restores the requested WAL file without parallelism", func() {
+		walList, err := gatherWALFilesToRestore("000000010000000100000002", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walList).To(Equal([]string{"000000010000000100000002"}))
+	})
+
+	It("doesn't prefetch anything for files that are not WAL segments", func() {
+		walList, err := gatherWALFilesToRestore("00000002.history", 8)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walList).To(Equal([]string{"00000002.history"}))
+	})
+})
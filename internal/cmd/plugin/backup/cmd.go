@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -43,8 +45,15 @@ type backupCommandOptions struct {
 	online              *bool
 	immediateCheckpoint *bool
 	waitForArchive      *bool
+	crashConsistent     bool
+	wait                bool
+	waitTimeout         time.Duration
 }
 
+// backupPollInterval is how often the status of the backup
+// is checked while waiting for its completion
+const backupPollInterval = 2 * time.Second
+
 func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfiguration {
 	var onlineConfiguration *apiv1.OnlineConfiguration
 	if options.immediateCheckpoint != nil || options.waitForArchive != nil {
@@ -59,6 +68,8 @@ func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfig
 // NewCmd creates the new "backup" subcommand
 func NewCmd() *cobra.Command {
 	var backupName, backupTarget, backupMethod, online, immediateCheckpoint, waitForArchive string
+	var crashConsistent, waitForCompletion bool
+	var waitTimeout time.Duration
 
 	backupSubcommand := &cobra.Command{
 		Use:   "backup [cluster]",
@@ -90,6 +101,8 @@ func NewCmd() *cobra.Command {
 				"",
 				string(apiv1.BackupMethodBarmanObjectStore),
 				string(apiv1.BackupMethodVolumeSnapshot),
+				string(apiv1.BackupMethodPlugin),
+				string(apiv1.BackupMethodPVC),
 			}
 			if !slices.Contains(allowedBackupMethods, backupMethod) {
 				return fmt.Errorf("backup-method: %s is not supported by the backup command", backupMethod)
@@ -132,6 +145,9 @@ func NewCmd() *cobra.Command {
 					online:              parsedOnline,
 					immediateCheckpoint: parsedImmediateCheckpoint,
 					waitForArchive:      parsedWaitForArchive,
+					crashConsistent:     crashConsistent,
+					wait:                waitForCompletion,
+					waitTimeout:         waitTimeout,
 				})
 		},
 	}
@@ -157,7 +173,7 @@ func NewCmd() *cobra.Command {
 		"m",
 		"",
 		"If present, will override the backup method defined in backup resource, "+
-			"valid values are volumeSnapshot and barmanObjectStore.",
+			"valid values are volumeSnapshot, barmanObjectStore, plugin and pvc.",
 	)
 
 	const optionalAcceptedValues = "Optional. Accepted values: true|false|\"\"."
@@ -183,6 +199,20 @@ func NewCmd() *cobra.Command {
 			optionalAcceptedValues,
 	)
 
+	backupSubcommand.Flags().BoolVar(&crashConsistent, "crash-consistent", false,
+		"Set the `.spec.crashConsistent` field of the Backup resource, taking the "+
+			"volume snapshots without putting PostgreSQL in backup mode nor fencing the instance",
+	)
+
+	backupSubcommand.Flags().BoolVar(&waitForCompletion, "wait", false,
+		"Wait for the backup to be completed, printing its ID and location",
+	)
+
+	backupSubcommand.Flags().DurationVar(&waitTimeout, "wait-timeout", 0,
+		"The maximum time to wait for the backup to be completed, when "+
+			"using --wait. Defaults to 0, meaning no timeout",
+	)
+
 	return backupSubcommand
 }
 
@@ -201,15 +231,79 @@ func createBackup(ctx context.Context, options backupCommandOptions) error {
 			Method:              options.method,
 			Online:              options.online,
 			OnlineConfiguration: options.getOnlineConfiguration(),
+			CrashConsistent:     options.crashConsistent,
 		},
 	}
 	utils.LabelClusterName(&backup.ObjectMeta, options.clusterName)
 
-	err := plugin.Client.Create(ctx, &backup)
-	if err == nil {
-		fmt.Printf("backup/%v created\n", backup.Name)
+	if err := plugin.Client.Create(ctx, &backup); err != nil {
+		return err
 	}
-	return err
+	fmt.Printf("backup/%v created\n", backup.Name)
+
+	if !options.wait {
+		return nil
+	}
+
+	if options.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.waitTimeout)
+		defer cancel()
+	}
+
+	if err := waitForBackup(ctx, plugin.Client, &backup, backupPollInterval); err != nil {
+		return err
+	}
+
+	fmt.Print(describeCompletedBackup(&backup))
+	return nil
+}
+
+// waitForBackup polls the passed backup until it's either completed or
+// failed, updating it with the latest status
+func waitForBackup(
+	ctx context.Context,
+	cli client.Client,
+	backup *apiv1.Backup,
+	interval time.Duration,
+) error {
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(backup), backup); err != nil {
+			return false, err
+		}
+		return backup.Status.IsDone(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("while waiting for backup/%s to be completed: %w", backup.Name, err)
+	}
+
+	if backup.Status.Phase == apiv1.BackupPhaseFailed {
+		return fmt.Errorf("backup/%s failed: %s", backup.Name, backup.Status.Error)
+	}
+
+	return nil
+}
+
+// describeCompletedBackup returns the ID and the location of
+// a completed backup, in a human-readable form
+func describeCompletedBackup(backup *apiv1.Backup) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "backup/%s completed\n", backup.Name)
+	if backup.Status.BackupID != "" {
+		fmt.Fprintf(&sb, "Backup ID: %s\n", backup.Status.BackupID)
+	}
+
+	switch {
+	case backup.Status.DestinationPath != "":
+		fmt.Fprintf(&sb, "Location: %s\n", backup.Status.DestinationPath)
+	case len(backup.Status.BackupSnapshotStatus.Elements) > 0:
+		sb.WriteString("Location:\n")
+		for _, element := range backup.Status.BackupSnapshotStatus.Elements {
+			fmt.Fprintf(&sb, "  volumesnapshot/%s\n", element.Name)
+		}
+	}
+
+	return sb.String()
 }
 
 func parseOptionalBooleanString(rawBool string) (*bool, error) {
@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("waiting for a backup", func() {
+	newBackup := func(status apiv1.BackupStatus) *apiv1.Backup {
+		return &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-backup", Namespace: "default"},
+			Status:     status,
+		}
+	}
+
+	It("returns the status of a completed backup", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newBackup(apiv1.BackupStatus{
+				Phase:    apiv1.BackupPhaseCompleted,
+				BackupID: "20231010T101010",
+			})).
+			Build()
+
+		backup := newBackup(apiv1.BackupStatus{})
+		Expect(waitForBackup(ctx, cli, backup, time.Millisecond)).To(Succeed())
+		Expect(backup.Status.BackupID).To(Equal("20231010T101010"))
+	})
+
+	It("reports the error of a failed backup", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newBackup(apiv1.BackupStatus{
+				Phase: apiv1.BackupPhaseFailed,
+				Error: "no space left on device",
+			})).
+			Build()
+
+		err := waitForBackup(ctx, cli, newBackup(apiv1.BackupStatus{}), time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("no space left on device")))
+	})
+
+	It("stops waiting when the context expires", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newBackup(apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning})).
+			Build()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		Expect(waitForBackup(timeoutCtx, cli, newBackup(apiv1.BackupStatus{}), time.Millisecond)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("describeCompletedBackup", func() {
+	It("prints the ID and the destination path", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Status: apiv1.BackupStatus{
+				BackupID:        "20231010T101010",
+				DestinationPath: "s3://backups/",
+			},
+		}
+		Expect(describeCompletedBackup(backup)).To(Equal(
+			"backup/backup completed\nBackup ID: 20231010T101010\nLocation: s3://backups/\n"))
+	})
+
+	It("lists the volume snapshots", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Status: apiv1.BackupStatus{
+				BackupSnapshotStatus: apiv1.BackupSnapshotStatus{
+					Elements: []apiv1.BackupSnapshotElementStatus{{Name: "backup"}, {Name: "backup-wal"}},
+				},
+			},
+		}
+		Expect(describeCompletedBackup(backup)).To(Equal(
+			"backup/backup completed\nLocation:\n  volumesnapshot/backup\n  volumesnapshot/backup-wal\n"))
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "diff" subcommand
+func NewCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff [cluster]",
+		Short: "Preview the configuration changes the operator would apply to a cluster",
+		Long: "Compare the PostgreSQL configuration, the HBA rules and the PgBouncer configuration " +
+			"live on each instance and pooler with the ones the operator would apply, telling if a " +
+			"reload or a restart is needed. The changes can be previewed before applying them by " +
+			"passing the manifest containing the new Cluster and Pooler definitions.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			manifestFile, _ := cmd.Flags().GetString("filename")
+
+			return Diff(cmd.Context(), clusterName, manifestFile)
+		},
+	}
+
+	diffCmd.Flags().StringP(
+		"filename", "f", "",
+		"Manifest containing the new definition of the Cluster and of its Poolers")
+
+	return diffCmd
+}
@@ -0,0 +1,189 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ldapBindPasswordRegexp matches the LDAP bind password inside the HBA rules
+var ldapBindPasswordRegexp = regexp.MustCompile(`ldapbindpasswd=("(?:[^"]|"")*"|\S+)`)
+
+// ldapBindPasswordPlaceholder is the value used in place of the LDAP bind
+// password, that is never shown nor compared
+const ldapBindPasswordPlaceholder = `"********"`
+
+// fileDiff is the difference between the live content of a configuration
+// file and the one the operator would apply
+type fileDiff struct {
+	// fileName is the name of the compared file
+	fileName string
+
+	// lines are the changed lines, prefixed by "-" when they would be
+	// removed and by "+" when they would be added
+	lines []string
+
+	// restartRequired is true when the changes are applied only
+	// by restarting the server
+	restartRequired bool
+}
+
+// hasChanges is true when the live content differs from the expected one
+func (d fileDiff) hasChanges() bool {
+	return len(d.lines) > 0
+}
+
+// getApplyMethod describes how the changes would be applied
+func (d fileDiff) getApplyMethod() string {
+	switch {
+	case !d.hasChanges():
+		return "no changes"
+	case d.restartRequired:
+		return "restart required"
+	default:
+		return "reload required"
+	}
+}
+
+// diffPostgresConfiguration compares two PostgreSQL configuration files
+// parameter by parameter. The changes require a restart when any of the
+// changed parameters is one of the passed ones
+func diffPostgresConfiguration(fileName, live, expected string, restartParameters []string) fileDiff {
+	liveParameters := parsePostgresConfiguration(live)
+	expectedParameters := parsePostgresConfiguration(expected)
+
+	names := make([]string, 0, len(liveParameters)+len(expectedParameters))
+	for name := range liveParameters {
+		names = append(names, name)
+	}
+	for name := range expectedParameters {
+		if _, found := liveParameters[name]; !found {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	result := fileDiff{fileName: fileName}
+	for _, name := range names {
+		liveValue, inLive := liveParameters[name]
+		expectedValue, inExpected := expectedParameters[name]
+		if inLive == inExpected && liveValue == expectedValue {
+			continue
+		}
+
+		if inLive {
+			result.lines = append(result.lines, "- "+name+" = "+liveValue)
+		}
+		if inExpected {
+			result.lines = append(result.lines, "+ "+name+" = "+expectedValue)
+		}
+		if slices.Contains(restartParameters, name) {
+			result.restartRequired = true
+		}
+	}
+
+	return result
+}
+
+// parsePostgresConfiguration gets the parameters set in a PostgreSQL
+// configuration file generated by the operator, skipping its checksum
+func parsePostgresConfiguration(content string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == postgres.CNPGConfigSha256 {
+			continue
+		}
+		result[name] = strings.TrimSpace(value)
+	}
+
+	return result
+}
+
+// diffLines compares two files line by line, ignoring the empty lines
+func diffLines(fileName, live, expected string) fileDiff {
+	liveLines := getNonEmptyLines(live)
+	expectedLines := getNonEmptyLines(expected)
+
+	// lcs[i][j] is the length of the longest common subsequence
+	// between liveLines[i:] and expectedLines[j:]
+	lcs := make([][]int, len(liveLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(expectedLines)+1)
+	}
+	for i := len(liveLines) - 1; i >= 0; i-- {
+		for j := len(expectedLines) - 1; j >= 0; j-- {
+			if liveLines[i] == expectedLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	result := fileDiff{fileName: fileName}
+	i, j := 0, 0
+	for i < len(liveLines) || j < len(expectedLines) {
+		switch {
+		case i < len(liveLines) && j < len(expectedLines) && liveLines[i] == expectedLines[j]:
+			i++
+			j++
+		case j == len(expectedLines) || (i < len(liveLines) && lcs[i+1][j] >= lcs[i][j+1]):
+			result.lines = append(result.lines, "- "+liveLines[i])
+			i++
+		default:
+			result.lines = append(result.lines, "+ "+expectedLines[j])
+			j++
+		}
+	}
+
+	return result
+}
+
+// getNonEmptyLines splits a file in lines, removing the empty ones
+func getNonEmptyLines(content string) []string {
+	var result []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		result = append(result, line)
+	}
+
+	return result
+}
+
+// redactLDAPBindPassword replaces the LDAP bind password in the HBA rules
+// with a placeholder
+func redactLDAPBindPassword(content string) string {
+	return ldapBindPasswordRegexp.ReplaceAllString(content, "ldapbindpasswd="+ldapBindPasswordPlaceholder)
+}
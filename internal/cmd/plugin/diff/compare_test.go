@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL configuration diff", func() {
+	live := "max_connections = '100'\nshared_buffers = '128MB'\nwork_mem = '4MB'\ncnpg.config_sha256 = 'abc'"
+
+	It("detects no changes when only the checksum differs", func() {
+		expected := "max_connections = '100'\nshared_buffers = '128MB'\nwork_mem = '4MB'\ncnpg.config_sha256 = 'def'"
+		diff := diffPostgresConfiguration("custom.conf", live, expected, []string{"max_connections"})
+		Expect(diff.hasChanges()).To(BeFalse())
+		Expect(diff.getApplyMethod()).To(Equal("no changes"))
+	})
+
+	It("requires a reload when only reloadable parameters change", func() {
+		expected := "max_connections = '100'\nshared_buffers = '128MB'\nwork_mem = '8MB'\nlog_min_duration_statement = '1s'"
+		diff := diffPostgresConfiguration("custom.conf", live, expected, []string{"max_connections"})
+		Expect(diff.lines).To(Equal([]string{
+			"+ log_min_duration_statement = '1s'",
+			"- work_mem = '4MB'",
+			"+ work_mem = '8MB'",
+		}))
+		Expect(diff.getApplyMethod()).To(Equal("reload required"))
+	})
+
+	It("requires a restart when a postmaster parameter changes", func() {
+		expected := "max_connections = '200'\nshared_buffers = '128MB'\nwork_mem = '4MB'"
+		diff := diffPostgresConfiguration("custom.conf", live, expected, []string{"max_connections"})
+		Expect(diff.lines).To(Equal([]string{
+			"- max_connections = '100'",
+			"+ max_connections = '200'",
+		}))
+		Expect(diff.getApplyMethod()).To(Equal("restart required"))
+	})
+
+	It("requires a restart when a postmaster parameter is removed", func() {
+		expected := "max_connections = '100'\nwork_mem = '4MB'"
+		diff := diffPostgresConfiguration("custom.conf", live, expected, []string{"shared_buffers"})
+		Expect(diff.lines).To(Equal([]string{"- shared_buffers = '128MB'"}))
+		Expect(diff.restartRequired).To(BeTrue())
+	})
+})
+
+var _ = Describe("Line by line diff", func() {
+	It("ignores the empty lines", func() {
+		diff := diffLines("pg_hba.conf", "local all all peer\n\nhost all all 0.0.0.0/0 md5\n",
+			"\nlocal all all peer\nhost all all 0.0.0.0/0 md5")
+		Expect(diff.hasChanges()).To(BeFalse())
+	})
+
+	It("keeps the order of the changed lines", func() {
+		live := "local all all peer\nhost all all 10.0.0.0/8 md5\nhost all all 0.0.0.0/0 md5"
+		expected := "local all all peer\nhostssl app app 10.0.0.0/8 scram-sha-256\n" +
+			"host all all 10.0.0.0/8 md5\nhost all all 0.0.0.0/0 scram-sha-256"
+		diff := diffLines("pg_hba.conf", live, expected)
+		Expect(diff.lines).To(Equal([]string{
+			"+ hostssl app app 10.0.0.0/8 scram-sha-256",
+			"- host all all 0.0.0.0/0 md5",
+			"+ host all all 0.0.0.0/0 scram-sha-256",
+		}))
+		Expect(diff.getApplyMethod()).To(Equal("reload required"))
+	})
+
+	It("detects the moved lines", func() {
+		diff := diffLines("pg_hba.conf", "a\nb", "b\na")
+		Expect(diff.lines).To(Equal([]string{"- a", "+ a"}))
+	})
+})
+
+var _ = Describe("LDAP bind password redaction", func() {
+	It("hides the quoted password", func() {
+		Expect(redactLDAPBindPassword(
+			`host all all 0.0.0.0/0 ldap ldapbinddn="cn=admin" ldapbindpasswd="se""cret" ldapsearchattribute="uid"`)).
+			To(Equal(`host all all 0.0.0.0/0 ldap ldapbinddn="cn=admin" ldapbindpasswd="********" ldapsearchattribute="uid"`))
+	})
+
+	It("leaves the rules without a password untouched", func() {
+		Expect(redactLDAPBindPassword("host all all 0.0.0.0/0 md5")).To(Equal("host all all 0.0.0.0/0 md5"))
+	})
+})
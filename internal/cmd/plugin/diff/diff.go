@@ -0,0 +1,337 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/logrusorgru/aurora/v4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	pgbouncer "github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// pgBouncerContainerName is the name of the container running PgBouncer
+	pgBouncerContainerName = "pgbouncer"
+
+	// restartParametersQuery gets the parameters that can be changed
+	// only by restarting the server
+	restartParametersQuery = "SELECT name FROM pg_catalog.pg_settings WHERE context = 'postmaster'"
+)
+
+// Diff shows the configuration changes the operator would apply to the
+// instances and to the poolers of a cluster. When a manifest is passed,
+// the Cluster and the Poolers it contains are used in place of the
+// ones running in Kubernetes
+func Diff(ctx context.Context, clusterName string, manifestFile string) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	var poolerList apiv1.PoolerList
+	if err := plugin.Client.List(ctx, &poolerList, client.InNamespace(plugin.Namespace)); err != nil {
+		return err
+	}
+	poolers := getClusterPoolers(poolerList.Items, clusterName)
+
+	if manifestFile != "" {
+		objects, err := readManifest(manifestFile)
+		if err != nil {
+			return err
+		}
+		if err := applyManifest(objects, &cluster, poolers); err != nil {
+			return err
+		}
+	}
+
+	instances, _, err := resources.GetInstancePods(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+
+	expectedConfiguration, err := postgres.GeneratePostgresqlConfiguration(&cluster)
+	if err != nil {
+		return err
+	}
+
+	// The LDAP bind password is redacted before comparing the HBA
+	// rules, so it's not needed to generate them
+	expectedHBA, err := (&postgres.Instance{}).GeneratePostgresqlHBA(&cluster, "")
+	if err != nil {
+		return err
+	}
+
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	for idx := range instances {
+		diffs, err := diffInstance(ctx, clientInterface, instances[idx], expectedConfiguration, expectedHBA)
+		printDiffs(fmt.Sprintf("Instance %s", instances[idx].Name), diffs, err)
+	}
+
+	for idx := range poolers {
+		if err := diffPooler(ctx, clientInterface, &poolers[idx]); err != nil {
+			printDiffs(fmt.Sprintf("Pooler %s", poolers[idx].Name), nil, err)
+		}
+	}
+
+	return nil
+}
+
+// getClusterPoolers gets the poolers of the cluster with the passed name
+func getClusterPoolers(poolers []apiv1.Pooler, clusterName string) []apiv1.Pooler {
+	var result []apiv1.Pooler
+	for _, pooler := range poolers {
+		if pooler.Spec.Cluster.Name == clusterName {
+			result = append(result, pooler)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// readManifest reads the objects contained in a YAML or JSON manifest
+func readManifest(fileName string) ([]json.RawMessage, error) {
+	file, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var result []json.RawMessage
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var object json.RawMessage
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while reading %s: %w", fileName, err)
+		}
+
+		if len(object) > 0 && string(object) != "null" {
+			result = append(result, object)
+		}
+	}
+}
+
+// applyManifest replaces the definition of the cluster and of its poolers
+// with the ones contained in the manifest. The status of the existing
+// objects is kept, as it's needed to generate the configuration
+func applyManifest(objects []json.RawMessage, cluster *apiv1.Cluster, poolers []apiv1.Pooler) error {
+	for _, object := range objects {
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(object, &typeMeta); err != nil {
+			return err
+		}
+
+		switch typeMeta.Kind {
+		case apiv1.ClusterKind:
+			var newCluster apiv1.Cluster
+			if err := json.Unmarshal(object, &newCluster); err != nil {
+				return err
+			}
+			if newCluster.Name != cluster.Name {
+				return fmt.Errorf("the manifest contains the cluster %q instead of %q", newCluster.Name, cluster.Name)
+			}
+
+			newCluster.Namespace = cluster.Namespace
+			newCluster.Status = cluster.Status
+			newCluster.SetDefaults()
+			*cluster = newCluster
+
+		case apiv1.PoolerKind:
+			var newPooler apiv1.Pooler
+			if err := json.Unmarshal(object, &newPooler); err != nil {
+				return err
+			}
+
+			// A new pooler has no running pods to compare with
+			for idx := range poolers {
+				if poolers[idx].Name == newPooler.Name {
+					poolers[idx].Spec = newPooler.Spec
+				}
+			}
+
+		default:
+			return fmt.Errorf("unsupported kind %q in the manifest", typeMeta.Kind)
+		}
+	}
+
+	return nil
+}
+
+// diffInstance compares the configuration files live on an instance
+// with the expected ones
+func diffInstance(
+	ctx context.Context,
+	clientInterface kubernetes.Interface,
+	pod corev1.Pod,
+	expectedConfiguration string,
+	expectedHBA string,
+) ([]fileDiff, error) {
+	liveConfiguration, err := readFile(ctx, clientInterface, pod, specs.PostgresContainerName,
+		path.Join(specs.PgDataPath, constants.PostgresqlCustomConfigurationFile))
+	if err != nil {
+		return nil, err
+	}
+
+	liveHBA, err := readFile(ctx, clientInterface, pod, specs.PostgresContainerName,
+		path.Join(specs.PgDataPath, constants.PostgresqlHBARulesFile))
+	if err != nil {
+		return nil, err
+	}
+
+	restartParameters, err := getRestartParameters(ctx, clientInterface, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	return []fileDiff{
+		diffPostgresConfiguration(
+			constants.PostgresqlCustomConfigurationFile,
+			liveConfiguration,
+			expectedConfiguration,
+			restartParameters),
+		diffLines(
+			constants.PostgresqlHBARulesFile,
+			redactLDAPBindPassword(liveHBA),
+			redactLDAPBindPassword(expectedHBA)),
+	}, nil
+}
+
+// diffPooler compares the PgBouncer configuration live on each pod
+// of the pooler with the expected one
+func diffPooler(ctx context.Context, clientInterface kubernetes.Interface, pooler *apiv1.Pooler) error {
+	secrets, err := pgbouncer.GetSecrets(ctx, plugin.Client, pooler)
+	if err != nil {
+		return err
+	}
+
+	files, err := config.BuildConfigurationFiles(pooler, secrets)
+	if err != nil {
+		return err
+	}
+	iniFileName := filepath.Join(config.ConfigsDir, config.PgBouncerIniFileName)
+	expectedIni := string(files[iniFileName])
+
+	var pods corev1.PodList
+	if err := plugin.Client.List(ctx, &pods, client.InNamespace(plugin.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name}); err != nil {
+		return err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	for idx := range pods.Items {
+		pod := pods.Items[idx]
+		liveIni, err := readFile(ctx, clientInterface, pod, pgBouncerContainerName, iniFileName)
+
+		// PgBouncer applies every configuration change with a reload
+		var diffs []fileDiff
+		if err == nil {
+			diffs = []fileDiff{diffLines(config.PgBouncerIniFileName, liveIni, expectedIni)}
+		}
+		printDiffs(fmt.Sprintf("Pooler %s, pod %s", pooler.Name, pod.Name), diffs, err)
+	}
+
+	return nil
+}
+
+// readFile reads the content of a file inside a container of a pod
+func readFile(
+	ctx context.Context,
+	clientInterface kubernetes.Interface,
+	pod corev1.Pod,
+	containerName string,
+	fileName string,
+) (string, error) {
+	timeout := time.Second * 10
+	stdout, _, err := utils.ExecCommand(ctx, clientInterface, plugin.Config, pod,
+		containerName,
+		&timeout,
+		"cat", fileName)
+	if err != nil {
+		return "", fmt.Errorf("while reading %s: %w", fileName, err)
+	}
+
+	return stdout, nil
+}
+
+// getRestartParameters gets from an instance the parameters that can be
+// changed only by restarting the server
+func getRestartParameters(ctx context.Context, clientInterface kubernetes.Interface, pod corev1.Pod) ([]string, error) {
+	timeout := time.Second * 10
+	stdout, _, err := utils.ExecCommand(ctx, clientInterface, plugin.Config, pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"psql", "-XAt", "-c", restartParametersQuery)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the parameters requiring a restart: %w", err)
+	}
+
+	return getNonEmptyLines(stdout), nil
+}
+
+// printDiffs prints the differences found for an instance or a pooler pod
+func printDiffs(title string, diffs []fileDiff, err error) {
+	fmt.Println(aurora.Green(title))
+	if err != nil {
+		fmt.Println(aurora.Red(err.Error()))
+		fmt.Println()
+		return
+	}
+
+	for _, diff := range diffs {
+		fmt.Printf("%s: %s\n", diff.fileName, diff.getApplyMethod())
+		for _, line := range diff.lines {
+			if strings.HasPrefix(line, "-") {
+				fmt.Println(aurora.Red(line))
+			} else {
+				fmt.Println(aurora.Green(line))
+			}
+		}
+	}
+	fmt.Println()
+}
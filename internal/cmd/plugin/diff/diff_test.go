@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	writeManifest := func(content string) string {
+		fileName := filepath.Join(GinkgoT().TempDir(), "manifest.yaml")
+		Expect(os.WriteFile(fileName, []byte(content), 0o600)).To(Succeed())
+		return fileName
+	}
+
+	newCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "4MB"},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+	}
+
+	newPoolers := func() []apiv1.Pooler {
+		return []apiv1.Pooler{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pooler-rw", Namespace: "default"},
+				Spec: apiv1.PoolerSpec{
+					Cluster:   apiv1.LocalObjectReference{Name: "cluster-example"},
+					PgBouncer: &apiv1.PgBouncerSpec{PoolMode: apiv1.PgBouncerPoolModeSession},
+				},
+			},
+		}
+	}
+
+	It("replaces the cluster and the poolers keeping their status", func() {
+		objects, err := readManifest(writeManifest(`
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: cluster-example
+spec:
+  instances: 3
+  postgresql:
+    parameters:
+      work_mem: 8MB
+---
+apiVersion: postgresql.cnpg.io/v1
+kind: Pooler
+metadata:
+  name: pooler-rw
+spec:
+  cluster:
+    name: cluster-example
+  pgbouncer:
+    poolMode: transaction
+---
+apiVersion: postgresql.cnpg.io/v1
+kind: Pooler
+metadata:
+  name: pooler-new
+spec:
+  cluster:
+    name: cluster-example
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(HaveLen(3))
+
+		cluster := newCluster()
+		poolers := newPoolers()
+		Expect(applyManifest(objects, cluster, poolers)).To(Succeed())
+		Expect(cluster.Namespace).To(Equal("default"))
+		Expect(cluster.Status.CurrentPrimary).To(Equal("cluster-example-1"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("work_mem", "8MB"))
+		Expect(poolers).To(HaveLen(1))
+		Expect(poolers[0].Spec.PgBouncer.PoolMode).To(Equal(apiv1.PgBouncerPoolModeTransaction))
+	})
+
+	It("refuses a different cluster", func() {
+		objects, err := readManifest(writeManifest(`
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: another-cluster
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applyManifest(objects, newCluster(), newPoolers())).ToNot(Succeed())
+	})
+
+	It("refuses unsupported kinds", func() {
+		objects, err := readManifest(writeManifest(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-example
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applyManifest(objects, newCluster(), newPoolers())).ToNot(Succeed())
+	})
+
+	It("gets the poolers of the cluster", func() {
+		poolers := append(newPoolers(), apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "another-pooler"},
+			Spec:       apiv1.PoolerSpec{Cluster: apiv1.LocalObjectReference{Name: "another-cluster"}},
+		})
+		Expect(getClusterPoolers(poolers, "cluster-example")).To(HaveLen(1))
+	})
+})
@@ -18,11 +18,8 @@ limitations under the License.
 package maintenance
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/cheynewallace/tabby"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -74,7 +71,7 @@ func Maintenance(ctx context.Context,
 
 	clusters.Print()
 	if confirmationRequired {
-		proceed := askToProceed()
+		proceed := plugin.AskToProceed()
 		if !proceed {
 			return nil
 		}
@@ -90,20 +87,6 @@ func Maintenance(ctx context.Context,
 	return nil
 }
 
-func askToProceed() bool {
-	fmt.Printf("Do you want to proceed? [y/n]: ")
-	reader := bufio.NewReader(os.Stdin)
-	answer, err := reader.ReadString('\n')
-	if err != nil {
-		return false
-	}
-	answer = strings.ToLower(strings.TrimSpace(answer))
-	if answer == "y" || answer == "yes" {
-		return true
-	}
-	return false
-}
-
 func getClusters(ctx context.Context, allNamespaces bool) (v1.ClusterList, error) {
 	var clusterList v1.ClusterList
 	var opts []client.ListOption
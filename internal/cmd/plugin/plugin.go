@@ -18,13 +18,16 @@ limitations under the License.
 package plugin
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
@@ -87,6 +90,7 @@ func createClient(cfg *rest.Config) error {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = apiv1.AddToScheme(scheme)
 	_ = storagesnapshotv1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 
 	Client, err = client.New(cfg, client.Options{Scheme: scheme})
 	if err != nil {
@@ -116,6 +120,22 @@ func CreateAndGenerateObjects(ctx context.Context, k8sObject []client.Object, op
 	return nil
 }
 
+// AskToProceed asks the user for a confirmation on the standard input,
+// returning true if the user agreed to proceed
+func AskToProceed() bool {
+	fmt.Printf("Do you want to proceed? [y/n]: ")
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	return false
+}
+
 // GetPGControlData obtains the PgControldata from the passed pod by doing an exec.
 // This approach should be used only in the plugin commands.
 func GetPGControlData(
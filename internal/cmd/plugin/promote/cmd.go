@@ -26,10 +26,15 @@ import (
 
 // NewCmd create the new "promote" subcommand
 func NewCmd() *cobra.Command {
+	var force, skipConfirmation bool
+
 	promoteCmd := &cobra.Command{
 		Use:   "promote [cluster] [node]",
 		Short: "Promote the pod named [cluster]-[node] or [node] to primary",
-		Args:  cobra.ExactArgs(2),
+		Long: "This command will trigger a switchover to the pod named [cluster]-[node] or [node], " +
+			"asking for a confirmation before proceeding. The switchover is refused when the " +
+			"current primary is not healthy, unless the --force flag is used",
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			clusterName := args[0]
@@ -37,9 +42,14 @@ func NewCmd() *cobra.Command {
 			if _, err := strconv.Atoi(args[1]); err == nil {
 				node = fmt.Sprintf("%s-%s", clusterName, node)
 			}
-			return Promote(ctx, clusterName, node)
+			return Promote(ctx, clusterName, node, force, skipConfirmation)
 		},
 	}
 
+	promoteCmd.Flags().BoolVar(&force,
+		"force", false, "Promote the pod even if the current primary is not healthy")
+	promoteCmd.Flags().BoolVarP(&skipConfirmation,
+		"yes", "y", false, "Proceed without asking for confirmation")
+
 	return promoteCmd
 }
@@ -20,8 +20,12 @@ package promote
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -30,7 +34,7 @@ import (
 )
 
 // Promote command implementation
-func Promote(ctx context.Context, clusterName string, serverName string) error {
+func Promote(ctx context.Context, clusterName string, serverName string, force, skipConfirmation bool) error {
 	var cluster apiv1.Cluster
 
 	// Get the Cluster object
@@ -46,6 +50,12 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return nil
 	}
 
+	// A switchover announced by the operator is still in flight, and
+	// promoting now would race with it. This can't be forced
+	if err := checkNoPlannedSwitchover(&cluster); err != nil {
+		return err
+	}
+
 	// Check if the Pod exist
 	var pod v1.Pod
 	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: serverName}, &pod)
@@ -53,6 +63,55 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return fmt.Errorf("new primary node %s not found in namespace %s", serverName, plugin.Namespace)
 	}
 
+	// A switchover needs the current primary to be shut down
+	// cleanly, which can't happen when it is not healthy
+	if !force {
+		var primaryPod *v1.Pod
+		if cluster.Status.CurrentPrimary != "" {
+			primaryPod = &v1.Pod{}
+			err = plugin.Client.Get(
+				ctx,
+				client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+				primaryPod)
+			if apierrs.IsNotFound(err) {
+				primaryPod = nil
+			} else if err != nil {
+				return err
+			}
+		}
+		if err := checkPrimaryHealth(&cluster, primaryPod); err != nil {
+			return fmt.Errorf("%w, use --force to promote %s anyway", err, serverName)
+		}
+	}
+
+	if !skipConfirmation {
+		if !isStdinTerminal() {
+			return fmt.Errorf("cannot ask for a confirmation as the standard input is not a terminal, "+
+				"use --yes to promote %s anyway", serverName)
+		}
+		fmt.Printf("Node %s in cluster %s will be promoted, replacing the current primary %s\n",
+			serverName, clusterName, cluster.Status.CurrentPrimary)
+		if !plugin.AskToProceed() {
+			return fmt.Errorf("promotion of %s not confirmed, use --yes to skip the confirmation", serverName)
+		}
+	}
+
+	// The switchover is orchestrated, let the operator drain
+	// the applications before promoting the instance
+	if cluster.IsSwitchoverOrchestrated() {
+		cluster.Status.PlannedSwitchover = &apiv1.PlannedSwitchoverStatus{
+			TargetPrimary: serverName,
+			Reason:        "requested by the user",
+			AnnouncedAt:   metav1.Now(),
+		}
+		if err := plugin.Client.Status().Update(ctx, &cluster); err != nil {
+			return err
+		}
+
+		fmt.Printf("Switchover to node %s in cluster %s has been announced\n", serverName, clusterName)
+		return nil
+	}
+
 	// The Pod exists, let's update status fields
 	cluster.Status.TargetPrimary = serverName
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
@@ -67,3 +126,42 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 	fmt.Printf("Node %s in cluster %s will be promoted\n", serverName, clusterName)
 	return nil
 }
+
+// isStdinTerminal checks if the standard input is attached to a terminal,
+// where the user can answer the confirmation prompt
+func isStdinTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// checkPrimaryHealth checks that the current primary of the cluster,
+// whose pod is passed when it exists, is healthy
+func checkPrimaryHealth(cluster *apiv1.Cluster, primaryPod *v1.Pod) error {
+	switch {
+	case cluster.Status.CurrentPrimary == "":
+		return fmt.Errorf("cluster %s has no current primary", cluster.Name)
+	case primaryPod == nil:
+		return fmt.Errorf("current primary %s not found", cluster.Status.CurrentPrimary)
+	case primaryPod.DeletionTimestamp != nil:
+		return fmt.Errorf("current primary %s is being deleted", cluster.Status.CurrentPrimary)
+	case !utils.IsPodReady(*primaryPod):
+		return fmt.Errorf("current primary %s is not ready", cluster.Status.CurrentPrimary)
+	default:
+		return nil
+	}
+}
+
+// checkNoPlannedSwitchover checks that no orchestrated switchover is in
+// progress in the cluster
+func checkNoPlannedSwitchover(cluster *apiv1.Cluster) error {
+	if planned := cluster.Status.PlannedSwitchover; planned.IsPending() {
+		return fmt.Errorf("a switchover to %s is already in progress in cluster %s, announced at %s",
+			planned.TargetPrimary, cluster.Name, planned.AnnouncedAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
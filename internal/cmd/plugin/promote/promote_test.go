@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promote
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary health", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Status:     apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+	}
+
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	It("accepts a ready primary", func() {
+		Expect(checkPrimaryHealth(cluster, readyPod())).To(Succeed())
+	})
+
+	It("complains if the cluster has no current primary", func() {
+		Expect(checkPrimaryHealth(&apiv1.Cluster{}, nil)).ToNot(Succeed())
+	})
+
+	It("complains if the pod of the primary doesn't exist", func() {
+		Expect(checkPrimaryHealth(cluster, nil)).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("complains if the primary is not ready", func() {
+		pod := readyPod()
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		Expect(checkPrimaryHealth(cluster, pod)).To(MatchError(ContainSubstring("not ready")))
+	})
+
+	It("complains if the primary is being deleted", func() {
+		pod := readyPod()
+		pod.DeletionTimestamp = &metav1.Time{}
+		Expect(checkPrimaryHealth(cluster, pod)).To(MatchError(ContainSubstring("being deleted")))
+	})
+})
+
+var _ = Describe("planned switchover check", func() {
+	It("accepts a cluster without a planned switchover", func() {
+		Expect(checkNoPlannedSwitchover(&apiv1.Cluster{})).To(Succeed())
+	})
+
+	It("accepts a cluster whose planned switchover was aborted", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				PlannedSwitchover: &apiv1.PlannedSwitchoverStatus{
+					TargetPrimary: "cluster-example-2",
+					AbortedAt:     &metav1.Time{},
+				},
+			},
+		}
+		Expect(checkNoPlannedSwitchover(cluster)).To(Succeed())
+	})
+
+	It("refuses a cluster with a switchover in progress", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status: apiv1.ClusterStatus{
+				PlannedSwitchover: &apiv1.PlannedSwitchoverStatus{
+					TargetPrimary: "cluster-example-2",
+					AnnouncedAt:   metav1.Now(),
+				},
+			},
+		}
+		Expect(checkNoPlannedSwitchover(cluster)).To(
+			MatchError(ContainSubstring("switchover to cluster-example-2 is already in progress")))
+	})
+})
@@ -27,6 +27,7 @@ import (
 // NewCmd creates the "psql" command
 func NewCmd() *cobra.Command {
 	var replica bool
+	var pooler string
 	var allocateTTY bool
 	var passStdin bool
 
@@ -40,6 +41,7 @@ func NewCmd() *cobra.Command {
 			psqlArgs := args[1:]
 			psqlOptions := psqlCommandOptions{
 				replica:     replica,
+				pooler:      pooler,
 				namespace:   plugin.Namespace,
 				allocateTTY: allocateTTY,
 				passStdin:   passStdin,
@@ -63,6 +65,14 @@ func NewCmd() *cobra.Command {
 		"Connects to the first replica on the pod list (by default connects to the primary)",
 	)
 
+	cmd.Flags().StringVar(
+		&pooler,
+		"pooler",
+		"",
+		"Connects to the application database through the given pooler of the cluster. "+
+			"psql will ask for the password of the application user",
+	)
+
 	cmd.Flags().BoolVarP(
 		&allocateTTY,
 		"tty",
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -43,6 +44,9 @@ type psqlCommand struct {
 	// The list of possible pods where to launch psql
 	podList []corev1.Pod
 
+	// The psql connection arguments preceding the ones passed by the user
+	connectionArgs []string
+
 	// The path of kubectl
 	kubectlPath string
 }
@@ -52,6 +56,9 @@ type psqlCommandOptions struct {
 	// Require a connection to a replica
 	replica bool
 
+	// The name of the pooler to connect through, if any
+	pooler string
+
 	// The cluster name
 	name string
 
@@ -88,16 +95,62 @@ func newPsqlCommand(
 		return nil, fmt.Errorf("while getting kubectl path: %w", err)
 	}
 
+	var connectionArgs []string
+	if len(options.pooler) > 0 {
+		if connectionArgs, err = getPoolerConnectionArgs(ctx, options); err != nil {
+			return nil, err
+		}
+	}
+
 	return &psqlCommand{
 		psqlCommandOptions: options,
 		podList:            pods.Items,
+		connectionArgs:     connectionArgs,
 		kubectlPath:        kubectlPath,
 	}, nil
 }
 
+// getPoolerConnectionArgs gets the psql arguments needed to connect
+// to the application database through the passed pooler
+func getPoolerConnectionArgs(ctx context.Context, options psqlCommandOptions) ([]string, error) {
+	var pooler apiv1.Pooler
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: options.pooler},
+		&pooler,
+	); err != nil {
+		return nil, fmt.Errorf("while getting pooler %s: %w", options.pooler, err)
+	}
+
+	if pooler.Spec.Cluster.Name != options.name {
+		return nil, fmt.Errorf("pooler %s doesn't belong to cluster %s", options.pooler, options.name)
+	}
+
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: options.name},
+		&cluster,
+	); err != nil {
+		return nil, fmt.Errorf("while getting cluster %s: %w", options.name, err)
+	}
+
+	return buildPoolerConnectionArgs(&pooler, &cluster), nil
+}
+
+// buildPoolerConnectionArgs builds the psql arguments needed to connect
+// to the application database through the pooler service
+func buildPoolerConnectionArgs(pooler *apiv1.Pooler, cluster *apiv1.Cluster) []string {
+	return []string{
+		"--host", pooler.Name,
+		"--username", cluster.GetApplicationDatabaseOwner(),
+		"--dbname", cluster.GetApplicationDatabaseName(),
+	}
+}
+
 // getKubectlInvocation gets the kubectl command to be executed
 func (psql *psqlCommand) getKubectlInvocation() ([]string, error) {
-	result := make([]string, 0, 11+len(psql.args))
+	result := make([]string, 0, 11+len(psql.connectionArgs)+len(psql.args))
 	result = append(result, "kubectl", "exec")
 
 	if psql.allocateTTY {
@@ -118,6 +171,7 @@ func (psql *psqlCommand) getKubectlInvocation() ([]string, error) {
 
 	result = append(result, podName)
 	result = append(result, "--", "psql")
+	result = append(result, psql.connectionArgs...)
 	result = append(result, psql.args...)
 	return result, nil
 }
@@ -20,6 +20,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -124,6 +125,54 @@ var _ = Describe("psql launcher", func() {
 			"select 1",
 		))
 	})
+
+	It("correctly composes a kubectl exec command line connecting through a pooler", func() {
+		pooler := &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-example-rw"},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+					},
+				},
+			},
+		}
+		cmd := psqlCommand{
+			psqlCommandOptions: psqlCommandOptions{
+				pooler:    "pooler-example-rw",
+				namespace: "default",
+				args: []string{
+					"-c",
+					"select 1",
+				},
+			},
+			podList:        podList,
+			connectionArgs: buildPoolerConnectionArgs(pooler, cluster),
+		}
+		Expect(cmd.getKubectlInvocation()).To(Equal([]string{
+			"kubectl",
+			"exec",
+			"-n",
+			"default",
+			"-c",
+			"postgres",
+			"cluster-example-2",
+			"--",
+			"psql",
+			"--host",
+			"pooler-example-rw",
+			"--username",
+			"app",
+			"--dbname",
+			"app",
+			"-c",
+			"select 1",
+		}))
+	})
 })
 
 func fakePod(name, role string) corev1.Pod {
@@ -17,20 +17,18 @@ limitations under the License.
 package reload
 
 import (
-	"context"
-
 	"github.com/spf13/cobra"
 )
 
-// NewCmd creates the new "reset" command
+// NewCmd creates the new "reload" command
 func NewCmd() *cobra.Command {
 	restartCmd := &cobra.Command{
-		Use:   "reload [clusterName]",
+		Use:   "reload clusterName",
 		Short: `Reload the cluster`,
 		Long:  `Triggers a reconciliation loop for all the cluster's instances, rolling out new configurations if present.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			ctx := cmd.Context()
 			clusterName := args[0]
 			return Reload(ctx, clusterName)
 		},
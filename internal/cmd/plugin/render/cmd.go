@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "render" subcommand
+func NewCmd() *cobra.Command {
+	renderCmd := &cobra.Command{
+		Use:   "render [manifest]",
+		Short: "Print the objects the operator would create for a cluster",
+		Long: "Print the Kubernetes objects the operator would create for the Clusters contained in a " +
+			"manifest, without applying them. The passwords and the certificates, which are generated " +
+			"by the operator, are not included in the Secrets.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("output")
+
+			switch format {
+			case plugin.OutputFormatYAML, plugin.OutputFormatJSON:
+			default:
+				return fmt.Errorf("unsupported output format: %q", format)
+			}
+
+			return Render(cmd.Context(), args[0], plugin.OutputFormat(format))
+		},
+	}
+
+	renderCmd.Flags().StringP(
+		"output", "o", plugin.OutputFormatYAML, "Output format. One of yaml|json")
+
+	return renderCmd
+}
@@ -0,0 +1,364 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// generatedPasswordPlaceholder replaces, in the rendered Secrets,
+// the passwords that are randomly generated by the operator
+const generatedPasswordPlaceholder = "<generated by the operator>"
+
+// Render prints the objects the operator would create for the
+// Clusters contained in a manifest, without applying them
+func Render(ctx context.Context, manifestFile string, format plugin.OutputFormat) error {
+	clusters, err := readClusters(manifestFile)
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no Cluster found in %s", manifestFile)
+	}
+
+	var objects []client.Object
+	for idx := range clusters {
+		clusterObjects, err := renderCluster(ctx, &clusters[idx])
+		if err != nil {
+			return fmt.Errorf("while rendering the cluster %s: %w", clusters[idx].Name, err)
+		}
+		objects = append(objects, clusterObjects...)
+	}
+
+	return printObjects(objects, format, os.Stdout)
+}
+
+// readClusters reads the Clusters contained in a YAML or JSON manifest,
+// ignoring every other object
+func readClusters(fileName string) ([]apiv1.Cluster, error) {
+	file, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var result []apiv1.Cluster
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var object json.RawMessage
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while reading %s: %w", fileName, err)
+		}
+		if len(object) == 0 || string(object) == "null" {
+			continue
+		}
+
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(object, &typeMeta); err != nil {
+			return nil, err
+		}
+		if typeMeta.Kind != apiv1.ClusterKind {
+			continue
+		}
+
+		var cluster apiv1.Cluster
+		if err := json.Unmarshal(object, &cluster); err != nil {
+			return nil, err
+		}
+		if cluster.Namespace == "" {
+			cluster.Namespace = plugin.Namespace
+		}
+		cluster.SetDefaults()
+		result = append(result, cluster)
+	}
+}
+
+// renderCluster builds the objects the operator would create for a cluster.
+// The first instance is considered to be the primary one
+func renderCluster(ctx context.Context, cluster *apiv1.Cluster) ([]client.Object, error) {
+	cluster.Status.CurrentPrimary = specs.GetInstanceName(cluster.Name, 1)
+	cluster.Status.TargetPrimary = cluster.Status.CurrentPrimary
+
+	var objects []client.Object
+	objects = append(objects, renderSecrets(cluster)...)
+	objects = append(objects, renderServices(cluster)...)
+
+	objects = append(objects, specs.BuildPrimaryPodDisruptionBudget(cluster))
+	if pdb := specs.BuildReplicasPodDisruptionBudget(cluster); pdb != nil {
+		objects = append(objects, pdb)
+	}
+
+	serviceAccount, err := renderServiceAccount(cluster)
+	if err != nil {
+		return nil, err
+	}
+	role := specs.CreateRole(*cluster, nil)
+	cluster.SetInheritedDataAndOwnership(&role.ObjectMeta)
+	roleBinding := specs.CreateRoleBinding(cluster.ObjectMeta)
+	cluster.SetInheritedDataAndOwnership(&roleBinding.ObjectMeta)
+	objects = append(objects, serviceAccount, &role, &roleBinding)
+
+	if cluster.IsPodMonitorEnabled() {
+		objects = append(objects, specs.NewClusterPodMonitorManager(cluster).BuildPodMonitor())
+	}
+
+	instanceObjects, err := renderInstances(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	objects = append(objects, instanceObjects...)
+
+	return objects, setTypeMeta(objects)
+}
+
+// renderSecrets builds the skeletons of the Secrets generated by the
+// operator, which don't contain the certificates and the passwords, and
+// the cert-manager Certificates requested for the issuers of the cluster.
+// cert-manager stores the CA together with the certificate it issues, so
+// no CA Secret is generated for an issuer
+func renderSecrets(cluster *apiv1.Cluster) []client.Object {
+	emptyKeyPair := certs.KeyPair{Private: []byte{}, Certificate: []byte{}}
+	certificates := cluster.Spec.Certificates
+	if certificates == nil {
+		certificates = &apiv1.CertificatesConfiguration{}
+	}
+
+	var result []*corev1.Secret
+	if certificates.ServerCASecret == "" && certificates.ServerIssuerRef == nil {
+		result = append(result, emptyKeyPair.GenerateCASecret(cluster.Namespace, cluster.GetServerCASecretName()))
+	}
+	if certificates.ServerTLSSecret == "" {
+		result = append(result,
+			emptyKeyPair.GenerateCertificateSecret(cluster.Namespace, cluster.GetServerTLSSecretName()))
+	}
+	// The server and the client CA share the same Secret by default
+	if certificates.ClientCASecret == "" && certificates.ReplicationIssuerRef == nil &&
+		cluster.GetClientCASecretName() != cluster.GetServerCASecretName() {
+		result = append(result, emptyKeyPair.GenerateCASecret(cluster.Namespace, cluster.GetClientCASecretName()))
+	}
+	if certificates.ReplicationTLSSecret == "" {
+		result = append(result,
+			emptyKeyPair.GenerateCertificateSecret(cluster.Namespace, cluster.GetReplicationSecretName()))
+	}
+
+	for idx := range result {
+		cluster.SetInheritedDataAndOwnership(&result[idx].ObjectMeta)
+	}
+
+	if cluster.GetEnableSuperuserAccess() &&
+		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "") {
+		result = append(result, specs.CreateSuperuserSecret(cluster, generatedPasswordPlaceholder))
+	}
+	if cluster.ShouldCreateApplicationSecret() {
+		result = append(result, specs.CreateApplicationSecret(cluster, generatedPasswordPlaceholder))
+	}
+
+	objects := make([]client.Object, 0, len(result)+2)
+	for idx := range result {
+		objects = append(objects, result[idx])
+	}
+	if certificate := specs.BuildServerCertManagerCertificate(cluster); certificate != nil {
+		objects = append(objects, certificate)
+	}
+	if certificate := specs.BuildReplicationCertManagerCertificate(cluster); certificate != nil {
+		objects = append(objects, certificate)
+	}
+	return objects
+}
+
+// renderServices builds the Services of the cluster
+func renderServices(cluster *apiv1.Cluster) []client.Object {
+	var instanceNames []string
+	if cluster.AreInstanceServicesEnabled() {
+		for serial := 1; serial <= cluster.Spec.Instances; serial++ {
+			instanceNames = append(instanceNames, specs.GetInstanceName(cluster.Name, serial))
+		}
+	}
+
+	services := specs.CreateClusterServices(cluster, configuration.Current.CreateAnyService, false, instanceNames)
+	objects := make([]client.Object, len(services))
+	for idx := range services {
+		utils.SetSpecHash(&services[idx].ObjectMeta, services[idx].Spec)
+		objects[idx] = services[idx]
+	}
+	return objects
+}
+
+// renderServiceAccount builds the ServiceAccount used by the instances.
+// The pull secret the operator may copy from its own namespace is not included
+func renderServiceAccount(cluster *apiv1.Cluster) (*corev1.ServiceAccount, error) {
+	pullSecretNames := make([]string, 0, len(cluster.Spec.ImagePullSecrets))
+	for _, secretReference := range cluster.Spec.ImagePullSecrets {
+		pullSecretNames = append(pullSecretNames, secretReference.Name)
+	}
+
+	serviceAccount, err := specs.CreateServiceAccount(cluster, pullSecretNames)
+	if err != nil {
+		return nil, fmt.Errorf("while creating new ServiceAccount: %w", err)
+	}
+
+	return serviceAccount, nil
+}
+
+// renderInstances builds the Jobs creating the instances of the cluster,
+// together with their PVCs and Pods
+func renderInstances(ctx context.Context, cluster *apiv1.Cluster) ([]client.Object, error) {
+	var objects []client.Object
+	for serial := 1; serial <= cluster.Spec.Instances; serial++ {
+		var job *batchv1.Job
+		var source *persistentvolumeclaim.StorageSource
+		var err error
+		if serial == 1 {
+			source = persistentvolumeclaim.GetCandidateStorageSourceForPrimary(cluster, nil)
+			job, err = renderPrimaryJob(cluster, serial, source)
+		} else {
+			source = persistentvolumeclaim.GetCandidateStorageSourceForReplica(ctx, cluster, apiv1.BackupList{})
+			job, err = renderReplicaJob(cluster, serial, source)
+		}
+		if err != nil {
+			return nil, err
+		}
+		cluster.SetInheritedDataAndOwnership(&job.ObjectMeta)
+		utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
+			cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
+			cluster.GetFixedInheritedLabels(), configuration.Current)
+		utils.SetSpecHash(&job.ObjectMeta, job.Spec)
+		objects = append(objects, job)
+
+		pvcs, err := persistentvolumeclaim.BuildInstancePVCs(cluster, source, serial)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range pvcs {
+			objects = append(objects, pvcs[idx])
+		}
+
+		pod := specs.PodWithExistingStorage(*cluster, serial)
+		cluster.SetInheritedDataAndOwnership(&pod.ObjectMeta)
+		objects = append(objects, pod)
+	}
+
+	return objects, nil
+}
+
+// renderPrimaryJob builds the Job bootstrapping the primary instance.
+// The Backup and the VolumeSnapshot objects used for the recovery are
+// not read from Kubernetes, so what the Job gets from them is missing
+func renderPrimaryJob(
+	cluster *apiv1.Cluster,
+	serial int,
+	source *persistentvolumeclaim.StorageSource,
+) (*batchv1.Job, error) {
+	switch {
+	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil:
+		if source != nil {
+			var snapshot storagesnapshotv1.VolumeSnapshot
+			snapshot.Name = source.DataSource.Name
+			snapshot.Namespace = cluster.Namespace
+			return specs.CreatePrimaryJobViaRestoreSnapshot(*cluster, serial, snapshot, nil)
+		}
+		return specs.CreatePrimaryJobViaRecovery(*cluster, serial, nil)
+	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil:
+		return specs.CreatePrimaryJobViaPgBaseBackup(*cluster, serial)
+	default:
+		return specs.CreatePrimaryJobViaInitdb(*cluster, serial)
+	}
+}
+
+// renderReplicaJob builds the Job creating a replica instance
+func renderReplicaJob(
+	cluster *apiv1.Cluster,
+	serial int,
+	source *persistentvolumeclaim.StorageSource,
+) (*batchv1.Job, error) {
+	if source != nil {
+		return specs.RestoreReplicaInstance(*cluster, serial)
+	}
+	return specs.JoinReplicaInstance(*cluster, serial)
+}
+
+// setTypeMeta sets the kind and the API version of the objects, as they
+// are not set by the functions building them
+func setTypeMeta(objects []client.Object) error {
+	knownScheme := scheme.BuildWithAllKnownScheme()
+	for _, object := range objects {
+		gvk, err := apiutil.GVKForObject(object, knownScheme)
+		if err != nil {
+			return err
+		}
+		object.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	return nil
+}
+
+// printObjects prints the objects as a multi-document YAML
+// or, when JSON is requested, as a List
+func printObjects(objects []client.Object, format plugin.OutputFormat, writer io.Writer) error {
+	if format == plugin.OutputFormatJSON {
+		list := corev1.List{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+			Items:    make([]runtime.RawExtension, len(objects)),
+		}
+		for idx := range objects {
+			list.Items[idx].Object = objects[idx]
+		}
+		return plugin.Print(list, format, writer)
+	}
+
+	for _, object := range objects {
+		if _, err := io.WriteString(writer, "---\n"); err != nil {
+			return err
+		}
+		if err := plugin.Print(object, format, writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
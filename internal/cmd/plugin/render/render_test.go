@@ -0,0 +1,246 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	const manifest = `
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: cluster-example
+  namespace: default
+spec:
+  instances: 3
+  storage:
+    size: 1Gi
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: unrelated
+`
+
+	writeManifest := func(content string) string {
+		fileName := filepath.Join(GinkgoT().TempDir(), "manifest.yaml")
+		Expect(os.WriteFile(fileName, []byte(content), 0o600)).To(Succeed())
+		return fileName
+	}
+
+	renderManifest := func(content string) []client.Object {
+		clusters, err := readClusters(writeManifest(content))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusters).To(HaveLen(1))
+
+		objects, err := renderCluster(context.Background(), &clusters[0])
+		Expect(err).ToNot(HaveOccurred())
+		return objects
+	}
+
+	getNames := func(objects []client.Object, kind string) []string {
+		var result []string
+		for _, object := range objects {
+			if object.GetObjectKind().GroupVersionKind().Kind == kind {
+				result = append(result, object.GetName())
+			}
+		}
+		return result
+	}
+
+	It("only reads the clusters contained in the manifest", func() {
+		clusters, err := readClusters(writeManifest(manifest))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusters).To(HaveLen(1))
+		Expect(clusters[0].Name).To(Equal("cluster-example"))
+		Expect(clusters[0].Spec.ImageName).ToNot(BeEmpty())
+	})
+
+	It("renders the objects created for every instance", func() {
+		objects := renderManifest(manifest)
+
+		Expect(getNames(objects, "Job")).To(Equal([]string{
+			"cluster-example-1-initdb", "cluster-example-2-join", "cluster-example-3-join",
+		}))
+		Expect(getNames(objects, "PersistentVolumeClaim")).To(Equal([]string{
+			"cluster-example-1", "cluster-example-2", "cluster-example-3",
+		}))
+		Expect(getNames(objects, "Pod")).To(Equal([]string{
+			"cluster-example-1", "cluster-example-2", "cluster-example-3",
+		}))
+		Expect(getNames(objects, "Service")).To(ContainElements(
+			"cluster-example-r", "cluster-example-ro", "cluster-example-rw"))
+		Expect(getNames(objects, "PodDisruptionBudget")).To(ConsistOf(
+			"cluster-example", "cluster-example-primary"))
+		Expect(getNames(objects, "ServiceAccount")).To(Equal([]string{"cluster-example"}))
+		Expect(getNames(objects, "Role")).To(Equal([]string{"cluster-example"}))
+		Expect(getNames(objects, "RoleBinding")).To(Equal([]string{"cluster-example"}))
+		Expect(getNames(objects, "PodMonitor")).To(BeEmpty())
+
+		for _, object := range objects {
+			Expect(object.GetNamespace()).To(Equal("default"))
+			Expect(object.GetLabels()).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+			Expect(object.GetAnnotations()).To(HaveKey(utils.OperatorVersionAnnotationName))
+
+			switch object.(type) {
+			case *corev1.Secret, *corev1.ServiceAccount:
+			default:
+				Expect(object.GetAnnotations()).To(HaveKey(utils.CNPGHashAnnotationName))
+			}
+		}
+	})
+
+	It("renders the skeletons of the secrets without the generated data", func() {
+		objects := renderManifest(manifest)
+
+		Expect(getNames(objects, "Secret")).To(Equal([]string{
+			"cluster-example-ca", "cluster-example-server", "cluster-example-replication",
+			"cluster-example-superuser", "cluster-example-app",
+		}))
+		for _, object := range objects {
+			secret, ok := object.(*corev1.Secret)
+			if !ok {
+				continue
+			}
+			for _, value := range secret.Data {
+				Expect(value).To(BeEmpty())
+			}
+			if secret.StringData != nil {
+				Expect(secret.StringData["password"]).To(Equal(generatedPasswordPlaceholder))
+			}
+		}
+	})
+
+	It("renders the cert-manager certificates instead of the self-signed ones", func() {
+		objects := renderManifest(`
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: cluster-example
+  namespace: default
+spec:
+  instances: 1
+  storage:
+    size: 1Gi
+  certificates:
+    serverTLSSecret: cluster-example-server-tls
+    serverIssuerRef:
+      name: server-issuer
+    replicationTLSSecret: cluster-example-replication-tls
+    replicationIssuerRef:
+      name: replication-issuer
+      kind: ClusterIssuer
+`)
+
+		Expect(getNames(objects, "Secret")).To(Equal([]string{
+			"cluster-example-superuser", "cluster-example-app",
+		}))
+		Expect(getNames(objects, "Certificate")).To(Equal([]string{
+			"cluster-example-server-tls", "cluster-example-replication-tls",
+		}))
+
+		var replicationCertificate *unstructured.Unstructured
+		for _, object := range objects {
+			if object.GetName() == "cluster-example-replication-tls" {
+				replicationCertificate = object.(*unstructured.Unstructured)
+			}
+		}
+		Expect(replicationCertificate).ToNot(BeNil())
+		Expect(replicationCertificate.GetAPIVersion()).To(Equal("cert-manager.io/v1"))
+		issuerKind, _, _ := unstructured.NestedString(replicationCertificate.Object, "spec", "issuerRef", "kind")
+		Expect(issuerKind).To(Equal("ClusterIssuer"))
+		commonName, _, _ := unstructured.NestedString(replicationCertificate.Object, "spec", "commonName")
+		Expect(commonName).To(Equal("streaming_replica"))
+	})
+
+	It("renders the job of the bootstrap method", func() {
+		objects := renderManifest(`
+apiVersion: postgresql.cnpg.io/v1
+kind: Cluster
+metadata:
+  name: cluster-example
+spec:
+  instances: 1
+  storage:
+    size: 1Gi
+  bootstrap:
+    pg_basebackup:
+      source: origin
+  externalClusters:
+  - name: origin
+    connectionParameters:
+      host: origin-rw
+  monitoring:
+    enablePodMonitor: true
+`)
+
+		var jobs []*batchv1.Job
+		for _, object := range objects {
+			if job, ok := object.(*batchv1.Job); ok {
+				jobs = append(jobs, job)
+			}
+		}
+		Expect(jobs).To(HaveLen(1))
+		Expect(jobs[0].Name).To(Equal("cluster-example-1-pgbasebackup"))
+		Expect(getNames(objects, "PodDisruptionBudget")).To(Equal([]string{"cluster-example-primary"}))
+		Expect(getNames(objects, "PodMonitor")).To(Equal([]string{"cluster-example"}))
+	})
+
+	It("prints the objects as YAML documents or as a JSON list", func() {
+		objects := renderManifest(manifest)
+
+		var yamlOutput bytes.Buffer
+		Expect(printObjects(objects, plugin.OutputFormatYAML, &yamlOutput)).To(Succeed())
+		Expect(bytes.Count(yamlOutput.Bytes(), []byte("---\n"))).To(Equal(len(objects)))
+		Expect(yamlOutput.String()).To(ContainSubstring("kind: Pod\n"))
+
+		var jsonOutput bytes.Buffer
+		Expect(printObjects(objects, plugin.OutputFormatJSON, &jsonOutput)).To(Succeed())
+		var list struct {
+			Kind  string `json:"kind"`
+			Items []struct {
+				Kind string `json:"kind"`
+			} `json:"items"`
+		}
+		Expect(json.Unmarshal(jsonOutput.Bytes(), &list)).To(Succeed())
+		Expect(list.Kind).To(Equal("List"))
+		Expect(list.Items).To(HaveLen(len(objects)))
+		Expect(list.Items[0].Kind).ToNot(BeEmpty())
+	})
+
+	It("fails when the manifest has no cluster", func() {
+		Expect(Render(context.Background(), writeManifest("kind: Secret\n"), plugin.OutputFormatYAML)).
+			To(MatchError(ContainSubstring("no Cluster found")))
+	})
+})
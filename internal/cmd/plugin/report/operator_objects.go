@@ -23,10 +23,12 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
@@ -152,6 +154,29 @@ func getOperatorSecrets(ctx context.Context, deployment appsv1.Deployment) ([]co
 	return secrets, nil
 }
 
+// getOperatorCRDs returns the CustomResourceDefinitions of the
+// resources managed by the operator
+func getOperatorCRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	var crdList apiextensionsv1.CustomResourceDefinitionList
+	if err := plugin.Client.List(ctx, &crdList); err != nil {
+		return nil, err
+	}
+
+	return filterOperatorCRDs(crdList.Items), nil
+}
+
+// filterOperatorCRDs returns the CustomResourceDefinitions belonging
+// to the API group of the operator
+func filterOperatorCRDs(crds []apiextensionsv1.CustomResourceDefinition) []apiextensionsv1.CustomResourceDefinition {
+	operatorCRDs := make([]apiextensionsv1.CustomResourceDefinition, 0, len(crds))
+	for _, crd := range crds {
+		if crd.Spec.Group == apiv1.GroupVersion.Group {
+			operatorCRDs = append(operatorCRDs, crd)
+		}
+	}
+	return operatorCRDs
+}
+
 // getOperatorConfigMaps returns the configmap referenced by the operator
 func getOperatorConfigMaps(ctx context.Context, deployment appsv1.Deployment) ([]corev1.ConfigMap, error) {
 	contextLogger := log.FromContext(ctx)
@@ -0,0 +1,43 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operator CRDs", func() {
+	It("keeps only the CRDs of the operator API group", func() {
+		crds := []apiextensionsv1.CustomResourceDefinition{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusters.postgresql.cnpg.io"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "postgresql.cnpg.io"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "cert-manager.io"},
+			},
+		}
+		filtered := filterOperatorCRDs(crds)
+		Expect(filtered).To(HaveLen(1))
+		Expect(filtered[0].Name).To(Equal("clusters.postgresql.cnpg.io"))
+	})
+})
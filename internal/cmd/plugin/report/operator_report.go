@@ -39,6 +39,7 @@ type operatorReport struct {
 	operatorPods            []corev1.Pod
 	secrets                 []namedObject
 	configs                 []namedObject
+	crds                    []namedObject
 	events                  corev1.EventList
 	webhookService          corev1.Service
 	mutatingWebhookConfig   *v12.MutatingWebhookConfigurationList
@@ -73,7 +74,7 @@ func (or operatorReport) writeToZip(zipper *zip.Writer, format plugin.OutputForm
 		}
 	}
 
-	multiObjects := [][]namedObject{or.configs, or.secrets}
+	multiObjects := [][]namedObject{or.configs, or.secrets, or.crds}
 	for _, obj := range multiObjects {
 		err := addObjectsToZip(obj, newFolder, format, zipper)
 		if err != nil {
@@ -89,6 +90,7 @@ func (or operatorReport) writeToZip(zipper *zip.Writer, format plugin.OutputForm
 //   - operator pod definition
 //   - operator configuration Configmap and Secret key (if any)
 //   - events in the operator namespace
+//   - CustomResourceDefinitions of the operator
 //   - operator's Validating/MutatingWebhookConfiguration and their associated services
 //   - operator pod's logs (if `includeLogs` is true)
 func operator(ctx context.Context, format plugin.OutputFormat,
@@ -135,6 +137,17 @@ func operator(ctx context.Context, format plugin.OutputFormat,
 		configs = append(configs, namedObject{Name: cm.Name, Object: configMapRedactor(cm)})
 	}
 
+	// Listing the CRDs requires cluster-wide permissions, which the
+	// user may lack: the rest of the report is still useful without them
+	operatorCRDs, err := getOperatorCRDs(ctx)
+	if err != nil {
+		fmt.Printf("WARNING: could not get operator CRDs, they won't be included in the report: %v\n", err)
+	}
+	crds := make([]namedObject, 0, len(operatorCRDs))
+	for _, crd := range operatorCRDs {
+		crds = append(crds, namedObject{Name: crd.Name + "(crd)", Object: crd})
+	}
+
 	var events corev1.EventList
 	err = plugin.Client.List(ctx, &events, client.InNamespace(operatorPods[0].Namespace))
 	if err != nil {
@@ -156,6 +169,7 @@ func operator(ctx context.Context, format plugin.OutputFormat,
 		operatorPods:            operatorPods,
 		secrets:                 secrets,
 		configs:                 configs,
+		crds:                    crds,
 		events:                  events,
 		mutatingWebhookConfig:   mutatingWebhook,
 		validatingWebhookConfig: validatingWebhook,
@@ -23,7 +23,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// NewCmd creates the new "reset" command
+// NewCmd creates the new "restart" command
 func NewCmd() *cobra.Command {
 	restartCmd := &cobra.Command{
 		Use:   "restart clusterName [instance]",
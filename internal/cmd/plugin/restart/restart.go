@@ -80,6 +80,9 @@ func instanceRestart(ctx context.Context, clusterName, node string) error {
 		if err != nil {
 			return fmt.Errorf("while getting POD %v: %w", node, err)
 		}
+		if err := checkInstanceBelongsToCluster(&cluster, &pod); err != nil {
+			return err
+		}
 		if err := plugin.Client.Delete(ctx, &pod); err != nil {
 			return fmt.Errorf("while deleting POD %v: %w", node, err)
 		}
@@ -87,3 +90,12 @@ func instanceRestart(ctx context.Context, clusterName, node string) error {
 	fmt.Printf("instance %s restarted\n", node)
 	return nil
 }
+
+// checkInstanceBelongsToCluster ensures we are not going to delete a Pod
+// which is not an instance of the passed cluster
+func checkInstanceBelongsToCluster(cluster *apiv1.Cluster, pod *corev1.Pod) error {
+	if pod.Labels[utils.ClusterLabelName] != cluster.Name {
+		return fmt.Errorf("POD %v is not an instance of cluster %v", pod.Name, cluster.Name)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restart
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance restart", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+	}
+
+	podWithCluster := func(clusterName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-example-2",
+				Labels: map[string]string{utils.ClusterLabelName: clusterName},
+			},
+		}
+	}
+
+	It("accepts an instance of the cluster", func() {
+		Expect(checkInstanceBelongsToCluster(cluster, podWithCluster("cluster-example"))).To(Succeed())
+	})
+
+	It("refuses to restart an instance of another cluster", func() {
+		Expect(checkInstanceBelongsToCluster(cluster, podWithCluster("another-cluster"))).
+			To(MatchError(ContainSubstring("is not an instance")))
+	})
+
+	It("refuses to restart a pod not managed by the operator", func() {
+		Expect(checkInstanceBelongsToCluster(cluster, &corev1.Pod{})).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,42 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorepoint
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "restore-point" command
+func NewCmd() *cobra.Command {
+	restorePointCmd := &cobra.Command{
+		Use:   "restore-point [clusterName] [restorePointName]",
+		Short: `Create a named restore point`,
+		Long: `Creates a named restore point on the primary instance of the cluster, ` +
+			`which can be used as the "targetName" recovery target.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			restorePointName := args[1]
+			return Create(ctx, clusterName, restorePointName)
+		},
+	}
+
+	return restorePointCmd
+}
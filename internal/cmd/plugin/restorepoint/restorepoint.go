@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package restorepoint implements a command to create a named restore point
+package restorepoint
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Create requests the creation of a named restore point on the primary
+// instance of the cluster
+func Create(ctx context.Context, clusterName string, restorePointName string) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if restorePoint := cluster.GetRestorePoint(restorePointName); restorePoint != nil {
+		return fmt.Errorf("restore point %s already created at LSN %s", restorePointName, restorePoint.LSN)
+	}
+
+	clusterRestorePoint := cluster.DeepCopy()
+	if clusterRestorePoint.Annotations == nil {
+		clusterRestorePoint.Annotations = make(map[string]string)
+	}
+	clusterRestorePoint.Annotations[utils.RestorePointAnnotationName] = restorePointName
+	clusterRestorePoint.ManagedFields = nil
+
+	err = plugin.Client.Patch(ctx, clusterRestorePoint, client.MergeFrom(&cluster))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restore point %s will be created in cluster %s\n", restorePointName, clusterName)
+	return nil
+}
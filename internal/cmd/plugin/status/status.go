@@ -110,6 +110,7 @@ func Status(ctx context.Context, clusterName string, verbose bool, format plugin
 			nonFatalError = err
 		}
 	}
+	status.printConfigurationDrift()
 	status.printCertificatesStatus()
 	status.printBackupStatus()
 	status.printBasebackupStatus()
@@ -392,6 +393,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatusTableHeader(table *tabby.T
 			"Write Lag",
 			"Flush Lag",
 			"Replay Lag",
+			"Replay Lag Bytes",
 			"State",
 			"Sync State",
 			"Sync Priority",
@@ -410,6 +412,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatusTableHeader(table *tabby.T
 			"Write Lag",
 			"Flush Lag",
 			"Replay Lag",
+			"Replay Lag Bytes",
 			"State",
 			"Sync State",
 			"Sync Priority",
@@ -425,6 +428,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatusTableHeader(table *tabby.T
 			"Write Lag",
 			"Flush Lag",
 			"Replay Lag",
+			"Replay Lag Bytes",
 			"State",
 			"Sync State",
 			"Sync Priority",
@@ -523,6 +527,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 			replication.WriteLag,
 			replication.FlushLag,
 			replication.ReplayLag,
+			getReplicationLagBytes(primaryInstanceStatus.CurrentLsn, replication.ReplayLsn),
 			replication.State,
 			replication.SyncState,
 			replication.SyncPriority,
@@ -534,6 +539,23 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 	fmt.Println()
 }
 
+// getReplicationLagBytes returns the amount of WAL, in bytes, that a standby
+// still needs to replay to reach the current write position of the primary
+func getReplicationLagBytes(currentLsn, replayLsn postgres.LSN) string {
+	current, err := currentLsn.Parse()
+	if err != nil {
+		return "-"
+	}
+	replayed, err := replayLsn.Parse()
+	if err != nil {
+		return "-"
+	}
+	if replayed > current {
+		return "0"
+	}
+	return strconv.FormatInt(current-replayed, 10)
+}
+
 func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 	//  Column "Replication role"
 	//  If fenced, print "Fenced"
@@ -556,6 +578,7 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 		"Name",
 		"Database Size",
 		"Current LSN", // For standby use "Replay LSN"
+		"Timeline",
 		"Replication role",
 		"Status",
 		"QoS",
@@ -570,6 +593,7 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 				"-",
 				"-",
 				"-",
+				"-",
 				instance.Error.Error(),
 				instance.Pod.Status.QOSClass,
 				"-",
@@ -587,6 +611,7 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 			instance.Pod.Name,
 			instance.TotalInstanceSize,
 			getCurrentLSN(instance),
+			instance.TimeLineID,
 			replicaRole,
 			statusMsg,
 			instance.Pod.Status.QOSClass,
@@ -955,6 +980,27 @@ func (fullStatus *PostgresqlStatus) printTablespacesStatus() {
 	fmt.Println()
 }
 
+func (fullStatus *PostgresqlStatus) printConfigurationDrift() {
+	const header = "Configuration drift"
+
+	configurationDrift := fullStatus.Cluster.Status.ConfigurationDrift
+	if len(configurationDrift) == 0 {
+		return
+	}
+
+	fmt.Println(aurora.Yellow(header))
+	fmt.Printf("Parameters changed with ALTER SYSTEM (policy: %s)\n",
+		fullStatus.Cluster.GetConfigurationDriftPolicy())
+
+	driftStatus := tabby.New()
+	driftStatus.AddHeader("Parameter", "Instance", "Live value", "Desired value")
+	for _, drift := range configurationDrift {
+		driftStatus.AddLine(drift.Name, drift.Instance, drift.Value, drift.DesiredValue)
+	}
+	driftStatus.Print()
+	fmt.Println()
+}
+
 func getPrimaryStartTime(cluster *apiv1.Cluster) string {
 	if len(cluster.Status.CurrentPrimaryTimestamp) == 0 {
 		return ""
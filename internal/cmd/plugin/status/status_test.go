@@ -76,3 +76,22 @@ var _ = Describe("getPrimaryStartTime", func() {
 		})
 	})
 })
+
+var _ = Describe("getReplicationLagBytes", func() {
+	It("should return the distance between the current LSN and the replay LSN", func() {
+		Expect(getReplicationLagBytes("3AF/EB0524F0", "3AF/EAFEDE50")).To(Equal("411296"))
+	})
+
+	It("should return zero when the standby is up to date", func() {
+		Expect(getReplicationLagBytes("3AF/EB0524F0", "3AF/EB0524F0")).To(Equal("0"))
+	})
+
+	It("should never return a negative lag", func() {
+		Expect(getReplicationLagBytes("3AF/EAFEDE50", "3AF/EB0524F0")).To(Equal("0"))
+	})
+
+	It("should return a placeholder when an LSN is not available", func() {
+		Expect(getReplicationLagBytes("", "3AF/EB0524F0")).To(Equal("-"))
+		Expect(getReplicationLagBytes("3AF/EB0524F0", "")).To(Equal("-"))
+	})
+})
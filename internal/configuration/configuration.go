@@ -32,6 +32,10 @@ var configurationLog = log.WithName("configuration")
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+// DefaultAdmissionHookTimeout is the default number of seconds the operator
+// waits for the admission hook to reply
+const DefaultAdmissionHookTimeout = 5
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -88,6 +92,39 @@ type Data struct {
 	// CreateAnyService is true when the user wants the operator to create
 	// the <cluster-name>-any service. Defaults to false.
 	CreateAnyService bool `json:"createAnyService" env:"CREATE_ANY_SERVICE"`
+
+	// MaxConcurrentRollouts is the maximum number of clusters that can
+	// perform a rolling update at the same time. Zero means no limit.
+	MaxConcurrentRollouts int `json:"maxConcurrentRollouts" env:"MAX_CONCURRENT_ROLLOUTS"`
+
+	// AdmissionHookURL is the URL of an external validating webhook that
+	// the operator calls, after its own validation, every time a Cluster
+	// is created or updated. Empty means no admission hook.
+	AdmissionHookURL string `json:"admissionHookURL" env:"ADMISSION_HOOK_URL"`
+
+	// AdmissionHookCAFile is the path of a PEM encoded CA bundle used to
+	// verify the certificate of the admission hook. When empty, the
+	// system certificate pool is used
+	AdmissionHookCAFile string `json:"admissionHookCAFile" env:"ADMISSION_HOOK_CA_FILE"`
+
+	// AdmissionHookTimeout is the number of seconds the operator waits
+	// for the admission hook to reply
+	AdmissionHookTimeout int `json:"admissionHookTimeout" env:"ADMISSION_HOOK_TIMEOUT"`
+
+	// AdmissionHookFailurePolicy defines how an error invoking the admission
+	// hook is handled. It can be "Fail" (the default), rejecting the
+	// request, or "Ignore", allowing it
+	AdmissionHookFailurePolicy string `json:"admissionHookFailurePolicy" env:"ADMISSION_HOOK_FAILURE_POLICY"`
+
+	// EnableUpdateRecommendations enables the operator to compare the
+	// PostgreSQL version of every cluster with the images it knows about,
+	// reporting the availability of a newer minor version in the status
+	EnableUpdateRecommendations bool `json:"enableUpdateRecommendations" env:"ENABLE_UPDATE_RECOMMENDATIONS"`
+
+	// PostgresImageCatalog is a list of PostgreSQL images, usually one for
+	// each supported major version, that are known to run the latest
+	// minor versions. The default PostgreSQL image is always included
+	PostgresImageCatalog []string `json:"postgresImageCatalog" env:"POSTGRES_IMAGE_CATALOG"`
 }
 
 // Current is the configuration used by the operator
@@ -100,6 +137,7 @@ func newDefaultConfig() *Data {
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
 		CreateAnyService:       false,
+		AdmissionHookTimeout:   DefaultAdmissionHookTimeout,
 	}
 }
 
@@ -128,6 +166,14 @@ func (config *Data) IsLabelInherited(name string) bool {
 	return evaluateGlobPatterns(config.InheritedLabels, name)
 }
 
+// GetPostgresImageCatalog gets the images used to detect the availability
+// of newer PostgreSQL minor versions, including the default image
+func (config *Data) GetPostgresImageCatalog() []string {
+	catalog := make([]string, 0, len(config.PostgresImageCatalog)+1)
+	catalog = append(catalog, config.PostgresImageCatalog...)
+	return append(catalog, config.PostgresImageName)
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
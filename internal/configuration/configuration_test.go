@@ -128,3 +128,16 @@ var _ = Describe("Annotation and label inheritance", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL image catalog", func() {
+	It("always contains the default image", func() {
+		config := Data{PostgresImageName: "ghcr.io/cloudnative-pg/postgresql:16.2"}
+		Expect(config.GetPostgresImageCatalog()).To(Equal([]string{"ghcr.io/cloudnative-pg/postgresql:16.2"}))
+
+		config.PostgresImageCatalog = []string{"ghcr.io/cloudnative-pg/postgresql:15.6"}
+		Expect(config.GetPostgresImageCatalog()).To(Equal([]string{
+			"ghcr.io/cloudnative-pg/postgresql:15.6",
+			"ghcr.io/cloudnative-pg/postgresql:16.2",
+		}))
+	})
+})
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// LastKnownClusterFile is the file where the last known definition of the
+// cluster is persisted. It is stored in the scratch data directory, which
+// survives the restarts of the instance manager
+var LastKnownClusterFile = path.Join(postgres.ScratchDataDirectory, "cluster.json")
+
+// PersistCluster writes the definition of the cluster into the
+// LastKnownClusterFile, returning true if the file has been changed
+func PersistCluster(cluster *apiv1.Cluster) (bool, error) {
+	contents, err := json.Marshal(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	return fileutils.WriteFileAtomic(LastKnownClusterFile, contents, 0o600)
+}
+
+// LoadPersistedCluster reads the last known definition of the cluster
+// from the LastKnownClusterFile, returning ErrCacheMiss when it has never
+// been persisted
+func LoadPersistedCluster() (*apiv1.Cluster, error) {
+	exists, err := fileutils.FileExists(LastKnownClusterFile)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCacheMiss
+	}
+
+	contents, err := fileutils.ReadFile(LastKnownClusterFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster apiv1.Cluster
+	if err := json.Unmarshal(contents, &cluster); err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Persisted cluster", func() {
+	BeforeEach(func() {
+		previousFile := LastKnownClusterFile
+		LastKnownClusterFile = filepath.Join(GinkgoT().TempDir(), "cluster.json")
+		DeferCleanup(func() {
+			LastKnownClusterFile = previousFile
+		})
+	})
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cluster-example",
+			Namespace:       "default",
+			ResourceVersion: "42",
+		},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+		},
+	}
+
+	It("reports a cache miss when the cluster has never been persisted", func() {
+		_, err := LoadPersistedCluster()
+		Expect(err).To(MatchError(ErrCacheMiss))
+	})
+
+	It("loads the persisted cluster", func() {
+		changed, err := PersistCluster(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		persistedCluster, err := LoadPersistedCluster()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(persistedCluster.Name).To(Equal("cluster-example"))
+		Expect(persistedCluster.ResourceVersion).To(Equal("42"))
+		Expect(persistedCluster.Status.CurrentPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("doesn't rewrite an unchanged cluster", func() {
+		_, err := PersistCluster(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		changed, err := PersistCluster(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("fails when the persisted cluster is corrupted", func() {
+		Expect(os.WriteFile(LastKnownClusterFile, []byte("{"), 0o600)).To(Succeed())
+
+		_, err := LoadPersistedCluster()
+		Expect(err).To(HaveOccurred())
+	})
+})
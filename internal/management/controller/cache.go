@@ -36,6 +36,12 @@ import (
 func (r *InstanceReconciler) updateCacheFromCluster(ctx context.Context, cluster *apiv1.Cluster) shoudRequeue {
 	cache.StoreCluster(cluster)
 
+	// Keep a copy of the cluster on disk, to be used when the instance
+	// manager is restarted while the API server is not reachable
+	if _, err := cache.PersistCluster(cluster); err != nil {
+		log.Error(err, "while persisting the last known cluster definition")
+	}
+
 	var missingPermissions shoudRequeue
 
 	// Populate the cache with the backup configuration
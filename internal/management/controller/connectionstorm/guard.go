@@ -0,0 +1,185 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionstorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// checkInterval is the interval between two checks of the number
+// of client connections
+const checkInterval = 5 * time.Second
+
+// hbaReloader is the entity able to regenerate the pg_hba.conf file
+// and to reload the configuration of the instance
+type hbaReloader interface {
+	ReloadPGHBA(ctx context.Context) error
+}
+
+// A Guard is a runner that detects connection storms in the local instance,
+// that is a number of client connections exceeding the configured threshold
+// for a sustained amount of time
+type Guard struct {
+	instance *postgres.Instance
+	reloader hbaReloader
+
+	// aboveThresholdSince is the time when the number of client connections
+	// has been detected above the threshold. It's zero when the number of
+	// connections is below the threshold
+	aboveThresholdSince time.Time
+}
+
+// NewGuard creates a new connection storm Guard
+func NewGuard(instance *postgres.Instance, reloader hbaReloader) *Guard {
+	return &Guard{
+		instance: instance,
+		reloader: reloader,
+	}
+}
+
+// Start starts running the connection storm Guard
+func (g *Guard) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("ConnectionStormGuard")
+	ticker := time.NewTicker(checkInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated connection storm guard loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := g.check(log.IntoContext(ctx, contextLog)); err != nil {
+			contextLog.Warning("checking for connection storms", "err", err)
+		}
+	}
+}
+
+func (g *Guard) check(ctx context.Context) error {
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	config := cluster.Spec.PostgresConfiguration.ConnectionStormProtection
+	if config == nil {
+		g.aboveThresholdSince = time.Time{}
+		return g.setStormInProgress(ctx, config, false, 0)
+	}
+
+	if g.instance.IsFenced() || g.instance.MightBeUnavailable() {
+		return nil
+	}
+
+	db, err := g.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	connections, err := countClientConnections(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	return g.setStormInProgress(ctx, config, g.evaluate(config, connections, time.Now()), connections)
+}
+
+// evaluate checks whether the given number of client connections
+// is part of a connection storm
+func (g *Guard) evaluate(
+	config *apiv1.ConnectionStormProtectionConfiguration,
+	connections int,
+	now time.Time,
+) bool {
+	if connections <= config.Threshold {
+		g.aboveThresholdSince = time.Time{}
+		return false
+	}
+
+	if g.aboveThresholdSince.IsZero() {
+		g.aboveThresholdSince = now
+	}
+
+	return now.Sub(g.aboveThresholdSince) >= config.GetDuration()
+}
+
+// setStormInProgress marks the instance as experiencing a connection storm
+// or not, regenerating the pg_hba.conf file when the state changes
+func (g *Guard) setStormInProgress(
+	ctx context.Context,
+	config *apiv1.ConnectionStormProtectionConfiguration,
+	inProgress bool,
+	connections int,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	if g.instance.IsConnectionStormInProgress() == inProgress {
+		return nil
+	}
+
+	if inProgress {
+		contextLog.Warning("Connection storm detected",
+			"connections", connections,
+			"threshold", config.Threshold,
+			"mitigation", config.GetMitigation())
+	} else {
+		contextLog.Info("Connection storm is over", "connections", connections)
+	}
+
+	g.instance.SetConnectionStormInProgress(inProgress)
+	if err := g.reloader.ReloadPGHBA(ctx); err != nil {
+		// we restore the previous state, so that we can try again
+		// in the next check
+		g.instance.SetConnectionStormInProgress(!inProgress)
+		return err
+	}
+
+	return nil
+}
+
+// countClientConnections counts the client connections to the
+// instance, excluding the ones of the superuser
+func countClientConnections(ctx context.Context, db *sql.DB) (int, error) {
+	var connections int
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT count(*)
+		FROM pg_catalog.pg_stat_activity
+		WHERE backend_type = 'client backend' AND usename IS DISTINCT FROM 'postgres'`)
+	if err := row.Scan(&connections); err != nil {
+		return 0, err
+	}
+
+	return connections, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectionstorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeReloader struct {
+	calls int
+	err   error
+}
+
+func (r *fakeReloader) ReloadPGHBA(_ context.Context) error {
+	r.calls++
+	return r.err
+}
+
+var _ = Describe("Connection storm guard", func() {
+	var (
+		guard    *Guard
+		reloader *fakeReloader
+	)
+	config := &apiv1.ConnectionStormProtectionConfiguration{
+		Threshold: 100,
+		Duration:  &metav1.Duration{Duration: time.Minute},
+	}
+	now := time.Now()
+
+	BeforeEach(func() {
+		reloader = &fakeReloader{}
+		guard = NewGuard(postgres.NewInstance(), reloader)
+	})
+
+	It("detects a storm only when the connections stay above the threshold", func() {
+		Expect(guard.evaluate(config, 101, now)).To(BeFalse())
+		Expect(guard.evaluate(config, 150, now.Add(30*time.Second))).To(BeFalse())
+		Expect(guard.evaluate(config, 150, now.Add(time.Minute))).To(BeTrue())
+	})
+
+	It("restarts counting when the connections go below the threshold", func() {
+		Expect(guard.evaluate(config, 101, now)).To(BeFalse())
+		Expect(guard.evaluate(config, 100, now.Add(30*time.Second))).To(BeFalse())
+		Expect(guard.evaluate(config, 150, now.Add(time.Minute))).To(BeFalse())
+		Expect(guard.evaluate(config, 150, now.Add(2*time.Minute))).To(BeTrue())
+	})
+
+	It("regenerates pg_hba.conf only when the state changes", func(ctx SpecContext) {
+		Expect(guard.setStormInProgress(ctx, config, false, 10)).To(Succeed())
+		Expect(reloader.calls).To(Equal(0))
+
+		Expect(guard.setStormInProgress(ctx, config, true, 150)).To(Succeed())
+		Expect(guard.instance.IsConnectionStormInProgress()).To(BeTrue())
+		Expect(guard.setStormInProgress(ctx, config, true, 150)).To(Succeed())
+		Expect(reloader.calls).To(Equal(1))
+
+		Expect(guard.setStormInProgress(ctx, config, false, 10)).To(Succeed())
+		Expect(guard.instance.IsConnectionStormInProgress()).To(BeFalse())
+		Expect(reloader.calls).To(Equal(2))
+	})
+
+	It("restores the previous state when pg_hba.conf cannot be regenerated", func(ctx SpecContext) {
+		reloader.err = errors.New("failure")
+		Expect(guard.setStormInProgress(ctx, config, true, 150)).ToNot(Succeed())
+		Expect(guard.instance.IsConnectionStormInProgress()).To(BeFalse())
+	})
+
+	It("counts the client connections", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery("SELECT count\\(\\*\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+		Expect(countClientConnections(ctx, db)).To(Equal(42))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
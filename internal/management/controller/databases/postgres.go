@@ -0,0 +1,337 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// extension is an extension as installed in a database
+type extension struct {
+	Name    string
+	Version string
+	Schema  string
+}
+
+// detectDatabase checks if the database exists in PostgreSQL
+func detectDatabase(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	obj *apiv1.Database,
+) (bool, error) {
+	row := superUserDB.QueryRowContext(
+		ctx,
+		`SELECT count(*)
+		FROM pg_catalog.pg_database
+		WHERE datname = $1`,
+		obj.Spec.Name)
+	if row.Err() != nil {
+		return false, fmt.Errorf("while checking if database %q exists: %w", obj.Spec.Name, row.Err())
+	}
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, fmt.Errorf("while checking if database %q exists: %w", obj.Spec.Name, err)
+	}
+
+	return count > 0, nil
+}
+
+// createDatabase creates the database in PostgreSQL
+func createDatabase(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	obj *apiv1.Database,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var query strings.Builder
+	query.WriteString(fmt.Sprintf("CREATE DATABASE %s", pgx.Identifier{obj.Spec.Name}.Sanitize()))
+	query.WriteString(fmt.Sprintf(" OWNER %s", pgx.Identifier{obj.Spec.Owner}.Sanitize()))
+	if len(obj.Spec.Template) > 0 {
+		query.WriteString(fmt.Sprintf(" TEMPLATE %s", pgx.Identifier{obj.Spec.Template}.Sanitize()))
+	}
+	if len(obj.Spec.Encoding) > 0 {
+		query.WriteString(fmt.Sprintf(" ENCODING %s", pq.QuoteLiteral(obj.Spec.Encoding)))
+	}
+	if len(obj.Spec.Locale) > 0 {
+		query.WriteString(fmt.Sprintf(" LC_COLLATE %s LC_CTYPE %s",
+			pq.QuoteLiteral(obj.Spec.Locale), pq.QuoteLiteral(obj.Spec.Locale)))
+	}
+	if obj.Spec.AllowConnections != nil {
+		query.WriteString(fmt.Sprintf(" ALLOW_CONNECTIONS %v", *obj.Spec.AllowConnections))
+	}
+	if obj.Spec.ConnectionLimit != nil {
+		query.WriteString(fmt.Sprintf(" CONNECTION LIMIT %v", *obj.Spec.ConnectionLimit))
+	}
+	if obj.Spec.IsTemplate != nil {
+		query.WriteString(fmt.Sprintf(" IS_TEMPLATE %v", *obj.Spec.IsTemplate))
+	}
+
+	contextLogger.Info("Creating database", "query", query.String())
+	if _, err := superUserDB.ExecContext(ctx, query.String()); err != nil {
+		return fmt.Errorf("while creating database %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// updateDatabase aligns the mutable properties of an existing
+// database with the specification
+func updateDatabase(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	obj *apiv1.Database,
+) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{obj.Spec.Name}.Sanitize()
+
+	queries := []string{
+		fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", identifier, pgx.Identifier{obj.Spec.Owner}.Sanitize()),
+	}
+	if obj.Spec.AllowConnections != nil {
+		queries = append(queries,
+			fmt.Sprintf("ALTER DATABASE %s WITH ALLOW_CONNECTIONS %v", identifier, *obj.Spec.AllowConnections))
+	}
+	if obj.Spec.ConnectionLimit != nil {
+		queries = append(queries,
+			fmt.Sprintf("ALTER DATABASE %s WITH CONNECTION LIMIT %v", identifier, *obj.Spec.ConnectionLimit))
+	}
+	if obj.Spec.IsTemplate != nil {
+		queries = append(queries,
+			fmt.Sprintf("ALTER DATABASE %s WITH IS_TEMPLATE %v", identifier, *obj.Spec.IsTemplate))
+	}
+
+	for _, query := range queries {
+		contextLogger.Debug("Updating database", "query", query)
+		if _, err := superUserDB.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("while updating database %q: %w", obj.Spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateDatabaseTimeouts sets the default timeouts of the sessions
+// connected to the database, resetting the ones which are not defined.
+// Only the timeouts differing from the current settings are altered, and
+// they are not managed at all when the specification doesn't contain them
+func updateDatabaseTimeouts(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	obj *apiv1.Database,
+) error {
+	if obj.Spec.Timeouts == nil {
+		return nil
+	}
+
+	currentTimeouts, err := getDatabaseTimeouts(ctx, superUserDB, obj.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{obj.Spec.Name}.Sanitize()
+	parameters := obj.Spec.Timeouts.ToParameters()
+
+	for _, name := range postgres.SessionTimeoutParameters {
+		value, inSpec := parameters[name]
+		currentValue, isSet := currentTimeouts[name]
+
+		var query string
+		switch {
+		case inSpec && (!isSet || currentValue != value):
+			query = fmt.Sprintf("ALTER DATABASE %s SET %s TO %s", identifier, name, pq.QuoteLiteral(value))
+		case !inSpec && isSet:
+			query = fmt.Sprintf("ALTER DATABASE %s RESET %s", identifier, name)
+		default:
+			continue
+		}
+		contextLogger.Debug("Updating database timeouts", "query", query)
+		if _, err := superUserDB.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("while updating the timeouts of database %q: %w", obj.Spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getDatabaseTimeouts gets the default timeouts of the sessions connected
+// to the database, applying to every role
+func getDatabaseTimeouts(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	name string,
+) (map[string]string, error) {
+	var settings pq.StringArray
+	row := superUserDB.QueryRowContext(
+		ctx,
+		`SELECT setting.setconfig
+		FROM pg_catalog.pg_db_role_setting AS setting
+		JOIN pg_catalog.pg_database AS db ON db.oid = setting.setdatabase
+		WHERE db.datname = $1 AND setting.setrole = 0`,
+		name)
+	err := row.Scan(&settings)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while reading the timeouts of database %q: %w", name, err)
+	}
+
+	return postgres.GetTimeoutSettings(settings), nil
+}
+
+// dropDatabase drops the database from PostgreSQL, if it exists
+func dropDatabase(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	obj *apiv1.Database,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP DATABASE IF EXISTS %s", pgx.Identifier{obj.Spec.Name}.Sanitize())
+	contextLogger.Info("Dropping database", "query", query)
+	if _, err := superUserDB.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping database %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// getExtension gets the extension installed in the database with the
+// passed name, or nil if the extension is not installed
+func getExtension(
+	ctx context.Context,
+	db *sql.DB,
+	name string,
+) (*extension, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT e.extversion, n.nspname
+		FROM pg_catalog.pg_extension e
+		JOIN pg_catalog.pg_namespace n ON e.extnamespace = n.oid
+		WHERE e.extname = $1`,
+		name)
+
+	result := extension{Name: name}
+	err := row.Scan(&result.Version, &result.Schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting extension %q: %w", name, err)
+	}
+
+	return &result, nil
+}
+
+// reconcileExtensions ensures the extensions installed in the database
+// match the passed specification
+func reconcileExtensions(
+	ctx context.Context,
+	db *sql.DB,
+	extensions []apiv1.ExtensionSpec,
+) error {
+	for _, spec := range extensions {
+		var err error
+		if spec.GetEnsure() == apiv1.EnsureAbsent {
+			err = dropExtension(ctx, db, spec)
+		} else {
+			err = ensureExtension(ctx, db, spec)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureExtension creates or updates an extension in the database
+func ensureExtension(
+	ctx context.Context,
+	db *sql.DB,
+	spec apiv1.ExtensionSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{spec.Name}.Sanitize()
+
+	current, err := getExtension(ctx, db, spec.Name)
+	if err != nil {
+		return err
+	}
+
+	var queries []string
+	if current == nil {
+		var query strings.Builder
+		query.WriteString(fmt.Sprintf("CREATE EXTENSION %s", identifier))
+		if len(spec.Schema) > 0 {
+			query.WriteString(fmt.Sprintf(" SCHEMA %s", pgx.Identifier{spec.Schema}.Sanitize()))
+		}
+		if len(spec.Version) > 0 {
+			query.WriteString(fmt.Sprintf(" VERSION %s", pq.QuoteLiteral(spec.Version)))
+		}
+		queries = append(queries, query.String())
+	} else {
+		if len(spec.Version) > 0 && spec.Version != current.Version {
+			queries = append(queries,
+				fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", identifier, pq.QuoteLiteral(spec.Version)))
+		}
+		if len(spec.Schema) > 0 && spec.Schema != current.Schema {
+			queries = append(queries,
+				fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", identifier, pgx.Identifier{spec.Schema}.Sanitize()))
+		}
+	}
+
+	for _, query := range queries {
+		contextLogger.Info("Reconciling extension", "query", query)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("while reconciling extension %q: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dropExtension drops an extension from the database, if it exists
+func dropExtension(
+	ctx context.Context,
+	db *sql.DB,
+	spec apiv1.ExtensionSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP EXTENSION IF EXISTS %s", pgx.Identifier{spec.Name}.Sanitize())
+	contextLogger.Info("Dropping extension", "query", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while dropping extension %q: %w", spec.Name, err)
+	}
+
+	return nil
+}
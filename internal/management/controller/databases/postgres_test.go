@@ -0,0 +1,218 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Postgres databases functions test", func() {
+	var (
+		db       *sql.DB
+		mock     sqlmock.Sqlmock
+		database *apiv1.Database
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		database = &apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				Name:  "app",
+				Owner: "app",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("detects whether the database exists", func(ctx SpecContext) {
+		mock.ExpectQuery(`SELECT count(*)
+		FROM pg_catalog.pg_database
+		WHERE datname = $1`).WithArgs("app").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		exists, err := detectDatabase(ctx, db, database)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("creates a database with the minimal options", func(ctx SpecContext) {
+		mock.ExpectExec(`CREATE DATABASE "app" OWNER "app"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createDatabase(ctx, db, database)).To(Succeed())
+	})
+
+	It("creates a database with all the options", func(ctx SpecContext) {
+		database.Spec.Template = "template0"
+		database.Spec.Encoding = "UTF8"
+		database.Spec.Locale = "C"
+		database.Spec.AllowConnections = ptr.To(true)
+		database.Spec.ConnectionLimit = ptr.To(10)
+		database.Spec.IsTemplate = ptr.To(false)
+
+		mock.ExpectExec(`CREATE DATABASE "app" OWNER "app" TEMPLATE "template0" ENCODING 'UTF8' ` +
+			`LC_COLLATE 'C' LC_CTYPE 'C' ALLOW_CONNECTIONS true CONNECTION LIMIT 10 IS_TEMPLATE false`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(createDatabase(ctx, db, database)).To(Succeed())
+	})
+
+	It("reports the errors while creating a database", func(ctx SpecContext) {
+		mock.ExpectExec(`CREATE DATABASE "app" OWNER "app"`).
+			WillReturnError(fmt.Errorf("boom"))
+
+		err := createDatabase(ctx, db, database)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("boom"))
+	})
+
+	It("updates the mutable options of an existing database", func(ctx SpecContext) {
+		database.Spec.AllowConnections = ptr.To(true)
+		database.Spec.ConnectionLimit = ptr.To(-1)
+		database.Spec.IsTemplate = ptr.To(true)
+
+		mock.ExpectExec(`ALTER DATABASE "app" OWNER TO "app"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ALTER DATABASE "app" WITH ALLOW_CONNECTIONS true`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ALTER DATABASE "app" WITH CONNECTION LIMIT -1`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`ALTER DATABASE "app" WITH IS_TEMPLATE true`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(updateDatabase(ctx, db, database)).To(Succeed())
+	})
+
+	It("doesn't manage the timeouts when they are not specified", func(ctx SpecContext) {
+		Expect(updateDatabaseTimeouts(ctx, db, database)).To(Succeed())
+	})
+
+	Context("with the timeouts specified", func() {
+		const timeoutsQuery = `SELECT setting.setconfig
+		FROM pg_catalog.pg_db_role_setting AS setting
+		JOIN pg_catalog.pg_database AS db ON db.oid = setting.setdatabase
+		WHERE db.datname = $1 AND setting.setrole = 0`
+
+		BeforeEach(func() {
+			database.Spec.Timeouts = &apiv1.SessionTimeouts{
+				IdleInTransactionSessionTimeout: "10min",
+				LockTimeout:                     "5s",
+			}
+		})
+
+		It("sets the specified timeouts", func(ctx SpecContext) {
+			mock.ExpectQuery(timeoutsQuery).WithArgs("app").WillReturnError(sql.ErrNoRows)
+			mock.ExpectExec(`ALTER DATABASE "app" SET idle_in_transaction_session_timeout TO '10min'`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`ALTER DATABASE "app" SET lock_timeout TO '5s'`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updateDatabaseTimeouts(ctx, db, database)).To(Succeed())
+		})
+
+		It("alters only the timeouts differing from the current ones", func(ctx SpecContext) {
+			mock.ExpectQuery(timeoutsQuery).WithArgs("app").
+				WillReturnRows(sqlmock.NewRows([]string{"setconfig"}).
+					AddRow([]byte(`{idle_in_transaction_session_timeout=10min,statement_timeout=30s,lock_timeout=1s}`)))
+			mock.ExpectExec(`ALTER DATABASE "app" RESET statement_timeout`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`ALTER DATABASE "app" SET lock_timeout TO '5s'`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updateDatabaseTimeouts(ctx, db, database)).To(Succeed())
+		})
+
+		It("doesn't alter the database when the timeouts are aligned", func(ctx SpecContext) {
+			mock.ExpectQuery(timeoutsQuery).WithArgs("app").
+				WillReturnRows(sqlmock.NewRows([]string{"setconfig"}).
+					AddRow([]byte(`{idle_in_transaction_session_timeout=10min,lock_timeout=5s,search_path=public}`)))
+
+			Expect(updateDatabaseTimeouts(ctx, db, database)).To(Succeed())
+		})
+	})
+
+	It("drops a database", func(ctx SpecContext) {
+		mock.ExpectExec(`DROP DATABASE IF EXISTS "app"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(dropDatabase(ctx, db, database)).To(Succeed())
+	})
+
+	Context("extensions", func() {
+		const getExtensionQuery = `SELECT e.extversion, n.nspname
+		FROM pg_catalog.pg_extension e
+		JOIN pg_catalog.pg_namespace n ON e.extnamespace = n.oid
+		WHERE e.extname = $1`
+
+		It("creates a missing extension", func(ctx SpecContext) {
+			mock.ExpectQuery(getExtensionQuery).WithArgs("postgis").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+			mock.ExpectExec(`CREATE EXTENSION "postgis" SCHEMA "gis" VERSION '3.4.0'`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(reconcileExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "postgis", Schema: "gis", Version: "3.4.0"},
+			})).To(Succeed())
+		})
+
+		It("updates the version and the schema of an existing extension", func(ctx SpecContext) {
+			mock.ExpectQuery(getExtensionQuery).WithArgs("postgis").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}).AddRow("3.3.0", "public"))
+			mock.ExpectExec(`ALTER EXTENSION "postgis" UPDATE TO '3.4.0'`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`ALTER EXTENSION "postgis" SET SCHEMA "gis"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(reconcileExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "postgis", Schema: "gis", Version: "3.4.0"},
+			})).To(Succeed())
+		})
+
+		It("leaves an existing extension alone when no version or schema is requested", func(ctx SpecContext) {
+			mock.ExpectQuery(getExtensionQuery).WithArgs("pg_stat_statements").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}).AddRow("1.10", "public"))
+
+			Expect(reconcileExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "pg_stat_statements"},
+			})).To(Succeed())
+		})
+
+		It("drops an extension that should be absent", func(ctx SpecContext) {
+			mock.ExpectExec(`DROP EXTENSION IF EXISTS "postgis"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(reconcileExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "postgis", Ensure: apiv1.EnsureAbsent},
+			})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,284 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// databaseReconciliationInterval is the time after which a database
+// that failed to be reconciled is retried, and after which the replicas
+// check if they have been promoted
+const databaseReconciliationInterval = 30 * time.Second
+
+// errClusterIsReplica is raised when the database is referring to a replica cluster,
+// where PostgreSQL is read-only
+var errClusterIsReplica = errors.New("waiting for the cluster to become primary")
+
+// Reconcile is the main reconciliation loop for the declarative databases
+func (r *DatabaseReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("database_reconciler")
+	// if the context has already been cancelled,
+	// trying to reconcile would just lead to misleading errors being reported
+	if err := ctx.Err(); err != nil {
+		contextLogger.Warning("Context cancelled, will not start database reconcile", "err", err)
+		return reconcile.Result{}, nil
+	}
+
+	var database apiv1.Database
+	if err := r.client.Get(ctx, req.NamespacedName, &database); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Database: %w", err)
+	}
+
+	// Databases of other clusters are not our business
+	if database.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return reconcile.Result{}, nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !isPrimary {
+		// The Database objects are not changed when this instance is
+		// promoted, so we need to check again later
+		contextLogger.Debug("skipping the database reconciler in replicas")
+		return reconcile.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted.
+			// We just need to wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	if cluster.IsReplica() {
+		return r.updateStatus(ctx, &database, errClusterIsReplica)
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping database reconciling")
+		return reconcile.Result{RequeueAfter: time.Second}, nil
+	}
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reconciling databases: %w", err)
+	}
+
+	conflictingDatabase, err := r.getConflictingDatabase(ctx, &database)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("while looking for conflicting databases: %w", err)
+	}
+
+	if !database.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.reconcileDeletion(ctx, superUserDB, &database, conflictingDatabase == nil)
+	}
+
+	if conflictingDatabase != nil {
+		return r.updateStatus(ctx, &database, fmt.Errorf(
+			"the database %q is already managed by the Database object %q",
+			database.Spec.Name, conflictingDatabase.Name))
+	}
+
+	if err := r.reconcileFinalizer(ctx, &database); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.updateStatus(ctx, &database, r.reconcileDatabase(ctx, superUserDB, &database))
+}
+
+// getConflictingDatabase returns the Database object managing the same
+// PostgreSQL database of the passed one, if any. When more objects refer
+// to the same database, the oldest one manages it
+func (r *DatabaseReconciler) getConflictingDatabase(
+	ctx context.Context,
+	database *apiv1.Database,
+) (*apiv1.Database, error) {
+	var databases apiv1.DatabaseList
+	if err := r.client.List(ctx, &databases, client.InNamespace(database.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for idx := range databases.Items {
+		item := &databases.Items[idx]
+		if item.Name == database.Name ||
+			item.Spec.ClusterRef.Name != database.Spec.ClusterRef.Name ||
+			item.Spec.Name != database.Spec.Name {
+			continue
+		}
+
+		if isManagedBefore(item, database) {
+			return item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// isManagedBefore checks if the first Database object takes precedence
+// over the second one, being created earlier
+func isManagedBefore(first, second *apiv1.Database) bool {
+	if !first.CreationTimestamp.Equal(&second.CreationTimestamp) {
+		return first.CreationTimestamp.Before(&second.CreationTimestamp)
+	}
+
+	return first.Name < second.Name
+}
+
+// reconcileDeletion drops the database, if required by the reclaim
+// policy and if the Database object is the one managing it, and releases
+// the Database object
+func (r *DatabaseReconciler) reconcileDeletion(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	database *apiv1.Database,
+	isManaging bool,
+) error {
+	if !controllerutil.ContainsFinalizer(database, utils.DatabaseFinalizerName) {
+		return nil
+	}
+
+	if isManaging && database.GetReclaimPolicy() == apiv1.DatabaseReclaimDelete {
+		if err := dropDatabase(ctx, superUserDB, database); err != nil {
+			return err
+		}
+	}
+
+	origDatabase := database.DeepCopy()
+	controllerutil.RemoveFinalizer(database, utils.DatabaseFinalizerName)
+	return r.client.Patch(ctx, database, client.MergeFrom(origDatabase))
+}
+
+// reconcileFinalizer adds the finalizer when the database needs to be
+// dropped on deletion, and removes it otherwise
+func (r *DatabaseReconciler) reconcileFinalizer(ctx context.Context, database *apiv1.Database) error {
+	origDatabase := database.DeepCopy()
+
+	var modified bool
+	if database.GetReclaimPolicy() == apiv1.DatabaseReclaimDelete {
+		modified = controllerutil.AddFinalizer(database, utils.DatabaseFinalizerName)
+	} else {
+		modified = controllerutil.RemoveFinalizer(database, utils.DatabaseFinalizerName)
+	}
+
+	if !modified {
+		return nil
+	}
+
+	return r.client.Patch(ctx, database, client.MergeFrom(origDatabase))
+}
+
+// reconcileDatabase ensures the database and its extensions
+// match the specification
+func (r *DatabaseReconciler) reconcileDatabase(
+	ctx context.Context,
+	superUserDB *sql.DB,
+	database *apiv1.Database,
+) error {
+	if database.GetEnsure() == apiv1.EnsureAbsent {
+		return dropDatabase(ctx, superUserDB, database)
+	}
+
+	exists, err := detectDatabase(ctx, superUserDB, database)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		err = updateDatabase(ctx, superUserDB, database)
+	} else {
+		err = createDatabase(ctx, superUserDB, database)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := updateDatabaseTimeouts(ctx, superUserDB, database); err != nil {
+		return err
+	}
+
+	if len(database.Spec.Extensions) == 0 {
+		return nil
+	}
+
+	db, err := r.instance.ConnectionPool().Connection(database.Spec.Name)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %q: %w", database.Spec.Name, err)
+	}
+
+	return reconcileExtensions(ctx, db, database.Spec.Extensions)
+}
+
+// updateStatus records the result of the reconciliation in the
+// status of the Database object
+func (r *DatabaseReconciler) updateStatus(
+	ctx context.Context,
+	database *apiv1.Database,
+	reconciliationErr error,
+) (reconcile.Result, error) {
+	origDatabase := database.DeepCopy()
+	setDatabaseStatus(database, reconciliationErr)
+
+	if err := r.client.Status().Patch(ctx, database, client.MergeFrom(origDatabase)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while setting the database status: %w", err)
+	}
+
+	if reconciliationErr != nil {
+		return reconcile.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// setDatabaseStatus sets the status of the Database object
+// given the result of the reconciliation
+func setDatabaseStatus(database *apiv1.Database, reconciliationErr error) {
+	database.Status.ObservedGeneration = database.Generation
+	if reconciliationErr != nil {
+		database.Status.Ready = false
+		database.Status.Error = reconciliationErr.Error()
+		return
+	}
+
+	database.Status.Ready = true
+	database.Status.Error = ""
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database reconciler", func() {
+	var (
+		database *apiv1.Database
+		cli      client.Client
+		r        *DatabaseReconciler
+	)
+
+	BeforeEach(func() {
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "db-one",
+				Namespace:  "default",
+				Generation: 2,
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "one",
+				Owner:      "app",
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(database).
+			WithStatusSubresource(database).
+			Build()
+		r = NewDatabaseReconciler(nil, cli)
+	})
+
+	It("adds the finalizer only when the database must be dropped on deletion", func(ctx SpecContext) {
+		Expect(r.reconcileFinalizer(ctx, database)).To(Succeed())
+		Expect(database.Finalizers).To(BeEmpty())
+
+		database.Spec.ReclaimPolicy = apiv1.DatabaseReclaimDelete
+		Expect(r.reconcileFinalizer(ctx, database)).To(Succeed())
+
+		var stored apiv1.Database
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(database), &stored)).To(Succeed())
+		Expect(stored.Finalizers).To(ConsistOf(utils.DatabaseFinalizerName))
+
+		database.Spec.ReclaimPolicy = apiv1.DatabaseReclaimRetain
+		Expect(r.reconcileFinalizer(ctx, database)).To(Succeed())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(database), &stored)).To(Succeed())
+		Expect(stored.Finalizers).To(BeEmpty())
+	})
+
+	It("records the reconciliation errors in the status", func(ctx SpecContext) {
+		result, err := r.updateStatus(context.Background(), database, errors.New("boom"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(databaseReconciliationInterval))
+
+		var stored apiv1.Database
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(database), &stored)).To(Succeed())
+		Expect(stored.Status.Ready).To(BeFalse())
+		Expect(stored.Status.Error).To(Equal("boom"))
+		Expect(stored.Status.ObservedGeneration).To(BeEquivalentTo(2))
+
+		result, err = r.updateStatus(context.Background(), database, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(database), &stored)).To(Succeed())
+		Expect(stored.Status.Ready).To(BeTrue())
+		Expect(stored.Status.Error).To(BeEmpty())
+	})
+
+	Context("with more Database objects for the same database", func() {
+		newDatabase := func(name string, creation time.Time, clusterName string) *apiv1.Database {
+			return &apiv1.Database{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(creation),
+				},
+				Spec: apiv1.DatabaseSpec{
+					ClusterRef: apiv1.LocalObjectReference{Name: clusterName},
+					Name:       "one",
+					Owner:      "app",
+				},
+			}
+		}
+
+		now := time.Now().Truncate(time.Second)
+
+		It("lets the oldest object manage the database", func(ctx SpecContext) {
+			older := newDatabase("db-older", now.Add(-time.Hour), "cluster-example")
+			newer := newDatabase("db-newer", now, "cluster-example")
+			otherCluster := newDatabase("db-other", now.Add(-2*time.Hour), "another-cluster")
+			cli = fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(older, newer, otherCluster).
+				Build()
+			r = NewDatabaseReconciler(nil, cli)
+
+			conflicting, err := r.getConflictingDatabase(ctx, newer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conflicting).ToNot(BeNil())
+			Expect(conflicting.Name).To(Equal("db-older"))
+
+			conflicting, err = r.getConflictingDatabase(ctx, older)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conflicting).To(BeNil())
+		})
+
+		It("uses the name of the objects when they were created at the same time", func() {
+			first := newDatabase("db-a", now, "cluster-example")
+			second := newDatabase("db-b", now, "cluster-example")
+			Expect(isManagedBefore(first, second)).To(BeTrue())
+			Expect(isManagedBefore(second, first)).To(BeFalse())
+		})
+	})
+})
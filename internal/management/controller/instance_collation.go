@@ -0,0 +1,363 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reindexLeftoversCleanupTimeout is the maximum time allowed to drop the
+// invalid indexes left behind by a canceled REINDEX CONCURRENTLY
+const reindexLeftoversCleanupTimeout = 5 * time.Minute
+
+// collationVersionMismatch contains the collations of a database whose
+// version differs from the one provided by the libraries of the image
+type collationVersionMismatch struct {
+	// The name of the database
+	database string
+
+	// Whether the default collation of the database is affected
+	defaultCollation bool
+
+	// The qualified names of the affected collations having dependent objects
+	collations []string
+}
+
+// reconcileCollationVersions checks the version of the collations used by
+// the databases against the one of the libraries in the image, reporting
+// the mismatches in the CollationVersionsAligned condition of the cluster.
+// As the libraries only change together with the image, the check runs once
+// after the instance manager starts as, or becomes, the primary. When
+// requested, the affected indexes are rebuilt in the background inside
+// the maintenance windows, and the rebuild is canceled when the window ends
+func (r *InstanceReconciler) reconcileCollationVersions(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !isPrimary {
+		r.collationVersionsChecked.Store(false)
+		return nil
+	}
+
+	if r.collationReindexRunning.Load() {
+		return nil
+	}
+
+	reindexDue := cluster.IsCollationAutoReindexEnabled() && !cluster.IsReplica() &&
+		meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionCollationVersionsAligned)) &&
+		cluster.IsInMaintenanceWindow(time.Now())
+	if r.collationVersionsChecked.Load() && !reindexDue {
+		return nil
+	}
+
+	mismatches, err := r.getCollationVersionMismatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.collationVersionsChecked.Store(true)
+	if err := conditions.Patch(ctx, r.client, cluster, buildCollationVersionsCondition(mismatches)); err != nil {
+		return err
+	}
+
+	if reindexDue && len(mismatches) > 0 {
+		// Rebuilding the indexes can take a long time, and
+		// must not block the reconciliation of the instance
+		var reindexCtx context.Context
+		var cancel context.CancelFunc
+		if end := cluster.GetMaintenanceWindowEnd(time.Now()); end.IsZero() {
+			reindexCtx, cancel = context.WithCancel(ctx)
+		} else {
+			reindexCtx, cancel = context.WithDeadline(ctx, end)
+		}
+
+		r.collationReindexRunning.Store(true)
+		go func() {
+			defer cancel()
+			r.refreshCollationVersions(reindexCtx, mismatches)
+		}()
+	}
+
+	return nil
+}
+
+// getCollationVersionMismatches gets the collations whose version changed
+// in every database accepting connections
+func (r *InstanceReconciler) getCollationVersionMismatches(ctx context.Context) ([]collationVersionMismatch, error) {
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return nil, fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	pgVersion, err := r.instance.GetPgVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	// The version of the default collation of the databases
+	// is only tracked since PostgreSQL 15
+	var databasesWithDefaultMismatch []string
+	if pgVersion.Major >= 15 {
+		if databasesWithDefaultMismatch, err = getDatabasesWithDefaultCollationMismatch(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	databases, listErrs := r.getAllAccessibleDatabases(ctx, db)
+	if len(listErrs) > 0 {
+		return nil, fmt.Errorf("while listing the databases: %w", errors.Join(listErrs...))
+	}
+
+	var mismatches []collationVersionMismatch
+	for _, databaseName := range databases {
+		databaseDB, err := r.instance.ConnectionPool().Connection(databaseName)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to database %s: %w", databaseName, err)
+		}
+
+		collations, err := getCollationsWithVersionMismatch(ctx, databaseDB)
+		if err != nil {
+			return nil, fmt.Errorf("while checking the collations of database %s: %w", databaseName, err)
+		}
+
+		defaultCollation := slices.Contains(databasesWithDefaultMismatch, databaseName)
+		if defaultCollation || len(collations) > 0 {
+			mismatches = append(mismatches, collationVersionMismatch{
+				database:         databaseName,
+				defaultCollation: defaultCollation,
+				collations:       collations,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// refreshCollationVersions rebuilds the indexes of the databases affected
+// by a collation version mismatch, then records the new collation versions.
+// The collation versions are checked again by the next reconciliation loop
+func (r *InstanceReconciler) refreshCollationVersions(
+	ctx context.Context,
+	mismatches []collationVersionMismatch,
+) {
+	contextLogger := log.FromContext(ctx)
+	defer r.collationReindexRunning.Store(false)
+
+	for _, mismatch := range mismatches {
+		db, err := r.instance.ConnectionPool().Connection(mismatch.database)
+		if err != nil {
+			contextLogger.Error(err, "Could not connect to database", "database", mismatch.database)
+			return
+		}
+
+		contextLogger.Info("Rebuilding the indexes affected by a collation version mismatch",
+			"database", mismatch.database,
+			"defaultCollation", mismatch.defaultCollation,
+			"collations", mismatch.collations)
+		if err := refreshCollationVersion(ctx, db, mismatch); err != nil {
+			if ctx.Err() == nil {
+				contextLogger.Error(err, "Cannot refresh the collation versions", "database", mismatch.database)
+				return
+			}
+
+			contextLogger.Info("The maintenance window ended, the indexes will be rebuilt in the next one",
+				"database", mismatch.database)
+			dropInvalidReindexLeftovers(ctx, db, mismatch.database)
+			return
+		}
+	}
+
+	contextLogger.Info("Collation versions refreshed")
+	r.collationVersionsChecked.Store(false)
+}
+
+// dropInvalidReindexLeftovers drops the invalid indexes left behind by a
+// canceled REINDEX CONCURRENTLY. The passed context is expected to be
+// done already, so a separate one is used
+func dropInvalidReindexLeftovers(ctx context.Context, db *sql.DB, database string) {
+	contextLogger := log.FromContext(ctx)
+
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reindexLeftoversCleanupTimeout)
+	defer cancel()
+
+	indexes, err := getInvalidReindexLeftovers(cleanupCtx, db)
+	if err != nil {
+		contextLogger.Error(err, "Cannot find the indexes left behind by REINDEX", "database", database)
+		return
+	}
+
+	for _, index := range indexes {
+		if _, err := db.ExecContext(cleanupCtx, fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", index)); err != nil {
+			contextLogger.Error(err, "Cannot drop the index left behind by REINDEX",
+				"database", database, "index", index)
+			return
+		}
+	}
+}
+
+// getInvalidReindexLeftovers gets the qualified names of the invalid
+// indexes left behind by a canceled REINDEX CONCURRENTLY
+func getInvalidReindexLeftovers(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT n.nspname, c.relname FROM pg_catalog.pg_index i "+
+			"JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace "+
+			"WHERE NOT i.indisvalid AND c.relname ~ '_ccnew[0-9]*$' "+
+			"ORDER BY n.nspname, c.relname")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var indexes []string
+	for rows.Next() {
+		var schema, name string
+		if err := rows.Scan(&schema, &name); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, pgx.Identifier{schema, name}.Sanitize())
+	}
+
+	return indexes, rows.Err()
+}
+
+// getDatabasesWithDefaultCollationMismatch gets the databases whose
+// default collation version differs from the one of the libraries
+func getDatabasesWithDefaultCollationMismatch(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT datname FROM pg_catalog.pg_database "+
+			"WHERE datallowconn AND datcollversion IS NOT NULL "+
+			"AND datcollversion IS DISTINCT FROM pg_catalog.pg_database_collation_actual_version(oid) "+
+			"ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("while checking the default collation of the databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		databases = append(databases, name)
+	}
+
+	return databases, rows.Err()
+}
+
+// getCollationsWithVersionMismatch gets the collations of the current
+// database, used by at least one object, whose version differs from
+// the one of the libraries
+func getCollationsWithVersionMismatch(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT n.nspname, c.collname FROM pg_catalog.pg_collation c "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.collnamespace "+
+			"WHERE c.collversion IS NOT NULL "+
+			"AND c.collversion IS DISTINCT FROM pg_catalog.pg_collation_actual_version(c.oid) "+
+			"AND EXISTS (SELECT 1 FROM pg_catalog.pg_depend d "+
+			"WHERE d.refclassid = 'pg_catalog.pg_collation'::pg_catalog.regclass AND d.refobjid = c.oid) "+
+			"ORDER BY n.nspname, c.collname")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var collations []string
+	for rows.Next() {
+		var schema, name string
+		if err := rows.Scan(&schema, &name); err != nil {
+			return nil, err
+		}
+		collations = append(collations, pgx.Identifier{schema, name}.Sanitize())
+	}
+
+	return collations, rows.Err()
+}
+
+// refreshCollationVersion rebuilds every index of a database without
+// blocking writes, and records the new version of the affected collations
+func refreshCollationVersion(ctx context.Context, db *sql.DB, mismatch collationVersionMismatch) error {
+	database := pgx.Identifier{mismatch.database}.Sanitize()
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("REINDEX DATABASE CONCURRENTLY %s", database)); err != nil {
+		return fmt.Errorf("while rebuilding the indexes of database %s: %w", mismatch.database, err)
+	}
+
+	for _, collation := range mismatch.collations {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER COLLATION %s REFRESH VERSION", collation)); err != nil {
+			return fmt.Errorf("while refreshing the version of collation %s: %w", collation, err)
+		}
+	}
+
+	if mismatch.defaultCollation {
+		if _, err := db.ExecContext(ctx,
+			fmt.Sprintf("ALTER DATABASE %s REFRESH COLLATION VERSION", database)); err != nil {
+			return fmt.Errorf("while refreshing the collation version of database %s: %w", mismatch.database, err)
+		}
+	}
+
+	return nil
+}
+
+// buildCollationVersionsCondition builds the CollationVersionsAligned
+// condition listing the databases needing their indexes to be rebuilt
+func buildCollationVersionsCondition(mismatches []collationVersionMismatch) *metav1.Condition {
+	if len(mismatches) == 0 {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionCollationVersionsAligned),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.CollationVersionsMatch),
+			Message: "Every collation matches the version of the libraries in the image",
+		}
+	}
+
+	databases := make([]string, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		databases = append(databases, mismatch.database)
+	}
+	sort.Strings(databases)
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionCollationVersionsAligned),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.CollationVersionMismatch),
+		Message: fmt.Sprintf("The version of some collations changed, REINDEX is needed in databases: %s",
+			strings.Join(databases, ", ")),
+	}
+}
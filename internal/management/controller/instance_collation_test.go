@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("collation versions", func() {
+	It("lists the used collations whose version changed", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("pg_collation_actual_version").
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "collname"}).
+				AddRow("pg_catalog", "en_US").
+				AddRow("public", "my collation"))
+
+		collations, err := getCollationsWithVersionMismatch(context.Background(), db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(collations).To(Equal([]string{`"pg_catalog"."en_US"`, `"public"."my collation"`}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("lists the databases whose default collation version changed", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("pg_database_collation_actual_version").
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+
+		databases, err := getDatabasesWithDefaultCollationMismatch(context.Background(), db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(databases).To(Equal([]string{"app", "postgres"}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("drops the invalid indexes left behind by a canceled REINDEX", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("_ccnew").
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname"}).
+				AddRow("public", "orders_name_idx_ccnew").
+				AddRow("public", "orders_pkey_ccnew1"))
+		mock.ExpectExec(`DROP INDEX CONCURRENTLY IF EXISTS "public"."orders_name_idx_ccnew"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DROP INDEX CONCURRENTLY IF EXISTS "public"."orders_pkey_ccnew1"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// The context of the rebuild is already canceled
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		dropInvalidReindexLeftovers(ctx, db, "app")
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("rebuilds the indexes before refreshing the collation versions", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectExec(`REINDEX DATABASE CONCURRENTLY "app"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ALTER COLLATION "public"."de_DE" REFRESH VERSION`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`ALTER DATABASE "app" REFRESH COLLATION VERSION`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(refreshCollationVersion(context.Background(), db, collationVersionMismatch{
+			database:         "app",
+			defaultCollation: true,
+			collations:       []string{`"public"."de_DE"`},
+		})).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the databases needing a reindex in the condition", func() {
+		condition := buildCollationVersionsCondition([]collationVersionMismatch{
+			{database: "postgres", defaultCollation: true},
+			{database: "app", collations: []string{`"public"."de_DE"`}},
+		})
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionCollationVersionsAligned)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.CollationVersionMismatch)))
+		Expect(condition.Message).To(HaveSuffix("databases: app, postgres"))
+	})
+
+	It("reports the collations are aligned without mismatches", func() {
+		condition := buildCollationVersionsCondition(nil)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.CollationVersionsMatch)))
+	})
+})
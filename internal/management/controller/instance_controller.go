@@ -46,15 +46,18 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
 const (
@@ -230,6 +233,22 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := r.reconcileRestorePoint(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot create the requested restore point: %w", err)
+	}
+
+	if err := r.reconcileCollationVersions(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot check the collation versions: %w", err)
+	}
+
+	if err := r.reconcileTransactionIDWraparound(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot check the transaction ID wraparound risk: %w", err)
+	}
+
+	if err := r.reconcileConfigurationDrift(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot revert the configuration drift: %w", err)
+	}
+
 	// EXTREMELY IMPORTANT
 	//
 	// The reconciliation loop may not have applied all the changes needed. In this case
@@ -349,6 +368,10 @@ func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Clus
 		return err
 	}
 
+	if err := r.verifyPgDataCoherenceForReplica(ctx, cluster); err != nil {
+		return err
+	}
+
 	if err := system.SetCoredumpFilter(cluster.GetCoredumpFilter()); err != nil {
 		return err
 	}
@@ -736,6 +759,14 @@ func (r *InstanceReconciler) reconcileMetrics(
 	}
 }
 
+// customMonitoringQueries are the custom monitoring queries loaded from
+// a ConfigMap or a Secret
+type customMonitoringQueries struct {
+	kind      string
+	reference interface{}
+	data      []byte
+}
+
 // reconcileMonitoringQueries applies the custom monitoring queries to the
 // web server
 func (r *InstanceReconciler) reconcileMonitoringQueries(
@@ -750,14 +781,49 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 		dbname = cluster.GetApplicationDatabaseName()
 	}
 
+	customQueries := r.getCustomMonitoringQueries(ctx, cluster)
+
+	// The queries collector is replaced only when the queries change,
+	// as it also holds the metrics cached because of `cache_seconds`
+	checksum, err := hash.ComputeHash(struct {
+		DBName  string
+		Queries []customMonitoringQueries
+	}{
+		DBName:  dbname,
+		Queries: customQueries,
+	})
+	if err == nil && checksum == r.monitoringQueriesChecksum {
+		return
+	}
+
 	queriesCollector := metrics.NewQueriesCollector("cnpg", r.instance, dbname)
 	queriesCollector.InjectUserQueries(metricserver.DefaultQueries)
 
+	for _, queries := range customQueries {
+		if err := queriesCollector.ParseQueries(queries.data); err != nil {
+			contextLogger.Warning("Error while parsing custom queries in "+queries.kind,
+				"reference", queries.reference,
+				"error", err.Error())
+		}
+	}
+
+	r.metricsServerExporter.SetCustomQueries(queriesCollector)
+	r.monitoringQueriesChecksum = checksum
+}
+
+// getCustomMonitoringQueries loads the custom monitoring queries from the
+// ConfigMaps and the Secrets referenced by the cluster
+func (r *InstanceReconciler) getCustomMonitoringQueries(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) []customMonitoringQueries {
+	contextLogger := log.FromContext(ctx)
+
 	if cluster.Spec.Monitoring == nil {
-		r.metricsServerExporter.SetCustomQueries(queriesCollector)
-		return
+		return nil
 	}
 
+	var result []customMonitoringQueries
 	for _, reference := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
 		var configMap corev1.ConfigMap
 		err := r.GetClient().Get(
@@ -778,13 +844,11 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 			continue
 		}
 
-		err = queriesCollector.ParseQueries([]byte(data))
-		if err != nil {
-			contextLogger.Warning("Error while parsing custom queries in ConfigMap",
-				"reference", reference,
-				"error", err.Error())
-			continue
-		}
+		result = append(result, customMonitoringQueries{
+			kind:      "ConfigMap",
+			reference: reference,
+			data:      []byte(data),
+		})
 	}
 
 	for _, reference := range cluster.Spec.Monitoring.CustomQueriesSecret {
@@ -804,16 +868,14 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 			continue
 		}
 
-		err = queriesCollector.ParseQueries(data)
-		if err != nil {
-			contextLogger.Warning("Error while parsing custom queries in Secret",
-				"reference", reference,
-				"error", err.Error())
-			continue
-		}
+		result = append(result, customMonitoringQueries{
+			kind:      "Secret",
+			reference: reference,
+			data:      data,
+		})
 	}
 
-	r.metricsServerExporter.SetCustomQueries(queriesCollector)
+	return result
 }
 
 // RefreshSecrets is called when the PostgreSQL secrets are changed
@@ -890,6 +952,8 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
 	r.instance.SmartStopDelay = cluster.GetSmartShutdownTimeout()
+	r.instance.ReplicationConnection = cluster.Spec.ReplicationConnection.DeepCopy()
+	r.instance.PrimaryService = cluster.GetServicePrimaryName()
 }
 
 // reconcileAutoConf reconciles the permission of `postgresql.auto.conf`
@@ -1125,12 +1189,24 @@ func (r *InstanceReconciler) handlePromotion(ctx context.Context, cluster *apiv1
 		}
 	}
 
+	if err := lifecyclehooks.Run(ctx, cluster, r.instance.PodName, lifecyclehooks.EventPrePromote); err != nil {
+		return fmt.Errorf("refusing to promote the instance: %w", err)
+	}
+
 	contextLogger.Info("I'm the target primary, applying WALs and promoting my instance")
 	// I must promote my instance here
-	err := r.instance.PromoteAndWait(ctx)
+	promoteCtx, span := tracing.StartClusterSpan(ctx, "Promote", cluster)
+	err := r.instance.PromoteAndWait(promoteCtx)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return fmt.Errorf("error promoting instance: %w", err)
 	}
+
+	// The instance has already been promoted, there's nothing to abort
+	// if the hooks fail
+	if err := lifecyclehooks.Run(ctx, cluster, r.instance.PodName, lifecyclehooks.EventPostPromote); err != nil {
+		contextLogger.Error(err, "while running the post-promote hooks")
+	}
 	return nil
 }
 
@@ -1199,20 +1275,34 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 		return err
 	}
 
+	return r.reconcileCredentials(ctx, cluster, db)
+}
+
+// reconcileCredentials aligns the passwords of the superuser and of the
+// owner of the application database with the content of their secrets
+func (r *InstanceReconciler) reconcileCredentials(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	db *sql.DB,
+) error {
 	if cluster.GetEnableSuperuserAccess() {
-		err = r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), db)
+		err := r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), db)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = postgresutils.DisableSuperuserPassword(db)
+		err := postgresutils.DisableSuperuserPassword(db)
 		if err != nil {
 			return err
 		}
+		// The password has been removed, so the content of the secret
+		// needs to be applied again if the superuser access is re-enabled,
+		// even if the secret itself didn't change
+		delete(r.secretVersions, cluster.GetSuperuserSecretName())
 	}
 
 	if cluster.ShouldCreateApplicationDatabase() {
-		err = r.reconcileUser(ctx, cluster.GetApplicationDatabaseOwner(), cluster.GetApplicationSecretName(), db)
+		err := r.reconcileUser(ctx, cluster.GetApplicationDatabaseOwner(), cluster.GetApplicationSecretName(), db)
 		if err != nil {
 			return err
 		}
@@ -1258,6 +1348,22 @@ func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string,
 	return nil
 }
 
+// ReloadPGHBA regenerates the pg_hba.conf file from the current definition
+// of the cluster, reloading the instance when its content changes
+func (r *InstanceReconciler) ReloadPGHBA(ctx context.Context) error {
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	changed, err := r.refreshPGHBA(ctx, cluster)
+	if err != nil || !changed {
+		return err
+	}
+
+	return r.instance.Reload(ctx)
+}
+
 func (r *InstanceReconciler) refreshPGHBA(ctx context.Context, cluster *apiv1.Cluster) (
 	postgresHBAChanged bool,
 	err error,
@@ -0,0 +1,217 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("monitoring queries", func() {
+	var (
+		ctx       context.Context
+		r         *InstanceReconciler
+		cluster   *apiv1.Cluster
+		configMap *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "custom-queries",
+				Namespace: "default",
+			},
+			Data: map[string]string{
+				"queries": "pg_custom:\n  query: SELECT 1 AS one\n  metrics:\n  - one:\n      usage: GAUGE\n",
+			},
+		}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					CustomQueriesConfigMap: []apiv1.ConfigMapKeySelector{
+						{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: configMap.Name},
+							Key:                  "queries",
+						},
+					},
+				},
+			},
+		}
+
+		instance := &postgres.Instance{Namespace: "default"}
+		r = &InstanceReconciler{
+			client: fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(configMap).
+				Build(),
+			instance:              instance,
+			metricsServerExporter: metricserver.NewExporter(instance),
+		}
+	})
+
+	It("applies the queries again only when they change", func() {
+		r.reconcileMonitoringQueries(ctx, cluster)
+		checksum := r.monitoringQueriesChecksum
+		Expect(checksum).ToNot(BeEmpty())
+
+		By("keeping the queries when nothing changed", func() {
+			r.reconcileMonitoringQueries(ctx, cluster)
+			Expect(r.monitoringQueriesChecksum).To(Equal(checksum))
+		})
+
+		By("applying the queries again when the ConfigMap changes", func() {
+			configMap.Data["queries"] += "  cache_seconds: 30\n"
+			Expect(r.client.Update(ctx, configMap)).To(Succeed())
+
+			r.reconcileMonitoringQueries(ctx, cluster)
+			Expect(r.monitoringQueriesChecksum).ToNot(Equal(checksum))
+		})
+	})
+
+	It("takes into account the queries of the referenced Secrets", func() {
+		r.reconcileMonitoringQueries(ctx, cluster)
+		checksum := r.monitoringQueriesChecksum
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "custom-queries-secret",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"queries": []byte(configMap.Data["queries"]),
+			},
+		}
+		Expect(r.client.Create(ctx, secret)).To(Succeed())
+		cluster.Spec.Monitoring.CustomQueriesSecret = []apiv1.SecretKeySelector{
+			{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: secret.Name},
+				Key:                  "queries",
+			},
+		}
+
+		r.reconcileMonitoringQueries(ctx, cluster)
+		Expect(r.monitoringQueriesChecksum).ToNot(Equal(checksum))
+		Expect(getCustomQueriesKinds(r.getCustomMonitoringQueries(ctx, cluster))).
+			To(Equal([]string{"ConfigMap", "Secret"}))
+	})
+})
+
+var _ = Describe("credentials reconciliation", func() {
+	const (
+		setPasswordQuery   = `ALTER ROLE "postgres" WITH PASSWORD 'secret'`
+		checkPasswordQuery = `SELECT rolpassword IS NOT NULL
+		FROM pg_catalog.pg_authid
+		WHERE rolname='postgres'`
+	)
+
+	var (
+		ctx     context.Context
+		r       *InstanceReconciler
+		cluster *apiv1.Cluster
+		mock    sqlmock.Sqlmock
+		db      *sql.DB
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetSuperuserSecretName(),
+				Namespace: "default",
+			},
+			Type: corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("postgres"),
+				corev1.BasicAuthPasswordKey: []byte("secret"),
+			},
+		}
+		r = &InstanceReconciler{
+			client: fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(secret).
+				Build(),
+			instance:       &postgres.Instance{Namespace: "default"},
+			secretVersions: make(map[string]string),
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("applies the superuser secret only when it changes", func() {
+		mock.ExpectExec(setPasswordQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(r.reconcileCredentials(ctx, cluster, db)).To(Succeed())
+		Expect(r.secretVersions).To(HaveKey(cluster.GetSuperuserSecretName()))
+
+		Expect(r.reconcileCredentials(ctx, cluster, db)).To(Succeed())
+	})
+
+	It("applies the superuser secret again when the access is re-enabled", func() {
+		mock.ExpectExec(setPasswordQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(r.reconcileCredentials(ctx, cluster, db)).To(Succeed())
+
+		cluster.Spec.EnableSuperuserAccess = ptr.To(false)
+		mock.ExpectQuery(checkPasswordQuery).WillReturnRows(sqlmock.NewRows([]string{"has_password"}).AddRow(true))
+		mock.ExpectBegin()
+		mock.ExpectExec("ALTER ROLE postgres WITH PASSWORD NULL").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		Expect(r.reconcileCredentials(ctx, cluster, db)).To(Succeed())
+		Expect(r.secretVersions).ToNot(HaveKey(cluster.GetSuperuserSecretName()))
+
+		cluster.Spec.EnableSuperuserAccess = ptr.To(true)
+		mock.ExpectExec(setPasswordQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(r.reconcileCredentials(ctx, cluster, db)).To(Succeed())
+	})
+})
+
+func getCustomQueriesKinds(queries []customMonitoringQueries) []string {
+	result := make([]string, len(queries))
+	for idx := range queries {
+		result[idx] = queries[idx].kind
+	}
+	return result
+}
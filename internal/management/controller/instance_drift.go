@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileConfigurationDrift resets the parameters changed with ALTER SYSTEM
+// in this instance, when the cluster is configured to revert them. The drift
+// is detected by the operator from the status of the instances
+func (r *InstanceReconciler) reconcileConfigurationDrift(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.GetConfigurationDriftPolicy() != apiv1.ConfigurationDriftPolicyRevert {
+		return nil
+	}
+
+	drifted := getDriftedParameters(cluster, r.instance.PodName)
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	// The drift in the status of the cluster may come from a status
+	// of this instance preceding the last reset
+	hasDrift, err := r.instance.HasAlterSystemParameters(drifted...)
+	if err != nil || !hasDrift {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Reverting the parameters changed with ALTER SYSTEM",
+		"parameters", drifted)
+	return r.instance.ResetAlterSystemParameters(ctx)
+}
+
+// getDriftedParameters gets the names of the parameters reported as changed
+// with ALTER SYSTEM in the passed instance
+func getDriftedParameters(cluster *apiv1.Cluster, instanceName string) []string {
+	var result []string
+	for _, drift := range cluster.Status.ConfigurationDrift {
+		if drift.Instance == instanceName {
+			result = append(result, drift.Name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("configuration drift", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			ConfigurationDrift: []apiv1.ParameterDrift{
+				{Name: "log_statement", Instance: "cluster-example-1", Value: "all"},
+				{Name: "work_mem", Instance: "cluster-example-1", Value: "64MB"},
+				{Name: "work_mem", Instance: "cluster-example-2", Value: "32MB"},
+			},
+		},
+	}
+
+	It("gets the parameters changed in the instance", func() {
+		Expect(getDriftedParameters(cluster, "cluster-example-1")).To(Equal([]string{"log_statement", "work_mem"}))
+		Expect(getDriftedParameters(cluster, "cluster-example-2")).To(Equal([]string{"work_mem"}))
+	})
+
+	It("returns nothing when the instance has no drift", func() {
+		Expect(getDriftedParameters(cluster, "cluster-example-3")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// maxRestorePoints is the number of restore points kept in the
+// status of the cluster
+const maxRestorePoints = 10
+
+// reconcileRestorePoint creates the restore point requested through the
+// restore point annotation, recording it in the status of the cluster
+func (r *InstanceReconciler) reconcileRestorePoint(ctx context.Context, cluster *apiv1.Cluster) error {
+	name := cluster.Annotations[utils.RestorePointAnnotationName]
+	if name == "" || cluster.IsReplica() {
+		return nil
+	}
+	if restorePoint := cluster.GetRestorePoint(name); restorePoint != nil && restorePoint.LSN != "" {
+		return nil
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !isPrimary {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	return r.createRequestedRestorePoint(ctx, cluster, db, name)
+}
+
+// createRequestedRestorePoint creates the named restore point and records
+// its LSN in the status of the cluster.
+// The name is recorded before creating the restore point, so that it is
+// not created again while its LSN is being recorded. An entry whose LSN
+// could not be recorded, because the instance manager failed or was
+// restarted in the meantime, is completed by creating the restore point
+// again: a duplicate with the same name is harmless, as the recovery
+// stops at the first one
+func (r *InstanceReconciler) createRequestedRestorePoint(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	db *sql.DB,
+	name string,
+) error {
+	recorded := cluster.GetRestorePoint(name) != nil
+	if !recorded {
+		oldCluster := cluster.DeepCopy()
+		cluster.Status.RestorePoints = appendRestorePoint(cluster.Status.RestorePoints, apiv1.RestorePoint{
+			Name:      name,
+			CreatedAt: metav1.Now(),
+		})
+		if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+			return fmt.Errorf("while recording the restore point %s: %w", name, err)
+		}
+	}
+
+	lsn, err := createRestorePoint(ctx, db, name)
+	if err != nil {
+		if recorded {
+			return err
+		}
+
+		// The restore point has not been created, so we remove it from
+		// the status to let it be created again
+		oldCluster := cluster.DeepCopy()
+		cluster.Status.RestorePoints = removeRestorePoint(cluster.Status.RestorePoints, name)
+		if patchErr := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); patchErr != nil {
+			return errors.Join(err, patchErr)
+		}
+		return err
+	}
+
+	log.FromContext(ctx).Info("Created the requested restore point", "name", name, "lsn", lsn)
+
+	oldCluster := cluster.DeepCopy()
+	cluster.GetRestorePoint(name).LSN = lsn
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// createRestorePoint creates a named restore point, returning its LSN
+func createRestorePoint(ctx context.Context, db *sql.DB, name string) (string, error) {
+	var lsn string
+	if err := db.QueryRowContext(ctx, "SELECT pg_catalog.pg_create_restore_point($1)", name).Scan(&lsn); err != nil {
+		return "", fmt.Errorf("while creating the restore point %s: %w", name, err)
+	}
+
+	return lsn, nil
+}
+
+// appendRestorePoint adds a restore point to the passed list, keeping only
+// the most recent ones
+func appendRestorePoint(restorePoints []apiv1.RestorePoint, restorePoint apiv1.RestorePoint) []apiv1.RestorePoint {
+	restorePoints = append(restorePoints, restorePoint)
+	if len(restorePoints) > maxRestorePoints {
+		restorePoints = restorePoints[len(restorePoints)-maxRestorePoints:]
+	}
+
+	return restorePoints
+}
+
+// removeRestorePoint removes the named restore point from the passed list
+func removeRestorePoint(restorePoints []apiv1.RestorePoint, name string) []apiv1.RestorePoint {
+	result := make([]apiv1.RestorePoint, 0, len(restorePoints))
+	for _, restorePoint := range restorePoints {
+		if restorePoint.Name != name {
+			result = append(result, restorePoint)
+		}
+	}
+
+	return result
+}
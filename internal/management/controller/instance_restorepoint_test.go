@@ -0,0 +1,177 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restore points", func() {
+	It("creates a named restore point returning its LSN", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT pg_catalog.pg_create_restore_point($1)").
+			WithArgs("pre-deploy").
+			WillReturnRows(sqlmock.NewRows([]string{"pg_create_restore_point"}).AddRow("0/3000090"))
+
+		lsn, err := createRestorePoint(context.Background(), db, "pre-deploy")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lsn).To(Equal("0/3000090"))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the errors raised by PostgreSQL", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("SELECT pg_catalog.pg_create_restore_point($1)").
+			WithArgs("pre-deploy").
+			WillReturnError(fmt.Errorf("recovery is in progress"))
+
+		_, err = createRestorePoint(context.Background(), db, "pre-deploy")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("keeps only the most recent restore points", func() {
+		var restorePoints []apiv1.RestorePoint
+		for idx := 0; idx < maxRestorePoints+2; idx++ {
+			restorePoints = appendRestorePoint(restorePoints, apiv1.RestorePoint{
+				Name: fmt.Sprintf("restore-point-%d", idx),
+			})
+		}
+
+		Expect(restorePoints).To(HaveLen(maxRestorePoints))
+		Expect(restorePoints[0].Name).To(Equal("restore-point-2"))
+		Expect(restorePoints[maxRestorePoints-1].Name).To(Equal(fmt.Sprintf("restore-point-%d", maxRestorePoints+1)))
+	})
+
+	It("removes a restore point", func() {
+		restorePoints := []apiv1.RestorePoint{
+			{Name: "before-deploy", LSN: "0/3000090"},
+			{Name: "before-migration"},
+		}
+
+		restorePoints = removeRestorePoint(restorePoints, "before-migration")
+		Expect(restorePoints).To(HaveLen(1))
+		Expect(restorePoints[0].Name).To(Equal("before-deploy"))
+		Expect(removeRestorePoint(restorePoints, "unknown")).To(Equal(restorePoints))
+	})
+})
+
+var _ = Describe("requested restore points", func() {
+	const createQuery = "SELECT pg_catalog.pg_create_restore_point($1)"
+
+	var (
+		ctx     context.Context
+		r       *InstanceReconciler
+		cluster *apiv1.Cluster
+		mock    sqlmock.Sqlmock
+		db      *sql.DB
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		r = &InstanceReconciler{
+			client: fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				WithStatusSubresource(cluster).
+				Build(),
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	getStoredRestorePoint := func() *apiv1.RestorePoint {
+		var stored apiv1.Cluster
+		Expect(r.client.Get(ctx, client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		return stored.GetRestorePoint("pre-deploy")
+	}
+
+	It("records the restore point with its LSN", func() {
+		mock.ExpectQuery(createQuery).
+			WithArgs("pre-deploy").
+			WillReturnRows(sqlmock.NewRows([]string{"pg_create_restore_point"}).AddRow("0/3000090"))
+
+		Expect(r.createRequestedRestorePoint(ctx, cluster, db, "pre-deploy")).To(Succeed())
+		Expect(getStoredRestorePoint()).To(HaveField("LSN", "0/3000090"))
+	})
+
+	It("removes the restore point from the status when it can't be created", func() {
+		mock.ExpectQuery(createQuery).
+			WithArgs("pre-deploy").
+			WillReturnError(fmt.Errorf("recovery is in progress"))
+
+		Expect(r.createRequestedRestorePoint(ctx, cluster, db, "pre-deploy")).ToNot(Succeed())
+		Expect(getStoredRestorePoint()).To(BeNil())
+	})
+
+	When("the restore point has been recorded without its LSN", func() {
+		BeforeEach(func() {
+			cluster.Status.RestorePoints = []apiv1.RestorePoint{
+				{Name: "pre-deploy", CreatedAt: metav1.Now()},
+			}
+		})
+
+		It("creates it again and records the LSN", func() {
+			mock.ExpectQuery(createQuery).
+				WithArgs("pre-deploy").
+				WillReturnRows(sqlmock.NewRows([]string{"pg_create_restore_point"}).AddRow("0/4000028"))
+
+			Expect(r.createRequestedRestorePoint(ctx, cluster, db, "pre-deploy")).To(Succeed())
+			Expect(getStoredRestorePoint()).To(HaveField("LSN", "0/4000028"))
+		})
+
+		It("keeps it to be retried when the restore point can't be created", func() {
+			mock.ExpectQuery(createQuery).
+				WithArgs("pre-deploy").
+				WillReturnError(fmt.Errorf("connection refused"))
+
+			Expect(r.createRequestedRestorePoint(ctx, cluster, db, "pre-deploy")).ToNot(Succeed())
+			Expect(getStoredRestorePoint()).To(HaveField("LSN", ""))
+		})
+	})
+})
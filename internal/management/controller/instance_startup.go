@@ -214,48 +214,127 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 			return err
 		}
 
-		tag := pkgUtils.GetImageTag(cluster.GetImageName())
-		pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
-		if err != nil {
+		if err := r.rewindFromPrimary(ctx, cluster); err != nil {
 			return err
 		}
 
-		// Clean up any stale pid file before executing pg_rewind
-		err = r.instance.CleanUpStalePid()
+		// Now I can demote myself
+		return r.instance.Demote(ctx, cluster)
+	}
+}
+
+// verifyPgDataCoherenceForReplica aligns a replica found on an older timeline
+// than the primary before PostgreSQL is started, following the stale timeline
+// policy of the cluster. A replica that went past the point where the timeline
+// of the primary forked can't follow it anymore, and would fail to start with
+// "requested timeline does not contain minimum recovery point"
+func (r *InstanceReconciler) verifyPgDataCoherenceForReplica(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	// The timeline of the primary is not tracked in replica clusters
+	if isPrimary || cluster.IsReplica() || cluster.Status.TargetPrimary == r.instance.PodName {
+		return nil
+	}
+
+	primaryTimeline := cluster.Status.TimelineID
+	if primaryTimeline == 0 {
+		return nil
+	}
+
+	timeline, err := r.instance.GetTimeline()
+	if err != nil {
+		return err
+	}
+	if timeline >= primaryTimeline {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	policy := cluster.GetStaleTimelinePolicy()
+	contextLogger.Info("This replica is on an older timeline than the primary",
+		"timeline", timeline,
+		"primaryTimeline", primaryTimeline,
+		"staleTimelinePolicy", policy)
+	if policy == apiv1.StaleTimelinePolicyManual {
+		return nil
+	}
+
+	// Wait for the switchover to be reflected in the cluster metadata
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		contextLogger.Info("Switchover in progress",
+			"targetPrimary", cluster.Status.TargetPrimary,
+			"currentPrimary", cluster.Status.CurrentPrimary)
+		return controllers.ErrNextLoop
+	}
+
+	if err := r.instance.WaitForPrimaryAvailable(); err != nil {
+		return err
+	}
+
+	// When there's no need to rewind, pg_rewind just exits without
+	// touching the data directory
+	err = r.rewindFromPrimary(ctx, cluster)
+	if err != nil && policy != apiv1.StaleTimelinePolicyReclone {
+		return err
+	}
+	if err != nil {
+		contextLogger.Info("pg_rewind failed, cloning the primary again", "err", err)
+		return r.instance.Reclone(ctx, cluster)
+	}
+
+	// pg_rewind copies the configuration of the primary, so we need
+	// to write the replica configuration again
+	return r.instance.Demote(ctx, cluster)
+}
+
+// rewindFromPrimary aligns the data directory of this instance with the
+// primary one using pg_rewind, completing the crash recovery if needed
+func (r *InstanceReconciler) rewindFromPrimary(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	tag := pkgUtils.GetImageTag(cluster.GetImageName())
+	pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
+	if err != nil {
+		return err
+	}
+
+	// Clean up any stale pid file before executing pg_rewind
+	err = r.instance.CleanUpStalePid()
+	if err != nil {
+		return err
+	}
+
+	// pg_rewind could require a clean shutdown of the old primary to
+	// work. Unfortunately, if the old primary is already clean starting
+	// it up may make it advance in respect to the new one.
+	// The only way to check if we really need to start it up before
+	// invoking pg_rewind is to try using pg_rewind and, on failures,
+	// retrying after having started up the instance.
+	err = r.instance.Rewind(ctx, pgMajorVersion)
+	if err != nil {
+		contextLogger.Info(
+			"pg_rewind failed, starting the server to complete the crash recovery",
+			"err", err)
+
+		// pg_rewind requires a clean shutdown of the old primary to work.
+		// The only way to do that is to start the server again
+		// and wait for it to be available again.
+		err = r.instance.CompleteCrashRecovery()
 		if err != nil {
 			return err
 		}
 
-		// pg_rewind could require a clean shutdown of the old primary to
-		// work. Unfortunately, if the old primary is already clean starting
-		// it up may make it advance in respect to the new one.
-		// The only way to check if we really need to start it up before
-		// invoking pg_rewind is to try using pg_rewind and, on failures,
-		// retrying after having started up the instance.
+		// Then let's go back to the point of the new primary
 		err = r.instance.Rewind(ctx, pgMajorVersion)
 		if err != nil {
-			contextLogger.Info(
-				"pg_rewind failed, starting the server to complete the crash recovery",
-				"err", err)
-
-			// pg_rewind requires a clean shutdown of the old primary to work.
-			// The only way to do that is to start the server again
-			// and wait for it to be available again.
-			err = r.instance.CompleteCrashRecovery()
-			if err != nil {
-				return err
-			}
-
-			// Then let's go back to the point of the new primary
-			err = r.instance.Rewind(ctx, pgMajorVersion)
-			if err != nil {
-				return err
-			}
+			return err
 		}
-
-		// Now I can demote myself
-		return r.instance.Demote(ctx, cluster)
 	}
+
+	return nil
 }
 
 // ReconcileWalStorage moves the files from PGDATA/pg_wal to the volume attached, if exists, and
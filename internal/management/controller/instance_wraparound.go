@@ -0,0 +1,190 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// wraparoundCheckInterval is the minimum interval between two checks
+// of the transaction ID wraparound risk
+const wraparoundCheckInterval = 5 * time.Minute
+
+// databaseXIDAge is the age of the oldest unfrozen transaction ID of a database
+type databaseXIDAge struct {
+	// The name of the database
+	database string
+
+	// The age of datfrozenxid
+	age int64
+
+	// Whether the database accepts connections
+	allowConnections bool
+}
+
+// reconcileTransactionIDWraparound checks the age of the oldest unfrozen
+// transaction ID of every database, reporting the databases at risk of
+// wraparound in the TransactionIDWraparoundSafe condition of the cluster.
+// When requested, an aggressive vacuum is run in the background on the
+// databases exceeding the emergency threshold
+func (r *InstanceReconciler) reconcileTransactionIDWraparound(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !isPrimary {
+		r.wraparoundCheckedAt.Store(time.Time{})
+		return nil
+	}
+
+	if time.Since(r.wraparoundCheckedAt.Load()) < wraparoundCheckInterval {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	ages, err := getDatabaseXIDAges(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	r.wraparoundCheckedAt.Store(time.Now())
+	condition := buildTransactionIDWraparoundCondition(ages, int64(cluster.GetWraparoundWarningAge()))
+	if err := conditions.Patch(ctx, r.client, cluster, condition); err != nil {
+		return err
+	}
+
+	emergencyVacuumAge, enabled := cluster.GetWraparoundEmergencyVacuumAge()
+	if !enabled || cluster.IsReplica() || r.emergencyVacuumRunning.Load() {
+		return nil
+	}
+
+	if databases := getDatabasesToFreeze(ages, int64(emergencyVacuumAge)); len(databases) > 0 {
+		// Freezing a big database can take a long time, and
+		// must not block the reconciliation of the instance
+		r.emergencyVacuumRunning.Store(true)
+		go r.freezeDatabases(ctx, databases)
+	}
+
+	return nil
+}
+
+// freezeDatabases runs an aggressive vacuum on the passed databases
+func (r *InstanceReconciler) freezeDatabases(ctx context.Context, databases []string) {
+	contextLogger := log.FromContext(ctx)
+	defer r.emergencyVacuumRunning.Store(false)
+
+	for _, database := range databases {
+		db, err := r.instance.ConnectionPool().Connection(database)
+		if err != nil {
+			contextLogger.Error(err, "Could not connect to database", "database", database)
+			return
+		}
+
+		contextLogger.Warning("Running an emergency vacuum to prevent transaction ID wraparound",
+			"database", database)
+		if _, err := db.ExecContext(ctx, "VACUUM (FREEZE)"); err != nil {
+			contextLogger.Error(err, "Emergency vacuum failed", "database", database)
+			return
+		}
+	}
+
+	contextLogger.Info("Emergency vacuum completed", "databases", databases)
+
+	// Check the transaction ID ages again in the next reconciliation loop
+	r.wraparoundCheckedAt.Store(time.Time{})
+}
+
+// getDatabaseXIDAges gets the age of the oldest unfrozen
+// transaction ID of every database
+func getDatabaseXIDAges(ctx context.Context, db *sql.DB) ([]databaseXIDAge, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT datname, pg_catalog.age(datfrozenxid), datallowconn FROM pg_catalog.pg_database ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("while checking the transaction ID age of the databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var ages []databaseXIDAge
+	for rows.Next() {
+		var age databaseXIDAge
+		if err := rows.Scan(&age.database, &age.age, &age.allowConnections); err != nil {
+			return nil, err
+		}
+		ages = append(ages, age)
+	}
+
+	return ages, rows.Err()
+}
+
+// getDatabasesToFreeze gets the databases accepting connections whose
+// oldest unfrozen transaction ID is older than the passed age
+func getDatabasesToFreeze(ages []databaseXIDAge, maxAge int64) []string {
+	var databases []string
+	for _, age := range ages {
+		if age.allowConnections && age.age > maxAge {
+			databases = append(databases, age.database)
+		}
+	}
+
+	return databases
+}
+
+// buildTransactionIDWraparoundCondition builds the TransactionIDWraparoundSafe
+// condition listing the databases whose oldest unfrozen transaction ID
+// is older than the warning age
+func buildTransactionIDWraparoundCondition(ages []databaseXIDAge, warningAge int64) *metav1.Condition {
+	var atRisk []string
+	for _, age := range ages {
+		if age.age > warningAge {
+			atRisk = append(atRisk, fmt.Sprintf("%s (%d)", age.database, age.age))
+		}
+	}
+
+	if len(atRisk) == 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionTransactionIDWraparoundSafe),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.TransactionIDAgeBelowThreshold),
+			Message: fmt.Sprintf("The oldest unfrozen transaction ID of every database is younger than %d",
+				warningAge),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionTransactionIDWraparoundSafe),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.TransactionIDAgeAboveThreshold),
+		Message: fmt.Sprintf("The oldest unfrozen transaction ID is older than %d in databases: %s",
+			warningAge, strings.Join(atRisk, ", ")),
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("transaction ID wraparound", func() {
+	ages := []databaseXIDAge{
+		{database: "app", age: 1200000000, allowConnections: true},
+		{database: "postgres", age: 300000000, allowConnections: true},
+		{database: "template0", age: 1500000000, allowConnections: false},
+	}
+
+	It("gets the transaction ID age of the databases", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("datfrozenxid").
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "age", "datallowconn"}).
+				AddRow("app", 1200000000, true).
+				AddRow("template0", 1500000000, false))
+
+		result, err := getDatabaseXIDAges(context.Background(), db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal([]databaseXIDAge{
+			{database: "app", age: 1200000000, allowConnections: true},
+			{database: "template0", age: 1500000000, allowConnections: false},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the databases older than the warning age in the condition", func() {
+		condition := buildTransactionIDWraparoundCondition(ages, 1000000000)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.TransactionIDAgeAboveThreshold)))
+		Expect(condition.Message).To(ContainSubstring("app (1200000000), template0 (1500000000)"))
+	})
+
+	It("reports that no database is at risk", func() {
+		condition := buildTransactionIDWraparoundCondition(ages, 1600000000)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.TransactionIDAgeBelowThreshold)))
+	})
+
+	It("freezes only the databases accepting connections", func() {
+		Expect(getDatabasesToFreeze(ages, 1000000000)).To(Equal([]string{"app"}))
+		Expect(getDatabasesToFreeze(ages, 1600000000)).To(BeEmpty())
+	})
+})
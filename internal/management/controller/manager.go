@@ -46,6 +46,22 @@ type InstanceReconciler struct {
 	systemInitialization  *concurrency.Executed
 	firstReconcileDone    atomic.Bool
 	metricsServerExporter *metricserver.Exporter
+	// the checksum of the monitoring queries applied to the exporter
+	monitoringQueriesChecksum string
+
+	// whether the collation versions have been checked since the
+	// instance manager started as, or became, the primary
+	collationVersionsChecked atomic.Bool
+	// whether the indexes affected by a collation version
+	// mismatch are being rebuilt
+	collationReindexRunning atomic.Bool
+
+	// when the transaction ID wraparound risk has been checked for
+	// the last time
+	wraparoundCheckedAt atomic.Time
+	// whether the emergency vacuum of the databases at risk of
+	// transaction ID wraparound is running
+	emergencyVacuumRunning atomic.Bool
 }
 
 // NewInstanceReconciler creates a new instance reconciler
@@ -19,6 +19,7 @@ package roles
 import (
 	"context"
 	"database/sql"
+	"maps"
 	"reflect"
 	"sort"
 
@@ -31,21 +32,22 @@ import (
 // The password management in the apiv1.RoleConfiguration assumes the use of Secrets,
 // so cannot cleanly be mapped to Postgres
 type DatabaseRole struct {
-	Name            string           `json:"name"`
-	Comment         string           `json:"comment,omitempty"`
-	Superuser       bool             `json:"superuser,omitempty"`
-	CreateDB        bool             `json:"createdb,omitempty"`
-	CreateRole      bool             `json:"createrole,omitempty"`
-	Inherit         bool             `json:"inherit,omitempty"` // defaults to true
-	Login           bool             `json:"login,omitempty"`
-	Replication     bool             `json:"replication,omitempty"`
-	BypassRLS       bool             `json:"bypassrls,omitempty"` // Row-Level Security
-	ignorePassword  bool             `json:"-"`
-	ConnectionLimit int64            `json:"connectionLimit,omitempty"` // default is -1
-	ValidUntil      pgtype.Timestamp `json:"validUntil,omitempty"`
-	InRoles         []string         `json:"inRoles,omitempty"`
-	password        sql.NullString   `json:"-"`
-	transactionID   int64            `json:"-"`
+	Name            string            `json:"name"`
+	Comment         string            `json:"comment,omitempty"`
+	Superuser       bool              `json:"superuser,omitempty"`
+	CreateDB        bool              `json:"createdb,omitempty"`
+	CreateRole      bool              `json:"createrole,omitempty"`
+	Inherit         bool              `json:"inherit,omitempty"` // defaults to true
+	Login           bool              `json:"login,omitempty"`
+	Replication     bool              `json:"replication,omitempty"`
+	BypassRLS       bool              `json:"bypassrls,omitempty"` // Row-Level Security
+	ignorePassword  bool              `json:"-"`
+	ConnectionLimit int64             `json:"connectionLimit,omitempty"` // default is -1
+	ValidUntil      pgtype.Timestamp  `json:"validUntil,omitempty"`
+	InRoles         []string          `json:"inRoles,omitempty"`
+	Timeouts        map[string]string `json:"timeouts,omitempty"`
+	password        sql.NullString    `json:"-"`
+	transactionID   int64             `json:"-"`
 }
 
 // passwordNeedsUpdating evaluates whether a DatabaseRole needs to be updated
@@ -75,6 +77,16 @@ func (d *DatabaseRole) isInSameRolesAs(inSpec apiv1.RoleConfiguration) bool {
 	return reflect.DeepEqual(d.InRoles, inSpec.InRoles)
 }
 
+// hasSameTimeoutsAs checks the default timeouts of the sessions of the role,
+// which are only managed when the spec contains the timeouts section
+func (d *DatabaseRole) hasSameTimeoutsAs(inSpec apiv1.RoleConfiguration) bool {
+	if inSpec.Timeouts == nil {
+		return true
+	}
+
+	return maps.Equal(d.Timeouts, inSpec.Timeouts.ToParameters())
+}
+
 func (d *DatabaseRole) hasSameValidUntilAs(inSpec apiv1.RoleConfiguration) bool {
 	if inSpec.ValidUntil == nil {
 		return !d.ValidUntil.Valid || d.ValidUntil.InfinityModifier == pgtype.Infinity
@@ -142,4 +154,7 @@ type RoleManager interface {
 	UpdateMembership(ctx context.Context, role DatabaseRole, rolesToGrant []string, rolesToRevoke []string) error
 	// GetParentRoles returns the roles the given role is a member of
 	GetParentRoles(ctx context.Context, role DatabaseRole) ([]string, error)
+	// UpdateTimeouts sets the default timeouts of the sessions of the role,
+	// resetting the ones which are not defined
+	UpdateTimeouts(ctx context.Context, role DatabaseRole) error
 }
@@ -102,6 +102,26 @@ var _ = Describe("DatabaseRole implementation test", func() {
 		Expect(res).To(BeFalse())
 	})
 
+	It("Detects whether the timeouts of the role are the same", func() {
+		role := DatabaseRole{
+			Name:     "abc",
+			Timeouts: map[string]string{"statement_timeout": "30s"},
+		}
+		Expect(role.hasSameTimeoutsAs(apiv1.RoleConfiguration{Name: "abc"})).To(BeTrue())
+		Expect(role.hasSameTimeoutsAs(apiv1.RoleConfiguration{
+			Name:     "abc",
+			Timeouts: &apiv1.SessionTimeouts{StatementTimeout: "30s"},
+		})).To(BeTrue())
+		Expect(role.hasSameTimeoutsAs(apiv1.RoleConfiguration{
+			Name:     "abc",
+			Timeouts: &apiv1.SessionTimeouts{StatementTimeout: "30s", LockTimeout: "5s"},
+		})).To(BeFalse())
+		Expect(role.hasSameTimeoutsAs(apiv1.RoleConfiguration{
+			Name:     "abc",
+			Timeouts: &apiv1.SessionTimeouts{},
+		})).To(BeFalse())
+	})
+
 	It("Detects that spec and db role have the same ValidUntil", func() {
 		role := DatabaseRole{
 			Name:       "abc",
@@ -27,6 +27,7 @@ import (
 	"github.com/lib/pq"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 // PostgresRoleManager is a RoleManager for a database instance
@@ -53,12 +54,14 @@ func (sm PostgresRoleManager) List(
 		`SELECT rolname, rolsuper, rolinherit, rolcreaterole, rolcreatedb, 
        			rolcanlogin, rolreplication, rolconnlimit, rolpassword, rolvaliduntil, rolbypassrls,
 				pg_catalog.shobj_description(auth.oid, 'pg_authid') as comment, auth.xmin,
-				mem.inroles
+				mem.inroles, setting.setconfig
 		FROM pg_catalog.pg_authid as auth
 		LEFT JOIN (
 			SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
 			FROM pg_auth_members GROUP BY member
 		) mem ON member = oid
+		LEFT JOIN pg_catalog.pg_db_role_setting as setting
+			ON setting.setrole = auth.oid AND setting.setdatabase = 0
 		WHERE rolname not like 'pg\_%'`)
 	if err != nil {
 		return nil, wrapErr(err)
@@ -73,7 +76,7 @@ func (sm PostgresRoleManager) List(
 	for rows.Next() {
 		var comment sql.NullString
 		var role DatabaseRole
-		var inRoles pq.StringArray
+		var inRoles, settings pq.StringArray
 		err := rows.Scan(
 			&role.Name,
 			&role.Superuser,
@@ -89,6 +92,7 @@ func (sm PostgresRoleManager) List(
 			&comment,
 			&role.transactionID,
 			&inRoles,
+			&settings,
 		)
 		if err != nil {
 			return nil, wrapErr(err)
@@ -98,6 +102,7 @@ func (sm PostgresRoleManager) List(
 		}
 
 		role.InRoles = inRoles
+		role.Timeouts = postgres.GetTimeoutSettings(settings)
 
 		roles = append(roles, role)
 	}
@@ -155,6 +160,20 @@ func (sm PostgresRoleManager) Create(ctx context.Context, role DatabaseRole) err
 		return wrapErr(err)
 	}
 
+	for _, name := range postgres.SessionTimeoutParameters {
+		value, ok := role.Timeouts[name]
+		if !ok {
+			continue
+		}
+		query.Reset()
+		query.WriteString(fmt.Sprintf("ALTER ROLE %s SET %s TO %s",
+			pgx.Identifier{role.Name}.Sanitize(), name, pq.QuoteLiteral(value)))
+
+		if _, err := sm.superUserDB.ExecContext(ctx, query.String()); err != nil {
+			return wrapErr(err)
+		}
+	}
+
 	if len(role.Comment) > 0 {
 		query.Reset()
 		query.WriteString(fmt.Sprintf("COMMENT ON ROLE %s IS %s",
@@ -313,6 +332,29 @@ func (sm PostgresRoleManager) GetParentRoles(
 	return parentRoles, nil
 }
 
+// UpdateTimeouts of the role
+func (sm PostgresRoleManager) UpdateTimeouts(ctx context.Context, role DatabaseRole) error {
+	contextLog := log.FromContext(ctx).WithName("roles_reconciler")
+	contextLog.Trace("Invoked", "role", role)
+	wrapErr := func(err error) error {
+		return fmt.Errorf("while updating timeouts for role %s with DRM: %w", role.Name, err)
+	}
+
+	for _, name := range postgres.SessionTimeoutParameters {
+		query := fmt.Sprintf("ALTER ROLE %s RESET %s", pgx.Identifier{role.Name}.Sanitize(), name)
+		if value, ok := role.Timeouts[name]; ok {
+			query = fmt.Sprintf("ALTER ROLE %s SET %s TO %s",
+				pgx.Identifier{role.Name}.Sanitize(), name, pq.QuoteLiteral(value))
+		}
+		contextLog.Debug("Updating timeout", "query", query)
+		if _, err := sm.superUserDB.ExecContext(ctx, query); err != nil {
+			return wrapErr(err)
+		}
+	}
+
+	return nil
+}
+
 func appendInRoleOptions(role DatabaseRole, query *strings.Builder) {
 	if len(role.InRoles) > 0 {
 		query.WriteString(fmt.Sprintf(" IN ROLE %s ", strings.Join(role.InRoles, ",")))
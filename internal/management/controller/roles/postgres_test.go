@@ -129,12 +129,14 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 	expectedSelStmt := `SELECT rolname, rolsuper, rolinherit, rolcreaterole, rolcreatedb, 
        			rolcanlogin, rolreplication, rolconnlimit, rolpassword, rolvaliduntil, rolbypassrls,
 				pg_catalog.shobj_description(auth.oid, 'pg_authid') as comment, auth.xmin,
-				mem.inroles
+				mem.inroles, setting.setconfig
 		FROM pg_catalog.pg_authid as auth
 		LEFT JOIN (
 			SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
 			FROM pg_auth_members GROUP BY member
 		) mem ON member = oid
+		LEFT JOIN pg_catalog.pg_db_role_setting as setting
+			ON setting.setrole = auth.oid AND setting.setdatabase = 0
 		WHERE rolname not like 'pg\_%'`
 
 	expectedMembershipStmt := `SELECT mem.inroles 
@@ -156,22 +158,23 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 		rows := sqlmock.NewRows([]string{
 			"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
 			"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
-			"xmin", "inroles",
+			"xmin", "inroles", "setconfig",
 		}).
 			AddRow("postgres", true, false, true, true, true, false, -1, []byte("12345"),
-				nil, false, []byte("This is postgres user"), 11, []byte("{}")).
+				nil, false, []byte("This is postgres user"), 11, []byte("{}"), nil).
 			AddRow("streaming_replica", false, false, true, true, false, true, 10, []byte("54321"),
 				pgtype.Timestamp{
 					Valid:            true,
 					Time:             testDate,
 					InfinityModifier: pgtype.Finite,
-				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`)).
+				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`),
+				[]byte(`{statement_timeout=30s,search_path=public}`)).
 			AddRow("future_man", false, false, true, true, false, true, 10, []byte("54321"),
 				pgtype.Timestamp{
 					Valid:            true,
 					Time:             time.Time{},
 					InfinityModifier: pgtype.Infinity,
-				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`))
+				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`), nil)
 		mock.ExpectQuery(expectedSelStmt).WillReturnRows(rows)
 		mock.ExpectExec("CREATE ROLE foo").WillReturnResult(sqlmock.NewResult(11, 1))
 		roles, err := prm.List(ctx)
@@ -218,6 +221,7 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 				"role1",
 				"role2",
 			},
+			Timeouts: map[string]string{"statement_timeout": "30s"},
 		}))
 	})
 	It("List returns error if there is a problem with the DB", func(ctx context.Context) {
@@ -246,6 +250,48 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 		err = prm.Create(ctx, internalWantedRole.toDatabaseRole())
 		Expect(err).ShouldNot(HaveOccurred())
 	})
+	It("Create will set the timeouts of the role", func(ctx context.Context) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		prm := NewPostgresRoleManager(db)
+
+		mock.ExpectExec(wantedRoleExpectedCrtStmt).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectExec(`ALTER ROLE "foo" SET idle_in_transaction_session_timeout TO '5min'`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectExec(`ALTER ROLE "foo" SET statement_timeout TO '30s'`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectExec(wantedRoleCommentStmt).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+
+		dbRole := internalWantedRole.toDatabaseRole()
+		dbRole.Timeouts = map[string]string{
+			"idle_in_transaction_session_timeout": "5min",
+			"statement_timeout":                   "30s",
+		}
+		err = prm.Create(ctx, dbRole)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+	It("UpdateTimeouts will set the defined timeouts and reset the others", func(ctx context.Context) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		prm := NewPostgresRoleManager(db)
+
+		mock.ExpectExec(`ALTER ROLE "foo" RESET idle_in_transaction_session_timeout`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectExec(`ALTER ROLE "foo" SET statement_timeout TO '30s'`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectExec(`ALTER ROLE "foo" RESET lock_timeout`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+
+		err = prm.UpdateTimeouts(ctx, DatabaseRole{
+			Name:     "foo",
+			Timeouts: map[string]string{"statement_timeout": "30s"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
 	It("Create will return error if there is a problem creating the role in the DB", func(ctx context.Context) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		Expect(err).ToNot(HaveOccurred())
@@ -66,6 +66,7 @@ func (role roleConfigurationAdapter) toDatabaseRole() DatabaseRole {
 		BypassRLS:       role.BypassRLS,
 		ConnectionLimit: role.ConnectionLimit,
 		InRoles:         role.InRoles,
+		Timeouts:        role.Timeouts.ToParameters(),
 	}
 	switch {
 	case role.ValidUntil != nil:
@@ -96,6 +97,7 @@ func (r rolesByAction) convertToRolesByStatus() rolesByStatus {
 		roleUpdate:            apiv1.RoleStatusPendingReconciliation,
 		roleSetComment:        apiv1.RoleStatusPendingReconciliation,
 		roleUpdateMemberships: apiv1.RoleStatusPendingReconciliation,
+		roleUpdateTimeouts:    apiv1.RoleStatusPendingReconciliation,
 		roleIsReconciled:      apiv1.RoleStatusReconciled,
 		roleIgnore:            apiv1.RoleStatusNotManaged,
 		roleIsReserved:        apiv1.RoleStatusReserved,
@@ -161,6 +163,11 @@ func evaluateNextRoleActions(
 				RoleConfiguration: inSpec,
 			}
 			rolesByAction[roleUpdateMemberships] = append(rolesByAction[roleUpdateMemberships], internalRole)
+		case isInSpec && !role.hasSameTimeoutsAs(inSpec):
+			internalRole := roleConfigurationAdapter{
+				RoleConfiguration: inSpec,
+			}
+			rolesByAction[roleUpdateTimeouts] = append(rolesByAction[roleUpdateTimeouts], internalRole)
 		case !isInSpec:
 			rolesByAction[roleIgnore] = append(rolesByAction[roleIgnore],
 				roleAdapterFromName(role.Name))
@@ -47,6 +47,7 @@ const (
 	roleIsReserved        roleAction = "RESERVED"
 	roleSetComment        roleAction = "SET_COMMENT"
 	roleUpdateMemberships roleAction = "UPDATE_MEMBERSHIPS"
+	roleUpdateTimeouts    roleAction = "UPDATE_TIMEOUTS"
 )
 
 // A RoleSynchronizer is a Kubernetes manager.Runnable
@@ -275,6 +276,11 @@ func (sr *RoleSynchronizer) applyRoleActions(
 				}
 				err = roleManager.UpdateMembership(ctx, dbRole, grants, revokes)
 				handleRoleError(err, role.Name, action)
+			case roleUpdateTimeouts:
+				// NOTE: the settings of a role are not stored in pg_authid,
+				// so they do not alter its TransactionID
+				err := roleManager.UpdateTimeouts(ctx, role.toDatabaseRole())
+				handleRoleError(err, role.Name, action)
 			}
 		}
 	}
@@ -128,6 +128,16 @@ func (m *mockRoleManager) GetParentRoles(_ context.Context, role DatabaseRole) (
 	return nil, nil
 }
 
+func (m *mockRoleManager) UpdateTimeouts(_ context.Context, role DatabaseRole) error {
+	m.callHistory = append(m.callHistory, funcCall{"updateTimeouts", role.Name})
+	_, found := m.roles[role.Name]
+	if !found {
+		return fmt.Errorf("trying to update timeouts of unknown role: %s", role.Name)
+	}
+	m.roles[role.Name] = role
+	return nil
+}
+
 // mock.ExpectExec(unWantedRoleExpectedDelStmt).
 // WillReturnError(&pgconn.PgError{Code: "2BP01"})
 
@@ -227,6 +237,11 @@ func (m *mockRoleManagerWithError) GetParentRoles(_ context.Context, role Databa
 	return nil, nil
 }
 
+func (m *mockRoleManagerWithError) UpdateTimeouts(_ context.Context, role DatabaseRole) error {
+	m.callHistory = append(m.callHistory, funcCall{"updateTimeouts", role.Name})
+	return nil
+}
+
 var _ = Describe("Role synchronizer tests", func() {
 	roleSynchronizer := RoleSynchronizer{
 		instance: &postgres.Instance{
@@ -380,6 +395,34 @@ var _ = Describe("Role synchronizer tests", func() {
 				funcCall{"updateComment", "edb_test"}))
 		})
 
+		It("it will call the updateTimeouts method", func(ctx context.Context) {
+			trueValue := true
+			managedConf := apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{
+					{
+						Name:      "edb_test",
+						Superuser: true,
+						Inherit:   &trueValue,
+						Timeouts:  &apiv1.SessionTimeouts{StatementTimeout: "30s"},
+					},
+				},
+			}
+			rm := mockRoleManager{
+				roles: map[string]DatabaseRole{
+					"edb_test": {
+						Name:      "edb_test",
+						Superuser: true,
+						Inherit:   true,
+						Timeouts:  map[string]string{"statement_timeout": "1min"},
+					},
+				},
+			}
+			_, _, err := roleSynchronizer.synchronizeRoles(ctx, &rm, &managedConf, map[string]apiv1.PasswordState{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rm.callHistory).To(ConsistOf(funcCall{"list", ""},
+				funcCall{"updateTimeouts", "edb_test"}))
+		})
+
 		It("it will no-op if the roles are reconciled", func(ctx context.Context) {
 			trueValue := true
 			managedConf := apiv1.ManagedConfiguration{
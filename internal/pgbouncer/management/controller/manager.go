@@ -20,7 +20,9 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -45,6 +47,7 @@ type PgBouncerReconciler struct {
 	poolerWatch          watch.Interface
 	instance             PgBouncerInstanceInterface
 	poolerNamespacedName types.NamespacedName
+	podName              string
 }
 
 // NewPgBouncerReconciler creates a new pgbouncer reconciler
@@ -54,10 +57,17 @@ func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBounc
 		return nil, err
 	}
 
+	// The hostname of the PgBouncer pods is the name of the pod itself
+	podName, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
 	return &PgBouncerReconciler{
 		client:               client,
 		instance:             NewPgBouncerInstance(),
 		poolerNamespacedName: poolerNamespacedName,
+		podName:              podName,
 	}, nil
 }
 
@@ -134,14 +144,46 @@ func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event)
 		return fmt.Errorf("while reconciling configuration: %w", err)
 	}
 
+	if err := r.reportReloadedSecrets(ctx, pooler); err != nil {
+		return fmt.Errorf("while reporting the reloaded secrets: %w", err)
+	}
+
 	return r.synchronizePause(pooler)
 }
 
+// reportReloadedSecrets writes in the Pooler status the hash of the
+// secrets versions this instance reloaded, allowing the operator to know
+// when every instance is using the current certificates
+func (r *PgBouncerReconciler) reportReloadedSecrets(ctx context.Context, pooler *apiv1.Pooler) error {
+	secretsHash, err := pooler.GetSecretsHash()
+	if err != nil {
+		return err
+	}
+
+	if pooler.Status.ReloadedSecrets[r.podName] == secretsHash {
+		return nil
+	}
+
+	// A merge patch only touches the entry of this instance, so the
+	// PgBouncer instances don't conflict with each other
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"reloadedSecrets": map[string]string{r.podName: secretsHash},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.client.Status().Patch(ctx, pooler, ctrl.RawPatch(types.MergePatchType, patch))
+}
+
 // synchronizePause ensure that the pause flag inside the Pooler
-// specification matches the PgBouncer status
+// specification, or the pause requested by the operator while the
+// primary of the cluster changes, matches the PgBouncer status
 func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
 	isPaused := r.instance.Paused()
-	shouldBePaused := pooler.Spec.PgBouncer.IsPaused()
+	shouldBePaused := pooler.Spec.PgBouncer.IsPaused() || pooler.IsPausedForSwitchover()
 	if shouldBePaused && !isPaused {
 		if err := r.instance.Pause(); err != nil {
 			return fmt.Errorf("while pausing instance: %w", err)
@@ -195,7 +237,7 @@ func (r *PgBouncerReconciler) writePgBouncerConfig(ctx context.Context, pooler *
 	// the secrets we require.
 	// This is why we are retrying the loading of the secrets.
 	if err := retry.OnError(retry.DefaultBackoff, apierrs.IsForbidden, func() error {
-		secrets, err = getSecrets(ctx, r.GetClient(), pooler)
+		secrets, err = GetSecrets(ctx, r.GetClient(), pooler)
 		return err
 	}); err != nil {
 		return false, fmt.Errorf("while reading secrets: %w", err)
@@ -28,19 +28,14 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 )
 
-// getSecrets loads the data needed to generate the configuration
+// GetSecrets loads the data needed to generate the configuration
 // from Kubernetes and a Pooler resource
-func getSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (*config.Secrets, error) {
+func GetSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (*config.Secrets, error) {
 	if pooler.Status.Secrets == nil {
 		return nil, fmt.Errorf("status not populated yet")
 	}
 
-	var (
-		authQuerySecret  corev1.Secret
-		serverCASecret   corev1.Secret
-		serverCertSecret corev1.Secret
-		clientCASecret   corev1.Secret
-	)
+	var authQuerySecret corev1.Secret
 
 	authQuerySecretName := pooler.GetAuthQuerySecretName()
 	if err := client.Get(ctx,
@@ -52,28 +47,41 @@ func getSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (
 		return nil, fmt.Errorf("while getting auth query secret %s: %w", authQuerySecretName, err)
 	}
 
-	if err := client.Get(ctx,
-		types.NamespacedName{Name: pooler.Status.Secrets.ServerCA.Name, Namespace: pooler.Namespace},
-		&serverCASecret); err != nil {
+	// A Pooler working on an external cluster has no certificate for
+	// the client connections, and the CA of the server is optional
+	serverCASecret, err := getOptionalSecret(ctx, client, pooler.Namespace, pooler.Status.Secrets.ServerCA.Name)
+	if err != nil {
 		return nil, fmt.Errorf("while getting server CA secret: %w", err)
 	}
 
-	if err := client.Get(ctx,
-		types.NamespacedName{Name: pooler.Status.Secrets.ServerTLS.Name, Namespace: pooler.Namespace},
-		&serverCertSecret); err != nil {
+	serverCertSecret, err := getOptionalSecret(ctx, client, pooler.Namespace, pooler.Status.Secrets.ServerTLS.Name)
+	if err != nil {
 		return nil, fmt.Errorf("while getting server cert secret: %w", err)
 	}
 
-	if err := client.Get(ctx,
-		types.NamespacedName{Name: pooler.Status.Secrets.ClientCA.Name, Namespace: pooler.Namespace},
-		&clientCASecret); err != nil {
+	clientCASecret, err := getOptionalSecret(ctx, client, pooler.Namespace, pooler.Status.Secrets.ClientCA.Name)
+	if err != nil {
 		return nil, fmt.Errorf("while getting client CA secret: %w", err)
 	}
 
 	return &config.Secrets{
 		AuthQuery: &authQuerySecret,
-		ServerCA:  &serverCASecret,
-		Client:    &serverCertSecret,
-		ClientCA:  &clientCASecret,
+		ServerCA:  serverCASecret,
+		Client:    serverCertSecret,
+		ClientCA:  clientCASecret,
 	}, nil
 }
+
+// getOptionalSecret gets a secret, returning nil when the name is empty
+func getOptionalSecret(ctx context.Context, client ctrl.Client, namespace, name string) (*corev1.Secret, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var secret corev1.Secret
+	if err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret); err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
@@ -41,7 +41,7 @@ var _ = Describe("getSecrets tests", func() {
 		It("should return error", func(ctx context.Context) {
 			pooler.Status.Secrets = nil
 
-			_, err := getSecrets(ctx, client, pooler)
+			_, err := GetSecrets(ctx, client, pooler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("status not populated yet"))
@@ -50,7 +50,7 @@ var _ = Describe("getSecrets tests", func() {
 
 	Context("when all secrets are found", func() {
 		It("should return secrets without error", func(ctx context.Context) {
-			res, err := getSecrets(ctx, client, pooler)
+			res, err := GetSecrets(ctx, client, pooler)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(res.ClientCA.Name).To(Equal(clientCAName))
@@ -66,9 +66,27 @@ var _ = Describe("getSecrets tests", func() {
 		})
 
 		It("should return error", func(ctx context.Context) {
-			_, err := getSecrets(ctx, client, pooler)
+			_, err := GetSecrets(ctx, client, pooler)
 
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Context("when the pooler works on an external cluster", func() {
+		BeforeEach(func() {
+			pooler.Status.Secrets.ServerTLS = apiv1.SecretVersion{}
+			pooler.Status.Secrets.ClientCA = apiv1.SecretVersion{}
+			pooler.Status.Secrets.ServerCA = apiv1.SecretVersion{}
+		})
+
+		It("should skip the certificates that are not set", func(ctx context.Context) {
+			res, err := GetSecrets(ctx, client, pooler)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.AuthQuery.Name).To(Equal(authQueryName))
+			Expect(res.ClientCA).To(BeNil())
+			Expect(res.Client).To(BeNil())
+			Expect(res.ServerCA).To(BeNil())
+		})
+	})
 })
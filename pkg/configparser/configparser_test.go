@@ -38,6 +38,10 @@ type FakeData struct {
 
 	// EnablePodDebugging enable debugging mode in new generated pods
 	EnablePodDebugging bool `json:"enablePodDebugging" env:"POD_DEBUG"`
+
+	// MaxConcurrentRollouts is the maximum number of clusters that can be
+	// rolled out at the same time
+	MaxConcurrentRollouts int `json:"maxConcurrentRollouts" env:"MAX_CONCURRENT_ROLLOUTS"`
 }
 
 var defaultInheritedAnnotations = []string{
@@ -84,6 +88,22 @@ var _ = Describe("Data test suite", func() {
 		Expect(config.InheritedLabels).To(Equal([]string{"alpha", "beta"}))
 	})
 
+	It("loads integer values", func() {
+		config := &FakeData{}
+		config.readConfigMap(map[string]string{
+			"MAX_CONCURRENT_ROLLOUTS": "3",
+		}, NewFakeEnvironment(nil))
+		Expect(config.MaxConcurrentRollouts).To(Equal(3))
+	})
+
+	It("skips invalid integer values", func() {
+		config := &FakeData{}
+		config.readConfigMap(map[string]string{
+			"MAX_CONCURRENT_ROLLOUTS": "three",
+		}, NewFakeEnvironment(nil))
+		Expect(config.MaxConcurrentRollouts).To(BeZero())
+	})
+
 	It("handles correctly default values of slices", func() {
 		config := &FakeData{}
 		config.readConfigMap(nil, NewFakeEnvironment(nil))
@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupplugin
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
+)
+
+const (
+	// SocketDirectory is the directory, shared between the PostgreSQL
+	// container and the plugin sidecar, containing the plugin sockets
+	SocketDirectory = "/plugins"
+
+	// SocketEnvVar is the environment variable containing the path of
+	// the socket where the plugin is expected to listen
+	SocketEnvVar = "PLUGIN_SOCKET"
+)
+
+// SocketPath is the path of the socket where the plugin with
+// the passed name listens
+func SocketPath(pluginName string) string {
+	return path.Join(SocketDirectory, pluginName+".sock")
+}
+
+// Client is a connection to a backup plugin
+type Client struct {
+	conn     *grpc.ClientConn
+	provider proto.BackupProviderClient
+}
+
+// Connect creates a connection to the plugin listening on the passed socket
+func Connect(socketPath string) (*Client, error) {
+	conn, err := grpc.Dial(
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn:     conn,
+		provider: proto.NewBackupProviderClient(conn),
+	}, nil
+}
+
+// ConnectToClusterPlugin creates a connection to the backup plugin
+// configured in the passed cluster
+func ConnectToClusterPlugin(cluster *apiv1.Cluster) (*Client, error) {
+	return Connect(SocketPath(cluster.GetBackupPlugin().Name))
+}
+
+// Close closes the connection to the plugin
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetMetadata returns the name and the version of the plugin
+func (c *Client) GetMetadata(ctx context.Context) (*proto.GetMetadataResponse, error) {
+	return c.provider.GetMetadata(ctx, &proto.GetMetadataRequest{})
+}
+
+// ArchiveWAL asks the plugin to archive a WAL file
+func (c *Client) ArchiveWAL(ctx context.Context, cluster *apiv1.Cluster, sourceFileName string) error {
+	clusterDefinition, err := json.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.provider.ArchiveWAL(ctx, &proto.ArchiveWALRequest{
+		ClusterDefinition: clusterDefinition,
+		SourceFileName:    sourceFileName,
+		Parameters:        cluster.GetBackupPlugin().Parameters,
+	})
+	return err
+}
+
+// Backup asks the plugin to take a base backup of the instance
+func (c *Client) Backup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+) (*proto.BackupResponse, error) {
+	clusterDefinition, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	backupDefinition, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.provider.Backup(ctx, &proto.BackupRequest{
+		ClusterDefinition: clusterDefinition,
+		BackupDefinition:  backupDefinition,
+		Parameters:        cluster.GetBackupPlugin().Parameters,
+	})
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupplugin
+
+import (
+	"context"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeProvider struct {
+	proto.UnimplementedBackupProviderServer
+
+	archivedWAL string
+	cluster     *apiv1.Cluster
+	backup      *apiv1.Backup
+	parameters  map[string]string
+}
+
+func (f *fakeProvider) GetMetadata(
+	context.Context,
+	*proto.GetMetadataRequest,
+) (*proto.GetMetadataResponse, error) {
+	return &proto.GetMetadataResponse{Name: "fake", Version: "1.0.0"}, nil
+}
+
+func (f *fakeProvider) ArchiveWAL(
+	_ context.Context,
+	request *proto.ArchiveWALRequest,
+) (*proto.ArchiveWALResponse, error) {
+	cluster, err := DecodeCluster(request.ClusterDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	f.cluster = cluster
+	f.archivedWAL = request.SourceFileName
+	f.parameters = request.Parameters
+	return &proto.ArchiveWALResponse{}, nil
+}
+
+func (f *fakeProvider) Backup(
+	_ context.Context,
+	request *proto.BackupRequest,
+) (*proto.BackupResponse, error) {
+	backup, err := DecodeBackup(request.BackupDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	f.backup = backup
+	return &proto.BackupResponse{
+		BackupId: "20231010T101010",
+		BeginWal: "000000010000000000000002",
+		EndWal:   "000000010000000000000003",
+	}, nil
+}
+
+var _ = Describe("Backup plugin", func() {
+	var (
+		provider *fakeProvider
+		client   *Client
+		cluster  *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		socketPath := path.Join(GinkgoT().TempDir(), "fake.sock")
+		provider = &fakeProvider{}
+		done := make(chan error, 1)
+		go func() {
+			done <- Serve(ctx, socketPath, provider)
+		}()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		var err error
+		client, err = Connect(socketPath)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(client.Close)
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					Plugin: &apiv1.BackupPluginConfiguration{
+						Name:       "fake",
+						Image:      "fake:latest",
+						Parameters: map[string]string{"bucket": "test"},
+					},
+				},
+			},
+		}
+	})
+
+	It("returns the metadata of the plugin", func(ctx SpecContext) {
+		metadata, err := client.GetMetadata(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.Name).To(Equal("fake"))
+		Expect(metadata.Version).To(Equal("1.0.0"))
+	})
+
+	It("sends the cluster and the parameters when archiving a WAL", func(ctx SpecContext) {
+		err := client.ArchiveWAL(ctx, cluster, "pg_wal/000000010000000000000001")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider.archivedWAL).To(Equal("pg_wal/000000010000000000000001"))
+		Expect(provider.cluster.Name).To(Equal("cluster-example"))
+		Expect(provider.parameters).To(HaveKeyWithValue("bucket", "test"))
+	})
+
+	It("returns the backup information from the plugin", func(ctx SpecContext) {
+		backup := &apiv1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "backup-example"}}
+		result, err := client.Backup(ctx, cluster, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider.backup.Name).To(Equal("backup-example"))
+		Expect(result.BackupId).To(Equal("20231010T101010"))
+		Expect(result.EndWal).To(Equal("000000010000000000000003"))
+	})
+})
+
+var _ = Describe("Socket path", func() {
+	It("is placed in the shared socket directory", func() {
+		Expect(SocketPath("barman-cloud")).To(Equal("/plugins/barman-cloud.sock"))
+	})
+})
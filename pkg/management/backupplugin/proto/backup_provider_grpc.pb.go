@@ -0,0 +1,204 @@
+//
+//Copyright The CloudNativePG Contributors
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: backup_provider.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BackupProvider_GetMetadata_FullMethodName = "/cnpg.backupplugin.v1.BackupProvider/GetMetadata"
+	BackupProvider_ArchiveWAL_FullMethodName  = "/cnpg.backupplugin.v1.BackupProvider/ArchiveWAL"
+	BackupProvider_Backup_FullMethodName      = "/cnpg.backupplugin.v1.BackupProvider/Backup"
+)
+
+// BackupProviderClient is the client API for BackupProvider service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BackupProviderClient interface {
+	// GetMetadata returns the name and the version of the plugin
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	// ArchiveWAL archives a WAL file or a timeline history file
+	ArchiveWAL(ctx context.Context, in *ArchiveWALRequest, opts ...grpc.CallOption) (*ArchiveWALResponse, error)
+	// Backup takes a physical base backup of the instance
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
+}
+
+type backupProviderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBackupProviderClient(cc grpc.ClientConnInterface) BackupProviderClient {
+	return &backupProviderClient{cc}
+}
+
+func (c *backupProviderClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, BackupProvider_GetMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backupProviderClient) ArchiveWAL(ctx context.Context, in *ArchiveWALRequest, opts ...grpc.CallOption) (*ArchiveWALResponse, error) {
+	out := new(ArchiveWALResponse)
+	err := c.cc.Invoke(ctx, BackupProvider_ArchiveWAL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backupProviderClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error) {
+	out := new(BackupResponse)
+	err := c.cc.Invoke(ctx, BackupProvider_Backup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BackupProviderServer is the server API for BackupProvider service.
+// All implementations must embed UnimplementedBackupProviderServer
+// for forward compatibility
+type BackupProviderServer interface {
+	// GetMetadata returns the name and the version of the plugin
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	// ArchiveWAL archives a WAL file or a timeline history file
+	ArchiveWAL(context.Context, *ArchiveWALRequest) (*ArchiveWALResponse, error)
+	// Backup takes a physical base backup of the instance
+	Backup(context.Context, *BackupRequest) (*BackupResponse, error)
+	mustEmbedUnimplementedBackupProviderServer()
+}
+
+// UnimplementedBackupProviderServer must be embedded to have forward compatible implementations.
+type UnimplementedBackupProviderServer struct {
+}
+
+func (UnimplementedBackupProviderServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedBackupProviderServer) ArchiveWAL(context.Context, *ArchiveWALRequest) (*ArchiveWALResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveWAL not implemented")
+}
+func (UnimplementedBackupProviderServer) Backup(context.Context, *BackupRequest) (*BackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (UnimplementedBackupProviderServer) mustEmbedUnimplementedBackupProviderServer() {}
+
+// UnsafeBackupProviderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BackupProviderServer will
+// result in compilation errors.
+type UnsafeBackupProviderServer interface {
+	mustEmbedUnimplementedBackupProviderServer()
+}
+
+func RegisterBackupProviderServer(s grpc.ServiceRegistrar, srv BackupProviderServer) {
+	s.RegisterService(&BackupProvider_ServiceDesc, srv)
+}
+
+func _BackupProvider_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupProviderServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackupProvider_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupProviderServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BackupProvider_ArchiveWAL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveWALRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupProviderServer).ArchiveWAL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackupProvider_ArchiveWAL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupProviderServer).ArchiveWAL(ctx, req.(*ArchiveWALRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BackupProvider_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackupProviderServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackupProvider_Backup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackupProviderServer).Backup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BackupProvider_ServiceDesc is the grpc.ServiceDesc for BackupProvider service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BackupProvider_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cnpg.backupplugin.v1.BackupProvider",
+	HandlerType: (*BackupProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler:    _BackupProvider_GetMetadata_Handler,
+		},
+		{
+			MethodName: "ArchiveWAL",
+			Handler:    _BackupProvider_ArchiveWAL_Handler,
+		},
+		{
+			MethodName: "Backup",
+			Handler:    _BackupProvider_Backup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "backup_provider.proto",
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// Serve exposes the passed backup provider on a Unix domain socket,
+// until the context is cancelled
+func Serve(ctx context.Context, socketPath string, provider proto.BackupProviderServer) error {
+	contextLogger := log.FromContext(ctx)
+
+	// A socket left behind by a previous execution of the plugin
+	// would prevent us from listening again
+	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	proto.RegisterBackupProviderServer(server, provider)
+
+	go func() {
+		<-ctx.Done()
+		contextLogger.Info("Stopping the backup plugin server")
+		server.GracefulStop()
+	}()
+
+	contextLogger.Info("Starting the backup plugin server", "socketPath", socketPath)
+	return server.Serve(listener)
+}
+
+// DecodeCluster decodes the definition of the Cluster as sent by
+// the instance manager
+func DecodeCluster(clusterDefinition []byte) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	if err := json.Unmarshal(clusterDefinition, &cluster); err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
+
+// DecodeBackup decodes the definition of the Backup as sent by
+// the instance manager
+func DecodeBackup(backupDefinition []byte) (*apiv1.Backup, error) {
+	var backup apiv1.Backup
+	if err := json.Unmarshal(backupDefinition, &backup); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
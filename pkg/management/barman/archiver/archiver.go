@@ -112,19 +112,30 @@ func (archiver *WALArchiver) DeleteFromSpool(walName string) (hasBeenDeleted boo
 	return true, archiver.spool.Remove(walName)
 }
 
-// ArchiveList archives a list of WAL files in parallel
+// ArchiveList archives a list of WAL files in parallel, uploading
+// at most maxParallel files at the same time
 func (archiver *WALArchiver) ArchiveList(
 	ctx context.Context,
 	walNames []string,
 	options []string,
+	maxParallel int,
 ) (result []WALArchiverResult) {
 	contextLog := log.FromContext(ctx)
 	result = make([]WALArchiverResult, len(walNames))
 
+	// The WAL files are started in order, so that the one requested by
+	// PostgreSQL is always the first to be uploaded
+	slots := make(chan struct{}, max(maxParallel, 1))
+
 	var waitGroup sync.WaitGroup
 	for idx := range walNames {
 		waitGroup.Add(1)
+		slots <- struct{}{}
 		go func(walIndex int) {
+			defer func() {
+				<-slots
+			}()
+
 			walStatus := &result[walIndex]
 			walStatus.WalName = walNames[walIndex]
 			walStatus.StartTime = time.Now()
@@ -180,8 +191,19 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
 	barmanCloudWalArchiveCmd.Env = archiver.env
+	// PostgreSQL passes the WAL file names relative to the data directory
+	barmanCloudWalArchiveCmd.Dir = archiver.pgDataDirectory
 
-	err := execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
+	// Every line of output is tagged with the WAL file it refers to, so that
+	// the output of parallel archivers can be told apart
+	walLogger := log.WithName(barmanCapabilities.BarmanCloudWalArchive).WithValues(
+		"walName", walName,
+		"startTime", time.Now(),
+	)
+	err := execlog.RunStreamingWithLogger(
+		barmanCloudWalArchiveCmd,
+		barmanCapabilities.BarmanCloudWalArchive,
+		walLogger)
 	if err != nil {
 		log.Error(err, "Error invoking "+barmanCapabilities.BarmanCloudWalArchive,
 			"walName", walName,
@@ -252,7 +274,16 @@ func (archiver *WALArchiver) CheckWalArchiveDestination(ctx context.Context, opt
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudCheckWalArchive, options...) // #nosec G204
 	barmanCloudWalArchiveCmd.Env = archiver.env
 
-	err = execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudCheckWalArchive)
+	// The output is tagged with the cluster it refers to, as the check
+	// can run while restoring a backup from another cluster
+	checkLogger := log.WithName(barmanCapabilities.BarmanCloudCheckWalArchive).WithValues(
+		"clusterName", archiver.cluster.Name,
+		"startTime", time.Now(),
+	)
+	err = execlog.RunStreamingWithLogger(
+		barmanCloudWalArchiveCmd,
+		barmanCapabilities.BarmanCloudCheckWalArchive,
+		checkLogger)
 	if err != nil {
 		contextLogger.Error(err, "Error invoking "+barmanCapabilities.BarmanCloudCheckWalArchive,
 			"currentPrimary", archiver.cluster.Status.CurrentPrimary,
@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeWalArchiveScript replaces barman-cloud-wal-archive, recording how
+// many copies of itself are running when it starts
+const fakeWalArchiveScript = `#!/bin/sh
+touch "%[1]s/running/$$"
+ls "%[1]s/running" | wc -l >> "%[1]s/concurrency"
+sleep 0.3
+rm "%[1]s/running/$$"
+`
+
+var _ = Describe("WAL archiver", func() {
+	var (
+		workDir  string
+		archiver *WALArchiver
+		walNames []string
+	)
+
+	BeforeEach(func() {
+		workDir = GinkgoT().TempDir()
+		Expect(os.Mkdir(filepath.Join(workDir, "running"), 0o700)).To(Succeed())
+
+		binDir := filepath.Join(workDir, "bin")
+		Expect(os.Mkdir(binDir, 0o700)).To(Succeed())
+		Expect(os.WriteFile(
+			filepath.Join(binDir, barmanCapabilities.BarmanCloudWalArchive),
+			[]byte(fmt.Sprintf(fakeWalArchiveScript, workDir)),
+			0o700, // #nosec G306
+		)).To(Succeed())
+		GinkgoT().Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		var err error
+		archiver, err = New(context.Background(), &apiv1.Cluster{}, nil,
+			filepath.Join(workDir, "spool"), workDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		walNames = nil
+		for idx := 1; idx <= 6; idx++ {
+			walNames = append(walNames, fmt.Sprintf("pg_wal/00000001000000000000000%d", idx))
+		}
+	})
+
+	readConcurrency := func() []int {
+		content, err := os.ReadFile(filepath.Join(workDir, "concurrency")) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+
+		var result []int
+		for _, line := range strings.Fields(string(content)) {
+			value, err := strconv.Atoi(line)
+			Expect(err).ToNot(HaveOccurred())
+			result = append(result, value)
+		}
+		return result
+	}
+
+	It("doesn't upload more than maxParallel files at the same time", func() {
+		results := archiver.ArchiveList(context.Background(), walNames, nil, 2)
+		Expect(results).To(HaveLen(len(walNames)))
+		for idx, result := range results {
+			Expect(result.WalName).To(Equal(walNames[idx]))
+			Expect(result.Err).ToNot(HaveOccurred())
+		}
+
+		concurrency := readConcurrency()
+		Expect(concurrency).To(HaveLen(len(walNames)))
+		Expect(concurrency).To(HaveEach(BeNumerically("<=", 2)))
+		Expect(concurrency).To(ContainElement(2))
+
+		// Every file but the requested one is recorded in the spool
+		for _, walName := range walNames[1:] {
+			Expect(archiver.spool.Contains(walName)).To(BeTrue())
+		}
+		Expect(archiver.spool.Contains(walNames[0])).To(BeFalse())
+	})
+
+	It("uploads one file at a time when maxParallel is not positive", func() {
+		results := archiver.ArchiveList(context.Background(), walNames[:3], nil, 0)
+		Expect(results).To(HaveLen(3))
+
+		Expect(readConcurrency()).To(Equal([]int{1, 1, 1}))
+	})
+})
@@ -170,7 +170,13 @@ func DeleteBackupsNotInCatalog(
 
 // useSameBackupLocation checks whether the given backup was taken using the same configuration as provided
 func useSameBackupLocation(backup *v1.BackupStatus, cluster *v1.Cluster) bool {
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if cluster.Spec.Backup == nil {
+		return false
+	}
+	if backup.Method == v1.BackupMethodPVC {
+		return cluster.Spec.Backup.PVC != nil && backup.ServerName == cluster.GetBackupPVCServerName()
+	}
+	if cluster.Spec.Backup.BarmanObjectStore == nil {
 		return false
 	}
 	configuration := cluster.Spec.Backup.BarmanObjectStore
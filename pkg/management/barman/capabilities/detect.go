@@ -51,6 +51,10 @@ func Detect() (*Capabilities, error) {
 		// The --name flag was added to Barman in version 3.3 but we also require the
 		// barman-cloud-backup-show command which was not added until Barman version 3.4
 		newCapabilities.hasName = true
+		// Bandwidth limit for barman-cloud-backup, available in Barman >= 3.4
+		newCapabilities.HasMaxBandwidth = true
+		// Custom AWS KMS keys for server side encryption, supported in Barman >= 3.4
+		newCapabilities.HasKMSKeyID = true
 		fallthrough
 	case version.GE(semver.Version{Major: 2, Minor: 18}):
 		// Tags, added in Barman >= 2.18
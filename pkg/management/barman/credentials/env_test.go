@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Azure credentials", func() {
+	const namespace = "default"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "azure-creds",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"account":    []byte("storageaccount"),
+			"key":        []byte("storagekey"),
+			"sas":        []byte("sastoken"),
+			"connection": []byte("connectionstring"),
+		},
+	}
+
+	selector := func(key string) *apiv1.SecretKeySelector {
+		return &apiv1.SecretKeySelector{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: "azure-creds"},
+			Key:                  key,
+		}
+	}
+
+	newConfiguration := func(credentials *apiv1.AzureCredentials) *apiv1.BarmanObjectStoreConfiguration {
+		return &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "https://storageaccount.blob.core.windows.net/backups",
+			BarmanCredentials: apiv1.BarmanCredentials{
+				Azure: credentials,
+			},
+		}
+	}
+
+	It("sets the storage account name and key", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).WithObjects(secret).Build()
+		env, err := EnvSetBackupCloudCredentials(ctx, cli, namespace, newConfiguration(&apiv1.AzureCredentials{
+			StorageAccount: selector("account"),
+			StorageKey:     selector("key"),
+		}), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf(
+			"AZURE_STORAGE_ACCOUNT=storageaccount",
+			"AZURE_STORAGE_KEY=storagekey",
+		))
+	})
+
+	It("sets the SAS token and the connection string", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).WithObjects(secret).Build()
+		env, err := EnvSetRestoreCloudCredentials(ctx, cli, namespace, newConfiguration(&apiv1.AzureCredentials{
+			StorageAccount:   selector("account"),
+			StorageSasToken:  selector("sas"),
+			ConnectionString: selector("connection"),
+		}), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf(
+			"AZURE_STORAGE_ACCOUNT=storageaccount",
+			"AZURE_STORAGE_SAS_TOKEN=sastoken",
+			"AZURE_STORAGE_CONNECTION_STRING=connectionstring",
+		))
+	})
+
+	It("doesn't need any secret when inheriting from Azure AD", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+		env, err := EnvSetBackupCloudCredentials(ctx, cli, namespace, newConfiguration(&apiv1.AzureCredentials{
+			InheritFromAzureAD: true,
+		}), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(BeEmpty())
+	})
+
+	It("fails when the secret doesn't exist", func(ctx context.Context) {
+		cli := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+		_, err := EnvSetBackupCloudCredentials(ctx, cli, namespace, newConfiguration(&apiv1.AzureCredentials{
+			StorageAccount: selector("account"),
+		}), nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
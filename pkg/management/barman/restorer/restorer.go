@@ -232,7 +232,14 @@ func (restorer *WALRestorer) Restore(walName, destinationPath string, baseOption
 		barmanCapabilities.BarmanCloudWalRestore,
 		options...) // #nosec G204
 	barmanCloudWalRestoreCmd.Env = restorer.env
-	err := execlog.RunStreaming(barmanCloudWalRestoreCmd, barmanCapabilities.BarmanCloudWalRestore)
+	walLogger := log.WithName(barmanCapabilities.BarmanCloudWalRestore).WithValues(
+		"walName", walName,
+		"startTime", time.Now(),
+	)
+	err := execlog.RunStreamingWithLogger(
+		barmanCloudWalRestoreCmd,
+		barmanCapabilities.BarmanCloudWalRestore,
+		walLogger)
 	if err == nil {
 		return nil
 	}
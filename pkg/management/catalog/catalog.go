@@ -254,6 +254,14 @@ type BarmanBackup struct {
 
 	// The TimeLine
 	TimeLine int `json:"timeline"`
+
+	// The size of the backup in bytes, if reported by Barman
+	Size *int64 `json:"size,omitempty"`
+
+	// The size in bytes of the data actually copied for the backup,
+	// if reported by Barman. This is lower than the backup size
+	// when the backup is deduplicated or incremental
+	DeduplicatedSize *int64 `json:"deduplicated_size,omitempty"`
 }
 
 type barmanBackupShow struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package catalog
 
 import (
+	"strings"
 	"time"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -245,5 +246,19 @@ var _ = Describe("barman-cloud-backup-show parsing", func() {
 		Expect(result.SystemID).To(Equal("6885668674852188181"))
 		Expect(result.BeginTimeString).To(Equal("Tue Jan 19 03:14:08 2038"))
 		Expect(result.EndTimeString).To(Equal("Tue Jan 19 04:14:08 2038"))
+		Expect(result.Size).To(BeNil())
+		Expect(result.DeduplicatedSize).To(BeNil())
+	})
+
+	It("must parse the backup size when available", func() {
+		output := strings.Replace(barmanCloudShowOutput,
+			`"size": null`, `"size": 52428800`, 1)
+		output = strings.Replace(output,
+			`"deduplicated_size": null`, `"deduplicated_size": 10485760`, 1)
+
+		result, err := NewBackupFromBarmanCloudBackupShow(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Size).To(HaveValue(BeEquivalentTo(52428800)))
+		Expect(result.DeduplicatedSize).To(HaveValue(BeEquivalentTo(10485760)))
 	})
 })
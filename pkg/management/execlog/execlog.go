@@ -103,11 +103,32 @@ func RunStreaming(cmd *exec.Cmd, cmdName string) (err error) {
 	return streamingCmd.Wait()
 }
 
+// RunStreamingWithLogger executes the command redirecting its stdout and stderr to the
+// passed logger, which is expected to be already named and enriched by the caller.
+// This function waits for command to terminate end reports non-zero exit codes.
+func RunStreamingWithLogger(cmd *exec.Cmd, cmdName string, logger log.Logger) (err error) {
+	streamingCmd, err := RunStreamingNoWaitWithLogger(cmd, cmdName, logger)
+	if err != nil {
+		return err
+	}
+
+	return streamingCmd.Wait()
+}
+
 // RunStreamingNoWait executes the command redirecting its stdout and stderr to the logger.
 // This function does not wait for command to terminate.
 func RunStreamingNoWait(cmd *exec.Cmd, cmdName string) (streamingCmd *StreamingCmd, err error) {
-	logger := log.WithName(cmdName)
+	return RunStreamingNoWaitWithLogger(cmd, cmdName, log.WithName(cmdName))
+}
 
+// RunStreamingNoWaitWithLogger executes the command redirecting its stdout and stderr to the
+// passed logger, adding the pipe the line was read from.
+// This function does not wait for command to terminate.
+func RunStreamingNoWaitWithLogger(
+	cmd *exec.Cmd,
+	cmdName string,
+	logger log.Logger,
+) (streamingCmd *StreamingCmd, err error) {
 	stdoutWriter := &LogWriter{
 		Logger: logger.WithValues(PipeKey, StdOut),
 	}
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execlog
+
+import (
+	"os/exec"
+	"sync"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recordedLine struct {
+	msg    string
+	values []interface{}
+}
+
+// recordingLogger is a logger that keeps track of the Info lines it received
+type recordingLogger struct {
+	log.Logger
+
+	lock   *sync.Mutex
+	lines  *[]recordedLine
+	values []interface{}
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{
+		Logger: log.GetLogger(),
+		lock:   &sync.Mutex{},
+		lines:  &[]recordedLine{},
+	}
+}
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	values := append(append([]interface{}{}, l.values...), keysAndValues...)
+	*l.lines = append(*l.lines, recordedLine{msg: msg, values: values})
+}
+
+func (l *recordingLogger) WithValues(keysAndValues ...interface{}) log.Logger {
+	return &recordingLogger{
+		Logger: l.Logger,
+		lock:   l.lock,
+		lines:  l.lines,
+		values: append(append([]interface{}{}, l.values...), keysAndValues...),
+	}
+}
+
+var _ = Describe("Streaming a command output to a logger", func() {
+	It("logs every line using the passed logger and the pipe it comes from", func() {
+		logger := newRecordingLogger()
+		cmd := exec.Command("sh", "-c", "echo first; echo second 1>&2")
+
+		err := RunStreamingWithLogger(cmd, "sh", logger.WithValues("walName", "000000010000000000000001"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(*logger.lines).To(ConsistOf(
+			recordedLine{
+				msg:    "first",
+				values: []interface{}{"walName", "000000010000000000000001", PipeKey, StdOut},
+			},
+			recordedLine{
+				msg:    "second",
+				values: []interface{}{"walName", "000000010000000000000001", PipeKey, StdErr},
+			},
+		))
+	})
+
+	It("reports the exit status of the command", func() {
+		cmd := exec.Command("sh", "-c", "exit 1")
+		err := RunStreamingWithLogger(cmd, "sh", newRecordingLogger())
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcommand
+
+import (
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/pflag"
+)
+
+// shellQuotedList is a flag containing a list of values, quoted
+// and joined as in a shell command line
+type shellQuotedList struct {
+	target *[]string
+}
+
+// String implements the pflag.Value interface
+func (list shellQuotedList) String() string {
+	if list.target == nil {
+		return ""
+	}
+	return shellquote.Join(*list.target...)
+}
+
+// Set implements the pflag.Value interface
+func (list shellQuotedList) Set(value string) error {
+	result, err := shellquote.Split(value)
+	if err != nil {
+		return err
+	}
+	*list.target = result
+	return nil
+}
+
+// Type implements the pflag.Value interface
+func (list shellQuotedList) Type() string {
+	return "string"
+}
+
+// AddFlags registers in a flag set the flags corresponding to the
+// options supported by a command
+func (options *Options) AddFlags(flags *pflag.FlagSet, command Command) {
+	supported, ok := supportedFlags[command]
+	if !ok {
+		return
+	}
+
+	if supported.Has(flagAppDBName) {
+		flags.StringVar(&options.AppDBName, flagAppDBName, "app",
+			"The name of the application containing the database")
+	}
+	if supported.Has(flagAppUser) {
+		flags.StringVar(&options.AppUser, flagAppUser, "app",
+			"The name of the application user")
+	}
+	if supported.Has(flagInitDBFlags) {
+		flags.Var(shellQuotedList{target: &options.InitDBFlags}, flagInitDBFlags,
+			"The list of flags to be passed to initdb while creating the initial database")
+	}
+	if supported.Has(flagPostInitSQL) {
+		flags.Var(shellQuotedList{target: &options.PostInitSQL}, flagPostInitSQL,
+			"The list of SQL queries to be executed to configure the new instance")
+	}
+	if supported.Has(flagPostInitApplicationSQL) {
+		flags.Var(shellQuotedList{target: &options.PostInitApplicationSQL}, flagPostInitApplicationSQL,
+			"The list of SQL queries to be executed inside application database right after the database is created")
+	}
+	if supported.Has(flagPostInitTemplateSQL) {
+		flags.Var(shellQuotedList{target: &options.PostInitTemplateSQL}, flagPostInitTemplateSQL,
+			"The list of SQL queries to be executed inside template1 database to configure the new instance")
+	}
+	if supported.Has(flagPostInitApplicationSQLRefsFolder) {
+		flags.StringVar(&options.PostInitApplicationSQLRefsFolder, flagPostInitApplicationSQLRefsFolder, "",
+			"The folder contains a set of SQL files to be executed in alphabetical order "+
+				"against the application database immediately after its creation")
+	}
+	if supported.Has(flagParentNode) {
+		flags.StringVar(&options.ParentNode, flagParentNode, "", "The origin node")
+	}
+	if supported.Has(flagBackupLabel) {
+		flags.StringVar(&options.BackupLabel, flagBackupLabel, "", "The restore backup_label file content")
+	}
+	if supported.Has(flagTablespaceMap) {
+		flags.StringVar(&options.TablespaceMap, flagTablespaceMap, "", "The restore tablespace_map file content")
+	}
+	if supported.Has(flagImmediate) {
+		flags.BoolVar(&options.Immediate, flagImmediate, false,
+			"Do not start PostgreSQL but just recover the snapshot")
+	}
+	if supported.Has(flagOldBinDirFile) {
+		flags.StringVar(&options.OldBinDirFile, flagOldBinDirFile, "",
+			"The file containing the location of the binaries of the old major version of PostgreSQL")
+	}
+	if supported.Has(flagPgWal) {
+		flags.StringVar(&options.PgWal, flagPgWal, "", "The PGWAL directory of the instance, if any")
+	}
+}
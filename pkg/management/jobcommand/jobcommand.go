@@ -0,0 +1,233 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jobcommand contains the typed options of the instance manager
+// commands run by the jobs creating or upgrading the PGDATA of an instance.
+// The operator serializes them as command line flags, and the instance
+// manager parses them back, validating them at both ends
+package jobcommand
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// Command is an instance manager command run by a job
+type Command string
+
+const (
+	// CommandInit creates a new PGDATA with initdb
+	CommandInit Command = "init"
+
+	// CommandJoin clones a new replica from another instance
+	CommandJoin Command = "join"
+
+	// CommandPgBaseBackup clones a new primary from an external cluster
+	CommandPgBaseBackup Command = "pgbasebackup"
+
+	// CommandRestore restores a new primary from a backup
+	CommandRestore Command = "restore"
+
+	// CommandRestoreSnapshot restores a new instance from a volume snapshot
+	CommandRestoreSnapshot Command = "restoresnapshot"
+
+	// CommandUpgradeExecute upgrades the PGDATA to a new major version
+	CommandUpgradeExecute Command = "upgrade execute"
+)
+
+// The flags corresponding to the Options fields
+const (
+	flagAppDBName                        = "app-db-name"
+	flagAppUser                          = "app-user"
+	flagInitDBFlags                      = "initdb-flags"
+	flagPostInitSQL                      = "post-init-sql"
+	flagPostInitApplicationSQL           = "post-init-application-sql"
+	flagPostInitTemplateSQL              = "post-init-template-sql"
+	flagPostInitApplicationSQLRefsFolder = "post-init-application-sql-refs-folder"
+	flagParentNode                       = "parent-node"
+	flagPgWal                            = "pg-wal"
+	flagBackupLabel                      = "backuplabel"
+	flagTablespaceMap                    = "tablespacemap"
+	flagImmediate                        = "immediate"
+	flagOldBinDirFile                    = "old-bindir-file"
+)
+
+// supportedFlags are the flags accepted by every command
+var supportedFlags = map[Command]*stringset.Data{
+	CommandInit: stringset.From([]string{
+		flagAppDBName,
+		flagAppUser,
+		flagInitDBFlags,
+		flagPostInitSQL,
+		flagPostInitApplicationSQL,
+		flagPostInitTemplateSQL,
+		flagPostInitApplicationSQLRefsFolder,
+		flagParentNode,
+		flagPgWal,
+	}),
+	CommandJoin:         stringset.From([]string{flagParentNode, flagPgWal}),
+	CommandPgBaseBackup: stringset.From([]string{flagPgWal}),
+	CommandRestore:      stringset.From([]string{flagPgWal}),
+	CommandRestoreSnapshot: stringset.From([]string{
+		flagPgWal,
+		flagBackupLabel,
+		flagTablespaceMap,
+		flagImmediate,
+	}),
+	CommandUpgradeExecute: stringset.From([]string{flagOldBinDirFile, flagInitDBFlags, flagPgWal}),
+}
+
+// requiredFlags are the flags that every command needs
+var requiredFlags = map[Command][]string{
+	CommandJoin:           {flagParentNode},
+	CommandUpgradeExecute: {flagOldBinDirFile},
+}
+
+// Options are the options of the instance manager commands run by jobs.
+// Every command only supports a subset of them
+type Options struct {
+	// The name of the application database to be created
+	AppDBName string
+
+	// The owner of the application database to be created
+	AppUser string
+
+	// The options to be passed to initdb
+	InitDBFlags []string
+
+	// The SQL queries to be executed right after the instance is created
+	PostInitSQL []string
+
+	// The SQL queries to be executed in the application database
+	// right after it is created
+	PostInitApplicationSQL []string
+
+	// The SQL queries to be executed in the template1 database
+	PostInitTemplateSQL []string
+
+	// The folder containing the SQL files to be executed in the
+	// application database right after it is created
+	PostInitApplicationSQLRefsFolder string
+
+	// The host the data directory is cloned from
+	ParentNode string
+
+	// The directory where the WAL files are stored, if not the default one
+	PgWal string
+
+	// The base64-encoded content of the backup_label file
+	BackupLabel string
+
+	// The base64-encoded content of the tablespace_map file
+	TablespaceMap string
+
+	// Whether to just recover the volume snapshot, without starting PostgreSQL
+	Immediate bool
+
+	// The file containing the location of the binaries of the old
+	// major version of PostgreSQL
+	OldBinDirFile string
+}
+
+// flagValue is the value of a flag set in the options
+type flagValue struct {
+	name    string
+	value   string
+	boolean bool
+}
+
+// setFlags returns the flags corresponding to the options that are set,
+// in a stable order
+func (options Options) setFlags() []flagValue {
+	var result []flagValue
+	addString := func(name, value string) {
+		if value != "" {
+			result = append(result, flagValue{name: name, value: value})
+		}
+	}
+	addList := func(name string, value []string) {
+		if len(value) > 0 {
+			result = append(result, flagValue{name: name, value: shellquote.Join(value...)})
+		}
+	}
+
+	addList(flagInitDBFlags, options.InitDBFlags)
+	addList(flagPostInitSQL, options.PostInitSQL)
+	addList(flagPostInitApplicationSQL, options.PostInitApplicationSQL)
+	addList(flagPostInitTemplateSQL, options.PostInitTemplateSQL)
+	addString(flagAppDBName, options.AppDBName)
+	addString(flagAppUser, options.AppUser)
+	addString(flagPostInitApplicationSQLRefsFolder, options.PostInitApplicationSQLRefsFolder)
+	addString(flagParentNode, options.ParentNode)
+	addString(flagBackupLabel, options.BackupLabel)
+	addString(flagTablespaceMap, options.TablespaceMap)
+	if options.Immediate {
+		result = append(result, flagValue{name: flagImmediate, boolean: true})
+	}
+	addString(flagOldBinDirFile, options.OldBinDirFile)
+	addString(flagPgWal, options.PgWal)
+
+	return result
+}
+
+// Validate checks that the options are supported by a command, and
+// that the ones it requires are set
+func (options Options) Validate(command Command) error {
+	supported, ok := supportedFlags[command]
+	if !ok {
+		return fmt.Errorf("unknown instance manager command: %q", command)
+	}
+
+	isSet := stringset.New()
+	for _, flag := range options.setFlags() {
+		if !supported.Has(flag.name) {
+			return fmt.Errorf("the %q option is not supported by the %q command", flag.name, command)
+		}
+		isSet.Put(flag.name)
+	}
+
+	for _, name := range requiredFlags[command] {
+		if !isSet.Has(name) {
+			return fmt.Errorf("the %q option is required by the %q command", name, command)
+		}
+	}
+
+	return nil
+}
+
+// BuildCommand validates the options and builds the command line running
+// a command of the instance manager with them
+func BuildCommand(command Command, options Options) ([]string, error) {
+	if err := options.Validate(command); err != nil {
+		return nil, err
+	}
+
+	result := []string{"/controller/manager", "instance"}
+	result = append(result, strings.Fields(string(command))...)
+	for _, flag := range options.setFlags() {
+		if flag.boolean {
+			result = append(result, "--"+flag.name)
+			continue
+		}
+		result = append(result, "--"+flag.name, flag.value)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobcommand
+
+import (
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Job commands", func() {
+	It("builds the command line of a command", func() {
+		command, err := BuildCommand(CommandJoin, Options{
+			ParentNode: "cluster-example-rw",
+			PgWal:      "/var/lib/postgresql/wal/pg_wal",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command).To(Equal([]string{
+			"/controller/manager", "instance", "join",
+			"--parent-node", "cluster-example-rw",
+			"--pg-wal", "/var/lib/postgresql/wal/pg_wal",
+		}))
+	})
+
+	It("splits the words of a subcommand", func() {
+		command, err := BuildCommand(CommandUpgradeExecute, Options{
+			OldBinDirFile: "/controller/old/bindir",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command).To(Equal([]string{
+			"/controller/manager", "instance", "upgrade", "execute",
+			"--old-bindir-file", "/controller/old/bindir",
+		}))
+	})
+
+	It("quotes the lists of values", func() {
+		command, err := BuildCommand(CommandInit, Options{
+			InitDBFlags: []string{"--encoding=UTF8", "--icu-rules=&V << w <<< W"},
+			PostInitSQL: []string{"CREATE ROLE test"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command).To(Equal([]string{
+			"/controller/manager", "instance", "init",
+			"--initdb-flags", "--encoding=UTF8 '--icu-rules=&V << w <<< W'",
+			"--post-init-sql", "'CREATE ROLE test'",
+		}))
+	})
+
+	It("emits boolean options without a value", func() {
+		command, err := BuildCommand(CommandRestoreSnapshot, Options{Immediate: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command).To(Equal([]string{
+			"/controller/manager", "instance", "restoresnapshot", "--immediate",
+		}))
+	})
+
+	It("rejects the options not supported by a command", func() {
+		_, err := BuildCommand(CommandPgBaseBackup, Options{ParentNode: "cluster-example-rw"})
+		Expect(err).To(MatchError(ContainSubstring(`"parent-node" option is not supported`)))
+	})
+
+	It("rejects a command missing a required option", func() {
+		_, err := BuildCommand(CommandJoin, Options{})
+		Expect(err).To(MatchError(ContainSubstring(`"parent-node" option is required`)))
+	})
+
+	It("rejects an unknown command", func() {
+		err := Options{}.Validate(Command("unknown"))
+		Expect(err).To(MatchError(ContainSubstring("unknown instance manager command")))
+	})
+
+	DescribeTable("parses back the options it serializes",
+		func(command Command, options Options) {
+			args, err := BuildCommand(command, options)
+			Expect(err).ToNot(HaveOccurred())
+
+			var parsed Options
+			flags := pflag.NewFlagSet(string(command), pflag.ContinueOnError)
+			parsed.AddFlags(flags, command)
+			Expect(flags.Parse(args)).To(Succeed())
+			Expect(parsed.Validate(command)).To(Succeed())
+			Expect(parsed).To(Equal(options))
+		},
+		Entry("init", CommandInit, Options{
+			AppDBName:                        "app",
+			AppUser:                          "app",
+			InitDBFlags:                      []string{"--locale-provider=icu", "--icu-rules=&V << w <<< W"},
+			PostInitSQL:                      []string{"CREATE ROLE test", "SELECT 'it''s'"},
+			PostInitApplicationSQL:           []string{"CREATE TABLE test()"},
+			PostInitTemplateSQL:              []string{"CREATE EXTENSION hstore"},
+			PostInitApplicationSQLRefsFolder: "/etc/post-init-application-sql-refs",
+			PgWal:                            "/var/lib/postgresql/wal/pg_wal",
+		}),
+		Entry("join", CommandJoin, Options{ParentNode: "cluster-example-rw"}),
+		Entry("restoresnapshot", CommandRestoreSnapshot, Options{
+			BackupLabel:   "YmFja3VwX2xhYmVs",
+			TablespaceMap: "dGFibGVzcGFjZV9tYXA=",
+			Immediate:     true,
+		}),
+		Entry("upgrade execute", CommandUpgradeExecute, Options{
+			OldBinDirFile: "/controller/old/bindir",
+			InitDBFlags:   []string{"--encoding=UTF8"},
+		}),
+	)
+})
@@ -0,0 +1,250 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecyclehooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// Event is a moment in the lifecycle of an instance where hooks can be invoked
+type Event string
+
+const (
+	// EventPrePromote happens before the instance is promoted to primary
+	EventPrePromote Event = "prePromote"
+
+	// EventPostPromote happens after the instance has been promoted to primary
+	EventPostPromote Event = "postPromote"
+
+	// EventPreShutdown happens before PostgreSQL is shut down
+	EventPreShutdown Event = "preShutdown"
+
+	// EventPostBootstrap happens after the bootstrap of the cluster
+	EventPostBootstrap Event = "postBootstrap"
+
+	// EventPreBackup happens before a backup is taken
+	EventPreBackup Event = "preBackup"
+
+	// EventPreSwitchover happens before the operator changes the target
+	// primary during a planned switchover
+	EventPreSwitchover Event = "preSwitchover"
+)
+
+// Payload is the body of the requests sent to the HTTP hooks
+type Payload struct {
+	// Event is the lifecycle event invoking the hook
+	Event Event `json:"event"`
+
+	// Namespace is the namespace of the cluster
+	Namespace string `json:"namespace"`
+
+	// ClusterName is the name of the cluster
+	ClusterName string `json:"clusterName"`
+
+	// PodName is the name of the instance where the event happened
+	PodName string `json:"podName"`
+
+	// TargetPodName is the name of the instance being promoted, only
+	// set for the preSwitchover event
+	TargetPodName string `json:"targetPodName,omitempty"`
+
+	// Timestamp is the time of the event
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// HookError is raised when a hook having the `Fail` failure policy fails
+type HookError struct {
+	// Event is the lifecycle event invoking the hook
+	Event Event
+
+	// HookName is the name of the hook
+	HookName string
+
+	// Err is the error raised by the hook
+	Err error
+}
+
+// Error implements the error interface
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %v", e.Event, e.HookName, e.Err)
+}
+
+// Unwrap returns the error raised by the hook
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// getHooks returns the hooks configured for the passed event
+func getHooks(cluster *apiv1.Cluster, event Event) []apiv1.LifecycleHook {
+	hooks := cluster.Spec.LifecycleHooks
+	if hooks == nil {
+		return nil
+	}
+
+	switch event {
+	case EventPrePromote:
+		return hooks.PrePromote
+	case EventPostPromote:
+		return hooks.PostPromote
+	case EventPreShutdown:
+		return hooks.PreShutdown
+	case EventPostBootstrap:
+		return hooks.PostBootstrap
+	case EventPreBackup:
+		return hooks.PreBackup
+	case EventPreSwitchover:
+		return hooks.PreSwitchover
+	default:
+		return nil
+	}
+}
+
+// Run invokes, in order, the hooks configured in the cluster for the
+// passed event. Failing hooks are logged, and the first failing hook
+// having the `Fail` failure policy stops the execution, making Run
+// return a *HookError
+func Run(ctx context.Context, cluster *apiv1.Cluster, podName string, event Event) error {
+	return runHooks(ctx, getHooks(cluster, event), Payload{
+		Event:       event,
+		Namespace:   cluster.Namespace,
+		ClusterName: cluster.Name,
+		PodName:     podName,
+		Timestamp:   time.Now(),
+	})
+}
+
+// RunPreSwitchover invokes the preSwitchover hooks configured in the
+// cluster, before switching over from the current primary to the passed
+// target primary. Only HTTP hooks are expected, as this is invoked by the
+// operator
+func RunPreSwitchover(ctx context.Context, cluster *apiv1.Cluster, targetPrimary string) error {
+	return runHooks(ctx, getHooks(cluster, EventPreSwitchover), Payload{
+		Event:         EventPreSwitchover,
+		Namespace:     cluster.Namespace,
+		ClusterName:   cluster.Name,
+		PodName:       cluster.Status.CurrentPrimary,
+		TargetPodName: targetPrimary,
+		Timestamp:     time.Now(),
+	})
+}
+
+func runHooks(ctx context.Context, hooks []apiv1.LifecycleHook, payload Payload) error {
+	event := payload.Event
+	for _, hook := range hooks {
+		contextLogger := log.FromContext(ctx).WithValues("event", event, "hook", hook.Name)
+
+		hookCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+		err := runHook(log.IntoContext(hookCtx, contextLogger), hook, payload)
+		cancel()
+
+		if err == nil {
+			contextLogger.Info("Lifecycle hook completed")
+			continue
+		}
+
+		contextLogger.Error(err, "Lifecycle hook failed", "failurePolicy", hook.GetFailurePolicy())
+		if hook.GetFailurePolicy() == apiv1.LifecycleHookFailurePolicyFail {
+			return &HookError{Event: event, HookName: hook.Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// RunForCluster loads the cluster with the passed name, and invokes
+// the hooks configured in it for the passed event
+func RunForCluster(
+	ctx context.Context,
+	cli client.Client,
+	namespace, clusterName, podName string,
+	event Event,
+) error {
+	var cluster apiv1.Cluster
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &cluster); err != nil {
+		return fmt.Errorf("while getting the cluster to run the %s hooks: %w", event, err)
+	}
+
+	return Run(ctx, &cluster, podName, event)
+}
+
+func runHook(ctx context.Context, hook apiv1.LifecycleHook, payload Payload) error {
+	switch {
+	case hook.HTTP != nil:
+		return runHTTPHook(ctx, hook.HTTP, payload)
+	case hook.Exec != nil:
+		return runExecHook(ctx, hook.Exec, payload)
+	default:
+		return fmt.Errorf("no http or exec action defined")
+	}
+}
+
+func runHTTPHook(ctx context.Context, hook *apiv1.HTTPLifecycleHook, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", response.StatusCode, string(responseBody))
+	}
+
+	return nil
+}
+
+func runExecHook(ctx context.Context, hook *apiv1.ExecLifecycleHook, payload Payload) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...) // #nosec G204
+	cmd.Env = append(os.Environ(),
+		"CNPG_HOOK_EVENT="+string(payload.Event),
+		"CNPG_CLUSTER_NAME="+payload.ClusterName,
+		"CNPG_NAMESPACE="+payload.Namespace,
+		"CNPG_POD_NAME="+payload.PodName,
+	)
+
+	return execlog.RunStreamingWithLogger(cmd, hook.Command[0], log.FromContext(ctx))
+}
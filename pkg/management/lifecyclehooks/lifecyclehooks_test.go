@@ -0,0 +1,160 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecyclehooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lifecycle hooks", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				LifecycleHooks: &apiv1.LifecycleHooksConfiguration{},
+			},
+		}
+	})
+
+	It("does nothing when no hook is configured", func(ctx SpecContext) {
+		Expect(Run(ctx, &apiv1.Cluster{}, "cluster-example-1", EventPrePromote)).To(Succeed())
+	})
+
+	It("sends the event to the HTTP hooks", func(ctx SpecContext) {
+		var payload Payload
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(server.Close)
+
+		cluster.Spec.LifecycleHooks.PrePromote = []apiv1.LifecycleHook{
+			{
+				Name: "dns",
+				HTTP: &apiv1.HTTPLifecycleHook{
+					URL:     server.URL,
+					Headers: map[string]string{"Authorization": "Bearer token"},
+				},
+			},
+		}
+
+		Expect(Run(ctx, cluster, "cluster-example-1", EventPrePromote)).To(Succeed())
+		Expect(authorization).To(Equal("Bearer token"))
+		Expect(payload.Event).To(Equal(EventPrePromote))
+		Expect(payload.ClusterName).To(Equal("cluster-example"))
+		Expect(payload.Namespace).To(Equal("default"))
+		Expect(payload.PodName).To(Equal("cluster-example-1"))
+	})
+
+	It("passes the event to the exec hooks", func(ctx SpecContext) {
+		outputFile := path.Join(GinkgoT().TempDir(), "event")
+		cluster.Spec.LifecycleHooks.PostPromote = []apiv1.LifecycleHook{
+			{
+				Name: "record",
+				Exec: &apiv1.ExecLifecycleHook{
+					Command: []string{"sh", "-c", `echo "$CNPG_HOOK_EVENT $CNPG_POD_NAME" > ` + outputFile},
+				},
+			},
+		}
+
+		Expect(Run(ctx, cluster, "cluster-example-2", EventPostPromote)).To(Succeed())
+		content, err := os.ReadFile(outputFile) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("postPromote cluster-example-2\n"))
+	})
+
+	It("ignores failing hooks by default", func(ctx SpecContext) {
+		cluster.Spec.LifecycleHooks.PreShutdown = []apiv1.LifecycleHook{
+			{Name: "failing", Exec: &apiv1.ExecLifecycleHook{Command: []string{"false"}}},
+		}
+
+		Expect(Run(ctx, cluster, "cluster-example-1", EventPreShutdown)).To(Succeed())
+	})
+
+	It("stops at the first failing hook with the Fail policy", func(ctx SpecContext) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		DeferCleanup(server.Close)
+
+		outputFile := path.Join(GinkgoT().TempDir(), "event")
+		cluster.Spec.LifecycleHooks.PreBackup = []apiv1.LifecycleHook{
+			{
+				Name:          "unavailable",
+				HTTP:          &apiv1.HTTPLifecycleHook{URL: server.URL},
+				FailurePolicy: apiv1.LifecycleHookFailurePolicyFail,
+			},
+			{
+				Name: "never-run",
+				Exec: &apiv1.ExecLifecycleHook{Command: []string{"touch", outputFile}},
+			},
+		}
+
+		err := Run(ctx, cluster, "cluster-example-1", EventPreBackup)
+		var hookErr *HookError
+		Expect(err).To(BeAssignableToTypeOf(hookErr))
+		Expect(err.Error()).To(ContainSubstring(`preBackup hook "unavailable" failed`))
+		Expect(outputFile).ToNot(BeAnExistingFile())
+	})
+
+	It("stops the hooks running longer than their timeout", func(ctx SpecContext) {
+		cluster.Spec.LifecycleHooks.PostBootstrap = []apiv1.LifecycleHook{
+			{
+				Name:           "slow",
+				Exec:           &apiv1.ExecLifecycleHook{Command: []string{"sleep", "10"}},
+				TimeoutSeconds: 1,
+				FailurePolicy:  apiv1.LifecycleHookFailurePolicyFail,
+			},
+		}
+
+		Expect(Run(ctx, cluster, "cluster-example-1", EventPostBootstrap)).ToNot(Succeed())
+	})
+
+	It("sends the current and the target primary to the preSwitchover hooks", func(ctx SpecContext) {
+		var payload Payload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			w.WriteHeader(http.StatusOK)
+		}))
+		DeferCleanup(server.Close)
+
+		cluster.Status.CurrentPrimary = "cluster-example-1"
+		cluster.Spec.LifecycleHooks.PreSwitchover = []apiv1.LifecycleHook{
+			{Name: "drain", HTTP: &apiv1.HTTPLifecycleHook{URL: server.URL}},
+		}
+
+		Expect(RunPreSwitchover(ctx, cluster, "cluster-example-2")).To(Succeed())
+		Expect(payload.Event).To(Equal(EventPreSwitchover))
+		Expect(payload.PodName).To(Equal("cluster-example-1"))
+		Expect(payload.TargetPodName).To(Equal("cluster-example-2"))
+	})
+})
@@ -69,9 +69,10 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
-
-[pgbouncer]
+{{ .Databases }}
+{{ if .Users }}[users]
+{{ .Users }}
+{{ end }}[pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
 auth_user = {{ .AuthQueryUser }}
 auth_query = {{ .AuthQuery }}
@@ -180,12 +181,30 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	if pooler.IsExternal() {
+		parameters["server_tls_sslmode"] = pooler.Spec.ExternalCluster.GetSSLMode()
+	}
+
+	if secrets.ServerCA == nil {
+		delete(parameters, "server_tls_ca_file")
+	}
+
+	// Without a certificate, PgBouncer can't accept TLS connections
+	if secrets.Client == nil || secrets.ClientCA == nil {
+		parameters["client_tls_sslmode"] = "disable"
+		delete(parameters, "client_tls_cert_file")
+		delete(parameters, "client_tls_key_file")
+		delete(parameters, "client_tls_ca_file")
+	}
+
 	templateData := struct {
 		Pooler            *apiv1.Pooler
 		AuthQuery         string
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
+		Databases         string
+		Users             string
 		PgHba             []string
 	}{
 		Pooler:            pooler,
@@ -200,7 +219,11 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
-		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+		Databases: stringifyPgBouncerDatabases(
+			getServerConnectionParameters(pooler),
+			pooler.Spec.PgBouncer.Databases),
+		Users: stringifyPgBouncerUsers(pooler.Spec.PgBouncer.Users),
+		PgHba: pooler.Spec.PgBouncer.PgHBA,
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
@@ -224,10 +247,27 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 	files[filepath.Join(ConfigsDir, PgBouncerHBAConfFileName)] = pgbouncerHBA.Bytes()
 
 	// The required crypto-material
-	files[serverTLSCAPath] = secrets.ServerCA.Data[certs.CACertKey]
-	files[clientTLSCAPath] = secrets.ClientCA.Data[certs.CACertKey]
-	files[clientTLSCertPath] = secrets.Client.Data[certs.TLSCertKey]
-	files[clientTLSKeyPath] = secrets.Client.Data[certs.TLSPrivateKeyKey]
+	if secrets.ServerCA != nil {
+		files[serverTLSCAPath] = secrets.ServerCA.Data[certs.CACertKey]
+	}
+	if secrets.Client != nil && secrets.ClientCA != nil {
+		files[clientTLSCAPath] = secrets.ClientCA.Data[certs.CACertKey]
+		files[clientTLSCertPath] = secrets.Client.Data[certs.TLSCertKey]
+		files[clientTLSKeyPath] = secrets.Client.Data[certs.TLSPrivateKeyKey]
+	}
 
 	return files, nil
 }
+
+// getServerConnectionParameters returns the connection parameters pointing
+// to the PostgreSQL server, which is the service of the referenced cluster
+// or the external cluster
+func getServerConnectionParameters(pooler *apiv1.Pooler) string {
+	if pooler.IsExternal() {
+		return fmt.Sprintf("host=%s port=%d",
+			cleanupPgBouncerValue(pooler.Spec.ExternalCluster.Host),
+			pooler.Spec.ExternalCluster.GetPort())
+	}
+
+	return fmt.Sprintf("host=%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type)
+}
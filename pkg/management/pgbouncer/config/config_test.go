@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server connection parameters", func() {
+	It("points to the service of the cluster", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRO,
+			},
+		}
+		Expect(getServerConnectionParameters(pooler)).To(Equal("host=cluster-example-ro"))
+	})
+
+	It("points to the external cluster", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				ExternalCluster: &apiv1.PoolerExternalCluster{
+					Host: "postgres.example.com",
+				},
+			},
+		}
+		Expect(getServerConnectionParameters(pooler)).To(Equal("host=postgres.example.com port=5432"))
+
+		pooler.Spec.ExternalCluster.Port = 6432
+		Expect(getServerConnectionParameters(pooler)).To(Equal("host=postgres.example.com port=6432"))
+	})
+})
@@ -21,6 +21,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -39,6 +41,52 @@ func stringifyPgBouncerParameters(parameters map[string]string) (paramsString st
 	return paramsString
 }
 
+// stringifyPgBouncerDatabases will emit the content of the `databases` section
+// of the PgBouncer configuration, pointing every entry to the server described
+// by the passed connection parameters. When no database is specified, a
+// wildcard entry is used
+func stringifyPgBouncerDatabases(server string, databases []apiv1.PgBouncerDatabase) string {
+	if len(databases) == 0 {
+		return fmt.Sprintf("* = %s\n", server)
+	}
+
+	var result strings.Builder
+	for _, database := range databases {
+		result.WriteString(fmt.Sprintf("%s = %s dbname=%s", database.Name, server, database.GetDBName()))
+		if database.PoolMode != "" {
+			result.WriteString(fmt.Sprintf(" pool_mode=%s", database.PoolMode))
+		}
+		writeOptionalInt(&result, "pool_size", database.PoolSize)
+		writeOptionalInt(&result, "min_pool_size", database.MinPoolSize)
+		writeOptionalInt(&result, "reserve_pool", database.ReservePoolSize)
+		writeOptionalInt(&result, "max_db_connections", database.MaxDBConnections)
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// stringifyPgBouncerUsers will emit the content of the `users` section
+// of the PgBouncer configuration
+func stringifyPgBouncerUsers(users []apiv1.PgBouncerUser) string {
+	var result strings.Builder
+	for _, user := range users {
+		result.WriteString(fmt.Sprintf("%s =", user.Name))
+		if user.PoolMode != "" {
+			result.WriteString(fmt.Sprintf(" pool_mode=%s", user.PoolMode))
+		}
+		writeOptionalInt(&result, "max_user_connections", user.MaxUserConnections)
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// writeOptionalInt writes a `key=value` setting when the value is set
+func writeOptionalInt(builder *strings.Builder, key string, value *int32) {
+	if value != nil {
+		builder.WriteString(fmt.Sprintf(" %s=%d", key, *value))
+	}
+}
+
 // buildPgBouncerParameters will build a PgBouncer configuration applying any
 // default parameters and forcing any required parameter needed for the
 // controller to work correctly
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -44,4 +46,43 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pool_mode.*"))
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
+
+	It("uses a wildcard entry when no database is specified", func() {
+		Expect(stringifyPgBouncerDatabases("host=cluster-example-rw", nil)).
+			To(Equal("* = host=cluster-example-rw\n"))
+	})
+
+	It("emits one entry for every database", func() {
+		poolSize := int32(10)
+		maxDBConnections := int32(20)
+		databases := []apiv1.PgBouncerDatabase{
+			{
+				Name: "app",
+			},
+			{
+				Name:             "reporting",
+				DBName:           "app",
+				PoolMode:         apiv1.PgBouncerPoolModeTransaction,
+				PoolSize:         &poolSize,
+				MaxDBConnections: &maxDBConnections,
+			},
+		}
+		Expect(stringifyPgBouncerDatabases("host=cluster-example-rw", databases)).To(Equal(
+			"app = host=cluster-example-rw dbname=app\n" +
+				"reporting = host=cluster-example-rw dbname=app pool_mode=transaction " +
+				"pool_size=10 max_db_connections=20\n"))
+	})
+
+	It("emits the user specific settings", func() {
+		maxUserConnections := int32(5)
+		users := []apiv1.PgBouncerUser{
+			{
+				Name:               "app",
+				PoolMode:           apiv1.PgBouncerPoolModeSession,
+				MaxUserConnections: &maxUserConnections,
+			},
+		}
+		Expect(stringifyPgBouncerUsers(users)).To(Equal("app = pool_mode=session max_user_connections=5\n"))
+		Expect(stringifyPgBouncerUsers(nil)).To(BeEmpty())
+	})
 })
@@ -150,6 +150,7 @@ func (e *Exporter) collectShowLists(ch chan<- prometheus.Metric, db *sql.DB) {
 			log.Error(err, "Error while executing SHOW LISTS")
 			e.Metrics.Error.Set(1)
 			e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+			continue
 		}
 		m, ok := e.Metrics.ShowLists[list]
 		if !ok {
@@ -0,0 +1,136 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exporter", func() {
+	const poolsKey = "cnpg_pgbouncer_lists_pools"
+
+	var (
+		registry *prometheus.Registry
+		db       *sql.DB
+		mock     sqlmock.Sqlmock
+		exp      *Exporter
+		ch       chan prometheus.Metric
+		columns  = []string{"list", "items"}
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		exp = &Exporter{
+			Metrics: newMetrics(),
+			pool:    fakePooler{db: db},
+		}
+
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exp.Metrics.PgbouncerUp)
+		registry.MustRegister(exp.Metrics.Error)
+		registry.MustRegister(exp.Metrics.ShowLists["pools"])
+
+		ch = make(chan prometheus.Metric, 1000)
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("collectShowLists", func() {
+		It("should react properly if SQL shows no lists", func() {
+			mock.ExpectQuery("SHOW LISTS;").WillReturnError(sql.ErrNoRows)
+			exp.collectShowLists(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			pgBouncerUpValue := getMetric(metrics, pgBouncerUpKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(pgBouncerUpValue).Should(BeEquivalentTo(0))
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(1))
+		})
+
+		It("should handle SQL rows scanning properly", func() {
+			mock.ExpectQuery("SHOW LISTS;").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("databases", 2).
+					AddRow("pools", 3))
+
+			exp.collectShowLists(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			pgBouncerUpValue := getMetric(metrics, pgBouncerUpKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(pgBouncerUpValue).Should(BeEquivalentTo(1))
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(0))
+
+			poolsValue := getMetric(metrics, poolsKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(poolsValue).To(BeEquivalentTo(3))
+		})
+
+		It("should flag the lists without a corresponding metric", func() {
+			mock.ExpectQuery("SHOW LISTS;").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("unknown", 1))
+
+			exp.collectShowLists(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(1))
+
+			poolsValue := getMetric(metrics, poolsKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(poolsValue).To(BeEquivalentTo(-1))
+		})
+
+		It("should handle error during SQL rows scanning", func() {
+			mock.ExpectQuery("SHOW LISTS;").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("pools", "error"))
+
+			exp.collectShowLists(ch, db)
+
+			registry.MustRegister(exp.Metrics.PgCollectionErrors)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			errorsMetric := getMetric(metrics, collectionErrorsTotalKey).GetMetric()[0]
+			Expect(errorsMetric.GetCounter().GetValue()).To(BeEquivalentTo(1))
+
+			// The metric is not set from a row that couldn't be scanned
+			poolsValue := getMetric(metrics, poolsKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(poolsValue).To(BeEquivalentTo(-1))
+		})
+	})
+})
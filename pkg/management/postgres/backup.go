@@ -22,6 +22,7 @@ import (
 	"os"
 	"os/exec"
 	"reflect"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -36,14 +37,20 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/lifecyclehooks"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/tracing"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system/compatibility"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	// this is needed to correctly open the sql connection with the pgx driver
@@ -77,9 +84,15 @@ func NewBackupCommand(
 	instance *Instance,
 	log log.Logger,
 ) (*BackupCommand, error) {
-	capabilities, err := barmanCapabilities.CurrentCapabilities()
-	if err != nil {
-		return nil, err
+	// Backups taken by a plugin or stored in a PVC don't depend on the
+	// Barman version installed in the PostgreSQL container, that may
+	// even be missing
+	var capabilities *barmanCapabilities.Capabilities
+	if backup.Spec.Method != apiv1.BackupMethodPlugin && backup.Spec.Method != apiv1.BackupMethodPVC {
+		var err error
+		if capabilities, err = barmanCapabilities.CurrentCapabilities(); err != nil {
+			return nil, err
+		}
 	}
 
 	return &BackupCommand{
@@ -121,6 +134,17 @@ func getDataConfiguration(
 			string(configuration.Data.Encryption))
 	}
 
+	if len(configuration.Data.KMSKeyID) != 0 {
+		if !capabilities.HasKMSKeyID {
+			return nil, fmt.Errorf("custom KMS keys are not supported in Barman %v", capabilities.Version)
+		}
+
+		options = append(
+			options,
+			"--sse-kms-key-id",
+			configuration.Data.KMSKeyID)
+	}
+
 	if configuration.Data.ImmediateCheckpoint {
 		options = append(
 			options,
@@ -134,6 +158,17 @@ func getDataConfiguration(
 			strconv.Itoa(int(*configuration.Data.Jobs)))
 	}
 
+	if configuration.Data.BandwidthLimit != nil {
+		if !capabilities.HasMaxBandwidth {
+			return nil, fmt.Errorf("bandwidth limit is not supported in Barman %v", capabilities.Version)
+		}
+
+		options = append(
+			options,
+			"--max-bandwidth",
+			strconv.FormatInt(configuration.Data.BandwidthLimit.Value(), 10))
+	}
+
 	return options, nil
 }
 
@@ -184,11 +219,13 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 	return options, nil
 }
 
-// Start initiates a backup for this instance using
-// barman-cloud-backup
+// Start initiates a backup for this instance using barman-cloud-backup,
+// pg_basebackup or the configured backup plugin
 func (b *BackupCommand) Start(ctx context.Context) error {
-	if err := b.ensureBarmanCompatibility(); err != nil {
-		return err
+	if !b.isPluginBackup() && !b.isPVCBackup() {
+		if err := b.ensureBarmanCompatibility(); err != nil {
+			return err
+		}
 	}
 
 	b.setupBackupStatus()
@@ -212,14 +249,18 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 		}
 	}
 
-	b.Env, err = barmanCredentials.EnvSetBackupCloudCredentials(
-		ctx,
-		b.Client,
-		b.Cluster.Namespace,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
-		b.Env)
-	if err != nil {
-		return fmt.Errorf("cannot recover backup credentials: %w", err)
+	// The credentials needed by the backup plugin are its own business,
+	// while a backup stored in a PVC doesn't need any
+	if !b.isPluginBackup() && !b.isPVCBackup() {
+		b.Env, err = barmanCredentials.EnvSetBackupCloudCredentials(
+			ctx,
+			b.Client,
+			b.Cluster.Namespace,
+			b.Cluster.Spec.Backup.BarmanObjectStore,
+			b.Env)
+		if err != nil {
+			return fmt.Errorf("cannot recover backup credentials: %w", err)
+		}
 	}
 
 	// Run the actual backup process
@@ -228,6 +269,18 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 	return nil
 }
 
+// isPluginBackup is true when this backup is delegated to
+// the backup plugin configured in the cluster
+func (b *BackupCommand) isPluginBackup() bool {
+	return b.Backup.Spec.Method == apiv1.BackupMethodPlugin
+}
+
+// isPVCBackup is true when this backup is stored in the
+// PersistentVolumeClaim configured in the cluster
+func (b *BackupCommand) isPVCBackup() bool {
+	return b.Backup.Spec.Method == apiv1.BackupMethodPVC
+}
+
 func (b *BackupCommand) ensureBarmanCompatibility() error {
 	postgresVers, err := b.Instance.GetPgVersion()
 	if err != nil {
@@ -261,11 +314,17 @@ func (b *BackupCommand) retryWithRefreshedCluster(
 	})
 }
 
-// run executes the barman-cloud-backup command and updates the status
+// run executes the backup and updates the status
 // This method will take long time and is supposed to run inside a dedicated
 // goroutine.
 func (b *BackupCommand) run(ctx context.Context) {
-	if err := b.takeBackup(ctx); err != nil {
+	ctx, span := tracing.StartClusterSpan(ctx, "Backup", b.Cluster, tracing.BackupNameKey.String(b.Backup.Name))
+	err := b.takeBackup(ctx)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	if err != nil {
 		backupStatus := b.Backup.GetStatus()
 
 		// record the failure
@@ -299,17 +358,8 @@ func (b *BackupCommand) run(ctx context.Context) {
 }
 
 func (b *BackupCommand) takeBackup(ctx context.Context) error {
-	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
-	backupStatus := b.Backup.GetStatus()
-
-	options, backupErr := b.getBarmanCloudBackupOptions(barmanConfiguration, backupStatus.ServerName)
-	if backupErr != nil {
-		b.Log.Error(backupErr, "while getting barman-cloud-backup options")
-		return backupErr
-	}
-
 	// record the backup beginning
-	b.Log.Info("Backup started", "options", options)
+	b.Log.Info("Backup started", "method", b.Backup.Spec.Method)
 	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
 
 	// Update backup status in cluster conditions on startup
@@ -322,46 +372,189 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 		// even if we are unable to communicate with the Kubernetes API server
 	}
 
-	if err := fileutils.EnsureDirectoryExists(postgres.BackupTemporaryDirectory); err != nil {
-		b.Log.Error(err, "Cannot create backup temporary directory", "err", err)
+	if err := lifecyclehooks.Run(ctx, b.Cluster, b.Instance.PodName, lifecyclehooks.EventPreBackup); err != nil {
 		return err
 	}
 
-	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
-	cmd.Env = b.Env
-	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
-	if err := execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup); err != nil {
+	var err error
+	switch {
+	case b.isPluginBackup():
+		err = b.takePluginBackup(ctx)
+	case b.isPVCBackup():
+		err = b.takePVCBackup(ctx)
+	default:
+		err = b.takeBarmanBackup(ctx)
+	}
+	if err != nil {
 		return err
 	}
 
-	b.Log.Info("Backup completed")
-	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup status as completed")
+	}
 
-	// Set the status to completed
-	b.Backup.Status.SetAsCompleted()
+	// Update backup status in cluster conditions on backup completion
+	if err := b.retryWithRefreshedCluster(ctx, func() error {
+		return conditions.Patch(ctx, b.Client, b.Cluster, apiv1.BackupSucceededCondition)
+	}); err != nil {
+		b.Log.Error(err, "Can't update the cluster with the completed backup data")
+	}
+
+	return nil
+}
 
-	barmanBackup, err := b.getExecutedBackupInfo(ctx)
+// takeBarmanBackup takes the backup with barman-cloud-backup, and
+// sets the backup status from the Barman catalog
+func (b *BackupCommand) takeBarmanBackup(ctx context.Context) error {
+	barmanBackup, err := b.ExecuteBarmanBackup(ctx)
 	if err != nil {
 		return err
 	}
 
+	b.setAsCompleted()
+
 	b.Log.Debug("extracted barman backup", "backup", barmanBackup)
 	assignBarmanBackupToBackup(b.Backup, barmanBackup)
+	return nil
+}
 
-	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
-		b.Log.Error(err, "Can't set backup status as completed")
+// takePVCBackup takes the backup with pg_basebackup, storing it in the
+// PersistentVolumeClaim configured in the cluster
+func (b *BackupCommand) takePVCBackup(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+	pvcBackup, err := pvcbackup.TakeBackup(
+		ctx,
+		backupStatus.DestinationPath,
+		backupStatus.BackupID,
+		buildPrimaryConnInfo("localhost", b.Backup.Name, nil),
+	)
+	if err != nil {
+		return err
 	}
 
-	// Update backup status in cluster conditions on backup completion
+	b.setAsCompleted()
+
+	b.Log.Debug("taken pvc backup", "backup", pvcBackup)
+	assignBarmanBackupToBackup(b.Backup, pvcBackup)
+	return nil
+}
+
+// takePluginBackup delegates the backup to the plugin configured in
+// the cluster, and sets the backup status from the plugin response
+func (b *BackupCommand) takePluginBackup(ctx context.Context) error {
+	pluginClient, err := backupplugin.ConnectToClusterPlugin(b.Cluster)
+	if err != nil {
+		return fmt.Errorf("while connecting to the backup plugin: %w", err)
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	result, err := pluginClient.Backup(ctx, b.Cluster, b.Backup)
+	if err != nil {
+		return fmt.Errorf("while taking the backup with the backup plugin: %w", err)
+	}
+
+	b.setAsCompleted()
+
+	b.Log.Debug("backup plugin response", "result", result)
+	assignPluginBackupToBackup(b.Backup, result)
+
+	// The plugin is in charge of the backup catalog, we only keep
+	// track of the last successful backup
 	if err := b.retryWithRefreshedCluster(ctx, func() error {
-		return conditions.Patch(ctx, b.Client, b.Cluster, apiv1.BackupSucceededCondition)
+		origCluster := b.Cluster.DeepCopy()
+
+		b.Cluster.UpdateBackupTimes(apiv1.BackupMethodPlugin, nil, &b.Backup.Status.StoppedAt.Time)
+		return b.Client.Status().Patch(ctx, b.Cluster, client.MergeFrom(origCluster))
 	}); err != nil {
-		b.Log.Error(err, "Can't update the cluster with the completed backup data")
+		b.Log.Error(err, "while setting the lastSuccessfulBackup")
 	}
 
 	return nil
 }
 
+func (b *BackupCommand) setAsCompleted() {
+	b.Log.Info("Backup completed")
+	b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+
+	// Set the status to completed
+	b.Backup.Status.SetAsCompleted()
+}
+
+// ExecuteBarmanBackup runs barman-cloud-backup, waiting for it to
+// complete, and returns the information about the backup taken from
+// the Barman catalog. The backup status is expected to contain the
+// server name and, when supported, the name of the backup
+func (b *BackupCommand) ExecuteBarmanBackup(ctx context.Context) (*catalog.BarmanBackup, error) {
+	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
+	backupStatus := b.Backup.GetStatus()
+
+	options, err := b.getBarmanCloudBackupOptions(barmanConfiguration, backupStatus.ServerName)
+	if err != nil {
+		b.Log.Error(err, "while getting barman-cloud-backup options")
+		return nil, err
+	}
+
+	b.Log.Info("Starting barman-cloud-backup", "options", options)
+
+	if err := fileutils.EnsureDirectoryExists(postgres.BackupTemporaryDirectory); err != nil {
+		b.Log.Error(err, "Cannot create backup temporary directory", "err", err)
+		return nil, err
+	}
+
+	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
+	cmd.Env = b.Env
+	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
+	backupLogger := log.WithName(barmanCapabilities.BarmanCloudBackup).WithValues(
+		"backupName", b.Backup.Name,
+		"startTime", time.Now(),
+	)
+	var streamingCmd *execlog.StreamingCmd
+	start := func() (err error) {
+		streamingCmd, err = execlog.RunStreamingNoWaitWithLogger(
+			cmd, barmanCapabilities.BarmanCloudBackup, backupLogger)
+		return err
+	}
+	if barmanConfiguration.Data != nil && barmanConfiguration.Data.LowPriority {
+		err = b.startWithLowPriority(start)
+	} else {
+		err = start()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := streamingCmd.Wait(); err != nil {
+		return nil, err
+	}
+
+	return b.getExecutedBackupInfo(ctx)
+}
+
+// startWithLowPriority calls the passed function, which starts the backup
+// process, from an OS thread having the lowest CPU and I/O priority. The
+// process inherits the priority when it is created, before it can spawn
+// any child, instead of having it lowered once it is already running
+func (b *BackupCommand) startWithLowPriority(start func() error) error {
+	result := make(chan error, 1)
+	go func() {
+		// The thread is never unlocked, so the Go runtime terminates it
+		// when this goroutine exits instead of reusing it with a lowered
+		// priority
+		runtime.LockOSThread()
+
+		if err := compatibility.LowerThreadPriority(); err != nil {
+			// The backup can still be taken, even if with a higher impact on the workload
+			b.Log.Error(err, "Cannot lower the priority of the backup process")
+		}
+
+		result <- start()
+	}()
+
+	return <-result
+}
+
 func (b *BackupCommand) getExecutedBackupInfo(
 	ctx context.Context,
 ) (*catalog.BarmanBackup, error) {
@@ -385,6 +578,17 @@ func (b *BackupCommand) getExecutedBackupInfo(
 }
 
 func (b *BackupCommand) backupMaintenance(ctx context.Context) {
+	// The retention policy and the catalog of the backups
+	// taken by a plugin are managed by the plugin itself
+	if b.isPluginBackup() {
+		return
+	}
+
+	if b.isPVCBackup() {
+		b.pvcBackupMaintenance(ctx)
+		return
+	}
+
 	// Delete backups per policy
 	if b.Cluster.Spec.Backup.RetentionPolicy != "" {
 		b.Log.Info("Applying backup retention policy",
@@ -416,7 +620,52 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 		origCluster := b.Cluster.DeepCopy()
 
 		// Set the first recoverability point and the last successful backup
-		updateClusterStatusWithBackupTimes(b.Cluster, backupList)
+		updateClusterStatusWithBackupTimes(b.Cluster, apiv1.BackupMethodBarmanObjectStore, backupList)
+
+		if reflect.DeepEqual(origCluster.Status, b.Cluster.Status) {
+			return nil
+		}
+		return b.Client.Status().Patch(ctx, b.Cluster, client.MergeFrom(origCluster))
+	}); err != nil {
+		b.Log.Error(err, "while setting the firstRecoverabilityPoint and latestSuccessfulBackup")
+	}
+}
+
+// pvcBackupMaintenance applies the retention policy to the backups
+// stored in the PersistentVolumeClaim, and updates the cluster status
+// with the content of their catalog
+func (b *BackupCommand) pvcBackupMaintenance(ctx context.Context) {
+	serverDirectory := b.Backup.Status.DestinationPath
+
+	cutoff, err := b.Cluster.Spec.Backup.GetRetentionCutoff(time.Now())
+	if err != nil {
+		b.Log.Error(err, "while parsing the retention policy",
+			"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
+	}
+	if cutoff != nil {
+		b.Log.Info("Applying backup retention policy",
+			"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
+		if err := pvcbackup.DeleteBackupsByPolicy(ctx, serverDirectory, *cutoff); err != nil {
+			b.Log.Error(err, "while applying the retention policy")
+			b.Recorder.Event(b.Cluster, "Warning", "RetentionPolicyFailed", "Retention policy failed")
+			// We do not want to return here, we must go on to set the first recoverability point
+		}
+	}
+
+	backupList, err := pvcbackup.GetCatalog(serverDirectory)
+	if err != nil {
+		b.Log.Error(err, "while reading the catalog of the backups stored in the PVC")
+		return
+	}
+
+	if err := barman.DeleteBackupsNotInCatalog(ctx, b.Client, b.Cluster, backupList); err != nil {
+		b.Log.Error(err, "while deleting Backups not present in the catalog")
+	}
+
+	if err := b.retryWithRefreshedCluster(ctx, func() error {
+		origCluster := b.Cluster.DeepCopy()
+
+		updateClusterStatusWithBackupTimes(b.Cluster, apiv1.BackupMethodPVC, backupList)
 
 		if reflect.DeepEqual(origCluster.Status, b.Cluster.Status) {
 			return nil
@@ -428,15 +677,19 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 }
 
 // updateClusterStatusWithBackupTimes updates the last successful backup time and first
-// recoverability point for the cluster
-func updateClusterStatusWithBackupTimes(cluster *apiv1.Cluster, backupList *catalog.Catalog) {
+// recoverability point of the passed backup method for the cluster
+func updateClusterStatusWithBackupTimes(
+	cluster *apiv1.Cluster,
+	backupMethod apiv1.BackupMethod,
+	backupList *catalog.Catalog,
+) {
 	firstRecoverabilityPoint := backupList.FirstRecoverabilityPoint()
 	var lastSuccessfulBackup *time.Time
 	if lastSuccessfulBackupInfo := backupList.LatestBackupInfo(); lastSuccessfulBackupInfo != nil {
 		lastSuccessfulBackup = &lastSuccessfulBackupInfo.EndTime
 	}
 
-	cluster.UpdateBackupTimes(apiv1.BackupMethodBarmanObjectStore, firstRecoverabilityPoint, lastSuccessfulBackup)
+	cluster.UpdateBackupTimes(backupMethod, firstRecoverabilityPoint, lastSuccessfulBackup)
 }
 
 // PatchBackupStatusAndRetry updates a certain backup's status in the k8s database,
@@ -467,8 +720,25 @@ func PatchBackupStatusAndRetry(
 func (b *BackupCommand) setupBackupStatus() {
 	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
 	backupStatus := b.Backup.GetStatus()
+	backupStatus.Phase = apiv1.BackupPhaseRunning
 
-	if b.Capabilities.ShouldExecuteBackupWithName(b.Cluster) {
+	// Backups stored in a PVC are identified by the time they were
+	// started, and are kept in the directory of the server
+	if b.isPVCBackup() {
+		backupStatus.ServerName = b.Cluster.GetBackupPVCServerName()
+		backupStatus.ClaimName = b.Cluster.GetBackupPVC().ClaimName
+		backupStatus.DestinationPath = pvcbackup.ServerDirectory(b.Cluster)
+		backupStatus.BackupID = utils.ToCompactISO8601(time.Now())
+		return
+	}
+
+	// Backups taken by a third party plugin have no relation with the
+	// Barman object store, while the barman-cloud plugin uses it
+	if barmanConfiguration == nil {
+		return
+	}
+
+	if b.Capabilities != nil && b.Capabilities.ShouldExecuteBackupWithName(b.Cluster) {
 		backupStatus.BackupName = fmt.Sprintf("backup-%v", utils.ToCompactISO8601(time.Now()))
 	}
 	backupStatus.BarmanCredentials = barmanConfiguration.BarmanCredentials
@@ -484,7 +754,6 @@ func (b *BackupCommand) setupBackupStatus() {
 	if backupStatus.ServerName == "" {
 		backupStatus.ServerName = b.Cluster.Name
 	}
-	backupStatus.Phase = apiv1.BackupPhaseRunning
 }
 
 func assignBarmanBackupToBackup(backup *apiv1.Backup, barmanBackup *catalog.BarmanBackup) {
@@ -498,4 +767,25 @@ func assignBarmanBackupToBackup(backup *apiv1.Backup, barmanBackup *catalog.Barm
 	backupStatus.EndWal = barmanBackup.EndWal
 	backupStatus.BeginLSN = barmanBackup.BeginLSN
 	backupStatus.EndLSN = barmanBackup.EndLSN
+	backupStatus.Size = barmanBackup.Size
+	backupStatus.DeduplicatedSize = barmanBackup.DeduplicatedSize
+	backupStatus.UpdateDuration()
+}
+
+func assignPluginBackupToBackup(backup *apiv1.Backup, result *proto.BackupResponse) {
+	backupStatus := backup.GetStatus()
+
+	backupStatus.BackupName = result.BackupName
+	backupStatus.BackupID = result.BackupId
+	if result.StartedAt != 0 {
+		backupStatus.StartedAt = &metav1.Time{Time: time.Unix(result.StartedAt, 0)}
+	}
+	if result.StoppedAt != 0 {
+		backupStatus.StoppedAt = &metav1.Time{Time: time.Unix(result.StoppedAt, 0)}
+	}
+	backupStatus.BeginWal = result.BeginWal
+	backupStatus.EndWal = result.EndWal
+	backupStatus.BeginLSN = result.BeginLsn
+	backupStatus.EndLSN = result.EndLsn
+	backupStatus.UpdateDuration()
 }
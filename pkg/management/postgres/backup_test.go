@@ -17,17 +17,23 @@ limitations under the License.
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin/proto"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -189,7 +195,7 @@ var _ = Describe("update barman backup metadata", func() {
 		Expect(cluster.Status.LastSuccessfulBackup).To(BeEmpty())
 		Expect(cluster.Status.LastSuccessfulBackupByMethod).To(BeEmpty())
 
-		updateClusterStatusWithBackupTimes(cluster, barmanBackups)
+		updateClusterStatusWithBackupTimes(cluster, apiv1.BackupMethodBarmanObjectStore, barmanBackups)
 
 		Expect(cluster.Status.FirstRecoverabilityPoint).To(Equal(twoHoursAgo.Format(time.RFC3339)))
 		Expect(cluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodBarmanObjectStore]).
@@ -215,7 +221,7 @@ var _ = Describe("update barman backup metadata", func() {
 			},
 		}
 
-		updateClusterStatusWithBackupTimes(cluster, barmanBackups)
+		updateClusterStatusWithBackupTimes(cluster, apiv1.BackupMethodBarmanObjectStore, barmanBackups)
 
 		Expect(cluster.Status.FirstRecoverabilityPoint).To(Equal(twoHoursAgo.Format(time.RFC3339)))
 		Expect(cluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodBarmanObjectStore]).
@@ -243,7 +249,7 @@ var _ = Describe("update barman backup metadata", func() {
 			},
 		}
 
-		updateClusterStatusWithBackupTimes(cluster, barmanBackups)
+		updateClusterStatusWithBackupTimes(cluster, apiv1.BackupMethodBarmanObjectStore, barmanBackups)
 
 		Expect(cluster.Status.FirstRecoverabilityPoint).To(Equal(threeHoursAgo.Format(time.RFC3339)))
 		Expect(cluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodBarmanObjectStore]).
@@ -256,4 +262,161 @@ var _ = Describe("update barman backup metadata", func() {
 		Expect(cluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodVolumeSnapshot]).
 			To(Equal(now))
 	})
+
+	It("records the backups stored in a PVC under their own method", func() {
+		updateClusterStatusWithBackupTimes(cluster, apiv1.BackupMethodPVC, barmanBackups)
+
+		Expect(cluster.Status.FirstRecoverabilityPointByMethod[apiv1.BackupMethodPVC]).
+			To(Equal(twoHoursAgo))
+		Expect(cluster.Status.FirstRecoverabilityPointByMethod).
+			ToNot(HaveKey(apiv1.BackupMethodBarmanObjectStore))
+		Expect(cluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodPVC]).
+			To(Equal(oneHourAgo))
+		Expect(cluster.Status.LastSuccessfulBackupByMethod).
+			ToNot(HaveKey(apiv1.BackupMethodBarmanObjectStore))
+	})
+})
+
+var _ = Describe("barman-cloud-backup data options", func() {
+	configuration := &apiv1.BarmanObjectStoreConfiguration{
+		Data: &apiv1.DataBackupConfiguration{
+			BandwidthLimit: ptr.To(resource.MustParse("100Mi")),
+		},
+	}
+
+	It("limits the bandwidth of the backup", func() {
+		capabilities := &barmanCapabilities.Capabilities{HasMaxBandwidth: true}
+		options, err := getDataConfiguration(nil, configuration, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--max-bandwidth", "104857600"}))
+	})
+
+	It("fails when Barman doesn't support the bandwidth limit", func() {
+		capabilities := &barmanCapabilities.Capabilities{}
+		_, err := getDataConfiguration(nil, configuration, capabilities)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("encrypts the backup with a custom KMS key", func() {
+		kmsConfiguration := &apiv1.BarmanObjectStoreConfiguration{
+			Data: &apiv1.DataBackupConfiguration{
+				Encryption: apiv1.EncryptionTypeNoneAWSKMS,
+				KMSKeyID:   "my-key",
+			},
+		}
+		capabilities := &barmanCapabilities.Capabilities{HasKMSKeyID: true}
+		options, err := getDataConfiguration(nil, kmsConfiguration, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--encryption", "aws:kms", "--sse-kms-key-id", "my-key"}))
+	})
+
+	It("fails when Barman doesn't support custom KMS keys", func() {
+		kmsConfiguration := &apiv1.BarmanObjectStoreConfiguration{
+			Data: &apiv1.DataBackupConfiguration{
+				Encryption: apiv1.EncryptionTypeNoneAWSKMS,
+				KMSKeyID:   "my-key",
+			},
+		}
+		_, err := getDataConfiguration(nil, kmsConfiguration, &barmanCapabilities.Capabilities{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("barman backups", func() {
+	It("sets the backup status from the Barman catalog", func() {
+		begin := time.Date(2023, 10, 10, 10, 10, 0, 0, time.UTC)
+		backup := &apiv1.Backup{}
+		assignBarmanBackupToBackup(backup, &catalog.BarmanBackup{
+			ID:               "20231010T101000",
+			BeginTime:        begin,
+			EndTime:          begin.Add(5 * time.Minute),
+			BeginWal:         "000000010000000000000002",
+			EndWal:           "000000010000000000000003",
+			Size:             ptr.To(int64(52428800)),
+			DeduplicatedSize: ptr.To(int64(10485760)),
+		})
+		Expect(backup.Status.BackupID).To(Equal("20231010T101000"))
+		Expect(backup.Status.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(backup.Status.EndWal).To(Equal("000000010000000000000003"))
+		Expect(backup.Status.Size).To(HaveValue(BeEquivalentTo(52428800)))
+		Expect(backup.Status.DeduplicatedSize).To(HaveValue(BeEquivalentTo(10485760)))
+		Expect(backup.Status.Duration).To(HaveValue(Equal(metav1.Duration{Duration: 5 * time.Minute})))
+	})
+})
+
+var _ = Describe("plugin backups", func() {
+	It("sets the backup status from the plugin response", func() {
+		backup := &apiv1.Backup{}
+		assignPluginBackupToBackup(backup, &proto.BackupResponse{
+			BackupId:   "20231010T101010",
+			BackupName: "backup-20231010101010",
+			StartedAt:  1696932610,
+			StoppedAt:  1696932670,
+			BeginWal:   "000000010000000000000002",
+			EndWal:     "000000010000000000000003",
+			BeginLsn:   "0/2000028",
+			EndLsn:     "0/3000100",
+		})
+		Expect(backup.Status.BackupID).To(Equal("20231010T101010"))
+		Expect(backup.Status.BackupName).To(Equal("backup-20231010101010"))
+		Expect(backup.Status.StartedAt.Unix()).To(BeEquivalentTo(1696932610))
+		Expect(backup.Status.StoppedAt.Unix()).To(BeEquivalentTo(1696932670))
+		Expect(backup.Status.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(backup.Status.EndLSN).To(Equal("0/3000100"))
+		Expect(backup.Status.Duration).To(HaveValue(Equal(metav1.Duration{Duration: time.Minute})))
+	})
+
+	It("keeps the completion time when the plugin doesn't report it", func() {
+		stoppedAt := metav1.Now()
+		backup := &apiv1.Backup{Status: apiv1.BackupStatus{StoppedAt: &stoppedAt}}
+		assignPluginBackupToBackup(backup, &proto.BackupResponse{BackupId: "custom"})
+		Expect(backup.Status.StoppedAt).To(Equal(&stoppedAt))
+		Expect(backup.Status.StartedAt).To(BeNil())
+	})
+
+	It("doesn't need Barman for third party plugins", func() {
+		backupCommand := BackupCommand{
+			Cluster: &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					Backup: &apiv1.BackupConfiguration{
+						Plugin: &apiv1.BackupPluginConfiguration{Name: "custom", Image: "custom:latest"},
+					},
+				},
+			},
+			Backup: &apiv1.Backup{Spec: apiv1.BackupSpec{Method: apiv1.BackupMethodPlugin}},
+		}
+		Expect(backupCommand.isPluginBackup()).To(BeTrue())
+
+		backupCommand.setupBackupStatus()
+		Expect(backupCommand.Backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseRunning))
+		Expect(backupCommand.Backup.Status.ServerName).To(BeEmpty())
+	})
+})
+
+var _ = Describe("low priority backups", func() {
+	// getNiceValue returns the nice value from the content of /proc/<pid>/stat
+	getNiceValue := func(stat string) string {
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		return fields[16]
+	}
+
+	It("starts the process with the lowest CPU priority", func() {
+		ownStat, err := os.ReadFile("/proc/self/stat")
+		if err != nil {
+			Skip("the process information is not available")
+		}
+
+		var stdout bytes.Buffer
+		cmd := exec.Command("cat", "/proc/self/stat")
+		cmd.Stdout = &stdout
+
+		b := &BackupCommand{Log: log.WithName("test")}
+		Expect(b.startWithLowPriority(cmd.Start)).To(Succeed())
+		Expect(cmd.Wait()).To(Succeed())
+
+		Expect(getNiceValue(stdout.String())).To(Equal("19"))
+		ownStat, err = os.ReadFile("/proc/self/stat")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getNiceValue(string(ownStat))).ToNot(Equal("19"))
+	})
 })
@@ -83,6 +83,13 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	return postgresConfigurationChanged, nil
 }
 
+// GeneratePostgresqlConfiguration generates the content of the PostgreSQL
+// configuration file the instance manager would install for the cluster
+func GeneratePostgresqlConfiguration(cluster *apiv1.Cluster) (string, error) {
+	postgresConfiguration, _, err := createPostgresqlConfiguration(cluster, false)
+	return postgresConfiguration, err
+}
+
 // GeneratePostgresqlHBA generates the pg_hba.conf content with the LDAP configuration if configured.
 func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBindPassword string) (string, error) {
 	version, err := cluster.GetPostgresqlVersion()
@@ -102,10 +109,18 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 		defaultAuthenticationMethod = "md5"
 	}
 
+	// While a connection storm is in progress we may be required to
+	// reject the new connections of the applications
+	rejectNonSuperuserConnections := instance.IsConnectionStormInProgress() &&
+		cluster.Spec.PostgresConfiguration.ConnectionStormProtection.GetMitigation() ==
+			apiv1.ConnectionStormMitigationReject
+
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
 		defaultAuthenticationMethod,
-		buildLDAPConfigString(cluster, ldapBindPassword))
+		buildLDAPConfigString(cluster, ldapBindPassword),
+		rejectNonSuperuserConnections,
+		cluster.Spec.HostNetwork)
 }
 
 // RefreshPGHBA generates and writes down the pg_hba.conf file
@@ -422,6 +437,7 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
+		WALTuningSettings:                cluster.GetWALTuningSettings(),
 	}
 
 	if preserveUserSettings {
@@ -435,8 +451,13 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	info.SyncReplicas = syncReplicas
 	info.SyncReplicasElectable = electable
 
-	// Ensure a consistent ordering to avoid spurious configuration changes
-	sort.Strings(info.SyncReplicasElectable)
+	if synchronous := cluster.Spec.PostgresConfiguration.Synchronous; synchronous != nil {
+		// The order of the standby names is decided by the user
+		info.SyncReplicasMethod = strings.ToUpper(string(synchronous.Method))
+	} else {
+		// Ensure a consistent ordering to avoid spurious configuration changes
+		sort.Strings(info.SyncReplicasElectable)
+	}
 
 	// Set cluster name
 	info.ClusterName = cluster.Name
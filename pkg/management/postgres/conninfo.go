@@ -23,8 +23,15 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+// defaultConnectTimeout is the connection timeout used when cloning
+// or waiting for the primary, unless a different one is configured
+const defaultConnectTimeout = 5
+
 // buildPrimaryConnInfo builds the connection string to connect to primaryHostname
-func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
+func buildPrimaryConnInfo(
+	primaryHostname, applicationName string,
+	connection *apiv1.ReplicationConnectionConfiguration,
+) string {
 	// We should have been using configfile.CreateConnectionString
 	// but doing that we would cause an unnecessary restart of
 	// existing PostgreSQL 12 clusters.
@@ -35,6 +42,39 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		fmt.Sprintf("sslcert=%v ", postgres.StreamingReplicaCertificateLocation) +
 		fmt.Sprintf("sslrootcert=%v ", postgres.ServerCACertificateLocation) +
 		fmt.Sprintf("application_name=%v ", applicationName) +
-		"sslmode=verify-ca"
+		fmt.Sprintf("sslmode=%v", connection.GetSslMode())
+
+	// The tuning parameters are only added when specified, so that
+	// the connection string of existing clusters doesn't change
+	if connection != nil {
+		for _, parameter := range []struct {
+			name  string
+			value int32
+		}{
+			{name: "connect_timeout", value: connection.ConnectTimeout},
+			{name: "keepalives_idle", value: connection.KeepalivesIdle},
+			{name: "keepalives_interval", value: connection.KeepalivesInterval},
+			{name: "keepalives_count", value: connection.KeepalivesCount},
+			{name: "tcp_user_timeout", value: connection.TCPUserTimeout},
+		} {
+			if parameter.value > 0 {
+				primaryConnInfo += fmt.Sprintf(" %v=%v", parameter.name, parameter.value)
+			}
+		}
+	}
+
 	return primaryConnInfo
 }
+
+// withDefaultConnectTimeout adds the default connection timeout to a
+// connection string built by buildPrimaryConnInfo, unless a different
+// one has been configured
+func withDefaultConnectTimeout(
+	primaryConnInfo string,
+	connection *apiv1.ReplicationConnectionConfiguration,
+) string {
+	if connection.GetConnectTimeout() > 0 {
+		return primaryConnInfo
+	}
+	return fmt.Sprintf("%v connect_timeout=%v", primaryConnInfo, defaultConnectTimeout)
+}
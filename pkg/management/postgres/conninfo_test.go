@@ -0,0 +1,57 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary connection string", func() {
+	It("doesn't change when no tuning is configured", func() {
+		Expect(buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2", nil)).To(Equal(
+			"host=cluster-example-rw user=streaming_replica port=5432 " +
+				"sslkey=/controller/certificates/streaming_replica.key " +
+				"sslcert=/controller/certificates/streaming_replica.crt " +
+				"sslrootcert=/controller/certificates/server-ca.crt " +
+				"application_name=cluster-example-2 sslmode=verify-ca"))
+	})
+
+	It("adds the configured tuning parameters", func() {
+		connInfo := buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2",
+			&apiv1.ReplicationConnectionConfiguration{
+				ConnectTimeout:     10,
+				KeepalivesIdle:     30,
+				KeepalivesInterval: 5,
+				KeepalivesCount:    3,
+				TCPUserTimeout:     45000,
+				SslMode:            apiv1.ReplicationSslModeVerifyFull,
+			})
+		Expect(connInfo).To(HaveSuffix("application_name=cluster-example-2 sslmode=verify-full " +
+			"connect_timeout=10 keepalives_idle=30 keepalives_interval=5 keepalives_count=3 " +
+			"tcp_user_timeout=45000"))
+	})
+
+	It("uses the default connection timeout only when none is configured", func() {
+		Expect(withDefaultConnectTimeout("host=pg", nil)).To(Equal("host=pg connect_timeout=5"))
+		Expect(withDefaultConnectTimeout("host=pg connect_timeout=10",
+			&apiv1.ReplicationConnectionConfiguration{ConnectTimeout: 10})).
+			To(Equal("host=pg connect_timeout=10"))
+	})
+})
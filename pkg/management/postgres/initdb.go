@@ -365,7 +365,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	}
 
 	if postgresVersion >= 120000 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetPrimaryConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresOverrideConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -388,10 +388,30 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 			}
 		}
 
+		if cluster.Spec.Bootstrap != nil &&
+			cluster.Spec.Bootstrap.InitDB != nil &&
+			cluster.Spec.Bootstrap.InitDB.ImportFromDump != nil {
+			err = executeDumpImport(ctx, instance, cluster)
+			if err != nil {
+				return fmt.Errorf("while importing the dump: %w", err)
+			}
+		}
+
 		return nil
 	})
 }
 
+func executeDumpImport(
+	ctx context.Context,
+	instance *Instance,
+	cluster *apiv1.Cluster,
+) error {
+	destinationPool := instance.ConnectionPool()
+	defer destinationPool.ShutdownConnections()
+
+	return logicalimport.ImportDump(ctx, cluster, destinationPool)
+}
+
 func executeLogicalImport(
 	ctx context.Context,
 	client ctrl.Client,
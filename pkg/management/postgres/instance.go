@@ -36,6 +36,7 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
@@ -169,12 +170,23 @@ type Instance struct {
 	// PgRewindIsRunning tells if there is a `pg_rewind` process running
 	PgRewindIsRunning bool
 
+	// RecloneIsRunning tells if the instance is cloning the primary again
+	// after having been found on an older timeline
+	RecloneIsRunning atomic.Bool
+
 	// MaxStopDelay is the current MaxStopDelay of the cluster
 	MaxStopDelay int32
 
 	// SmartStopDelay is used to control PostgreSQL smart shutdown timeout
 	SmartStopDelay int32
 
+	// ReplicationConnection is the tuning of the connection to the primary
+	ReplicationConnection *apiv1.ReplicationConnectionConfiguration
+
+	// PrimaryService is the name of the service selecting the primary,
+	// the read-write one of the cluster when empty
+	PrimaryService string
+
 	// canCheckReadiness specifies whether the instance can start being checked for readiness
 	// Is set to true before the instance is run and to false once it exits,
 	// it's used by the readiness probe to know whether it should be short-circuited
@@ -187,6 +199,17 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// connectionStormInProgress specifies whether the instance is experiencing
+	// a connection storm, as detected by the connection storm guard
+	connectionStormInProgress atomic.Bool
+
+	// skipSmartShutdown specifies whether the smart shutdown has to be skipped
+	// when PostgreSQL is stopped, as requested by the preStop hook
+	skipSmartShutdown atomic.Bool
+
+	// storageCheck holds the result of the last check of the volumes
+	storageCheck storageCheckCache
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
 
@@ -210,6 +233,42 @@ func (instance *Instance) SetAlterSystemEnabled(enabled bool) error {
 	return os.Chmod(autoConfFileName, mode)
 }
 
+// HasAlterSystemParameters checks whether the `postgresql.auto.conf` file
+// in PGDATA still sets any of the passed parameters
+func (instance *Instance) HasAlterSystemParameters(names ...string) (bool, error) {
+	autoConfContent, err := fileutils.ReadFileLines(path.Join(instance.PgData, "postgresql.auto.conf"))
+	if err != nil {
+		return false, fmt.Errorf("while reading postgresql.auto.conf file: %w", err)
+	}
+
+	return len(configfile.ReadLinesFromConfigurationContents(autoConfContent, names...)) > 0, nil
+}
+
+// ResetAlterSystemParameters removes every parameter set with ALTER SYSTEM
+// and reloads the configuration. The `postgresql.auto.conf` file is made
+// writable for the time needed, as this is only used when the usage of
+// ALTER SYSTEM is denied
+func (instance *Instance) ResetAlterSystemParameters(ctx context.Context) (err error) {
+	if err := instance.SetAlterSystemEnabled(true); err != nil {
+		return err
+	}
+	defer func() {
+		if modeErr := instance.SetAlterSystemEnabled(false); err == nil {
+			err = modeErr
+		}
+	}()
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+	if _, err := superUserDB.ExecContext(ctx, "ALTER SYSTEM RESET ALL"); err != nil {
+		return err
+	}
+
+	return instance.Reload(ctx)
+}
+
 // IsFenced checks whether the instance is marked as fenced
 func (instance *Instance) IsFenced() bool {
 	return instance.fenced.Load()
@@ -238,6 +297,21 @@ func (instance *Instance) SetCanCheckReadiness(enabled bool) {
 	instance.canCheckReadiness.Store(enabled)
 }
 
+// IsConnectionStormInProgress checks whether the instance is experiencing a connection storm
+func (instance *Instance) IsConnectionStormInProgress() bool {
+	return instance.connectionStormInProgress.Load()
+}
+
+// SetConnectionStormInProgress marks whether the instance is experiencing a connection storm
+func (instance *Instance) SetConnectionStormInProgress(inProgress bool) {
+	instance.connectionStormInProgress.Store(inProgress)
+}
+
+// SetSkipSmartShutdown marks whether the smart shutdown has to be skipped when stopping PostgreSQL
+func (instance *Instance) SetSkipSmartShutdown(skip bool) {
+	instance.skipSmartShutdown.Store(skip)
+}
+
 // SetMightBeUnavailable marks whether the instance being down should be tolerated
 func (instance *Instance) SetMightBeUnavailable(enabled bool) {
 	instance.mightBeUnavailable.Store(enabled)
@@ -467,7 +541,10 @@ func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 	var err error
 
 	smartTimeout := instance.SmartStopDelay
-	if instance.MaxStopDelay <= instance.SmartStopDelay {
+	if instance.skipSmartShutdown.Load() {
+		contextLogger.Info("Skipping the smart shutdown, as requested by the preStop hook")
+		smartTimeout = 0
+	} else if instance.MaxStopDelay <= instance.SmartStopDelay {
 		contextLogger.Warning("Ignoring maxStopDelay <= smartShutdownTimeout",
 			"smartShutdownTimeout", instance.SmartStopDelay,
 			"maxStopDelay", instance.MaxStopDelay,
@@ -737,7 +814,8 @@ func (instance *Instance) Demote(ctx context.Context, cluster *apiv1.Cluster) er
 
 // WaitForPrimaryAvailable waits until we can connect to the primary
 func (instance *Instance) WaitForPrimaryAvailable() error {
-	primaryConnInfo := instance.GetPrimaryConnInfo() + " dbname=postgres connect_timeout=5"
+	primaryConnInfo := withDefaultConnectTimeout(
+		instance.GetPrimaryConnInfo()+" dbname=postgres", instance.ReplicationConnection)
 
 	log.Info("Waiting for the new primary to be available",
 		"primaryConnInfo", primaryConnInfo)
@@ -767,6 +845,20 @@ func (instance *Instance) CompleteCrashRecovery() error {
 	return instance.WithActiveInstance(instance.WaitForSuperuserConnectionAvailable)
 }
 
+// Checkpoint issues a checkpoint on the instance
+func (instance *Instance) Checkpoint(ctx context.Context) error {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, "CHECKPOINT"); err != nil {
+		return fmt.Errorf("while issuing a checkpoint: %w", err)
+	}
+
+	return nil
+}
+
 // WaitForSuperuserConnectionAvailable waits until we can connect to this
 // instance using the superuser account
 func (instance *Instance) WaitForSuperuserConnectionAvailable() error {
@@ -1160,7 +1252,11 @@ func (instance *Instance) DropConnections() error {
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
 func (instance *Instance) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
+	primaryService := instance.PrimaryService
+	if primaryService == "" {
+		primaryService = instance.ClusterName + apiv1.ServiceReadWriteSuffix
+	}
+	return buildPrimaryConnInfo(primaryService, instance.PodName, instance.ReplicationConnection)
 }
 
 // HandleInstanceCommandRequests execute a command requested by the reconciliation
@@ -261,4 +261,13 @@ var _ = Describe("ALTER SYSTEM enable and disable", func() {
 
 		Expect(info.Mode()).To(BeEquivalentTo(0o400))
 	})
+
+	It("should detect the parameters set with ALTER SYSTEM", func() {
+		Expect(instance.HasAlterSystemParameters("work_mem")).To(BeFalse())
+
+		Expect(os.WriteFile(autoConfFile, []byte(
+			"# Do not edit this file manually!\nwork_mem = '64MB'\n"), 0o600)).To(Succeed())
+		Expect(instance.HasAlterSystemParameters("work_mem")).To(BeTrue())
+		Expect(instance.HasAlterSystemParameters("log_statement")).To(BeFalse())
+	})
 })
@@ -70,7 +70,28 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 
 // Join creates a new instance joined to an existing PostgreSQL cluster
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
-	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
+	primaryConnInfo := buildCloneConnInfo(
+		cluster,
+		buildPrimaryConnInfo(info.ParentNode, info.PodName, cluster.Spec.ReplicationConnection))
+
+	coredumpFilter := cluster.GetCoredumpFilter()
+	if err := system.SetCoredumpFilter(coredumpFilter); err != nil {
+		return err
+	}
+
+	if err := ClonePgData(primaryConnInfo, info.PgData, info.PgWal); err != nil {
+		return err
+	}
+
+	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
+	_, err := UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(cluster), slotName)
+	return err
+}
+
+// buildCloneConnInfo builds the connection string used by pg_basebackup
+// to clone the primary, starting from the primary connection string
+func buildCloneConnInfo(cluster *apiv1.Cluster, primaryConnInfo string) string {
+	primaryConnInfo = withDefaultConnectTimeout(primaryConnInfo+" dbname=postgres", cluster.Spec.ReplicationConnection)
 
 	pgVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
@@ -85,16 +106,5 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 		primaryConnInfo += " options='-c wal_sender_timeout=0s'"
 	}
 
-	coredumpFilter := cluster.GetCoredumpFilter()
-	if err := system.SetCoredumpFilter(coredumpFilter); err != nil {
-		return err
-	}
-
-	if err = ClonePgData(primaryConnInfo, info.PgData, info.PgWal); err != nil {
-		return err
-	}
-
-	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
-	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
-	return err
+	return primaryConnInfo
 }
@@ -18,6 +18,7 @@ package logicalimport
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os/exec"
 
@@ -193,7 +194,7 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 		return err
 	}
 
-	if _, err = db.Exec(fmt.Sprintf("ALTER USER %s SUPERUSER", pgx.Identifier{owner}.Sanitize())); err != nil {
+	if err = setSuperuser(db, owner, true); err != nil {
 		return err
 	}
 
@@ -227,11 +228,18 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 
 	contextLogger.Info("removing superuser permission from owner user",
 		"owner", owner)
-	if _, err = db.Exec(fmt.Sprintf("ALTER USER %s NOSUPERUSER", pgx.Identifier{owner}.Sanitize())); err != nil {
-		return err
+	return setSuperuser(db, owner, false)
+}
+
+// setSuperuser grants or revokes the superuser permission to the passed role
+func setSuperuser(db *sql.DB, role string, superuser bool) error {
+	option := "NOSUPERUSER"
+	if superuser {
+		option = "SUPERUSER"
 	}
 
-	return nil
+	_, err := db.Exec(fmt.Sprintf("ALTER USER %s %s", pgx.Identifier{role}.Sanitize(), option))
+	return err
 }
 
 func (ds *databaseSnapshotter) databaseExists(
@@ -0,0 +1,286 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"k8s.io/utils/strings/slices"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// downloadedDumpFileName is the name of the file where a dump
+// downloaded from an object store is stored
+const downloadedDumpFileName = dumpDirectory + "/import.dump"
+
+// ImportDump restores a dump file, stored in a PersistentVolumeClaim or
+// in an object store, into the application database
+func ImportDump(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	destination pool.Pooler,
+) error {
+	contextLogger := log.FromContext(ctx)
+	ds := databaseSnapshotter{cluster: cluster}
+	initDB := cluster.Spec.Bootstrap.InitDB
+	contextLogger.Info("starting dump import process", "format", initDB.ImportFromDump.GetFormat())
+
+	dumpPath, err := getDumpPath(ctx, initDB.ImportFromDump)
+	if err != nil {
+		return err
+	}
+
+	if err := ds.dropExtensionsFromDatabase(ctx, destination, initDB.Database); err != nil {
+		return err
+	}
+
+	db, err := destination.Connection(initDB.Database)
+	if err != nil {
+		return err
+	}
+
+	// The dump may contain "CREATE EXTENSION" and/or "COMMENT ON EXTENSION"
+	// statements, and to execute them we'll generically need to be
+	// superusers on the target database.
+	contextLogger.Info("temporarily granting superuser permission to owner user",
+		"owner", initDB.Owner)
+	if err := setSuperuser(db, initDB.Owner, true); err != nil {
+		return err
+	}
+
+	createdRoles, err := createMissingMappedRoles(ctx, db, initDB.ImportFromDump.OwnerMapping)
+	if err != nil {
+		return err
+	}
+
+	command, options := buildDumpRestoreCommand(
+		initDB.ImportFromDump,
+		destination.GetDsn(initDB.Database),
+		initDB.Owner,
+		dumpPath,
+	)
+	contextLogger.Info("Restoring the dump", "cmd", command, "options", options)
+	restoreCommand := exec.Command(command, options...) // #nosec
+	if err := execlog.RunStreaming(restoreCommand, command); err != nil {
+		return fmt.Errorf("error while executing %s: %w", command, err)
+	}
+
+	if err := reassignMappedOwnership(ctx, db, initDB.ImportFromDump.OwnerMapping, createdRoles); err != nil {
+		return err
+	}
+
+	contextLogger.Info("removing superuser permission from owner user",
+		"owner", initDB.Owner)
+	if err := setSuperuser(db, initDB.Owner, false); err != nil {
+		return err
+	}
+
+	if err := cleanDumpDirectory(); err != nil {
+		return err
+	}
+
+	return ds.analyze(ctx, destination, []string{initDB.Database})
+}
+
+// getDumpPath returns the path of the dump file to be restored,
+// downloading it when it is stored in an object store
+func getDumpPath(ctx context.Context, importFromDump *apiv1.ImportFromDump) (string, error) {
+	if importFromDump.Location.PersistentVolumeClaim != nil {
+		return filepath.Join(specs.ImportDumpVolumePath, importFromDump.Location.PersistentVolumeClaim.Path), nil
+	}
+
+	url := os.Getenv(specs.ImportDumpURLEnvVar)
+	if url == "" {
+		return "", fmt.Errorf("missing URL of the dump in the %s environment variable", specs.ImportDumpURLEnvVar)
+	}
+
+	if err := createDumpsDirectory(); err != nil {
+		return "", err
+	}
+
+	// The URL may embed the credentials to access the object store,
+	// so we never log it
+	log.FromContext(ctx).Info("downloading the dump", "destination", downloadedDumpFileName)
+	if err := downloadDump(ctx, url, downloadedDumpFileName); err != nil {
+		return "", err
+	}
+
+	return downloadedDumpFileName, nil
+}
+
+// downloadDump stores the content available at the passed URL
+// in the destination file
+func downloadDump(ctx context.Context, url string, destination string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("while creating the dump download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// Avoid leaking the URL, which is contained in the error
+		return fmt.Errorf("while downloading the dump: %w", errors.Unwrap(err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("while downloading the dump: unexpected status %s", resp.Status)
+	}
+
+	file, err := os.Create(destination) // #nosec
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("while downloading the dump: %w", err)
+	}
+
+	return file.Close()
+}
+
+// buildDumpRestoreCommand returns the command, and its options, needed to
+// restore the passed dump file into the database with the passed DSN.
+// Unless requested to preserve the ownership, objects are owned by the
+// passed owner
+func buildDumpRestoreCommand(
+	importFromDump *apiv1.ImportFromDump,
+	dsn string,
+	owner string,
+	dumpPath string,
+) (executable, []string) {
+	if importFromDump.GetFormat() == apiv1.DumpFormatPlain {
+		options := []string{
+			"-U", "postgres",
+			"-d", dsn,
+			"-v", "ON_ERROR_STOP=1",
+			"--single-transaction",
+		}
+		if !importFromDump.PreserveOwnership {
+			options = append(options, "-c", fmt.Sprintf("SET ROLE %s", pgx.Identifier{owner}.Sanitize()))
+		}
+		options = append(options, "-f", dumpPath)
+		return psql, options
+	}
+
+	options := []string{
+		"-U", "postgres",
+		"-d", dsn,
+		fmt.Sprintf("--format=%s", importFromDump.GetFormat()),
+	}
+	if !importFromDump.PreserveOwnership {
+		options = append(options,
+			"--no-owner",
+			"--no-privileges",
+			fmt.Sprintf("--role=%s", owner),
+		)
+	}
+	options = append(options, dumpPath)
+	return pgRestore, options
+}
+
+// createMissingMappedRoles creates the source roles of the owner mapping
+// which don't exist in the cluster, as restoring the ownership recorded
+// in the dump requires them. The created roles can't log in and are
+// returned, to be dropped once the ownership has been reassigned
+func createMissingMappedRoles(
+	ctx context.Context,
+	db *sql.DB,
+	ownerMapping map[string]string,
+) ([]string, error) {
+	var createdRoles []string
+	for _, source := range sortedSourceRoles(ownerMapping) {
+		var exists bool
+		row := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)", source)
+		if err := row.Scan(&exists); err != nil {
+			return nil, fmt.Errorf("while checking if role %s exists: %w", source, err)
+		}
+		if exists {
+			continue
+		}
+
+		log.FromContext(ctx).Info("temporarily creating the role owning objects in the dump", "role", source)
+		if _, err := db.Exec(fmt.Sprintf("CREATE ROLE %s NOLOGIN", pgx.Identifier{source}.Sanitize())); err != nil {
+			return nil, fmt.Errorf("while creating role %s: %w", source, err)
+		}
+		createdRoles = append(createdRoles, source)
+	}
+
+	return createdRoles, nil
+}
+
+// reassignMappedOwnership moves the ownership of the objects owned by the
+// source roles of the owner mapping to their target roles, dropping the
+// source roles created by createMissingMappedRoles together with the
+// privileges granted to them
+func reassignMappedOwnership(
+	ctx context.Context,
+	db *sql.DB,
+	ownerMapping map[string]string,
+	createdRoles []string,
+) error {
+	for _, source := range sortedSourceRoles(ownerMapping) {
+		target := ownerMapping[source]
+		log.FromContext(ctx).Info("reassigning the ownership of the imported objects",
+			"from", source, "to", target)
+		if _, err := db.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO %s",
+			pgx.Identifier{source}.Sanitize(), pgx.Identifier{target}.Sanitize())); err != nil {
+			return fmt.Errorf("while reassigning the objects owned by %s to %s: %w", source, target, err)
+		}
+
+		if !slices.Contains(createdRoles, source) {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("DROP OWNED BY %s", pgx.Identifier{source}.Sanitize())); err != nil {
+			return fmt.Errorf("while dropping the privileges of role %s: %w", source, err)
+		}
+		if _, err := db.Exec(fmt.Sprintf("DROP ROLE %s", pgx.Identifier{source}.Sanitize())); err != nil {
+			return fmt.Errorf("while dropping role %s: %w", source, err)
+		}
+	}
+
+	return nil
+}
+
+// sortedSourceRoles gets the source roles of the owner mapping,
+// in a stable order
+func sortedSourceRoles(ownerMapping map[string]string) []string {
+	roles := make([]string, 0, len(ownerMapping))
+	for source := range ownerMapping {
+		roles = append(roles, source)
+	}
+	sort.Strings(roles)
+	return roles
+}
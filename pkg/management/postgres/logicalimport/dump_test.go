@@ -0,0 +1,150 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildDumpRestoreCommand", func() {
+	It("restores custom dumps with pg_restore, remapping the ownership", func() {
+		command, options := buildDumpRestoreCommand(&apiv1.ImportFromDump{}, "dsn", "app", "/dump/app.dump")
+		Expect(command).To(Equal(pgRestore))
+		Expect(options).To(Equal([]string{
+			"-U", "postgres",
+			"-d", "dsn",
+			"--format=custom",
+			"--no-owner",
+			"--no-privileges",
+			"--role=app",
+			"/dump/app.dump",
+		}))
+	})
+
+	It("preserves the ownership when requested", func() {
+		command, options := buildDumpRestoreCommand(
+			&apiv1.ImportFromDump{Format: apiv1.DumpFormatTar, PreserveOwnership: true},
+			"dsn", "app", "/dump/app.tar",
+		)
+		Expect(command).To(Equal(pgRestore))
+		Expect(options).To(Equal([]string{"-U", "postgres", "-d", "dsn", "--format=tar", "/dump/app.tar"}))
+	})
+
+	It("restores plain dumps with psql in a single transaction", func() {
+		command, options := buildDumpRestoreCommand(
+			&apiv1.ImportFromDump{Format: apiv1.DumpFormatPlain},
+			"dsn", "app", "/dump/app.sql",
+		)
+		Expect(command).To(Equal(psql))
+		Expect(options).To(Equal([]string{
+			"-U", "postgres",
+			"-d", "dsn",
+			"-v", "ON_ERROR_STOP=1",
+			"--single-transaction",
+			"-c", `SET ROLE "app"`,
+			"-f", "/dump/app.sql",
+		}))
+	})
+})
+
+var _ = Describe("owner mapping", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	ownerMapping := map[string]string{"legacy": "app", "reports": "app"}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates only the missing source roles", func(ctx context.Context) {
+		query := "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1)"
+		mock.ExpectQuery(query).WithArgs("legacy").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(`CREATE ROLE "legacy" NOLOGIN`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(query).WithArgs("reports").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		createdRoles, err := createMissingMappedRoles(ctx, db, ownerMapping)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(createdRoles).To(Equal([]string{"legacy"}))
+	})
+
+	It("reassigns the ownership, dropping the created roles", func(ctx context.Context) {
+		mock.ExpectExec(`REASSIGN OWNED BY "legacy" TO "app"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DROP OWNED BY "legacy"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DROP ROLE "legacy"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`REASSIGN OWNED BY "reports" TO "app"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reassignMappedOwnership(ctx, db, ownerMapping, []string{"legacy"})).To(Succeed())
+	})
+})
+
+var _ = Describe("downloadDump", func() {
+	It("stores the downloaded content in the destination file", func(ctx context.Context) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("dump content"))
+		}))
+		defer server.Close()
+
+		destination := filepath.Join(GinkgoT().TempDir(), "import.dump")
+		Expect(downloadDump(ctx, server.URL, destination)).To(Succeed())
+		Expect(os.ReadFile(destination)).To(BeEquivalentTo("dump content"))
+	})
+
+	It("fails without leaking the URL when the dump can't be found", func(ctx context.Context) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		err := downloadDump(ctx, server.URL+"/secret-token", filepath.Join(GinkgoT().TempDir(), "import.dump"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("404"))
+		Expect(err.Error()).ToNot(ContainSubstring("secret-token"))
+	})
+})
+
+var _ = Describe("getDumpPath", func() {
+	It("points to the file inside the mounted claim", func(ctx context.Context) {
+		path, err := getDumpPath(ctx, &apiv1.ImportFromDump{
+			Location: apiv1.DumpLocation{
+				PersistentVolumeClaim: &apiv1.DumpVolumeSource{ClaimName: "dumps", Path: "app/app.dump"},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(path).To(Equal("/var/lib/postgresql/dump/app/app.dump"))
+	})
+})
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,6 +49,18 @@ type QueriesCollector struct {
 
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
+
+	// The metrics collected by the queries having `cache_seconds` set,
+	// indexed by query name and target database
+	cachedMetrics     map[string]cachedQueryMetrics
+	cachedMetricsLock *sync.Mutex
+}
+
+// cachedQueryMetrics are the metrics collected by a query, that will
+// be exposed again instead of running the query until they expire
+type cachedQueryMetrics struct {
+	metrics   []prometheus.Metric
+	expiresAt time.Time
 }
 
 // Name returns the name of this collector, as supplied by the user in the configMap
@@ -126,7 +140,7 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 				continue
 			}
 
-			err = collector.collect(conn, ch)
+			err = q.collectWithCache(name, targetDatabase, collector, conn, ch)
 			if err != nil {
 				queryLogger.Error(err, "Error collecting user query",
 					"targetDatabase", targetDatabase)
@@ -138,6 +152,76 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 	return nil
 }
 
+// collectWithCache runs a query on a target database, unless the metrics
+// collected by a previous run are still valid because of `cache_seconds`
+func (q QueriesCollector) collectWithCache(
+	name string,
+	targetDatabase string,
+	collector QueryCollector,
+	conn *sql.DB,
+	ch chan<- prometheus.Metric,
+) error {
+	if collector.userQuery.CacheSeconds == 0 {
+		return collector.collect(conn, ch)
+	}
+
+	cacheKey := name + "/" + targetDatabase
+	if metrics, ok := q.getCachedMetrics(cacheKey); ok {
+		for _, metric := range metrics {
+			ch <- metric
+		}
+		return nil
+	}
+
+	metricsCh := make(chan prometheus.Metric)
+	metricsDone := make(chan struct{})
+	var metrics []prometheus.Metric
+	go func() {
+		defer close(metricsDone)
+		for metric := range metricsCh {
+			metrics = append(metrics, metric)
+		}
+	}()
+
+	err := collector.collect(conn, metricsCh)
+	close(metricsCh)
+	<-metricsDone
+
+	for _, metric := range metrics {
+		ch <- metric
+	}
+	if err != nil {
+		return err
+	}
+
+	q.setCachedMetrics(cacheKey, metrics, time.Duration(collector.userQuery.CacheSeconds)*time.Second)
+	return nil
+}
+
+// getCachedMetrics gets the metrics cached with a certain key, if they
+// are not expired
+func (q QueriesCollector) getCachedMetrics(key string) ([]prometheus.Metric, bool) {
+	q.cachedMetricsLock.Lock()
+	defer q.cachedMetricsLock.Unlock()
+
+	cached, ok := q.cachedMetrics[key]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return nil, false
+	}
+	return cached.metrics, true
+}
+
+// setCachedMetrics caches the metrics collected by a query
+func (q QueriesCollector) setCachedMetrics(key string, metrics []prometheus.Metric, duration time.Duration) {
+	q.cachedMetricsLock.Lock()
+	defer q.cachedMetricsLock.Unlock()
+
+	q.cachedMetrics[key] = cachedQueryMetrics{
+		metrics:   metrics,
+		expiresAt: time.Now().Add(duration),
+	}
+}
+
 func (q QueriesCollector) toBeChecked(name string, userQuery UserQuery, isPrimary bool, queryLogger log.Logger) bool {
 	if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
 		queryLogger.Debug("Skipping because runs only on primary")
@@ -258,6 +342,9 @@ func NewQueriesCollector(
 		variableLabels: make(map[string]VariableSet),
 		userQueries:    make(UserQueries),
 		defaultDBName:  defaultDBName,
+
+		cachedMetrics:     make(map[string]cachedQueryMetrics),
+		cachedMetricsLock: &sync.Mutex{},
 		errorUserQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: name,
 			Name:      "errors_total",
@@ -360,7 +447,17 @@ func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error
 		return nil
 	}
 
+	var collectedRows uint64
 	for rows.Next() {
+		// Limit the cardinality of the generated metrics
+		if c.userQuery.MaxRows > 0 && collectedRows >= c.userQuery.MaxRows {
+			log.Warning("Query returned more than the maximum number of rows, ignoring the remaining ones",
+				"name", c.namespace,
+				"maxRows", c.userQuery.MaxRows)
+			break
+		}
+		collectedRows++
+
 		if err = rows.Scan(scanArgs...); err != nil {
 			return err
 		}
@@ -17,6 +17,11 @@ limitations under the License.
 package metrics
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/prometheus/client_golang/prometheus"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -150,3 +155,112 @@ var _ = Describe("QueryCollector tests", func() {
 		})
 	})
 })
+
+var _ = Describe("QueryCollector limits", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	newQueryCollector := func(userQuery UserQuery) QueryCollector {
+		columnMapping, variableLabels := userQuery.ToMetricMap("test_sequences")
+		return QueryCollector{
+			namespace:      "test_sequences",
+			userQuery:      userQuery,
+			columnMapping:  columnMapping,
+			variableLabels: variableLabels,
+		}
+	}
+
+	expectQuery := func(rows *sqlmock.Rows) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SET application_name TO cnpg_metrics_exporter").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET standard_conforming_strings TO on").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET ROLE TO pg_monitor").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT sequencename, used_ratio FROM sequences").WillReturnRows(rows)
+		mock.ExpectCommit()
+	}
+
+	userQuery := UserQuery{
+		Query: "SELECT sequencename, used_ratio FROM sequences",
+		Metrics: []Mapping{
+			{"sequencename": ColumnMapping{Usage: LABEL}},
+			{"used_ratio": ColumnMapping{Usage: GAUGE}},
+		},
+	}
+
+	sequenceRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"sequencename", "used_ratio"}).
+			AddRow("seq1", 0.9).
+			AddRow("seq2", 0.5).
+			AddRow("seq3", 0.1)
+	}
+
+	It("collects all the rows when max_rows is not set", func() {
+		expectQuery(sequenceRows())
+
+		ch := make(chan prometheus.Metric, 10)
+		Expect(newQueryCollector(userQuery).collect(db, ch)).To(Succeed())
+		Expect(ch).To(HaveLen(3))
+	})
+
+	It("ignores the rows exceeding max_rows", func() {
+		limitedQuery := userQuery
+		limitedQuery.MaxRows = 2
+		expectQuery(sequenceRows())
+
+		ch := make(chan prometheus.Metric, 10)
+		Expect(newQueryCollector(limitedQuery).collect(db, ch)).To(Succeed())
+		Expect(ch).To(HaveLen(2))
+	})
+
+	It("runs the query again only when the cache_seconds window expires", func() {
+		cachedQuery := userQuery
+		cachedQuery.CacheSeconds = 60
+		collector := newQueryCollector(cachedQuery)
+		q := NewQueriesCollector("test", nil, "db")
+
+		By("running the query the first time", func() {
+			expectQuery(sequenceRows())
+
+			ch := make(chan prometheus.Metric, 10)
+			Expect(q.collectWithCache("test_sequences", "app", collector, db, ch)).To(Succeed())
+			Expect(ch).To(HaveLen(3))
+		})
+
+		By("exposing the cached metrics without running the query again", func() {
+			ch := make(chan prometheus.Metric, 10)
+			Expect(q.collectWithCache("test_sequences", "app", collector, db, ch)).To(Succeed())
+			Expect(ch).To(HaveLen(3))
+		})
+
+		By("running the query again when the cached metrics are expired", func() {
+			cached := q.cachedMetrics["test_sequences/app"]
+			cached.expiresAt = time.Now().Add(-time.Second)
+			q.cachedMetrics["test_sequences/app"] = cached
+			expectQuery(sequenceRows().RowError(1, errors.New("test error")))
+
+			ch := make(chan prometheus.Metric, 10)
+			Expect(q.collectWithCache("test_sequences", "app", collector, db, ch)).ToNot(Succeed())
+			Expect(ch).To(HaveLen(1))
+		})
+
+		By("not caching the metrics of a failed collection", func() {
+			_, ok := q.getCachedMetrics("test_sequences/app")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
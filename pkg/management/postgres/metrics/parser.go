@@ -33,6 +33,7 @@ type UserQuery struct {
 	Master          bool      `yaml:"master"` // wokeignore:rule=master
 	Primary         bool      `yaml:"primary"`
 	CacheSeconds    uint64    `yaml:"cache_seconds"`
+	MaxRows         uint64    `yaml:"max_rows"`
 	RunOnServer     string    `yaml:"runonserver"`
 	TargetDatabases []string  `yaml:"target_databases"`
 }
@@ -48,6 +48,7 @@ some_query:
   query: |
     SELECT current_database() as datname, count(*) as rows FROM some_table
   cache_seconds: 100
+  max_rows: 20
   metrics:
   - datname:
       usage: "LABEL"
@@ -70,6 +71,7 @@ some_query:
 		Expect(result["some_query"].Primary).To(BeFalse())
 		Expect(result["some_query"].TargetDatabases).To(ContainElements("test", "app"))
 		Expect(result["some_query"].CacheSeconds).To(BeEquivalentTo(100))
+		Expect(result["some_query"].MaxRows).To(BeEquivalentTo(20))
 		Expect(result["some_query"].Master).To(BeFalse()) // wokeignore:rule=master
 		Expect(result["some_query"].Metrics).To(HaveLen(2))
 		Expect(result["some_query"].Metrics[0]["datname"].Usage).To(Equal(ColumnUsage("LABEL")))
@@ -55,6 +55,10 @@ func (instance *Instance) IsServerReady() error {
 	if !instance.CanCheckReadiness() {
 		return fmt.Errorf("instance is not ready yet")
 	}
+	if err := instance.CheckStorage(); err != nil {
+		return err
+	}
+
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return err
@@ -94,6 +98,15 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		result.IsPgRewindRunning = true
 		return result, nil
 	}
+
+	// A failing storage can make the following queries hang, and will
+	// break the instance sooner or later: we report it immediately
+	if err := instance.CheckStorage(); err != nil {
+		result.StorageFailure = err.Error()
+		result.IsPrimary, _ = instance.IsPrimary()
+		return result, nil
+	}
+
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return result, err
@@ -275,6 +288,10 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	if err := fillAlterSystemParameters(superUserDB, result); err != nil {
+		return err
+	}
+
 	return instance.fillWalStatus(result)
 }
 
@@ -383,6 +400,37 @@ func fillArchiverStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus)
 	)
 }
 
+// fillAlterSystemParameters gets the parameters whose live value comes
+// from the `postgresql.auto.conf` file, that is the ones set with ALTER SYSTEM
+func fillAlterSystemParameters(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	rows, err := superUserDB.Query(
+		`
+		SELECT name, current_setting(name)
+		FROM pg_catalog.pg_settings
+		WHERE sourcefile = current_setting('data_directory') || '/postgresql.auto.conf'
+		`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result.AlterSystemParameters = nil
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return err
+		}
+		if result.AlterSystemParameters == nil {
+			result.AlterSystemParameters = make(map[string]string)
+		}
+		result.AlterSystemParameters[name] = value
+	}
+
+	return rows.Err()
+}
+
 // fillReplicationSlotsStatus get information about the replication slots
 func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
 	if !result.IsPrimary {
@@ -479,7 +527,8 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			coalesce(flush_lag, '0'::interval),
 			coalesce(replay_lag, '0'::interval),
 			coalesce(sync_state, ''),
-			coalesce(sync_priority, 0)
+			coalesce(sync_priority, 0),
+			coalesce(extract(epoch from replay_lag), 0)
 		FROM pg_catalog.pg_stat_replication
 		WHERE application_name ~ $1 AND usename = $2`,
 		fmt.Sprintf("%s-[0-9]+$", instance.ClusterName),
@@ -508,6 +557,7 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			&pgr.ReplayLag,
 			&pgr.SyncState,
 			&pgr.SyncPriority,
+			&pgr.ReplayLagSeconds,
 		)
 		if err != nil {
 			return err
@@ -53,7 +53,8 @@ var _ = Describe("probes", func() {
 				coalesce(flush_lag, '0'::interval),
 				coalesce(replay_lag, '0'::interval),
 				coalesce(sync_state, ''),
-				coalesce(sync_priority, 0)
+				coalesce(sync_priority, 0),
+				coalesce(extract(epoch from replay_lag), 0)
 			FROM pg_catalog.pg_stat_replication
 			WHERE application_name ~ $1 AND usename = $2`),
 		).WithArgs("-[0-9]+$", "streaming_replica").WillReturnError(errFailedQuery)
@@ -62,6 +63,36 @@ var _ = Describe("probes", func() {
 		Expect(err).To(Equal(errFailedQuery))
 	})
 
+	It("fillAlterSystemParameters gets the parameters set with ALTER SYSTEM", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`postgresql.auto.conf`).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "current_setting"}).
+				AddRow("work_mem", "64MB").
+				AddRow("log_statement", "all"))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillAlterSystemParameters(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.AlterSystemParameters).To(Equal(map[string]string{
+			"work_mem":      "64MB",
+			"log_statement": "all",
+		}))
+	})
+
+	It("fillAlterSystemParameters reports no parameter when ALTER SYSTEM wasn't used", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`postgresql.auto.conf`).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "current_setting"}))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillAlterSystemParameters(db, status)).To(Succeed())
+		Expect(status.AlterSystemParameters).To(BeNil())
+	})
+
 	It("fillArchiveStatus should properly handle errors", func() {
 		db, mock, err := sqlmock.New()
 		Expect(err).ToNot(HaveOccurred())
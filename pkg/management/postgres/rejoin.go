@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgControldataLatestCheckpointTimeline = "Latest checkpoint's TimeLineID"
+	pgControldataMinRecoveryTimeline      = "Min recovery ending loc's timeline"
+)
+
+// GetTimeline gets the timeline of the data directory from pg_controldata.
+// This function is meant to be used when PostgreSQL is not running
+func (instance *Instance) GetTimeline() (int, error) {
+	controlData, err := instance.GetPgControldata()
+	if err != nil {
+		return 0, err
+	}
+
+	return getTimelineFromPgControldata(utils.ParsePgControldataOutput(controlData))
+}
+
+// getTimelineFromPgControldata extracts the timeline from the parsed output
+// of pg_controldata. A replica whose minimum recovery point is on a later
+// timeline than its latest checkpoint is already following that timeline
+func getTimelineFromPgControldata(controlData map[string]string) (int, error) {
+	rawTimeline, ok := controlData[pgControldataLatestCheckpointTimeline]
+	if !ok {
+		return 0, fmt.Errorf("missing %q in pg_controldata output", pgControldataLatestCheckpointTimeline)
+	}
+
+	timeline, err := strconv.Atoi(rawTimeline)
+	if err != nil {
+		return 0, fmt.Errorf("while parsing %q: %w", pgControldataLatestCheckpointTimeline, err)
+	}
+
+	if rawMinRecoveryTimeline, ok := controlData[pgControldataMinRecoveryTimeline]; ok {
+		minRecoveryTimeline, err := strconv.Atoi(rawMinRecoveryTimeline)
+		if err != nil {
+			return 0, fmt.Errorf("while parsing %q: %w", pgControldataMinRecoveryTimeline, err)
+		}
+		timeline = max(timeline, minRecoveryTimeline)
+	}
+
+	return timeline, nil
+}
+
+// Reclone replaces the content of the data directory, of the WAL directory
+// and of the tablespaces with a new copy of the primary, and then configures
+// the instance as a replica. The content of the instance is lost.
+// Important: this function must be called only when the instance isn't started
+func (instance *Instance) Reclone(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// Signal the liveness probe that we are cloning the primary before starting postgres
+	instance.RecloneIsRunning.Store(true)
+	defer func() {
+		instance.RecloneIsRunning.Store(false)
+	}()
+
+	instance.LogPgControldata(ctx, "before cloning the primary again")
+
+	walDir := ""
+	if hasWalVolume, err := fileutils.FileExists(specs.PgWalVolumePath); err != nil {
+		return err
+	} else if hasWalVolume {
+		walDir = specs.PgWalVolumePgWalPath
+	}
+
+	contextLogger.Info("Removing the content of the instance before cloning the primary again",
+		"pgdata", instance.PgData,
+		"walDir", walDir)
+	if err := fileutils.RemoveDirectoryContent(instance.PgData); err != nil {
+		return fmt.Errorf("while cleaning up the data directory: %w", err)
+	}
+	if walDir != "" {
+		if err := os.RemoveAll(walDir); err != nil {
+			return fmt.Errorf("while cleaning up the WAL directory: %w", err)
+		}
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		if err := os.RemoveAll(specs.LocationForTablespace(tablespace.Name)); err != nil {
+			return fmt.Errorf("while cleaning up tablespace %s: %w", tablespace.Name, err)
+		}
+	}
+
+	primaryConnInfo := buildCloneConnInfo(cluster, instance.GetPrimaryConnInfo())
+	if err := ClonePgData(primaryConnInfo, instance.PgData, walDir); err != nil {
+		return err
+	}
+
+	slotName := cluster.GetSlotNameFromInstanceName(instance.PodName)
+	_, err := UpdateReplicaConfiguration(instance.PgData, instance.GetPrimaryConnInfo(), slotName)
+	return err
+}
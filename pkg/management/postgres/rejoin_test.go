@@ -0,0 +1,52 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("timeline from pg_controldata", func() {
+	It("uses the timeline of the latest checkpoint", func() {
+		timeline, err := getTimelineFromPgControldata(map[string]string{
+			"Latest checkpoint's TimeLineID":     "3",
+			"Min recovery ending loc's timeline": "0",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeline).To(Equal(3))
+	})
+
+	It("uses the timeline of the minimum recovery point when it is later", func() {
+		timeline, err := getTimelineFromPgControldata(map[string]string{
+			"Latest checkpoint's TimeLineID":     "3",
+			"Min recovery ending loc's timeline": "4",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeline).To(Equal(4))
+	})
+
+	It("fails when the timeline is missing or invalid", func() {
+		_, err := getTimelineFromPgControldata(map[string]string{})
+		Expect(err).To(HaveOccurred())
+
+		_, err = getTimelineFromPgControldata(map[string]string{
+			"Latest checkpoint's TimeLineID": "three",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -50,6 +50,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -182,6 +183,24 @@ func (info InitInfo) createBackupObjectForSnapshotRestore(
 	}
 	serverName := server.GetServerName()
 
+	// The WAL files will be fetched from the PVC storing
+	// the backups of the external cluster
+	if server.PVC != nil {
+		return &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{
+					Name: serverName,
+				},
+			},
+			Status: apiv1.BackupStatus{
+				Method:          apiv1.BackupMethodPVC,
+				DestinationPath: pvcbackup.RecoveryServerDirectory(cluster, &server),
+				ServerName:      serverName,
+				Phase:           apiv1.BackupPhaseCompleted,
+			},
+		}, os.Environ(), nil
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -244,12 +263,21 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
-		return err
-	}
+	// The backups stored in a PVC contain the WAL files needed to make
+	// them consistent, and are restored without barman-cloud
+	if backup.Status.Method == apiv1.BackupMethodPVC {
+		if err := pvcbackup.RestoreBackup(
+			ctx, backup.Status.DestinationPath, backup.Status.BackupID, info.PgData); err != nil {
+			return err
+		}
+	} else {
+		if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
+			return err
+		}
 
-	if err := info.restoreDataDir(backup, env); err != nil {
-		return err
+		if err := info.restoreDataDir(backup, env); err != nil {
+			return err
+		}
 	}
 
 	if _, err := info.restoreCustomWalDir(ctx); err != nil {
@@ -396,7 +424,12 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 
 	cmd := exec.Command(barmanCapabilities.BarmanCloudRestore, options...) // #nosec G204
 	cmd.Env = env
-	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudRestore)
+	restoreLogger := log.WithName(barmanCapabilities.BarmanCloudRestore).WithValues(
+		"backupName", backup.Name,
+		"backupID", backup.Status.BackupID,
+		"startTime", time.Now(),
+	)
+	err = execlog.RunStreamingWithLogger(cmd, barmanCapabilities.BarmanCloudRestore, restoreLogger)
 	if err != nil {
 		log.Error(err, "Can't restore backup")
 		return err
@@ -452,6 +485,10 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	}
 	serverName := server.GetServerName()
 
+	if server.PVC != nil {
+		return loadBackupObjectFromPVC(cluster, &server)
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -467,23 +504,11 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 		return nil, nil, err
 	}
 
-	// We are now choosing the right backup to restore
-	var targetBackup *catalog.BarmanBackup
-	if cluster.Spec.Bootstrap.Recovery != nil &&
-		cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
-		targetBackup, err = backupCatalog.FindBackupInfo(cluster.Spec.Bootstrap.Recovery.RecoveryTarget)
-		if err != nil {
-			return nil, nil, err
-		}
-	} else {
-		targetBackup = backupCatalog.LatestBackupInfo()
-	}
-	if targetBackup == nil {
-		return nil, nil, fmt.Errorf("no target backup found")
+	targetBackup, err := findTargetBackup(cluster, backupCatalog)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Info("Target backup found", "backup", targetBackup)
-
 	return &apiv1.Backup{
 		Spec: apiv1.BackupSpec{
 			Cluster: apiv1.LocalObjectReference{
@@ -511,6 +536,71 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	}, env, nil
 }
 
+// loadBackupObjectFromPVC generates an in-memory Backup structure given a
+// reference to an external cluster whose backups are stored in a
+// PersistentVolumeClaim, loading the required information from its catalog
+func loadBackupObjectFromPVC(
+	cluster *apiv1.Cluster,
+	server *apiv1.ExternalCluster,
+) (*apiv1.Backup, []string, error) {
+	serverName := server.GetServerName()
+	serverDirectory := pvcbackup.RecoveryServerDirectory(cluster, server)
+
+	backupCatalog, err := pvcbackup.GetCatalog(serverDirectory)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetBackup, err := findTargetBackup(cluster, backupCatalog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &apiv1.Backup{
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{
+				Name: serverName,
+			},
+			Method: apiv1.BackupMethodPVC,
+		},
+		Status: apiv1.BackupStatus{
+			Method:          apiv1.BackupMethodPVC,
+			DestinationPath: serverDirectory,
+			ServerName:      serverName,
+			BackupID:        targetBackup.ID,
+			Phase:           apiv1.BackupPhaseCompleted,
+			StartedAt:       &metav1.Time{Time: targetBackup.BeginTime},
+			StoppedAt:       &metav1.Time{Time: targetBackup.EndTime},
+			BeginWal:        targetBackup.BeginWal,
+			EndWal:          targetBackup.EndWal,
+			BeginLSN:        targetBackup.BeginLSN,
+			EndLSN:          targetBackup.EndLSN,
+		},
+	}, os.Environ(), nil
+}
+
+// findTargetBackup chooses the backup to restore from the catalog,
+// depending on the recovery target
+func findTargetBackup(cluster *apiv1.Cluster, backupCatalog *catalog.Catalog) (*catalog.BarmanBackup, error) {
+	var targetBackup *catalog.BarmanBackup
+	if cluster.Spec.Bootstrap.Recovery != nil &&
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
+		var err error
+		targetBackup, err = backupCatalog.FindBackupInfo(cluster.Spec.Bootstrap.Recovery.RecoveryTarget)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		targetBackup = backupCatalog.LatestBackupInfo()
+	}
+	if targetBackup == nil {
+		return nil, fmt.Errorf("no target backup found")
+	}
+
+	log.Info("Target backup found", "backup", targetBackup)
+	return targetBackup, nil
+}
+
 // loadBackupFromReference loads a backup object and the required credentials given the backup object resource
 func (info InitInfo) loadBackupFromReference(
 	ctx context.Context,
@@ -526,6 +616,20 @@ func (info InitInfo) loadBackupFromReference(
 		return nil, nil, err
 	}
 
+	// The PVC storing the backup is mounted by the recovery job in a
+	// directory which may differ from the one used by the origin
+	if backup.Status.Method == apiv1.BackupMethodPVC {
+		if backup.Status.ClaimName == "" {
+			return nil, nil, fmt.Errorf(
+				"backup %s doesn't report the PVC storing it, and can only be recovered through an external cluster",
+				backup.Name)
+		}
+		backup.Status.DestinationPath = pvcbackup.BackupServerDirectory(cluster, &backup)
+
+		log.Info("Recovering existing backup", "backup", backup)
+		return &backup, os.Environ(), nil
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -550,7 +654,33 @@ func (info InitInfo) loadBackupFromReference(
 // to complete the WAL recovery from the object storage and then start
 // as a new primary
 func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
-	var err error
+	restoreCommand, err := buildRestoreCommand(backup)
+	if err != nil {
+		return err
+	}
+
+	major, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect major version: %w", err)
+	}
+
+	recoveryFileContents := fmt.Sprintf(
+		"recovery_target_action = promote\n"+
+			"restore_command = '%s'\n"+
+			"%s%s",
+		restoreCommand,
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions(),
+		cluster.Spec.Bootstrap.Recovery.Tuning.BuildPostgresOptions(major))
+
+	return info.writeRecoveryConfiguration(recoveryFileContents)
+}
+
+// buildRestoreCommand gets the `restore_command` fetching the WAL
+// files from the archive where the passed backup is stored
+func buildRestoreCommand(backup *apiv1.Backup) (string, error) {
+	if backup.Status.Method == apiv1.BackupMethodPVC {
+		return pvcbackup.RestoreCommand(backup.Status.DestinationPath), nil
+	}
 
 	cmd := []string{barmanCapabilities.BarmanCloudWalRestore}
 	if backup.Status.EndpointURL != "" {
@@ -559,21 +689,13 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 	cmd = append(cmd, backup.Status.DestinationPath)
 	cmd = append(cmd, backup.Status.ServerName)
 
-	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
+	cmd, err := barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	cmd = append(cmd, "%f", "%p")
-
-	recoveryFileContents := fmt.Sprintf(
-		"recovery_target_action = promote\n"+
-			"restore_command = '%s'\n"+
-			"%s",
-		strings.Join(cmd, " "),
-		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
-
-	return info.writeRecoveryConfiguration(recoveryFileContents)
+	return strings.Join(cmd, " "), nil
 }
 
 func (info InitInfo) writeRecoveryConfiguration(recoveryFileContents string) error {
@@ -808,7 +930,7 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 	}
 
 	if majorVersion >= 12 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetPrimaryConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresOverrideConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -833,8 +955,8 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 }
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
-func (info InitInfo) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName)
+func (info InitInfo) GetPrimaryConnInfo(cluster *apiv1.Cluster) string {
+	return buildPrimaryConnInfo(cluster.GetServicePrimaryName(), info.PodName, cluster.Spec.ReplicationConnection)
 }
 
 func (info *InitInfo) checkBackupDestination(
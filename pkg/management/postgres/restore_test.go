@@ -22,8 +22,12 @@ import (
 	"path"
 
 	"github.com/thoas/go-funk"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -148,3 +152,85 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("restore command", func() {
+	It("fetches the WAL files from the backups stored in a PVC", func() {
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				Method:          apiv1.BackupMethodPVC,
+				DestinationPath: "/var/lib/postgresql/recovery-backups/origin",
+				ServerName:      "origin",
+			},
+		}
+		Expect(buildRestoreCommand(backup)).
+			To(Equal("cp /var/lib/postgresql/recovery-backups/origin/wals/%f %p"))
+	})
+})
+
+var _ = Describe("loading a Backup stored in a PVC", func() {
+	const namespace = "default"
+
+	newCluster := func(backupClaimName string) *apiv1.Cluster {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-restore", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Backup: &apiv1.BackupSource{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-example"},
+						},
+					},
+				},
+			},
+		}
+		if backupClaimName != "" {
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{
+				PVC: &apiv1.PVCBackupConfiguration{ClaimName: backupClaimName},
+			}
+		}
+		return cluster
+	}
+
+	newBackup := func(claimName string) *apiv1.Backup {
+		return &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: namespace},
+			Spec:       apiv1.BackupSpec{Method: apiv1.BackupMethodPVC},
+			Status: apiv1.BackupStatus{
+				Method:          apiv1.BackupMethodPVC,
+				ClaimName:       claimName,
+				ServerName:      "cluster-example",
+				DestinationPath: "/var/lib/postgresql/backups/cluster-example",
+				BackupID:        "20240101T000000",
+				Phase:           apiv1.BackupPhaseCompleted,
+			},
+		}
+	}
+
+	load := func(cluster *apiv1.Cluster, backup *apiv1.Backup) (*apiv1.Backup, error) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(backup).
+			Build()
+		info := InitInfo{Namespace: namespace}
+		result, _, err := info.loadBackupFromReference(context.TODO(), cli, cluster)
+		return result, err
+	}
+
+	It("reads the backup from the directory where the recovery job mounts the claim", func() {
+		backup, err := load(newCluster(""), newBackup("postgres-backups"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.Status.DestinationPath).To(Equal("/var/lib/postgresql/recovery-backups/cluster-example"))
+		Expect(backup.Status.BackupID).To(Equal("20240101T000000"))
+	})
+
+	It("reads the backup from the claim of the cluster when they're the same", func() {
+		backup, err := load(newCluster("postgres-backups"), newBackup("postgres-backups"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.Status.DestinationPath).To(Equal("/var/lib/postgresql/backups/cluster-example"))
+	})
+
+	It("refuses backups not reporting the claim storing them", func() {
+		_, err := load(newCluster(""), newBackup(""))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// storageProbeFileName is the name of the file written to verify
+	// that a volume used by the instance is still working
+	storageProbeFileName = ".cnpg-storage-probe"
+
+	// storageCheckInterval is the minimum time between two writes
+	// of the storage probe
+	storageCheckInterval = 10 * time.Second
+)
+
+// ErrStorageFailure is returned when a volume used by the instance
+// has been remounted read-only or is reporting I/O errors
+var ErrStorageFailure = errors.New("storage failure")
+
+// storageCheckCache holds the result of the last storage check, so that
+// the probes and the status requests don't sync a file at every call
+type storageCheckCache struct {
+	sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// CheckStorage verifies that the volumes holding PGDATA and, if
+// separated, the WALs, can still be written. Only the errors denoting
+// a failure of the underlying storage are reported, wrapping
+// ErrStorageFailure, while any other error is ignored. The result is
+// reused for storageCheckInterval, and concurrent callers wait for the
+// check in progress instead of starting a new one
+func (instance *Instance) CheckStorage() error {
+	instance.storageCheck.Lock()
+	defer instance.storageCheck.Unlock()
+
+	if !instance.storageCheck.checkedAt.IsZero() &&
+		time.Since(instance.storageCheck.checkedAt) < storageCheckInterval {
+		return instance.storageCheck.err
+	}
+
+	instance.storageCheck.err = instance.checkStorage()
+	instance.storageCheck.checkedAt = time.Now()
+	return instance.storageCheck.err
+}
+
+// checkStorage writes the storage probe to every volume used by the instance
+func (instance *Instance) checkStorage() error {
+	for _, directory := range instance.getStorageProbeDirectories() {
+		err := writeStorageProbe(directory)
+		if err == nil {
+			continue
+		}
+
+		if isStorageFailure(err) {
+			return fmt.Errorf("%w: %s", ErrStorageFailure, err.Error())
+		}
+
+		log.Debug("Ignoring error while checking the storage", "directory", directory, "err", err)
+	}
+
+	return nil
+}
+
+// getStorageProbeDirectories gets the root directories of the volumes
+// used by the instance, to which the storage probe is written
+func (instance *Instance) getStorageProbeDirectories() []string {
+	directories := []string{filepath.Dir(instance.PgData)}
+
+	walDirectory, err := filepath.EvalSymlinks(filepath.Join(instance.PgData, "pg_wal"))
+	if err != nil {
+		return directories
+	}
+
+	if walVolume := filepath.Dir(walDirectory); walVolume != directories[0] && walVolume != instance.PgData {
+		directories = append(directories, walVolume)
+	}
+
+	return directories
+}
+
+// writeStorageProbe writes and syncs a small file in the given directory,
+// removing it afterward
+func writeStorageProbe(directory string) error {
+	fileName := filepath.Join(directory, storageProbeFileName)
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteString("storage probe\n")
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(fileName)
+}
+
+// isStorageFailure checks whether the error is caused by a read-only
+// file system or by an I/O error of the underlying device
+func isStorageFailure(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, syscall.EIO)
+}
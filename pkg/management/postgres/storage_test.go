@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("storage check", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(tempDir, "data", "pgdata", "pg_wal"), 0o700)).To(Succeed())
+	})
+
+	It("succeeds on a working volume, without leaving the probe behind", func() {
+		instance := Instance{PgData: filepath.Join(tempDir, "data", "pgdata")}
+		Expect(instance.CheckStorage()).To(Succeed())
+		Expect(filepath.Join(tempDir, "data", storageProbeFileName)).ToNot(BeAnExistingFile())
+	})
+
+	It("checks the WAL volume when pg_wal is a symbolic link", func() {
+		pgData := filepath.Join(tempDir, "data", "pgdata")
+		Expect(os.MkdirAll(filepath.Join(tempDir, "wal", "pg_wal"), 0o700)).To(Succeed())
+		Expect(os.Remove(filepath.Join(pgData, "pg_wal"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(tempDir, "wal", "pg_wal"), filepath.Join(pgData, "pg_wal"))).To(Succeed())
+
+		instance := Instance{PgData: pgData}
+		Expect(instance.getStorageProbeDirectories()).To(Equal([]string{
+			filepath.Join(tempDir, "data"),
+			filepath.Join(tempDir, "wal"),
+		}))
+	})
+
+	It("ignores the errors not related to a storage failure", func() {
+		instance := Instance{PgData: filepath.Join(tempDir, "missing", "pgdata")}
+		Expect(instance.CheckStorage()).To(Succeed())
+	})
+
+	It("reuses the result of a recent check", func() {
+		instance := Instance{PgData: filepath.Join(tempDir, "data", "pgdata")}
+		instance.storageCheck.checkedAt = time.Now()
+		instance.storageCheck.err = ErrStorageFailure
+		Expect(instance.CheckStorage()).To(MatchError(ErrStorageFailure))
+
+		instance.storageCheck.checkedAt = time.Now().Add(-storageCheckInterval)
+		Expect(instance.CheckStorage()).To(Succeed())
+		Expect(instance.storageCheck.err).ToNot(HaveOccurred())
+	})
+
+	It("detects read-only file systems and I/O errors", func() {
+		Expect(isStorageFailure(&os.PathError{Op: "open", Path: "test", Err: syscall.EROFS})).To(BeTrue())
+		Expect(isStorageFailure(fmt.Errorf("wrapped: %w", syscall.EIO))).To(BeTrue())
+		Expect(isStorageFailure(&os.PathError{Op: "write", Path: "test", Err: syscall.ENOSPC})).To(BeFalse())
+		Expect(isStorageFailure(errors.New("generic error"))).To(BeFalse())
+	})
+})
@@ -0,0 +1,300 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgUpgradeName = "pg_upgrade"
+
+	// upgradedDirectorySuffix is appended to the data and WAL directories
+	// to get the ones where the upgraded instance is created
+	upgradedDirectorySuffix = "-new"
+
+	// oldDirectorySuffix is appended to the data and WAL directories to get
+	// the ones where the old instance is moved before being removed
+	oldDirectorySuffix = "-old"
+
+	// deleteOldClusterScriptName is the script generated by pg_upgrade to
+	// remove the files of the old instance, including its tablespaces
+	deleteOldClusterScriptName = "delete_old_cluster.sh"
+)
+
+// UpgradeMajorVersion upgrades the data directory to the major version of the
+// PostgreSQL binaries available in the PATH with pg_upgrade in link mode, using
+// the binaries of the old major version found in oldBinDir. The upgraded data
+// directory replaces the old one, which is removed
+func (info InitInfo) UpgradeMajorVersion(ctx context.Context, oldBinDir string) error {
+	contextLogger := log.FromContext(ctx)
+
+	// pg_upgrade writes its logs and scripts in the working directory,
+	// which needs to be writable
+	workingDirectory := filepath.Dir(info.PgData)
+	upgradedInfo := info.withDirectorySuffix(upgradedDirectorySuffix)
+
+	// pg_upgrade generates the script removing the old instance once it
+	// has completed, and the script is removed only once the old instance is
+	// gone. If it exists, a previous attempt has been interrupted while
+	// replacing the data directory, and must not upgrade it again
+	completed, err := fileutils.FileExists(path.Join(workingDirectory, deleteOldClusterScriptName))
+	if err != nil {
+		return err
+	}
+	if completed {
+		contextLogger.Info("Resuming the replacement of the upgraded data directory", "pgdata", info.PgData)
+		return info.replaceDataDirectory(ctx, upgradedInfo, workingDirectory)
+	}
+
+	controlData, err := getPgControldataWithBinDir(oldBinDir, info.PgData)
+	if err != nil {
+		return err
+	}
+
+	// The new data directory must be compatible with the old one
+	upgradedInfo.InitDBOptions = getUpgradeInitDBOptions(info.InitDBOptions, controlData)
+
+	// Clean up anything left behind by a previous attempt
+	for _, dir := range []string{upgradedInfo.PgData, upgradedInfo.PgWal} {
+		if dir == "" {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("while removing %s: %w", dir, err)
+		}
+	}
+
+	if err := upgradedInfo.CreateDataDirectory(); err != nil {
+		return err
+	}
+
+	postgresPath, err := exec.LookPath(postgresName)
+	if err != nil {
+		return fmt.Errorf("while looking for the new PostgreSQL binaries: %w", err)
+	}
+
+	options := []string{
+		"--link",
+		"--username", "postgres",
+		"--old-bindir", oldBinDir,
+		"--new-bindir", filepath.Dir(postgresPath),
+		"--old-datadir", info.PgData,
+		"--new-datadir", upgradedInfo.PgData,
+		// The certificates are not available in the job, and the
+		// old instance must not archive anything while upgrading
+		"--old-options", "-c ssl=off -c archive_mode=off",
+	}
+
+	contextLogger.Info("Upgrading the data directory",
+		"pgdata", info.PgData,
+		"oldBinDir", oldBinDir,
+		"pgUpgradeOptions", options)
+
+	pgUpgradeCmd := exec.Command(pgUpgradeName, options...) // #nosec G204
+	pgUpgradeCmd.Dir = workingDirectory
+	if err := execlog.RunBuffering(pgUpgradeCmd, pgUpgradeName); err != nil {
+		return fmt.Errorf("error while upgrading the PostgreSQL instance: %w", err)
+	}
+
+	return info.replaceDataDirectory(ctx, upgradedInfo, workingDirectory)
+}
+
+// withDirectorySuffix gets the init info having the passed suffix appended
+// to the data directory, and to the WAL directory if separated
+func (info InitInfo) withDirectorySuffix(suffix string) InitInfo {
+	result := info
+	result.PgData = info.PgData + suffix
+	if info.PgWal != "" {
+		result.PgWal = info.PgWal + suffix
+	}
+
+	return result
+}
+
+// replaceDataDirectory moves the old data directory, and the WAL directory
+// if separated, aside and the upgraded ones in their place, and finally
+// removes the old ones. Every step is skipped when already done, so that an
+// interrupted replacement can be resumed, and the old data directory is
+// removed only once the upgraded one is in place
+func (info InitInfo) replaceDataDirectory(ctx context.Context, upgradedInfo InitInfo, workingDirectory string) error {
+	contextLogger := log.FromContext(ctx)
+	oldInfo := info.withDirectorySuffix(oldDirectorySuffix)
+
+	contextLogger.Info("Moving the upgraded data directory in place", "pgdata", info.PgData)
+	if err := swapDirectory(info.PgData, upgradedInfo.PgData, oldInfo.PgData); err != nil {
+		return fmt.Errorf("while moving the upgraded data directory: %w", err)
+	}
+
+	if info.PgWal != "" {
+		if err := swapDirectory(info.PgWal, upgradedInfo.PgWal, oldInfo.PgWal); err != nil {
+			return fmt.Errorf("while moving the upgraded WAL directory: %w", err)
+		}
+
+		// The upgraded data directory points to the WAL directory with its temporary name
+		if err := ensureSymlink(info.PgWal, path.Join(info.PgData, "pg_wal")); err != nil {
+			return err
+		}
+	}
+
+	contextLogger.Info("Removing the old data directory", "pgdata", oldInfo.PgData)
+	if err := removeOldTablespaceDirectories(info.PgData); err != nil {
+		return err
+	}
+	for _, dir := range []string{oldInfo.PgData, oldInfo.PgWal} {
+		if dir == "" {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("while removing %s: %w", dir, err)
+		}
+	}
+
+	return fileutils.RemoveFile(path.Join(workingDirectory, deleteOldClusterScriptName))
+}
+
+// swapDirectory moves the current directory to the old path, and the
+// upgraded one in its place. Nothing is done if the upgraded directory
+// has already been moved
+func swapDirectory(current, upgraded, old string) error {
+	upgradedExists, err := fileutils.FileExists(upgraded)
+	if err != nil || !upgradedExists {
+		return err
+	}
+
+	currentExists, err := fileutils.FileExists(current)
+	if err != nil {
+		return err
+	}
+	if currentExists {
+		if err := os.Rename(current, old); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(upgraded, current)
+}
+
+// ensureSymlink makes the link point to the passed target
+func ensureSymlink(target, link string) error {
+	if current, err := os.Readlink(link); err == nil && current == target {
+		return nil
+	}
+
+	if err := fileutils.RemoveFile(link); err != nil {
+		return err
+	}
+	return os.Symlink(target, link)
+}
+
+// removeOldTablespaceDirectories removes, from the location of every
+// tablespace of the passed data directory, the directories belonging to
+// other major versions of PostgreSQL, that pg_upgrade leaves behind
+func removeOldTablespaceDirectories(pgData string) error {
+	version, err := fileutils.ReadFile(path.Join(pgData, "PG_VERSION"))
+	if err != nil {
+		return err
+	}
+	currentPrefix := fmt.Sprintf("PG_%s_", strings.TrimSpace(string(version)))
+
+	links, err := os.ReadDir(path.Join(pgData, "pg_tblspc"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		location, err := os.Readlink(path.Join(pgData, "pg_tblspc", link.Name()))
+		if err != nil {
+			return err
+		}
+
+		entries, err := os.ReadDir(location)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), "PG_") || strings.HasPrefix(entry.Name(), currentPrefix) {
+				continue
+			}
+			if err := os.RemoveAll(path.Join(location, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPgControldataWithBinDir parses the output of the pg_controldata
+// executable found in a given directory for a data directory
+func getPgControldataWithBinDir(binDir, pgData string) (map[string]string, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgControlDataCmd := exec.Command(path.Join(binDir, pgControlDataName),
+		"-D",
+		pgData) // #nosec G204
+	pgControlDataCmd.Stdout = &stdoutBuffer
+	pgControlDataCmd.Stderr = &stderrBuffer
+	pgControlDataCmd.Env = append(pgControlDataCmd.Env, "LANG=C", "LC_MESSAGES=C")
+	if err := pgControlDataCmd.Run(); err != nil {
+		log.Error(err, "while reading pg_controldata",
+			"stderr", stderrBuffer.String(),
+			"stdout", stdoutBuffer.String())
+		return nil, err
+	}
+
+	return utils.ParsePgControldataOutput(stdoutBuffer.String()), nil
+}
+
+// getUpgradeInitDBOptions gets the options to create the data directory of
+// the upgraded instance: data checksums and WAL segment size must be the
+// same of the old instance, regardless of the current cluster definition
+func getUpgradeInitDBOptions(initDBOptions []string, controlData map[string]string) []string {
+	options := make([]string, 0, len(initDBOptions)+2)
+	for _, option := range initDBOptions {
+		if option == "-k" || option == "--data-checksums" || strings.HasPrefix(option, "--wal-segsize=") {
+			continue
+		}
+		options = append(options, option)
+	}
+
+	if checksumVersion := controlData["Data page checksum version"]; checksumVersion != "" && checksumVersion != "0" {
+		options = append(options, "-k")
+	}
+
+	if walSegmentSize, err := strconv.Atoi(controlData["Bytes per WAL segment"]); err == nil && walSegmentSize > 0 {
+		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize/(1024*1024)))
+	}
+
+	return options
+}
@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major upgrade initdb options", func() {
+	It("keeps the data checksums and the WAL segment size of the old instance", func() {
+		controlData := map[string]string{
+			"Data page checksum version": "1",
+			"Bytes per WAL segment":      "67108864",
+		}
+		options := getUpgradeInitDBOptions(
+			[]string{"--encoding=UTF8", "--wal-segsize=16", "--lc-collate=C"}, controlData)
+		Expect(options).To(Equal([]string{"--encoding=UTF8", "--lc-collate=C", "-k", "--wal-segsize=64"}))
+	})
+
+	It("disables the data checksums when the old instance doesn't have them", func() {
+		controlData := map[string]string{
+			"Data page checksum version": "0",
+		}
+		Expect(getUpgradeInitDBOptions([]string{"-k", "--encoding=UTF8"}, controlData)).
+			To(Equal([]string{"--encoding=UTF8"}))
+	})
+})
+
+var _ = Describe("major upgrade data directory replacement", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "upgrade")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+	})
+
+	It("moves the upgraded data and WAL directories in place of the old ones", func() {
+		info := InitInfo{
+			PgData: path.Join(tempDir, "data", "pgdata"),
+			PgWal:  path.Join(tempDir, "wal", "pg_wal"),
+		}
+		upgradedInfo := InitInfo{
+			PgData: info.PgData + upgradedDirectorySuffix,
+			PgWal:  info.PgWal + upgradedDirectorySuffix,
+		}
+
+		for _, dir := range []string{info.PgData, info.PgWal, upgradedInfo.PgData, upgradedInfo.PgWal} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+		}
+		Expect(os.WriteFile(path.Join(info.PgData, "PG_VERSION"), []byte("15\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(path.Join(upgradedInfo.PgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.Symlink(upgradedInfo.PgWal, path.Join(upgradedInfo.PgData, "pg_wal"))).To(Succeed())
+
+		Expect(info.replaceDataDirectory(context.TODO(), upgradedInfo, tempDir)).To(Succeed())
+
+		Expect(os.ReadFile(path.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(os.Readlink(path.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(upgradedInfo.PgData).ToNot(BeADirectory())
+		Expect(upgradedInfo.PgWal).ToNot(BeADirectory())
+		Expect(info.PgWal).To(BeADirectory())
+		Expect(info.PgData + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.PgWal + oldDirectorySuffix).ToNot(BeADirectory())
+		Expect(path.Join(tempDir, deleteOldClusterScriptName)).ToNot(BeAnExistingFile())
+	})
+
+	It("resumes a replacement interrupted after moving the old data directory aside", func() {
+		info := InitInfo{
+			PgData: path.Join(tempDir, "data", "pgdata"),
+		}
+		upgradedInfo := info.withDirectorySuffix(upgradedDirectorySuffix)
+		oldInfo := info.withDirectorySuffix(oldDirectorySuffix)
+
+		for _, dir := range []string{oldInfo.PgData, upgradedInfo.PgData} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+		}
+		Expect(os.WriteFile(path.Join(oldInfo.PgData, "PG_VERSION"), []byte("15\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(path.Join(upgradedInfo.PgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(path.Join(tempDir, deleteOldClusterScriptName), []byte(""), 0o600)).To(Succeed())
+
+		Expect(info.replaceDataDirectory(context.TODO(), upgradedInfo, tempDir)).To(Succeed())
+		Expect(os.ReadFile(path.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(oldInfo.PgData).ToNot(BeADirectory())
+		Expect(path.Join(tempDir, deleteOldClusterScriptName)).ToNot(BeAnExistingFile())
+
+		// Running it again once completed changes nothing
+		Expect(info.replaceDataDirectory(context.TODO(), upgradedInfo, tempDir)).To(Succeed())
+		Expect(os.ReadFile(path.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+	})
+
+	It("removes the directories of the old major version from the tablespaces", func() {
+		pgData := path.Join(tempDir, "pgdata")
+		location := path.Join(tempDir, "tablespaces", "app", "data")
+		for _, dir := range []string{
+			path.Join(pgData, "pg_tblspc"),
+			path.Join(location, "PG_15_202209061"),
+			path.Join(location, "PG_16_202307071"),
+		} {
+			Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+		}
+		Expect(os.WriteFile(path.Join(pgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.Symlink(location, path.Join(pgData, "pg_tblspc", "16385"))).To(Succeed())
+
+		Expect(removeOldTablespaceDirectories(pgData)).To(Succeed())
+		Expect(path.Join(location, "PG_15_202209061")).ToNot(BeADirectory())
+		Expect(path.Join(location, "PG_16_202307071")).To(BeADirectory())
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// isolationCheckTimeout is the time we wait for the API server to
+// answer before considering it unreachable
+const isolationCheckTimeout = 2 * time.Second
+
+// errPrimaryIsolated is raised when the primary can reach neither the
+// API server nor any of its standbys
+var errPrimaryIsolated = errors.New("the primary instance is isolated")
+
+// checkPrimaryIsolation fails when a primary instance of a cluster with
+// more than one instance, and with `stopIsolatedPrimary` enabled, can reach
+// neither the Kubernetes API server nor any of its standbys. In this
+// situation the operator has likely promoted another instance already:
+// failing the liveness probe makes the kubelet stop the isolated primary,
+// which can't accept writes anymore until it reaches the API server and
+// learns about its new role.
+func checkPrimaryIsolation(
+	ctx context.Context,
+	cli client.Client,
+	db *sql.DB,
+	cluster *apiv1.Cluster,
+) error {
+	if cluster == nil || !cluster.Spec.StopIsolatedPrimary {
+		return nil
+	}
+
+	if cluster.Spec.Instances <= 1 {
+		// There's no instance to fail over to, hence no risk of split-brain
+		return nil
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, isolationCheckTimeout)
+	defer cancel()
+
+	var currentCluster apiv1.Cluster
+	err := cli.Get(timeoutCtx, client.ObjectKeyFromObject(cluster), &currentCluster)
+	var statusErr apierrs.APIStatus
+	if err == nil || errors.As(err, &statusErr) {
+		// The API server answered, even if with an error status
+		return nil
+	}
+
+	// A standby catching up is connected to the primary as well as a
+	// streaming one: the primary is not isolated
+	var connectedStandbys int
+	row := db.QueryRowContext(ctx,
+		"SELECT count(*) FROM pg_catalog.pg_stat_replication WHERE state IN ('streaming', 'catchup')")
+	if scanErr := row.Scan(&connectedStandbys); scanErr != nil {
+		return fmt.Errorf("while counting the connected standbys: %w", scanErr)
+	}
+	if connectedStandbys > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: API server unreachable (%v) and no connected standby", errPrimaryIsolated, err)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary isolation check", func() {
+	const countStandbysQuery = "SELECT count\\(\\*\\) FROM pg_catalog.pg_stat_replication " +
+		"WHERE state IN \\('streaming', 'catchup'\\)"
+
+	var (
+		cluster *apiv1.Cluster
+		db      *sql.DB
+		mock    sqlmock.Sqlmock
+	)
+
+	newClient := func(getErr error) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(
+					ctx context.Context,
+					cli client.WithWatch,
+					key client.ObjectKey,
+					obj client.Object,
+					opts ...client.GetOption,
+				) error {
+					if getErr != nil {
+						return getErr
+					}
+					return cli.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3, StopIsolatedPrimary: true},
+		}
+
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("succeeds when the API server is reachable", func(ctx SpecContext) {
+
+		Expect(checkPrimaryIsolation(ctx, newClient(nil), db, cluster)).To(Succeed())
+	})
+
+	It("succeeds when the API server answers with an error", func(ctx SpecContext) {
+
+		forbidden := apierrs.NewForbidden(schema.GroupResource{Resource: "clusters"}, cluster.Name, errors.New("denied"))
+		Expect(checkPrimaryIsolation(ctx, newClient(forbidden), db, cluster)).To(Succeed())
+	})
+
+	It("succeeds when the API server is unreachable but a standby is connected", func(ctx SpecContext) {
+		mock.ExpectQuery(countStandbysQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		Expect(checkPrimaryIsolation(ctx, newClient(errors.New("connection refused")), db, cluster)).
+			To(Succeed())
+	})
+
+	It("fails when neither the API server nor the standbys are reachable", func(ctx SpecContext) {
+		mock.ExpectQuery(countStandbysQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		err := checkPrimaryIsolation(ctx, newClient(errors.New("connection refused")), db, cluster)
+		Expect(err).To(MatchError(errPrimaryIsolated))
+	})
+
+	It("is skipped for single instance clusters", func(ctx SpecContext) {
+		cluster.Spec.Instances = 1
+
+		Expect(checkPrimaryIsolation(ctx, newClient(errors.New("connection refused")), db, cluster)).
+			To(Succeed())
+	})
+
+	It("is skipped unless stopIsolatedPrimary is enabled", func(ctx SpecContext) {
+		cluster.Spec.StopIsolatedPrimary = false
+
+		Expect(checkPrimaryIsolation(ctx, newClient(errors.New("connection refused")), db, cluster)).
+			To(Succeed())
+	})
+})
@@ -61,6 +61,7 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgPreStop, endpoints.preStop)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -161,7 +162,18 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	switch {
+	case backup.Spec.Method == apiv1.BackupMethodPlugin:
+		if cluster.GetBackupPlugin() == nil {
+			http.Error(w, "Backup plugin not configured in the cluster", http.StatusConflict)
+			return
+		}
+	case backup.Spec.Method == apiv1.BackupMethodPVC:
+		if cluster.GetBackupPVC() == nil {
+			http.Error(w, "Backup PVC not configured in the cluster", http.StatusConflict)
+			return
+		}
+	case cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil:
 		http.Error(w, "Backup not configured in the cluster", http.StatusConflict)
 		return
 	}
@@ -196,3 +208,24 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 
 	_, _ = fmt.Fprint(w, "OK")
 }
+
+// This is the preStop hook, invoked by the kubelet through the "instance
+// prestop" command before sending the termination signal. It is served by
+// the local webserver, as it changes how the instance is shut down.
+// The checkpoint is issued here to shorten the one PostgreSQL runs when
+// shutting down, avoiding the instance to be killed at the end of the
+// grace period.
+// We always report success, as a failure wouldn't stop the termination
+func (ws *localWebserverEndpoints) preStop(w http.ResponseWriter, r *http.Request) {
+	strategy := apiv1.PreStopStrategy(r.URL.Query().Get("strategy"))
+	if strategy == apiv1.PreStopStrategyFastShutdown {
+		ws.instance.SetSkipSmartShutdown(true)
+	}
+
+	log.Info("Issuing a checkpoint before shutting down the instance", "preStopStrategy", strategy)
+	if err := ws.instance.Checkpoint(r.Context()); err != nil {
+		log.Info("Cannot issue a checkpoint before shutting down the instance", "err", err.Error())
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
@@ -39,7 +39,7 @@ import (
 // or the operator
 const PrometheusNamespace = "cnpg"
 
-var synchronousStandbyNamesRegex = regexp.MustCompile(`ANY ([0-9]+) \(.*\)`)
+var synchronousStandbyNamesRegex = regexp.MustCompile(`(?:ANY|FIRST) ([0-9]+) \(.*\)`)
 
 // Exporter exports a set of metrics and collectors on a given postgres instance
 type Exporter struct {
@@ -68,6 +68,9 @@ type metrics struct {
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	LaggingReplicas              prometheus.Gauge
+	ConnectionStorm              prometheus.Gauge
+	SessionsExceedingTimeout     *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -197,6 +200,27 @@ func newMetrics() *metrics {
 				"implying the absence of High Availability (HA). Ideally this value " +
 				"should match the number of instances in the cluster.",
 		}),
+		LaggingReplicas: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "lagging_replicas",
+			Help: "The number of replicas whose replication lag exceeds the thresholds " +
+				"defined in the cluster (.spec.replicationLagSLO). Only available on the primary",
+		}),
+		ConnectionStorm: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "connection_storm",
+			Help:      "1 if a connection storm is in progress in the instance, 0 otherwise",
+		}),
+		SessionsExceedingTimeout: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "sessions_exceeding_timeout",
+			Help: "Number of sessions which are running a statement or are idle in a transaction for " +
+				"longer than the statement_timeout or the idle_in_transaction_session_timeout of their " +
+				"role and database",
+		}, []string{"datname", "usename", "timeout"}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -275,6 +299,9 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.LaggingReplicas.Describe(ch)
+	e.Metrics.ConnectionStorm.Describe(ch)
+	e.Metrics.SessionsExceedingTimeout.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -313,6 +340,9 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.LaggingReplicas.Collect(ch)
+	e.Metrics.ConnectionStorm.Collect(ch)
+	e.Metrics.SessionsExceedingTimeout.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -336,6 +366,12 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	}
 	e.Metrics.FencingOn.Set(0)
 
+	if e.instance.IsConnectionStormInProgress() {
+		e.Metrics.ConnectionStorm.Set(1)
+	} else {
+		e.Metrics.ConnectionStorm.Set(0)
+	}
+
 	if e.instance.MightBeUnavailable() {
 		log.Info("metrics collection skipped due to instance still being down")
 		e.Metrics.Error.Set(0)
@@ -393,6 +429,8 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.collectFromPrimaryLastAvailableBackupTimestamp()
 
 		e.collectFromPrimaryLastFailedBackupTimestamp()
+
+		e.collectFromPrimaryLaggingReplicas()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -409,6 +447,13 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.PgWALDirectory.Reset()
 	}
 
+	if err := collectSessionsExceedingTimeout(e, db); err != nil {
+		log.Error(err, "while collecting the sessions exceeding their timeouts")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.SessionsExceedingTimeout").Inc()
+		e.Metrics.SessionsExceedingTimeout.Reset()
+	}
+
 	if err := collectPGVersion(e); err != nil {
 		log.Error(err, "while collecting PGVersion metrics")
 		e.Metrics.Error.Set(1)
@@ -489,6 +534,23 @@ func (e *Exporter) collectNodesUsed() {
 	e.Metrics.NodesUsed.Set(float64(cluster.Status.Topology.NodesUsed))
 }
 
+func (e *Exporter) collectFromPrimaryLaggingReplicas() {
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.LaggingReplicas").Inc()
+		e.Metrics.LaggingReplicas.Set(0)
+		return
+	}
+
+	e.Metrics.LaggingReplicas.Set(float64(len(cluster.Status.LaggingReplicas)))
+}
+
 func (e *Exporter) collectFromPrimaryLastFailedBackupTimestamp() {
 	const errorLabel = "Collect.LastFailedBackupTimestamp"
 	e.setTimestampMetric(e.Metrics.LastFailedBackupTimestamp, errorLabel, func(cluster *apiv1.Cluster) string {
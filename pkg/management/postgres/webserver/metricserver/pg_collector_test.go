@@ -235,6 +235,33 @@ var _ = Describe("ensure timestamp metric it's set properly", func() {
 			Expect(pgCollectionErrorMetric).To(BeNil())
 		})
 	})
+
+	Context("collectFromPrimaryLaggingReplicas", func() {
+		const laggingReplicasName = "cnpg_collector_lagging_replicas"
+
+		It("should return the number of lagging replicas", func() {
+			cluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-example",
+				},
+				Status: apiv1.ClusterStatus{
+					LaggingReplicas: []string{"cluster-example-2", "cluster-example-3"},
+				},
+			}
+			cache.Store(cache.ClusterKey, cluster)
+
+			exporter.collectFromPrimaryLaggingReplicas()
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.LaggingReplicas)
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			laggingReplicasMetric := getMetric(metrics, laggingReplicasName)
+			Expect(laggingReplicasMetric).ToNot(BeNil())
+			Expect(laggingReplicasMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(2))
+		})
+	})
 })
 
 type nameGetter interface {
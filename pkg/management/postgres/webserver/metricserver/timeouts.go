@@ -0,0 +1,202 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// timeoutScope identifies the database and the role a default setting
+// applies to, using the OID 0 for all the databases or all the roles,
+// like in pg_db_role_setting
+type timeoutScope struct {
+	database uint32
+	role     uint32
+}
+
+// sessionTimeouts are the timeouts defined in each scope, together with
+// the ones of the PostgreSQL configuration
+type sessionTimeouts struct {
+	defaults map[string]time.Duration
+	scoped   map[timeoutScope]map[string]time.Duration
+}
+
+// timedSession is a session which is running a statement or is idle in
+// a transaction, with the timeout limiting its duration
+type timedSession struct {
+	database uint32
+	datname  string
+	role     uint32
+	usename  string
+	timeout  string
+	duration time.Duration
+}
+
+// effectiveTimeout gets the value of a timeout for a session in the passed
+// database and role. Like in PostgreSQL, the settings of the database and
+// role pair take precedence over the ones of the role, which in turn take
+// precedence over the ones of the database
+func (timeouts sessionTimeouts) effectiveTimeout(database, role uint32, name string) time.Duration {
+	for _, scope := range []timeoutScope{
+		{database: database, role: role},
+		{role: role},
+		{database: database},
+	} {
+		if value, ok := timeouts.scoped[scope][name]; ok {
+			return value
+		}
+	}
+
+	return timeouts.defaults[name]
+}
+
+// collectSessionsExceedingTimeout counts, for each database and role, the
+// sessions running for longer than the timeouts which should have stopped
+// them, such as the ones which overrode the defaults
+func collectSessionsExceedingTimeout(e *Exporter, db *sql.DB) error {
+	timeouts, err := getSessionTimeouts(db)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(
+		`SELECT datid, datname, usesysid, usename,
+			CASE WHEN state = 'active' THEN $1 ELSE $2 END,
+			EXTRACT(EPOCH FROM pg_catalog.clock_timestamp() -
+				CASE WHEN state = 'active' THEN query_start ELSE state_change END)
+		FROM pg_catalog.pg_stat_activity
+		WHERE backend_type = 'client backend'
+			AND state IN ('active', 'idle in transaction', 'idle in transaction (aborted)')
+			AND pid <> pg_catalog.pg_backend_pid()`,
+		postgresconf.StatementTimeoutParameter,
+		postgresconf.IdleInTransactionSessionTimeoutParameter)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var sessions []timedSession
+	for rows.Next() {
+		var session timedSession
+		var seconds float64
+		if err := rows.Scan(
+			&session.database,
+			&session.datname,
+			&session.role,
+			&session.usename,
+			&session.timeout,
+			&seconds,
+		); err != nil {
+			return err
+		}
+		session.duration = time.Duration(seconds * float64(time.Second))
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Forget the sessions which are not exceeding their timeouts anymore
+	e.Metrics.SessionsExceedingTimeout.Reset()
+	for _, session := range sessions {
+		timeout := timeouts.effectiveTimeout(session.database, session.role, session.timeout)
+		if timeout > 0 && session.duration > timeout {
+			e.Metrics.SessionsExceedingTimeout.
+				WithLabelValues(session.datname, session.usename, session.timeout).
+				Inc()
+		}
+	}
+
+	return nil
+}
+
+// getSessionTimeouts reads the timeouts from the PostgreSQL configuration
+// and from the default settings of the databases and the roles.
+// The server-wide values are read from the configuration files, falling
+// back to the built-in defaults, as the ones in pg_settings are the ones
+// of the current session, which are affected by the settings of its role
+// and database
+func getSessionTimeouts(db *sql.DB) (sessionTimeouts, error) {
+	timeouts := sessionTimeouts{
+		defaults: make(map[string]time.Duration),
+		scoped:   make(map[timeoutScope]map[string]time.Duration),
+	}
+
+	rows, err := db.Query(
+		`SELECT s.name, COALESCE(
+			(SELECT f.setting FROM pg_catalog.pg_file_settings f
+				WHERE f.name = s.name AND f.applied
+				ORDER BY f.seqno DESC LIMIT 1),
+			s.boot_val)
+		FROM pg_catalog.pg_settings s
+		WHERE s.name = ANY($1)`,
+		pq.Array(postgresconf.SessionTimeoutParameters))
+	if err != nil {
+		return timeouts, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var name, setting string
+		if err := rows.Scan(&name, &setting); err != nil {
+			return timeouts, err
+		}
+		if value, err := postgresconf.ParseTimeoutParameter(setting); err == nil {
+			timeouts.defaults[name] = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return timeouts, err
+	}
+
+	settingRows, err := db.Query("SELECT setdatabase, setrole, setconfig FROM pg_catalog.pg_db_role_setting")
+	if err != nil {
+		return timeouts, err
+	}
+	defer func() {
+		_ = settingRows.Close()
+	}()
+
+	for settingRows.Next() {
+		var scope timeoutScope
+		var settings pq.StringArray
+		if err := settingRows.Scan(&scope.database, &scope.role, &settings); err != nil {
+			return timeouts, err
+		}
+		for name, value := range postgresconf.GetTimeoutSettings(settings) {
+			parsedValue, err := postgresconf.ParseTimeoutParameter(value)
+			if err != nil {
+				continue
+			}
+			if timeouts.scoped[scope] == nil {
+				timeouts.scoped[scope] = make(map[string]time.Duration)
+			}
+			timeouts.scoped[scope][name] = parsedValue
+		}
+	}
+
+	return timeouts, settingRows.Err()
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sessions exceeding timeout metric", func() {
+	It("counts the sessions exceeding the timeouts of their database and role", func() {
+		exporter := NewExporter(postgres.NewInstance())
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		// the server-wide defaults are read from the configuration files
+		mock.ExpectQuery("FROM pg_catalog.pg_file_settings").WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "setting"}).
+				AddRow("idle_in_transaction_session_timeout", "0").
+				AddRow("statement_timeout", "0").
+				AddRow("lock_timeout", "0"))
+		mock.ExpectQuery("FROM pg_catalog.pg_db_role_setting").
+			WillReturnRows(sqlmock.NewRows([]string{"setdatabase", "setrole", "setconfig"}).
+				AddRow(0, 10, []byte(`{statement_timeout=30s,search_path=public}`)).
+				AddRow(5, 0, []byte(`{idle_in_transaction_session_timeout=1min}`)).
+				AddRow(5, 10, []byte(`{statement_timeout=1h}`)))
+		mock.ExpectQuery("FROM pg_catalog.pg_stat_activity").
+			WithArgs("statement_timeout", "idle_in_transaction_session_timeout").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"datid", "datname", "usesysid", "usename", "timeout", "duration",
+			}).
+				// the timeout of the database and role pair wins
+				AddRow(5, "app", 10, "app", "statement_timeout", 7200).
+				AddRow(5, "app", 10, "app", "statement_timeout", 40).
+				// the timeout of the role
+				AddRow(6, "other", 10, "app", "statement_timeout", 45).
+				// no timeout at all
+				AddRow(6, "other", 11, "report", "statement_timeout", 36000).
+				// the timeout of the database
+				AddRow(5, "app", 11, "report", "idle_in_transaction_session_timeout", 120))
+
+		Expect(collectSessionsExceedingTimeout(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.SessionsExceedingTimeout)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		sessionsMetric := getMetric(metrics, "cnpg_collector_sessions_exceeding_timeout")
+		Expect(sessionsMetric).ToNot(BeNil())
+		counts := make(map[string]float64)
+		for _, metric := range sessionsMetric.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			counts[labels["datname"]+"/"+labels["usename"]+"/"+labels["timeout"]] = metric.GetGauge().GetValue()
+		}
+		Expect(counts).To(Equal(map[string]float64{
+			"app/app/statement_timeout":                      1,
+			"other/app/statement_timeout":                    1,
+			"app/report/idle_in_transaction_session_timeout": 1,
+		}))
+	})
+})
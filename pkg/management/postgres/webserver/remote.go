@@ -27,6 +27,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -82,6 +83,7 @@ func NewRemoteWebServer(
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
 	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
 	serveMux.HandleFunc(url.PathPGControlData, endpoints.pgControlData)
+	serveMux.HandleFunc(url.PathPgCheckpoint, endpoints.pgCheckpoint)
 	serveMux.HandleFunc(url.PathUpdate, endpoints.updateInstanceManager(cancelFunc, exitedConditions))
 
 	server := &http.Server{
@@ -94,11 +96,12 @@ func NewRemoteWebServer(
 	return NewWebServer(instance, server), nil
 }
 
-func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, _ *http.Request) {
-	// If `pg_rewind` is running the Pod is starting up.
+func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, req *http.Request) {
+	// If `pg_rewind` is running, or the primary is being cloned again,
+	// the Pod is starting up.
 	// We need to report it healthy to avoid being killed by the kubelet.
 	// Same goes for instances with fencing on.
-	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() {
+	if ws.instance.PgRewindIsRunning || ws.instance.RecloneIsRunning.Load() || ws.instance.MightBeUnavailable() {
 		log.Trace("Liveness probe skipped")
 		_, _ = fmt.Fprint(w, "Skipped")
 		return
@@ -111,10 +114,37 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, _ *ht
 		return
 	}
 
+	if err := ws.isPrimaryIsolated(req.Context()); err != nil {
+		log.Warning("Liveness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log.Trace("Liveness probe succeeding")
 	_, _ = fmt.Fprint(w, "OK")
 }
 
+// isPrimaryIsolated checks whether this instance is a primary which
+// is isolated from the rest of the cluster
+func (ws *remoteWebserverEndpoints) isPrimaryIsolated(ctx context.Context) error {
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return nil
+	}
+
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return nil
+	}
+
+	db, err := ws.instance.GetSuperUserDB()
+	if err != nil {
+		return nil
+	}
+
+	return checkPrimaryIsolation(ctx, ws.typedClient, db, cluster)
+}
+
 // This is the readiness probe
 func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, _ *http.Request) {
 	if err := ws.instance.IsServerReady(); err != nil {
@@ -181,6 +211,24 @@ func (ws *remoteWebserverEndpoints) pgControlData(w http.ResponseWriter, _ *http
 	_, _ = w.Write(res)
 }
 
+// pgCheckpoint issues a checkpoint on the instance, which is requested
+// by the operator before a planned switchover to shorten the shutdown
+// of the primary
+func (ws *remoteWebserverEndpoints) pgCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := ws.instance.Checkpoint(r.Context()); err != nil {
+		log.Info("Cannot issue the requested checkpoint", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
 // updateInstanceManager replace the instance with one in the
 // new binary
 func (ws *remoteWebserverEndpoints) updateInstanceManager(
@@ -0,0 +1,227 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// pgBaseBackupName is the name of the pg_basebackup executable
+const pgBaseBackupName = "pg_basebackup"
+
+var (
+	// startWalLocationRe matches the start of the backup in the
+	// backup_label file
+	startWalLocationRe = regexp.MustCompile(`(?m)^START WAL LOCATION: ([0-9A-F]+/[0-9A-F]+) \(file ([0-9A-F]{24})\)$`)
+
+	// startTimelineRe matches the timeline of the backup in the
+	// backup_label file
+	startTimelineRe = regexp.MustCompile(`(?m)^START TIMELINE: ([0-9]+)$`)
+)
+
+// backupManifest is the part of the manifest written by pg_basebackup
+// reporting the range of WAL needed to restore the backup
+type backupManifest struct {
+	WALRanges []struct {
+		EndLSN string `json:"End-LSN"`
+	} `json:"WAL-Ranges"`
+}
+
+// TakeBackup runs pg_basebackup to store a base backup in the directory
+// of the server, using the tar format. The WAL files needed to make the
+// backup consistent are included in it too, so that the backup can be
+// restored even when taken from a standby, whose WAL files are not
+// archived
+func TakeBackup(
+	ctx context.Context,
+	serverDirectory, backupID, connectionString string,
+) (*catalog.BarmanBackup, error) {
+	contextLogger := log.FromContext(ctx)
+	backupDirectory := path.Join(serverDirectory, baseDirectory, backupID)
+	if err := fileutils.EnsureParentDirectoryExist(backupDirectory); err != nil {
+		return nil, err
+	}
+
+	options := []string{
+		"--pgdata", backupDirectory,
+		"--format", "tar",
+		"--wal-method", "fetch",
+		"--checkpoint", "fast",
+		"--label", backupID,
+		"--no-password",
+		"--dbname", connectionString,
+	}
+
+	beginTime := time.Now()
+	contextLogger.Info("Starting pg_basebackup", "backupDirectory", backupDirectory)
+	cmd := exec.CommandContext(ctx, pgBaseBackupName, options...) // #nosec G204
+	if err := execlog.RunStreaming(cmd, pgBaseBackupName); err != nil {
+		if removeErr := os.RemoveAll(backupDirectory); removeErr != nil {
+			contextLogger.Error(removeErr, "while removing the directory of the failed backup")
+		}
+		return nil, fmt.Errorf("while running pg_basebackup: %w", err)
+	}
+
+	backupInfo, err := readBackupInfo(backupDirectory)
+	if err != nil {
+		return nil, err
+	}
+	backupInfo.ID = backupID
+	backupInfo.Label = backupID
+	backupInfo.BeginTime = beginTime
+	backupInfo.EndTime = time.Now()
+
+	content, err := json.Marshal(backupInfo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fileutils.WriteFileAtomic(path.Join(backupDirectory, backupInfoFile), content, 0o600); err != nil {
+		return nil, err
+	}
+
+	return backupInfo, nil
+}
+
+// readBackupInfo extracts the information about a base backup from
+// the files written by pg_basebackup
+func readBackupInfo(backupDirectory string) (*catalog.BarmanBackup, error) {
+	var result catalog.BarmanBackup
+
+	entries, err := os.ReadDir(backupDirectory)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		size += info.Size()
+	}
+	result.Size = &size
+
+	if err := readBaseArchive(path.Join(backupDirectory, baseArchiveFile), &result); err != nil {
+		return nil, err
+	}
+
+	// The manifest is available since PostgreSQL 13
+	manifestContent, err := os.ReadFile(path.Join(backupDirectory, "backup_manifest")) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return &result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest backupManifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("while parsing the backup manifest: %w", err)
+	}
+	if len(manifest.WALRanges) > 0 {
+		result.EndLSN = manifest.WALRanges[len(manifest.WALRanges)-1].EndLSN
+	}
+
+	return &result, nil
+}
+
+// readBaseArchive reads the start of the backup from the backup_label
+// file and the last WAL file needed to restore it from the pg_wal
+// directory of the tar archive of PGDATA
+func readBaseArchive(archivePath string, result *catalog.BarmanBackup) error {
+	archive, err := os.Open(archivePath) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = archive.Close()
+	}()
+
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("while reading %s: %w", archivePath, err)
+		}
+
+		name := path.Clean(header.Name)
+		switch {
+		case name == "backup_label":
+			label, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			if err := parseBackupLabel(string(label), result); err != nil {
+				return err
+			}
+
+		case path.Dir(name) == "pg_wal" && postgres.IsWALFile(name):
+			if walName := path.Base(name); walName > result.EndWal {
+				result.EndWal = walName
+			}
+		}
+	}
+
+	if result.BeginWal == "" {
+		return fmt.Errorf("backup_label not found in %s", archivePath)
+	}
+	if result.EndWal == "" {
+		result.EndWal = result.BeginWal
+	}
+
+	return nil
+}
+
+// parseBackupLabel reads the start of the backup from the content
+// of the backup_label file
+func parseBackupLabel(label string, result *catalog.BarmanBackup) error {
+	startWalLocation := startWalLocationRe.FindStringSubmatch(label)
+	if startWalLocation == nil {
+		return fmt.Errorf("start WAL location not found in the backup label")
+	}
+	result.BeginLSN = startWalLocation[1]
+	result.BeginWal = startWalLocation[2]
+
+	if startTimeline := startTimelineRe.FindStringSubmatch(label); startTimeline != nil {
+		timeline, err := strconv.Atoi(startTimeline[1])
+		if err != nil {
+			return err
+		}
+		result.TimeLine = timeline
+	}
+
+	return nil
+}
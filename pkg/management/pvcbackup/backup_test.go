@@ -0,0 +1,217 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tarEntry is an entry of a test tar archive
+type tarEntry struct {
+	name     string
+	content  string
+	linkname string
+	typeflag byte
+}
+
+// writeTestArchive writes a tar archive with the passed entries
+func writeTestArchive(archivePath string, entries ...tarEntry) {
+	file, err := os.Create(archivePath) // #nosec G304
+	Expect(err).ToNot(HaveOccurred())
+	writer := tar.NewWriter(file)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Mode:     0o600,
+			Size:     int64(len(entry.content)),
+			Linkname: entry.linkname,
+			Typeflag: entry.typeflag,
+		}
+		if header.Typeflag == 0 {
+			header.Typeflag = tar.TypeReg
+		}
+		if header.Typeflag == tar.TypeDir {
+			header.Mode = 0o700
+		}
+		Expect(writer.WriteHeader(header)).To(Succeed())
+		_, err := writer.Write([]byte(entry.content))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(writer.Close()).To(Succeed())
+	Expect(file.Close()).To(Succeed())
+}
+
+const testBackupLabel = `START WAL LOCATION: 0/2000028 (file 000000010000000000000002)
+CHECKPOINT LOCATION: 0/2000060
+BACKUP METHOD: streamed
+BACKUP FROM: standby
+START TIME: 2024-01-10 10:00:00 UTC
+LABEL: 20240110100000
+START TIMELINE: 1
+`
+
+// writeTestBackup writes a completed base backup in the directory of
+// the server
+func writeTestBackup(serverDirectory string, backup catalog.BarmanBackup) {
+	backupDirectory := path.Join(serverDirectory, "base", backup.ID)
+	Expect(os.MkdirAll(backupDirectory, 0o700)).To(Succeed())
+	content, err := json.Marshal(backup)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(os.WriteFile(path.Join(backupDirectory, "backup.info"), content, 0o600)).To(Succeed())
+}
+
+var _ = Describe("Backup information", func() {
+	var backupDirectory string
+
+	BeforeEach(func() {
+		backupDirectory = GinkgoT().TempDir()
+		writeTestArchive(path.Join(backupDirectory, "base.tar"),
+			tarEntry{name: "PG_VERSION", content: "16\n"},
+			tarEntry{name: "backup_label", content: testBackupLabel},
+			tarEntry{name: "pg_wal", typeflag: tar.TypeDir},
+			tarEntry{name: "pg_wal/000000010000000000000002", content: "wal"},
+			tarEntry{name: "pg_wal/000000010000000000000003", content: "wal"},
+		)
+	})
+
+	It("reads the range of WAL needed to restore the backup", func() {
+		Expect(os.WriteFile(path.Join(backupDirectory, "backup_manifest"),
+			[]byte(`{"WAL-Ranges": [{"Timeline": 1, "Start-LSN": "0/2000028", "End-LSN": "0/3000100"}]}`),
+			0o600)).To(Succeed())
+
+		info, err := readBackupInfo(backupDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.BeginLSN).To(Equal("0/2000028"))
+		Expect(info.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(info.EndWal).To(Equal("000000010000000000000003"))
+		Expect(info.EndLSN).To(Equal("0/3000100"))
+		Expect(info.TimeLine).To(Equal(1))
+		Expect(*info.Size).To(BeNumerically(">", 0))
+	})
+
+	It("works without the backup manifest", func() {
+		info, err := readBackupInfo(backupDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.BeginWal).To(Equal("000000010000000000000002"))
+		Expect(info.EndLSN).To(BeEmpty())
+	})
+
+	It("fails when the backup label is missing", func() {
+		writeTestArchive(path.Join(backupDirectory, "base.tar"),
+			tarEntry{name: "PG_VERSION", content: "16\n"})
+		_, err := readBackupInfo(backupDirectory)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Backup catalog and retention policy", func() {
+	var serverDirectory string
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		serverDirectory = GinkgoT().TempDir()
+		beginWals := map[int]string{
+			1:  "000000010000000000000002",
+			10: "000000010000000000000010",
+			20: "000000020000000000000020",
+			30: "000000020000000000000030",
+		}
+		for day, beginWal := range beginWals {
+			begin := time.Date(2024, 1, day, 10, 0, 0, 0, time.UTC)
+			writeTestBackup(serverDirectory, catalog.BarmanBackup{
+				ID:        begin.Format("20060102150405"),
+				BeginTime: begin,
+				EndTime:   begin.Add(time.Hour),
+				BeginWal:  beginWal,
+				TimeLine:  1,
+			})
+		}
+
+		// A backup whose pg_basebackup is still running
+		Expect(os.MkdirAll(path.Join(serverDirectory, "base", "20240131110000"), 0o700)).To(Succeed())
+
+		Expect(os.MkdirAll(path.Join(serverDirectory, "wals"), 0o700)).To(Succeed())
+		for _, name := range []string{
+			"000000010000000000000002",
+			"000000010000000000000010",
+			"000000010000000000000010.00000028.backup",
+			"000000010000000000000015.partial",
+			"00000002.history",
+			"000000020000000000000020",
+			"000000020000000000000030",
+		} {
+			Expect(os.WriteFile(path.Join(serverDirectory, "wals", name), nil, 0o600)).To(Succeed())
+		}
+	})
+
+	It("lists the completed backups only", func() {
+		backupCatalog, err := GetCatalog(serverDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupCatalog.List).To(HaveLen(4))
+		Expect(backupCatalog.LatestBackupInfo().ID).To(Equal("20240130100000"))
+	})
+
+	It("returns an empty catalog when no backup was taken", func() {
+		backupCatalog, err := GetCatalog(GinkgoT().TempDir())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupCatalog.List).To(BeEmpty())
+	})
+
+	It("keeps the backups needed to recover to any point of the window", func() {
+		Expect(DeleteBackupsByPolicy(ctx, serverDirectory, now.AddDate(0, 0, -15))).To(Succeed())
+
+		backupCatalog, err := GetCatalog(serverDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupCatalog.List).To(HaveLen(3))
+		Expect(backupCatalog.List[0].ID).To(Equal("20240110100000"))
+
+		wals, err := os.ReadDir(path.Join(serverDirectory, "wals"))
+		Expect(err).ToNot(HaveOccurred())
+		names := make([]string, 0, len(wals))
+		for _, wal := range wals {
+			names = append(names, wal.Name())
+		}
+		Expect(names).To(ConsistOf(
+			"000000010000000000000010",
+			"000000010000000000000010.00000028.backup",
+			"000000010000000000000015.partial",
+			"00000002.history",
+			"000000020000000000000020",
+			"000000020000000000000030",
+		))
+	})
+
+	It("never deletes the latest backup", func() {
+		Expect(DeleteBackupsByPolicy(ctx, serverDirectory, now)).To(Succeed())
+
+		backupCatalog, err := GetCatalog(serverDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backupCatalog.List).To(HaveLen(1))
+		Expect(backupCatalog.List[0].ID).To(Equal("20240130100000"))
+		Expect(path.Join(serverDirectory, "wals", "00000002.history")).To(BeARegularFile())
+		Expect(path.Join(serverDirectory, "wals", "000000020000000000000020")).ToNot(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// GetCatalog reads the catalog of the completed base backups of the
+// server. The directories of the backups lacking the backup.info file,
+// whose pg_basebackup is still running or has been interrupted, are
+// skipped
+func GetCatalog(serverDirectory string) (*catalog.Catalog, error) {
+	entries, err := os.ReadDir(path.Join(serverDirectory, baseDirectory))
+	if errors.Is(err, os.ErrNotExist) {
+		return catalog.NewCatalog(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]catalog.BarmanBackup, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile( // #nosec G304
+			path.Join(serverDirectory, baseDirectory, entry.Name(), backupInfoFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var backup catalog.BarmanBackup
+		if err := json.Unmarshal(content, &backup); err != nil {
+			return nil, fmt.Errorf("while parsing the information about backup %s: %w", entry.Name(), err)
+		}
+		backups = append(backups, backup)
+	}
+
+	return catalog.NewCatalog(backups), nil
+}
+
+// DeleteBackupsByPolicy enforces a recovery window starting at the
+// cutoff time: the base backups which are not needed to recover to any
+// point after the cutoff are deleted, together with the WAL files
+// preceding the oldest base backup that is kept. The latest backup
+// is never deleted
+func DeleteBackupsByPolicy(ctx context.Context, serverDirectory string, cutoff time.Time) error {
+	contextLogger := log.FromContext(ctx)
+
+	backupCatalog, err := GetCatalog(serverDirectory)
+	if err != nil {
+		return err
+	}
+	if backupCatalog.Len() == 0 {
+		return nil
+	}
+
+	// The catalog is sorted from the oldest backup, and the most recent
+	// backup ended before the cutoff is the one starting the window
+	firstKept := 0
+	for idx, backup := range backupCatalog.List {
+		if backup.EndTime.Before(cutoff) {
+			firstKept = idx
+		}
+	}
+
+	for _, backup := range backupCatalog.List[:firstKept] {
+		contextLogger.Info("Deleting backup according to the retention policy", "backupID", backup.ID)
+		if err := os.RemoveAll(path.Join(serverDirectory, baseDirectory, backup.ID)); err != nil {
+			return fmt.Errorf("while deleting backup %s: %w", backup.ID, err)
+		}
+	}
+
+	return deleteWALsBefore(ctx, serverDirectory, backupCatalog.List[firstKept].BeginWal)
+}
+
+// deleteWALsBefore deletes the WAL files preceding the passed one from
+// the archive of the server. The timeline history files are kept, as
+// they are needed to follow the timeline switches during the recovery
+func deleteWALsBefore(ctx context.Context, serverDirectory, walName string) error {
+	archiveDirectory := path.Join(serverDirectory, walsDirectory)
+	entries, err := os.ReadDir(archiveDirectory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	for _, entry := range entries {
+		// The .partial and .backup files are named after their segment
+		name := entry.Name()
+		if len(name) < len(walName) || !postgres.IsWALFile(name[:len(walName)]) ||
+			name[:len(walName)] >= walName {
+			continue
+		}
+
+		if err := os.Remove(path.Join(archiveDirectory, name)); err != nil {
+			return err
+		}
+		deleted++
+	}
+
+	log.FromContext(ctx).Info("Deleted the WAL files not needed by the backups",
+		"firstNeededWAL", walName, "deletedWALFiles", deleted)
+	return nil
+}
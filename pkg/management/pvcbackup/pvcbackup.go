@@ -0,0 +1,96 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"errors"
+	"os"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// VolumeDirectory is where the PersistentVolumeClaim storing the
+	// backups of the cluster is mounted
+	VolumeDirectory = "/var/lib/postgresql/backups"
+
+	// RecoveryVolumeDirectory is where the recovery job mounts the
+	// PersistentVolumeClaim storing the backups of the recovery source,
+	// unless it's the same claim used by the cluster
+	RecoveryVolumeDirectory = "/var/lib/postgresql/recovery-backups"
+
+	// walsDirectory is the directory of a server containing the WAL archive
+	walsDirectory = "wals"
+
+	// baseDirectory is the directory of a server containing the base backups,
+	// one per subdirectory named after the backup ID
+	baseDirectory = "base"
+
+	// backupInfoFile is the file describing a completed base backup
+	backupInfoFile = "backup.info"
+
+	// baseArchiveFile is the tar archive produced by pg_basebackup with
+	// the content of PGDATA
+	baseArchiveFile = "base.tar"
+)
+
+// ErrWALNotFound is returned when the requested WAL file is not archived
+var ErrWALNotFound = errors.New("WAL file not found in the archive")
+
+// ServerDirectory gets the directory where the WAL archive and the base
+// backups of the cluster are stored
+func ServerDirectory(cluster *apiv1.Cluster) string {
+	return path.Join(VolumeDirectory, cluster.GetBackupPVCServerName())
+}
+
+// RecoveryServerDirectory gets the directory where the WAL archive and
+// the base backups of the recovery source of the cluster are stored
+func RecoveryServerDirectory(cluster *apiv1.Cluster, source *apiv1.ExternalCluster) string {
+	return path.Join(recoveryVolumeDirectory(cluster, source.PVC.ClaimName), source.GetServerName())
+}
+
+// BackupServerDirectory gets the directory where the WAL archive and
+// the base backups of the server of a Backup taken with the `pvc` method
+// are stored, when recovering the cluster from it
+func BackupServerDirectory(cluster *apiv1.Cluster, backup *apiv1.Backup) string {
+	return path.Join(recoveryVolumeDirectory(cluster, backup.Status.ClaimName), backup.Status.ServerName)
+}
+
+// recoveryVolumeDirectory gets the directory where the recovery job mounts
+// the passed PersistentVolumeClaim
+func recoveryVolumeDirectory(cluster *apiv1.Cluster, claimName string) string {
+	if backupPVC := cluster.GetBackupPVC(); backupPVC != nil && backupPVC.ClaimName == claimName {
+		return VolumeDirectory
+	}
+
+	return RecoveryVolumeDirectory
+}
+
+// syncDirectory flushes the entries of a directory to the disk, making
+// the files renamed into it durable
+func syncDirectory(directory string) error {
+	dir, err := os.Open(directory) // #nosec G304
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		_ = dir.Close()
+		return err
+	}
+	return dir.Close()
+}
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// tablespaceArchiveRe matches the tar archives produced by pg_basebackup
+// for the tablespaces, which are named after their OID
+var tablespaceArchiveRe = regexp.MustCompile(`^([0-9]+)\.tar$`)
+
+// RestoreBackup extracts a base backup of the server into PGDATA. The
+// tablespaces are extracted in the locations pointed by the symbolic
+// links in pg_tblspc, that are the same used by the backed up cluster
+func RestoreBackup(ctx context.Context, serverDirectory, backupID, pgData string) error {
+	contextLogger := log.FromContext(ctx)
+	backupDirectory := path.Join(serverDirectory, baseDirectory, backupID)
+
+	contextLogger.Info("Extracting the base backup", "backupDirectory", backupDirectory, "pgData", pgData)
+	if err := extractArchive(path.Join(backupDirectory, baseArchiveFile), pgData); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(backupDirectory)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		matches := tablespaceArchiveRe.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		location, err := os.Readlink(path.Join(pgData, "pg_tblspc", matches[1]))
+		if err != nil {
+			return fmt.Errorf("while looking for the location of tablespace %s: %w", matches[1], err)
+		}
+
+		contextLogger.Info("Extracting the tablespace", "oid", matches[1], "location", location)
+		if err := extractArchive(path.Join(backupDirectory, entry.Name()), location); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractArchive extracts a tar archive produced by pg_basebackup into
+// the passed directory
+func extractArchive(archivePath, destination string) error {
+	archive, err := os.Open(archivePath) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = archive.Close()
+	}()
+
+	if err := os.MkdirAll(destination, 0o700); err != nil {
+		return err
+	}
+
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("while reading %s: %w", archivePath, err)
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path %s in %s", header.Name, archivePath)
+		}
+		target := path.Join(destination, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+
+		case tar.TypeSymlink:
+			if err := fileutils.EnsureParentDirectoryExist(target); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := extractFile(reader, target, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractFile writes the current file of the tar archive
+func extractFile(reader io.Reader, target string, mode os.FileMode) (err error) {
+	if err := fileutils.EnsureParentDirectoryExist(target); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeError := file.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	// The archives are written by pg_basebackup, and not compressed
+	_, err = io.Copy(file, reader) // #nosec G110
+	return err
+}
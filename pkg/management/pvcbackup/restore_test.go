@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"archive/tar"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restore of a base backup", func() {
+	var serverDirectory, backupDirectory, pgData, tablespaceLocation string
+
+	BeforeEach(func() {
+		serverDirectory = GinkgoT().TempDir()
+		backupDirectory = path.Join(serverDirectory, "base", "20240110100000")
+		Expect(os.MkdirAll(backupDirectory, 0o700)).To(Succeed())
+		pgData = path.Join(GinkgoT().TempDir(), "pgdata")
+		tablespaceLocation = path.Join(GinkgoT().TempDir(), "data")
+	})
+
+	It("extracts PGDATA and the tablespaces", func() {
+		writeTestArchive(path.Join(backupDirectory, "base.tar"),
+			tarEntry{name: "PG_VERSION", content: "16\n"},
+			tarEntry{name: "backup_label", content: testBackupLabel},
+			tarEntry{name: "pg_wal", typeflag: tar.TypeDir},
+			tarEntry{name: "pg_wal/000000010000000000000002", content: "wal"},
+			tarEntry{name: "pg_tblspc", typeflag: tar.TypeDir},
+			tarEntry{name: "pg_tblspc/16385", typeflag: tar.TypeSymlink, linkname: tablespaceLocation},
+		)
+		writeTestArchive(path.Join(backupDirectory, "16385.tar"),
+			tarEntry{name: "PG_16_202307071", typeflag: tar.TypeDir},
+			tarEntry{name: "PG_16_202307071/16384", typeflag: tar.TypeDir},
+			tarEntry{name: "PG_16_202307071/16384/16386", content: "table"},
+		)
+
+		Expect(RestoreBackup(ctx, serverDirectory, "20240110100000", pgData)).To(Succeed())
+		Expect(os.ReadFile(path.Join(pgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(path.Join(pgData, "pg_wal", "000000010000000000000002")).To(BeARegularFile())
+		Expect(os.Readlink(path.Join(pgData, "pg_tblspc", "16385"))).To(Equal(tablespaceLocation))
+		Expect(os.ReadFile(path.Join(tablespaceLocation, "PG_16_202307071", "16384", "16386"))).
+			To(BeEquivalentTo("table"))
+	})
+
+	It("refuses the paths outside of the destination", func() {
+		writeTestArchive(path.Join(backupDirectory, "base.tar"),
+			tarEntry{name: "../escaped", content: "content"},
+		)
+
+		Expect(RestoreBackup(ctx, serverDirectory, "20240110100000", pgData)).ToNot(Succeed())
+		Expect(path.Join(path.Dir(pgData), "escaped")).ToNot(BeAnExistingFile())
+	})
+})
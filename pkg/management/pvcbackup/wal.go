@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+// ArchiveWAL copies a WAL file, whose name is relative to PGDATA, into
+// the WAL archive of the server. Archiving again a file having the same
+// content is not an error, which is what PostgreSQL expects when the
+// previous archival failed after the copy
+func ArchiveWAL(serverDirectory, pgData, walName string) error {
+	source := walName
+	if !filepath.IsAbs(source) {
+		source = path.Join(pgData, walName)
+	}
+	archiveDirectory := path.Join(serverDirectory, walsDirectory)
+	destination := path.Join(archiveDirectory, path.Base(walName))
+
+	exists, err := fileutils.FileExists(destination)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ensureSameContent(source, destination)
+	}
+
+	// The file is copied with a temporary name, so that a partially
+	// copied WAL file is never found in the archive
+	temporaryDestination := destination + ".tmp"
+	if err := fileutils.CopyFile(source, temporaryDestination); err != nil {
+		return fmt.Errorf("while copying %s to the archive: %w", walName, err)
+	}
+	if err := os.Rename(temporaryDestination, destination); err != nil {
+		return err
+	}
+
+	return syncDirectory(archiveDirectory)
+}
+
+// ensureSameContent checks that a WAL file has already been archived
+// with the same content
+func ensureSameContent(source, destination string) error {
+	sourceContent, err := os.ReadFile(source) // #nosec G304
+	if err != nil {
+		return err
+	}
+	archivedContent, err := os.ReadFile(destination) // #nosec G304
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sourceContent, archivedContent) {
+		return fmt.Errorf("%s is already archived with a different content", path.Base(destination))
+	}
+
+	return nil
+}
+
+// RestoreWAL copies a WAL file from the archive of the server to the
+// passed destination, returning ErrWALNotFound if it's not archived
+func RestoreWAL(serverDirectory, walName, destinationPath string) error {
+	source := path.Join(serverDirectory, walsDirectory, walName)
+	exists, err := fileutils.FileExists(source)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWALNotFound
+	}
+
+	return fileutils.CopyFile(source, destinationPath)
+}
+
+// RestoreCommand gets the `restore_command` fetching the WAL files from
+// the archive of the server
+func RestoreCommand(serverDirectory string) string {
+	return fmt.Sprintf("cp %s/%%f %%p", path.Join(serverDirectory, walsDirectory))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvcbackup
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive", func() {
+	const walName = "000000010000000000000001"
+
+	var pgData, serverDirectory string
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		serverDirectory = path.Join(GinkgoT().TempDir(), "cluster-example")
+		Expect(os.MkdirAll(path.Join(pgData, "pg_wal"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(path.Join(pgData, "pg_wal", walName), []byte("content"), 0o600)).To(Succeed())
+	})
+
+	It("archives and restores a WAL file", func() {
+		Expect(ArchiveWAL(serverDirectory, pgData, path.Join("pg_wal", walName))).To(Succeed())
+		Expect(path.Join(serverDirectory, "wals", walName)).To(BeARegularFile())
+		Expect(path.Join(serverDirectory, "wals", walName+".tmp")).ToNot(BeAnExistingFile())
+
+		destination := path.Join(GinkgoT().TempDir(), "RECOVERYXLOG")
+		Expect(RestoreWAL(serverDirectory, walName, destination)).To(Succeed())
+		Expect(os.ReadFile(destination)).To(BeEquivalentTo("content"))
+	})
+
+	It("accepts to archive again a file with the same content", func() {
+		Expect(ArchiveWAL(serverDirectory, pgData, path.Join("pg_wal", walName))).To(Succeed())
+		Expect(ArchiveWAL(serverDirectory, pgData, path.Join("pg_wal", walName))).To(Succeed())
+	})
+
+	It("refuses to overwrite a WAL file archived with a different content", func() {
+		Expect(ArchiveWAL(serverDirectory, pgData, path.Join("pg_wal", walName))).To(Succeed())
+		Expect(os.WriteFile(path.Join(pgData, "pg_wal", walName), []byte("changed"), 0o600)).To(Succeed())
+		Expect(ArchiveWAL(serverDirectory, pgData, path.Join("pg_wal", walName))).ToNot(Succeed())
+	})
+
+	It("reports the WAL files missing from the archive", func() {
+		err := RestoreWAL(serverDirectory, walName, path.Join(GinkgoT().TempDir(), "RECOVERYXLOG"))
+		Expect(err).To(MatchError(ErrWALNotFound))
+	})
+
+	It("builds the restore command reading from the archive", func() {
+		Expect(RestoreCommand("/var/lib/postgresql/backups/cluster-example")).
+			To(Equal("cp /var/lib/postgresql/backups/cluster-example/wals/%f %p"))
+	})
+})
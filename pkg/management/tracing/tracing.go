@@ -0,0 +1,187 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+const (
+	// TracerName is the name of the tracer used by CloudNativePG
+	TracerName = "github.com/cloudnative-pg/cloudnative-pg"
+
+	// OperatorServiceName is the service name used in the traces
+	// exported by the operator
+	OperatorServiceName = "cloudnative-pg-operator"
+
+	// InstanceManagerServiceName is the service name used in the traces
+	// exported by the instance manager
+	InstanceManagerServiceName = "cloudnative-pg-instance"
+
+	// ClusterNameKey is the attribute containing the name of the cluster
+	ClusterNameKey = attribute.Key("cnpg.cluster.name")
+
+	// ClusterUIDKey is the attribute containing the UID of the cluster,
+	// used to correlate the spans produced by the different components
+	ClusterUIDKey = attribute.Key("cnpg.cluster.uid")
+
+	// BackupNameKey is the attribute containing the name of the backup
+	// an operation refers to
+	BackupNameKey = attribute.Key("cnpg.backup.name")
+
+	// ShutdownTimeout is the maximum time spent flushing the pending
+	// spans when a process exits, so that an unreachable collector
+	// doesn't delay it
+	ShutdownTimeout = 5 * time.Second
+
+	// endpointEnvVar and tracesEndpointEnvVar are the standard OTLP
+	// environment variables defining where traces are exported
+	endpointEnvVar       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	tracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+
+	// sdkDisabledEnvVar is the standard environment variable used
+	// to disable the OpenTelemetry SDK
+	sdkDisabledEnvVar = "OTEL_SDK_DISABLED"
+)
+
+// ShutdownFunc flushes the pending spans and stops the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// IsEnabled is true when an OTLP endpoint has been configured
+// through the standard OpenTelemetry environment variables
+func IsEnabled() bool {
+	if strings.EqualFold(os.Getenv(sdkDisabledEnvVar), "true") {
+		return false
+	}
+
+	return os.Getenv(endpointEnvVar) != "" || os.Getenv(tracesEndpointEnvVar) != ""
+}
+
+// Setup configures the global tracer provider to export the spans
+// via OTLP over HTTP. The exporter is configured using the standard
+// OpenTelemetry environment variables, and when no endpoint is
+// defined this function does nothing, leaving the default no-op
+// tracer provider in place.
+// The returned function must be called before the process exits,
+// to flush the pending spans
+func Setup(ctx context.Context, serviceName string, attrs ...attribute.KeyValue) (ShutdownFunc, error) {
+	if !IsEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAttributes := []attribute.KeyValue{
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(versions.Version),
+	}
+	serviceAttributes = append(serviceAttributes, attrs...)
+
+	traceResource, err := resource.New(
+		ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(serviceAttributes...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(traceResource),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// ClusterAttributes returns the attributes identifying a cluster,
+// including its UID, which is used to correlate the spans produced
+// by the operator with the ones produced by the instances
+func ClusterAttributes(cluster metav1.Object) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ClusterNameKey.String(cluster.GetName()),
+		ClusterUIDKey.String(string(cluster.GetUID())),
+		semconv.K8SNamespaceName(cluster.GetNamespace()),
+	}
+}
+
+// PodAttributes returns the attributes identifying the Pod running
+// the instance manager
+func PodAttributes(namespace, podName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.K8SNamespaceName(namespace),
+		semconv.K8SPodName(podName),
+	}
+}
+
+// StartSpan starts a new span, child of the one contained in the
+// passed context if any
+func StartSpan(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClusterSpan starts a new span annotated with the attributes
+// of the passed cluster
+func StartClusterSpan(
+	ctx context.Context,
+	name string,
+	cluster metav1.Object,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return StartSpan(ctx, name, append(ClusterAttributes(cluster), attrs...)...)
+}
+
+// EndSpan records the outcome of the operation in the span status,
+// together with the passed error if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
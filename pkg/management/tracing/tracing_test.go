@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func setEnv(name, value string) {
+	previous, isSet := os.LookupEnv(name)
+	Expect(os.Setenv(name, value)).To(Succeed())
+	DeferCleanup(func() {
+		if isSet {
+			_ = os.Setenv(name, previous)
+		} else {
+			_ = os.Unsetenv(name)
+		}
+	})
+}
+
+var _ = Describe("Tracing configuration", func() {
+	BeforeEach(func() {
+		setEnv(endpointEnvVar, "")
+		setEnv(tracesEndpointEnvVar, "")
+		setEnv(sdkDisabledEnvVar, "")
+	})
+
+	It("is disabled when no endpoint is configured", func() {
+		Expect(IsEnabled()).To(BeFalse())
+
+		shutdown, err := Setup(context.Background(), OperatorServiceName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+	})
+
+	It("is enabled when the OTLP endpoint is configured", func() {
+		setEnv(endpointEnvVar, "http://collector:4318")
+		Expect(IsEnabled()).To(BeTrue())
+	})
+
+	It("is enabled when the OTLP traces endpoint is configured", func() {
+		setEnv(tracesEndpointEnvVar, "http://collector:4318/v1/traces")
+		Expect(IsEnabled()).To(BeTrue())
+	})
+
+	It("can be disabled even if an endpoint is configured", func() {
+		setEnv(endpointEnvVar, "http://collector:4318")
+		setEnv(sdkDisabledEnvVar, "true")
+		Expect(IsEnabled()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Cluster spans", func() {
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		previousProvider := otel.GetTracerProvider()
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(func() {
+			otel.SetTracerProvider(previousProvider)
+		})
+	})
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+			UID:       "2bd5a1a9-5c4e-4a6c-9f60-1f7a3c2b5d11",
+		},
+	}
+
+	It("are annotated with the cluster UID and nested in the parent span", func() {
+		ctx, parent := StartClusterSpan(context.Background(), "parent", cluster)
+		_, child := StartClusterSpan(ctx, "child", cluster)
+		EndSpan(child, nil)
+		EndSpan(parent, nil)
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("child"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(spans[1].SpanContext().SpanID()))
+		Expect(spans[0].Attributes()).To(ContainElements(ClusterAttributes(cluster)))
+		Expect(spans[0].Attributes()).To(ContainElement(ClusterUIDKey.String(string(cluster.UID))))
+		Expect(spans[0].Status().Code).To(Equal(codes.Ok))
+		Expect(spans[1].Status().Code).To(Equal(codes.Ok))
+	})
+
+	It("record the error they ended with", func() {
+		_, span := StartClusterSpan(context.Background(), "failing", cluster)
+		EndSpan(span, errors.New("something went wrong"))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[0].Status().Description).To(Equal("something went wrong"))
+		Expect(spans[0].Events()).To(HaveLen(1))
+		Expect(spans[0].Events()[0].Name).To(Equal("exception"))
+	})
+})
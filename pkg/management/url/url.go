@@ -40,6 +40,12 @@ const (
 	// PathPGControlData is the URL path for PostgreSQL pg_controldata output
 	PathPGControlData string = "/pg/controldata"
 
+	// PathPgCheckpoint is the URL path used to request a checkpoint
+	PathPgCheckpoint string = "/pg/checkpoint"
+
+	// PathPgPreStop is the URL path for the preStop hook of the instance Pods
+	PathPgPreStop string = "/pg/prestop"
+
 	// PathPgStatus is the URL path for PostgreSQL Status
 	PathPgStatus string = "/pg/status"
 
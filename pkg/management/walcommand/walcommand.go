@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walcommand
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// shell is the shell executing the commands
+const shell = "/bin/sh"
+
+// Expand replaces the placeholders of the command template with the
+// shell-quoted path and name of the WAL file. Like in PostgreSQL, `%p`
+// is replaced by the path, `%f` by the name and `%%` by a single `%`,
+// while any other placeholder is left untouched
+func Expand(template, path, name string) string {
+	var result strings.Builder
+	for idx := 0; idx < len(template); idx++ {
+		if template[idx] != '%' || idx == len(template)-1 {
+			result.WriteByte(template[idx])
+			continue
+		}
+
+		switch template[idx+1] {
+		case 'p':
+			result.WriteString(shellquote.Join(path))
+		case 'f':
+			result.WriteString(shellquote.Join(name))
+		case '%':
+			result.WriteByte('%')
+		default:
+			result.WriteString(template[idx : idx+2])
+		}
+		idx++
+	}
+
+	return result.String()
+}
+
+// Run executes the command template in the passed directory after having
+// expanded it, streaming its output to the logs of the instance manager.
+// The returned error is not nil when the command exits with a non-zero status
+func Run(ctx context.Context, template, directory, path, name string) error {
+	command := Expand(template, path, name)
+	cmd := exec.CommandContext(ctx, shell, "-c", command) // #nosec G204
+	cmd.Dir = directory
+	cmd.Env = os.Environ()
+
+	logger := log.FromContext(ctx).WithValues("walName", name, "command", command)
+	return execlog.RunStreamingWithLogger(cmd, shell, logger)
+}
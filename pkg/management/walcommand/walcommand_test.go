@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walcommand
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expand", func() {
+	DescribeTable("replaces the placeholders of the template",
+		func(template, expected string) {
+			Expect(Expand(template, "pg_wal/000000010000000000000001", "000000010000000000000001")).
+				To(Equal(expected))
+		},
+		Entry("with the path", "cp %p /archive", "cp pg_wal/000000010000000000000001 /archive"),
+		Entry("with the name", "cp /archive/%f %p",
+			"cp /archive/000000010000000000000001 pg_wal/000000010000000000000001"),
+		Entry("with an escaped percent", "echo 100%% %f", "echo 100% 000000010000000000000001"),
+		Entry("with an unknown placeholder", "date +%s; cp %p /archive",
+			"date +%s; cp pg_wal/000000010000000000000001 /archive"),
+		Entry("with a trailing percent", "echo %", "echo %"),
+	)
+
+	It("quotes the values for the shell", func() {
+		Expect(Expand("cp %p /archive", "pg wal/file", "file")).To(Equal("cp 'pg wal/file' /archive"))
+	})
+})
+
+var _ = Describe("Run", func() {
+	It("runs the command in the passed directory", func(ctx context.Context) {
+		directory := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(directory, "wal"), []byte("content"), 0o600)).To(Succeed())
+
+		Expect(Run(ctx, "cp %p %f.copy", directory, "wal", "archived")).To(Succeed())
+		Expect(filepath.Join(directory, "archived.copy")).To(BeAnExistingFile())
+	})
+
+	It("fails when the command exits with a non-zero status", func(ctx context.Context) {
+		Expect(Run(ctx, "test -f %p", GinkgoT().TempDir(), "missing", "missing")).ToNot(Succeed())
+	})
+})
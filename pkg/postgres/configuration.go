@@ -33,14 +33,32 @@ const (
 # FIXED RULES
 #
 
-# Grant local access (`local` user map)
+# Grant local access ('local' user map)
 local all all peer map=local
 
 # Require client certificate authentication for the streaming_replica user
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
 hostssl all cnpg_pooler_pgbouncer all cert
+{{ if .RejectLoopbackConnections }}
+#
+# HOST NETWORK
+#
+
+# The loopback interface is shared with the node, reject every
+# TCP connection coming from it
+host all all 127.0.0.1/32 reject
+host all all ::1/128 reject
+{{ end }}
+{{- if .RejectNonSuperuserConnections }}
+#
+# CONNECTION STORM PROTECTION
+#
 
+# Reject the new connections of any user but the superuser
+host all postgres all {{.DefaultAuthenticationMethod}}
+host all all all reject
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -69,7 +87,7 @@ host all all all {{.DefaultAuthenticationMethod}}
 # FIXED RULES
 #
 
-# Grant local access (`local` user map)
+# Grant local access ('local' user map)
 local {{.Username}} postgres
 
 #
@@ -241,6 +259,11 @@ type ConfigurationInfo struct {
 
 	// The number of desired number of synchronous replicas
 	SyncReplicas int
+
+	// The method used to select the synchronous replicas, that is
+	// "ANY" (the default) or "FIRST"
+	SyncReplicasMethod string
+
 	// List of additional sharedPreloadLibraries to be loaded
 	AdditionalSharedPreloadLibraries []string
 
@@ -263,6 +286,10 @@ type ConfigurationInfo struct {
 
 	// TemporaryTablespaces is the list of temporary tablespaces
 	TemporaryTablespaces []string
+
+	// The settings of the WAL tuning profile, applied on top
+	// of the default settings
+	WALTuningSettings SettingsCollection
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -455,20 +482,29 @@ var (
 )
 
 // CreateHBARules will create the content of pg_hba.conf file given
-// the rules set by the cluster spec
+// the rules set by the cluster spec. When rejectNonSuperuserConnections is
+// true, the new connections of any user but the superuser are rejected,
+// with the exception of the ones authenticated via certificates.
+// When rejectLoopbackConnections is true, the TCP connections coming
+// from the loopback interface are rejected
 func CreateHBARules(hba []string,
 	defaultAuthenticationMethod, ldapConfigString string,
+	rejectNonSuperuserConnections, rejectLoopbackConnections bool,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
-		UserRules                   []string
-		LDAPConfiguration           string
-		DefaultAuthenticationMethod string
+		UserRules                     []string
+		LDAPConfiguration             string
+		DefaultAuthenticationMethod   string
+		RejectNonSuperuserConnections bool
+		RejectLoopbackConnections     bool
 	}{
-		UserRules:                   hba,
-		LDAPConfiguration:           ldapConfigString,
-		DefaultAuthenticationMethod: defaultAuthenticationMethod,
+		UserRules:                     hba,
+		LDAPConfiguration:             ldapConfigString,
+		DefaultAuthenticationMethod:   defaultAuthenticationMethod,
+		RejectNonSuperuserConnections: rejectNonSuperuserConnections,
+		RejectLoopbackConnections:     rejectLoopbackConnections,
 	}
 
 	if err := hbaTemplate.Execute(&hbaContent, templateData); err != nil {
@@ -623,6 +659,11 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 			}
 		}
 	}
+
+	// apply the settings of the WAL tuning profile
+	for key, value := range info.WALTuningSettings {
+		configuration.OverwriteConfig(key, value)
+	}
 }
 
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
@@ -666,8 +707,13 @@ func setReplicasListConfigurations(info ConfigurationInfo, configuration *PgConf
 		for idx, name := range info.SyncReplicasElectable {
 			escapedReplicas[idx] = escapePostgresConfLiteral(name)
 		}
+		method := info.SyncReplicasMethod
+		if method == "" {
+			method = "ANY"
+		}
 		configuration.OverwriteConfig(SynchronousStandbyNames, fmt.Sprintf(
-			"ANY %v (%v)",
+			"%v %v (%v)",
+			method,
 			info.SyncReplicas,
 			strings.Join(escapedReplicas, ",")))
 	}
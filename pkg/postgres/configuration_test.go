@@ -179,6 +179,21 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("synchronous_standby_names")).
 				To(Equal("ANY 2 (\"one\",\"two\",\"three\")"))
 		})
+
+		It("uses the requested method to select the synchronous replicas", func() {
+			info := ConfigurationInfo{
+				Settings:              CnpgConfigurationSettings,
+				MajorVersion:          130000,
+				UserSettings:          settings,
+				IncludingMandatory:    true,
+				SyncReplicasElectable: []string{"one", "two"},
+				SyncReplicas:          1,
+				SyncReplicasMethod:    "FIRST",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("synchronous_standby_names")).
+				To(Equal("FIRST 1 (\"one\",\"two\")"))
+		})
 	})
 
 	It("checks if PreserveFixedSettingsFromUser works properly", func() {
@@ -223,6 +238,23 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("recovery_target_name")).To(Equal(""))
 		})
 	})
+
+	It("applies the WAL tuning settings, unless overridden by the user", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 160000,
+			UserSettings: map[string]string{
+				"max_wal_size": "4GB",
+			},
+			WALTuningSettings: map[string]string{
+				"max_wal_size":       "8192MB",
+				"checkpoint_timeout": "15min",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("max_wal_size")).To(Equal("4GB"))
+		Expect(config.GetConfig("checkpoint_timeout")).To(Equal("15min"))
+	})
 })
 
 var _ = Describe("pg_hba.conf generation", func() {
@@ -233,19 +265,40 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, "md5", "", false, false)).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, "this-one", "", false, false)).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString", false, false)).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("rejects the non superuser connections before the user-defined rules when requested", func() {
+		Expect(CreateHBARules(specRules, "md5", "", false, false)).ToNot(
+			ContainSubstring("\nhost all all all reject\n"))
+
+		rules, err := CreateHBARules(specRules, "md5", "", true, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhost all postgres all md5\nhost all all all reject\n"))
+		Expect(strings.Index(rules, "host all all all reject")).To(BeNumerically("<", strings.Index(rules, "\none\n")))
+	})
+
+	It("rejects the loopback connections before the user-defined rules when requested", func() {
+		Expect(CreateHBARules(specRules, "md5", "", false, false)).ToNot(
+			ContainSubstring("127.0.0.1/32"))
+
+		rules, err := CreateHBARules(specRules, "md5", "", false, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring("\nhost all all 127.0.0.1/32 reject\nhost all all ::1/128 reject\n"))
+		Expect(strings.Index(rules, "127.0.0.1/32")).To(BeNumerically("<", strings.Index(rules, "\none\n")))
+		Expect(strings.Index(rules, "local all all peer")).To(BeNumerically("<", strings.Index(rules, "127.0.0.1/32")))
+	})
 })
 
 var _ = Describe("pg_ident.conf generation", func() {
@@ -0,0 +1,191 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The memory model used to estimate the memory needed by an instance in the
+// worst case, which is the sum of:
+//
+//   - the shared memory allocated at startup, made of `shared_buffers` and
+//     `wal_buffers`, which is fully used once the buffers are filled
+//   - for each of the `max_connections` backends, `work_mem` scaled by
+//     `hash_mem_multiplier`, for a sort or hash operation
+//   - for each of the `autovacuum_max_workers` workers, `autovacuum_work_mem`
+//     or, when not set, `maintenance_work_mem`
+//
+// The memory used by each backend for its catalog caches and execution
+// state is not considered, while a single query can run more than one sort
+// or hash operation at a time and parallel workers have their own
+// `work_mem`: the estimation is not an upper bound, but a configuration
+// exceeding it is very likely to run out of memory under load.
+const (
+	// maxDefaultWalBuffers is the maximum size of the WAL buffers when
+	// they are sized automatically, that is the size of a WAL segment
+	maxDefaultWalBuffers int64 = 16 * 1024 * 1024
+
+	// minWalBuffers is the minimum size of the WAL buffers
+	minWalBuffers int64 = 64 * 1024
+
+	blockSize int64 = 8 * 1024
+	kiloByte  int64 = 1024
+
+	// hashMemMultiplierDefaultChangeVersion is the major version of PostgreSQL
+	// where the default value of `hash_mem_multiplier` was raised to 2.0
+	hashMemMultiplierDefaultChangeVersion = 15
+)
+
+// memoryParameterDefaults are the default values of the parameters
+// used by the memory model, as defined by the latest major version of
+// PostgreSQL
+var memoryParameterDefaults = map[string]string{
+	"shared_buffers":         "128MB",
+	"wal_buffers":            "-1",
+	"max_connections":        "100",
+	"work_mem":               "4MB",
+	"hash_mem_multiplier":    "2.0",
+	"maintenance_work_mem":   "64MB",
+	"autovacuum_max_workers": "3",
+	"autovacuum_work_mem":    "-1",
+}
+
+// MemoryUsageEstimate is the estimation of the memory needed by a
+// PostgreSQL instance in the worst case, in bytes
+type MemoryUsageEstimate struct {
+	// The shared memory allocated at startup
+	SharedMemory int64
+
+	// The memory used by the backends and by the autovacuum workers
+	BackendMemory int64
+}
+
+// Total is the total memory needed by the instance
+func (estimate MemoryUsageEstimate) Total() int64 {
+	return estimate.SharedMemory + estimate.BackendMemory
+}
+
+// EstimateMemoryUsage estimates the memory needed by an instance of the
+// passed major version running with the passed parameters, using the
+// PostgreSQL defaults for the missing ones. An unknown major version, passed
+// as zero, gets the defaults of the latest one. See the documentation of the
+// memory model above for the details
+func EstimateMemoryUsage(parameters map[string]string, majorVersion int) (MemoryUsageEstimate, error) {
+	getParameter := func(name string) string {
+		if value, ok := parameters[name]; ok {
+			return value
+		}
+		// PostgreSQL 13 and 14 use 1.0, and PostgreSQL 12 doesn't
+		// scale the memory of the hash operations at all
+		if name == "hash_mem_multiplier" && majorVersion != 0 &&
+			majorVersion < hashMemMultiplierDefaultChangeVersion {
+			return "1.0"
+		}
+		return memoryParameterDefaults[name]
+	}
+
+	var estimate MemoryUsageEstimate
+	sharedBuffers, err := parseMemoryParameter(getParameter("shared_buffers"), blockSize)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing shared_buffers: %w", err)
+	}
+	walBuffers, err := parseMemoryParameter(getParameter("wal_buffers"), blockSize)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing wal_buffers: %w", err)
+	}
+	if walBuffers < 0 {
+		walBuffers = min(max(sharedBuffers/32, minWalBuffers), maxDefaultWalBuffers)
+	}
+	estimate.SharedMemory = sharedBuffers + walBuffers
+
+	maxConnections, err := strconv.ParseInt(strings.TrimSpace(getParameter("max_connections")), 10, 64)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing max_connections: %w", err)
+	}
+	workMem, err := parseMemoryParameter(getParameter("work_mem"), kiloByte)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing work_mem: %w", err)
+	}
+	hashMemMultiplier, err := strconv.ParseFloat(strings.TrimSpace(getParameter("hash_mem_multiplier")), 64)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing hash_mem_multiplier: %w", err)
+	}
+	backendMemory := int64(float64(workMem) * hashMemMultiplier)
+
+	autovacuumMaxWorkers, err := strconv.ParseInt(strings.TrimSpace(getParameter("autovacuum_max_workers")), 10, 64)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing autovacuum_max_workers: %w", err)
+	}
+	autovacuumWorkMem, err := parseMemoryParameter(getParameter("autovacuum_work_mem"), kiloByte)
+	if err != nil {
+		return estimate, fmt.Errorf("while parsing autovacuum_work_mem: %w", err)
+	}
+	if autovacuumWorkMem < 0 {
+		autovacuumWorkMem, err = parseMemoryParameter(getParameter("maintenance_work_mem"), kiloByte)
+		if err != nil {
+			return estimate, fmt.Errorf("while parsing maintenance_work_mem: %w", err)
+		}
+	}
+
+	estimate.BackendMemory = maxConnections*backendMemory + autovacuumMaxWorkers*autovacuumWorkMem
+	return estimate, nil
+}
+
+// parseMemoryParameter parses the value of a memory parameter in bytes.
+// Like in PostgreSQL, a value without unit is expressed in the base unit
+// of the parameter, and negative values are kept as they are, as they
+// usually mean that the value is computed from other parameters
+func parseMemoryParameter(value string, baseUnit int64) (int64, error) {
+	value = strings.TrimSpace(value)
+	numberEnd := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-'
+	})
+	if numberEnd < 0 {
+		numberEnd = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:numberEnd], 64)
+	if err != nil {
+		return 0, err
+	}
+	if number < 0 {
+		return int64(number), nil
+	}
+
+	var multiplier int64
+	switch unit := strings.TrimSpace(value[numberEnd:]); unit {
+	case "":
+		multiplier = baseUnit
+	case "B":
+		multiplier = 1
+	case "kB":
+		multiplier = kiloByte
+	case "MB":
+		multiplier = kiloByte * kiloByte
+	case "GB":
+		multiplier = kiloByte * kiloByte * kiloByte
+	case "TB":
+		multiplier = kiloByte * kiloByte * kiloByte * kiloByte
+	default:
+		return 0, fmt.Errorf("invalid memory unit: %q", unit)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory usage estimation", func() {
+	const mebiByte = 1024 * 1024
+
+	It("uses the PostgreSQL defaults for the missing parameters", func() {
+		estimate, err := EstimateMemoryUsage(nil, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(estimate.SharedMemory).To(BeEquivalentTo(132 * mebiByte))
+		Expect(estimate.BackendMemory).To(BeEquivalentTo((800 + 192) * mebiByte))
+		Expect(estimate.Total()).To(BeEquivalentTo(1124 * mebiByte))
+	})
+
+	It("uses the default hash_mem_multiplier of the major version", func() {
+		estimate, err := EstimateMemoryUsage(nil, 14)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(estimate.BackendMemory).To(BeEquivalentTo((400 + 192) * mebiByte))
+
+		estimate, err = EstimateMemoryUsage(nil, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(estimate.BackendMemory).To(BeEquivalentTo((800 + 192) * mebiByte))
+	})
+
+	It("uses the configured parameters", func() {
+		estimate, err := EstimateMemoryUsage(map[string]string{
+			"shared_buffers":         "1GB",
+			"wal_buffers":            "2048",
+			"max_connections":        "10",
+			"work_mem":               "8192",
+			"hash_mem_multiplier":    "1.0",
+			"autovacuum_max_workers": "2",
+			"autovacuum_work_mem":    "100MB",
+		}, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(estimate.SharedMemory).To(BeEquivalentTo(1040 * mebiByte))
+		Expect(estimate.BackendMemory).To(BeEquivalentTo((10*8 + 200) * mebiByte))
+	})
+
+	It("caps the automatically sized WAL buffers", func() {
+		estimate, err := EstimateMemoryUsage(map[string]string{
+			"shared_buffers": "4GB",
+		}, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(estimate.SharedMemory).To(BeEquivalentTo((4096 + 16) * mebiByte))
+	})
+
+	It("complains about invalid values", func() {
+		_, err := EstimateMemoryUsage(map[string]string{"work_mem": "4 parsecs"}, 16)
+		Expect(err).To(HaveOccurred())
+
+		_, err = EstimateMemoryUsage(map[string]string{"max_connections": "many"}, 16)
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("parses the memory parameters",
+		func(value string, baseUnit int64, expected int64) {
+			Expect(parseMemoryParameter(value, baseUnit)).To(Equal(expected))
+		},
+		Entry("without unit", "16", int64(8192), int64(16*8192)),
+		Entry("in bytes", "512B", int64(1024), int64(512)),
+		Entry("in kilobytes", "64kB", int64(1024), int64(64*1024)),
+		Entry("with a space before the unit", "1 GB", int64(1024), int64(1024*mebiByte)),
+		Entry("with decimals", "1.5MB", int64(1024), int64(1536*1024)),
+		Entry("with a negative value", "-1", int64(1024), int64(-1)),
+	)
+})
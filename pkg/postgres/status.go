@@ -18,6 +18,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -46,6 +47,10 @@ type PostgresqlStatus struct {
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
+	// populated when a volume of the instance has been remounted read-only
+	// or is reporting I/O errors
+	StorageFailure string `json:"storageFailure,omitempty"`
+
 	// Archiver status
 
 	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
@@ -53,6 +58,9 @@ type PostgresqlStatus struct {
 	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
 	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
 
+	// The parameters set with ALTER SYSTEM, with their live values
+	AlterSystemParameters map[string]string `json:"alterSystemParameters,omitempty"`
+
 	// WAL Status
 
 	CurrentWAL string `json:"currentWAL,omitempty"`
@@ -94,17 +102,18 @@ type PostgresqlStatus struct {
 
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
-	ApplicationName string `json:"applicationName,omitempty"`
-	State           string `json:"state,omitempty"`
-	SentLsn         LSN    `json:"receivedLsn,omitempty"`
-	WriteLsn        LSN    `json:"writeLsn,omitempty"`
-	FlushLsn        LSN    `json:"flushLsn,omitempty"`
-	ReplayLsn       LSN    `json:"replayLsn,omitempty"`
-	WriteLag        string `json:"writeLag,omitempty"`
-	FlushLag        string `json:"flushLag,omitempty"`
-	ReplayLag       string `json:"replayLag,omitempty"`
-	SyncState       string `json:"syncState,omitempty"`
-	SyncPriority    string `json:"syncPriority,omitempty"`
+	ApplicationName  string  `json:"applicationName,omitempty"`
+	State            string  `json:"state,omitempty"`
+	SentLsn          LSN     `json:"receivedLsn,omitempty"`
+	WriteLsn         LSN     `json:"writeLsn,omitempty"`
+	FlushLsn         LSN     `json:"flushLsn,omitempty"`
+	ReplayLsn        LSN     `json:"replayLsn,omitempty"`
+	WriteLag         string  `json:"writeLag,omitempty"`
+	FlushLag         string  `json:"flushLag,omitempty"`
+	ReplayLag        string  `json:"replayLag,omitempty"`
+	ReplayLagSeconds float64 `json:"replayLagSeconds,omitempty"`
+	SyncState        string  `json:"syncState,omitempty"`
+	SyncPriority     string  `json:"syncPriority,omitempty"`
 }
 
 // PgStatBasebackup contains the information for progress of basebackup as reported by the primary instance
@@ -147,6 +156,12 @@ func (status PostgresqlStatus) HasHTTPStatus() bool {
 	return status.Error == nil
 }
 
+// HasStorageFailure checks if the instance manager is reporting a
+// failure of the storage used by this instance
+func (status PostgresqlStatus) HasStorageFailure() bool {
+	return status.StorageFailure != ""
+}
+
 // PgStatReplicationList is a list of PgStatReplication reported by the primary instance
 type PgStatReplicationList []PgStatReplication
 
@@ -386,7 +401,7 @@ func (list PostgresqlStatusList) AllReadyInstancesStatusUnreachable() bool {
 		}
 
 		hasActiveAndReady = true
-		if item.Error == nil {
+		if item.Error == nil || item.HasStorageFailure() {
 			return false
 		}
 	}
@@ -394,6 +409,31 @@ func (list PostgresqlStatusList) AllReadyInstancesStatusUnreachable() bool {
 	return hasActiveAndReady
 }
 
+// IsReportingStorageFailure checks whether the given instance is reporting
+// a failure of its storage
+func (list PostgresqlStatusList) IsReportingStorageFailure(podName string) bool {
+	for _, item := range list.Items {
+		if item.Pod.Name == podName && item.HasStorageFailure() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Errors returns the errors hit while getting the status of the
+// instances, joined together, or nil if every instance reported it
+func (list PostgresqlStatusList) Errors() error {
+	var errs []error
+	for _, item := range list.Items {
+		if item.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Pod.Name, item.Error))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // InstancesReportingStatus returns the number of instances that are Ready or MightBeUnavailable
 func (list PostgresqlStatusList) InstancesReportingStatus() int {
 	var n int
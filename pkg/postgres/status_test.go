@@ -18,6 +18,7 @@ package postgres
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -86,6 +87,12 @@ var _ = Describe("PostgreSQL status", func() {
 
 		Expect(list.IsComplete()).To(BeFalse())
 		Expect(greenList.IsComplete()).To(BeTrue())
+
+		err := list.Errors()
+		Expect(err).To(MatchError(ContainSubstring("server-04: cannot connect to PostgreSQL")))
+		Expect(err).To(MatchError(ContainSubstring("server-06: cannot connect to PostgreSQL")))
+		Expect(errors.Is(err, errCannotConnectToPostgres)).To(BeTrue())
+		Expect(greenList.Errors()).ToNot(HaveOccurred())
 	})
 
 	It("checks for pods on which we are upgrading the instance manager", func() {
@@ -133,6 +140,26 @@ var _ = Describe("PostgreSQL status", func() {
 		Expect(podList.InstancesReportingStatus()).To(BeEquivalentTo(2))
 	})
 
+	It("checks for pods reporting a storage failure", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-20"}},
+					IsPrimary: false,
+				},
+				{
+					Pod:            &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					StorageFailure: "read-only file system",
+				},
+			},
+		}
+		Expect(podList.Items[0].HasStorageFailure()).To(BeFalse())
+		Expect(podList.Items[1].HasStorageFailure()).To(BeTrue())
+		Expect(podList.IsReportingStorageFailure("server-20")).To(BeFalse())
+		Expect(podList.IsReportingStorageFailure("server-10")).To(BeTrue())
+		Expect(podList.IsReportingStorageFailure("server-30")).To(BeFalse())
+	})
+
 	Describe("when sorted", func() {
 		sort.Sort(&list)
 
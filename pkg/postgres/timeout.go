@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// IdleInTransactionSessionTimeoutParameter is the parameter terminating
+	// the sessions which are idle in an open transaction for too long
+	IdleInTransactionSessionTimeoutParameter = "idle_in_transaction_session_timeout"
+
+	// StatementTimeoutParameter is the parameter aborting the statements
+	// running for too long
+	StatementTimeoutParameter = "statement_timeout"
+
+	// LockTimeoutParameter is the parameter aborting the statements
+	// waiting for a lock for too long
+	LockTimeoutParameter = "lock_timeout"
+)
+
+// SessionTimeoutParameters are the parameters controlling the timeouts of
+// the sessions, which can have a default value for each role and database
+var SessionTimeoutParameters = []string{
+	IdleInTransactionSessionTimeoutParameter,
+	StatementTimeoutParameter,
+	LockTimeoutParameter,
+}
+
+// GetTimeoutSettings extracts the timeouts from the default settings of a
+// role or a database, which are stored as `name=value` strings
+func GetTimeoutSettings(settings []string) map[string]string {
+	var result map[string]string
+	for _, setting := range settings {
+		name, value, found := strings.Cut(setting, "=")
+		if !found || !slices.Contains(SessionTimeoutParameters, name) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[name] = value
+	}
+
+	return result
+}
+
+// ParseTimeoutParameter parses the value of a timeout parameter.
+// Like in PostgreSQL, a value without unit is expressed in milliseconds,
+// and zero means that the timeout is disabled
+func ParseTimeoutParameter(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	numberEnd := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if numberEnd < 0 {
+		numberEnd = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:numberEnd], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	var multiplier time.Duration
+	switch unit := strings.TrimSpace(value[numberEnd:]); unit {
+	case "", "ms":
+		multiplier = time.Millisecond
+	case "us":
+		multiplier = time.Microsecond
+	case "s":
+		multiplier = time.Second
+	case "min":
+		multiplier = time.Minute
+	case "h":
+		multiplier = time.Hour
+	case "d":
+		multiplier = 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("invalid time unit: %q", unit)
+	}
+
+	return time.Duration(number * float64(multiplier)), nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("timeout parameters", func() {
+	DescribeTable("parses the valid values",
+		func(value string, expected time.Duration) {
+			Expect(ParseTimeoutParameter(value)).To(Equal(expected))
+		},
+		Entry("without unit", "1500", 1500*time.Millisecond),
+		Entry("disabled", "0", time.Duration(0)),
+		Entry("in microseconds", "250us", 250*time.Microsecond),
+		Entry("in seconds", "30s", 30*time.Second),
+		Entry("in minutes", "5min", 5*time.Minute),
+		Entry("with a space before the unit", "2 h", 2*time.Hour),
+		Entry("in days", "1d", 24*time.Hour),
+		Entry("with decimals", "1.5s", 1500*time.Millisecond),
+	)
+
+	DescribeTable("refuses the invalid values",
+		func(value string) {
+			_, err := ParseTimeoutParameter(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("with an unknown unit", "5m"),
+		Entry("not a number", "forever"),
+	)
+
+	It("extracts the timeouts from the default settings", func() {
+		Expect(GetTimeoutSettings([]string{"statement_timeout=30s", "search_path=public", "lock_timeout=5s"})).
+			To(Equal(map[string]string{"statement_timeout": "30s", "lock_timeout": "5s"}))
+		Expect(GetTimeoutSettings([]string{"search_path=public"})).To(BeNil())
+	})
+})
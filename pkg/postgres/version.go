@@ -137,3 +137,38 @@ func CanUpgrade(fromImage, toImage string) (bool, error) {
 
 	return IsUpgradePossible(fromVersion, toVersion), nil
 }
+
+// GetLatestMinorImage looks in a catalog of images for the one running the
+// latest minor version of the same repository and PostgreSQL major version
+// of the passed image. An empty string is returned when the catalog doesn't
+// contain any newer image, while an error is returned when the version of
+// the passed image can't be detected
+func GetLatestMinorImage(image string, catalog []string) (string, error) {
+	reference := utils.NewReference(image)
+	if reference.Tag == "latest" {
+		return "", fmt.Errorf("cannot detect the PostgreSQL version of %s", image)
+	}
+
+	latestVersion, err := GetPostgresVersionFromTag(reference.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	latestImage := ""
+	for _, candidate := range catalog {
+		candidateReference := utils.NewReference(candidate)
+		if candidateReference.Name != reference.Name {
+			continue
+		}
+
+		candidateVersion, err := GetPostgresVersionFromTag(candidateReference.Tag)
+		if err != nil || !IsUpgradePossible(latestVersion, candidateVersion) || candidateVersion <= latestVersion {
+			continue
+		}
+
+		latestVersion = candidateVersion
+		latestImage = candidate
+	}
+
+	return latestImage, nil
+}
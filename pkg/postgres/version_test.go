@@ -118,4 +118,37 @@ var _ = Describe("PostgreSQL version handling", func() {
 			Expect(status).To(BeFalse())
 		})
 	})
+
+	Context("looking for the latest minor image in a catalog", func() {
+		catalog := []string{
+			"ghcr.io/cloudnative-pg/postgresql:15.6",
+			"ghcr.io/cloudnative-pg/postgresql:16.2",
+			"ghcr.io/cloudnative-pg/postgresql:16.1",
+			"ghcr.io/cloudnative-pg/postgresql:17.0",
+			"ghcr.io/cloudnative-pg/postgis:16.3",
+			"ghcr.io/cloudnative-pg/postgresql:not-a-version",
+		}
+
+		It("finds the latest minor of the same major and repository", func() {
+			Expect(GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql:16.0", catalog)).To(
+				Equal("ghcr.io/cloudnative-pg/postgresql:16.2"))
+			Expect(GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql:15.1", catalog)).To(
+				Equal("ghcr.io/cloudnative-pg/postgresql:15.6"))
+		})
+
+		It("returns an empty string when the image is up to date", func() {
+			Expect(GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql:16.2", catalog)).To(BeEmpty())
+			Expect(GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql:14.1", catalog)).To(BeEmpty())
+			Expect(GetLatestMinorImage("example.com/postgresql:16.0", catalog)).To(BeEmpty())
+		})
+
+		It("raise errors when the version of the image can't be detected", func() {
+			_, err := GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql:latest", catalog)
+			Expect(err).To(HaveOccurred())
+
+			_, err = GetLatestMinorImage("ghcr.io/cloudnative-pg/postgresql@sha256:"+
+				"3a5b5e3c7e5e8b4a8f4b0c5e7d7c4f0e6a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d", catalog)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -73,6 +73,9 @@ var (
 		WALSegmentNameRe +
 		`$`)
 
+	// WALHistoryRe is the timeline history file name parser
+	WALHistoryRe = regexp.MustCompile(`^` + WALTimeLineRe + `\.history$`)
+
 	// ErrorBadWALSegmentName is raised when parsing an invalid segment name
 	ErrorBadWALSegmentName = errors.New("invalid WAL segment name")
 )
@@ -96,6 +99,13 @@ func IsWALFile(name string) bool {
 	return WALSegmentRe.MatchString(baseName)
 }
 
+// IsHistoryFile check if the passed file name is a timeline history file.
+// It supports either a full file path or a simple file name
+func IsHistoryFile(name string) bool {
+	baseName := path.Base(name)
+	return WALHistoryRe.MatchString(baseName)
+}
+
 // SegmentFromName retrieves the timeline, log ID and segment ID
 // from the name of a xlog segment, and can also handle a full path
 // or a simple file name
@@ -243,4 +243,37 @@ var _ = Describe("WAL files checking", func() {
 				Equal(test.result), "name:%v expected:%v", test.name, test.result)
 		}
 	})
+
+	It("detects timeline history files", func() {
+		tests := []struct {
+			name   string
+			result bool
+		}{
+			{
+				name:   "00000002.history",
+				result: true,
+			},
+			{
+				name:   "pg_wal/0000000A.history",
+				result: true,
+			},
+			{
+				name:   "00000001000000000000000A.history",
+				result: false,
+			},
+			{
+				name:   "00000001000000000000000A",
+				result: false,
+			},
+			{
+				name:   "0000002.history",
+				result: false,
+			},
+		}
+
+		for _, test := range tests {
+			Expect(IsHistoryFile(test.name)).To(
+				Equal(test.result), "name:%v expected:%v", test.name, test.result)
+		}
+	})
 })
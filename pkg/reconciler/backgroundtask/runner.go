@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backgroundtask
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type task struct {
+	attempt string
+	done    chan struct{}
+	err     error
+}
+
+// Runner runs at most one task per cluster in the background, letting
+// the reconciliation loop poll for its completion instead of waiting for
+// it. The tasks are kept in memory only, and are lost when the operator
+// is restarted.
+// A nil Runner runs the tasks synchronously.
+type Runner struct {
+	lock     sync.Mutex
+	clusters map[types.NamespacedName]*task
+}
+
+// NewRunner creates a new Runner with no tasks
+func NewRunner() *Runner {
+	return &Runner{
+		clusters: make(map[types.NamespacedName]*task),
+	}
+}
+
+// Poll starts running fn in the background for the passed attempt of the
+// cluster, unless it is already running, and reports whether it has
+// completed and the error it returned. A completed task is forgotten once
+// reported, and a task started for a different attempt of the same
+// cluster is replaced, ignoring its result.
+func (r *Runner) Poll(cluster types.NamespacedName, attempt string, fn func() error) (bool, error) {
+	if r == nil {
+		return true, fn()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	current, ok := r.clusters[cluster]
+	if !ok || current.attempt != attempt {
+		current = &task{attempt: attempt, done: make(chan struct{})}
+		r.clusters[cluster] = current
+		go func() {
+			defer close(current.done)
+			current.err = fn()
+		}()
+		return false, nil
+	}
+
+	select {
+	case <-current.done:
+		delete(r.clusters, cluster)
+		return true, current.err
+	default:
+		return false, nil
+	}
+}
+
+// Forget removes the task of the passed cluster. A task that is still
+// running completes in the background, and its result is ignored
+func (r *Runner) Forget(cluster types.NamespacedName) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.clusters, cluster)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backgroundtask
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Background task runner", func() {
+	cluster := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+
+	poll := func(runner *Runner, attempt string, fn func() error) func() bool {
+		return func() bool {
+			done, _ := runner.Poll(cluster, attempt, fn)
+			return done
+		}
+	}
+
+	It("runs the task synchronously when the runner is nil", func() {
+		var runner *Runner
+		done, err := runner.Poll(cluster, "first", func() error { return errors.New("failed") })
+		Expect(done).To(BeTrue())
+		Expect(err).To(MatchError("failed"))
+		runner.Forget(cluster)
+	})
+
+	It("runs the task in the background and reports its result once", func() {
+		runner := NewRunner()
+		release := make(chan struct{})
+		calls := 0
+		fn := func() error {
+			calls++
+			<-release
+			return errors.New("failed")
+		}
+
+		done, err := runner.Poll(cluster, "first", fn)
+		Expect(done).To(BeFalse())
+		Expect(err).ToNot(HaveOccurred())
+
+		done, err = runner.Poll(cluster, "first", fn)
+		Expect(done).To(BeFalse())
+		Expect(err).ToNot(HaveOccurred())
+
+		close(release)
+		Eventually(poll(runner, "first", fn)).Should(BeTrue())
+		Expect(calls).To(Equal(1))
+
+		// The completed task has been forgotten, so polling again
+		// starts it once more
+		Expect(poll(runner, "first", fn)()).To(BeFalse())
+		Eventually(poll(runner, "first", fn)).Should(BeTrue())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("replaces the task of a previous attempt", func() {
+		runner := NewRunner()
+		release := make(chan struct{})
+		defer close(release)
+
+		Expect(poll(runner, "first", func() error {
+			<-release
+			return errors.New("stale")
+		})()).To(BeFalse())
+
+		var err error
+		Eventually(func() bool {
+			var done bool
+			done, err = runner.Poll(cluster, "second", func() error { return nil })
+			return done
+		}).Should(BeTrue())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("forgets the task of a cluster", func() {
+		runner := NewRunner()
+		release := make(chan struct{})
+		defer close(release)
+
+		Expect(poll(runner, "first", func() error {
+			<-release
+			return nil
+		})()).To(BeFalse())
+
+		runner.Forget(cluster)
+		Eventually(poll(runner, "first", func() error { return nil })).Should(BeTrue())
+	})
+})
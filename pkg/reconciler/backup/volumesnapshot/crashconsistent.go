@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumesnapshot
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// crashConsistentExecutor takes the snapshots of a running instance as they
+// are, without putting PostgreSQL in backup mode nor fencing the instance.
+// The snapshotted PGDATA is restored like after a crash, replaying the WAL
+// files contained in the same volume
+type crashConsistentExecutor struct{}
+
+func newCrashConsistentExecutor() *crashConsistentExecutor {
+	return &crashConsistentExecutor{}
+}
+
+func (c *crashConsistentExecutor) prepare(
+	_ context.Context,
+	_ *apiv1.Cluster,
+	_ *apiv1.Backup,
+	_ *corev1.Pod,
+) (*ctrl.Result, error) {
+	return nil, nil
+}
+
+func (c *crashConsistentExecutor) finalize(
+	_ context.Context,
+	_ *apiv1.Cluster,
+	_ *apiv1.Backup,
+	_ *corev1.Pod,
+) (*ctrl.Result, error) {
+	return nil, nil
+}
+
+// ensureCrashConsistencyIsPossible checks that the snapshots of the passed PVCs,
+// that are not taken atomically, can be restored like after a crash
+func ensureCrashConsistencyIsPossible(pvcs []corev1.PersistentVolumeClaim) error {
+	if len(pvcs) != 1 {
+		return fmt.Errorf(
+			"crash-consistent snapshots require PGDATA, WALs and tablespaces on a single volume, found %d volumes",
+			len(pvcs))
+	}
+
+	return nil
+}
@@ -142,7 +142,11 @@ type executor interface {
 	) (*ctrl.Result, error)
 }
 
-func (se *Reconciler) newExecutor(online bool) executor {
+func (se *Reconciler) newExecutor(backup *apiv1.Backup, online bool) executor {
+	if backup.Spec.CrashConsistent {
+		return newCrashConsistentExecutor()
+	}
+
 	if online {
 		return newOnlineExecutor()
 	}
@@ -168,10 +172,17 @@ func (se *Reconciler) Reconcile(
 	}
 	volumeSnapshotConfig := backup.GetVolumeSnapshotConfiguration(*cluster.Spec.Backup.VolumeSnapshot)
 
-	exec := se.newExecutor(volumeSnapshotConfig.GetOnline())
+	if backup.Spec.CrashConsistent {
+		if err := ensureCrashConsistencyIsPossible(pvcs); err != nil {
+			return nil, err
+		}
+	}
+
+	exec := se.newExecutor(backup, volumeSnapshotConfig.GetOnline())
 
 	// Step 1: backup preparation.
 	// This will set PostgreSQL in backup mode for hot snapshots, or fence the Pods for cold snapshots.
+	// Nothing is done for crash-consistent snapshots.
 	if len(volumeSnapshots) == 0 {
 		if res, err := exec.prepare(ctx, cluster, backup, targetPod); res != nil || err != nil {
 			return res, err
@@ -237,7 +248,7 @@ func (se *Reconciler) finalizeSnapshotBackupStep(
 	}
 
 	backup.Status.SetAsFinalizing()
-	backup.Status.Online = ptr.To(volumeSnapshotConfig.GetOnline())
+	backup.Status.Online = ptr.To(volumeSnapshotConfig.GetOnline() && !backup.Spec.CrashConsistent)
 	snapshots, err := getBackupVolumeSnapshots(ctx, se.cli, backup.Namespace, backup.Name)
 	if err != nil {
 		return nil, err
@@ -269,6 +280,8 @@ func (se *Reconciler) completeSnapshotBackupStep(
 		return nil, err
 	}
 
+	// The restore size is reported once the snapshots are ready
+	backup.Status.Size = snapshots.getRestoreSize()
 	if err := annotateSnapshotsWithBackupData(ctx, se.cli, snapshots, &backup.Status); err != nil {
 		contextLogger.Error(err, "while enriching the snapshots's status")
 		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
@@ -152,6 +152,53 @@ var _ = Describe("Volumesnapshot reconciler", func() {
 		Expect(snapshotList.Items).NotTo(BeEmpty())
 	})
 
+	It("should not fence the target pod for crash-consistent snapshots", func(ctx SpecContext) {
+		backup.Spec.CrashConsistent = true
+		mockClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(backup, cluster, targetPod).
+			Build()
+
+		executor := NewReconcilerBuilder(mockClient, record.NewFakeRecorder(3)).
+			Build()
+
+		result, err := executor.Reconcile(ctx, cluster, backup, targetPod, pvcs[:1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+
+		var latestCluster apiv1.Cluster
+		err = mockClient.Get(ctx, types.NamespacedName{Name: clusterName, Namespace: namespace}, &latestCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		data, err := utils.GetFencedInstances(latestCluster.Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data.Len()).To(BeZero())
+
+		var snapshotList storagesnapshotv1.VolumeSnapshotList
+		err = mockClient.List(ctx, &snapshotList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshotList.Items).To(HaveLen(1))
+	})
+
+	It("should refuse crash-consistent snapshots of more than one volume", func(ctx SpecContext) {
+		backup.Spec.CrashConsistent = true
+		mockClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(backup, cluster, targetPod).
+			Build()
+
+		executor := NewReconcilerBuilder(mockClient, record.NewFakeRecorder(3)).
+			Build()
+
+		_, err := executor.Reconcile(ctx, cluster, backup, targetPod, pvcs)
+		Expect(err).To(HaveOccurred())
+
+		var snapshotList storagesnapshotv1.VolumeSnapshotList
+		err = mockClient.List(ctx, &snapshotList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshotList.Items).To(BeEmpty())
+	})
+
 	It("should not fence the target pod when there are existing volumesnapshots", func(ctx SpecContext) {
 		snapshots := storagesnapshotv1.VolumeSnapshotList{
 			Items: []storagesnapshotv1.VolumeSnapshot{
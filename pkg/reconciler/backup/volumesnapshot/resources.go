@@ -106,6 +106,24 @@ func (s slice) getControldata() (string, error) {
 	return "", fmt.Errorf("could not retrieve pg_controldata from any snapshot")
 }
 
+// getRestoreSize returns the total restore size, in bytes, of the snapshots,
+// or nil if the storage provider didn't report it for any of them
+func (s slice) getRestoreSize() *int64 {
+	var result *int64
+	for _, volumeSnapshot := range s {
+		if volumeSnapshot.Status == nil || volumeSnapshot.Status.RestoreSize == nil {
+			continue
+		}
+
+		total := volumeSnapshot.Status.RestoreSize.Value()
+		if result != nil {
+			total += *result
+		}
+		result = &total
+	}
+	return result
+}
+
 // getBackupVolumeSnapshots extracts the list of volume snapshots related
 // to a backup name
 func getBackupVolumeSnapshots(
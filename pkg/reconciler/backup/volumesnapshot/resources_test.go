@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -193,3 +194,22 @@ var _ = Describe("parseVolumeSnapshotInfo", func() {
 		})
 	})
 })
+
+var _ = Describe("getRestoreSize", func() {
+	It("sums the restore size of the snapshots", func() {
+		snapshots := slice{
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{RestoreSize: ptr.To(resource.MustParse("10Gi"))}},
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{RestoreSize: ptr.To(resource.MustParse("2Gi"))}},
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{}},
+		}
+		Expect(snapshots.getRestoreSize()).To(HaveValue(BeEquivalentTo(12 * 1024 * 1024 * 1024)))
+	})
+
+	It("returns nil when no restore size has been reported", func() {
+		snapshots := slice{
+			{},
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{}},
+		}
+		Expect(snapshots.getRestoreSize()).To(BeNil())
+	})
+})
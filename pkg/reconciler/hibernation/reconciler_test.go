@@ -179,6 +179,33 @@ var _ = Describe("Reconcile resources", func() {
 		Expect(Reconcile(ctx, mock, &cluster, pods)).ToNot(BeNil())
 		Expect(mock.deletedPods).To(ConsistOf("cluster-example-1"))
 	})
+
+	It("keeps re-queueing when every pod has already been deleted", func(ctx SpecContext) {
+		mock := &clientMock{}
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.HibernationAnnotationName: HibernationOn,
+				},
+				Name: "cluster-example",
+			},
+			Status: apiv1.ClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   HibernationConditionType,
+						Status: metav1.ConditionTrue,
+						Reason: HibernationConditionReasonDeletingPods,
+					},
+				},
+			},
+		}
+
+		result, err := Reconcile(ctx, mock, &cluster, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		Expect(mock.deletedPods).To(BeEmpty())
+	})
 })
 
 func fakePod(name string, role string) corev1.Pod {
@@ -30,6 +30,53 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// ReconcileRoleLabels ensures that the role labels of the instances match
+// the current primary of the cluster. Pods losing the primary role are
+// patched before the new primary gets its label, so that the -rw service
+// never selects more than one instance.
+func ReconcileRoleLabels(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	instances corev1.PodList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	// The current primary is processed last
+	indexes := make([]int, 0, len(instances.Items))
+	primaryIdx := -1
+	for idx := range instances.Items {
+		if instances.Items[idx].Name == cluster.Status.CurrentPrimary {
+			primaryIdx = idx
+			continue
+		}
+		indexes = append(indexes, idx)
+	}
+	if primaryIdx >= 0 {
+		indexes = append(indexes, primaryIdx)
+	}
+
+	for _, idx := range indexes {
+		origInstance := instances.Items[idx].DeepCopy()
+		instance := &instances.Items[idx]
+
+		if !updateRoleLabels(ctx, cluster, instance) {
+			continue
+		}
+
+		if err := cli.Patch(ctx, instance, client.MergeFrom(origInstance)); err != nil {
+			contextLogger.Error(
+				err,
+				"while patching instance role labels",
+				"instanceName", origInstance.Name,
+			)
+			return fmt.Errorf("cannot update role labels on pods: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // ReconcileMetadata ensures that the instance metadata is kept up to date
 func ReconcileMetadata(
 	ctx context.Context,
@@ -39,15 +86,17 @@ func ReconcileMetadata(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
+	// Update the labels for the -rw service to work correctly
+	if err := ReconcileRoleLabels(ctx, cli, cluster, instances); err != nil {
+		return err
+	}
+
 	for idx := range instances.Items {
 		origInstance := instances.Items[idx].DeepCopy()
 		instance := &instances.Items[idx]
 
-		// Update the labels for the -rw service to work correctly
-		modified := updateRoleLabels(ctx, cluster, instance)
-
 		// updated any labels that are coming from the operator
-		modified = updateOperatorLabels(ctx, instance) || modified
+		modified := updateOperatorLabels(ctx, instance)
 
 		// Update any modified/new labels coming from the cluster resource
 		modified = updateClusterLabels(ctx, cluster, instance) || modified
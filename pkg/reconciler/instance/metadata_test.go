@@ -21,7 +21,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
@@ -570,3 +572,96 @@ var _ = Describe("metadata update functions", func() {
 		})
 	})
 })
+
+var _ = Describe("role labels reconciliation test", func() {
+	Context("ReconcileRoleLabels", func() {
+		It("Should demote the former primary before promoting the new one", func() {
+			instanceList := corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Labels: map[string]string{}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Labels: map[string]string{}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Labels: map[string]string{}}},
+				},
+			}
+			utils.SetInstanceRole(instanceList.Items[0].ObjectMeta, specs.ClusterRoleLabelReplica)
+			utils.SetInstanceRole(instanceList.Items[1].ObjectMeta, specs.ClusterRoleLabelReplica)
+			utils.SetInstanceRole(instanceList.Items[2].ObjectMeta, specs.ClusterRoleLabelPrimary)
+
+			cluster := &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "pod1",
+				},
+			}
+
+			var patched []string
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(&instanceList.Items[0], &instanceList.Items[1], &instanceList.Items[2]).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(
+						ctx context.Context,
+						client client.WithWatch,
+						obj client.Object,
+						patch client.Patch,
+						opts ...client.PatchOption,
+					) error {
+						patched = append(patched, obj.GetName())
+						return client.Patch(ctx, obj, patch, opts...)
+					},
+				}).
+				Build()
+
+			err := ReconcileRoleLabels(context.Background(), cli, cluster, instanceList)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(patched).To(Equal([]string{"pod3", "pod1"}))
+
+			var updatedInstanceList corev1.PodList
+			err = cli.List(context.Background(), &updatedInstanceList)
+			Expect(err).ToNot(HaveOccurred())
+			for _, pod := range updatedInstanceList.Items {
+				expectedRole := specs.ClusterRoleLabelReplica
+				if pod.Name == "pod1" {
+					expectedRole = specs.ClusterRoleLabelPrimary
+				}
+				Expect(pod.Labels[utils.ClusterRoleLabelName]).To(Equal(expectedRole))
+				Expect(pod.Labels[utils.ClusterInstanceRoleLabelName]).To(Equal(expectedRole))
+			}
+		})
+
+		It("Should not patch the pods when the role labels are correct", func() {
+			instanceList := corev1.PodList{
+				Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Labels: map[string]string{}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Labels: map[string]string{}}},
+				},
+			}
+			utils.SetInstanceRole(instanceList.Items[0].ObjectMeta, specs.ClusterRoleLabelPrimary)
+			utils.SetInstanceRole(instanceList.Items[1].ObjectMeta, specs.ClusterRoleLabelReplica)
+
+			cluster := &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "pod1",
+				},
+			}
+
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(
+						_ context.Context,
+						_ client.WithWatch,
+						obj client.Object,
+						_ client.Patch,
+						_ ...client.PatchOption,
+					) error {
+						Fail("unexpected patch of pod " + obj.GetName())
+						return nil
+					},
+				}).
+				Build()
+
+			err := ReconcileRoleLabels(context.Background(), cli, cluster, instanceList)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
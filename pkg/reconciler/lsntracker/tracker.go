@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsntracker
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// Position is a position in the WAL stream, on a given timeline
+type Position struct {
+	LSN        postgres.LSN
+	TimelineID int
+}
+
+type primaryPosition struct {
+	podName  string
+	position Position
+}
+
+// Tracker stores, for each cluster, the last LSN and timeline reported by
+// its primary instance. The information is kept in memory only, as it changes at every
+// reconciliation loop, and is lost when the operator is restarted.
+// A nil Tracker doesn't track anything.
+type Tracker struct {
+	lock     sync.Mutex
+	clusters map[types.NamespacedName]primaryPosition
+}
+
+// NewTracker creates a new empty Tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		clusters: make(map[types.NamespacedName]primaryPosition),
+	}
+}
+
+// Update records the position reported by the passed primary pod of the
+// cluster. A position older than the one already known for the same pod,
+// on the same timeline, is ignored.
+func (t *Tracker) Update(cluster types.NamespacedName, podName string, position Position) {
+	if t == nil || podName == "" || position.TimelineID == 0 {
+		return
+	}
+
+	if _, err := position.LSN.Parse(); err != nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if known, ok := t.clusters[cluster]; ok && known.podName == podName {
+		if position.TimelineID < known.position.TimelineID ||
+			position.TimelineID == known.position.TimelineID && position.LSN.Less(known.position.LSN) {
+			return
+		}
+	}
+
+	t.clusters[cluster] = primaryPosition{podName: podName, position: position}
+}
+
+// Get returns the last position reported by the passed primary pod of the
+// cluster, if known
+func (t *Tracker) Get(cluster types.NamespacedName, podName string) (Position, bool) {
+	if t == nil {
+		return Position{}, false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	known, ok := t.clusters[cluster]
+	if !ok || known.podName != podName {
+		return Position{}, false
+	}
+
+	return known.position, true
+}
+
+// Forget removes the information stored for the passed cluster
+func (t *Tracker) Forget(cluster types.NamespacedName) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.clusters, cluster)
+}
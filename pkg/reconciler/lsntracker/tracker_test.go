@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsntracker
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LSN tracker", func() {
+	cluster := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+
+	position := func(lsn postgres.LSN, timelineID int) Position {
+		return Position{LSN: lsn, TimelineID: timelineID}
+	}
+
+	It("doesn't track anything when the tracker is nil", func() {
+		var tracker *Tracker
+		tracker.Update(cluster, "cluster-example-1", position("0/3000060", 1))
+		_, ok := tracker.Get(cluster, "cluster-example-1")
+		Expect(ok).To(BeFalse())
+		tracker.Forget(cluster)
+	})
+
+	It("returns the last position reported by the primary", func() {
+		tracker := NewTracker()
+		tracker.Update(cluster, "cluster-example-1", position("0/3000060", 1))
+		tracker.Update(cluster, "cluster-example-1", position("0/4000000", 1))
+
+		known, ok := tracker.Get(cluster, "cluster-example-1")
+		Expect(ok).To(BeTrue())
+		Expect(known).To(Equal(position("0/4000000", 1)))
+	})
+
+	It("ignores older and invalid positions", func() {
+		tracker := NewTracker()
+		tracker.Update(cluster, "cluster-example-1", position("0/4000000", 2))
+		tracker.Update(cluster, "cluster-example-1", position("0/3000060", 2))
+		tracker.Update(cluster, "cluster-example-1", position("0/5000000", 1))
+		tracker.Update(cluster, "cluster-example-1", position("0/5000000", 0))
+		tracker.Update(cluster, "cluster-example-1", position("", 2))
+
+		known, ok := tracker.Get(cluster, "cluster-example-1")
+		Expect(ok).To(BeTrue())
+		Expect(known).To(Equal(position("0/4000000", 2)))
+	})
+
+	It("accepts a lower LSN on a newer timeline", func() {
+		tracker := NewTracker()
+		tracker.Update(cluster, "cluster-example-1", position("0/4000000", 1))
+		tracker.Update(cluster, "cluster-example-1", position("0/3000060", 2))
+
+		known, ok := tracker.Get(cluster, "cluster-example-1")
+		Expect(ok).To(BeTrue())
+		Expect(known).To(Equal(position("0/3000060", 2)))
+	})
+
+	It("only returns the position of the requested primary", func() {
+		tracker := NewTracker()
+		tracker.Update(cluster, "cluster-example-1", position("0/4000000", 1))
+		_, ok := tracker.Get(cluster, "cluster-example-2")
+		Expect(ok).To(BeFalse())
+
+		By("replacing the information after a change of primary", func() {
+			tracker.Update(cluster, "cluster-example-2", position("0/3000060", 2))
+			known, ok := tracker.Get(cluster, "cluster-example-2")
+			Expect(ok).To(BeTrue())
+			Expect(known).To(Equal(position("0/3000060", 2)))
+		})
+	})
+
+	It("forgets a cluster", func() {
+		tracker := NewTracker()
+		tracker.Update(cluster, "cluster-example-1", position("0/4000000", 1))
+		tracker.Forget(cluster)
+		_, ok := tracker.Get(cluster, "cluster-example-1")
+		Expect(ok).To(BeFalse())
+	})
+})
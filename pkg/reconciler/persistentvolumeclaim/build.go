@@ -81,6 +81,7 @@ func Build(
 	if pvc.Spec.Resources.Requests.Storage().IsZero() {
 		return nil, ErrorInvalidSize
 	}
+	utils.SetSpecHash(&pvc.ObjectMeta, pvc.Spec)
 
 	return pvc, nil
 }
@@ -149,4 +149,26 @@ var _ = Describe("PVC Creation", func() {
 		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
 		Expect(pvc.Labels[utils.TablespaceNameLabelName]).To(Equal(tbsName))
 	})
+
+	It("builds all the PVCs of an instance", func() {
+		pvcs, err := BuildInstancePVCs(
+			&apiv1.Cluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "thecluster",
+				},
+				Spec: apiv1.ClusterSpec{
+					StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+					WalStorage:           &apiv1.StorageConfiguration{Size: "2Gi"},
+				},
+			},
+			nil,
+			2,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvcs).To(HaveLen(2))
+		Expect(pvcs[0].Name).To(Equal("thecluster-2"))
+		Expect(pvcs[0].Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		Expect(pvcs[1].Name).To(Equal("thecluster-2-wal"))
+		Expect(pvcs[1].Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+	})
 })
@@ -68,6 +68,8 @@ var _ = Describe("testing create function", func() {
 			err = cli.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: "default"}, &expectedPVC)
 			Expect(err).ToNot(HaveOccurred())
 
+			Expect(expectedPVC.Annotations).To(HaveKey(utils.CNPGHashAnnotationName))
+			delete(expectedPVC.Annotations, utils.CNPGHashAnnotationName)
 			Expect(expectedPVC.Annotations).To(Equal(map[string]string{
 				utils.ClusterSerialAnnotationName:   "1",
 				utils.OperatorVersionAnnotationName: versions.Version,
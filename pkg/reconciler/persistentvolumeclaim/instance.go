@@ -18,6 +18,7 @@ package persistentvolumeclaim
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"golang.org/x/exp/slices"
@@ -41,6 +42,36 @@ func CreateInstancePVCs(
 	return err
 }
 
+// BuildInstancePVCs builds the expected pvcs for the instance, without creating them
+func BuildInstancePVCs(
+	cluster *apiv1.Cluster,
+	source *StorageSource,
+	serial int,
+) ([]*corev1.PersistentVolumeClaim, error) {
+	instanceName := specs.GetInstanceName(cluster.Name, serial)
+	expectedPVCs := getExpectedPVCsFromCluster(cluster, instanceName)
+	result := make([]*corev1.PersistentVolumeClaim, 0, len(expectedPVCs))
+	for _, expectedPVC := range expectedPVCs {
+		conf, err := expectedPVC.calculator.GetStorageConfiguration(cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		pvcSource, err := expectedPVC.calculator.GetSource(source)
+		if err != nil {
+			return nil, err
+		}
+
+		pvc, err := Build(cluster, expectedPVC.toCreateConfiguration(serial, conf, pvcSource))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a PVC spec for node with serial %v: %w", serial, err)
+		}
+		result = append(result, pvc)
+	}
+
+	return result, nil
+}
+
 // reconcileMultipleInstancesMissingPVCs evaluate multiple instances that may miss some PVCs.
 // It will work on the first instance where the PVCs should be reconciled, leaving the next
 // ones for the other reconciliation loops.
@@ -29,6 +29,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
 type metadataReconciler struct {
@@ -58,6 +59,7 @@ func (m metadataReconciler) reconcile(
 
 		patch := client.MergeFrom(pvc.DeepCopy())
 		m.update(pvc)
+		utils.SetOperatorVersion(&pvc.ObjectMeta, versions.Version)
 
 		contextLogger.Info("Updating pvc metadata", "pvc", pvc.Name, "reconciler", m.name)
 		if err := c.Patch(ctx, pvc, patch); err != nil {
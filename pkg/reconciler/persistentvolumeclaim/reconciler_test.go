@@ -21,9 +21,12 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -33,6 +36,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -130,6 +134,7 @@ var _ = Describe("Reconcile Resources", func() {
 		_, err := Reconcile(
 			context.Background(),
 			cli,
+			record.NewFakeRecorder(10),
 			cluster,
 			pods.Items,
 			pvcs.Items,
@@ -157,6 +162,7 @@ var _ = Describe("Reconcile resource requests", func() {
 		err := reconcileResourceRequests(
 			context.Background(),
 			cli,
+			record.NewFakeRecorder(10),
 			cluster,
 			[]corev1.PersistentVolumeClaim{},
 		)
@@ -174,6 +180,7 @@ var _ = Describe("Reconcile resource requests", func() {
 		err := reconcileResourceRequests(
 			context.Background(),
 			cli,
+			record.NewFakeRecorder(10),
 			cluster,
 			[]corev1.PersistentVolumeClaim{},
 		)
@@ -252,10 +259,11 @@ var _ = Describe("PVC reconciliation", func() {
 		)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(pvcs.Items[2].Annotations).To(BeEquivalentTo(map[string]string{
-			utils.PVCStatusAnnotationName:     "ready",
-			utils.ClusterSerialAnnotationName: "3-wal",
-			"annotation1":                     "value",
-			"annotation2":                     "value",
+			utils.PVCStatusAnnotationName:       "ready",
+			utils.ClusterSerialAnnotationName:   "3-wal",
+			utils.OperatorVersionAnnotationName: versions.Version,
+			"annotation1":                       "value",
+			"annotation2":                       "value",
 		}))
 	})
 
@@ -443,11 +451,12 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 		cli = fake.NewClientBuilder().
 			WithScheme(scheme.BuildWithAllKnownScheme()).
 			WithObjects(cluster, &pvc, &pvc2).
+			WithStatusSubresource(cluster).
 			Build()
 	})
 
 	It("fail if we dont' have the proper role", func() {
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -460,7 +469,7 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 			utils.PvcRoleLabelName: string(utils.PVCRolePgData),
 		}
 
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -472,7 +481,7 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 		cluster.Spec.StorageConfiguration = apiv1.StorageConfiguration{}
 
 		// If we don't have a proper storage configuration we should also fail
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -486,7 +495,7 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 		}
 		cluster.Spec.StorageConfiguration.Size = "1Gi"
 
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -494,6 +503,134 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should not resize a PVC whose storage class doesn't allow volume expansion", func() {
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "fixed"},
+			AllowVolumeExpansion: ptr.To(false),
+		}
+		Expect(cli.Create(context.Background(), storageClass)).To(Succeed())
+
+		pvc.Labels = map[string]string{
+			utils.PvcRoleLabelName: string(utils.PVCRolePgData),
+		}
+		pvc.Spec.StorageClassName = ptr.To("fixed")
+		cluster.Spec.StorageConfiguration.Size = "2Gi"
+
+		expandable, err := reconcilePVCQuantity(
+			context.Background(),
+			cli,
+			cluster,
+			&pvc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expandable).To(BeFalse())
+
+		var storedPVC corev1.PersistentVolumeClaim
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(&pvc), &storedPVC)).To(Succeed())
+		Expect(storedPVC.Spec.Resources.Requests).ToNot(HaveKey(corev1.ResourceStorage))
+	})
+
+	It("should report the PVCs whose storage class doesn't allow volume expansion", func() {
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "fixed"},
+			AllowVolumeExpansion: ptr.To(false),
+		}
+		Expect(cli.Create(context.Background(), storageClass)).To(Succeed())
+
+		pvc.Labels = map[string]string{
+			utils.PvcRoleLabelName: string(utils.PVCRolePgData),
+		}
+		pvc.Spec.StorageClassName = ptr.To("fixed")
+		cluster.Spec.StorageConfiguration.Size = "2Gi"
+		Expect(cli.Update(context.Background(), cluster)).To(Succeed())
+		recorder := record.NewFakeRecorder(10)
+
+		By("adding the condition and emitting an event when the PVC can't be expanded", func() {
+			err := reconcileResourceRequests(
+				context.Background(),
+				cli,
+				recorder,
+				cluster,
+				[]corev1.PersistentVolumeClaim{pvc})
+			Expect(err).ToNot(HaveOccurred())
+
+			var storedCluster apiv1.Cluster
+			Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+			condition := meta.FindStatusCondition(storedCluster.Status.Conditions,
+				string(apiv1.ConditionVolumesExpandable))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.VolumeExpansionNotAllowed)))
+			Expect(condition.Message).To(ContainSubstring(pvc.Name))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning VolumeExpansionNotAllowed")))
+		})
+
+		By("not emitting the event again while the PVC can't be expanded", func() {
+			err := reconcileResourceRequests(
+				context.Background(),
+				cli,
+				recorder,
+				cluster,
+				[]corev1.PersistentVolumeClaim{pvc})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Events).ToNot(Receive())
+		})
+
+		By("marking the condition as resolved once the PVC has the requested size", func() {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("2Gi"),
+			}
+			err := reconcileResourceRequests(
+				context.Background(),
+				cli,
+				recorder,
+				cluster,
+				[]corev1.PersistentVolumeClaim{pvc})
+			Expect(err).ToNot(HaveOccurred())
+
+			var storedCluster apiv1.Cluster
+			Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(storedCluster.Status.Conditions,
+				string(apiv1.ConditionVolumesExpandable))).To(BeTrue())
+		})
+	})
+
+	It("should resize a PVC whose storage class allows volume expansion", func() {
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+			AllowVolumeExpansion: ptr.To(true),
+		}
+		Expect(cli.Create(context.Background(), storageClass)).To(Succeed())
+
+		pvc.Labels = map[string]string{
+			utils.PvcRoleLabelName: string(utils.PVCRolePgData),
+		}
+		pvc.Spec.StorageClassName = ptr.To("expandable")
+		cluster.Spec.StorageConfiguration.Size = "2Gi"
+
+		_, err := reconcilePVCQuantity(
+			context.Background(),
+			cli,
+			cluster,
+			&pvc)
+		Expect(err).ToNot(HaveOccurred())
+
+		var storedPVC corev1.PersistentVolumeClaim
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(&pvc), &storedPVC)).To(Succeed())
+		Expect(storedPVC.Spec.Resources.Requests).To(HaveKeyWithValue(
+			corev1.ResourceStorage, resource.MustParse("2Gi")))
+
+		expectedPVC, err := Build(cluster, &CreateConfiguration{
+			NodeSerial: 1,
+			Calculator: NewPgDataCalculator(),
+			Storage:    cluster.Spec.StorageConfiguration,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(storedPVC.Annotations).To(HaveKeyWithValue(
+			utils.CNPGHashAnnotationName, expectedPVC.Annotations[utils.CNPGHashAnnotationName]))
+		Expect(storedPVC.Annotations).To(HaveKeyWithValue(
+			utils.OperatorVersionAnnotationName, versions.Version))
+	})
+
 	It("It should succeed increasing size of tablespaces", func() {
 		// Now we set the proper storage configuration
 		cluster.Spec.Tablespaces = []apiv1.TablespaceConfiguration{
@@ -505,7 +642,7 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 			},
 		}
 
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -524,7 +661,7 @@ var _ = Describe("Reconcile PVC Quantity", func() {
 			},
 		}
 
-		err := reconcilePVCQuantity(
+		_, err := reconcilePVCQuantity(
 			context.Background(),
 			cli,
 			cluster,
@@ -18,42 +18,95 @@ package persistentvolumeclaim
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
-// reconcileResourceRequests align the resource requests
+// reconcileResourceRequests align the resource requests, reporting the
+// PVCs whose storage class doesn't allow them to be expanded
 func reconcileResourceRequests(
 	ctx context.Context,
 	c client.Client,
+	recorder record.EventRecorder,
 	cluster *apiv1.Cluster,
 	pvcs []corev1.PersistentVolumeClaim,
 ) error {
-	if !cluster.ShouldResizeInUseVolumes() {
-		return nil
+	var notExpandable []string
+	if cluster.ShouldResizeInUseVolumes() {
+		for idx := range pvcs {
+			expandable, err := reconcilePVCQuantity(ctx, c, cluster, &pvcs[idx])
+			if err != nil {
+				return err
+			}
+			if !expandable {
+				notExpandable = append(notExpandable, pvcs[idx].Name)
+			}
+		}
 	}
 
-	for idx := range pvcs {
-		if err := reconcilePVCQuantity(ctx, c, cluster, &pvcs[idx]); err != nil {
-			return err
+	return updateVolumesExpandability(ctx, c, recorder, cluster, notExpandable)
+}
+
+// updateVolumesExpandability sets the VolumesExpandable condition depending
+// on the PVCs which cannot be expanded, emitting a Warning event when they
+// are first detected. The condition is added only after a PVC which cannot
+// be expanded has been detected
+func updateVolumesExpandability(
+	ctx context.Context,
+	c client.Client,
+	recorder record.EventRecorder,
+	cluster *apiv1.Cluster,
+	notExpandable []string,
+) error {
+	if len(notExpandable) > 0 {
+		message := fmt.Sprintf("The storage class doesn't allow expanding the PVCs: %s",
+			strings.Join(notExpandable, ", "))
+		if !meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionVolumesExpandable)) {
+			recorder.Event(cluster, "Warning", string(apiv1.VolumeExpansionNotAllowed), message)
 		}
+		return conditions.Patch(ctx, c, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionVolumesExpandable),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.VolumeExpansionNotAllowed),
+			Message: message,
+		})
 	}
 
-	return nil
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionVolumesExpandable)) == nil {
+		return nil
+	}
+	return conditions.Patch(ctx, c, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionVolumesExpandable),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.VolumeExpansionAllowed),
+		Message: "No PVC is waiting for an expansion refused by its storage class",
+	})
 }
 
+// reconcilePVCQuantity aligns the size requested by the PVC with the
+// storage configuration of the cluster. It returns false when the PVC
+// needs to be expanded but its storage class doesn't allow it
 func reconcilePVCQuantity(
 	ctx context.Context,
 	c client.Client,
 	cluster *apiv1.Cluster,
 	pvc *corev1.PersistentVolumeClaim,
-) error {
+) (bool, error) {
 	contextLogger := log.FromContext(ctx)
 	pvcRole, err := GetExpectedObjectCalculator(pvc.GetLabels())
 	if err != nil {
@@ -61,7 +114,7 @@ func reconcilePVCQuantity(
 			"encountered an error while trying to get pvc role from label",
 			"role", pvc.Labels[utils.PvcRoleLabelName],
 		)
-		return err
+		return false, err
 	}
 
 	storageConfiguration, err := pvcRole.GetStorageConfiguration(cluster)
@@ -71,23 +124,35 @@ func reconcilePVCQuantity(
 			"role", pvc.Labels[utils.PvcRoleLabelName],
 			"pvcName", pvc.Name,
 		)
-		return err
+		return false, err
 	}
 
 	parsedSize := storageConfiguration.GetSizeOrNil()
 	if parsedSize == nil {
-		return ErrorInvalidSize
+		return false, ErrorInvalidSize
 	}
 	currentSize := pvc.Spec.Resources.Requests["storage"]
 
 	switch currentSize.AsDec().Cmp(parsedSize.AsDec()) {
 	case 0:
-		return nil
+		return true, nil
 	case 1:
 		contextLogger.Warning("cannot decrease storage requirement",
 			"from", currentSize, "to", parsedSize,
 			"pvcName", pvc.Name)
-		return nil
+		return true, nil
+	}
+
+	expansionAllowed, err := isVolumeExpansionAllowed(ctx, c, pvc)
+	if err != nil {
+		return false, err
+	}
+	if !expansionAllowed {
+		contextLogger.Warning("the storage class doesn't allow volume expansion, cannot increase storage requirement",
+			"from", currentSize, "to", parsedSize,
+			"storageClass", pvc.Spec.StorageClassName,
+			"pvcName", pvc.Name)
+		return false, nil
 	}
 
 	oldPVC := pvc.DeepCopy()
@@ -96,14 +161,51 @@ func reconcilePVCQuantity(
 		WithRequests(corev1.ResourceList{"storage": *parsedSize}).
 		Build()
 
+	// The PVC is now aligned with the storage configuration, and so
+	// must be the hash of its specification
+	expectedPVC, err := Build(cluster, &CreateConfiguration{
+		Calculator: pvcRole,
+		Storage:    storageConfiguration,
+		Source:     pvc.Spec.DataSource,
+	})
+	if err != nil {
+		return false, err
+	}
+	utils.SetSpecHash(&pvc.ObjectMeta, expectedPVC.Spec)
+	utils.SetOperatorVersion(&pvc.ObjectMeta, versions.Version)
+
 	if err := c.Patch(ctx, pvc, client.MergeFrom(oldPVC)); err != nil {
 		contextLogger.Error(err, "error while changing PVC storage requirement",
 			"pvcName", pvc.Name,
 			"pvc", pvc,
 			"requests", pvc.Spec.Resources.Requests,
 			"oldRequests", oldPVC.Spec.Resources.Requests)
-		return err
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isVolumeExpansionAllowed checks if the storage class of the PVC allows
+// it to be expanded. When the storage class is not set or can't be found,
+// the decision is left to the Kubernetes API server
+func isVolumeExpansionAllowed(
+	ctx context.Context,
+	c client.Client,
+	pvc *corev1.PersistentVolumeClaim,
+) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return true, nil
+	}
+
+	var storageClass storagev1.StorageClass
+	err := c.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, &storageClass)
+	if apierrs.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
 }
@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -73,6 +74,19 @@ func isResizing(pvc corev1.PersistentVolumeClaim) bool {
 	return false
 }
 
+// getFileSystemResizePendingTime returns the time since when the file system
+// of the PVC is waiting to be expanded, or nil if no expansion is pending
+func getFileSystemResizePendingTime(pvc corev1.PersistentVolumeClaim) *metav1.Time {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending &&
+			condition.Status == corev1.ConditionTrue {
+			return &condition.LastTransitionTime
+		}
+	}
+
+	return nil
+}
+
 // BelongToInstance returns a boolean indicating if that given PVC belongs to an instance
 func BelongToInstance(cluster *apiv1.Cluster, instanceName, pvcName string) bool {
 	expectedPVCs := getExpectedInstancePVCNamesFromCluster(cluster, instanceName)
@@ -18,6 +18,7 @@ package persistentvolumeclaim
 
 import (
 	"context"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -79,6 +80,51 @@ var _ = Describe("PVC detection", func() {
 		}))
 		Expect(cluster.Status.UnusablePVC).Should(BeEmpty())
 	})
+
+	It("will list the PVCs whose file system resize requires a restart of their Pod", func() {
+		clusterName := "myCluster"
+		makeFileSystemResizePendingPVC := func(serial string, since time.Time) corev1.PersistentVolumeClaim {
+			pvc := makePVC(clusterName, serial, NewPgDataCalculator(), false)
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+				{
+					Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(since),
+				},
+			}
+			return pvc
+		}
+		pvcs := []corev1.PersistentVolumeClaim{
+			makeFileSystemResizePendingPVC("1", time.Now().Add(-time.Minute)),
+			makeFileSystemResizePendingPVC("2", time.Now().Add(-time.Hour)),
+			makeFileSystemResizePendingPVC("3", time.Now().Add(-time.Hour)),
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: clusterName,
+			},
+		}
+		EnrichStatus(
+			context.TODO(),
+			cluster,
+			[]corev1.Pod{
+				makePod(clusterName, "1", specs.ClusterRoleLabelPrimary),
+				makePod(clusterName, "2", specs.ClusterRoleLabelReplica),
+			},
+			nil,
+			pvcs,
+		)
+
+		Expect(cluster.Status.ResizingPVC).Should(Equal([]string{
+			clusterName + "-1",
+		}))
+		Expect(cluster.Status.FileSystemResizePendingPVC).Should(Equal([]string{
+			clusterName + "-2",
+		}))
+		Expect(cluster.Status.DanglingPVC).Should(Equal([]string{
+			clusterName + "-3",
+		}))
+	})
 })
 
 var _ = Describe("PVCs used by instance", func() {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -49,6 +50,11 @@ const (
 // user is not valid and can't be specified in a PVC declaration
 var ErrorInvalidSize = fmt.Errorf("invalid storage size")
 
+// fileSystemResizeTimeout is the time given to the kubelet to expand the
+// file system of a PVC in use by a Pod, before restarting the Pod to
+// complete the resize
+const fileSystemResizeTimeout = 5 * time.Minute
+
 type status string
 
 const (
@@ -63,6 +69,10 @@ const (
 	// INFO: https://kubernetes.io/blog/2018/07/12/resizing-persistent-volumes-using-kubernetes/
 	resizing status = "resizing"
 
+	// List of PVCs whose file system was not expanded while in use by the Pod.
+	// Requires a pod restart.
+	fileSystemResizePending status = "fileSystemResizePending"
+
 	// List of PVCs that are dangling (they don't have a corresponding Job nor a corresponding Pod)
 	dangling status = "dangling"
 
@@ -144,6 +154,7 @@ func EnrichStatus(
 	cluster.Status.PVCCount = int32(len(managedPVCs))
 	cluster.Status.InitializingPVC = result.getSorted(initializing)
 	cluster.Status.ResizingPVC = result.getSorted(resizing)
+	cluster.Status.FileSystemResizePendingPVC = result.getSorted(fileSystemResizePending)
 	cluster.Status.DanglingPVC = result.getSorted(dangling)
 	cluster.Status.HealthyPVC = result.getSorted(healthy)
 	cluster.Status.UnusablePVC = result.getSorted(unusable)
@@ -178,6 +189,14 @@ func classifyPVC(
 
 	// PVC has a corresponding Pod
 	if hasPod(pvc, podList) {
+		// The kubelet expands the file system of the PVC while the Pod is
+		// running, unless the storage doesn't support online expansion
+		if pendingSince := getFileSystemResizePendingTime(pvc); pendingSince != nil {
+			if time.Since(pendingSince.Time) < fileSystemResizeTimeout {
+				return resizing
+			}
+			return fileSystemResizePending
+		}
 		return healthy
 	}
 
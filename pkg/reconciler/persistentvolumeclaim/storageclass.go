@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetInstancesWithOutdatedStorageClass gets the sorted names of the instances
+// having at least one PVC using a storage class different from the one
+// requested in the cluster specification for its role. PVCs whose storage
+// class is not explicitly set in the specification are never outdated
+func GetInstancesWithOutdatedStorageClass(
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+) []string {
+	var result []string
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		instanceName := pvc.Labels[utils.InstanceNameLabelName]
+		if instanceName == "" || slices.Contains(result, instanceName) {
+			continue
+		}
+
+		if isStorageClassOutdated(cluster, pvc) {
+			result = append(result, instanceName)
+		}
+	}
+	slices.Sort(result)
+
+	return result
+}
+
+// isStorageClassOutdated checks if the PVC is using a storage class different
+// from the one requested in the cluster specification
+func isStorageClassOutdated(cluster *apiv1.Cluster, pvc *corev1.PersistentVolumeClaim) bool {
+	if utils.PVCRole(pvc.Labels[utils.PvcRoleLabelName]) == utils.PVCRolePgWal && cluster.Spec.WalStorage == nil {
+		return false
+	}
+
+	calculator, err := GetExpectedObjectCalculator(pvc.Labels)
+	if err != nil {
+		return false
+	}
+
+	configuration, err := calculator.GetStorageConfiguration(cluster)
+	if err != nil || configuration.StorageClass == nil {
+		return false
+	}
+
+	return pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != *configuration.StorageClass
+}
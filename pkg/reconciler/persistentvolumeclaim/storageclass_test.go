@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Storage class migration", func() {
+	makePVCWithStorageClass := func(suffix string, meta Meta, storageClass *string) corev1.PersistentVolumeClaim {
+		pvc := makePVC("cluster-example", suffix, meta, false)
+		pvc.Spec.StorageClassName = storageClass
+		return pvc
+	}
+
+	It("never reports the instances when the storage class is not requested", func() {
+		cluster := &apiv1.Cluster{}
+		pvcs := []corev1.PersistentVolumeClaim{
+			makePVCWithStorageClass("1", NewPgDataCalculator(), ptr.To("old")),
+			makePVCWithStorageClass("2", NewPgDataCalculator(), nil),
+		}
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, pvcs)).To(BeEmpty())
+	})
+
+	It("reports the instances with a PVC using a different storage class", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{StorageClass: ptr.To("new")},
+				WalStorage:           &apiv1.StorageConfiguration{StorageClass: ptr.To("new-wal")},
+			},
+		}
+		pvcs := []corev1.PersistentVolumeClaim{
+			makePVCWithStorageClass("1", NewPgDataCalculator(), ptr.To("new")),
+			makePVCWithStorageClass("1-wal", NewPgWalCalculator(), ptr.To("new-wal")),
+			makePVCWithStorageClass("3", NewPgDataCalculator(), ptr.To("new")),
+			makePVCWithStorageClass("3-wal", NewPgWalCalculator(), ptr.To("old")),
+			makePVCWithStorageClass("2", NewPgDataCalculator(), nil),
+			makePVCWithStorageClass("2-wal", NewPgWalCalculator(), ptr.To("old")),
+		}
+		// the WAL PVCs are labelled with the name of their own instance
+		pvcs[1].Labels = NewPgWalCalculator().GetLabels("cluster-example-1")
+		pvcs[3].Labels = NewPgWalCalculator().GetLabels("cluster-example-3")
+		pvcs[5].Labels = NewPgWalCalculator().GetLabels("cluster-example-2")
+
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, pvcs)).To(Equal([]string{
+			"cluster-example-2",
+			"cluster-example-3",
+		}))
+	})
+
+	It("ignores the WAL PVCs when the WAL storage is not configured", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{StorageClass: ptr.To("new")},
+			},
+		}
+		pvcs := []corev1.PersistentVolumeClaim{
+			makePVCWithStorageClass("1-wal", NewPgWalCalculator(), ptr.To("old")),
+		}
+		Expect(GetInstancesWithOutdatedStorageClass(cluster, pvcs)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutqueue
+
+import (
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Manager assigns a limited number of rollout slots to the clusters
+// requiring a rolling update. Clusters waiting for a slot are served in
+// the same order in which they asked for one.
+// A nil Manager doesn't limit the number of concurrent rollouts.
+type Manager struct {
+	lock                  sync.Mutex
+	maxConcurrentRollouts int
+	active                map[types.NamespacedName]struct{}
+	queue                 []types.NamespacedName
+}
+
+// NewManager creates a new Manager allowing up to maxConcurrentRollouts
+// clusters to be rolled out at the same time. Zero or a negative value
+// means no limit.
+func NewManager(maxConcurrentRollouts int) *Manager {
+	return &Manager{
+		maxConcurrentRollouts: maxConcurrentRollouts,
+		active:                make(map[types.NamespacedName]struct{}),
+	}
+}
+
+// Acquire tries to assign a rollout slot to the passed cluster, returning
+// true if the cluster holds a slot. Otherwise, the cluster is queued, and
+// its position in the queue is returned, starting from 1
+func (m *Manager) Acquire(cluster types.NamespacedName) (bool, int) {
+	if m == nil || m.maxConcurrentRollouts <= 0 {
+		return true, 0
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.active[cluster]; ok {
+		return true, 0
+	}
+
+	position := slices.Index(m.queue, cluster)
+	if position < 0 {
+		m.queue = append(m.queue, cluster)
+		position = len(m.queue) - 1
+	}
+
+	if position < m.maxConcurrentRollouts-len(m.active) {
+		m.queue = slices.Delete(m.queue, position, position+1)
+		m.active[cluster] = struct{}{}
+		return true, 0
+	}
+
+	return false, position + 1
+}
+
+// Release frees the rollout slot held by the passed cluster, if any,
+// and removes it from the queue
+func (m *Manager) Release(cluster types.NamespacedName) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.active, cluster)
+	if position := slices.Index(m.queue, cluster); position >= 0 {
+		m.queue = slices.Delete(m.queue, position, position+1)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolloutqueue
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rollout manager", func() {
+	first := types.NamespacedName{Namespace: "default", Name: "first"}
+	second := types.NamespacedName{Namespace: "default", Name: "second"}
+	third := types.NamespacedName{Namespace: "default", Name: "third"}
+
+	It("doesn't limit the rollouts when the manager is nil", func() {
+		var manager *Manager
+		acquired, _ := manager.Acquire(first)
+		Expect(acquired).To(BeTrue())
+		manager.Release(first)
+	})
+
+	It("doesn't limit the rollouts when there is no maximum", func() {
+		manager := NewManager(0)
+		for _, cluster := range []types.NamespacedName{first, second, third} {
+			acquired, _ := manager.Acquire(cluster)
+			Expect(acquired).To(BeTrue())
+		}
+	})
+
+	It("limits the number of concurrent rollouts", func() {
+		manager := NewManager(1)
+
+		acquired, _ := manager.Acquire(first)
+		Expect(acquired).To(BeTrue())
+
+		By("keeping the slot to the cluster already holding it", func() {
+			acquired, _ := manager.Acquire(first)
+			Expect(acquired).To(BeTrue())
+		})
+
+		By("queueing the other clusters in order", func() {
+			acquired, position := manager.Acquire(second)
+			Expect(acquired).To(BeFalse())
+			Expect(position).To(Equal(1))
+
+			acquired, position = manager.Acquire(third)
+			Expect(acquired).To(BeFalse())
+			Expect(position).To(Equal(2))
+		})
+
+		By("serving the queue in order once the slot is released", func() {
+			manager.Release(first)
+
+			acquired, position := manager.Acquire(third)
+			Expect(acquired).To(BeFalse())
+			Expect(position).To(Equal(2))
+
+			acquired, _ = manager.Acquire(second)
+			Expect(acquired).To(BeTrue())
+
+			acquired, position = manager.Acquire(third)
+			Expect(acquired).To(BeFalse())
+			Expect(position).To(Equal(1))
+		})
+	})
+
+	It("removes the released clusters from the queue", func() {
+		manager := NewManager(1)
+
+		acquired, _ := manager.Acquire(first)
+		Expect(acquired).To(BeTrue())
+		acquired, _ = manager.Acquire(second)
+		Expect(acquired).To(BeFalse())
+		acquired, position := manager.Acquire(third)
+		Expect(acquired).To(BeFalse())
+		Expect(position).To(Equal(2))
+
+		manager.Release(second)
+		acquired, position = manager.Acquire(third)
+		Expect(acquired).To(BeFalse())
+		Expect(position).To(Equal(1))
+	})
+})
@@ -32,6 +32,7 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -117,6 +118,11 @@ func (r *StatusClient) getReplicaStatusFromPodViaHTTP(
 			return false
 		}
 
+		// A storage failure won't go away by retrying
+		if errors.Is(err, postgresManagement.ErrStorageFailure) {
+			return false
+		}
+
 		contextLog.Debug("Error while requesting the status of an instance, retrying",
 			"pod", pod.Name,
 			"error", err)
@@ -210,6 +216,39 @@ func (r *StatusClient) GetPgControlDataFromInstance(
 	return result.Data, result.Error
 }
 
+// RequestCheckpoint asks the instance running in the passed Pod to
+// issue a checkpoint, waiting for it to complete
+func (r *StatusClient) RequestCheckpoint(ctx context.Context, pod *corev1.Pod) error {
+	contextLogger := log.FromContext(ctx)
+
+	httpURL := url.Build(pod.Status.PodIP, url.PathPgCheckpoint, url.StatusPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, httpURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			contextLogger.Error(err, "while closing body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return nil
+}
+
 // rawInstanceStatusRequest retrieves the status of PostgreSQL pods via an HTTP request with GET method.
 func (r *StatusClient) rawInstanceStatusRequest(
 	ctx context.Context,
@@ -253,5 +292,12 @@ func (r *StatusClient) rawInstanceStatusRequest(
 		return result
 	}
 
+	// An instance with a failing storage is not healthy, even if
+	// its instance manager is answering
+	if result.HasStorageFailure() {
+		result.Error = fmt.Errorf("the instance is reporting a %w (%s)",
+			postgresManagement.ErrStorageFailure, result.StorageFailure)
+	}
+
 	return result
 }
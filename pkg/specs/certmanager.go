@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// CertManagerCertificateGVK is the kind of the cert-manager certificates.
+// They are handled as unstructured objects, to avoid depending on the
+// cert-manager API
+var CertManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// BuildServerCertManagerCertificate creates the cert-manager certificate
+// issuing the server TLS certificate of the cluster, or nil if the cluster
+// doesn't use a cert-manager issuer for it
+func BuildServerCertManagerCertificate(cluster *apiv1.Cluster) *unstructured.Unstructured {
+	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ServerIssuerRef == nil {
+		return nil
+	}
+
+	dnsNames := make([]interface{}, 0, len(cluster.GetClusterAltDNSNames()))
+	for _, name := range cluster.GetClusterAltDNSNames() {
+		dnsNames = append(dnsNames, name)
+	}
+
+	return buildCertManagerCertificate(
+		cluster,
+		cluster.GetServerTLSSecretName(),
+		cluster.Spec.Certificates.ServerIssuerRef,
+		map[string]interface{}{
+			"commonName": cluster.GetServiceReadWriteName(),
+			"dnsNames":   dnsNames,
+			"usages":     []interface{}{"server auth"},
+		})
+}
+
+// BuildReplicationCertManagerCertificate creates the cert-manager certificate
+// issuing the client certificate of the `streaming_replica` user, or nil if
+// the cluster doesn't use a cert-manager issuer for it
+func BuildReplicationCertManagerCertificate(cluster *apiv1.Cluster) *unstructured.Unstructured {
+	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ReplicationIssuerRef == nil {
+		return nil
+	}
+
+	return buildCertManagerCertificate(
+		cluster,
+		cluster.GetReplicationSecretName(),
+		cluster.Spec.Certificates.ReplicationIssuerRef,
+		map[string]interface{}{
+			"commonName": apiv1.StreamingReplicationUser,
+			"usages":     []interface{}{"client auth"},
+		})
+}
+
+// buildCertManagerCertificate creates a cert-manager certificate named
+// after the secret where it is stored. The secret is labeled to be
+// watched, so that the instances are reloaded when cert-manager renews it
+func buildCertManagerCertificate(
+	cluster *apiv1.Cluster,
+	secretName string,
+	issuer *apiv1.CertManagerIssuerReference,
+	spec map[string]interface{},
+) *unstructured.Unstructured {
+	spec["secretName"] = secretName
+	spec["issuerRef"] = map[string]interface{}{
+		"name":  issuer.Name,
+		"kind":  issuer.GetKind(),
+		"group": issuer.GetGroup(),
+	}
+	spec["secretTemplate"] = map[string]interface{}{
+		"labels": map[string]interface{}{
+			utils.ClusterLabelName: cluster.Name,
+			utils.WatchedLabelName: "true",
+		},
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      secretName,
+		Namespace: cluster.Namespace,
+	}
+	cluster.SetInheritedDataAndOwnership(&meta)
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetName(meta.Name)
+	certificate.SetNamespace(meta.Namespace)
+	certificate.SetLabels(meta.Labels)
+	certificate.SetAnnotations(meta.Annotations)
+	certificate.SetOwnerReferences(meta.OwnerReferences)
+
+	return certificate
+}
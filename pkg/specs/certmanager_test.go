@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func nestedField(certificate *unstructured.Unstructured, fields ...string) interface{} {
+	value, found, err := unstructured.NestedFieldCopy(certificate.Object, fields...)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(found).To(BeTrue())
+	return value
+}
+
+var _ = Describe("cert-manager certificates", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Certificates: &apiv1.CertificatesConfiguration{
+					ServerTLSSecret:      "server-tls",
+					ServerAltDNSNames:    []string{"cluster-example.example.com"},
+					ServerIssuerRef:      &apiv1.CertManagerIssuerReference{Name: "ca-issuer"},
+					ReplicationTLSSecret: "replication-tls",
+					ReplicationIssuerRef: &apiv1.CertManagerIssuerReference{
+						Name: "client-issuer",
+						Kind: "ClusterIssuer",
+					},
+				},
+			},
+		}
+	})
+
+	It("doesn't create any certificate without issuers", func() {
+		cluster.Spec.Certificates = nil
+		Expect(BuildServerCertManagerCertificate(cluster)).To(BeNil())
+		Expect(BuildReplicationCertManagerCertificate(cluster)).To(BeNil())
+	})
+
+	It("creates the server certificate", func() {
+		certificate := BuildServerCertManagerCertificate(cluster)
+		Expect(certificate).ToNot(BeNil())
+		Expect(certificate.GroupVersionKind()).To(Equal(CertManagerCertificateGVK))
+		Expect(certificate.GetName()).To(Equal("server-tls"))
+		Expect(certificate.GetNamespace()).To(Equal("default"))
+
+		Expect(nestedField(certificate, "spec", "secretName")).To(Equal("server-tls"))
+		Expect(nestedField(certificate, "spec", "commonName")).To(Equal("cluster-example-rw"))
+		Expect(nestedField(certificate, "spec", "dnsNames")).
+			To(ContainElements("cluster-example-rw.default.svc", "cluster-example.example.com"))
+		Expect(nestedField(certificate, "spec", "usages")).To(Equal([]interface{}{"server auth"}))
+		Expect(nestedField(certificate, "spec", "issuerRef")).To(Equal(map[string]interface{}{
+			"name":  "ca-issuer",
+			"kind":  "Issuer",
+			"group": "cert-manager.io",
+		}))
+	})
+
+	It("creates the client certificate of the streaming_replica user", func() {
+		certificate := BuildReplicationCertManagerCertificate(cluster)
+		Expect(certificate).ToNot(BeNil())
+		Expect(certificate.GetName()).To(Equal("replication-tls"))
+
+		Expect(nestedField(certificate, "spec", "commonName")).To(Equal("streaming_replica"))
+		Expect(nestedField(certificate, "spec", "usages")).To(Equal([]interface{}{"client auth"}))
+		Expect(nestedField(certificate, "spec", "issuerRef", "kind")).To(Equal("ClusterIssuer"))
+	})
+
+	It("labels the secret to reload the instances when it is renewed", func() {
+		certificate := BuildServerCertManagerCertificate(cluster)
+		labels, _, err := unstructured.NestedStringMap(certificate.Object, "spec", "secretTemplate", "labels")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(labels).To(HaveKeyWithValue(utils.WatchedLabelName, "true"))
+		Expect(labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+		Expect(certificate.DeepCopy()).To(Equal(certificate))
+	})
+})
@@ -22,7 +22,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -30,12 +29,19 @@ import (
 // createBootstrapContainer creates the init container bootstrapping the operator
 // executable inside the generated Pods
 func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
+	// The instance manager is installed from the operator image, unless
+	// the PostgreSQL image is already shipping one
+	source := "/manager"
+	if path := cluster.GetInstanceManagerPath(); path != "" {
+		source = path
+	}
+
 	container := corev1.Container{
 		Name:            BootstrapControllerContainerName,
-		Image:           configuration.Current.OperatorImageName,
+		Image:           cluster.GetBootstrapControllerImageName(),
 		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 		Command: []string{
-			"/manager",
+			source,
 			"bootstrap",
 			"/controller/manager",
 		},
@@ -49,6 +55,20 @@ func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
 	return container
 }
 
+// createInitContainers creates the init containers of the generated Pods,
+// starting from the one bootstrapping the operator executable
+func createInitContainers(cluster apiv1.Cluster) []corev1.Container {
+	containers := []corev1.Container{
+		createBootstrapContainer(cluster),
+	}
+
+	for idx := range cluster.Spec.InitContainers {
+		containers = append(containers, *cluster.Spec.InitContainers[idx].DeepCopy())
+	}
+
+	return containers
+}
+
 // addManagerLoggingOptions propagate the logging configuration
 // to the manager inside the generated pod.
 func addManagerLoggingOptions(cluster apiv1.Cluster, container *corev1.Container) {
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -53,6 +54,41 @@ var _ = Describe("Bootstrap Container creation", func() {
 		Expect(container.Resources.Limits["a_test_field"]).ToNot(BeNil())
 		Expect(container.Resources.Requests["another_test_field"]).ToNot(BeNil())
 	})
+
+	It("installs the instance manager from the operator image by default", func() {
+		cluster := apiv1.Cluster{}
+		container := createBootstrapContainer(cluster)
+		Expect(container.Image).To(Equal(configuration.Current.OperatorImageName))
+		Expect(container.Command[:3]).To(Equal([]string{"/manager", "bootstrap", "/controller/manager"}))
+	})
+
+	It("installs the instance manager shipped by the PostgreSQL image", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:16-custom",
+				BootstrapController: &apiv1.BootstrapControllerConfiguration{
+					InstanceManagerPath: "/usr/local/bin/manager",
+				},
+			},
+		}
+		container := createBootstrapContainer(cluster)
+		Expect(container.Image).To(Equal("postgres:16-custom"))
+		Expect(container.Command[:3]).To(Equal([]string{"/usr/local/bin/manager", "bootstrap", "/controller/manager"}))
+	})
+
+	It("appends the additional init containers", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				InitContainers: []corev1.Container{
+					{Name: "warm-up", Image: "busybox"},
+				},
+			},
+		}
+		containers := createInitContainers(cluster)
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[0].Name).To(Equal(BootstrapControllerContainerName))
+		Expect(containers[1].Name).To(Equal("warm-up"))
+	})
 })
 
 var _ = Describe("Container Security Context creation", func() {
@@ -19,7 +19,6 @@ package specs
 import (
 	"fmt"
 
-	"github.com/kballard/go-shellquote"
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -27,7 +26,9 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/jobcommand"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -35,62 +36,108 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// MajorUpgradePrepareContainerName is the name of the init container
+	// copying the binaries of the old major version of PostgreSQL
+	MajorUpgradePrepareContainerName = "prepare-major-upgrade"
+
+	// MajorUpgradeOldBinariesPath is where the binaries of the old major
+	// version of PostgreSQL are copied during a major upgrade
+	MajorUpgradeOldBinariesPath = "/controller/old"
+
+	// ImportDumpVolumePath is where the PersistentVolumeClaim containing
+	// the dump to be imported is mounted in the import job
+	ImportDumpVolumePath = "/var/lib/postgresql/dump"
+
+	// ImportDumpURLEnvVar is the environment variable containing the URL
+	// of the dump to be imported, when it is downloaded from an object store
+	ImportDumpURLEnvVar = "IMPORT_DUMP_URL"
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
-func CreatePrimaryJobViaInitdb(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"init",
-	}
+func CreatePrimaryJobViaInitdb(cluster apiv1.Cluster, nodeSerial int) (*batchv1.Job, error) {
+	var options jobcommand.Options
 
 	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
-		initCommand = append(initCommand, buildInitDBFlags(cluster)...)
+		options.InitDBFlags = buildInitDBFlags(cluster)
 	}
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitSQL != nil {
-		initCommand = append(
-			initCommand,
-			"--post-init-sql",
-			shellquote.Join(cluster.Spec.Bootstrap.InitDB.PostInitSQL...))
-	}
+	options.PostInitSQL = cluster.Spec.Bootstrap.InitDB.PostInitSQL
+	options.PostInitApplicationSQL = cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQL
+	options.PostInitTemplateSQL = cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQL
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQL != nil {
-		initCommand = append(
-			initCommand,
-			"--post-init-application-sql",
-			shellquote.Join(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQL...))
+	if cluster.ShouldInitDBCreateApplicationDatabase() {
+		options.AppDBName = cluster.Spec.Bootstrap.InitDB.Database
+		options.AppUser = cluster.Spec.Bootstrap.InitDB.Owner
 	}
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQL != nil {
-		initCommand = append(
-			initCommand,
-			"--post-init-template-sql",
-			shellquote.Join(cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQL...))
+	setCommonInitJobOptions(cluster, &options)
+
+	role := jobRoleInitDB
+	if cluster.Spec.Bootstrap.InitDB.Import != nil || cluster.Spec.Bootstrap.InitDB.ImportFromDump != nil {
+		role = jobRoleImport
+	} else if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
+		options.PostInitApplicationSQLRefsFolder = postInitApplicationSQLRefsFolder
 	}
 
-	if cluster.ShouldInitDBCreateApplicationDatabase() {
-		initCommand = append(initCommand,
-			"--app-db-name", cluster.Spec.Bootstrap.InitDB.Database,
-			"--app-user", cluster.Spec.Bootstrap.InitDB.Owner)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandInit, options)
+	if err != nil {
+		return nil, err
 	}
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+	job := createPrimaryJob(cluster, nodeSerial, role, initCommand)
+	addDumpToJob(cluster, job)
 
-	if cluster.Spec.Bootstrap.InitDB.Import != nil {
-		return createPrimaryJob(cluster, nodeSerial, jobRoleImport, initCommand)
+	return job, nil
+}
+
+// addDumpToJob makes the dump to be imported available to the import job,
+// either mounting the PersistentVolumeClaim containing it or injecting
+// the URL from where it can be downloaded
+func addDumpToJob(cluster apiv1.Cluster, job *batchv1.Job) {
+	importFromDump := cluster.Spec.Bootstrap.InitDB.ImportFromDump
+	if importFromDump == nil {
+		return
 	}
 
-	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
-		initCommand = append(initCommand,
-			"--post-init-application-sql-refs-folder", postInitApplicationSQLRefsFolder)
+	podSpec := &job.Spec.Template.Spec
+	location := importFromDump.Location
+	if location.PersistentVolumeClaim != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "dump",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: location.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "dump",
+				MountPath: ImportDumpVolumePath,
+				ReadOnly:  true,
+			},
+		)
 	}
 
-	return createPrimaryJob(cluster, nodeSerial, jobRoleInitDB, initCommand)
+	if location.URLSecret != nil {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name: ImportDumpURLEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: location.URLSecret.Name,
+					},
+					Key: location.URLSecret.Key,
+				},
+			},
+		})
+	}
 }
 
-func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
+// buildInitDBFlags builds the options to be passed to initdb
+func buildInitDBFlags(cluster apiv1.Cluster) []string {
 	config := cluster.Spec.Bootstrap.InitDB
 	var options []string
 	// Kept for backward compatibility.
@@ -101,12 +148,7 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 			"cluster", cluster.Name,
 			"namespace", cluster.Namespace)
 
-		options = append(options, config.Options...)
-		initCommand = append(
-			initCommand,
-			"--initdb-flags",
-			shellquote.Join(options...))
-		return initCommand
+		return append(options, config.Options...)
 	}
 	if config.DataChecksums != nil &&
 		*config.DataChecksums {
@@ -125,15 +167,23 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if localeCType := config.LocaleCType; localeCType != "" {
 		options = append(options, fmt.Sprintf("--lc-ctype=%s", localeCType))
 	}
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.IcuLocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
+	if icuRules := config.IcuRules; icuRules != "" {
+		options = append(options, fmt.Sprintf("--icu-rules=%s", icuRules))
+	}
+	if builtinLocale := config.BuiltinLocale; builtinLocale != "" {
+		options = append(options, fmt.Sprintf("--builtin-locale=%s", builtinLocale))
+	}
 	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
-	initCommand = append(
-		initCommand,
-		"--initdb-flags",
-		shellquote.Join(options...))
 
-	return initCommand
+	return options
 }
 
 // CreatePrimaryJobViaRestoreSnapshot creates a new primary instance in a Pod, restoring from a volumeSnapshot
@@ -142,47 +192,78 @@ func CreatePrimaryJobViaRestoreSnapshot(
 	nodeSerial int,
 	snapshot storagesnapshotv1.VolumeSnapshot,
 	backup *apiv1.Backup,
-) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"restoresnapshot",
-	}
-
-	if snapshot.Annotations[utils.BackupLabelFileAnnotationName] != "" {
-		flag := fmt.Sprintf("--backuplabel=%s", snapshot.Annotations[utils.BackupLabelFileAnnotationName])
-		initCommand = append(initCommand, flag)
+) (*batchv1.Job, error) {
+	options := jobcommand.Options{
+		BackupLabel:   snapshot.Annotations[utils.BackupLabelFileAnnotationName],
+		TablespaceMap: snapshot.Annotations[utils.BackupTablespaceMapFileAnnotationName],
 	}
+	setCommonInitJobOptions(cluster, &options)
 
-	if snapshot.Annotations[utils.BackupTablespaceMapFileAnnotationName] != "" {
-		flag := fmt.Sprintf("--tablespacemap=%s", snapshot.Annotations[utils.BackupTablespaceMapFileAnnotationName])
-		initCommand = append(initCommand, flag)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandRestoreSnapshot, options)
+	if err != nil {
+		return nil, err
 	}
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
-
 	job := createPrimaryJob(cluster, nodeSerial, jobRoleSnapshotRecovery, initCommand)
 
 	addBarmanEndpointCAToJobFromCluster(cluster, backup, job)
+	addRecoveryPVCToJob(cluster, backup, job)
 
-	return job
+	return job, nil
 }
 
 // CreatePrimaryJobViaRecovery creates a new primary instance in a Pod, restoring from a Backup
-func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *apiv1.Backup) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"restore",
-	}
+func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *apiv1.Backup) (*batchv1.Job, error) {
+	var options jobcommand.Options
+	setCommonInitJobOptions(cluster, &options)
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandRestore, options)
+	if err != nil {
+		return nil, err
+	}
 
 	job := createPrimaryJob(cluster, nodeSerial, jobRoleFullRecovery, initCommand)
 
 	addBarmanEndpointCAToJobFromCluster(cluster, backup, job)
+	addRecoveryPVCToJob(cluster, backup, job)
 
-	return job
+	return job, nil
+}
+
+// addRecoveryPVCToJob mounts the PersistentVolumeClaim storing the backups
+// of the recovery source, or the passed Backup taken with the `pvc` method,
+// unless it's the one used by the cluster and thus already mounted
+func addRecoveryPVCToJob(cluster apiv1.Cluster, backup *apiv1.Backup, job *batchv1.Job) {
+	var claimName string
+	if backup != nil && backup.Status.Method == apiv1.BackupMethodPVC {
+		claimName = backup.Status.ClaimName
+	} else if source, found := cluster.GetRecoveryPVC(); found {
+		claimName = source.PVC.ClaimName
+	}
+	if claimName == "" {
+		return
+	}
+	if backupPVC := cluster.GetBackupPVC(); backupPVC != nil && backupPVC.ClaimName == claimName {
+		return
+	}
+
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "recovery-backups",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+				ReadOnly:  true,
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{
+			Name:      "recovery-backups",
+			MountPath: pvcbackup.RecoveryVolumeDirectory,
+			ReadOnly:  true,
+		},
+	)
 }
 
 func addBarmanEndpointCAToJobFromCluster(cluster apiv1.Cluster, backup *apiv1.Backup, job *batchv1.Job) {
@@ -210,55 +291,116 @@ func addBarmanEndpointCAToJobFromCluster(cluster apiv1.Cluster, backup *apiv1.Ba
 }
 
 // CreatePrimaryJobViaPgBaseBackup creates a new primary instance in a Pod
-func CreatePrimaryJobViaPgBaseBackup(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"pgbasebackup",
-	}
+func CreatePrimaryJobViaPgBaseBackup(cluster apiv1.Cluster, nodeSerial int) (*batchv1.Job, error) {
+	var options jobcommand.Options
+	setCommonInitJobOptions(cluster, &options)
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandPgBaseBackup, options)
+	if err != nil {
+		return nil, err
+	}
 
-	return createPrimaryJob(cluster, nodeSerial, jobRolePGBaseBackup, initCommand)
+	return createPrimaryJob(cluster, nodeSerial, jobRolePGBaseBackup, initCommand), nil
 }
 
 // JoinReplicaInstance create a new PostgreSQL node, copying the contents from another Pod
-func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"join",
-		"--parent-node", cluster.GetServiceReadWriteName(),
+func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) (*batchv1.Job, error) {
+	options := jobcommand.Options{
+		ParentNode: getJoinParentNode(cluster),
 	}
+	setCommonInitJobOptions(cluster, &options)
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandJoin, options)
+	if err != nil {
+		return nil, err
+	}
 
-	return createPrimaryJob(cluster, nodeSerial, jobRoleJoin, initCommand)
+	return createPrimaryJob(cluster, nodeSerial, jobRoleJoin, initCommand), nil
+}
+
+// getJoinParentNode gets the host a new replica is cloned from. When the
+// "-any" service exists, the current primary is addressed with its stable
+// DNS name, as the read-write service may not be pointing to it yet.
+// The stable names of the instances are not in the server certificate, so
+// the service of the primary is used when the replication connection
+// verifies the host name with `verify-full`
+func getJoinParentNode(cluster apiv1.Cluster) string {
+	verifyHostName := cluster.Spec.ReplicationConnection.GetSslMode() == apiv1.ReplicationSslModeVerifyFull
+	if configuration.Current.CreateAnyService && cluster.Status.CurrentPrimary != "" && !verifyHostName {
+		return cluster.GetInstanceFQDN(cluster.Status.CurrentPrimary)
+	}
+
+	return cluster.GetServicePrimaryName()
 }
 
 // RestoreReplicaInstance creates a new PostgreSQL replica starting from a volume snapshot backup
-func RestoreReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
-	initCommand := []string{
-		"/controller/manager",
-		"instance",
-		"restoresnapshot",
-		"--immediate",
+func RestoreReplicaInstance(cluster apiv1.Cluster, nodeSerial int) (*batchv1.Job, error) {
+	options := jobcommand.Options{
+		Immediate: true,
 	}
+	setCommonInitJobOptions(cluster, &options)
 
-	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+	initCommand, err := jobcommand.BuildCommand(jobcommand.CommandRestoreSnapshot, options)
+	if err != nil {
+		return nil, err
+	}
 
-	job := createPrimaryJob(cluster, nodeSerial, jobRoleSnapshotRecovery, initCommand)
-	return job
+	return createPrimaryJob(cluster, nodeSerial, jobRoleSnapshotRecovery, initCommand), nil
 }
 
-func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
-	var flags []string
+// CreateMajorUpgradeJob creates a job upgrading the data directory of the
+// primary instance to the major version of PostgreSQL of the cluster image.
+// The binaries of the old major version are copied by an init container
+// running the old image, as pg_upgrade needs both of them
+func CreateMajorUpgradeJob(cluster apiv1.Cluster, nodeSerial int, oldImage string) (*batchv1.Job, error) {
+	options := jobcommand.Options{
+		OldBinDirFile: MajorUpgradeOldBinariesPath + "/bindir",
+	}
 
-	if cluster.ShouldCreateWalArchiveVolume() {
-		flags = append(flags, "--pg-wal", PgWalVolumePgWalPath)
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		options.InitDBFlags = buildInitDBFlags(cluster)
 	}
 
-	return flags
+	setCommonInitJobOptions(cluster, &options)
+
+	upgradeCommand, err := jobcommand.BuildCommand(jobcommand.CommandUpgradeExecute, options)
+	if err != nil {
+		return nil, err
+	}
+
+	job := createPrimaryJob(cluster, nodeSerial, jobRoleMajorUpgrade, upgradeCommand)
+
+	prepareContainer := corev1.Container{
+		Name:            MajorUpgradePrepareContainerName,
+		Image:           oldImage,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+			MajorUpgradeOldBinariesPath,
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, prepareContainer)
+
+	// A failed upgrade needs to be investigated before being retried
+	backoffLimit := int32(0)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	return job, nil
+}
+
+// setCommonInitJobOptions sets the options shared by all the jobs
+func setCommonInitJobOptions(cluster apiv1.Cluster, options *jobcommand.Options) {
+	if cluster.ShouldCreateWalArchiveVolume() {
+		options.PgWal = PgWalVolumePgWalPath
+	}
 }
 
 // jobRole describe a possible type of job
@@ -271,15 +413,24 @@ const (
 	jobRoleFullRecovery     jobRole = "full-recovery"
 	jobRoleJoin             jobRole = "join"
 	jobRoleSnapshotRecovery jobRole = "snapshot-recovery"
+	jobRoleMajorUpgrade     jobRole = "major-upgrade"
 )
 
-var jobRoleList = []jobRole{jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin}
+var jobRoleList = []jobRole{
+	jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin, jobRoleMajorUpgrade,
+}
 
 // getJobName returns a string indicating the job name
 func (role jobRole) getJobName(instanceName string) string {
 	return fmt.Sprintf("%s-%s", instanceName, role)
 }
 
+// GetMajorUpgradeJobName gets the name of the job upgrading the
+// major version of PostgreSQL of a given instance
+func GetMajorUpgradeJobName(instanceName string) string {
+	return jobRoleMajorUpgrade.getJobName(instanceName)
+}
+
 // GetPossibleJobNames get all the possible job names for a given instance
 func GetPossibleJobNames(instanceName string) []string {
 	res := make([]string, len(jobRoleList))
@@ -304,23 +455,23 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 			Labels: map[string]string{
 				utils.InstanceNameLabelName: instanceName,
 				utils.ClusterLabelName:      cluster.Name,
+				utils.JobRoleLabelName:      string(role),
 			},
 		},
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
-						utils.InstanceNameLabelName: instanceName,
-						utils.ClusterLabelName:      cluster.Name,
-						utils.JobRoleLabelName:      string(role),
+						utils.InstanceNameLabelName:           instanceName,
+						utils.ClusterLabelName:                cluster.Name,
+						utils.JobRoleLabelName:                string(role),
+						utils.KubernetesAppManagedByLabelName: utils.ManagerName,
 					},
 				},
 				Spec: corev1.PodSpec{
-					Hostname: jobName,
-					InitContainers: []corev1.Container{
-						createBootstrapContainer(cluster),
-					},
-					SchedulerName: cluster.Spec.SchedulerName,
+					Hostname:       jobName,
+					InitContainers: createInitContainers(cluster),
+					SchedulerName:  cluster.Spec.SchedulerName,
 					Containers: []corev1.Container{
 						{
 							Name:            string(role),
@@ -345,6 +496,8 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 					RestartPolicy:             corev1.RestartPolicyNever,
 					NodeSelector:              cluster.Spec.Affinity.NodeSelector,
 					TopologySpreadConstraints: cluster.Spec.TopologySpreadConstraints,
+					DNSPolicy:                 cluster.Spec.DNSPolicy,
+					DNSConfig:                 cluster.Spec.DNSConfig,
 				},
 			},
 		},
@@ -22,6 +22,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -158,10 +161,365 @@ var _ = Describe("Job created via InitDB", func() {
 				},
 			},
 		}
-		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		job, err := CreatePrimaryJobViaInitdb(cluster, 0)
+		Expect(err).ToNot(HaveOccurred())
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitTemplateSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitApplicationSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
+
+	It("passes the locale provider options to initdb", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						LocaleProvider: "icu",
+						IcuLocale:      "en-US",
+						IcuRules:       "&V << w <<< W",
+					},
+				},
+			},
+		}
+		job, err := CreatePrimaryJobViaInitdb(cluster, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(
+			"--locale-provider=icu --icu-locale=en-US '--icu-rules=&V << w <<< W'"))
+	})
+})
+
+var _ = Describe("Jobs of a cluster with a separate WAL volume", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{},
+			},
+			WalStorage: &apiv1.StorageConfiguration{
+				Size: "1Gi",
+			},
+		},
+	}
+
+	It("creates the WAL directory in the WAL volume", func() {
+		job, err := CreatePrimaryJobViaInitdb(cluster, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+			"--pg-wal", PgWalVolumePgWalPath))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "pg-wal")))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "pg-wal",
+			MountPath: PgWalVolumePath,
+		}))
+	})
+
+	It("clones the primary into the WAL volume", func() {
+		job, err := JoinReplicaInstance(cluster, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+			"--pg-wal", PgWalVolumePgWalPath))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "pg-wal")))
+	})
+})
+
+var _ = Describe("Jobs of a cluster storing the backups in a PVC", func() {
+	newCluster := func() apiv1.Cluster {
+		return apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{Source: "origin"},
+				},
+				Backup: &apiv1.BackupConfiguration{
+					PVC: &apiv1.PVCBackupConfiguration{ClaimName: "backups"},
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "origin",
+						PVC:  &apiv1.PVCBackupConfiguration{ClaimName: "origin-backups"},
+					},
+				},
+			},
+		}
+	}
+
+	It("mounts the claim of the recovery source in the recovery job", func() {
+		job, err := CreatePrimaryJobViaRecovery(newCluster(), 1, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElements(
+			HaveField("PersistentVolumeClaim.ClaimName", "backups"),
+			HaveField("PersistentVolumeClaim.ClaimName", "origin-backups"),
+		))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: "backups", MountPath: pvcbackup.VolumeDirectory},
+			corev1.VolumeMount{Name: "recovery-backups", MountPath: pvcbackup.RecoveryVolumeDirectory, ReadOnly: true},
+		))
+	})
+
+	It("doesn't mount the same claim twice", func() {
+		cluster := newCluster()
+		cluster.Spec.ExternalClusters[0].PVC.ClaimName = "backups"
+		job, err := CreatePrimaryJobViaRecovery(cluster, 1, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "recovery-backups")))
+	})
+
+	It("mounts the claim storing the Backup the cluster is recovered from", func() {
+		cluster := newCluster()
+		cluster.Spec.Bootstrap.Recovery = &apiv1.BootstrapRecovery{
+			Backup: &apiv1.BackupSource{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "backup-example"},
+			},
+		}
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				Method:    apiv1.BackupMethodPVC,
+				ClaimName: "other-backups",
+			},
+		}
+		job, err := CreatePrimaryJobViaRecovery(cluster, 1, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(
+			HaveField("PersistentVolumeClaim.ClaimName", "other-backups"),
+		))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(
+			corev1.VolumeMount{Name: "recovery-backups", MountPath: pvcbackup.RecoveryVolumeDirectory, ReadOnly: true},
+		))
+	})
+})
+
+var _ = Describe("Jobs of a cluster importing a dump", func() {
+	newCluster := func(location apiv1.DumpLocation) apiv1.Cluster {
+		return apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						ImportFromDump: &apiv1.ImportFromDump{Location: location},
+					},
+				},
+			},
+		}
+	}
+
+	It("mounts the claim containing the dump", func() {
+		job, err := CreatePrimaryJobViaInitdb(newCluster(apiv1.DumpLocation{
+			PersistentVolumeClaim: &apiv1.DumpVolumeSource{ClaimName: "dumps", Path: "app.dump"},
+		}), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Name).To(Equal("cluster-example-1-import"))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(
+			HaveField("PersistentVolumeClaim.ClaimName", "dumps"),
+		))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(
+			corev1.VolumeMount{Name: "dump", MountPath: ImportDumpVolumePath, ReadOnly: true},
+		))
+	})
+
+	It("injects the URL of the dump from the secret", func() {
+		job, err := CreatePrimaryJobViaInitdb(newCluster(apiv1.DumpLocation{
+			URLSecret: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "dump"},
+				Key:                  "url",
+			},
+		}), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).ToNot(ContainElement(HaveField("Name", "dump")))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(And(
+			HaveField("Name", ImportDumpURLEnvVar),
+			HaveField("ValueFrom.SecretKeyRef.Name", "dump"),
+			HaveField("ValueFrom.SecretKeyRef.Key", "url"),
+		)))
+	})
+})
+
+var _ = Describe("Jobs of a cluster with tablespaces", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{},
+			},
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{
+					Name:    "fragglerock",
+					Storage: apiv1.StorageConfiguration{Size: "1Gi"},
+				},
+			},
+		},
+	}
+
+	It("mounts the PVC of the tablespace in the bootstrap job", func() {
+		job, err := CreatePrimaryJobViaInitdb(cluster, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "fragglerock",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "cluster-example-1-tbs-fragglerock",
+				},
+			},
+		}))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "fragglerock",
+			MountPath: MountForTablespace("fragglerock"),
+		}))
+	})
+
+	It("clones the tablespace of the primary into the PVC of the new instance", func() {
+		job, err := JoinReplicaInstance(cluster, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "fragglerock",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "cluster-example-2-tbs-fragglerock",
+				},
+			},
+		}))
+		Expect(job.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "fragglerock",
+			MountPath: MountForTablespace("fragglerock"),
+		}))
+	})
+})
+
+var _ = Describe("Job created via pg_basebackup", func() {
+	It("runs the pgbasebackup bootstrap command", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					PgBaseBackup: &apiv1.BootstrapPgBaseBackup{
+						Source: "source-db",
+					},
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "source-db",
+						ConnectionParameters: map[string]string{
+							"host": "source-db.example.com",
+						},
+					},
+				},
+			},
+		}
+		job, err := CreatePrimaryJobViaPgBaseBackup(cluster, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Name).To(Equal("cluster-example-1-pgbasebackup"))
+		Expect(job.Spec.Template.Labels).To(HaveKeyWithValue(utils.JobRoleLabelName, "pgbasebackup"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements(
+			"/controller/manager", "instance", "pgbasebackup"))
+	})
+})
+
+var _ = Describe("Job metadata", func() {
+	It("is owned by the cluster and consistently labeled", func() {
+		cluster := apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-uid",
+			},
+		}
+		job, err := JoinReplicaInstance(cluster, 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.OwnerReferences[0].Name).To(Equal("cluster-example"))
+		Expect(job.OwnerReferences[0].Kind).To(Equal(apiv1.ClusterKind))
+		for _, labels := range []map[string]string{job.Labels, job.Spec.Template.Labels} {
+			Expect(labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+			Expect(labels).To(HaveKeyWithValue(utils.InstanceNameLabelName, "cluster-example-2"))
+			Expect(labels).To(HaveKeyWithValue(utils.JobRoleLabelName, "join"))
+			Expect(labels).To(HaveKeyWithValue(utils.KubernetesAppManagedByLabelName, utils.ManagerName))
+		}
+	})
+})
+
+var _ = Describe("Join job parent node", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+		},
+	}
+
+	AfterEach(func() {
+		configuration.Current.CreateAnyService = false
+	})
+
+	It("uses the read-write service without the any service", func() {
+		Expect(getJoinParentNode(cluster)).To(Equal("cluster-example-rw"))
+	})
+
+	It("uses the stable name of the current primary with the any service", func() {
+		configuration.Current.CreateAnyService = true
+		Expect(getJoinParentNode(cluster)).To(Equal("cluster-example-1.cluster-example-any.default.svc"))
+
+		job, err := JoinReplicaInstance(cluster, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(
+			ContainElement("cluster-example-1.cluster-example-any.default.svc"))
+	})
+
+	It("uses the read-write service when the host name of the primary is verified", func() {
+		configuration.Current.CreateAnyService = true
+		verifyFullCluster := cluster.DeepCopy()
+		verifyFullCluster.Spec.ReplicationConnection = &apiv1.ReplicationConnectionConfiguration{
+			SslMode: apiv1.ReplicationSslModeVerifyFull,
+		}
+		Expect(getJoinParentNode(*verifyFullCluster)).To(Equal("cluster-example-rw"))
+		Expect(verifyFullCluster.GetClusterAltDNSNames()).To(ContainElement(getJoinParentNode(*verifyFullCluster)))
+	})
+})
+
+var _ = Describe("Major upgrade job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:16.1",
+		},
+	}
+
+	It("copies the old binaries before running pg_upgrade", func() {
+		job, err := CreateMajorUpgradeJob(cluster, 1, "postgres:15.5")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.Name).To(Equal(GetMajorUpgradeJobName("cluster-example-1")))
+		Expect(job.Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+
+		initContainers := job.Spec.Template.Spec.InitContainers
+		Expect(initContainers).To(HaveLen(2))
+		Expect(initContainers[1].Name).To(Equal(MajorUpgradePrepareContainerName))
+		Expect(initContainers[1].Image).To(Equal("postgres:15.5"))
+		Expect(initContainers[1].Command).To(ContainElements("upgrade", "prepare", MajorUpgradeOldBinariesPath))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("postgres:16.1"))
+		Expect(container.Command).To(ContainElements("upgrade", "execute", MajorUpgradeOldBinariesPath+"/bindir"))
+	})
+
+	It("is one of the possible jobs of an instance", func() {
+		Expect(GetPossibleJobNames("cluster-example-1")).To(ContainElement("cluster-example-1-major-upgrade"))
+	})
 })
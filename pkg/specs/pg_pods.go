@@ -19,6 +19,7 @@ package specs
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -93,3 +94,22 @@ func GetInitContainerImageName(pod corev1.Pod, containerName string) (string, er
 
 	return "", fmt.Errorf("init container %q not found", containerName)
 }
+
+// GetPostgresImageDigest get the digest of the PostgreSQL image that the
+// container runtime resolved for this Pod, or an empty string if it is
+// not known yet
+func GetPostgresImageDigest(pod corev1.Pod) string {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name != PostgresContainerName {
+			continue
+		}
+
+		// The image ID is usually in the "<repository>@<digest>" form,
+		// optionally prefixed by the container runtime scheme
+		if idx := strings.LastIndex(containerStatus.ImageID, "@"); idx >= 0 {
+			return containerStatus.ImageID[idx+1:]
+		}
+	}
+
+	return ""
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -43,3 +44,37 @@ var _ = Describe("Extract the used image name", func() {
 		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
 	})
 })
+
+var _ = Describe("Extract the used image digest", func() {
+	podWithImageID := func(imageID string) corev1.Pod {
+		return corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:    PostgresContainerName,
+						ImageID: imageID,
+					},
+				},
+			},
+		}
+	}
+
+	It("extracts the digest from the image ID", func() {
+		pod := podWithImageID("ghcr.io/cloudnative-pg/postgresql@sha256:0123456789abcdef")
+		Expect(GetPostgresImageDigest(pod)).To(Equal("sha256:0123456789abcdef"))
+	})
+
+	It("extracts the digest when the image ID has a runtime prefix", func() {
+		pod := podWithImageID("docker-pullable://ghcr.io/cloudnative-pg/postgresql@sha256:0123456789abcdef")
+		Expect(GetPostgresImageDigest(pod)).To(Equal("sha256:0123456789abcdef"))
+	})
+
+	It("returns an empty string when the image ID doesn't contain a digest", func() {
+		pod := podWithImageID("docker://sha256:0123456789abcdef")
+		Expect(GetPostgresImageDigest(pod)).To(BeEmpty())
+	})
+
+	It("returns an empty string when the container has not been started yet", func() {
+		Expect(GetPostgresImageDigest(corev1.Pod{})).To(BeEmpty())
+	})
+})
@@ -48,27 +48,56 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		return nil, err
 	}
 
-	podTemplate := podspec.NewFrom(pooler.Spec.Template).
-		WithLabel(utils.PgbouncerNameLabel, pooler.Name).
-		WithLabel(utils.ClusterLabelName, cluster.Name).
-		WithLabel(utils.PodRoleLabelName, string(utils.PodRolePooler)).
-		WithVolume(&corev1.Volume{
-			Name: "ca",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: cluster.GetServerCASecretName(),
+	resourcesChecksum, err := hash.ComputeHash(pooler.Status.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	podTemplateBuilder := podspec.NewFrom(pooler.Spec.Template)
+	if pooler.GetResourcesUpdatePolicy() == apiv1.ResourcesUpdatePolicyRestart {
+		// Changing the checksum in the template triggers a rolling update
+		podTemplateBuilder = podTemplateBuilder.
+			WithAnnotation(utils.ResourcesChecksumAnnotationName, resourcesChecksum)
+	}
+
+	labels := map[string]string{
+		utils.PgbouncerNameLabel: pooler.Name,
+		utils.PodRoleLabelName:   string(utils.PodRolePooler),
+	}
+
+	// A Pooler working on an external cluster has no CloudNativePG
+	// cluster to take the certificates and the seccomp profile from
+	seccompProfile := &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	}
+	if cluster != nil {
+		labels[utils.ClusterLabelName] = cluster.Name
+		seccompProfile = cluster.GetSeccompProfile()
+		podTemplateBuilder = podTemplateBuilder.
+			WithVolume(&corev1.Volume{
+				Name: "ca",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: cluster.GetServerCASecretName(),
+					},
 				},
-			},
-		}).
-		WithVolume(&corev1.Volume{
-			Name: "server-tls",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: cluster.GetServerTLSSecretName(),
+			}).
+			WithVolume(&corev1.Volume{
+				Name: "server-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: cluster.GetServerTLSSecretName(),
+					},
 				},
-			},
-		}).
-		WithSecurityContext(specs.CreatePodSecurityContext(cluster.GetSeccompProfile(), 998, 996), true).
+			})
+	}
+
+	for key, value := range labels {
+		podTemplateBuilder = podTemplateBuilder.WithLabel(key, value)
+	}
+
+	podTemplate := podTemplateBuilder.
+		WithSecurityContext(specs.CreatePodSecurityContext(seccompProfile, 998, 996), true).
 		WithContainerImage("pgbouncer", DefaultPgbouncerImage, false).
 		WithContainerCommand("pgbouncer", []string{
 			"/controller/manager",
@@ -88,7 +117,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			[]string{"/manager", "bootstrap", "/controller/manager"},
 			true).
 		WithInitContainerSecurityContext(specs.BootstrapControllerContainerName,
-			specs.CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+			specs.CreateContainerSecurityContext(seccompProfile),
 			true).
 		WithVolume(&corev1.Volume{
 			Name: "scratch-data",
@@ -106,7 +135,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name}, true).
-		WithContainerSecurityContext("pgbouncer", specs.CreateContainerSecurityContext(cluster.GetSeccompProfile()), true).
+		WithContainerSecurityContext("pgbouncer", specs.CreateContainerSecurityContext(seccompProfile), true).
 		WithServiceAccountName(pooler.Name, true).
 		WithReadinessProbe("pgbouncer", &corev1.Probe{
 			TimeoutSeconds: 5,
@@ -118,17 +147,17 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		}, false).
 		Build()
 
+	podTemplate.Spec.TopologySpreadConstraints = getTopologySpreadConstraints(
+		pooler.Name, podTemplate.Spec.TopologySpreadConstraints)
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pooler.Name,
 			Namespace: pooler.Namespace,
-			Labels: map[string]string{
-				utils.ClusterLabelName:   cluster.Name,
-				utils.PgbouncerNameLabel: pooler.Name,
-				utils.PodRoleLabelName:   string(utils.PodRolePooler),
-			},
+			Labels:    labels,
 			Annotations: map[string]string{
-				utils.PoolerSpecHashAnnotationName: poolerHash,
+				utils.PoolerSpecHashAnnotationName:    poolerHash,
+				utils.ResourcesChecksumAnnotationName: resourcesChecksum,
 			},
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -150,6 +179,32 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 	}, nil
 }
 
+// getTopologySpreadConstraints returns a copy of the topology spread
+// constraints set in the Pooler template, where the constraints without
+// a label selector are applied to the pods of the Pooler
+func getTopologySpreadConstraints(
+	poolerName string,
+	constraints []corev1.TopologySpreadConstraint,
+) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return constraints
+	}
+
+	result := make([]corev1.TopologySpreadConstraint, len(constraints))
+	for idx := range constraints {
+		constraints[idx].DeepCopyInto(&result[idx])
+		if result[idx].LabelSelector == nil {
+			result[idx].LabelSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.PgbouncerNameLabel: poolerName,
+				},
+			}
+		}
+	}
+
+	return result
+}
+
 func getDeploymentStrategy(strategy *appsv1.DeploymentStrategy) appsv1.DeploymentStrategy {
 	if strategy != nil {
 		return *strategy.DeepCopy()
@@ -18,6 +18,7 @@ package pgbouncer
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -105,6 +106,77 @@ var _ = Describe("Deployment", func() {
 		Expect(podTemplate.Spec.Containers[0].Image).To(Equal(DefaultPgbouncerImage))
 	})
 
+	It("annotates the Deployment with the checksum of the secrets", func() {
+		pooler.Status.Secrets = &apiv1.PoolerSecrets{
+			ServerCA: apiv1.SecretVersion{Name: "test-cluster-ca", Version: "1"},
+		}
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		checksum := deployment.Annotations[utils.ResourcesChecksumAnnotationName]
+		Expect(checksum).ToNot(BeEmpty())
+		Expect(deployment.Spec.Template.Annotations).ToNot(HaveKey(utils.ResourcesChecksumAnnotationName))
+
+		pooler.Status.Secrets.ServerCA.Version = "2"
+		deployment, err = Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Annotations[utils.ResourcesChecksumAnnotationName]).ToNot(Equal(checksum))
+	})
+
+	It("annotates the pod template with the checksum of the secrets with the restart policy", func() {
+		pooler.Spec.ResourcesUpdatePolicy = apiv1.ResourcesUpdatePolicyRestart
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(
+			utils.ResourcesChecksumAnnotationName,
+			deployment.Annotations[utils.ResourcesChecksumAnnotationName]))
+	})
+
+	It("doesn't change the pod template when the databases and users change", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		pooler.Spec.PgBouncer.Databases = []apiv1.PgBouncerDatabase{{Name: "app", PoolSize: ptr.To(int32(10))}}
+		pooler.Spec.PgBouncer.Users = []apiv1.PgBouncerUser{{Name: "app", MaxUserConnections: ptr.To(int32(5))}}
+		updatedDeployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(updatedDeployment.Spec.Template).To(Equal(deployment.Spec.Template))
+	})
+
+	It("applies the topology spread constraints without a label selector to the pooler pods", func() {
+		customSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "custom"}}
+		pooler.Spec.Template = &apiv1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "kubernetes.io/hostname",
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+					},
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+						LabelSelector:     customSelector,
+					},
+				},
+			},
+		}
+
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0].TopologyKey).To(Equal("kubernetes.io/hostname"))
+		Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(map[string]string{
+			utils.PgbouncerNameLabel: pooler.Name,
+		}))
+		Expect(constraints[1].LabelSelector).To(Equal(customSelector))
+
+		// The Pooler specification is left untouched
+		Expect(pooler.Spec.Template.Spec.TopologySpreadConstraints[0].LabelSelector).To(BeNil())
+	})
+
 	It("sets the correct number of replicas", func() {
 		pooler.Spec.Instances = ptr.To(int32(3))
 		deployment, err := Deployment(pooler, cluster)
@@ -156,4 +228,19 @@ var _ = Describe("Deployment", func() {
 		Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port).
 			To(Equal(intstr.FromInt(pgBouncerConfig.PgBouncerPort)))
 	})
+
+	It("creates a Deployment for an external cluster", func() {
+		pooler.Spec.Cluster = apiv1.LocalObjectReference{}
+		pooler.Spec.ExternalCluster = &apiv1.PoolerExternalCluster{Host: "postgres.example.com"}
+
+		deployment, err := Deployment(pooler, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment).ToNot(BeNil())
+		Expect(deployment.Labels).ToNot(HaveKey(utils.ClusterLabelName))
+		Expect(deployment.Labels[utils.PgbouncerNameLabel]).To(Equal(pooler.Name))
+		Expect(deployment.Spec.Template.Labels).ToNot(HaveKey(utils.ClusterLabelName))
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).ToNot(BeElementOf("ca", "server-tls"))
+		}
+	})
 })
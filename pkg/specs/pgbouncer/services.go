@@ -29,14 +29,18 @@ import (
 // Service create the specification for the service of
 // pgbouncer
 func Service(pooler *apiv1.Pooler, cluster *apiv1.Cluster) *corev1.Service {
+	labels := map[string]string{
+		utils.PgbouncerNameLabel: pooler.Name,
+	}
+	if cluster != nil {
+		labels[utils.ClusterLabelName] = cluster.Name
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pooler.Name,
 			Namespace: pooler.Namespace,
-			Labels: map[string]string{
-				utils.PgbouncerNameLabel: pooler.Name,
-				utils.ClusterLabelName:   cluster.Name,
-			},
+			Labels:    labels,
 		},
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeClusterIP,
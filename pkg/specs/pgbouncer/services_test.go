@@ -68,5 +68,11 @@ var _ = Describe("Pooler Service", func() {
 				utils.PgbouncerNameLabel: pooler.Name,
 			}))
 		})
+
+		It("doesn't set the cluster label for an external cluster", func() {
+			service := Service(pooler, nil)
+			Expect(service.Labels).ToNot(HaveKey(utils.ClusterLabelName))
+			Expect(service.Labels[utils.PgbouncerNameLabel]).To(Equal(pooler.Name))
+		})
 	})
 })
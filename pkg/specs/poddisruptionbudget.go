@@ -52,6 +52,7 @@ func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisru
 		},
 	}
 
+	utils.SetSpecHash(&pdb.ObjectMeta, pdb.Spec)
 	cluster.SetInheritedDataAndOwnership(&pdb.ObjectMeta)
 
 	return pdb
@@ -81,6 +82,7 @@ func BuildPrimaryPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisrup
 		},
 	}
 
+	utils.SetSpecHash(&pdb.ObjectMeta, pdb.Spec)
 	cluster.SetInheritedDataAndOwnership(&pdb.ObjectMeta)
 
 	return pdb
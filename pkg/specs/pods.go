@@ -32,6 +32,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -72,6 +73,10 @@ const (
 	// controller inside the Pod file system
 	BootstrapControllerContainerName = "bootstrap-controller"
 
+	// BackupPluginContainerName is the name of the sidecar container
+	// running the backup plugin
+	BackupPluginContainerName = "backup-plugin"
+
 	// PgDataPath is the path to PGDATA variable
 	PgDataPath = "/var/lib/postgresql/data/pgdata"
 
@@ -161,13 +166,11 @@ func CreateClusterPodSpec(
 	gracePeriod int64,
 ) corev1.PodSpec {
 	return corev1.PodSpec{
-		Hostname: podName,
-		InitContainers: []corev1.Container{
-			createBootstrapContainer(cluster),
-		},
-		SchedulerName: cluster.Spec.SchedulerName,
-		Containers:    createPostgresContainers(cluster, envConfig),
-		Volumes:       createPostgresVolumes(cluster, podName),
+		Hostname:       podName,
+		InitContainers: createInitContainers(cluster),
+		SchedulerName:  cluster.Spec.SchedulerName,
+		Containers:     createPostgresContainers(cluster, envConfig),
+		Volumes:        createPostgresVolumes(cluster, podName),
 		SecurityContext: CreatePodSecurityContext(
 			cluster.GetSeccompProfile(),
 			cluster.GetPostgresUID(),
@@ -178,7 +181,21 @@ func CreateClusterPodSpec(
 		NodeSelector:                  cluster.Spec.Affinity.NodeSelector,
 		TerminationGracePeriodSeconds: &gracePeriod,
 		TopologySpreadConstraints:     cluster.Spec.TopologySpreadConstraints,
+		DNSPolicy:                     getInstanceDNSPolicy(cluster),
+		DNSConfig:                     cluster.Spec.DNSConfig,
+		HostNetwork:                   cluster.Spec.HostNetwork,
+	}
+}
+
+// getInstanceDNSPolicy gets the DNS policy of the instance Pods. When the
+// Pods are using the host network and no policy has been chosen, we still
+// want them to resolve the cluster Services
+func getInstanceDNSPolicy(cluster apiv1.Cluster) corev1.DNSPolicy {
+	if cluster.Spec.HostNetwork && cluster.Spec.DNSPolicy == "" {
+		return corev1.DNSClusterFirstWithHostNet
 	}
+
+	return cluster.Spec.DNSPolicy
 }
 
 // createPostgresContainers create the PostgreSQL containers that are
@@ -223,6 +240,7 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 					},
 				},
 			},
+			Lifecycle: createPostgresContainerLifecycle(cluster),
 			Command: []string{
 				"/controller/manager",
 				"instance",
@@ -252,9 +270,100 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 
 	addManagerLoggingOptions(cluster, &containers[0])
 
+	if cluster.Spec.HostNetwork {
+		addHostPorts(&containers[0])
+	}
+
+	if plugin := cluster.GetBackupPlugin(); plugin != nil {
+		containers = append(containers, createBackupPluginContainer(cluster, *plugin, containers[0]))
+	}
+
 	return containers
 }
 
+// addHostPorts reserves on the node the ports used by the PostgreSQL
+// container when it is running in the host network namespace, including
+// the local web server, which is bound to the loopback interface of the
+// node. This lets the scheduler avoid placing two instances using the same
+// ports on the same node
+func addHostPorts(container *corev1.Container) {
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          "local",
+		ContainerPort: int32(url.LocalPort),
+		Protocol:      "TCP",
+	})
+
+	for i := range container.Ports {
+		container.Ports[i].HostPort = container.Ports[i].ContainerPort
+	}
+}
+
+// createBackupPluginContainer creates the sidecar container running the
+// backup plugin. The sidecar shares the environment and the volumes of
+// the PostgreSQL container, and exposes the plugin on a socket in the
+// plugins directory
+func createBackupPluginContainer(
+	cluster apiv1.Cluster,
+	plugin apiv1.BackupPluginConfiguration,
+	postgresContainer corev1.Container,
+) corev1.Container {
+	env := make([]corev1.EnvVar, 0, len(postgresContainer.Env)+1)
+	env = append(env, postgresContainer.Env...)
+	env = append(env, corev1.EnvVar{
+		Name:  backupplugin.SocketEnvVar,
+		Value: backupplugin.SocketPath(plugin.Name),
+	})
+
+	container := corev1.Container{
+		Name:            BackupPluginContainerName,
+		Image:           plugin.Image,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Env:             env,
+		EnvFrom:         postgresContainer.EnvFrom,
+		VolumeMounts:    postgresContainer.VolumeMounts,
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+	}
+
+	// The barman-cloud plugin is embedded in the instance manager, and
+	// runs using the operand image
+	if plugin.Name == apiv1.BarmanCloudPluginName {
+		if container.Image == "" {
+			container.Image = cluster.GetImageName()
+		}
+		container.Command = []string{
+			"/controller/manager",
+			"backup-plugin",
+			apiv1.BarmanCloudPluginName,
+		}
+		addManagerLoggingOptions(cluster, &container)
+	}
+
+	return container
+}
+
+// createPostgresContainerLifecycle creates the lifecycle hooks of the
+// PostgreSQL container, depending on the preStop strategy of the cluster
+func createPostgresContainerLifecycle(cluster apiv1.Cluster) *corev1.Lifecycle {
+	strategy := cluster.GetPreStopStrategy()
+	if strategy == apiv1.PreStopStrategyNone {
+		return nil
+	}
+
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"/controller/manager",
+					"instance",
+					"prestop",
+					"--strategy",
+					string(strategy),
+				},
+			},
+		},
+	}
+}
+
 // getStartupProbeFailureThreshold get the startup probe failure threshold
 // FAILURE_THRESHOLD = ceil(startDelay / periodSeconds) and minimum value is 1
 func getStartupProbeFailureThreshold(startupDelay int32) int32 {
@@ -377,7 +486,7 @@ func CreatePodSecurityContext(seccompProfile *corev1.SeccompProfile, user, group
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	podName := GetInstanceName(cluster.Name, nodeSerial)
-	gracePeriod := int64(cluster.GetMaxStopDelay())
+	gracePeriod := cluster.GetTerminationGracePeriodSeconds()
 
 	envConfig := CreatePodEnvConfig(cluster, podName)
 
@@ -386,13 +495,15 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: map[string]string{
-				utils.ClusterLabelName:      cluster.Name,
-				utils.InstanceNameLabelName: podName,
-				utils.PodRoleLabelName:      string(utils.PodRoleInstance),
+				utils.ClusterLabelName:                cluster.Name,
+				utils.InstanceNameLabelName:           podName,
+				utils.PodRoleLabelName:                string(utils.PodRoleInstance),
+				utils.KubernetesAppManagedByLabelName: utils.ManagerName,
 			},
 			Annotations: map[string]string{
-				utils.ClusterSerialAnnotationName: strconv.Itoa(nodeSerial),
-				utils.PodEnvHashAnnotationName:    envConfig.Hash,
+				utils.ClusterSerialAnnotationName:     strconv.Itoa(nodeSerial),
+				utils.PodEnvHashAnnotationName:        envConfig.Hash,
+				utils.ResourcesChecksumAnnotationName: GetResourcesChecksum(cluster),
 			},
 			Name:      podName,
 			Namespace: cluster.Namespace,
@@ -415,9 +526,32 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	if utils.IsAnnotationAppArmorPresent(&pod.Spec, cluster.Annotations) {
 		utils.AnnotateAppArmor(&pod.ObjectMeta, &pod.Spec, cluster.Annotations)
 	}
+	utils.SetSpecHash(&pod.ObjectMeta, pod.Spec)
+
 	return pod
 }
 
+// GetResourcesChecksum returns the checksum of the versions of the Secrets
+// projected into the instances of the cluster. The other Secrets and
+// ConfigMaps, like the certificates and the custom queries, are read by
+// the instance manager from the API server and don't need to be tracked
+func GetResourcesChecksum(cluster apiv1.Cluster) string {
+	var projectedSecrets struct {
+		superuserSecret   string
+		applicationSecret string
+	}
+	if cluster.GetEnableSuperuserAccess() {
+		projectedSecrets.superuserSecret = cluster.Status.SecretsResourceVersion.SuperuserSecretVersion
+	}
+	if cluster.ShouldCreateApplicationDatabase() {
+		projectedSecrets.applicationSecret = cluster.Status.SecretsResourceVersion.ApplicationSecretVersion
+	}
+
+	checksum, _ := hash.ComputeHash(projectedSecrets)
+
+	return checksum
+}
+
 // GetInstanceName returns a string indicating the instance name
 func GetInstanceName(clusterName string, nodeSerial int) string {
 	return fmt.Sprintf("%s-%v", clusterName, nodeSerial)
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -839,3 +840,204 @@ var _ = Describe("Compute startup probe failure threshold", func() {
 		Expect(getStartupProbeFailureThreshold(109)).To(BeNumerically("==", 11))
 	})
 })
+
+var _ = Describe("Pod termination", func() {
+	It("uses the stop delay as the default termination grace period", func() {
+		cluster := v1.Cluster{Spec: v1.ClusterSpec{MaxStopDelay: 1800}}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(1800))
+		Expect(pod.Spec.Containers[0].Lifecycle).To(BeNil())
+	})
+
+	It("uses the configured termination grace period and preStop strategy", func() {
+		gracePeriod := int64(600)
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStopDelay:                  1800,
+				TerminationGracePeriodSeconds: &gracePeriod,
+				PreStopStrategy:               v1.PreStopStrategyFastShutdown,
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(600))
+
+		lifecycle := pod.Spec.Containers[0].Lifecycle
+		Expect(lifecycle).ToNot(BeNil())
+		Expect(lifecycle.PreStop.HTTPGet).To(BeNil())
+		Expect(lifecycle.PreStop.Exec.Command).To(HaveExactElements(
+			"/controller/manager", "instance", "prestop", "--strategy", "fastShutdown"))
+	})
+})
+
+var _ = Describe("Resources checksum", func() {
+	It("is set on the instance pods", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(
+			utils.ResourcesChecksumAnnotationName, GetResourcesChecksum(cluster)))
+	})
+
+	It("changes only when the version of a projected secret changes", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				EnableSuperuserAccess: ptr.To(true),
+				Bootstrap: &v1.BootstrapConfiguration{
+					InitDB: &v1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+		}
+		checksum := GetResourcesChecksum(cluster)
+		Expect(GetResourcesChecksum(cluster)).To(Equal(checksum))
+
+		By("ignoring the secrets and config maps read from the API server", func() {
+			cluster.Status.SecretsResourceVersion.ServerCASecretVersion = "2"
+			cluster.Status.ConfigMapResourceVersion.Metrics = map[string]string{"custom-queries": "3"}
+			Expect(GetResourcesChecksum(cluster)).To(Equal(checksum))
+		})
+
+		By("tracking the superuser secret", func() {
+			cluster.Status.SecretsResourceVersion.SuperuserSecretVersion = "4"
+			superuserChecksum := GetResourcesChecksum(cluster)
+			Expect(superuserChecksum).ToNot(Equal(checksum))
+			checksum = superuserChecksum
+		})
+
+		By("tracking the application secret", func() {
+			cluster.Status.SecretsResourceVersion.ApplicationSecretVersion = "5"
+			applicationChecksum := GetResourcesChecksum(cluster)
+			Expect(applicationChecksum).ToNot(Equal(checksum))
+			checksum = applicationChecksum
+		})
+
+		By("ignoring the superuser secret when it is not mounted", func() {
+			cluster.Spec.EnableSuperuserAccess = ptr.To(false)
+			cluster.Status.SecretsResourceVersion.SuperuserSecretVersion = "6"
+			unmountedChecksum := GetResourcesChecksum(cluster)
+			cluster.Status.SecretsResourceVersion.SuperuserSecretVersion = "7"
+			Expect(GetResourcesChecksum(cluster)).To(Equal(unmountedChecksum))
+		})
+	})
+})
+
+var _ = Describe("Backup plugin sidecar", func() {
+	It("is not added when no plugin is configured", func() {
+		cluster := v1.Cluster{}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+	})
+
+	It("runs the barman-cloud plugin with the operand image", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				ImageName: "postgres:16",
+				Backup: &v1.BackupConfiguration{
+					BarmanObjectStore: &v1.BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket"},
+					Plugin:            &v1.BackupPluginConfiguration{Name: v1.BarmanCloudPluginName},
+				},
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+
+		sidecar := pod.Spec.Containers[1]
+		Expect(sidecar.Name).To(Equal(BackupPluginContainerName))
+		Expect(sidecar.Image).To(Equal("postgres:16"))
+		Expect(sidecar.Command).To(HaveExactElements("/controller/manager", "backup-plugin", "barman-cloud"))
+		Expect(sidecar.Env).To(ContainElement(corev1.EnvVar{
+			Name:  "PLUGIN_SOCKET",
+			Value: "/plugins/barman-cloud.sock",
+		}))
+		Expect(sidecar.VolumeMounts).To(Equal(pod.Spec.Containers[0].VolumeMounts))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "plugins",
+			MountPath: "/plugins",
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", "plugins")))
+	})
+
+	It("runs third party plugins with their own image and entrypoint", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Backup: &v1.BackupConfiguration{
+					Plugin: &v1.BackupPluginConfiguration{
+						Name:  "custom",
+						Image: "example.com/custom-backup:1.0",
+					},
+				},
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Spec.Containers[1].Image).To(Equal("example.com/custom-backup:1.0"))
+		Expect(pod.Spec.Containers[1].Command).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Pod DNS", func() {
+	It("uses the default DNS settings when not configured", func() {
+		cluster := v1.Cluster{}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.DNSPolicy).To(BeEmpty())
+		Expect(pod.Spec.DNSConfig).To(BeNil())
+	})
+
+	It("uses the DNS policy and configuration of the cluster", func() {
+		ndots := "1"
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				DNSPolicy: corev1.DNSClusterFirst,
+				DNSConfig: &corev1.PodDNSConfig{
+					Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
+				},
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirst))
+		Expect(pod.Spec.DNSConfig).To(Equal(cluster.Spec.DNSConfig))
+
+		specsMatch, diff := ComparePodSpecs(PodWithExistingStorage(v1.Cluster{}, 1).Spec, pod.Spec)
+		Expect(specsMatch).To(BeFalse())
+		Expect(diff).To(BeElementOf("dns-policy", "dns-config"))
+	})
+})
+
+var _ = Describe("Pod host network", func() {
+	It("uses the pod network by default", func() {
+		pod := PodWithExistingStorage(v1.Cluster{}, 1)
+		Expect(pod.Spec.HostNetwork).To(BeFalse())
+		for _, port := range pod.Spec.Containers[0].Ports {
+			Expect(port.HostPort).To(BeZero())
+		}
+	})
+
+	It("reserves the ports on the node when using the host network", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				HostNetwork: true,
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.HostNetwork).To(BeTrue())
+		Expect(pod.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+		Expect(pod.Spec.Containers[0].Ports).To(HaveLen(4))
+		for _, port := range pod.Spec.Containers[0].Ports {
+			Expect(port.HostPort).To(Equal(port.ContainerPort))
+		}
+
+		specsMatch, _ := ComparePodSpecs(PodWithExistingStorage(v1.Cluster{}, 1).Spec, pod.Spec)
+		Expect(specsMatch).To(BeFalse())
+	})
+
+	It("keeps the chosen DNS policy when using the host network", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				HostNetwork: true,
+				DNSPolicy:   corev1.DNSDefault,
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.DNSPolicy).To(Equal(corev1.DNSDefault))
+	})
+})
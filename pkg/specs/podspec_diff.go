@@ -69,6 +69,15 @@ func ComparePodSpecs(
 		"service-account-name": func() bool {
 			return currentPodSpec.ServiceAccountName == targetPodSpec.ServiceAccountName
 		},
+		"dns-policy": func() bool {
+			return currentPodSpec.DNSPolicy == targetPodSpec.DNSPolicy
+		},
+		"dns-config": func() bool {
+			return reflect.DeepEqual(currentPodSpec.DNSConfig, targetPodSpec.DNSConfig)
+		},
+		"host-network": func() bool {
+			return currentPodSpec.HostNetwork == targetPodSpec.HostNetwork
+		},
 		"scheduler-name": func() bool {
 			return currentPodSpec.SchedulerName == targetPodSpec.SchedulerName
 		},
@@ -163,6 +172,9 @@ func doContainersMatch(currentContainer, targetContainer corev1.Container) (bool
 		"command": func() bool {
 			return reflect.DeepEqual(currentContainer.Command, targetContainer.Command)
 		},
+		"lifecycle": func() bool {
+			return reflect.DeepEqual(currentContainer.Lifecycle, targetContainer.Lifecycle)
+		},
 		"resources": func() bool {
 			return reflect.DeepEqual(currentContainer.Resources, targetContainer.Resources)
 		},
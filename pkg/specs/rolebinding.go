@@ -19,12 +19,14 @@ package specs
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // CreateRoleBinding is the binding between the permissions that the instance manager can use
 // and the ServiceAccount used by the Pod
 func CreateRoleBinding(objectMeta metav1.ObjectMeta) rbacv1.RoleBinding {
-	return rbacv1.RoleBinding{
+	roleBinding := rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: objectMeta.Namespace,
 			Name:      objectMeta.Name,
@@ -43,4 +45,10 @@ func CreateRoleBinding(objectMeta metav1.ObjectMeta) rbacv1.RoleBinding {
 			Name:     objectMeta.Name,
 		},
 	}
+	utils.SetSpecHash(&roleBinding.ObjectMeta, struct {
+		Subjects []rbacv1.Subject
+		RoleRef  rbacv1.RoleRef
+	}{roleBinding.Subjects, roleBinding.RoleRef})
+
+	return roleBinding
 }
@@ -165,7 +165,22 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(7))
+		Expect(serviceAccount.Rules).To(HaveLen(9))
+	})
+
+	It("allows the instance manager to reconcile the databases", func() {
+		serviceAccount := CreateRole(cluster, nil)
+		var databasesPolicy, databasesStatusPolicy v1.PolicyRule
+		for _, policy := range serviceAccount.Rules {
+			switch {
+			case len(policy.Resources) > 0 && policy.Resources[0] == "databases":
+				databasesPolicy = policy
+			case len(policy.Resources) > 0 && policy.Resources[0] == "databases/status":
+				databasesStatusPolicy = policy
+			}
+		}
+		Expect(databasesPolicy.Verbs).To(ConsistOf("get", "list", "patch", "update", "watch"))
+		Expect(databasesStatusPolicy.Verbs).To(ConsistOf("get", "patch", "update"))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -67,6 +68,34 @@ func CreateSecret(
 	}
 }
 
+// CreateSuperuserSecret creates the secret with the credentials of the
+// postgres superuser, generated by the operator for the cluster
+func CreateSuperuserSecret(cluster *apiv1.Cluster, password string) *corev1.Secret {
+	secret := CreateSecret(
+		cluster.GetSuperuserSecretName(),
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		"*",
+		"postgres",
+		password)
+	cluster.SetInheritedDataAndOwnership(&secret.ObjectMeta)
+	return secret
+}
+
+// CreateApplicationSecret creates the secret with the credentials of the
+// owner of the application database, generated by the operator for the cluster
+func CreateApplicationSecret(cluster *apiv1.Cluster, password string) *corev1.Secret {
+	secret := CreateSecret(
+		cluster.GetApplicationSecretName(),
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		cluster.GetApplicationDatabaseName(),
+		cluster.GetApplicationDatabaseOwner(),
+		password)
+	cluster.SetInheritedDataAndOwnership(&secret.ObjectMeta)
+	return secret
+}
+
 type connectionStringBuilder struct {
 	host     string
 	dbname   string
@@ -17,6 +17,10 @@ limitations under the License.
 package specs
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -40,4 +44,28 @@ var _ = Describe("Secret creation", func() {
 			Equal("jdbc:postgresql://thishost:5432/thisdb?password=thispassword&user=thisuser"),
 		)
 	})
+
+	It("create the secrets of the superuser and of the application owner", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+		}
+
+		superuserSecret := CreateSuperuserSecret(cluster, "password")
+		Expect(superuserSecret.Name).To(Equal("cluster-example-superuser"))
+		Expect(superuserSecret.StringData["username"]).To(Equal("postgres"))
+		Expect(superuserSecret.StringData["dbname"]).To(Equal("*"))
+		Expect(superuserSecret.StringData["host"]).To(Equal("cluster-example-rw"))
+		Expect(superuserSecret.OwnerReferences).To(HaveLen(1))
+
+		appSecret := CreateApplicationSecret(cluster, "password")
+		Expect(appSecret.Name).To(Equal("cluster-example-app"))
+		Expect(appSecret.StringData["username"]).To(Equal("app"))
+		Expect(appSecret.StringData["dbname"]).To(Equal("app"))
+		Expect(appSecret.OwnerReferences).To(HaveLen(1))
+	})
 })
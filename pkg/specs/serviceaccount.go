@@ -24,10 +24,29 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// CreateServiceAccount creates the ServiceAccount used by the instances
+// of the cluster, referencing the passed pull secrets
+func CreateServiceAccount(cluster *apiv1.Cluster, imagePullSecretsNames []string) (*corev1.ServiceAccount, error) {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		},
+	}
+	if err := UpdateServiceAccount(imagePullSecretsNames, serviceAccount); err != nil {
+		return nil, err
+	}
+
+	cluster.SetInheritedDataAndOwnership(&serviceAccount.ObjectMeta)
+	cluster.Spec.ServiceAccountTemplate.MergeMetadata(serviceAccount)
+	return serviceAccount, nil
+}
+
 // UpdateServiceAccount sets the needed values in the ServiceAccount that will be used in every Pod
 func UpdateServiceAccount(imagePullSecretsNames []string, serviceAccount *corev1.ServiceAccount) error {
 	if serviceAccount.ImagePullSecrets == nil {
@@ -20,6 +20,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -36,6 +37,25 @@ var _ = Describe("Service accounts", func() {
 		Expect(sa.Annotations[utils.OperatorManagedSecretsAnnotationName]).To(Equal("null"))
 	})
 
+	It("create the service account of a cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				ServiceAccountTemplate: &apiv1.ServiceAccountTemplate{
+					Metadata: apiv1.Metadata{Annotations: map[string]string{"team": "db"}},
+				},
+			},
+		}
+
+		sa, err := CreateServiceAccount(cluster, []string{"pull-secret"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sa.Name).To(Equal("cluster-example"))
+		Expect(sa.Namespace).To(Equal("default"))
+		Expect(sa.ImagePullSecrets).To(Equal([]v1.LocalObjectReference{{Name: "pull-secret"}}))
+		Expect(sa.Annotations).To(HaveKeyWithValue("team", "db"))
+		Expect(sa.OwnerReferences).To(HaveLen(1))
+	})
+
 	It("correctly create the annotation storing the secret names", func() {
 		sa := &v1.ServiceAccount{}
 		err := UpdateServiceAccount([]string{"one", "two"}, sa)
@@ -26,6 +26,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// clusterRoleLabelDetached is the role selected by a detached -rw service.
+// No pod has it, so the service doesn't select any instance
+const clusterRoleLabelDetached = "detached"
+
 func buildInstanceServicePorts() []corev1.ServicePort {
 	return []corev1.ServicePort{
 		{
@@ -37,7 +41,9 @@ func buildInstanceServicePorts() []corev1.ServicePort {
 	}
 }
 
-// CreateClusterAnyService create a service insisting on all the pods
+// CreateClusterAnyService create a headless service insisting on all the pods.
+// The service is the subdomain of the instances, giving each of them a stable
+// DNS name which can be resolved even before the instance is ready
 func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -46,6 +52,7 @@ func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 		},
 		Spec: corev1.ServiceSpec{
 			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
 			PublishNotReadyAddresses: true,
 			Ports:                    buildInstanceServicePorts(),
 			Selector: map[string]string{
@@ -110,3 +117,110 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 		},
 	}
 }
+
+// CreateClusterDesignatedPrimaryService create the service insisting on the
+// designated primary of a permanent replica cluster, used by the other
+// instances in place of the -rw service
+func CreateClusterDesignatedPrimaryService(cluster apiv1.Cluster) *corev1.Service {
+	service := CreateClusterReadWriteService(cluster)
+	service.Name = cluster.GetServiceDesignatedPrimaryName()
+	return service
+}
+
+// CreateClusterDetachedReadWriteService create the -rw service without
+// selecting any pod, so that it has no endpoints while the primary changes
+func CreateClusterDetachedReadWriteService(cluster apiv1.Cluster) *corev1.Service {
+	service := CreateClusterReadWriteService(cluster)
+	service.Spec.Selector[utils.ClusterRoleLabelName] = clusterRoleLabelDetached
+	return service
+}
+
+// IsDetachedReadWriteService checks if the passed -rw service has been
+// detached from the primary
+func IsDetachedReadWriteService(service *corev1.Service) bool {
+	return service.Spec.Selector[utils.ClusterRoleLabelName] == clusterRoleLabelDetached
+}
+
+// CreateClusterServices creates the Services of the cluster, with the
+// metadata inherited from it: the -any service if requested, the -r, -ro
+// and -rw ones, with the latter detached from the primary if requested or
+// replaced by the -designated one in a permanent replica cluster, and the
+// services of the passed instances
+func CreateClusterServices(
+	cluster *apiv1.Cluster,
+	createAnyService bool,
+	detachReadWriteService bool,
+	instanceNames []string,
+) []*corev1.Service {
+	var result []*corev1.Service
+	if createAnyService {
+		result = append(result, CreateClusterAnyService(*cluster))
+	}
+
+	// A permanent replica cluster doesn't accept writes, so it has no -rw
+	// service, and its instances stream from the designated primary service
+	primaryService := CreateClusterReadWriteService(*cluster)
+	switch {
+	case cluster.IsPermanentReplica():
+		primaryService = CreateClusterDesignatedPrimaryService(*cluster)
+	case detachReadWriteService:
+		primaryService = CreateClusterDetachedReadWriteService(*cluster)
+	}
+	result = append(result,
+		CreateClusterReadService(*cluster),
+		CreateClusterReadOnlyService(*cluster),
+		primaryService,
+	)
+
+	for _, instanceName := range instanceNames {
+		result = append(result, CreateInstanceService(*cluster, instanceName))
+	}
+
+	for _, service := range result {
+		cluster.SetInheritedDataAndOwnership(&service.ObjectMeta)
+	}
+	return result
+}
+
+// CreateInstanceService create a service insisting on a single instance,
+// regardless of its role. The service is named after the instance itself
+func CreateInstanceService(cluster apiv1.Cluster, instanceName string) *corev1.Service {
+	var configuration *apiv1.InstanceServicesConfiguration
+	if cluster.Spec.Managed != nil && cluster.Spec.Managed.Services != nil {
+		configuration = cluster.Spec.Managed.Services.InstanceServices
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instanceName,
+			Namespace:   cluster.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: configuration.GetType(),
+			// The instance needs to be reachable while it is still
+			// catching up with the primary, like with the "-any" service
+			PublishNotReadyAddresses: true,
+			Ports:                    buildInstanceServicePorts(),
+			Selector: map[string]string{
+				utils.ClusterLabelName:      cluster.Name,
+				utils.InstanceNameLabelName: instanceName,
+			},
+		},
+	}
+
+	if hostname := cluster.GetInstanceExternalHostname(instanceName); hostname != "" {
+		service.Annotations[utils.ExternalDNSHostnameAnnotationName] = hostname
+	}
+
+	if configuration != nil && configuration.Metadata != nil {
+		utils.MergeMap(service.Labels, configuration.Metadata.Labels)
+		utils.MergeMap(service.Annotations, configuration.Metadata.Annotations)
+	}
+
+	// the operator relies on this label to find the instance services
+	service.Labels[utils.InstanceNameLabelName] = instanceName
+
+	return service
+}
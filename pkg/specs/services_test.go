@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -36,6 +37,7 @@ var _ = Describe("Services specification", func() {
 	It("create a configured -any service", func() {
 		service := CreateClusterAnyService(postgresql)
 		Expect(service.Name).To(Equal("clustername-any"))
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
 		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[utils.PodRoleLabelName]).To(Equal(string(utils.PodRoleInstance)))
@@ -63,5 +65,102 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.PublishNotReadyAddresses).To(BeFalse())
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
+		Expect(IsDetachedReadWriteService(service)).To(BeFalse())
+	})
+	It("create a detached -rw service", func() {
+		service := CreateClusterDetachedReadWriteService(postgresql)
+		Expect(service.Name).To(Equal("clustername-rw"))
+		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).ToNot(Equal(ClusterRoleLabelPrimary))
+		Expect(service.Spec.Selector[utils.ClusterRoleLabelName]).ToNot(Equal(ClusterRoleLabelReplica))
+		Expect(IsDetachedReadWriteService(service)).To(BeTrue())
+	})
+	It("create a configured instance service", func() {
+		service := CreateInstanceService(postgresql, "clustername-1")
+		Expect(service.Name).To(Equal("clustername-1"))
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[utils.InstanceNameLabelName]).To(Equal("clustername-1"))
+		Expect(service.Labels[utils.InstanceNameLabelName]).To(Equal("clustername-1"))
+		Expect(service.Annotations).ToNot(HaveKey(utils.ExternalDNSHostnameAnnotationName))
+	})
+
+	It("create an instance service with an external hostname", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				InstanceServices: &apiv1.InstanceServicesConfiguration{
+					Enabled:        true,
+					ExternalDomain: "db.example.com",
+				},
+			},
+		}
+		service := CreateInstanceService(*cluster, "clustername-1")
+		Expect(service.Annotations[utils.ExternalDNSHostnameAnnotationName]).
+			To(Equal("clustername-1.db.example.com"))
+	})
+	It("create an instance service with custom type and metadata", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				InstanceServices: &apiv1.InstanceServicesConfiguration{
+					Enabled:        true,
+					ExternalDomain: "db.example.com",
+					Type:           corev1.ServiceTypeLoadBalancer,
+					Metadata: &apiv1.Metadata{
+						Labels: map[string]string{
+							"team":                      "dba",
+							utils.InstanceNameLabelName: "overridden",
+						},
+						Annotations: map[string]string{
+							"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+							utils.ExternalDNSHostnameAnnotationName:                 "custom.example.com",
+						},
+					},
+				},
+			},
+		}
+		service := CreateInstanceService(*cluster, "clustername-1")
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Labels).To(HaveKeyWithValue("team", "dba"))
+		Expect(service.Labels).To(HaveKeyWithValue(utils.InstanceNameLabelName, "clustername-1"))
+		Expect(service.Annotations).To(HaveKeyWithValue(
+			"service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+		Expect(service.Annotations).To(HaveKeyWithValue(
+			utils.ExternalDNSHostnameAnnotationName, "custom.example.com"))
+	})
+
+	It("create every service of the cluster", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Namespace = "default"
+		services := CreateClusterServices(cluster, true, false, []string{"clustername-1"})
+
+		names := make([]string, 0, len(services))
+		for _, service := range services {
+			names = append(names, service.Name)
+			Expect(service.OwnerReferences).To(HaveLen(1))
+			Expect(service.OwnerReferences[0].Name).To(Equal("clustername"))
+		}
+		Expect(names).To(Equal([]string{
+			"clustername-any", "clustername-r", "clustername-ro", "clustername-rw", "clustername-1",
+		}))
+		Expect(IsDetachedReadWriteService(services[3])).To(BeFalse())
+	})
+
+	It("create the services of the cluster with a detached -rw service", func() {
+		services := CreateClusterServices(postgresql.DeepCopy(), false, true, nil)
+		Expect(services).To(HaveLen(3))
+		Expect(services[2].Name).To(Equal("clustername-rw"))
+		Expect(IsDetachedReadWriteService(services[2])).To(BeTrue())
+	})
+
+	It("replaces the -rw service of a permanent replica cluster", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Permanent: true}
+		services := CreateClusterServices(cluster, false, true, nil)
+		Expect(services).To(HaveLen(3))
+		Expect(services[2].Name).To(Equal("clustername-designated"))
+		Expect(services[2].Spec.Selector).To(HaveKeyWithValue(utils.ClusterRoleLabelName, ClusterRoleLabelPrimary))
 	})
 })
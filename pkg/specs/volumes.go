@@ -25,6 +25,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/backupplugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pvcbackup"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -177,6 +179,28 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
+
+	if cluster.GetBackupPlugin() != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: "plugins",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+	}
+
+	if backupPVC := cluster.GetBackupPVC(); backupPVC != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: "backups",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: backupPVC.ClaimName,
+					},
+				},
+			})
+	}
 	return result
 }
 
@@ -310,6 +334,24 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 			)
 		}
 	}
+
+	if cluster.GetBackupPlugin() != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "plugins",
+				MountPath: backupplugin.SocketDirectory,
+			},
+		)
+	}
+
+	if cluster.GetBackupPVC() != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "backups",
+				MountPath: pvcbackup.VolumeDirectory,
+			},
+		)
+	}
 	return volumeMounts
 }
 
@@ -18,6 +18,7 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -472,3 +473,43 @@ var _ = DescribeTable("test creation of volumes",
 			},
 		}),
 )
+
+var _ = Describe("superuser secret volume", func() {
+	hasSuperuserSecretVolume := func(cluster apiv1.Cluster) bool {
+		for _, volume := range createPostgresVolumes(cluster, "pod-1") {
+			if volume.Name == "superuser-secret" {
+				return true
+			}
+		}
+		return false
+	}
+
+	hasSuperuserSecretVolumeMount := func(cluster apiv1.Cluster) bool {
+		for _, volumeMount := range createPostgresVolumeMounts(cluster) {
+			if volumeMount.Name == "superuser-secret" {
+				return true
+			}
+		}
+		return false
+	}
+
+	It("is not mounted when the superuser access is disabled", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				EnableSuperuserAccess: ptr.To(false),
+			},
+		}
+		Expect(hasSuperuserSecretVolume(cluster)).To(BeFalse())
+		Expect(hasSuperuserSecretVolumeMount(cluster)).To(BeFalse())
+	})
+
+	It("is mounted when the superuser access is enabled", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				EnableSuperuserAccess: ptr.To(true),
+			},
+		}
+		Expect(hasSuperuserSecretVolume(cluster)).To(BeTrue())
+		Expect(hasSuperuserSecretVolumeMount(cluster)).To(BeTrue())
+	})
+})
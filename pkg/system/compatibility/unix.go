@@ -22,6 +22,15 @@ package compatibility
 
 import (
 	"os"
+	"syscall"
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+
+	lowestNiceValue = 19
 )
 
 // SetCoredumpFilter set the value of /proc/self/coredump_filter
@@ -29,3 +38,24 @@ func SetCoredumpFilter(coredumpFilter string) error {
 	coredumpFilterFile := "/proc/self/coredump_filter"
 	return os.WriteFile(coredumpFilterFile, []byte(coredumpFilter), 0o600)
 }
+
+// LowerThreadPriority sets the lowest CPU priority and the idle I/O
+// scheduling class for the calling OS thread. On Linux both are per-thread
+// attributes, inherited by the processes started from this thread
+func LowerThreadPriority() error {
+	tid := syscall.Gettid()
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, lowestNiceValue); err != nil {
+		return err
+	}
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOPRIO_SET,
+		ioprioWhoProcess,
+		uintptr(tid),
+		ioprioClassIdle<<ioprioClassShift)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
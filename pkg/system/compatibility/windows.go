@@ -24,3 +24,8 @@ package compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// LowerThreadPriority for Windows compatibility
+func LowerThreadPriority() error {
+	return nil
+}
@@ -23,6 +23,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
 // MetadataNamespace is the annotation and label namespace used by the operator
@@ -89,6 +91,14 @@ const (
 
 	// IsOnlineBackupLabelName is the name of the label used to specify whether a backup was online
 	IsOnlineBackupLabelName = MetadataNamespace + "/onlineBackup"
+
+	// KubernetesAppManagedByLabelName is the name of the well-known label
+	// containing the tool managing the object
+	KubernetesAppManagedByLabelName = "app.kubernetes.io/managed-by"
+
+	// ManagerName is the value of the KubernetesAppManagedByLabelName label
+	// of the objects created by the operator
+	ManagerName = "cloudnative-pg"
 )
 
 const (
@@ -131,6 +141,20 @@ const (
 	// PgControldataAnnotationName is the name of the annotation containing the pg_controldata output of the cluster
 	PgControldataAnnotationName = MetadataNamespace + "/pgControldata"
 
+	// AllowDataLossOnFailoverAnnotationName is the name of the annotation which
+	// allows the operator to complete a failover even if the data loss exceeds
+	// the `.spec.maxDataLossOnFailover` threshold
+	AllowDataLossOnFailoverAnnotationName = MetadataNamespace + "/allowDataLossOnFailover"
+
+	// ApprovePrimaryUpdateAnnotationName is the name of the annotation which
+	// allows the operator to complete a rolling update of the primary when the
+	// `supervised` primary update strategy is used
+	ApprovePrimaryUpdateAnnotationName = MetadataNamespace + "/approvePrimaryUpdate"
+
+	// RestorePointAnnotationName is the name of the annotation containing
+	// the name of the restore point to be created on the primary
+	RestorePointAnnotationName = MetadataNamespace + "/restorePoint"
+
 	// skipEmptyWalArchiveCheck is the name of the annotation which turns off the checks that ensure that the WAL
 	// archive is empty before writing data
 	skipEmptyWalArchiveCheck = MetadataNamespace + "/skipEmptyWalArchiveCheck"
@@ -159,6 +183,10 @@ const (
 	// the hash of the Pooler Specification
 	PoolerSpecHashAnnotationName = MetadataNamespace + "/poolerSpecHash"
 
+	// ResourcesChecksumAnnotationName is the name of the annotation containing the
+	// checksum of the versions of the Secrets and ConfigMaps used by a Pod
+	ResourcesChecksumAnnotationName = MetadataNamespace + "/resourcesChecksum"
+
 	// OperatorManagedSecretsAnnotationName is the name of the annotation containing
 	// the secrets managed by the operator inside the generated service account
 	OperatorManagedSecretsAnnotationName = MetadataNamespace + "/managedSecrets"
@@ -169,7 +197,8 @@ const (
 	FencedInstanceAnnotation = MetadataNamespace + "/fencedInstances"
 
 	// CNPGHashAnnotationName is the name of the annotation containing the hash of the resource used by operator
-	// expect the pooler that uses PoolerSpecHashAnnotationName
+	// expect the pooler that uses PoolerSpecHashAnnotationName. The objects generated for a Cluster
+	// are annotated with the hash of their specification
 	CNPGHashAnnotationName = MetadataNamespace + "/hash"
 
 	// BackupStartWALAnnotationName is the name of the annotation where a backup's start WAL is kept
@@ -196,9 +225,17 @@ const (
 	// SnapshotEndTimeAnnotationName is the name of the annotation where a snapshot's end time is kept
 	SnapshotEndTimeAnnotationName = MetadataNamespace + "/snapshotEndTime"
 
+	// TimelineAnnotationName is the name of the annotation where the
+	// timeline of the current primary is kept
+	TimelineAnnotationName = MetadataNamespace + "/timeline"
+
 	// ClusterRestartAnnotationName is the name of the annotation containing the
 	// latest required restart time
 	ClusterRestartAnnotationName = "kubectl.kubernetes.io/restartedAt"
+
+	// ExternalDNSHostnameAnnotationName is the name of the annotation used by
+	// external-dns to register the hostname of a Service
+	ExternalDNSHostnameAnnotationName = "external-dns.alpha.kubernetes.io/hostname"
 )
 
 type annotationStatus string
@@ -239,6 +276,15 @@ func LabelClusterName(object *metav1.ObjectMeta, name string) {
 	object.Labels[ClusterLabelName] = name
 }
 
+// LabelManagedBy labels the object as managed by the operator
+func LabelManagedBy(object *metav1.ObjectMeta) {
+	if object.Labels == nil {
+		object.Labels = make(map[string]string)
+	}
+
+	object.Labels[KubernetesAppManagedByLabelName] = ManagerName
+}
+
 // SetOperatorVersion set inside a certain object metadata the annotation
 // containing the version of the operator that generated the object
 func SetOperatorVersion(object *metav1.ObjectMeta, version string) {
@@ -249,6 +295,22 @@ func SetOperatorVersion(object *metav1.ObjectMeta, version string) {
 	object.Annotations[OperatorVersionAnnotationName] = version
 }
 
+// SetSpecHash set inside a certain object metadata the annotation containing
+// the hash of the specification that was generated for it, so that the objects
+// which are not aligned with their definition anymore can be detected
+func SetSpecHash(object *metav1.ObjectMeta, spec interface{}) {
+	specHash, err := hash.ComputeHash(spec)
+	if err != nil {
+		return
+	}
+
+	if object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+
+	object.Annotations[CNPGHashAnnotationName] = specHash
+}
+
 // InheritanceController controls if a label or an annotation should be
 // inherited
 type InheritanceController interface {
@@ -376,6 +438,18 @@ func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[skipEmptyWalArchiveCheck] != string(annotationStatusEnabled)
 }
 
+// IsDataLossOnFailoverAllowed returns a boolean indicating if the user allowed
+// a failover to proceed even if it exceeds the maximum data loss threshold
+func IsDataLossOnFailoverAllowed(object *metav1.ObjectMeta) bool {
+	return object.Annotations[AllowDataLossOnFailoverAnnotationName] == string(annotationStatusEnabled)
+}
+
+// IsPrimaryUpdateApproved returns a boolean indicating if the user approved
+// the pending supervised rolling update of the primary
+func IsPrimaryUpdateApproved(object *metav1.ObjectMeta) bool {
+	return object.Annotations[ApprovePrimaryUpdateAnnotationName] == string(annotationStatusEnabled)
+}
+
 func mergeMap(receiver, giver map[string]string) map[string]string {
 	for key, value := range giver {
 		receiver[key] = value
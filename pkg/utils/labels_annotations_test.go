@@ -85,6 +85,22 @@ var _ = Describe("Annotation management", func() {
 	})
 })
 
+var _ = Describe("Primary update approval", func() {
+	It("is not approved without the annotation", func() {
+		Expect(IsPrimaryUpdateApproved(&metav1.ObjectMeta{})).To(BeFalse())
+	})
+
+	It("is approved only when the annotation is enabled", func() {
+		object := &metav1.ObjectMeta{
+			Annotations: map[string]string{ApprovePrimaryUpdateAnnotationName: "true"},
+		}
+		Expect(IsPrimaryUpdateApproved(object)).To(BeFalse())
+
+		object.Annotations[ApprovePrimaryUpdateAnnotationName] = "enabled"
+		Expect(IsPrimaryUpdateApproved(object)).To(BeTrue())
+	})
+})
+
 // nolint:dupl
 var _ = Describe("Label management", func() {
 	config := &fakeInhericanceController{
@@ -129,6 +145,12 @@ var _ = Describe("Label cluster name management", func() {
 		Expect(podTwo.ObjectMeta.Labels[ClusterLabelName]).To(Equal("test-label"))
 		Expect(podTwo.ObjectMeta.Labels["test"]).To(Equal("toast"))
 	})
+
+	It("must label objects as managed by the operator", func() {
+		LabelManagedBy(&podTwo.ObjectMeta)
+		Expect(podTwo.ObjectMeta.Labels[KubernetesAppManagedByLabelName]).To(Equal(ManagerName))
+		Expect(podTwo.ObjectMeta.Labels["test"]).To(Equal("toast"))
+	})
 })
 
 var _ = Describe("Annotate pods management", func() {
@@ -156,3 +178,27 @@ var _ = Describe("Annotate pods management", func() {
 		Expect(isPresent).To(BeFalse())
 	})
 })
+
+var _ = Describe("Spec hash annotation management", func() {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "postgres"}}}
+
+	It("must annotate empty objects", func() {
+		pod := corev1.Pod{Spec: spec}
+		SetSpecHash(&pod.ObjectMeta, pod.Spec)
+		Expect(pod.Annotations[CNPGHashAnnotationName]).ToNot(BeEmpty())
+	})
+
+	It("gives the same hash to objects generated from the same specification", func() {
+		living := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"one": "1"}}}
+		proposed := corev1.Pod{}
+		SetSpecHash(&living.ObjectMeta, spec)
+		SetSpecHash(&proposed.ObjectMeta, *spec.DeepCopy())
+		Expect(living.Annotations).To(HaveKeyWithValue("one", "1"))
+		Expect(living.Annotations[CNPGHashAnnotationName]).To(Equal(proposed.Annotations[CNPGHashAnnotationName]))
+
+		changedSpec := spec.DeepCopy()
+		changedSpec.Containers[0].Image = "postgres:16"
+		SetSpecHash(&proposed.ObjectMeta, *changedSpec)
+		Expect(living.Annotations[CNPGHashAnnotationName]).ToNot(Equal(proposed.Annotations[CNPGHashAnnotationName]))
+	})
+})
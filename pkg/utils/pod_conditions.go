@@ -87,6 +87,22 @@ func IsPodUnscheduled(p *corev1.Pod) bool {
 	return false
 }
 
+// GetPodUnscheduledMessage gets the reason reported by the scheduler for
+// a Pod that could not be scheduled, i.e. "0/3 nodes are available: 3
+// Insufficient cpu.", or an empty string if the Pod is not unscheduled
+func GetPodUnscheduledMessage(p *corev1.Pod) string {
+	if !IsPodUnscheduled(p) {
+		return ""
+	}
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodScheduled {
+			return c.Message
+		}
+	}
+
+	return ""
+}
+
 // IsPodAlive check if a pod is active and not crash-looping
 func IsPodAlive(p corev1.Pod) bool {
 	if corev1.PodRunning == p.Status.Phase {
@@ -157,4 +157,24 @@ var _ = Describe("Pod conditions test suite", func() {
 		}
 		Expect(IsPodEvicted(pod)).To(BeFalse())
 	})
+
+	Describe("Must report why a pod could not be scheduled", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient cpu.",
+					},
+				},
+			},
+		}
+		Expect(GetPodUnscheduledMessage(pod)).To(Equal("0/3 nodes are available: 3 Insufficient cpu."))
+
+		pod.Status.Phase = corev1.PodRunning
+		Expect(GetPodUnscheduledMessage(pod)).To(BeEmpty())
+	})
 })
@@ -18,6 +18,7 @@ package e2e
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -1259,24 +1260,33 @@ var _ = Describe("Backup and restore Safety", Label(tests.LabelBackupRestore), f
 			clusterRestoreSampleFile4 = fixturesDir + "/backup/backup_restore_safety/external-clusters-minio-4.yaml.template"
 			sourceBackup              = fixturesDir + "/backup/backup_restore_safety/backup-source-cluster.yaml"
 			restoreBackup             = fixturesDir + "/backup/backup_restore_safety/backup-cluster-2.yaml"
+			// the environment variable used in the YAML templates to refer to
+			// the namespace where minio is deployed
+			backupSafetyNamespaceEnv = "BACKUP_SAFETY_NAMESPACE"
 		)
 		BeforeAll(func() {
 			if !IsLocal() {
 				Skip("This test is only run on local cluster")
 			}
-			// This name is used in yaml file, keep it as const
-			namespace = "backup-safety-1"
+			const namespacePrefix = "backup-safety-1"
 			namespacePrefix2 := "backup-safety-2"
 			var err error
-			clusterName, err = env.GetResourceNameFromYAML(clusterSampleFile)
+			namespace, err = env.CreateUniqueNamespace(namespacePrefix)
 			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(func() error {
+				return env.DeleteNamespace(namespace)
+			})
 
-			err = env.CreateNamespace(namespace)
+			// The minio endpoint in the YAML templates refers to this namespace
+			err = os.Setenv(backupSafetyNamespaceEnv, namespace)
 			Expect(err).ToNot(HaveOccurred())
 			DeferCleanup(func() error {
-				return env.DeleteNamespace(namespace)
+				return os.Unsetenv(backupSafetyNamespaceEnv)
 			})
 
+			clusterName, err = env.GetResourceNameFromYAML(clusterSampleFile)
+			Expect(err).ToNot(HaveOccurred())
+
 			namespace2, err = env.CreateUniqueNamespace(namespacePrefix2)
 			Expect(err).ToNot(HaveOccurred())
 			DeferCleanup(func() error {
@@ -0,0 +1,191 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/tests"
+	testsUtils "github.com/cloudnative-pg/cloudnative-pg/tests/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Network partitions are simulated with NetworkPolicies, and degraded
+// networks with netem, so these tests are executed only when the "chaos"
+// label is explicitly requested, as they require a CNI supporting
+// NetworkPolicies and the permission to run privileged ephemeral containers
+var _ = Describe("Network partition", Label(tests.LabelChaos, tests.LabelSelfHealing), func() {
+	const (
+		namespacePrefix = "network-partition-e2e"
+		sampleFile      = fixturesDir + "/network_partition/cluster-network-partition.yaml.template"
+		clusterName     = "cluster-network-partition"
+		level           = tests.Medium
+	)
+	var namespace string
+
+	BeforeEach(func() {
+		if testLevelEnv.Depth < int(level) {
+			Skip("Test depth is lower than the amount requested for this test")
+		}
+		if !strings.Contains(GinkgoLabelFilter(), tests.LabelChaos) {
+			Skip("Network partition tests run only when the '" + tests.LabelChaos + "' label is selected")
+		}
+	})
+
+	JustAfterEach(func() {
+		if CurrentSpecReport().Failed() {
+			env.DumpNamespaceObjects(namespace, "out/"+CurrentSpecReport().LeafNodeText+".log")
+		}
+	})
+
+	// acceptsWrites checks whether the given instance accepts writes.
+	// The command is executed through the API server and the kubelet,
+	// so it works even when the pod network of the instance is isolated
+	acceptsWrites := func(pod corev1.Pod) bool {
+		commandTimeout := time.Second * 10
+		_, _, err := env.ExecCommand(env.Ctx, pod, specs.PostgresContainerName, &commandTimeout,
+			"psql", "-U", "postgres", "-tAc", "CREATE TABLE IF NOT EXISTS split_brain_check (id int)")
+		return err == nil
+	}
+
+	// countWritablePrimaries returns the number of instances accepting writes
+	countWritablePrimaries := func() (int, error) {
+		podList, err := env.GetClusterPodList(namespace, clusterName)
+		if err != nil {
+			return 0, err
+		}
+		primaries := 0
+		for _, pod := range podList.Items {
+			if acceptsWrites(pod) {
+				primaries++
+			}
+		}
+		return primaries, nil
+	}
+
+	It("fails over when the primary is isolated and rejoins it once the partition heals", func() {
+		var err error
+		namespace, err = env.CreateUniqueNamespace(namespacePrefix)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() error {
+			return env.DeleteNamespace(namespace)
+		})
+		AssertCreateCluster(namespace, clusterName, sampleFile, env)
+
+		cluster, err := env.GetCluster(namespace, clusterName)
+		Expect(err).ToNot(HaveOccurred())
+		oldPrimary := cluster.Status.CurrentPrimary
+
+		var networkPolicy *networkingv1.NetworkPolicy
+		By("isolating the primary from the network", func() {
+			networkPolicy, err = testsUtils.IsolateInstance(namespace, oldPrimary, env)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AssertNewPrimary(namespace, clusterName, oldPrimary)
+
+		By("verifying the isolated primary stops accepting writes", func() {
+			// Unable to reach both the API server and its standbys, the
+			// isolated primary fails its liveness probe and is stopped
+			Eventually(func() (bool, error) {
+				pod, err := env.GetPod(namespace, oldPrimary)
+				if err != nil {
+					return false, err
+				}
+				return acceptsWrites(*pod), nil
+			}, 180, 5).Should(BeFalse())
+		})
+
+		By("verifying only one instance accepts writes", func() {
+			Consistently(countWritablePrimaries, 30, 5).Should(BeEquivalentTo(1))
+		})
+
+		By("verifying the -rw service only points to one instance", func() {
+			endpointName := clusterName + "-rw"
+			Consistently(func() (int, error) {
+				endpoint := &corev1.Endpoints{}
+				err := env.Client.Get(env.Ctx,
+					types.NamespacedName{Namespace: namespace, Name: endpointName},
+					endpoint)
+				if err != nil {
+					return 0, err
+				}
+				addresses := 0
+				for _, subset := range endpoint.Subsets {
+					addresses += len(subset.Addresses)
+				}
+				return addresses, nil
+			}, 30, 2).Should(BeNumerically("<=", 1))
+		})
+
+		By("healing the network partition", func() {
+			err := testsUtils.HealInstanceIsolation(networkPolicy, env)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("having the old primary rejoining as a standby", func() {
+			AssertClusterIsReady(namespace, clusterName, testTimeouts[testsUtils.ClusterIsReady], env)
+			Eventually(countWritablePrimaries, 120).Should(BeEquivalentTo(1))
+		})
+
+		AssertClusterStandbysAreStreaming(namespace, clusterName, 120)
+	})
+
+	It("keeps the primary and replicates through latency and packet loss", func() {
+		const degradationDuration = 90 * time.Second
+
+		var err error
+		namespace, err = env.CreateUniqueNamespace(namespacePrefix)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() error {
+			return env.DeleteNamespace(namespace)
+		})
+		AssertCreateCluster(namespace, clusterName, sampleFile, env)
+
+		cluster, err := env.GetCluster(namespace, clusterName)
+		Expect(err).ToNot(HaveOccurred())
+		primary := cluster.Status.CurrentPrimary
+
+		By("degrading the network of the primary", func() {
+			err := testsUtils.DegradeInstanceNetwork(namespace, primary,
+				200*time.Millisecond, 10, degradationDuration, env)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("verifying no failover happens and only one instance accepts writes", func() {
+			Consistently(func(g Gomega) {
+				cluster, err := env.GetCluster(namespace, clusterName)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cluster.Status.CurrentPrimary).To(Equal(primary))
+				g.Expect(countWritablePrimaries()).To(BeEquivalentTo(1))
+			}, degradationDuration, 10*time.Second).Should(Succeed())
+		})
+
+		By("having the standbys streaming once the network recovers", func() {
+			AssertClusterIsReady(namespace, clusterName, testTimeouts[testsUtils.ClusterIsReady], env)
+			AssertClusterStandbysAreStreaming(namespace, clusterName, 120)
+		})
+	})
+})
@@ -119,7 +119,14 @@ var _ = SynchronizedAfterSuite(func() {
 // of output are not legal JSON
 func saveLogs(buf *bytes.Buffer, logsType, specName string, output io.Writer, capLines int) {
 	scanner := bufio.NewScanner(buf)
-	filename := fmt.Sprintf("out/%s_%s.log", logsType, specName)
+	// bucket the logs by Ginkgo parallel process, to avoid clashes
+	// between specs running concurrently
+	dir := fmt.Sprintf("out/process-%d", GinkgoParallelProcess())
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		fmt.Println(err)
+		return
+	}
+	filename := filepath.Join(dir, fmt.Sprintf("%s_%s.log", logsType, specName))
 	f, err := os.Create(filepath.Clean(filename))
 	if err != nil {
 		fmt.Println(err)
@@ -56,6 +56,10 @@ We test the following:
 * A ScheduledBackup created with the previous version is still scheduled after the upgrade.
 * A cluster with the previous version is created as a current version one after the upgrade.
 * We reply all the previous tests, but we enable the online upgrade in the final CLuster.
+* A cluster and a pooler created with the previous version survive an online
+  upgrade to the current version and a rollback to the previous one, without
+  PostgreSQL being restarted, and a backup taken before the upgrade can be
+  restored after the rollback.
 */
 
 var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), Ordered, Serial, func() {
@@ -64,8 +68,9 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 		configName          = "cnpg-controller-manager-config"
 		operatorUpgradeFile = fixturesDir + "/upgrade/current-manifest.yaml"
 
-		rollingUpgradeNamespace = "rolling-upgrade"
-		onlineUpgradeNamespace  = "online-upgrade"
+		rollingUpgradeNamespace  = "rolling-upgrade"
+		onlineUpgradeNamespace   = "online-upgrade"
+		rollbackUpgradeNamespace = "rollback-upgrade"
 
 		pgSecrets = fixturesDir + "/upgrade/pgsecrets.yaml" //nolint:gosec
 
@@ -311,71 +316,46 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 		return namespace
 	}
 
-	applyUpgrade := func(upgradeNamespace string) {
-		// Create the secrets used by the clusters and minio
-		By("creating the postgres secrets", func() {
-			CreateResourceFromFile(upgradeNamespace, pgSecrets)
-		})
-		By("creating the cloud storage credentials", func() {
-			AssertStorageCredentialsAreCreated(upgradeNamespace, "aws-creds", "minio", "minio123")
-		})
-
-		// Create the cluster. Since it will take a while, we'll do more stuff
-		// in parallel and check for it to be up later.
-		By(fmt.Sprintf("creating a Cluster in the '%v' upgradeNamespace",
-			upgradeNamespace), func() {
-			CreateResourceFromFile(upgradeNamespace, sampleFile)
-		})
-
-		By("setting up minio", func() {
-			setup, err := testsUtils.MinioDefaultSetup(upgradeNamespace)
-			Expect(err).ToNot(HaveOccurred())
-			err = testsUtils.InstallMinio(env, setup, uint(testTimeouts[testsUtils.MinioInstallation]))
-			Expect(err).ToNot(HaveOccurred())
-		})
-
-		// Create the minio client pod and wait for it to be ready.
-		// We'll use it to check if everything is archived correctly
-		By("setting up minio client pod", func() {
-			minioClient := testsUtils.MinioDefaultClient(upgradeNamespace)
-			err := testsUtils.PodCreateAndWaitForReady(env, &minioClient, 240)
-			Expect(err).ToNot(HaveOccurred())
-		})
-
-		By("having minio resources ready", func() {
-			// Wait for the minio pod to be ready
-			deploymentName := "minio"
-			deploymentNamespacedName := types.NamespacedName{
-				Namespace: upgradeNamespace,
-				Name:      deploymentName,
-			}
+	upgradeOperator := func() {
+		By("upgrading the operator to current version", func() {
+			timeout := 120
+			// Upgrade to the new version
+			_, _, err := testsUtils.Run(fmt.Sprintf("kubectl apply -f %v", operatorUpgradeFile))
+			Expect(err).NotTo(HaveOccurred())
+			// With the new deployment, a new pod should be started. When it's
+			// ready, the old one is removed. We wait for the number of replicas
+			// to decrease to 1.
 			Eventually(func() (int32, error) {
-				deployment := &appsv1.Deployment{}
-				err := env.Client.Get(env.Ctx, deploymentNamespacedName, deployment)
+				deployment, err := env.GetOperatorDeployment()
+				if err != nil {
+					return 0, err
+				}
+				return deployment.Status.Replicas, err
+			}, timeout).Should(BeEquivalentTo(1))
+			// For a final check, we verify the pod is ready
+			Eventually(func() (int32, error) {
+				deployment, err := env.GetOperatorDeployment()
+				if err != nil {
+					return 0, err
+				}
 				return deployment.Status.ReadyReplicas, err
-			}, 300).Should(BeEquivalentTo(1))
-
-			// Wait for the minio client pod to be ready
-			mcNamespacedName := types.NamespacedName{
-				Namespace: upgradeNamespace,
-				Name:      minioClientName,
-			}
-			Eventually(func() (bool, error) {
-				mc := &corev1.Pod{}
-				err := env.Client.Get(env.Ctx, mcNamespacedName, mc)
-				return utils.IsPodReady(*mc), err
-			}, 180).Should(BeTrue())
-		})
-
-		// Cluster ready happens after minio is ready
-		By("having a Cluster with three instances ready", func() {
-			AssertClusterIsReady(upgradeNamespace, clusterName1, testTimeouts[testsUtils.ClusterIsReady], env)
+			}, timeout).Should(BeEquivalentTo(1))
 		})
+	}
 
-		By("creating a Pooler with two instances", func() {
-			CreateResourceFromFile(upgradeNamespace, pgBouncerSampleFile)
+	// Check that PostgreSQL has not been restarted on any instance, comparing
+	// the postmaster start times with the ones taken before
+	assertPostgresNotRestarted := func(upgradeNamespace string, startTimes map[string]string) {
+		By("verifying PostgreSQL has not been restarted", func() {
+			Eventually(func() (map[string]string, error) {
+				return testsUtils.GetPostmasterStartTimes(upgradeNamespace, clusterName1, env)
+			}, 60).Should(BeEquivalentTo(startTimes))
 		})
+	}
 
+	// assertBackupIsTaken adds some data to the first cluster, and checks
+	// that it is archived in a backup on minio
+	assertBackupIsTaken := func(upgradeNamespace string) {
 		// Now that everything is in place, we add a bit of data we'll use to
 		// check if the backup is working
 		By("creating data on the database", func() {
@@ -449,6 +429,122 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 				return value, err, atoiErr
 			}, 60).Should(BeEquivalentTo(1))
 		})
+	}
+
+	// assertBackupIsRestored checks that the backup taken with
+	// assertBackupIsTaken can bootstrap a new cluster
+	assertBackupIsRestored := func(upgradeNamespace string) {
+		By("restoring the backup taken from the first Cluster in a new cluster", func() {
+			restoredClusterName := "cluster-restore"
+			CreateResourceFromFile(upgradeNamespace, restoreFile)
+			AssertClusterIsReady(upgradeNamespace, restoredClusterName, testTimeouts[testsUtils.ClusterIsReadySlow], env)
+
+			// Test data should be present on restored primary
+			primary := restoredClusterName + "-1"
+			out, _, err := env.ExecQueryInInstancePod(
+				testsUtils.PodLocator{
+					Namespace: upgradeNamespace,
+					PodName:   primary,
+				},
+				testsUtils.DatabaseName("appdb"),
+				"SELECT count(*) FROM to_restore")
+			Expect(strings.Trim(out, "\n"), err).To(BeEquivalentTo("2"))
+
+			// Restored primary should be a timeline higher than 1, because
+			// we expect a promotion. We can't enforce "2" because the timeline
+			// ID will also depend on the history files existing in the cloud
+			// storage and we don't know the status of that.
+			out, _, err = env.ExecQueryInInstancePod(
+				testsUtils.PodLocator{
+					Namespace: upgradeNamespace,
+					PodName:   primary,
+				},
+				testsUtils.DatabaseName("appdb"),
+				"select substring(pg_walfile_name(pg_current_wal_lsn()), 1, 8)")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strconv.Atoi(strings.Trim(out, "\n"))).To(
+				BeNumerically(">", 1))
+
+			// Restored standbys should soon attach themselves to restored primary
+			Eventually(func() (string, error) {
+				out, _, err = env.ExecQueryInInstancePod(
+					testsUtils.PodLocator{
+						Namespace: upgradeNamespace,
+						PodName:   primary,
+					},
+					testsUtils.DatabaseName("appdb"),
+					"SELECT count(*) FROM pg_stat_replication")
+				return strings.Trim(out, "\n"), err
+			}, 180).Should(BeEquivalentTo("2"))
+		})
+	}
+
+	applyUpgrade := func(upgradeNamespace string) {
+		// Create the secrets used by the clusters and minio
+		By("creating the postgres secrets", func() {
+			CreateResourceFromFile(upgradeNamespace, pgSecrets)
+		})
+		By("creating the cloud storage credentials", func() {
+			AssertStorageCredentialsAreCreated(upgradeNamespace, "aws-creds", "minio", "minio123")
+		})
+
+		// Create the cluster. Since it will take a while, we'll do more stuff
+		// in parallel and check for it to be up later.
+		By(fmt.Sprintf("creating a Cluster in the '%v' upgradeNamespace",
+			upgradeNamespace), func() {
+			CreateResourceFromFile(upgradeNamespace, sampleFile)
+		})
+
+		By("setting up minio", func() {
+			setup, err := testsUtils.MinioDefaultSetup(upgradeNamespace)
+			Expect(err).ToNot(HaveOccurred())
+			err = testsUtils.InstallMinio(env, setup, uint(testTimeouts[testsUtils.MinioInstallation]))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		// Create the minio client pod and wait for it to be ready.
+		// We'll use it to check if everything is archived correctly
+		By("setting up minio client pod", func() {
+			minioClient := testsUtils.MinioDefaultClient(upgradeNamespace)
+			err := testsUtils.PodCreateAndWaitForReady(env, &minioClient, 240)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("having minio resources ready", func() {
+			// Wait for the minio pod to be ready
+			deploymentName := "minio"
+			deploymentNamespacedName := types.NamespacedName{
+				Namespace: upgradeNamespace,
+				Name:      deploymentName,
+			}
+			Eventually(func() (int32, error) {
+				deployment := &appsv1.Deployment{}
+				err := env.Client.Get(env.Ctx, deploymentNamespacedName, deployment)
+				return deployment.Status.ReadyReplicas, err
+			}, 300).Should(BeEquivalentTo(1))
+
+			// Wait for the minio client pod to be ready
+			mcNamespacedName := types.NamespacedName{
+				Namespace: upgradeNamespace,
+				Name:      minioClientName,
+			}
+			Eventually(func() (bool, error) {
+				mc := &corev1.Pod{}
+				err := env.Client.Get(env.Ctx, mcNamespacedName, mc)
+				return utils.IsPodReady(*mc), err
+			}, 180).Should(BeTrue())
+		})
+
+		// Cluster ready happens after minio is ready
+		By("having a Cluster with three instances ready", func() {
+			AssertClusterIsReady(upgradeNamespace, clusterName1, testTimeouts[testsUtils.ClusterIsReady], env)
+		})
+
+		By("creating a Pooler with two instances", func() {
+			CreateResourceFromFile(upgradeNamespace, pgBouncerSampleFile)
+		})
+
+		assertBackupIsTaken(upgradeNamespace)
 
 		By("creating a ScheduledBackup", func() {
 			// We create a ScheduledBackup
@@ -465,30 +561,7 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 			podUIDs = append(podUIDs, pod.GetUID())
 		}
 
-		By("upgrading the operator to current version", func() {
-			timeout := 120
-			// Upgrade to the new version
-			_, _, err := testsUtils.Run(fmt.Sprintf("kubectl apply -f %v", operatorUpgradeFile))
-			Expect(err).NotTo(HaveOccurred())
-			// With the new deployment, a new pod should be started. When it's
-			// ready, the old one is removed. We wait for the number of replicas
-			// to decrease to 1.
-			Eventually(func() (int32, error) {
-				deployment, err := env.GetOperatorDeployment()
-				if err != nil {
-					return 0, err
-				}
-				return deployment.Status.Replicas, err
-			}, timeout).Should(BeEquivalentTo(1))
-			// For a final check, we verify the pod is ready
-			Eventually(func() (int32, error) {
-				deployment, err := env.GetOperatorDeployment()
-				if err != nil {
-					return 0, err
-				}
-				return deployment.Status.ReadyReplicas, err
-			}, timeout).Should(BeEquivalentTo(1))
-		})
+		upgradeOperator()
 
 		operatorConfigMapNamespacedName := types.NamespacedName{
 			Namespace: operatorNamespace,
@@ -548,49 +621,7 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 
 		// We verify that the backup taken before the upgrade is usable to
 		// create a v1 cluster
-		By("restoring the backup taken from the first Cluster in a new cluster", func() {
-			restoredClusterName := "cluster-restore"
-			CreateResourceFromFile(upgradeNamespace, restoreFile)
-			AssertClusterIsReady(upgradeNamespace, restoredClusterName, testTimeouts[testsUtils.ClusterIsReadySlow], env)
-
-			// Test data should be present on restored primary
-			primary := restoredClusterName + "-1"
-			out, _, err := env.ExecQueryInInstancePod(
-				testsUtils.PodLocator{
-					Namespace: upgradeNamespace,
-					PodName:   primary,
-				},
-				testsUtils.DatabaseName("appdb"),
-				"SELECT count(*) FROM to_restore")
-			Expect(strings.Trim(out, "\n"), err).To(BeEquivalentTo("2"))
-
-			// Restored primary should be a timeline higher than 1, because
-			// we expect a promotion. We can't enforce "2" because the timeline
-			// ID will also depend on the history files existing in the cloud
-			// storage and we don't know the status of that.
-			out, _, err = env.ExecQueryInInstancePod(
-				testsUtils.PodLocator{
-					Namespace: upgradeNamespace,
-					PodName:   primary,
-				},
-				testsUtils.DatabaseName("appdb"),
-				"select substring(pg_walfile_name(pg_current_wal_lsn()), 1, 8)")
-			Expect(err).NotTo(HaveOccurred())
-			Expect(strconv.Atoi(strings.Trim(out, "\n"))).To(
-				BeNumerically(">", 1))
-
-			// Restored standbys should soon attach themselves to restored primary
-			Eventually(func() (string, error) {
-				out, _, err = env.ExecQueryInInstancePod(
-					testsUtils.PodLocator{
-						Namespace: upgradeNamespace,
-						PodName:   primary,
-					},
-					testsUtils.DatabaseName("appdb"),
-					"SELECT count(*) FROM pg_stat_replication")
-				return strings.Trim(out, "\n"), err
-			}, 180).Should(BeEquivalentTo("2"))
-		})
+		assertBackupIsRestored(upgradeNamespace)
 		AssertScheduledBackupsAreScheduled(upgradeNamespace)
 
 		By("scaling down the pooler to 0", func() {
@@ -645,4 +676,81 @@ var _ = Describe("Upgrade", Label(tests.LabelUpgrade, tests.LabelNoOpenshift), O
 
 		assertManagerRollout()
 	})
+
+	It("can be rolled back after an online upgrade", func() {
+		By("applying environment changes for current upgrade to be performed", func() {
+			testsUtils.EnableOnlineUpgradeForInstanceManager(operatorNamespace, configName, env)
+		})
+
+		mostRecentTag, err := testsUtils.GetMostRecentReleaseTag("../../releases")
+		Expect(err).NotTo(HaveOccurred())
+
+		GinkgoWriter.Printf("installing the recent CNPG tag %s\n", mostRecentTag)
+		testsUtils.InstallLatestCNPGOperator(mostRecentTag, env)
+
+		upgradeNamespace := assertCreateNamespace(rollbackUpgradeNamespace)
+		DeferCleanup(cleanupNamespace, upgradeNamespace)
+
+		By("creating the postgres secrets", func() {
+			CreateResourceFromFile(upgradeNamespace, pgSecrets)
+		})
+		By("creating the cloud storage credentials", func() {
+			AssertStorageCredentialsAreCreated(upgradeNamespace, "aws-creds", "minio", "minio123")
+		})
+		By("setting up minio", func() {
+			setup, err := testsUtils.MinioDefaultSetup(upgradeNamespace)
+			Expect(err).ToNot(HaveOccurred())
+			err = testsUtils.InstallMinio(env, setup, uint(testTimeouts[testsUtils.MinioInstallation]))
+			Expect(err).ToNot(HaveOccurred())
+		})
+		By("setting up minio client pod", func() {
+			minioClient := testsUtils.MinioDefaultClient(upgradeNamespace)
+			err := testsUtils.PodCreateAndWaitForReady(env, &minioClient, 240)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		By(fmt.Sprintf("creating a Cluster in the '%v' upgradeNamespace", upgradeNamespace), func() {
+			CreateResourceFromFile(upgradeNamespace, sampleFile)
+			AssertClusterIsReady(upgradeNamespace, clusterName1, testTimeouts[testsUtils.ClusterIsReady], env)
+		})
+		By("creating a Pooler with two instances", func() {
+			CreateResourceFromFile(upgradeNamespace, pgBouncerSampleFile)
+		})
+		assertPGBouncerPodsAreReady(upgradeNamespace, pgBouncerSampleFile, 2)
+
+		// The backup is taken with the previous version, and restored
+		// once the operator has been rolled back to it
+		assertBackupIsTaken(upgradeNamespace)
+
+		startTimes, err := testsUtils.GetPostmasterStartTimes(upgradeNamespace, clusterName1, env)
+		Expect(err).ToNot(HaveOccurred())
+
+		upgradeOperator()
+		assertManagerRollout()
+		AssertClusterIsReady(upgradeNamespace, clusterName1, 300, env)
+		assertPostgresNotRestarted(upgradeNamespace, startTimes)
+		assertPGBouncerPodsAreReady(upgradeNamespace, pgBouncerSampleFile, 2)
+
+		By(fmt.Sprintf("rolling back the operator to the %s release", mostRecentTag), func() {
+			testsUtils.InstallLatestCNPGOperator(mostRecentTag, env)
+			Eventually(func() (bool, error) {
+				return env.IsOperatorReady()
+			}, 120).Should(BeTrue())
+		})
+		AssertClusterIsReady(upgradeNamespace, clusterName1, 300, env)
+		assertPostgresNotRestarted(upgradeNamespace, startTimes)
+		assertPGBouncerPodsAreReady(upgradeNamespace, pgBouncerSampleFile, 2)
+
+		By("verifying the cluster is still writable after the rollback", func() {
+			primary, err := env.GetClusterPrimary(upgradeNamespace, clusterName1)
+			Expect(err).ToNot(HaveOccurred())
+
+			commandTimeout := time.Second * 10
+			query := "CREATE TABLE IF NOT EXISTS post_rollback AS VALUES (1),(2);"
+			_, _, err = env.EventuallyExecCommand(env.Ctx, *primary, specs.PostgresContainerName, &commandTimeout,
+				"psql", "-U", "postgres", "appdb", "-tAc", query)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		assertBackupIsRestored(upgradeNamespace)
+	})
 })
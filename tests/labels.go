@@ -88,4 +88,8 @@ const (
 
 	// LabelMaintenance is a label for selecting importing-databases test
 	LabelMaintenance = "maintenance"
+
+	// LabelChaos is a label for selecting tests injecting network failures.
+	// These tests require a CNI supporting NetworkPolicies
+	LabelChaos = "chaos"
 )
@@ -153,9 +153,29 @@ func (env TestingEnvironment) DumpOperatorLogs(getPrevious bool, requestedLineLe
 	return logs.GetPodLogs(env.Ctx, env.Interface, pod, getPrevious, f, requestedLineLength)
 }
 
+// bucketArtifactPath returns the path where the artifact file named
+// filename, related to the passed namespace, should be written.
+// Artifacts are bucketed in a directory named after the namespace, which
+// is unique for each spec, so that specs running in parallel don't
+// overwrite each other's files
+func bucketArtifactPath(namespace string, filename string) (string, error) {
+	dir, name := filepath.Split(filepath.Clean(filename))
+	bucket := filepath.Join(dir, namespace)
+	if err := os.MkdirAll(bucket, 0o750); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(bucket, name), nil
+}
+
 // DumpNamespaceObjects logs the clusters, pods, pvcs etc. found in a namespace as JSON sections
 func (env TestingEnvironment) DumpNamespaceObjects(namespace string, filename string) {
-	f, err := os.Create(filepath.Clean(filename))
+	path, err := bucketArtifactPath(namespace, filename)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	f, err := os.Create(filepath.Clean(path))
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,13 +29,24 @@ import (
 
 // CreateUniqueNamespace creates a namespace by using the passed prefix.
 // Return the namespace name and any errors encountered.
+//
+// When the suite is executed with parallel Ginkgo processes, each process
+// keeps its own list of generated names, so the generated name may already
+// be in use by another process: in that case a new name is generated.
 func (env TestingEnvironment) CreateUniqueNamespace(
 	namespacePrefix string,
 	opts ...client.CreateOption,
 ) (string, error) {
-	name := env.createdNamespaces.generateUniqueName(namespacePrefix)
+	for {
+		name := env.createdNamespaces.generateUniqueName(namespacePrefix)
 
-	return name, env.CreateNamespace(name, opts...)
+		err := env.CreateNamespace(name, opts...)
+		if apierrs.IsAlreadyExists(err) {
+			continue
+		}
+
+		return name, err
+	}
 }
 
 // CreateNamespace creates a namespace.
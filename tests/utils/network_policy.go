@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// IsolateInstance creates a NetworkPolicy denying every ingress and egress
+// connection of the given instance pod, simulating a network partition
+// between that instance and the rest of the Kubernetes cluster, including
+// the other instances, the operator and the API server.
+// The partition is healed by deleting the returned NetworkPolicy.
+//
+// This requires a CNI supporting NetworkPolicies.
+func IsolateInstance(namespace, podName string, env *TestingEnvironment) (*networkingv1.NetworkPolicy, error) {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      podName + "-isolation",
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.InstanceNameLabelName: podName,
+				},
+			},
+			// No ingress or egress rules: every connection is denied
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}
+
+	if _, err := CreateObject(env, networkPolicy); err != nil {
+		return nil, err
+	}
+
+	return networkPolicy, nil
+}
+
+// HealInstanceIsolation removes a network partition created via IsolateInstance
+func HealInstanceIsolation(networkPolicy *networkingv1.NetworkPolicy, env *TestingEnvironment) error {
+	return DeleteObject(env, networkPolicy)
+}
+
+// netemImage is the image used to shape the traffic of the instances,
+// which must contain the `tc` command
+const netemImage = "nicolaka/netshoot:v0.11"
+
+// DegradeInstanceNetwork adds an ephemeral container to the given instance
+// pod, which uses netem to add the given latency and packet loss to the
+// traffic of the pod for the given duration.
+// As ephemeral containers can't be removed, the traffic shaping rules are
+// removed by the container itself once the duration is elapsed.
+func DegradeInstanceNetwork(
+	namespace, podName string,
+	latency time.Duration,
+	packetLossPercentage int,
+	duration time.Duration,
+	env *TestingEnvironment,
+) error {
+	pod, err := env.Interface.CoreV1().Pods(namespace).Get(env.Ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(
+		"tc qdisc add dev eth0 root netem delay %dms loss %d%% && sleep %d; tc qdisc del dev eth0 root",
+		latency.Milliseconds(), packetLossPercentage, int(duration.Seconds()))
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    fmt.Sprintf("netem-%d", len(pod.Spec.EphemeralContainers)),
+			Image:   netemImage,
+			Command: []string{"sh", "-c", script},
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Add: []corev1.Capability{"NET_ADMIN"},
+				},
+			},
+		},
+	})
+
+	_, err = env.Interface.CoreV1().Pods(namespace).UpdateEphemeralContainers(
+		env.Ctx, podName, pod, metav1.UpdateOptions{})
+	return err
+}
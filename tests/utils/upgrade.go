@@ -17,6 +17,9 @@ limitations under the License.
 package utils
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -102,3 +105,31 @@ func InstallLatestCNPGOperator(releaseTag string, env *TestingEnvironment) {
 		return err
 	}, 150).ShouldNot(HaveOccurred())
 }
+
+// GetPostmasterStartTimes returns, for every instance of the cluster, the
+// start time of the postmaster. It can be used to verify that PostgreSQL
+// has not been restarted by an operation, such as an online upgrade of the
+// instance manager
+func GetPostmasterStartTimes(namespace, clusterName string, env *TestingEnvironment) (map[string]string, error) {
+	podList, err := env.GetClusterPodList(namespace, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	startTimes := make(map[string]string, len(podList.Items))
+	for _, pod := range podList.Items {
+		out, _, err := env.ExecQueryInInstancePod(
+			PodLocator{
+				Namespace: namespace,
+				PodName:   pod.Name,
+			},
+			"postgres",
+			"SELECT pg_postmaster_start_time()")
+		if err != nil {
+			return nil, fmt.Errorf("while getting the postmaster start time of %s: %w", pod.Name, err)
+		}
+		startTimes[pod.Name] = strings.TrimSpace(out)
+	}
+
+	return startTimes, nil
+}